@@ -8,9 +8,11 @@ import (
 	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/llms/ollama"
 	"go-ai-study/internal/ai"
+	"go-ai-study/internal/tools"
 	"log"
 	"log/slog"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -42,7 +44,7 @@ func main() {
 		log.Fatal(err)
 	}
 	fmt.Println("3. 正在生成向量并存入数据库 (请耐心等待)...")
-	err = ai.IndexDocs(ctx, mc, e, chunks)
+	err = ai.IndexDocs(ctx, mc, ai.CodeCollection, e, chunks)
 	if err != nil {
 		log.Fatalf("入库失败: %v", err)
 	}
@@ -61,6 +63,28 @@ func main() {
 	logger := ai.NewLogger(slog.LevelInfo)
 
 	insightEngine := ai.NewEngine(mc, e, chatLLM, logger)
+	// 注册 go_doc 工具：回答标准库相关的问题时由模型查询本地文档；
+	// 复杂度分析器和安全扫描器供问题路由直接执行（指标、安全类问题）
+	toolManager := tools.NewToolManager(tools.NewNoopLogger())
+	toolManager.Register(tools.NewGoDocLookup(), tools.DefaultToolConfig("go_doc"))
+	toolManager.Register(tools.NewComplexityAnalyzer(), tools.DefaultToolConfig("complexity_analyzer"))
+	toolManager.Register(tools.NewSecurityScanner(), tools.DefaultToolConfig("security_scanner"))
+	insightEngine.ExtraTools = toolManager.LLMTools()
+	insightEngine.ToolRunner = toolManager.CallJSON
+	// GO_AI_INSIGHT_SELF_CHECK=1 时每个回答都做一次自检，附加置信度说明
+	insightEngine.SelfCheck = os.Getenv("GO_AI_INSIGHT_SELF_CHECK") != ""
+	// GO_AI_INSIGHT_CONTEXT_TOKENS 检索代码在提示词中的 token 预算，上下文较小的本地模型可以调低
+	insightEngine.ContextBudget, _ = strconv.Atoi(os.Getenv("GO_AI_INSIGHT_CONTEXT_TOKENS"))
+	// scan 索引过的 README、docs/、ADR 片段按默认比例与代码一起检索
+	insightEngine.Filter.DocRatio = ai.DefaultDocRatio
+	// scan --history 建立过提交历史时，"为什么改成这样" 之类的问题同时参考相关提交
+	if ai.HasCommitHistory(ctx, mc, "") {
+		insightEngine.CommitCollection = ai.CommitCollection
+	}
+	// sync-docs 同步过 issue/PR 时，同时参考促成改动的讨论
+	if ai.HasProjectDocs(ctx, mc, "") {
+		insightEngine.ProjectDocsCollection = ai.ProjectDocsCollection
+	}
 	terminalScanner := bufio.NewScanner(os.Stdin)
 	fmt.Println("\n-------------------------------------------")
 	fmt.Println("💡 进入交互模式。请输入你的问题（输入 'exit' 退出程序）")
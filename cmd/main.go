@@ -5,7 +5,10 @@ import (
 	"flag"
 	"fmt"
 	"go-ai-study/internal/cli"
+	"go-ai-study/internal/i18n"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
 const version = "1.0.0"
@@ -13,16 +16,28 @@ const version = "1.0.0"
 func main() {
 	// 解析全局参数
 	configFile := flag.String("c", "", "配置文件路径")
-	outputFormat := flag.String("f", "text", "输出格式 (json|text)")
+	outputFormat := flag.String("f", "text", "输出格式 (json|text|template|html)")
+	templateFile := flag.String("template", "", "-f template 使用的 Go 模板文件（.html/.htm 使用 html/template）")
 	outputFile := flag.String("o", "", "输出文件路径")
 	verbose := flag.Bool("v", false, "详细输出")
 	showVersion := flag.Bool("version", false, "显示版本信息")
+	localOnly := flag.Bool("local-only", false, "只允许连接本机地址，任何非本机连接直接失败")
+	dryRun := flag.Bool("dry-run", false, "不写入任何文件，以 unified diff 输出将要做的修改")
+	outDir := flag.String("out-dir", "", "生成的文件写到该目录（保持相对路径结构），不修改源码目录")
+	filesFrom := flag.String("files-from", "", "从文件列表读取要分析的文件（每行一个路径），代替遍历目录")
+	symlinks := flag.String("symlinks", "", "遍历目录时符号链接的处理方式 (files|skip|follow)，默认使用配置文件中的 discovery.symlinks")
+	maxDepth := flag.Int("max-depth", 0, "遍历目录时进入子目录的层数上限，0 表示使用配置文件中的 discovery.max_depth")
+	lang := flag.String("lang", "", "输出语言 (zh-CN|en-US)，默认使用配置文件中的 locale")
+	noEmoji := flag.Bool("no-emoji", false, "文本输出去掉 ✅/⚠️/📊 等 emoji")
+	schema := flag.String("schema", "", "bug、security 结果的输出结构 (v1|v2)，v1 已弃用，默认使用配置文件中的 output_schema")
+	ascii := flag.Bool("ascii", false, "文本输出只使用 ASCII 符号（全角标点、箭头等替换为 ASCII，同时去掉 emoji）")
 
 	// 日志配置参数
 	logLevel := flag.String("log-level", "", "日志级别 (debug|info|warn|error)")
 	logFormat := flag.String("log-format", "", "日志格式 (text|json)")
 	logOutput := flag.String("log-output", "", "日志输出 (stdout|stderr|file)")
 	logFilePath := flag.String("log-file", "", "日志文件路径 (当 log-output=file 时使用)")
+	logPrompts := flag.Bool("log-prompts", false, "记录完整的提示词、检索片段、工具调用和回答（脱敏后），需要 --log-level debug")
 
 	flag.Parse()
 
@@ -33,19 +48,41 @@ func main() {
 	}
 
 	// 创建 CLI
-	cli, err := cli.NewCLI(*configFile, *outputFormat, *outputFile, *verbose,
-		*logLevel, *logFormat, *logOutput, *logFilePath)
+	cli, err := cli.NewCLI(cli.CLIOptions{
+		ConfigPath:   *configFile,
+		Format:       *outputFormat,
+		OutputPath:   *outputFile,
+		TemplatePath: *templateFile,
+		Verbose:      *verbose,
+		LocalOnly:    *localOnly,
+		DryRun:       *dryRun,
+		OutDir:       *outDir,
+		Lang:         *lang,
+		NoEmoji:      *noEmoji,
+		ASCII:        *ascii,
+		Schema:       *schema,
+		FilesFrom:    *filesFrom,
+		Symlinks:     *symlinks,
+		MaxDepth:     *maxDepth,
+		LogLevel:     *logLevel,
+		LogFormat:    *logFormat,
+		LogOutput:    *logOutput,
+		LogFilePath:  *logFilePath,
+		LogPrompts:   *logPrompts,
+	})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "初始化失败: %v\n", err)
+		fmt.Fprintln(os.Stderr, i18n.T("cli.init_failed", err))
 		os.Exit(1)
 	}
 
-	// 执行命令
-	ctx := context.Background()
+	// 执行命令，Ctrl-C 或 SIGTERM 取消 context，正在进行的扫描尽快停止
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	args := flag.Args()
 
-	if err := cli.Run(ctx, args); err != nil {
-		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+	err = cli.Run(ctx, args)
+	stop()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("cli.error", err))
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,29 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// AnswerWithHits 只用给定的检索结果回答问题，不调用工具、不使用对话历史
+// 用于评测：同样的检索结果下比较不同对话模型的回答
+func (e *SourceInsightEngine) AnswerWithHits(ctx context.Context, question string, hits []CodeHit) (string, error) {
+	prompt := fmt.Sprintf(`你是一个代码助手。请只根据下面的参考代码回答问题，回答中引用相关的函数名和位置（文件:行号）。
+
+参考代码：
+%s
+问题：%s`, formatHits(hits, e.ContextBudget), question)
+
+	resp, err := e.ChatModel.GenerateContent(ctx, []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, prompt),
+	})
+	if err != nil {
+		return "", fmt.Errorf("AI 请求失败: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("AI 响应中没有选择项")
+	}
+	return resp.Choices[0].Content, nil
+}
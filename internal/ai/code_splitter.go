@@ -31,6 +31,9 @@ func (cs *CodeSplitter) SplitDocuments(docs []schema.Document) ([]schema.Documen
 	var chunks []schema.Document
 
 	for _, doc := range docs {
+		// 不是经由 LoadCode 读入的文档也统一换行，否则切出的行带着 \r
+		doc.PageContent = NormalizeSource(doc.PageContent)
+
 		// 解析 Go 代码
 		fset := token.NewFileSet()
 		node, err := parser.ParseFile(fset, "", doc.PageContent, parser.ParseComments)
@@ -56,29 +59,156 @@ func (cs *CodeSplitter) SplitDocuments(docs []schema.Document) ([]schema.Documen
 					return true
 				}
 
+				// 每个块使用独立的元数据，记录所在函数和行范围
+				metadata := funcChunkMetadata(doc.Metadata, fnDecl, start+1, end+1)
+
 				// 检查函数大小
 				if end-start+1 <= cs.MaxLines {
 					// 函数不大，直接作为一个块
+					content, line := cs.addContext(lines, start, end, metadata)
+					if line > 0 {
+						metadata[MetaLine] = line
+					}
 					chunks = append(chunks, schema.Document{
-						PageContent: cs.addContext(lines, start, end, doc.Metadata),
-						Metadata:    doc.Metadata,
+						PageContent: content,
+						Metadata:    metadata,
 					})
 				} else {
 					// 函数太大，按逻辑子块分割
-					subChunks := cs.splitLargeFunction(lines, start, end, doc.Metadata)
+					subChunks := cs.splitLargeFunction(lines, start, end, metadata)
 					chunks = append(chunks, subChunks...)
 				}
 			}
 			return true
 		})
+
+		// 函数之外的内容（包注释、import、类型、全局变量和常量）单独成块，否则这些代码检索不到
+		chunks = append(chunks, cs.splitResidual(fset, node, lines, doc.Metadata)...)
 	}
 
 	return chunks, nil
 }
 
+// splitResidual 把不属于任何函数（含函数的文档注释）的连续行切分为片段
+// 超过 MaxLines 的区域在顶层声明的边界处切开，找不到边界时按 MaxLines 硬切
+func (cs *CodeSplitter) splitResidual(fset *token.FileSet, file *ast.File, lines []string, base map[string]any) []schema.Document {
+	inFunc := make([]bool, len(lines))
+	var cuts []int // 顶层声明（含文档注释）的起始行，0 起
+	for _, decl := range file.Decls {
+		start := fset.Position(decl.Pos()).Line - 1
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Doc != nil {
+				start = fset.Position(d.Doc.Pos()).Line - 1
+			}
+			end := fset.Position(d.End()).Line - 1
+			for i := max(start, 0); i <= end && i < len(lines); i++ {
+				inFunc[i] = true
+			}
+		case *ast.GenDecl:
+			if d.Doc != nil {
+				start = fset.Position(d.Doc.Pos()).Line - 1
+			}
+			cuts = append(cuts, start)
+		}
+	}
+
+	var chunks []schema.Document
+	emit := func(start, end int) {
+		// 去掉首尾空行，全是空行的区域不成块
+		for start <= end && strings.TrimSpace(lines[start]) == "" {
+			start++
+		}
+		for end >= start && strings.TrimSpace(lines[end]) == "" {
+			end--
+		}
+		// 只有 package 子句的区域没有可检索的内容，每个文件都有一个，不成块
+		if start > end || (start == end && strings.HasPrefix(strings.TrimSpace(lines[start]), "package ")) {
+			return
+		}
+		metadata := withLine(base, start+1)
+		metadata[MetaStartLine] = start + 1
+		metadata[MetaEndLine] = end + 1
+		chunks = append(chunks, schema.Document{
+			PageContent: strings.Join(lines[start:end+1], "\n"),
+			Metadata:    metadata,
+		})
+	}
+
+	for i := 0; i < len(lines); {
+		if inFunc[i] {
+			i++
+			continue
+		}
+		end := i
+		for end+1 < len(lines) && !inFunc[end+1] {
+			end++
+		}
+		// 过长的区域优先在顶层声明边界处切开
+		start := i
+		for end-start+1 > cs.MaxLines {
+			limit := start + cs.MaxLines
+			cut := limit
+			for _, c := range cuts {
+				// 只接受后半段的边界，避免切出过短的片段
+				if c > start+cs.MaxLines/2 && c < limit {
+					cut = c
+				}
+			}
+			emit(start, cut-1)
+			start = cut
+		}
+		emit(start, end)
+		i = end + 1
+	}
+	return chunks
+}
+
+// funcChunkMetadata 复制文档元数据并补充函数名和行范围
+func funcChunkMetadata(base map[string]any, fn *ast.FuncDecl, startLine, endLine int) map[string]any {
+	metadata := make(map[string]any, len(base)+3)
+	for k, v := range base {
+		metadata[k] = v
+	}
+
+	name := fn.Name.Name
+	if fn.Recv != nil && len(fn.Recv.List) > 0 {
+		recv := fn.Recv.List[0].Type
+		if star, ok := recv.(*ast.StarExpr); ok {
+			recv = star.X
+		}
+		switch t := recv.(type) {
+		case *ast.Ident:
+			name = t.Name + "." + name
+		case *ast.IndexExpr:
+			if id, ok := t.X.(*ast.Ident); ok {
+				name = id.Name + "." + name
+			}
+		case *ast.IndexListExpr:
+			if id, ok := t.X.(*ast.Ident); ok {
+				name = id.Name + "." + name
+			}
+		}
+	}
+	metadata[MetaFunction] = name
+	metadata[MetaStartLine] = startLine
+	metadata[MetaEndLine] = endLine
+	return metadata
+}
+
+// withLine 复制元数据并记录片段内容的起始行号
+func withLine(base map[string]any, line int) map[string]any {
+	metadata := make(map[string]any, len(base)+3)
+	for k, v := range base {
+		metadata[k] = v
+	}
+	metadata[MetaLine] = line
+	return metadata
+}
+
 // addContext 添加注释和上下文
-// 向前查找关联的注释，向后查找可能的相邻代码
-func (cs *CodeSplitter) addContext(lines []string, start, end int, metadata map[string]any) string {
+// 向前查找关联的注释，向后查找可能的相邻代码；同时返回内容第一行的行号（从 1 开始），内容为空时为 0
+func (cs *CodeSplitter) addContext(lines []string, start, end int, metadata map[string]any) (string, int) {
 	// 往前查找注释
 	contextStart := start
 	for i := start - 1; i >= 0; i-- {
@@ -112,10 +242,10 @@ func (cs *CodeSplitter) addContext(lines []string, start, end int, metadata map[
 		contextEnd = len(lines) - 1
 	}
 	if contextStart > contextEnd {
-		return ""
+		return "", 0
 	}
 
-	return strings.Join(lines[contextStart:contextEnd+1], "\n")
+	return strings.Join(lines[contextStart:contextEnd+1], "\n"), contextStart + 1
 }
 
 // splitLargeFunction 分割大函数
@@ -145,7 +275,7 @@ func (cs *CodeSplitter) splitLargeFunction(lines []string, start, end int, metad
 				code := commentBuffer + strings.Join(lines[currentStart:i+1], "\n")
 				chunks = append(chunks, schema.Document{
 					PageContent: code,
-					Metadata:    metadata,
+					Metadata:    subChunkMetadata(metadata, commentBuffer, currentStart),
 				})
 				// 重置
 				currentStart = i + 1
@@ -159,13 +289,21 @@ func (cs *CodeSplitter) splitLargeFunction(lines []string, start, end int, metad
 		code := commentBuffer + strings.Join(lines[currentStart:end+1], "\n")
 		chunks = append(chunks, schema.Document{
 			PageContent: code,
-			Metadata:    metadata,
+			Metadata:    subChunkMetadata(metadata, commentBuffer, currentStart),
 		})
 	}
 
 	return chunks
 }
 
+// subChunkMetadata 大函数子块的元数据：前面拼接了注释的子块内容与源码不连续，不记录起始行号
+func subChunkMetadata(metadata map[string]any, commentBuffer string, start int) map[string]any {
+	if commentBuffer != "" {
+		return metadata
+	}
+	return withLine(metadata, start+1)
+}
+
 // isLogicalSplitPoint 判断是否是逻辑分割点
 // 根据关键字识别代码中的逻辑分割点
 func (cs *CodeSplitter) isLogicalSplitPoint(line string) bool {
@@ -196,7 +334,7 @@ func (cs *CodeSplitter) simpleSplitByLines(doc schema.Document) []schema.Documen
 		}
 		chunks = append(chunks, schema.Document{
 			PageContent: strings.Join(lines[i:end], "\n"),
-			Metadata:    doc.Metadata,
+			Metadata:    withLine(doc.Metadata, i+1),
 		})
 	}
 
@@ -0,0 +1,231 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+// CommitCollection 提交历史集合（scan --history 建立，可选），与代码片段集合分开存放
+const CommitCollection = "commit_history"
+
+// CommitSchemaVersion 提交历史集合的表结构版本，修改 commitSchema 时需要递增
+//
+//	1: hash / author / date / content / vector
+const CommitSchemaVersion = 1
+
+// CommitDoc 一次提交：提交说明和改动摘要
+type CommitDoc struct {
+	Hash    string       `json:"hash"`
+	Author  string       `json:"author"`
+	Date    int64        `json:"date"` // 作者时间（Unix 秒）
+	Message string       `json:"message"`
+	Changes []FileChange `json:"changes,omitempty"`
+	Excerpt []string     `json:"excerpt,omitempty"` // 节选的改动行（带 +/- 前缀），让提问中的标识符也能命中
+}
+
+// FileChange 一次提交中一个文件的改动
+type FileChange struct {
+	Path      string   `json:"path"`
+	Added     int      `json:"added"`
+	Deleted   int      `json:"deleted"`
+	Functions []string `json:"functions,omitempty"` // 改动所在的函数（diff 块头中的上下文）
+}
+
+// Text 向量化和写入提示词的文本：提交号、作者、日期、提交说明、改动文件和节选的改动行
+// 超过 content 字段上限时截断
+func (c CommitDoc) Text() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "提交 %s\n作者: %s  日期: %s\n\n%s\n", c.Hash, c.Author, time.Unix(c.Date, 0).UTC().Format(time.DateOnly), strings.TrimSpace(c.Message))
+	if len(c.Changes) > 0 {
+		sb.WriteString("\n改动文件:\n")
+		for _, change := range c.Changes {
+			fmt.Fprintf(&sb, "- %s (+%d -%d)", change.Path, change.Added, change.Deleted)
+			if len(change.Functions) > 0 {
+				sb.WriteString(" " + strings.Join(change.Functions, "; "))
+			}
+			sb.WriteString("\n")
+		}
+	}
+	if len(c.Excerpt) > 0 {
+		sb.WriteString("\n代码改动（节选）:\n" + strings.Join(c.Excerpt, "\n") + "\n")
+	}
+	text := sb.String()
+	if len(text) > CodeContentMaxLength {
+		text = text[:CodeContentMaxLength]
+		for !utf8.ValidString(text) {
+			text = text[:len(text)-1]
+		}
+	}
+	return text
+}
+
+// CommitHit 一条提交历史的检索结果
+type CommitHit struct {
+	Hash    string  `json:"hash"`
+	Author  string  `json:"author"`
+	Date    int64   `json:"date"`
+	Content string  `json:"content"`
+	Score   float32 `json:"score"`
+}
+
+// ShortHash 引用时使用的短提交号
+func (h CommitHit) ShortHash() string {
+	if len(h.Hash) > 10 {
+		return h.Hash[:10]
+	}
+	return h.Hash
+}
+
+// commitSchema 提交历史集合的表结构
+func commitSchema(collection string, dim int) *entity.Schema {
+	fields := []*entity.Field{
+		entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true).WithIsAutoID(true),
+		entity.NewField().WithName("hash").WithDataType(entity.FieldTypeVarChar).WithMaxLength(64),
+		entity.NewField().WithName("author").WithDataType(entity.FieldTypeVarChar).WithMaxLength(200),
+		entity.NewField().WithName("date").WithDataType(entity.FieldTypeInt64),
+		entity.NewField().WithName("content").WithDataType(entity.FieldTypeVarChar).WithMaxLength(CodeContentMaxLength),
+		entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithDim(int64(dim)),
+	}
+	return &entity.Schema{
+		CollectionName: collection,
+		Fields:         fields,
+		Description:    "提交历史",
+	}
+}
+
+// ResetCommitCollection 删除现有的提交历史集合并按当前版本重建
+// 提交历史每次 scan --history 时整体重建，不做增量更新
+func ResetCommitCollection(ctx context.Context, m client.Client, embeddingModel string) error {
+	exists, err := m.HasCollection(ctx, CommitCollection)
+	if err != nil {
+		return fmt.Errorf("检查集合失败: %w", err)
+	}
+	if exists {
+		if err := m.DropCollection(ctx, CommitCollection); err != nil {
+			return fmt.Errorf("删除旧集合失败: %w", err)
+		}
+	}
+	options := []client.CreateCollectionOption{
+		client.WithCollectionProperty(propSchemaVersion, strconv.Itoa(CommitSchemaVersion)),
+		client.WithCollectionProperty(propEmbeddingModel, embeddingModel),
+	}
+	if err := m.CreateCollection(ctx, commitSchema(CommitCollection, codeVectorDim), entity.DefaultShardNumber, options...); err != nil {
+		return fmt.Errorf("创建集合失败: %w", err)
+	}
+	idx, err := entity.NewIndexHNSW(entity.COSINE, 16, 64)
+	if err != nil {
+		return fmt.Errorf("创建索引参数失败: %w", err)
+	}
+	if err := m.CreateIndex(ctx, CommitCollection, "vector", idx, false); err != nil {
+		return fmt.Errorf("创建索引 vector 失败: %w", err)
+	}
+	if err := m.LoadCollection(ctx, CommitCollection, false); err != nil {
+		return fmt.Errorf("加载集合失败: %w", err)
+	}
+	return nil
+}
+
+// HasCommitHistory 提交历史集合是否存在且与当前版本、向量模型一致；不一致时不检索，避免返回错误的结果
+func HasCommitHistory(ctx context.Context, m client.Client, embeddingModel string) bool {
+	info, err := DescribeCodeIndex(ctx, m, CommitCollection)
+	if err != nil || !info.Exists {
+		return false
+	}
+	return info.SchemaVersion == CommitSchemaVersion && (embeddingModel == "" || info.EmbeddingModel == embeddingModel)
+}
+
+// IndexCommits 为提交生成向量并写入提交历史集合
+func IndexCommits(ctx context.Context, m client.Client, e embeddings.Embedder, commits []CommitDoc) error {
+	if len(commits) == 0 {
+		return nil
+	}
+	texts := make([]string, len(commits))
+	for i, c := range commits {
+		texts[i] = c.Text()
+	}
+	vectors, err := embedInBatches(ctx, e, texts)
+	if err != nil {
+		return fmt.Errorf("生成提交向量失败: %w", err)
+	}
+	if len(vectors) != len(commits) {
+		return fmt.Errorf("提交向量数量不符: 期望 %d，实际 %d", len(commits), len(vectors))
+	}
+
+	hashes := make([]string, len(commits))
+	authors := make([]string, len(commits))
+	dates := make([]int64, len(commits))
+	for i, c := range commits {
+		hashes[i], authors[i], dates[i] = c.Hash, c.Author, c.Date
+	}
+	_, err = m.Insert(ctx, CommitCollection, "",
+		entity.NewColumnVarChar("hash", hashes),
+		entity.NewColumnVarChar("author", authors),
+		entity.NewColumnInt64("date", dates),
+		entity.NewColumnVarChar("content", texts),
+		entity.NewColumnFloatVector("vector", len(vectors[0]), vectors))
+	if err != nil {
+		return fmt.Errorf("插入提交失败: %w", err)
+	}
+	if err := m.Flush(ctx, CommitCollection, false); err != nil {
+		return fmt.Errorf("Flush 失败: %w", err)
+	}
+	return nil
+}
+
+// SearchCommits 在提交历史中语义检索
+func SearchCommits(ctx context.Context, m client.Client, e embeddings.Embedder, query string, topK int) ([]CommitHit, error) {
+	queryVec, err := e.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("向量化失败: %w", err)
+	}
+	searchParam, _ := entity.NewIndexHNSWSearchParam(64)
+	res, err := m.Search(ctx, CommitCollection, []string{}, "",
+		[]string{"hash", "author", "date", "content"}, []entity.Vector{entity.FloatVector(queryVec)},
+		"vector", entity.COSINE, topK, searchParam)
+	if err != nil {
+		return nil, fmt.Errorf("Milvus 搜索提交历史失败: %w", err)
+	}
+
+	var hits []CommitHit
+	if len(res) == 0 {
+		return hits, nil
+	}
+	sr := res[0]
+	for i := 0; i < sr.IDs.Len(); i++ {
+		var hit CommitHit
+		if col := sr.Fields.GetColumn("hash"); col != nil {
+			hit.Hash, _ = col.GetAsString(i)
+		}
+		if col := sr.Fields.GetColumn("author"); col != nil {
+			hit.Author, _ = col.GetAsString(i)
+		}
+		if col := sr.Fields.GetColumn("date"); col != nil {
+			hit.Date, _ = col.GetAsInt64(i)
+		}
+		if col := sr.Fields.GetColumn("content"); col != nil {
+			hit.Content, _ = col.GetAsString(i)
+		}
+		if i < len(sr.Scores) {
+			hit.Score = sr.Scores[i]
+		}
+		hits = append(hits, hit)
+	}
+	return hits, nil
+}
+
+// formatCommitHits 把检索到的提交整理为提示词中的参考资料
+func formatCommitHits(hits []CommitHit) string {
+	var builder strings.Builder
+	for _, hit := range hits {
+		builder.WriteString(fmt.Sprintf("\n提交 %s:\n%s\n", hit.ShortHash(), strings.TrimSpace(hit.Content)))
+	}
+	return builder.String()
+}
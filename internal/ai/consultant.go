@@ -7,7 +7,6 @@ import (
 	"github.com/milvus-io/milvus-sdk-go/v2/entity"
 	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/llms"
-	"log"
 	"path/filepath"
 	"strings"
 )
@@ -21,8 +20,8 @@ func Consult(ctx context.Context, mc client.Client, e embeddings.Embedder, chatL
 		logger.Error("搜索失败", "error", err)
 		return
 	}
-	filterExpr := fmt.Sprintf("source == '%s'", filepath.ToSlash(targetFileName))
-	res, err := mc.Search(ctx, "code_segments", []string{}, filterExpr, []string{"content"},
+	filterExpr := "source == " + quoteExprString(filepath.ToSlash(targetFileName))
+	res, err := mc.Search(ctx, CodeCollection, []string{}, filterExpr, []string{"content"},
 		[]entity.Vector{entity.FloatVector(queryVec)}, "vector",
 		entity.COSINE, 3, searchParam)
 	if err != nil {
@@ -0,0 +1,154 @@
+package ai
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultContextBudget 检索代码在提示词中默认占用的 token 上限
+// 3 个 100 行的片段约 3000 token，4k 上下文的本地模型放不下，再加上问题和系统提示词
+const DefaultContextBudget = 2000
+
+// minTruncatedTokens 剩余预算少于这个值时不再截断放入片段，只有几行的代码没有参考价值
+const minTruncatedTokens = 128
+
+// EstimateTokens 粗略估算文本的 token 数：ASCII 约 4 个字符一个 token，中文等非 ASCII 字符每个字符一个 token
+// 不依赖具体模型的分词器，只用于预算控制，偏向高估
+func EstimateTokens(s string) int {
+	ascii, other := 0, 0
+	for _, r := range s {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
+
+// packHits 按相似度从高到低贪心地放入预算内的片段；超出剩余预算的片段在语句边界处截断后放入，
+// 剩余预算太少时跳过，继续尝试后面更短的片段。budget <= 0 时使用 DefaultContextBudget
+func packHits(hits []CodeHit, budget int) []CodeHit {
+	if budget <= 0 {
+		budget = DefaultContextBudget
+	}
+	order := make([]int, len(hits))
+	for i := range order {
+		order[i] = i
+	}
+	// 合并重叠片段后的次序不一定按相似度，按相似度决定放入的优先级
+	sort.SliceStable(order, func(a, b int) bool { return hits[order[a]].Score > hits[order[b]].Score })
+
+	taken := make([]bool, len(hits))
+	packed := make([]CodeHit, len(hits))
+	remaining := budget
+	for _, i := range order {
+		hit := hits[i]
+		cost := hitTokens(hit)
+		if cost > remaining {
+			if remaining < minTruncatedTokens {
+				continue
+			}
+			var ok bool
+			if hit, ok = fitHit(hit, remaining); !ok {
+				continue
+			}
+			cost = hitTokens(hit)
+		}
+		taken[i], packed[i] = true, hit
+		remaining -= cost
+	}
+
+	// 保持合并后的原有次序（提示词中的编号与检索结果一致）
+	result := make([]CodeHit, 0, len(hits))
+	for i, ok := range taken {
+		if ok {
+			result = append(result, packed[i])
+		}
+	}
+	return result
+}
+
+// hitTokens 片段放入提示词后占用的 token 数（含来源、摘要、行号和截断说明）
+func hitTokens(hit CodeHit) int {
+	tokens := EstimateTokens(hit.Location()+hit.Summary+hit.NumberedContent()) + 8
+	if hit.omitted > 0 {
+		tokens += EstimateTokens(omittedMarker(hit.omitted))
+	}
+	return tokens
+}
+
+// omittedMarker 截断片段末尾的说明
+func omittedMarker(omitted int) string {
+	return fmt.Sprintf("// ...（超出上下文预算，省略 %d 行）", omitted)
+}
+
+// fitHit 把片段截断到 budget 以内：从末尾去掉若干行，截断点优先选在语句结束处
+// （括号回到片段第一层、行尾不是运算符或逗号），并注明省略的行数；一行都放不下时返回 false
+func fitHit(hit CodeHit, budget int) (CodeHit, bool) {
+	lines := strings.Split(hit.Content, "\n")
+
+	// fit: 在预算内最多能保留的行数
+	fit := 0
+	for n := 1; n < len(lines); n++ {
+		trial := hit
+		trial.Content, trial.omitted = strings.Join(lines[:n], "\n"), len(lines)-n
+		if hitTokens(trial) > budget {
+			break
+		}
+		fit = n
+	}
+	if fit == 0 {
+		return hit, false
+	}
+
+	// 在能保留的行中找最后一个语句边界
+	cut, depth := fit, 0
+	for n := 0; n < fit; n++ {
+		depth += bracketDelta(lines[n])
+		if depth <= 1 && endsStatement(lines[n]) {
+			cut = n + 1
+		}
+	}
+	hit.Content, hit.omitted = strings.Join(lines[:cut], "\n"), len(lines)-cut
+	return hit, true
+}
+
+// bracketDelta 一行代码中括号的净增量，忽略字符串、字符字面量和行注释中的括号
+func bracketDelta(line string) int {
+	delta := 0
+	var quote rune
+	escaped := false
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if escaped {
+				escaped = false
+			} else if r == '\\' && quote != '`' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'' || r == '`':
+			quote = r
+		case r == '/' && strings.HasPrefix(line[i:], "//"):
+			return delta
+		case r == '{' || r == '(' || r == '[':
+			delta++
+		case r == '}' || r == ')' || r == ']':
+			delta--
+		}
+	}
+	return delta
+}
+
+// endsStatement 行是否可能是一条语句的结尾：空行、注释，或不以运算符、逗号、左括号结尾
+func endsStatement(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "//") {
+		return true
+	}
+	return !strings.ContainsAny(trimmed[len(trimmed)-1:], "+-*/%&|^<>=!,.(:[{")
+}
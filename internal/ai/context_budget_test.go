@@ -0,0 +1,101 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// budgetHit 生成 n 行语句组成的片段
+func budgetHit(source string, n int, score float32) CodeHit {
+	var lines []string
+	for i := 0; i < n; i++ {
+		lines = append(lines, fmt.Sprintf("total += compute(%d)", i))
+	}
+	return CodeHit{Source: source, Content: strings.Join(lines, "\n"), Line: 1, Score: score}
+}
+
+// 测试按预算放入片段：预算边界、超出预算时截断或跳过、按相似度决定优先级但保持原有次序
+func TestPackHits(t *testing.T) {
+	small := budgetHit("small.go", 3, 0.5)
+	medium := budgetHit("medium.go", 40, 0.9)
+	large := budgetHit("large.go", 200, 0.7)
+	low := budgetHit("low.go", 40, 0.3)
+	cost := func(hits ...CodeHit) int {
+		total := 0
+		for _, hit := range hits {
+			total += hitTokens(hit)
+		}
+		return total
+	}
+
+	cases := []struct {
+		name      string
+		hits      []CodeHit
+		budget    int
+		want      []string // 放入的片段，按输出次序
+		truncated string   // 被截断的片段
+	}{
+		{"预算恰好够用", []CodeHit{small, medium}, cost(small, medium), []string{"small.go", "medium.go"}, ""},
+		{"差一个 token 时截断相似度较低的片段", []CodeHit{large, medium}, cost(medium) + cost(large) - 1, []string{"large.go", "medium.go"}, "large.go"},
+		{"相似度高的先放入，输出保持原有次序", []CodeHit{low, medium}, cost(medium) + minTruncatedTokens - 1, []string{"medium.go"}, ""},
+		{"剩余预算太少时跳过，继续放入更短的片段", []CodeHit{medium, large, small}, cost(medium, small) + 1, []string{"medium.go", "small.go"}, ""},
+		{"单个片段超过预算时截断", []CodeHit{large}, 300, []string{"large.go"}, "large.go"},
+		{"一行都放不下时跳过", []CodeHit{{Source: "long.go", Content: strings.Repeat("x", 4000) + "\ny", Score: 1}}, minTruncatedTokens, nil, ""},
+		{"预算为 0 使用默认值", []CodeHit{small, medium}, 0, []string{"small.go", "medium.go"}, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			budget := tc.budget
+			if budget <= 0 {
+				budget = DefaultContextBudget
+			}
+			packed := packHits(tc.hits, tc.budget)
+			var got []string
+			for _, hit := range packed {
+				got = append(got, hit.Source)
+				if (hit.omitted > 0) != (hit.Source == tc.truncated) {
+					t.Errorf("%s 省略 %d 行，期望截断的片段为 %q", hit.Source, hit.omitted, tc.truncated)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("放入 %v，期望 %v", got, tc.want)
+			}
+			if total := cost(packed...); total > budget {
+				t.Errorf("占用 %d 个 token，超过预算 %d", total, budget)
+			}
+		})
+	}
+}
+
+// 测试截断点选在语句结束处：多行调用不从中间切开，省略的行数与截掉的行一致
+func TestFitHit(t *testing.T) {
+	lines := []string{
+		"func load() error {",
+		"\tcfg := read()",
+		"\terr := validate(cfg,",
+		"\t\tstrict,",
+		"\t\tverbose)",
+		"\treturn err",
+		"}",
+	}
+	hit := CodeHit{Source: "a.go", Content: strings.Join(lines, "\n"), Line: 10}
+	for keep := 1; keep < len(lines); keep++ {
+		trial := hit
+		trial.Content, trial.omitted = strings.Join(lines[:keep], "\n"), len(lines)-keep
+		fitted, ok := fitHit(hit, hitTokens(trial))
+		if !ok {
+			t.Fatalf("预算够放 %d 行时不应跳过", keep)
+		}
+		kept := strings.Count(fitted.Content, "\n") + 1
+		if kept > keep || kept+fitted.omitted != len(lines) {
+			t.Errorf("预算够放 %d 行: 保留 %d 行，省略 %d 行", keep, kept, fitted.omitted)
+		}
+		if last := lines[kept-1]; strings.HasSuffix(last, ",") {
+			t.Errorf("预算够放 %d 行: 不应在 %q 之后截断", keep, last)
+		}
+	}
+	if marker := omittedMarker(3); !strings.Contains(marker, "省略 3 行") || !strings.HasPrefix(marker, "//") {
+		t.Errorf("截断说明 = %q", marker)
+	}
+}
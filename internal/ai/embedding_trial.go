@@ -0,0 +1,79 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// EmbeddingTrial 用一个向量模型为样本代码建立的临时索引，比较向量模型时使用，评测完成后删除
+type EmbeddingTrial struct {
+	Model      string
+	Collection string
+	Dim        int           // 模型输出的向量维度
+	IndexTime  time.Duration // 生成向量并写入的耗时
+	embedder   embeddings.Embedder
+}
+
+// BuildEmbeddingTrial 用 model 为片段生成向量，写入新建的临时集合
+// 集合按模型的实际维度建表，不要求与正式索引（bge-m3，1024 维）一致；失败时删除已建的集合
+func BuildEmbeddingTrial(ctx context.Context, mc client.Client, endpoint, model string, chunks []schema.Document) (*EmbeddingTrial, error) {
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("没有可索引的代码片段")
+	}
+	e, err := NewEmbedder(endpoint, model)
+	if err != nil {
+		return nil, err
+	}
+	trial := &EmbeddingTrial{
+		Model:      model,
+		Collection: CodeCollectionName(fmt.Sprintf("trial_%s_%d", model, time.Now().UnixNano())),
+		embedder:   e,
+	}
+
+	contents := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		contents[i] = chunk.PageContent
+	}
+	start := time.Now()
+	vectors, err := embedInBatches(ctx, e, contents)
+	if err != nil {
+		return nil, fmt.Errorf("%s 生成向量失败: %w", model, err)
+	}
+	if len(vectors) != len(chunks) || len(vectors[0]) == 0 {
+		return nil, fmt.Errorf("%s 向量数量不符: 期望 %d，实际 %d", model, len(chunks), len(vectors))
+	}
+	trial.Dim = len(vectors[0])
+
+	err = ensureCodeCollection(ctx, mc, trial.Collection, model, trial.Dim)
+	if err == nil {
+		// 样本不生成摘要，摘要向量沿用代码向量
+		err = InsertCodeChunks(ctx, mc, trial.Collection, codeChunkRows(chunks, vectors, vectors))
+	}
+	if err != nil {
+		_ = trial.Drop(context.WithoutCancel(ctx), mc)
+		return nil, err
+	}
+	trial.IndexTime = time.Since(start)
+	return trial, nil
+}
+
+// Search 在临时索引中检索，返回命中的片段和耗时（包括问题的向量化）
+func (t *EmbeddingTrial) Search(ctx context.Context, mc client.Client, query string, filter SearchFilter, topK int) ([]CodeHit, time.Duration, error) {
+	filter.Collection = t.Collection
+	start := time.Now()
+	hits, err := SearchCode(ctx, mc, t.embedder, query, filter, topK)
+	return hits, time.Since(start), err
+}
+
+// Drop 删除临时集合
+func (t *EmbeddingTrial) Drop(ctx context.Context, mc client.Client) error {
+	if err := mc.DropCollection(ctx, t.Collection); err != nil {
+		return fmt.Errorf("删除临时集合 %s 失败: %w", t.Collection, err)
+	}
+	return nil
+}
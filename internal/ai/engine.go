@@ -5,10 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/milvus-io/milvus-sdk-go/v2/client"
-	"github.com/milvus-io/milvus-sdk-go/v2/entity"
 	"github.com/tmc/langchaingo/embeddings"
 	"github.com/tmc/langchaingo/llms"
-	"log"
+	"os"
 	"path/filepath"
 	"strings"
 )
@@ -18,7 +17,19 @@ type SourceInsightEngine struct {
 	Embedder     embeddings.Embedder
 	ChatModel    llms.Model
 	History      []llms.MessageContent
-	logger       *Logger
+	Filter       SearchFilter // 检索时附加的标量过滤条件（复杂度、问题数）
+	// ExtraTools 额外注册给模型的函数定义（如分析工具的 JSON Schema），由 ToolRunner 执行
+	ExtraTools []llms.Tool
+	ToolRunner func(ctx context.Context, name, arguments string) (string, error)
+	// CommitCollection 提交历史集合，为空时不检索提交历史；历史类问题（为什么改、什么时候改的）同时参考相关提交
+	CommitCollection string
+	// ProjectDocsCollection issue/PR 讨论集合，为空时不检索；历史类问题同时参考促成改动的讨论
+	ProjectDocsCollection string
+	// SelfCheck 生成回答后再请求一次模型，对照检索到的代码标出没有依据的说法，并附加置信度说明
+	SelfCheck bool
+	// ContextBudget 检索到的代码在提示词中最多占用的 token 数，0 使用 DefaultContextBudget
+	ContextBudget int
+	logger        *Logger
 }
 
 func NewEngine(mc client.Client, e embeddings.Embedder, chat llms.Model, logger *Logger) *SourceInsightEngine {
@@ -30,49 +41,91 @@ func NewEngine(mc client.Client, e embeddings.Embedder, chat llms.Model, logger
 	}
 }
 
-func (e *SourceInsightEngine) Ask(ctx context.Context, question string, fileName string) {
-	// 1. 【路径标准化】：解决 Windows 斜杠问题
-	cleanFileName := filepath.ToSlash(fileName)
+// ownershipInstruction 归属问题的回答要求：只依据索引中记录的 git 信息，不凭代码风格或注释猜测作者
+const ownershipInstruction = `要求：这是代码归属问题，只根据参考代码中的"最后修改（git）"信息回答，注明作者、日期和对应的 文件:行号；
+参考代码没有该信息时，说明索引中没有记录 git 信息（代码不在 git 仓库中、尚未提交，或需要在 git 仓库中重新 scan --reindex），不要猜测作者。`
+
+// historyInstruction 历史问题的回答要求：依据提交说明、改动和 issue/PR 讨论回答，引用提交号或编号
+const historyInstruction = `要求：这是改动历史问题，优先根据相关提交的说明和改动、相关 issue/PR 中的讨论回答"为什么、什么时候"，注明提交号或 issue/PR 编号；其中找不到依据时直接说明，不要编造原因。`
 
-	// 2. 【RAG 检索】：从 Milvus 找相关代码
-	queryVec, err := e.Embedder.EmbedQuery(ctx, question)
+// AskResult 一次问答的结果
+type AskResult struct {
+	Question    string   `json:"question"`
+	Answer      string   `json:"answer"`
+	Intent      Intent   `json:"intent"`
+	Tool        string   `json:"tool,omitempty"`        // 路由到的静态分析工具或模型调用的工具
+	Sources     []string `json:"sources,omitempty"`     // 作为参考的代码片段位置（文件:行号）
+	Commits     []string `json:"commits,omitempty"`     // 作为参考的提交（短提交号）
+	Discussions []string `json:"discussions,omitempty"` // 作为参考的 issue/PR（如 org/repo#12）
+}
+
+// Ask 回答问题并打印分析报告，失败时记录日志（交互模式使用）
+func (e *SourceInsightEngine) Ask(ctx context.Context, question string, fileName string) {
+	result, err := e.Answer(ctx, question, fileName)
 	if err != nil {
-		e.logger.Error("向量化失败", "error", err)
+		e.logger.Error("回答失败", "error", err)
 		return
 	}
+	fmt.Println("\n🔍 分析报告：")
+	fmt.Println(result.Answer)
+}
 
-	searchParam, _ := entity.NewIndexHNSWSearchParam(64)
-	var filterExpr string
-	if cleanFileName != "" {
-		filterExpr = fmt.Sprintf("source == '%s'", cleanFileName)
-	}
+// Answer 回答问题：路由、检索代码、调用模型（必要时执行工具后再请求一次），并把问答存入对话记忆
+// 开启自检时回答附加置信度说明（不存入记忆）
+func (e *SourceInsightEngine) Answer(ctx context.Context, question string, fileName string) (*AskResult, error) {
+	// 1. 【路径标准化】：解决 Windows 斜杠问题
+	cleanFileName := filepath.ToSlash(fileName)
 
-	res, err := e.MilvusClient.Search(ctx, "code_segments", []string{}, filterExpr,
-		[]string{"content", "source"}, []entity.Vector{entity.FloatVector(queryVec)},
-		"vector", entity.COSINE, 3, searchParam)
+	// 2. 【问题路由】：指标、安全类问题先跑静态分析工具，时间、找文件不检索代码
+	route := ClassifyQuestion(question)
+	e.logger.Info("问题路由", "intent", route.Intent, "tool", route.Tool, "keyword", route.Keyword, "file", cleanFileName)
+	currentTranscript().Record(TranscriptRoute, route.Tool, fmt.Sprintf("intent=%s keyword=%s file=%s question=%s", route.Intent, route.Keyword, cleanFileName, question))
+	staticResult := e.runStaticTool(ctx, route, fileName)
+	result := &AskResult{Question: question, Intent: route.Intent, Tool: route.Tool}
 
+	// 3. 【RAG 检索】：从 Milvus 找相关代码（附加引擎的标量过滤条件）
+	var relevantCode string
+	if route.NeedsCode() {
+		filter := e.Filter
+		filter.Source = cleanFileName
+		hits, err := SearchCode(ctx, e.MilvusClient, e.Embedder, question, filter, 3)
+		if err != nil {
+			return nil, fmt.Errorf("检索失败: %w", err)
+		}
+		relevantCode = formatHits(hits, e.ContextBudget)
+		for _, hit := range packHits(mergeOverlappingHits(hits), e.ContextBudget) {
+			result.Sources = append(result.Sources, hit.Location())
+		}
+		currentTranscript().Record(TranscriptRetrieval, "", relevantCode)
+	}
+	relevantCommits, err := e.searchHistory(ctx, route, question)
 	if err != nil {
-		e.logger.Error("Milvus 搜索失败", "error", err)
-		return
+		return nil, err
 	}
-
-	// 3. 【解析 RAG 结果】
-	var builder strings.Builder
-	if len(res) > 0 && res[0].IDs.Len() > 0 {
-		sr := res[0]
-		for i := 0; i < sr.IDs.Len(); i++ {
-			c, _ := sr.Fields.GetColumn("content").Get(i)
-			builder.WriteString(fmt.Sprintf("\n代码片段 %d:\n%s\n", i+1, c))
-		}
+	for _, hit := range relevantCommits {
+		result.Commits = append(result.Commits, hit.ShortHash())
+	}
+	relevantDiscussions, err := e.searchDiscussions(ctx, route, question)
+	if err != nil {
+		return nil, err
+	}
+	for _, hit := range relevantDiscussions {
+		result.Discussions = append(result.Discussions, hit.Ref())
 	}
-	relevantCode := builder.String()
 
-	// 4. 【逻辑降噪】：如果是问时间，不传代码干扰 AI
+	// 4. 【逻辑降噪】：工具类问题（时间、找文件）不传代码干扰 AI
 	var finalPrompt string
-	if strings.Contains(question, "时间") || strings.Contains(question, "几点") {
+	switch {
+	case !route.NeedsCode():
 		finalPrompt = question
-	} else {
-		finalPrompt = fmt.Sprintf("参考代码：\n%s\n问题：%s", relevantCode, question)
+	case staticResult != "":
+		finalPrompt = fmt.Sprintf("静态分析结果（%s）：\n%s\n参考代码（引用时注明 文件:行号）：\n%s\n问题：%s", route.Tool, staticResult, relevantCode, question)
+	case len(relevantCommits) > 0 || len(relevantDiscussions) > 0:
+		finalPrompt = fmt.Sprintf("%s参考代码（引用时注明 文件:行号）：\n%s\n问题：%s\n%s", historyReferences(relevantCommits, relevantDiscussions), relevantCode, question, historyInstruction)
+	case route.Intent == IntentOwnership:
+		finalPrompt = fmt.Sprintf("参考代码（引用时注明 文件:行号）：\n%s\n问题：%s\n%s", relevantCode, question, ownershipInstruction)
+	default:
+		finalPrompt = fmt.Sprintf("参考代码（引用时注明 文件:行号）：\n%s\n问题：%s", relevantCode, question)
 	}
 
 	// 5. 【构造 System Prompt】：下达死命令
@@ -80,7 +133,8 @@ func (e *SourceInsightEngine) Ask(ctx context.Context, question string, fileName
 【工具调用法律】：  
 1. 查时间必须调用 get_current_time。  
 2. 找文件必须调用 search_file。  
-3. 如果你要调用工具，请直接发送 JSON 信号。如果你无法发送信号，请在回复中包含 {"tool_call": "工具名", "arguments": {...}} 格式。`
+3. 涉及 Go 标准库的行为时，如果可以调用 go_doc，先查询文档再回答，不要凭记忆猜测。  
+4. 如果你要调用工具，请直接发送 JSON 信号。如果你无法发送信号，请在回复中包含 {"tool_call": "工具名", "arguments": {...}} 格式。`
 
 	// 6. 【组装消息流】：System -> History -> Human
 	var messages []llms.MessageContent
@@ -89,16 +143,15 @@ func (e *SourceInsightEngine) Ask(ctx context.Context, question string, fileName
 	messages = append(messages, llms.TextParts(llms.ChatMessageTypeHuman, finalPrompt))
 
 	// 7. 【第一次呼叫 AI】：开启工具箱
-	resp, err := e.ChatModel.GenerateContent(ctx, messages, llms.WithTools(TotalTools))
+	availableTools := append(append([]llms.Tool{}, TotalTools...), e.ExtraTools...)
+	resp, err := e.ChatModel.GenerateContent(ctx, messages, llms.WithTools(availableTools))
 	if err != nil {
-		e.logger.Error("AI 请求失败", "error", err)
-		return
+		return nil, fmt.Errorf("AI 请求失败: %w", err)
 	}
 
 	// 检查响应是否有选择项
 	if len(resp.Choices) == 0 {
-		e.logger.Error("AI 响应中没有选择项")
-		return
+		return nil, fmt.Errorf("AI 响应中没有选择项")
 	}
 
 	choice := resp.Choices[0]
@@ -113,6 +166,17 @@ func (e *SourceInsightEngine) Ask(ctx context.Context, question string, fileName
 		if fn, ok := ToolFunctions[toolCall.FunctionCall.Name]; ok {
 			toolResult = fn(toolCall.FunctionCall.Arguments)
 			toolExecuted = true
+		} else if e.ToolRunner != nil {
+			result, err := e.ToolRunner(ctx, toolCall.FunctionCall.Name, toolCall.FunctionCall.Arguments)
+			if err != nil {
+				result = "工具执行失败: " + err.Error()
+			}
+			currentTranscript().Record(TranscriptToolCall, toolCall.FunctionCall.Name, result)
+			toolResult = result
+			toolExecuted = true
+		}
+		if toolExecuted {
+			result.Tool = toolCall.FunctionCall.Name
 			// 反馈给 AI 的正式格式
 			messages = append(messages, llms.TextParts(llms.ChatMessageTypeAI, choice.Content))
 			messages = append(messages, llms.MessageContent{
@@ -151,7 +215,9 @@ func (e *SourceInsightEngine) Ask(ctx context.Context, question string, fileName
 			}
 
 			if toolExecuted {
+				result.Tool = tName
 				e.logger.Info("手动分发成功", "result", toolResult)
+				currentTranscript().Record(TranscriptToolCall, tName, toolResult)
 				// 二次闭环需要的消息
 				messages = append(messages, llms.TextParts(llms.ChatMessageTypeAI, aiSay))
 				messages = append(messages, llms.TextParts(llms.ChatMessageTypeHuman, "系统反馈工具结果: "+toolResult))
@@ -163,13 +229,11 @@ func (e *SourceInsightEngine) Ask(ctx context.Context, question string, fileName
 	if toolExecuted {
 		resp, err = e.ChatModel.GenerateContent(ctx, messages)
 		if err != nil {
-			e.logger.Error("AI 二次请求失败", "error", err)
-			return
+			return nil, fmt.Errorf("AI 二次请求失败: %w", err)
 		}
 		// 再次检查响应是否有选择项
 		if len(resp.Choices) == 0 {
-			e.logger.Error("AI 二次响应中没有选择项")
-			return
+			return nil, fmt.Errorf("AI 二次响应中没有选择项")
 		}
 	}
 
@@ -182,7 +246,77 @@ func (e *SourceInsightEngine) Ask(ctx context.Context, question string, fileName
 		e.History = e.History[2:]
 	}
 
-	// 11. 【最终输出】
-	fmt.Println("\n🔍 分析报告：")
-	fmt.Println(resp.Choices[0].Content)
-}
\ No newline at end of file
+	// 11. 【最终输出】：开启自检时附加置信度说明（不存入记忆）
+	result.Answer = e.appendSelfCheck(ctx, question, resp.Choices[0].Content, relevantCode)
+	return result, nil
+}
+
+// historyTopK 历史问题参考的提交数
+const historyTopK = 3
+
+// searchHistory 历史类问题检索相关提交；没有配置提交历史集合或不是历史问题时返回空
+func (e *SourceInsightEngine) searchHistory(ctx context.Context, route Route, question string) ([]CommitHit, error) {
+	if route.Intent != IntentHistory || e.CommitCollection == "" || e.MilvusClient == nil || e.Embedder == nil {
+		return nil, nil
+	}
+	hits, err := SearchCommits(ctx, e.MilvusClient, e.Embedder, question, historyTopK)
+	if err != nil {
+		return nil, fmt.Errorf("检索提交历史失败: %w", err)
+	}
+	currentTranscript().Record(TranscriptRetrieval, "commits", formatCommitHits(hits))
+	return hits, nil
+}
+
+// searchDiscussions 历史类问题检索相关的 issue/PR；没有配置 issue/PR 集合或不是历史问题时返回空
+func (e *SourceInsightEngine) searchDiscussions(ctx context.Context, route Route, question string) ([]ProjectDocHit, error) {
+	if route.Intent != IntentHistory || e.ProjectDocsCollection == "" || e.MilvusClient == nil || e.Embedder == nil {
+		return nil, nil
+	}
+	hits, err := SearchProjectDocs(ctx, e.MilvusClient, e.Embedder, question, historyTopK)
+	if err != nil {
+		return nil, fmt.Errorf("检索 issue/PR 失败: %w", err)
+	}
+	currentTranscript().Record(TranscriptRetrieval, "discussions", formatProjectDocHits(hits))
+	return hits, nil
+}
+
+// historyReferences 历史问题提示词中的相关提交和相关讨论，没有的部分省略
+func historyReferences(commits []CommitHit, discussions []ProjectDocHit) string {
+	var sb strings.Builder
+	if len(commits) > 0 {
+		sb.WriteString(fmt.Sprintf("相关提交（引用时注明提交号）：\n%s\n", formatCommitHits(commits)))
+	}
+	if len(discussions) > 0 {
+		sb.WriteString(fmt.Sprintf("相关 issue/PR（引用时注明编号）：\n%s\n", formatProjectDocHits(discussions)))
+	}
+	return sb.String()
+}
+
+// runStaticTool 路由到静态分析工具时，对提问的文件执行该工具，返回结果供模型参考
+// 没有指定文件、没有注册工具执行器或执行失败时返回空，退化为只用检索到的代码回答
+func (e *SourceInsightEngine) runStaticTool(ctx context.Context, route Route, fileName string) string {
+	if route.Intent != IntentMetric && route.Intent != IntentSecurity {
+		return ""
+	}
+	if fileName == "" || e.ToolRunner == nil {
+		e.logger.Info("静态分析工具不可用，改为代码检索", "tool", route.Tool, "file", fileName, "runner", e.ToolRunner != nil)
+		return ""
+	}
+	code, err := os.ReadFile(fileName)
+	if err != nil {
+		e.logger.Warn("读取文件失败，改为代码检索", "file", fileName, "error", err)
+		return ""
+	}
+	args, ok := staticToolArguments(route.Tool, fileName, []byte(NormalizeSource(string(code))))
+	if !ok {
+		return ""
+	}
+	result, err := e.ToolRunner(ctx, route.Tool, args)
+	if err != nil {
+		e.logger.Warn("静态分析工具执行失败，改为代码检索", "tool", route.Tool, "error", err)
+		return ""
+	}
+	e.logger.Info("静态分析工具执行完成", "tool", route.Tool, "result_bytes", len(result))
+	currentTranscript().Record(TranscriptToolCall, route.Tool, result)
+	return result
+}
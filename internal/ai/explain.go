@@ -0,0 +1,81 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// FindingContext 需要解释的分析问题及其上下文
+type FindingContext struct {
+	RuleID          string // 规则ID
+	RuleName        string // 规则名称
+	Severity        string // 严重程度
+	Category        string // 问题类别
+	RuleDescription string // 规则文档
+	Suggestion      string // 规则给出的通用修复建议
+	File            string // 文件名
+	Line            int    // 行号
+	Function        string // 所在函数
+	Snippet         string // 问题代码（带行号的上下文）
+}
+
+// ExplainFinding 解释"为什么这里被标记"，并给出针对性的修复补丁
+// 检索同文件的相关代码作为补充上下文；未连接 Milvus 时只使用问题代码
+func (e *SourceInsightEngine) ExplainFinding(ctx context.Context, finding FindingContext) (string, error) {
+	related := e.retrieveRelated(ctx, finding.Snippet, finding.File)
+
+	prompt := fmt.Sprintf(`你是一名资深 Go 代码审查专家。静态分析工具在下面的代码中标记了一个问题。
+请结合【规则说明】【问题代码】和【相关代码】回答：
+1. 解释：用 2-4 句话说明这段代码为什么被标记，以及在本项目中可能造成的实际影响；如果你认为这是误报，请明确说明理由。
+2. 修复补丁：给出一个针对这段代码的 unified diff（以 --- a/%s 和 +++ b/%s 开头），只修改必要的行。
+
+【规则说明】：
+规则: %s %s（%s，%s）
+说明: %s
+通用建议: %s
+
+【问题代码】（%s:%d，函数 %s）：
+%s
+
+【相关代码】：
+%s`,
+		finding.File, finding.File,
+		finding.RuleID, finding.RuleName, finding.Severity, finding.Category,
+		finding.RuleDescription, finding.Suggestion,
+		finding.File, finding.Line, finding.Function,
+		finding.Snippet, related)
+
+	resp, err := e.ChatModel.GenerateContent(ctx, []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, prompt),
+	})
+	if err != nil {
+		return "", fmt.Errorf("AI 请求失败: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("AI 响应中没有选择项")
+	}
+	question := fmt.Sprintf("%s:%d 为什么被标记为 %s %s", finding.File, finding.Line, finding.RuleID, finding.RuleName)
+	return e.appendSelfCheck(ctx, question, resp.Choices[0].Content, finding.Snippet+"\n"+related), nil
+}
+
+// retrieveRelated 从 Milvus 检索与问题代码相关的片段（限定在同一文件）
+func (e *SourceInsightEngine) retrieveRelated(ctx context.Context, query, fileName string) string {
+	if e.MilvusClient == nil || e.Embedder == nil {
+		return "（未连接代码索引）"
+	}
+
+	hits, err := SearchCode(ctx, e.MilvusClient, e.Embedder, query, SearchFilter{Source: fileName, Scopes: e.Filter.Scopes}, 3)
+	if err != nil {
+		e.logger.Warn("检索失败", "error", err)
+		return "（检索失败）"
+	}
+
+	related := formatHits(hits, e.ContextBudget)
+	currentTranscript().Record(TranscriptRetrieval, "", related)
+	if related == "" {
+		return "（无）"
+	}
+	return related
+}
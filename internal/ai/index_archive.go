@@ -0,0 +1,398 @@
+package ai
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+// 索引归档中的文件
+const (
+	archiveManifest = "manifest.json"
+	archiveChunks   = "chunks.jsonl"
+)
+
+// indexBatchSize 导出分页和导入批量写入的行数
+const indexBatchSize = 512
+
+// 归档的压缩方式，由扩展名决定
+const (
+	compressNone = ""
+	compressGzip = "gzip"
+	compressZstd = "zstd"
+)
+
+// importCollectionSuffix 导入时临时集合名的后缀，全部写入成功后才替换目标集合
+const importCollectionSuffix = "_importing"
+
+// IndexManifest 索引归档的元数据，导入时用于校验表结构版本和向量模型
+type IndexManifest struct {
+	SchemaVersion  int       `json:"schema_version"`
+	EmbeddingModel string    `json:"embedding_model"`
+	Dim            int       `json:"dim"`
+	Rows           int       `json:"rows"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ExportCodeIndex 把集合中的代码索引（片段、向量和元数据）导出为 tar 归档，.tar.gz / .tgz 使用 gzip 压缩，.tar.zst 使用 zstd 压缩
+// CI 构建一次索引后导出，开发者导入即可使用，不需要重新生成向量；go.work 成员模块的集合名见 CodeCollectionName
+func ExportCodeIndex(ctx context.Context, m client.Client, collection, path string) (*IndexManifest, error) {
+	info, err := DescribeCodeIndex(ctx, m, collection)
+	if err != nil {
+		return nil, err
+	}
+	if !info.Exists {
+		return nil, fmt.Errorf("%w，请先运行 scan 建立索引", ErrIndexMissing)
+	}
+	if info.SchemaVersion != CodeSchemaVersion {
+		return nil, staleIndexError(info.Mismatches(""))
+	}
+	if err := m.LoadCollection(ctx, collection, false); err != nil {
+		return nil, fmt.Errorf("加载集合失败: %w", err)
+	}
+
+	// tar 条目需要预先知道大小，片段先写入临时文件
+	tmp, err := os.CreateTemp("", "insight-index-*.jsonl")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	buffered := bufio.NewWriter(tmp)
+	encoder := json.NewEncoder(buffered)
+	rows := 0
+	err = queryCodeChunks(ctx, m, collection, func(row CodeChunkRow) error {
+		rows++
+		return encoder.Encode(row)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := buffered.Flush(); err != nil {
+		return nil, fmt.Errorf("写入临时文件失败: %w", err)
+	}
+
+	manifest := &IndexManifest{
+		SchemaVersion:  info.SchemaVersion,
+		EmbeddingModel: info.EmbeddingModel,
+		Dim:            codeVectorDim,
+		Rows:           rows,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if err := writeIndexArchive(path, manifest, tmp); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// queryCodeChunks 按主键分页读取集合中的全部片段
+func queryCodeChunks(ctx context.Context, m client.Client, collection string, fn func(CodeChunkRow) error) error {
+	fields := []string{"id", "source", "content", "summary", "acl", "dependency", "complexity", "findings", "line", "author", "modified", "doc_type", "section", "vector", "summary_vector"}
+	lastID := int64(-1)
+	for {
+		rs, err := m.Query(ctx, collection, []string{}, fmt.Sprintf("id > %d", lastID), fields,
+			client.WithLimit(indexBatchSize))
+		if err != nil {
+			return fmt.Errorf("读取索引数据失败: %w", err)
+		}
+		idCol := rs.GetColumn("id")
+		if idCol == nil || idCol.Len() == 0 {
+			return nil
+		}
+		vectors, _ := rs.GetColumn("vector").(*entity.ColumnFloatVector)
+		summaryVectors, _ := rs.GetColumn("summary_vector").(*entity.ColumnFloatVector)
+		if vectors == nil || summaryVectors == nil {
+			return fmt.Errorf("读取索引数据失败: 缺少向量字段")
+		}
+
+		for i := 0; i < idCol.Len(); i++ {
+			id, err := idCol.GetAsInt64(i)
+			if err != nil {
+				return fmt.Errorf("读取主键失败: %w", err)
+			}
+			if id > lastID {
+				lastID = id
+			}
+			row := CodeChunkRow{
+				Vector:        vectors.Data()[i],
+				SummaryVector: summaryVectors.Data()[i],
+			}
+			row.Source, _ = rs.GetColumn("source").GetAsString(i)
+			row.Content, _ = rs.GetColumn("content").GetAsString(i)
+			row.Summary, _ = rs.GetColumn("summary").GetAsString(i)
+			row.ACL, _ = rs.GetColumn("acl").GetAsString(i)
+			if col, ok := rs.GetColumn("dependency").(*entity.ColumnBool); ok {
+				row.Dependency = col.Data()[i]
+			}
+			row.Complexity, _ = rs.GetColumn("complexity").GetAsInt64(i)
+			row.Findings, _ = rs.GetColumn("findings").GetAsInt64(i)
+			if col := rs.GetColumn("line"); col != nil {
+				row.Line, _ = col.GetAsInt64(i)
+			}
+			if col := rs.GetColumn("author"); col != nil {
+				row.Author, _ = col.GetAsString(i)
+			}
+			if col := rs.GetColumn("modified"); col != nil {
+				row.Modified, _ = col.GetAsInt64(i)
+			}
+			if col := rs.GetColumn("doc_type"); col != nil {
+				row.DocType, _ = col.GetAsString(i)
+			}
+			if col := rs.GetColumn("section"); col != nil {
+				row.Section, _ = col.GetAsString(i)
+			}
+			if err := fn(row); err != nil {
+				return fmt.Errorf("写入片段失败: %w", err)
+			}
+		}
+		if idCol.Len() < indexBatchSize {
+			return nil
+		}
+	}
+}
+
+// writeIndexArchive 写出归档：manifest.json 在前，导入时可以先校验再读取片段
+func writeIndexArchive(path string, manifest *IndexManifest, chunks *os.File) error {
+	compression, err := archiveCompression(path)
+	if err != nil {
+		return err
+	}
+	stat, err := chunks.Stat()
+	if err != nil {
+		return fmt.Errorf("读取临时文件失败: %w", err)
+	}
+	if _, err := chunks.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("读取临时文件失败: %w", err)
+	}
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化索引元数据失败: %w", err)
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("创建归档失败: %w", err)
+	}
+	defer out.Close()
+
+	var w io.Writer = out
+	var compressor io.WriteCloser
+	switch compression {
+	case compressGzip:
+		compressor = gzip.NewWriter(out)
+	case compressZstd:
+		if compressor, err = zstd.NewWriter(out); err != nil {
+			return fmt.Errorf("创建 zstd 压缩器失败: %w", err)
+		}
+	}
+	if compressor != nil {
+		w = compressor
+	}
+	tw := tar.NewWriter(w)
+	if err := tw.WriteHeader(&tar.Header{Name: archiveManifest, Mode: 0644, Size: int64(len(manifestBytes)), ModTime: manifest.CreatedAt}); err != nil {
+		return fmt.Errorf("写入归档失败: %w", err)
+	}
+	if _, err := tw.Write(manifestBytes); err != nil {
+		return fmt.Errorf("写入归档失败: %w", err)
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: archiveChunks, Mode: 0644, Size: stat.Size(), ModTime: manifest.CreatedAt}); err != nil {
+		return fmt.Errorf("写入归档失败: %w", err)
+	}
+	if _, err := io.Copy(tw, chunks); err != nil {
+		return fmt.Errorf("写入归档失败: %w", err)
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("写入归档失败: %w", err)
+	}
+	if compressor != nil {
+		if err := compressor.Close(); err != nil {
+			return fmt.Errorf("写入归档失败: %w", err)
+		}
+	}
+	return out.Close()
+}
+
+// ImportCodeIndex 从归档导入代码索引，替换集合 collection
+// 归档的表结构版本必须与当前程序一致；向量模型与配置不一致时拒绝导入，否则检索向量与索引向量不可比
+// 片段先写入临时集合，归档完整读完、全部写入成功后才删除旧集合并把临时集合改名，归档损坏时原有索引不受影响
+func ImportCodeIndex(ctx context.Context, m client.Client, collection, path, embeddingModel string) (*IndexManifest, error) {
+	tr, closeArchive, err := openIndexArchive(path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeArchive()
+
+	manifest, err := readIndexManifest(tr)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.SchemaVersion != CodeSchemaVersion {
+		return nil, fmt.Errorf("%w（归档表结构版本 %d，当前版本 %d），请用当前版本重新导出", ErrIndexStale, manifest.SchemaVersion, CodeSchemaVersion)
+	}
+	if manifest.Dim != codeVectorDim {
+		return nil, fmt.Errorf("归档向量维度 %d 与当前维度 %d 不一致", manifest.Dim, codeVectorDim)
+	}
+	if embeddingModel != "" && manifest.EmbeddingModel != embeddingModel {
+		return nil, fmt.Errorf("归档向量模型 %s 与当前配置 %s 不一致", manifest.EmbeddingModel, embeddingModel)
+	}
+
+	header, err := tr.Next()
+	if err != nil || header.Name != archiveChunks {
+		return nil, fmt.Errorf("归档格式错误: 缺少 %s", archiveChunks)
+	}
+
+	staging := importCollectionName(collection)
+	if err := ResetCodeCollection(ctx, m, staging, manifest.EmbeddingModel); err != nil {
+		return nil, err
+	}
+	if err := importCodeChunks(ctx, m, staging, tr, manifest.Rows); err != nil {
+		if dropErr := m.DropCollection(ctx, staging); dropErr != nil {
+			return nil, fmt.Errorf("%w（清理临时集合 %s 失败: %v）", err, staging, dropErr)
+		}
+		return nil, err
+	}
+	if err := replaceCollection(ctx, m, staging, collection); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// importCodeChunks 逐行解码片段并批量写入集合，行数必须与元数据一致
+func importCodeChunks(ctx context.Context, m client.Client, collection string, r io.Reader, rows int) error {
+	decoder := json.NewDecoder(r)
+	batch := make([]CodeChunkRow, 0, indexBatchSize)
+	imported := 0
+	for {
+		var row CodeChunkRow
+		if err := decoder.Decode(&row); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("读取第 %d 个片段失败: %w", imported+len(batch)+1, err)
+		}
+		if len(row.Vector) != codeVectorDim || len(row.SummaryVector) != codeVectorDim {
+			return fmt.Errorf("第 %d 个片段向量维度错误", imported+len(batch)+1)
+		}
+		batch = append(batch, row)
+		if len(batch) == indexBatchSize {
+			if err := InsertCodeChunks(ctx, m, collection, batch); err != nil {
+				return err
+			}
+			imported += len(batch)
+			batch = batch[:0]
+		}
+	}
+	if len(batch) > 0 {
+		if err := InsertCodeChunks(ctx, m, collection, batch); err != nil {
+			return err
+		}
+		imported += len(batch)
+	}
+	if imported != rows {
+		return fmt.Errorf("归档不完整: 元数据记录 %d 个片段，实际读取 %d 个", rows, imported)
+	}
+	return nil
+}
+
+// replaceCollection 删除目标集合（存在时），把临时集合改名为目标集合名
+func replaceCollection(ctx context.Context, m client.Client, staging, collection string) error {
+	exists, err := m.HasCollection(ctx, collection)
+	if err != nil {
+		return fmt.Errorf("检查集合失败: %w", err)
+	}
+	if exists {
+		if err := m.DropCollection(ctx, collection); err != nil {
+			return fmt.Errorf("删除旧集合失败: %w", err)
+		}
+	}
+	if err := m.RenameCollection(ctx, staging, collection); err != nil {
+		return fmt.Errorf("临时集合 %s 改名为 %s 失败: %w", staging, collection, err)
+	}
+	return nil
+}
+
+// importCollectionName 导入用的临时集合名，保留后缀的前提下不超过 Milvus 集合名的长度上限
+func importCollectionName(collection string) string {
+	if len(collection)+len(importCollectionSuffix) > 255 {
+		collection = collection[:255-len(importCollectionSuffix)]
+	}
+	return collection + importCollectionSuffix
+}
+
+// openIndexArchive 按扩展名解压并打开归档，返回的函数关闭解压器和文件
+func openIndexArchive(path string) (*tar.Reader, func(), error) {
+	compression, err := archiveCompression(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	in, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("打开归档失败: %w", err)
+	}
+
+	var r io.Reader = in
+	closeArchive := func() { in.Close() }
+	switch compression {
+	case compressGzip:
+		gz, err := gzip.NewReader(in)
+		if err != nil {
+			in.Close()
+			return nil, nil, fmt.Errorf("解压归档失败: %w", err)
+		}
+		r = gz
+		closeArchive = func() { gz.Close(); in.Close() }
+	case compressZstd:
+		zr, err := zstd.NewReader(in)
+		if err != nil {
+			in.Close()
+			return nil, nil, fmt.Errorf("解压归档失败: %w", err)
+		}
+		r = zr
+		closeArchive = func() { zr.Close(); in.Close() }
+	}
+	return tar.NewReader(r), closeArchive, nil
+}
+
+// readIndexManifest 读取归档中的第一个条目 manifest.json
+func readIndexManifest(tr *tar.Reader) (*IndexManifest, error) {
+	header, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("读取归档失败: %w", err)
+	}
+	if header.Name != archiveManifest {
+		return nil, fmt.Errorf("归档格式错误: 第一个条目应为 %s，实际为 %s", archiveManifest, header.Name)
+	}
+	var manifest IndexManifest
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("解析索引元数据失败: %w", err)
+	}
+	return &manifest, nil
+}
+
+// archiveCompression 根据扩展名判断压缩方式：.tar.gz / .tgz 为 gzip，.tar.zst / .tzst 为 zstd，.tar 不压缩
+func archiveCompression(path string) (string, error) {
+	lower := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return compressGzip, nil
+	case strings.HasSuffix(lower, ".tar.zst"), strings.HasSuffix(lower, ".tzst"):
+		return compressZstd, nil
+	case strings.HasSuffix(lower, ".tar"):
+		return compressNone, nil
+	default:
+		return "", fmt.Errorf("不支持的归档格式: %s（支持 .tar.zst、.tar.gz、.tgz、.tar）", path)
+	}
+}
@@ -0,0 +1,75 @@
+package ai
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 测试归档读写：三种压缩方式写出的归档都能读回元数据和片段
+func TestIndexArchiveRoundTrip(t *testing.T) {
+	row := CodeChunkRow{Source: "main.go", Content: "func main() {}", Vector: make([]float32, codeVectorDim), SummaryVector: make([]float32, codeVectorDim)}
+	for _, name := range []string{"index.tar", "index.tar.gz", "index.tgz", "index.tar.zst"} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			chunks, err := os.Create(filepath.Join(dir, "chunks.jsonl"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer chunks.Close()
+			if err := json.NewEncoder(chunks).Encode(row); err != nil {
+				t.Fatal(err)
+			}
+
+			path := filepath.Join(dir, name)
+			manifest := &IndexManifest{SchemaVersion: CodeSchemaVersion, EmbeddingModel: "bge-m3:latest", Dim: codeVectorDim, Rows: 1, CreatedAt: time.Now().UTC()}
+			if err := writeIndexArchive(path, manifest, chunks); err != nil {
+				t.Fatalf("写入归档失败: %v", err)
+			}
+
+			tr, closeArchive, err := openIndexArchive(path)
+			if err != nil {
+				t.Fatalf("打开归档失败: %v", err)
+			}
+			defer closeArchive()
+			got, err := readIndexManifest(tr)
+			if err != nil {
+				t.Fatalf("读取元数据失败: %v", err)
+			}
+			if got.Rows != 1 || got.EmbeddingModel != manifest.EmbeddingModel {
+				t.Errorf("元数据不一致: %+v", got)
+			}
+			header, err := tr.Next()
+			if err != nil || header.Name != archiveChunks {
+				t.Fatalf("第二个条目应为 %s: %v", archiveChunks, err)
+			}
+			var decoded CodeChunkRow
+			if err := json.NewDecoder(tr).Decode(&decoded); err != nil {
+				t.Fatalf("解码片段失败: %v", err)
+			}
+			if decoded.Source != row.Source || len(decoded.Vector) != codeVectorDim {
+				t.Errorf("片段不一致: source=%s dim=%d", decoded.Source, len(decoded.Vector))
+			}
+		})
+	}
+}
+
+// 测试不支持的扩展名和临时集合名的长度上限
+func TestArchiveCompressionAndStagingName(t *testing.T) {
+	if _, err := archiveCompression("index.zip"); err == nil {
+		t.Error("index.zip 应该报不支持的格式")
+	}
+	if got, _ := archiveCompression("INDEX.TAR.ZST"); got != compressZstd {
+		t.Errorf("扩展名大小写不敏感，期望 zstd，实际 %q", got)
+	}
+	if got := importCollectionName(CodeCollection); got != CodeCollection+importCollectionSuffix {
+		t.Errorf("临时集合名 = %s", got)
+	}
+	long := importCollectionName(strings.Repeat("a", 255))
+	if len(long) != 255 || !strings.HasSuffix(long, importCollectionSuffix) {
+		t.Errorf("临时集合名应截断到 255 个字符并保留后缀，实际长度 %d", len(long))
+	}
+}
@@ -0,0 +1,130 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+)
+
+// CodeSchemaVersion 代码片段集合的表结构版本，修改 codeSchema 时需要递增
+//
+//	1: source / content / vector
+//	2: 增加 complexity / findings 标量字段
+//	3: 增加 summary / summary_vector 摘要字段
+//	4: 增加 acl 访问控制标签
+//	5: 增加 dependency 标记（依赖模块的源码）
+//	6: 增加 line 字段（片段内容的起始行号）
+//	7: 增加 author / modified 字段（片段最后一次修改的 git 作者和时间）
+//	8: 增加 doc_type / section 字段（README、docs/、ADR 等项目文档的片段）
+const CodeSchemaVersion = 8
+
+// 集合属性键，建表时写入，用于检测索引是否过期
+const (
+	propSchemaVersion  = "insight.schema_version"
+	propEmbeddingModel = "insight.embedding_model"
+)
+
+var (
+	ErrIndexMissing = errors.New("代码索引不存在")
+	ErrIndexStale   = errors.New("代码索引已过期")
+)
+
+// IndexInfo 代码索引的版本信息
+type IndexInfo struct {
+	Exists         bool   `json:"exists"`
+	SchemaVersion  int    `json:"schema_version"`  // 0 表示旧版本集合，没有记录版本
+	EmbeddingModel string `json:"embedding_model"` // 建索引时使用的向量模型，旧版本集合为空
+	Rows           int64  `json:"rows"`
+}
+
+// Mismatches 与当前程序的表结构版本和配置的向量模型比较，返回不一致的原因
+func (info *IndexInfo) Mismatches(embeddingModel string) []string {
+	var reasons []string
+	if info.SchemaVersion != CodeSchemaVersion {
+		if info.SchemaVersion == 0 {
+			reasons = append(reasons, fmt.Sprintf("集合没有记录表结构版本（当前版本 %d）", CodeSchemaVersion))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("表结构版本 %d，当前版本 %d", info.SchemaVersion, CodeSchemaVersion))
+		}
+	}
+	if embeddingModel != "" && info.EmbeddingModel != embeddingModel {
+		stored := info.EmbeddingModel
+		if stored == "" {
+			stored = "未记录"
+		}
+		reasons = append(reasons, fmt.Sprintf("向量模型 %s，当前配置 %s", stored, embeddingModel))
+	}
+	return reasons
+}
+
+// DescribeCodeIndex 读取代码索引的版本信息，集合不存在时 Exists 为 false
+func DescribeCodeIndex(ctx context.Context, m client.Client, collection string) (*IndexInfo, error) {
+	exists, err := m.HasCollection(ctx, collection)
+	if err != nil {
+		return nil, fmt.Errorf("检查集合失败: %w", err)
+	}
+	info := &IndexInfo{Exists: exists}
+	if !exists {
+		return info, nil
+	}
+
+	coll, err := m.DescribeCollection(ctx, collection)
+	if err != nil {
+		return nil, fmt.Errorf("读取集合信息失败: %w", err)
+	}
+	info.SchemaVersion, _ = strconv.Atoi(coll.Properties[propSchemaVersion])
+	info.EmbeddingModel = coll.Properties[propEmbeddingModel]
+
+	stats, err := m.GetCollectionStatistics(ctx, collection)
+	if err == nil {
+		info.Rows, _ = strconv.ParseInt(stats["row_count"], 10, 64)
+	}
+	return info, nil
+}
+
+// CheckCodeIndex 检查代码索引是否存在且与当前版本、向量模型一致
+// 不一致时返回 ErrIndexStale，避免在过期的集合上返回错误的检索结果
+func CheckCodeIndex(ctx context.Context, m client.Client, collection, embeddingModel string) error {
+	info, err := DescribeCodeIndex(ctx, m, collection)
+	if err != nil {
+		return err
+	}
+	if !info.Exists {
+		return fmt.Errorf("%w，请先运行 scan 建立索引", ErrIndexMissing)
+	}
+	if reasons := info.Mismatches(embeddingModel); len(reasons) > 0 {
+		return staleIndexError(reasons)
+	}
+	return nil
+}
+
+// staleIndexError 生成带原因和处理建议的 ErrIndexStale
+func staleIndexError(reasons []string) error {
+	return fmt.Errorf("%w（%s），请运行 scan --reindex 重建索引", ErrIndexStale, strings.Join(reasons, "；"))
+}
+
+// ResetCodeCollection 删除现有的代码片段集合并按当前版本重建（重建后需要重新写入数据）
+func ResetCodeCollection(ctx context.Context, m client.Client, collection, embeddingModel string) error {
+	exists, err := m.HasCollection(ctx, collection)
+	if err != nil {
+		return fmt.Errorf("检查集合失败: %w", err)
+	}
+	if exists {
+		if err := m.DropCollection(ctx, collection); err != nil {
+			return fmt.Errorf("删除旧集合失败: %w", err)
+		}
+	}
+	return EnsureCodeCollection(ctx, m, collection, embeddingModel)
+}
+
+// codeCollectionOptions 建表时写入的版本属性
+func codeCollectionOptions(embeddingModel string) []client.CreateCollectionOption {
+	return []client.CreateCollectionOption{
+		client.WithCollectionProperty(propSchemaVersion, strconv.Itoa(CodeSchemaVersion)),
+		client.WithCollectionProperty(propEmbeddingModel, embeddingModel),
+	}
+}
@@ -0,0 +1,68 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+// fakeIndexClient 只实现读取集合版本信息需要的方法，其余方法调用时 panic
+type fakeIndexClient struct {
+	client.Client
+	props map[string]string // 集合属性，nil 表示集合不存在
+}
+
+func (c *fakeIndexClient) HasCollection(ctx context.Context, name string) (bool, error) {
+	return c.props != nil, nil
+}
+
+func (c *fakeIndexClient) DescribeCollection(ctx context.Context, name string) (*entity.Collection, error) {
+	return &entity.Collection{Name: name, Properties: c.props}, nil
+}
+
+func (c *fakeIndexClient) GetCollectionStatistics(ctx context.Context, name string) (map[string]string, error) {
+	return map[string]string{"row_count": "42"}, nil
+}
+
+// 测试索引版本检查：旧表结构版本（如没有 doc_type / section 字段的 7）、未记录版本和向量模型不一致都返回 ErrIndexStale
+func TestCheckCodeIndex(t *testing.T) {
+	const model = "bge-m3:latest"
+	current := strconv.Itoa(CodeSchemaVersion)
+	cases := []struct {
+		name  string
+		props map[string]string
+		want  error
+	}{
+		{"当前版本", map[string]string{propSchemaVersion: current, propEmbeddingModel: model}, nil},
+		{"旧表结构版本", map[string]string{propSchemaVersion: strconv.Itoa(CodeSchemaVersion - 1), propEmbeddingModel: model}, ErrIndexStale},
+		{"未记录版本", map[string]string{}, ErrIndexStale},
+		{"向量模型不一致", map[string]string{propSchemaVersion: current, propEmbeddingModel: "nomic-embed-text"}, ErrIndexStale},
+		{"集合不存在", nil, ErrIndexMissing},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckCodeIndex(context.Background(), &fakeIndexClient{props: tc.props}, CodeCollection, model)
+			if tc.want == nil {
+				if err != nil {
+					t.Errorf("不应报错: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tc.want) {
+				t.Errorf("错误 = %v，期望 %v", err, tc.want)
+			}
+		})
+	}
+
+	info, err := DescribeCodeIndex(context.Background(), &fakeIndexClient{props: map[string]string{propSchemaVersion: "7", propEmbeddingModel: model}}, CodeCollection)
+	if err != nil {
+		t.Fatalf("读取版本信息失败: %v", err)
+	}
+	if info.Rows != 42 || len(info.Mismatches(model)) != 1 {
+		t.Errorf("版本 7 的索引应该只有表结构版本一处不一致: %+v %v", info, info.Mismatches(model))
+	}
+}
@@ -8,17 +8,34 @@ import (
 	"github.com/tmc/langchaingo/schema"
 )
 
-func IndexDocs(ctx context.Context, mc client.Client, e embeddings.Embedder, chunks []schema.Document) error {
+// 代码片段元数据键
+const (
+	MetaSource     = "source"     // 源文件路径
+	MetaFunction   = "function"   // 所在函数（方法为 Type.Method）
+	MetaStartLine  = "start_line" // 所在函数的起始行
+	MetaEndLine    = "end_line"   // 所在函数的结束行
+	MetaLine       = "line"       // 片段内容第一行在源文件中的行号，内容与源码不连续时不设置
+	MetaComplexity = "complexity" // 所在函数的圈复杂度
+	MetaFindings   = "findings"   // 所在函数的分析问题数
+	MetaSummary    = "summary"    // LLM 生成的自然语言摘要
+	MetaACL        = "acl"        // 访问控制标签，为空表示公开
+	MetaDependency = "dependency" // 为 true 表示依赖模块的源码
+	MetaAuthor     = "author"     // 片段最后一次修改的 git 作者
+	MetaModified   = "modified"   // 片段最后一次修改的提交时间（Unix 秒）
+	MetaDocType    = "doc_type"   // 项目文档类型（DocTypeReadme 等），代码片段不设置
+	MetaSection    = "section"    // 文档片段所属的章节（各级标题用 " > " 连接）
+)
+
+// IndexDocs 为代码片段（以及文档片段）生成向量并写入 collection 集合
+func IndexDocs(ctx context.Context, mc client.Client, collection string, e embeddings.Embedder, chunks []schema.Document) error {
 	var contents []string
-	var sources []string
 	for _, chunk := range chunks {
-		contents = append(contents, chunk.PageContent)
-		sources = append(sources, chunk.Metadata["source"].(string))
+		contents = append(contents, embeddingText(chunk))
 	}
 	fmt.Printf("正在为 %d 个碎块生成向量数字...\n", len(contents))
-	vectors, err := e.EmbedDocuments(ctx, contents)
+	vectors, err := embedInBatches(ctx, e, contents)
 	if err != nil {
-		return fmt.Errorf("生成向量失败: %v", err)
+		return fmt.Errorf("生成向量失败: %w", err)
 	}
 
 	// 调试：检查向量维度
@@ -28,11 +45,121 @@ func IndexDocs(ctx context.Context, mc client.Client, e embeddings.Embedder, chu
 		fmt.Println("✗ 警告：没有生成任何向量！")
 	}
 
+	summaryVectors, err := embedSummaries(ctx, e, chunks, vectors)
+	if err != nil {
+		return err
+	}
+
 	fmt.Println("正在将数据存入 Milvus 数据库...")
-	err = InsertCodeChunks(ctx, mc, sources, contents, vectors)
+	err = InsertCodeChunks(ctx, mc, collection, codeChunkRows(chunks, vectors, summaryVectors))
 	if err != nil {
 		return fmt.Errorf("插入数据失败: %v", err)
 	}
 	fmt.Println("索引创建完成！AI 现在已经记住你的代码了。")
 	return nil
 }
+
+// embeddingText 生成向量的文本：文档片段前面加上所属章节，从长章节中切出的后半段也能按标题命中
+// 入库的 content 仍是原文，行号与源文件一致
+func embeddingText(chunk schema.Document) string {
+	if section, _ := chunk.Metadata[MetaSection].(string); section != "" {
+		return section + "\n" + chunk.PageContent
+	}
+	return chunk.PageContent
+}
+
+// codeChunkRows 把片段及其向量组装为入库的行
+func codeChunkRows(chunks []schema.Document, vectors, summaryVectors [][]float32) []CodeChunkRow {
+	rows := make([]CodeChunkRow, len(chunks))
+	for i, chunk := range chunks {
+		source, _ := chunk.Metadata[MetaSource].(string)
+		summary, _ := chunk.Metadata[MetaSummary].(string)
+		acl, _ := chunk.Metadata[MetaACL].(string)
+		dependency, _ := chunk.Metadata[MetaDependency].(bool)
+		author, _ := chunk.Metadata[MetaAuthor].(string)
+		docType, _ := chunk.Metadata[MetaDocType].(string)
+		section, _ := chunk.Metadata[MetaSection].(string)
+		rows[i] = CodeChunkRow{
+			Source:        source,
+			Content:       chunk.PageContent,
+			Summary:       summary,
+			ACL:           acl,
+			Dependency:    dependency,
+			Complexity:    int64(MetadataInt(chunk.Metadata, MetaComplexity)),
+			Findings:      int64(MetadataInt(chunk.Metadata, MetaFindings)),
+			Line:          int64(MetadataInt(chunk.Metadata, MetaLine)),
+			Author:        author,
+			Modified:      int64(MetadataInt(chunk.Metadata, MetaModified)),
+			DocType:       docType,
+			Section:       section,
+			Vector:        vectors[i],
+			SummaryVector: summaryVectors[i],
+		}
+	}
+	return rows
+}
+
+// embedSummaries 为带摘要的片段生成摘要向量；没有摘要的片段沿用代码向量，保证两个向量字段都有值
+func embedSummaries(ctx context.Context, e embeddings.Embedder, chunks []schema.Document, codeVectors [][]float32) ([][]float32, error) {
+	result := make([][]float32, len(chunks))
+	copy(result, codeVectors)
+
+	var summaries []string
+	var positions []int
+	for i, chunk := range chunks {
+		if summary, _ := chunk.Metadata[MetaSummary].(string); summary != "" {
+			summaries = append(summaries, summary)
+			positions = append(positions, i)
+		}
+	}
+	if len(summaries) == 0 {
+		return result, nil
+	}
+
+	fmt.Printf("正在为 %d 条摘要生成向量...\n", len(summaries))
+	vectors, err := embedInBatches(ctx, e, summaries)
+	if err != nil {
+		return nil, fmt.Errorf("生成摘要向量失败: %w", err)
+	}
+	if len(vectors) != len(summaries) {
+		return nil, fmt.Errorf("摘要向量数量不符: 期望 %d，实际 %d", len(summaries), len(vectors))
+	}
+	for i, pos := range positions {
+		result[pos] = vectors[i]
+	}
+	return result, nil
+}
+
+// embedBatchSize 每批生成向量的文本数
+const embedBatchSize = 64
+
+// embedInBatches 分批生成向量，每批之前检查 ctx，超时或 Ctrl-C 后不再发起新的请求
+func embedInBatches(ctx context.Context, e embeddings.Embedder, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += embedBatchSize {
+		if ctx.Err() != nil {
+			return nil, context.Cause(ctx)
+		}
+		end := min(start+embedBatchSize, len(texts))
+		batch, err := e.EmbedDocuments(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, batch...)
+	}
+	return vectors, nil
+}
+
+// MetadataInt 读取整数类型的元数据，缺失或类型不符时返回 0
+func MetadataInt(metadata map[string]any, key string) int {
+	switch v := metadata[key].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,259 @@
+package ai
+
+import (
+	"maps"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// 项目文档类型（doc_type 字段），代码片段为空
+const (
+	DocTypeReadme = "readme" // README
+	DocTypeADR    = "adr"    // 架构决策记录
+	DocTypeGuide  = "doc"    // docs/ 下的其他文档
+)
+
+// DefaultDocRatio 检索结果中文档片段默认最多占的比例
+const DefaultDocRatio = 0.25
+
+// 文档片段的默认大小上限（字节），与按函数切分的代码片段大致相当
+const defaultMarkdownChunkSize = 2000
+
+// sectionMaxLength section 字段的最大长度（字节）
+const sectionMaxLength = 500
+
+// MarkdownSplitter 按标题切分 Markdown 文档：每个章节（标题及其正文，到下一个标题为止）是一个片段
+// 超过 MaxChunkSize 的章节在段落边界切开，每一段都记录所属章节；围栏代码块中的 # 不算标题，代码块也不会从中间切开
+type MarkdownSplitter struct {
+	MaxChunkSize int // 单个片段的最大字节数，单独一个段落超过时按行切开
+}
+
+// NewMarkdownSplitter 创建按标题切分的文档切分器
+func NewMarkdownSplitter() *MarkdownSplitter {
+	return &MarkdownSplitter{MaxChunkSize: defaultMarkdownChunkSize}
+}
+
+// markdownSection 一个章节：path 为从顶层到本章节的标题，line 为第一行的行号
+type markdownSection struct {
+	path  string
+	line  int
+	lines []string
+}
+
+// SplitDocuments 切分文档，片段带上原文档的元数据、起始行号（MetaLine）和所属章节（MetaSection）
+// 只有标题没有正文的章节不单独成片段，标题仍然出现在下级章节的路径中
+func (s *MarkdownSplitter) SplitDocuments(docs []schema.Document) ([]schema.Document, error) {
+	maxSize := s.MaxChunkSize
+	if maxSize <= 0 {
+		maxSize = defaultMarkdownChunkSize
+	}
+	var chunks []schema.Document
+	for _, doc := range docs {
+		for _, section := range splitMarkdownSections(doc.PageContent) {
+			for _, part := range splitSectionParts(section.lines, maxSize) {
+				metadata := maps.Clone(doc.Metadata)
+				if metadata == nil {
+					metadata = map[string]any{}
+				}
+				metadata[MetaLine] = section.line + part.offset
+				if section.path != "" {
+					metadata[MetaSection] = section.path
+				}
+				chunks = append(chunks, schema.Document{PageContent: part.text, Metadata: metadata})
+			}
+		}
+	}
+	return chunks, nil
+}
+
+// splitMarkdownSections 按 ATX 标题（# 到 ######）把文档分成章节，第一个标题之前的内容是没有路径的章节
+func splitMarkdownSections(content string) []markdownSection {
+	var sections []markdownSection
+	var titles [6]string
+	current := markdownSection{line: 1}
+	fence := ""
+	flush := func() {
+		for _, line := range current.lines[min(1, len(current.lines)):] {
+			if strings.TrimSpace(line) != "" {
+				sections = append(sections, current)
+				return
+			}
+		}
+		// 第一个标题之前的内容没有标题行，第一行也算正文
+		if current.path == "" && len(current.lines) > 0 && strings.TrimSpace(current.lines[0]) != "" {
+			sections = append(sections, current)
+		}
+	}
+
+	for i, line := range strings.Split(content, "\n") {
+		if marker := fenceMarker(line); marker != "" {
+			if fence == "" {
+				fence = marker
+			} else if strings.HasPrefix(marker, fence) {
+				fence = ""
+			}
+		} else if fence == "" {
+			if level, title, ok := atxHeading(line); ok {
+				flush()
+				titles[level-1] = title
+				clear(titles[level:])
+				current = markdownSection{path: sectionPath(titles[:level]), line: i + 1}
+			}
+		}
+		current.lines = append(current.lines, line)
+	}
+	flush()
+	return sections
+}
+
+// sectionPath 用 " > " 连接各级标题（跳过缺失的层级），超过 section 字段长度时截断
+func sectionPath(titles []string) string {
+	var parts []string
+	for _, title := range titles {
+		if title != "" {
+			parts = append(parts, title)
+		}
+	}
+	path := strings.Join(parts, " > ")
+	if len(path) > sectionMaxLength {
+		path = strings.ToValidUTF8(path[:sectionMaxLength], "")
+	}
+	return path
+}
+
+// atxHeading 解析 ATX 标题行，返回级别和去掉首尾 # 的标题文字；最多缩进 3 个空格
+func atxHeading(line string) (int, string, bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return 0, "", false
+	}
+	level := 0
+	for level < len(trimmed) && trimmed[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 {
+		return 0, "", false
+	}
+	rest := trimmed[level:]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return 0, "", false // #tag 不是标题
+	}
+	title := strings.TrimSpace(rest)
+	if closing := strings.TrimRight(title, "#"); closing == "" || strings.HasSuffix(closing, " ") {
+		title = strings.TrimSpace(closing)
+	}
+	return level, title, true
+}
+
+// fenceMarker 围栏代码块的开始或结束行返回围栏符号（``` 或 ~~~ 的连续部分），其他行返回空
+func fenceMarker(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 || len(trimmed) < 3 {
+		return ""
+	}
+	c := trimmed[0]
+	if c != '`' && c != '~' {
+		return ""
+	}
+	n := 0
+	for n < len(trimmed) && trimmed[n] == c {
+		n++
+	}
+	if n < 3 {
+		return ""
+	}
+	return trimmed[:n]
+}
+
+// sectionPart 章节切开后的一段，offset 为第一行相对章节第一行的偏移
+type sectionPart struct {
+	text   string
+	offset int
+}
+
+// splitSectionParts 章节不超过 maxSize 时整体作为一段，否则按段落（空行分隔，代码块算一个段落）尽量装满每一段
+// 单个段落超过 maxSize 时按行切开；每段去掉首尾的空行
+func splitSectionParts(lines []string, maxSize int) []sectionPart {
+	blocks := markdownBlocks(lines)
+	if len(blocks) == 0 {
+		return nil
+	}
+	var parts []sectionPart
+	emit := func(start, end int) {
+		parts = append(parts, sectionPart{text: strings.Join(lines[start:end], "\n"), offset: start})
+	}
+	if linesSize(lines[blocks[0][0]:blocks[len(blocks)-1][1]]) <= maxSize {
+		emit(blocks[0][0], blocks[len(blocks)-1][1])
+		return parts
+	}
+
+	start, end := -1, -1
+	for _, block := range blocks {
+		if start >= 0 && linesSize(lines[start:block[1]]) <= maxSize {
+			end = block[1]
+			continue
+		}
+		if start >= 0 {
+			emit(start, end)
+		}
+		start, end = block[0], block[1]
+		if linesSize(lines[start:end]) <= maxSize {
+			continue
+		}
+		// 超长的段落按行切开，最后不足一段的部分留给后面的段落继续装
+		for from := start; from < end; {
+			to := from + 1
+			for to < end && linesSize(lines[from:to+1]) <= maxSize {
+				to++
+			}
+			if to == end {
+				start = from
+				break
+			}
+			emit(from, to)
+			from = to
+		}
+	}
+	emit(start, end)
+	return parts
+}
+
+// markdownBlocks 空行分隔的段落，返回每个段落的 [起始行, 结束行)；围栏代码块中的空行不分段
+func markdownBlocks(lines []string) [][2]int {
+	var blocks [][2]int
+	start := -1
+	fence := ""
+	for i, line := range lines {
+		if marker := fenceMarker(line); marker != "" {
+			if fence == "" {
+				fence = marker
+			} else if strings.HasPrefix(marker, fence) {
+				fence = ""
+			}
+		}
+		if strings.TrimSpace(line) == "" && fence == "" {
+			if start >= 0 {
+				blocks = append(blocks, [2]int{start, i})
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		blocks = append(blocks, [2]int{start, len(lines)})
+	}
+	return blocks
+}
+
+// linesSize 多行拼接后的字节数
+func linesSize(lines []string) int {
+	size := 0
+	for _, line := range lines {
+		size += len(line) + 1
+	}
+	return max(size-1, 0)
+}
@@ -0,0 +1,119 @@
+package ai
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// 测试按标题切分章节：各级标题组成路径，同级或更高级的标题结束下级章节，只有标题的章节不单独成片段
+func TestSplitMarkdownSections(t *testing.T) {
+	type section struct {
+		path string
+		line int
+	}
+	cases := []struct {
+		name    string
+		content string
+		want    []section
+	}{
+		{
+			name:    "标题层级",
+			content: "# 安装\n简介\n## 依赖\nGo 1.22\n### Milvus\n端口 19530\n## 配置\nconfig.json",
+			want:    []section{{"安装", 1}, {"安装 > 依赖", 3}, {"安装 > 依赖 > Milvus", 5}, {"安装 > 配置", 7}},
+		},
+		{
+			name:    "跳过的层级和新的顶级标题",
+			content: "# A\n### C\nc\n# B\nb",
+			want:    []section{{"A > C", 2}, {"B", 4}},
+		},
+		{
+			name:    "第一个标题之前的内容",
+			content: "徽章\n\n# 标题\n正文",
+			want:    []section{{"", 1}, {"标题", 3}},
+		},
+		{
+			name:    "代码块中的 # 不是标题",
+			content: "# 用法\n```sh\n# 启动服务\ngo run .\n```\n## 参数\n-v",
+			want:    []section{{"用法", 1}, {"用法 > 参数", 6}},
+		},
+		{
+			name:    "波浪线围栏和更长的结束围栏",
+			content: "# A\n~~~\n# x\n```\n# y\n~~~~\n## B\nb",
+			want:    []section{{"A", 1}, {"A > B", 7}},
+		},
+		{
+			name:    "只有标题的章节",
+			content: "# A\n\n## B\n\n## C\nc",
+			want:    []section{{"A > C", 5}},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []section
+			for _, s := range splitMarkdownSections(tc.content) {
+				got = append(got, section{s.path, s.line})
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("章节 = %v，期望 %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// 测试 ATX 标题的识别
+func TestAtxHeading(t *testing.T) {
+	cases := []struct {
+		line  string
+		level int
+		title string
+		ok    bool
+	}{
+		{"# 标题", 1, "标题", true},
+		{"### 三级 ###", 3, "三级", true},
+		{"## C# 入门", 2, "C# 入门", true},
+		{"   # 缩进三格", 1, "缩进三格", true},
+		{"    # 缩进四格是代码", 0, "", false},
+		{"#tag", 0, "", false},
+		{"####### 七级", 0, "", false},
+		{"#", 1, "", true},
+		{"正文 # 不在行首", 0, "", false},
+	}
+	for _, tc := range cases {
+		level, title, ok := atxHeading(tc.line)
+		if level != tc.level || title != tc.title || ok != tc.ok {
+			t.Errorf("atxHeading(%q) = %d, %q, %v，期望 %d, %q, %v", tc.line, level, title, ok, tc.level, tc.title, tc.ok)
+		}
+	}
+}
+
+// 测试超长章节在段落边界切开：代码块不从中间切开，每段记录起始行号和所属章节
+func TestMarkdownSplitter_LongSection(t *testing.T) {
+	content := "# 指南\n" +
+		"第一段" + strings.Repeat("甲", 10) + "\n\n" +
+		"```go\n# 注释\nfunc main() {}\n```\n\n" +
+		"第三段" + strings.Repeat("乙", 10)
+	splitter := &MarkdownSplitter{MaxChunkSize: 60}
+	chunks, err := splitter.SplitDocuments([]schema.Document{{PageContent: content, Metadata: map[string]any{"source": "README.md"}}})
+	if err != nil {
+		t.Fatalf("切分失败: %v", err)
+	}
+
+	wantLines := []int{1, 4, 9}
+	if len(chunks) != len(wantLines) {
+		t.Fatalf("应该切成 %d 段，实际 %d 段: %v", len(wantLines), len(chunks), chunks)
+	}
+	for i, chunk := range chunks {
+		if chunk.Metadata[MetaLine] != wantLines[i] || chunk.Metadata[MetaSection] != "指南" || chunk.Metadata["source"] != "README.md" {
+			t.Errorf("第 %d 段元数据 = %v", i+1, chunk.Metadata)
+		}
+		if len(chunk.PageContent) > 60 {
+			t.Errorf("第 %d 段超过大小上限: %d", i+1, len(chunk.PageContent))
+		}
+	}
+	if chunks[1].PageContent != "```go\n# 注释\nfunc main() {}\n```" {
+		t.Errorf("代码块应该完整地作为一段: %q", chunks[1].PageContent)
+	}
+}
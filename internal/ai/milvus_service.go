@@ -6,6 +6,8 @@ import (
 	"github.com/milvus-io/milvus-sdk-go/v2/client" // 引入 Milvus SDK
 	"github.com/milvus-io/milvus-sdk-go/v2/entity"
 	"log"
+	"strings"
+	"unicode"
 )
 
 //	func InitMilvus(ctx context.Context) client.Client {
@@ -184,43 +186,210 @@ import (
 //		}
 //		return "没找到", nil
 //	}
-func InitCode(ctx context.Context) client.Client {
-	m, err := client.NewClient(ctx, client.Config{
-		Address: "localhost:19530",
-	})
-	if err != nil {
-		log.Fatal("连接 Milvus 失败:", err)
+
+// CodeCollection 代码片段集合名
+const CodeCollection = "code_segments"
+
+// CodeCollectionName 返回模块的代码片段集合名，module 为空时返回 CodeCollection
+// go.work 中的每个成员模块单独建集合，检索时不会混入其他模块的片段
+func CodeCollectionName(module string) string {
+	if module == "" {
+		return CodeCollection
+	}
+	var sb strings.Builder
+	for _, r := range module {
+		if r < 128 && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteByte('_')
+		}
 	}
+	// Milvus 集合名最长 255 个字符
+	name := CodeCollection + "_" + sb.String()
+	if len(name) > 255 {
+		name = name[:255]
+	}
+	return name
+}
+
+// codeVectorDim 代码向量的默认维度（bge-m3），临时评测集合按实际模型的维度建表
+const codeVectorDim = 1024
+
+// CodeContentMaxLength content 字段的最大长度（字节），超过时整批写入失败
+const CodeContentMaxLength = 10000
+
+// codeVectorFields 代码片段集合中的向量字段，每个字段都需要单独建索引
+var codeVectorFields = []string{"vector", "summary_vector"}
+
+// codeSchema 代码片段集合的表结构
+// complexity / findings 是分析器输出的标量字段，检索时可以与语义相似度组合过滤
+// line 是片段内容第一行在源文件中的行号（0 表示未知），用于给提示词中的代码标注行号
+// author / modified 是片段中最近一次提交的作者和时间（Unix 秒，0 表示不在 git 仓库中或尚未提交），用于回答代码归属问题
+// doc_type / section 是项目文档片段的类型和所属章节，代码片段为空，检索时按 doc_type 区分文档和代码
+// summary_vector 是 LLM 摘要的向量（没有摘要时与 vector 相同），检索时与代码向量一起召回
+func codeSchema(collection string, dim int) *entity.Schema {
 	fields := []*entity.Field{
 		entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true).WithIsAutoID(true),
 		entity.NewField().WithName("source").WithDataType(entity.FieldTypeVarChar).WithMaxLength(500),
-		entity.NewField().WithName("content").WithDataType(entity.FieldTypeVarChar).WithMaxLength(10000),
-		entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithDim(1024),
+		entity.NewField().WithName("content").WithDataType(entity.FieldTypeVarChar).WithMaxLength(CodeContentMaxLength),
+		entity.NewField().WithName("summary").WithDataType(entity.FieldTypeVarChar).WithMaxLength(2000),
+		entity.NewField().WithName("acl").WithDataType(entity.FieldTypeVarChar).WithMaxLength(100),
+		entity.NewField().WithName("dependency").WithDataType(entity.FieldTypeBool),
+		entity.NewField().WithName("complexity").WithDataType(entity.FieldTypeInt64),
+		entity.NewField().WithName("findings").WithDataType(entity.FieldTypeInt64),
+		entity.NewField().WithName("line").WithDataType(entity.FieldTypeInt64),
+		entity.NewField().WithName("author").WithDataType(entity.FieldTypeVarChar).WithMaxLength(200),
+		entity.NewField().WithName("modified").WithDataType(entity.FieldTypeInt64),
+		entity.NewField().WithName("doc_type").WithDataType(entity.FieldTypeVarChar).WithMaxLength(16),
+		entity.NewField().WithName("section").WithDataType(entity.FieldTypeVarChar).WithMaxLength(sectionMaxLength),
+		entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithDim(int64(dim)),
+		entity.NewField().WithName("summary_vector").WithDataType(entity.FieldTypeFloatVector).WithDim(int64(dim)),
 	}
-	schema := &entity.Schema{
-		CollectionName: "code_segments",
+	return &entity.Schema{
+		CollectionName: collection,
 		Fields:         fields,
 		Description:    "用户代码库",
 	}
-	err = m.CreateCollection(ctx, schema, entity.DefaultShardNumber)
+}
+
+func InitCode(ctx context.Context) client.Client {
+	m, err := client.NewClient(ctx, client.Config{
+		Address: "localhost:19530",
+	})
 	if err != nil {
-		fmt.Printf("表可能已经存在: %v\n", err)
+		log.Fatal("连接 Milvus 失败:", err)
+	}
+	if err := EnsureCodeCollection(ctx, m, CodeCollection, "bge-m3:latest"); err != nil {
+		fmt.Printf("初始化集合失败: %v\n", err)
 	}
-	idx, _ := entity.NewIndexHNSW(entity.COSINE, 16, 64)
-	_ = m.CreateIndex(ctx, "code_segments", "vector", idx, false)
-	_ = m.LoadCollection(ctx, "code_segments", false)
 	fmt.Println("code_segments 初始化成功")
 	return m
 }
-func InsertCodeChunks(ctx context.Context, m client.Client, sources []string, contents []string, vectors [][]float32) error {
+
+// EnsureCodeCollection 确保代码片段集合存在，并建立索引、加载到内存
+// 新建的集合记录表结构版本和向量模型；已有集合与之不一致时返回 ErrIndexStale
+func EnsureCodeCollection(ctx context.Context, m client.Client, collection, embeddingModel string) error {
+	return ensureCodeCollection(ctx, m, collection, embeddingModel, codeVectorDim)
+}
+
+// ensureCodeCollection 确保集合存在，新建时向量字段为 dim 维
+func ensureCodeCollection(ctx context.Context, m client.Client, collection, embeddingModel string, dim int) error {
+	info, err := DescribeCodeIndex(ctx, m, collection)
+	if err != nil {
+		return err
+	}
+	if info.Exists {
+		if reasons := info.Mismatches(embeddingModel); len(reasons) > 0 {
+			return staleIndexError(reasons)
+		}
+	} else {
+		if err := m.CreateCollection(ctx, codeSchema(collection, dim), entity.DefaultShardNumber, codeCollectionOptions(embeddingModel)...); err != nil {
+			return fmt.Errorf("创建集合失败: %w", err)
+		}
+		idx, err := entity.NewIndexHNSW(entity.COSINE, 16, 64)
+		if err != nil {
+			return fmt.Errorf("创建索引参数失败: %w", err)
+		}
+		for _, field := range codeVectorFields {
+			if err := m.CreateIndex(ctx, collection, field, idx, false); err != nil {
+				return fmt.Errorf("创建索引 %s 失败: %w", field, err)
+			}
+		}
+	}
+	if err := m.LoadCollection(ctx, collection, false); err != nil {
+		return fmt.Errorf("加载集合失败: %w", err)
+	}
+	return nil
+}
+
+// ConnectMilvus 连接 Milvus（不创建集合，失败时返回错误而不是退出）
+func ConnectMilvus(ctx context.Context, endpoint string) (client.Client, error) {
+	m, err := client.NewClient(ctx, client.Config{
+		Address: endpoint,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("连接 Milvus 失败: %w", err)
+	}
+	return m, nil
+}
+
+// CodeChunkRow 一条待入库的代码片段
+type CodeChunkRow struct {
+	Source        string    `json:"source"`
+	Content       string    `json:"content"`
+	Summary       string    `json:"summary,omitempty"`    // LLM 生成的摘要，可以为空
+	ACL           string    `json:"acl,omitempty"`        // 访问控制标签，为空表示公开
+	Dependency    bool      `json:"dependency,omitempty"` // 是否为依赖模块的源码（来自模块缓存，只读）
+	Complexity    int64     `json:"complexity"`           // 片段所在函数的圈复杂度
+	Findings      int64     `json:"findings"`             // 片段所在函数的分析问题数
+	Line          int64     `json:"line,omitempty"`       // 片段内容第一行在源文件中的行号，0 表示未知
+	Author        string    `json:"author,omitempty"`     // 片段最后一次修改的 git 作者
+	Modified      int64     `json:"modified,omitempty"`   // 片段最后一次修改的提交时间（Unix 秒），0 表示未知
+	DocType       string    `json:"doc_type,omitempty"`   // 项目文档类型，代码片段为空
+	Section       string    `json:"section,omitempty"`    // 文档片段所属的章节
+	Vector        []float32 `json:"vector"`
+	SummaryVector []float32 `json:"summary_vector"` // 摘要向量，没有摘要时与 Vector 相同
+}
+
+// InsertCodeChunks 批量写入代码片段并 Flush
+func InsertCodeChunks(ctx context.Context, m client.Client, collection string, rows []CodeChunkRow) error {
+	sources := make([]string, len(rows))
+	contents := make([]string, len(rows))
+	summaries := make([]string, len(rows))
+	acls := make([]string, len(rows))
+	dependencies := make([]bool, len(rows))
+	complexities := make([]int64, len(rows))
+	findings := make([]int64, len(rows))
+	lines := make([]int64, len(rows))
+	authors := make([]string, len(rows))
+	modified := make([]int64, len(rows))
+	docTypes := make([]string, len(rows))
+	sections := make([]string, len(rows))
+	vectors := make([][]float32, len(rows))
+	summaryVectors := make([][]float32, len(rows))
+	for i, row := range rows {
+		sources[i] = row.Source
+		contents[i] = row.Content
+		summaries[i] = row.Summary
+		acls[i] = row.ACL
+		dependencies[i] = row.Dependency
+		complexities[i] = row.Complexity
+		findings[i] = row.Findings
+		lines[i] = row.Line
+		authors[i] = row.Author
+		modified[i] = row.Modified
+		docTypes[i] = row.DocType
+		sections[i] = row.Section
+		vectors[i] = row.Vector
+		summaryVectors[i] = row.SummaryVector
+		if summaryVectors[i] == nil {
+			summaryVectors[i] = row.Vector
+		}
+	}
+
 	sourcesCol := entity.NewColumnVarChar("source", sources)
 	contentsCol := entity.NewColumnVarChar("content", contents)
-	vectorsCol := entity.NewColumnFloatVector("vector", 1024, vectors)
-	_, err := m.Insert(ctx, "code_segments", "", sourcesCol, vectorsCol, contentsCol)
+	complexityCol := entity.NewColumnInt64("complexity", complexities)
+	findingsCol := entity.NewColumnInt64("findings", findings)
+	lineCol := entity.NewColumnInt64("line", lines)
+	authorCol := entity.NewColumnVarChar("author", authors)
+	modifiedCol := entity.NewColumnInt64("modified", modified)
+	docTypeCol := entity.NewColumnVarChar("doc_type", docTypes)
+	sectionCol := entity.NewColumnVarChar("section", sections)
+	summariesCol := entity.NewColumnVarChar("summary", summaries)
+	aclCol := entity.NewColumnVarChar("acl", acls)
+	dependencyCol := entity.NewColumnBool("dependency", dependencies)
+	dim := codeVectorDim
+	if len(rows) > 0 && len(rows[0].Vector) > 0 {
+		dim = len(rows[0].Vector)
+	}
+	vectorsCol := entity.NewColumnFloatVector("vector", dim, vectors)
+	summaryVectorsCol := entity.NewColumnFloatVector("summary_vector", dim, summaryVectors)
+	_, err := m.Insert(ctx, collection, "", sourcesCol, vectorsCol, contentsCol, summariesCol, aclCol, dependencyCol, complexityCol, findingsCol, lineCol, authorCol, modifiedCol, docTypeCol, sectionCol, summaryVectorsCol)
 	if err != nil {
 		return fmt.Errorf("插入数据失败: %v", err)
 	}
-	err = m.Flush(ctx, "code_segments", false)
+	err = m.Flush(ctx, collection, false)
 	if err != nil {
 		return fmt.Errorf("Flush 失败: %v", err)
 	}
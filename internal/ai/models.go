@@ -0,0 +1,45 @@
+package ai
+
+import (
+	"fmt"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/ollama"
+)
+
+// NewChatModel 根据 Ollama 地址和模型名创建对话模型
+// 只创建客户端，不会立即连接 Ollama
+func NewChatModel(endpoint, model string) (llms.Model, error) {
+	opts := []ollama.Option{ollama.WithModel(model)}
+	if endpoint != "" {
+		opts = append(opts, ollama.WithServerURL(endpoint))
+	}
+
+	chat, err := ollama.New(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("创建对话模型失败: %w", err)
+	}
+	if t := currentTranscript(); t != nil {
+		return &transcriptModel{Model: chat, transcript: t}, nil
+	}
+	return chat, nil
+}
+
+// NewEmbedder 根据 Ollama 地址和模型名创建向量模型
+func NewEmbedder(endpoint, model string) (embeddings.Embedder, error) {
+	opts := []ollama.Option{ollama.WithModel(model)}
+	if endpoint != "" {
+		opts = append(opts, ollama.WithServerURL(endpoint))
+	}
+
+	embedLLM, err := ollama.New(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("创建向量模型失败: %w", err)
+	}
+	e, err := embeddings.NewEmbedder(embedLLM)
+	if err != nil {
+		return nil, fmt.Errorf("创建向量模型失败: %w", err)
+	}
+	return e, nil
+}
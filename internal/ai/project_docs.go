@@ -0,0 +1,292 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+// ProjectDocsCollection issue 和 PR 讨论集合（sync-docs 同步，可选），与代码片段集合分开存放
+const ProjectDocsCollection = "project_docs"
+
+// ProjectDocsSchemaVersion issue/PR 集合的表结构版本，修改 projectDocsSchema 时需要递增
+//
+//	1: repo / kind / number / url / updated / content / vector
+const ProjectDocsSchemaVersion = 1
+
+// ProjectDoc.Kind 的取值
+const (
+	ProjectDocIssue       = "issue"
+	ProjectDocPullRequest = "pr" // GitHub PR 或 GitLab MR
+)
+
+// ProjectDoc 一个 issue 或 PR：标题、描述和基本信息（不含评论）
+type ProjectDoc struct {
+	Repo    string   `json:"repo"` // owner/name，GitLab 为项目路径
+	Kind    string   `json:"kind"` // issue, pr
+	Number  int64    `json:"number"`
+	Title   string   `json:"title"`
+	State   string   `json:"state"`
+	Author  string   `json:"author"`
+	URL     string   `json:"url"`
+	Labels  []string `json:"labels,omitempty"`
+	Updated int64    `json:"updated"` // 最后更新时间（Unix 秒）
+	Body    string   `json:"body"`
+}
+
+// Ref 引用时使用的编号，如 org/repo#12
+func (d ProjectDoc) Ref() string {
+	return projectDocRef(d.Repo, d.Number)
+}
+
+// Text 向量化和写入提示词的文本：编号、类型、状态、标题、标签和描述，超过 content 字段上限时截断
+func (d ProjectDoc) Text() string {
+	kind := "Issue"
+	if d.Kind == ProjectDocPullRequest {
+		kind = "PR"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s（%s）: %s\n作者: %s  更新: %s\n", kind, d.Ref(), d.State, d.Title, d.Author, time.Unix(d.Updated, 0).UTC().Format(time.DateOnly))
+	if len(d.Labels) > 0 {
+		sb.WriteString("标签: " + strings.Join(d.Labels, ", ") + "\n")
+	}
+	if body := strings.TrimSpace(d.Body); body != "" {
+		sb.WriteString("\n" + body + "\n")
+	}
+	text := sb.String()
+	if len(text) > CodeContentMaxLength {
+		text = text[:CodeContentMaxLength]
+		for !utf8.ValidString(text) {
+			text = text[:len(text)-1]
+		}
+	}
+	return text
+}
+
+// ProjectDocHit 一条 issue/PR 的检索结果
+type ProjectDocHit struct {
+	Repo    string  `json:"repo"`
+	Kind    string  `json:"kind"`
+	Number  int64   `json:"number"`
+	URL     string  `json:"url"`
+	Updated int64   `json:"updated"`
+	Content string  `json:"content"`
+	Score   float32 `json:"score"`
+}
+
+// Ref 引用时使用的编号，如 org/repo#12
+func (h ProjectDocHit) Ref() string {
+	return projectDocRef(h.Repo, h.Number)
+}
+
+// projectDocRef 仓库和编号组成的引用
+func projectDocRef(repo string, number int64) string {
+	return fmt.Sprintf("%s#%d", repo, number)
+}
+
+// projectDocsSchema issue/PR 集合的表结构
+func projectDocsSchema(collection string, dim int) *entity.Schema {
+	fields := []*entity.Field{
+		entity.NewField().WithName("id").WithDataType(entity.FieldTypeInt64).WithIsPrimaryKey(true).WithIsAutoID(true),
+		entity.NewField().WithName("repo").WithDataType(entity.FieldTypeVarChar).WithMaxLength(200),
+		entity.NewField().WithName("kind").WithDataType(entity.FieldTypeVarChar).WithMaxLength(16),
+		entity.NewField().WithName("number").WithDataType(entity.FieldTypeInt64),
+		entity.NewField().WithName("url").WithDataType(entity.FieldTypeVarChar).WithMaxLength(500),
+		entity.NewField().WithName("updated").WithDataType(entity.FieldTypeInt64),
+		entity.NewField().WithName("content").WithDataType(entity.FieldTypeVarChar).WithMaxLength(CodeContentMaxLength),
+		entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithDim(int64(dim)),
+	}
+	return &entity.Schema{
+		CollectionName: collection,
+		Fields:         fields,
+		Description:    "issue 和 PR 讨论",
+	}
+}
+
+// EnsureProjectDocsCollection 确保 issue/PR 集合存在且与当前版本、向量模型一致
+// reset 为 true，或者现有集合的版本、向量模型不一致时删除重建（之后需要全量同步），返回是否重建
+func EnsureProjectDocsCollection(ctx context.Context, m client.Client, embeddingModel string, reset bool) (bool, error) {
+	info, err := DescribeCodeIndex(ctx, m, ProjectDocsCollection)
+	if err != nil {
+		return false, err
+	}
+	if info.Exists {
+		if !reset && info.SchemaVersion == ProjectDocsSchemaVersion && info.EmbeddingModel == embeddingModel {
+			return false, nil
+		}
+		if err := m.DropCollection(ctx, ProjectDocsCollection); err != nil {
+			return false, fmt.Errorf("删除旧集合失败: %w", err)
+		}
+	}
+
+	options := []client.CreateCollectionOption{
+		client.WithCollectionProperty(propSchemaVersion, strconv.Itoa(ProjectDocsSchemaVersion)),
+		client.WithCollectionProperty(propEmbeddingModel, embeddingModel),
+	}
+	if err := m.CreateCollection(ctx, projectDocsSchema(ProjectDocsCollection, codeVectorDim), entity.DefaultShardNumber, options...); err != nil {
+		return false, fmt.Errorf("创建集合失败: %w", err)
+	}
+	idx, err := entity.NewIndexHNSW(entity.COSINE, 16, 64)
+	if err != nil {
+		return false, fmt.Errorf("创建索引参数失败: %w", err)
+	}
+	if err := m.CreateIndex(ctx, ProjectDocsCollection, "vector", idx, false); err != nil {
+		return false, fmt.Errorf("创建索引 vector 失败: %w", err)
+	}
+	if err := m.LoadCollection(ctx, ProjectDocsCollection, false); err != nil {
+		return false, fmt.Errorf("加载集合失败: %w", err)
+	}
+	return true, nil
+}
+
+// HasProjectDocs issue/PR 集合是否存在且与当前版本、向量模型一致；不一致时不检索，避免返回错误的结果
+func HasProjectDocs(ctx context.Context, m client.Client, embeddingModel string) bool {
+	info, err := DescribeCodeIndex(ctx, m, ProjectDocsCollection)
+	if err != nil || !info.Exists {
+		return false
+	}
+	return info.SchemaVersion == ProjectDocsSchemaVersion && (embeddingModel == "" || info.EmbeddingModel == embeddingModel)
+}
+
+// ProjectDocsSyncedAt 集合中该仓库最近一次更新的 issue/PR 的时间（Unix 秒），没有记录时返回 0
+// 增量同步只拉取此后更新过的 issue/PR
+func ProjectDocsSyncedAt(ctx context.Context, m client.Client, repo string) (int64, error) {
+	var latest int64
+	lastID := int64(-1)
+	for {
+		rs, err := m.Query(ctx, ProjectDocsCollection, []string{}, fmt.Sprintf("repo == '%s' && id > %d", repo, lastID),
+			[]string{"id", "updated"}, client.WithLimit(indexBatchSize))
+		if err != nil {
+			return 0, fmt.Errorf("读取同步记录失败: %w", err)
+		}
+		idCol, updatedCol := rs.GetColumn("id"), rs.GetColumn("updated")
+		if idCol == nil || updatedCol == nil || idCol.Len() == 0 {
+			return latest, nil
+		}
+		for i := 0; i < idCol.Len(); i++ {
+			id, _ := idCol.GetAsInt64(i)
+			updated, _ := updatedCol.GetAsInt64(i)
+			lastID = max(lastID, id)
+			latest = max(latest, updated)
+		}
+	}
+}
+
+// UpsertProjectDocs 写入 issue/PR：先删除集合中同一仓库、同一编号的旧记录，再生成向量插入
+func UpsertProjectDocs(ctx context.Context, m client.Client, e embeddings.Embedder, docs []ProjectDoc) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	// GitHub 的 issue 和 PR 共用编号，GitLab 的 issue 和 MR 各自编号，因此按仓库和类型删除
+	stale := make(map[[2]string][]string)
+	for _, d := range docs {
+		key := [2]string{d.Repo, d.Kind}
+		stale[key] = append(stale[key], strconv.FormatInt(d.Number, 10))
+	}
+	for key, numbers := range stale {
+		expr := fmt.Sprintf("repo == '%s' && kind == '%s' && number in [%s]", key[0], key[1], strings.Join(numbers, ", "))
+		if err := m.Delete(ctx, ProjectDocsCollection, "", expr); err != nil {
+			return fmt.Errorf("删除旧记录失败: %w", err)
+		}
+	}
+
+	texts := make([]string, len(docs))
+	for i, d := range docs {
+		texts[i] = d.Text()
+	}
+	vectors, err := embedInBatches(ctx, e, texts)
+	if err != nil {
+		return fmt.Errorf("生成 issue/PR 向量失败: %w", err)
+	}
+	if len(vectors) != len(docs) {
+		return fmt.Errorf("issue/PR 向量数量不符: 期望 %d，实际 %d", len(docs), len(vectors))
+	}
+
+	repos := make([]string, len(docs))
+	kinds := make([]string, len(docs))
+	numbers := make([]int64, len(docs))
+	urls := make([]string, len(docs))
+	updated := make([]int64, len(docs))
+	for i, d := range docs {
+		repos[i], kinds[i], numbers[i], urls[i], updated[i] = d.Repo, d.Kind, d.Number, d.URL, d.Updated
+	}
+	_, err = m.Insert(ctx, ProjectDocsCollection, "",
+		entity.NewColumnVarChar("repo", repos),
+		entity.NewColumnVarChar("kind", kinds),
+		entity.NewColumnInt64("number", numbers),
+		entity.NewColumnVarChar("url", urls),
+		entity.NewColumnInt64("updated", updated),
+		entity.NewColumnVarChar("content", texts),
+		entity.NewColumnFloatVector("vector", len(vectors[0]), vectors))
+	if err != nil {
+		return fmt.Errorf("插入 issue/PR 失败: %w", err)
+	}
+	if err := m.Flush(ctx, ProjectDocsCollection, false); err != nil {
+		return fmt.Errorf("Flush 失败: %w", err)
+	}
+	return nil
+}
+
+// SearchProjectDocs 在 issue/PR 讨论中语义检索
+func SearchProjectDocs(ctx context.Context, m client.Client, e embeddings.Embedder, query string, topK int) ([]ProjectDocHit, error) {
+	queryVec, err := e.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("向量化失败: %w", err)
+	}
+	searchParam, _ := entity.NewIndexHNSWSearchParam(64)
+	res, err := m.Search(ctx, ProjectDocsCollection, []string{}, "",
+		[]string{"repo", "kind", "number", "url", "updated", "content"}, []entity.Vector{entity.FloatVector(queryVec)},
+		"vector", entity.COSINE, topK, searchParam)
+	if err != nil {
+		return nil, fmt.Errorf("Milvus 搜索 issue/PR 失败: %w", err)
+	}
+
+	var hits []ProjectDocHit
+	if len(res) == 0 {
+		return hits, nil
+	}
+	sr := res[0]
+	for i := 0; i < sr.IDs.Len(); i++ {
+		var hit ProjectDocHit
+		if col := sr.Fields.GetColumn("repo"); col != nil {
+			hit.Repo, _ = col.GetAsString(i)
+		}
+		if col := sr.Fields.GetColumn("kind"); col != nil {
+			hit.Kind, _ = col.GetAsString(i)
+		}
+		if col := sr.Fields.GetColumn("number"); col != nil {
+			hit.Number, _ = col.GetAsInt64(i)
+		}
+		if col := sr.Fields.GetColumn("url"); col != nil {
+			hit.URL, _ = col.GetAsString(i)
+		}
+		if col := sr.Fields.GetColumn("updated"); col != nil {
+			hit.Updated, _ = col.GetAsInt64(i)
+		}
+		if col := sr.Fields.GetColumn("content"); col != nil {
+			hit.Content, _ = col.GetAsString(i)
+		}
+		if i < len(sr.Scores) {
+			hit.Score = sr.Scores[i]
+		}
+		hits = append(hits, hit)
+	}
+	return hits, nil
+}
+
+// formatProjectDocHits 把检索到的 issue/PR 整理为提示词中的参考资料
+func formatProjectDocHits(hits []ProjectDocHit) string {
+	var builder strings.Builder
+	for _, hit := range hits {
+		builder.WriteString(fmt.Sprintf("\n%s（%s）:\n%s\n", hit.Ref(), hit.URL, strings.TrimSpace(hit.Content)))
+	}
+	return builder.String()
+}
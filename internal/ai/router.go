@@ -0,0 +1,138 @@
+package ai
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Intent 问题意图，决定由静态分析工具还是代码检索（RAG）回答
+type Intent string
+
+const (
+	IntentMetric        Intent = "metric"        // 指标类问题（复杂度、函数数量），交给复杂度分析器
+	IntentSecurity      Intent = "security"      // 安全类问题（是否安全、有没有漏洞），交给安全扫描器
+	IntentUtility       Intent = "utility"       // 时间、找文件等，交给工具函数，不需要检索代码
+	IntentOwnership     Intent = "ownership"     // 代码归属（谁最后修改、什么时候改的），走 RAG 检索并依据片段的 git 信息回答
+	IntentHistory       Intent = "history"       // 改动历史（为什么改、什么时候改的），检索代码的同时检索提交历史
+	IntentComprehension Intent = "comprehension" // 代码理解，走 RAG 检索
+)
+
+// Route 问题的路由结果
+type Route struct {
+	Intent  Intent `json:"intent"`
+	Tool    string `json:"tool,omitempty"`    // 直接执行的工具，为空表示只走 RAG
+	Keyword string `json:"keyword,omitempty"` // 命中的关键词，便于排查路由是否合理
+}
+
+// intentRule 一类意图的关键词
+// noCode 为 true 时问题中出现代码相关的词就不命中："超时时间怎么配置" 问的是代码，不是现在几点
+type intentRule struct {
+	intent   Intent
+	tool     string
+	keywords []string
+	noCode   bool
+}
+
+// intentRules 按顺序匹配，先命中的生效：归属和历史问题的关键词最具体（"谁最后修改了时间解析" 不是问时间），
+// 其次是工具类问题，再其次是安全和指标；关键词都用小写，匹配前问题也转为小写
+// 英文关键词按单词边界匹配（"author" 不匹配 "authorization"），以 * 结尾的只要求开头是单词边界
+var intentRules = []intentRule{
+	{IntentOwnership, "", []string{"谁改", "谁修改", "谁最后", "谁最近", "谁写", "谁负责", "谁维护", "最后修改", "最近修改", "最后改动", "作者",
+		"who last", "who wrote", "who changed", "who modified", "who touched", "who owns", "who maintains", "last touched", "last modified", "last changed", "author", "authors"}, false},
+	{IntentHistory, "", []string{"为什么改", "为什么换", "为什么从", "为什么切换", "为什么要改", "什么时候改", "什么时候加", "什么时候引入", "哪次提交", "哪个提交",
+		"提交记录", "提交历史", "修改历史", "改动历史", "哪个 issue", "哪个 pr", "动机",
+		"why was", "why did", "why were", "when was", "when did", "which commit", "commit history", "which issue", "which pr", "motivat*", "switched", "histor*"}, false},
+	{IntentUtility, "get_current_time", []string{"几点", "时间", "日期", "几号", "星期几", "what time", "current time", "current date", "today"}, true},
+	{IntentUtility, "search_file", []string{"文件在哪", "在哪个文件", "找文件", "哪个目录", "where is the file", "find file", "locate file"}, false},
+	{IntentSecurity, "security_scanner", []string{"安全", "漏洞", "注入", "泄露", "硬编码密码", "is this safe", "is it safe", "secur*", "vulnerab*", "injection", "exploit*"}, false},
+	{IntentMetric, "complexity_analyzer", []string{"复杂度", "圈复杂度", "多少个函数", "多少行", "最复杂", "complexity", "cyclomatic", "how many functions", "metric*"}, false},
+}
+
+// ClassifyQuestion 按关键词判断问题意图
+// 只做轻量的关键词匹配，不调用模型：判断错了最多是多跑一个工具或少一段检索结果，
+// 不值得为此多一次模型请求。没有命中任何关键词时按代码理解处理
+func ClassifyQuestion(question string) Route {
+	q := strings.ToLower(question)
+	for _, rule := range intentRules {
+		if rule.noCode && matchKeyword(q, codeTerms) != "" {
+			continue
+		}
+		if kw := matchKeyword(q, rule.keywords); kw != "" {
+			return Route{Intent: rule.intent, Tool: rule.tool, Keyword: strings.TrimSuffix(kw, "*")}
+		}
+	}
+	return Route{Intent: IntentComprehension}
+}
+
+// codeTerms 说明问题与代码有关的词，出现时时间类关键词不再路由到工具，继续检索代码
+var codeTerms = []string{"函数", "方法", "代码", "配置", "解析", "格式化", "超时", "过期", "变量", "字段", "参数", "返回", "接口", "结构体",
+	"实现", "调用", "逻辑", "怎么", "如何", "哪里", "在哪", "为什么",
+	"func*", "method*", "code", "config*", "pars*", "format*", "timeout*", "expir*", "variable*", "field*", "param*",
+	"return*", "struct*", "interface*", "implement*", "call*", "how", "where", "why", ".go"}
+
+// matchKeyword 返回问题中第一个出现的关键词，都没有时返回空
+func matchKeyword(q string, keywords []string) string {
+	for _, kw := range keywords {
+		if containsKeyword(q, kw) {
+			return kw
+		}
+	}
+	return ""
+}
+
+// containsKeyword 问题是否包含关键词；英文关键词两端（以 * 结尾时只有开头）必须是单词边界，中文关键词直接按子串匹配
+func containsKeyword(q, kw string) bool {
+	prefix := strings.HasSuffix(kw, "*")
+	kw = strings.TrimSuffix(kw, "*")
+	if !isASCIIWord(kw) {
+		return strings.Contains(q, kw)
+	}
+	for i := 0; ; {
+		j := strings.Index(q[i:], kw)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(kw)
+		if !isWordByte(q, start-1) && (prefix || !isWordByte(q, end)) {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+// isASCIIWord 关键词是否以 ASCII 字母或数字开头（英文关键词）
+func isASCIIWord(kw string) bool {
+	return kw != "" && isWordByte(kw, 0)
+}
+
+// isWordByte 位置 i 上是否为英文单词字符，越界时不是
+func isWordByte(s string, i int) bool {
+	if i < 0 || i >= len(s) {
+		return false
+	}
+	c := s[i]
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// NeedsCode 回答该问题是否需要检索代码
+func (r Route) NeedsCode() bool {
+	return r.Intent != IntentUtility
+}
+
+// staticToolArguments 生成静态分析工具的 JSON 参数，工具不需要代码时返回空
+func staticToolArguments(tool, file string, code []byte) (string, bool) {
+	var args any
+	switch tool {
+	case "complexity_analyzer":
+		args = map[string]any{"input": string(code)}
+	case "security_scanner":
+		args = map[string]any{"code": string(code), "file": file}
+	default:
+		return "", false
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
@@ -0,0 +1,60 @@
+package ai
+
+import "testing"
+
+// 测试问题路由：时间类关键词出现在代码问题中时仍检索代码，英文关键词按单词边界匹配
+func TestClassifyQuestion(t *testing.T) {
+	cases := []struct {
+		question string
+		intent   Intent
+		tool     string
+	}{
+		{"现在几点了", IntentUtility, "get_current_time"},
+		{"今天的日期是多少", IntentUtility, "get_current_time"},
+		{"What time is it?", IntentUtility, "get_current_time"},
+		{"重试的超时时间怎么配置", IntentComprehension, ""},
+		{"日期解析函数在哪里", IntentComprehension, ""},
+		{"where is the date parsing done today", IntentComprehension, ""},
+		{"how does the authorization middleware work", IntentComprehension, ""},
+		{"which functions are authored by the cli package", IntentComprehension, ""},
+		{"who is the author of search.go", IntentOwnership, ""},
+		{"谁最后修改了时间解析", IntentOwnership, ""},
+		{"为什么改成按日期分片", IntentHistory, ""},
+		{"what motivated the switch to zstd", IntentHistory, ""},
+		{"is there a sql injection here", IntentSecurity, "security_scanner"},
+		{"any security issues in this file", IntentSecurity, "security_scanner"},
+		{"这个文件的圈复杂度是多少", IntentMetric, "complexity_analyzer"},
+		{"解释一下 packHits 的逻辑", IntentComprehension, ""},
+	}
+	for _, tc := range cases {
+		route := ClassifyQuestion(tc.question)
+		if route.Intent != tc.intent || route.Tool != tc.tool {
+			t.Errorf("ClassifyQuestion(%q) = %s/%s（关键词 %q），期望 %s/%s", tc.question, route.Intent, route.Tool, route.Keyword, tc.intent, tc.tool)
+		}
+		if route.NeedsCode() != (tc.intent != IntentUtility) {
+			t.Errorf("ClassifyQuestion(%q).NeedsCode() = %v", tc.question, route.NeedsCode())
+		}
+	}
+}
+
+// 测试关键词匹配的单词边界：* 结尾的关键词只要求开头是边界
+func TestContainsKeyword(t *testing.T) {
+	cases := []struct {
+		q, kw string
+		want  bool
+	}{
+		{"who is the author", "author", true},
+		{"authorization header", "author", false},
+		{"co-author list", "author", true},
+		{"unauthorized", "author*", false},
+		{"vulnerabilities found", "vulnerab*", true},
+		{"sparse arrays", "pars*", false},
+		{"test.go 里的超时", ".go", true},
+		{"重试的超时时间", "时间", true},
+	}
+	for _, tc := range cases {
+		if got := containsKeyword(tc.q, tc.kw); got != tc.want {
+			t.Errorf("containsKeyword(%q, %q) = %v，期望 %v", tc.q, tc.kw, got, tc.want)
+		}
+	}
+}
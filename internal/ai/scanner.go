@@ -7,16 +7,34 @@ import (
 )
 
 func ScanCode(rootPath string) ([]schema.Document, error) {
-	var docs []schema.Document
+	var paths []string
 	err := filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
 		if !info.IsDir() && filepath.Ext(path) == ".go" {
-			content, _ := os.ReadFile(path)
-			docs = append(docs, schema.Document{
-				PageContent: string(content),
-				Metadata:    map[string]any{"source": filepath.ToSlash(path)},
-			})
+			paths = append(paths, path)
 		}
 		return nil
 	})
-	return docs, err
+	if err != nil {
+		return nil, err
+	}
+	return LoadCode(paths), nil
+}
+
+// LoadCode 读取给定的源文件（通常来自构建系统的文件列表），读取失败的文件跳过
+func LoadCode(paths []string) []schema.Document {
+	var docs []schema.Document
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		docs = append(docs, schema.Document{
+			PageContent: NormalizeSource(string(content)),
+			Metadata:    map[string]any{"source": filepath.ToSlash(path)},
+		})
+	}
+	return docs
 }
@@ -0,0 +1,438 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+// SearchFilter 检索过滤条件，与语义相似度组合使用
+type SearchFilter struct {
+	Source        string   // 限定源文件
+	MinComplexity int      // 所在函数的最小圈复杂度
+	MinFindings   int      // 所在函数的最少分析问题数
+	Scopes        []string // 调用方的访问范围：公开片段总是可见，受限片段只在 ACL 标签属于其中时可见，"*" 表示全部
+	Dependencies  string   // 依赖源码：为空或 include 时一起检索，exclude 只检索项目代码，only 只检索依赖
+	Collection    string   // 检索的集合，为空使用 CodeCollection（go.work 成员模块见 CodeCollectionName）
+	DocRatio      float64  // 项目文档片段（README、docs/、ADR）最多占结果的比例（0-1），0 只检索代码
+}
+
+// Expr 生成 Milvus 过滤表达式，没有条件时返回空；每个条件各自加括号，任何一个条件都不能改变其他条件（尤其是 ACL）的含义
+func (f SearchFilter) Expr() string {
+	var conds []string
+	if f.Source != "" {
+		conds = append(conds, "source == "+quoteExprString(filepath.ToSlash(f.Source)))
+	}
+	if f.MinComplexity > 0 {
+		conds = append(conds, fmt.Sprintf("complexity >= %d", f.MinComplexity))
+	}
+	if f.MinFindings > 0 {
+		conds = append(conds, fmt.Sprintf("findings >= %d", f.MinFindings))
+	}
+	switch f.Dependencies {
+	case DependenciesExclude:
+		conds = append(conds, "dependency == false")
+	case DependenciesOnly:
+		conds = append(conds, "dependency == true")
+	}
+	if expr := f.aclExpr(); expr != "" {
+		conds = append(conds, expr)
+	}
+	for i, cond := range conds {
+		conds[i] = "(" + cond + ")"
+	}
+	return strings.Join(conds, " && ")
+}
+
+// quoteExprString 把字符串写成 Milvus 过滤表达式中的单引号字面量，转义反斜杠和单引号
+// 文件路径等外部输入必须经过转义，否则 ' 会截断字面量，拼出的表达式可以绕过 ACL 等其他条件
+func quoteExprString(s string) string {
+	return "'" + exprStringEscaper.Replace(s) + "'"
+}
+
+var exprStringEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+// SearchFilter.Dependencies 的取值
+const (
+	DependenciesInclude = "include"
+	DependenciesExclude = "exclude"
+	DependenciesOnly    = "only"
+)
+
+// aclExpr 按访问范围过滤受限片段
+func (f SearchFilter) aclExpr() string {
+	var tags []string
+	for _, scope := range f.Scopes {
+		if scope == "*" {
+			return ""
+		}
+		if scope != "" && !strings.ContainsAny(scope, `'"\`) {
+			tags = append(tags, fmt.Sprintf("'%s'", scope))
+		}
+	}
+	if len(tags) == 0 {
+		return "acl == ''"
+	}
+	return fmt.Sprintf("acl == '' || acl in [%s]", strings.Join(tags, ", "))
+}
+
+// CodeHit 一条检索结果
+type CodeHit struct {
+	Source     string  `json:"source"`
+	Content    string  `json:"content"`
+	Summary    string  `json:"summary,omitempty"`
+	ACL        string  `json:"acl,omitempty"`
+	Dependency bool    `json:"dependency,omitempty"`
+	Complexity int     `json:"complexity"`
+	Findings   int     `json:"findings"`
+	Line       int     `json:"line,omitempty"`     // 内容第一行在源文件中的行号，0 表示未知（旧索引或内容与源码不连续）
+	Author     string  `json:"author,omitempty"`   // 最后一次修改的 git 作者
+	Modified   int64   `json:"modified,omitempty"` // 最后一次修改的提交时间（Unix 秒），0 表示未知
+	DocType    string  `json:"doc_type,omitempty"` // 项目文档类型，代码片段为空
+	Section    string  `json:"section,omitempty"`  // 文档片段所属的章节
+	Score      float32 `json:"score"`
+	omitted    int     // 按上下文预算截断时省略的末尾行数
+}
+
+// Location 片段的位置：source:line，行号未知时只有文件路径
+func (h CodeHit) Location() string {
+	if h.Line <= 0 {
+		return h.Source
+	}
+	return fmt.Sprintf("%s:%d", h.Source, h.Line)
+}
+
+// LastModified 最后一次修改的作者和日期，如 "alice，2024-05-01"；没有 git 信息时返回空
+func (h CodeHit) LastModified() string {
+	if h.Modified <= 0 {
+		return ""
+	}
+	date := time.Unix(h.Modified, 0).UTC().Format(time.DateOnly)
+	if h.Author == "" {
+		return date
+	}
+	return h.Author + "，" + date
+}
+
+// NumberedContent 每行前加上源文件中的行号，便于引用到具体的行；行号未知时原样返回
+func (h CodeHit) NumberedContent() string {
+	if h.Line <= 0 {
+		return h.Content
+	}
+	lines := strings.Split(h.Content, "\n")
+	width := len(strconv.Itoa(h.Line + len(lines) - 1))
+	for i, line := range lines {
+		lines[i] = fmt.Sprintf("%*d| %s", width, h.Line+i, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SearchCode 语义检索代码片段，并按过滤条件筛选标量字段
+// 同时检索代码向量和摘要向量，按片段合并并保留较高的相似度
+// filter.DocRatio 大于 0 时另外检索文档片段，按比例与代码片段混合（见 blendHits）
+func SearchCode(ctx context.Context, mc client.Client, e embeddings.Embedder, query string, filter SearchFilter, topK int) ([]CodeHit, error) {
+	queryVec, err := e.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("向量化失败: %w", err)
+	}
+
+	hits, err := searchHits(ctx, mc, queryVec, filter, "doc_type == ''", topK)
+	if err != nil || filter.DocRatio <= 0 {
+		return hits, err
+	}
+	docHits, err := searchHits(ctx, mc, queryVec, filter, "doc_type != ''", topK)
+	if err != nil {
+		return nil, err
+	}
+	return blendHits(hits, docHits, topK, filter.DocRatio), nil
+}
+
+// searchHits 在两个向量字段上检索满足过滤条件和 kindExpr（代码或文档）的片段，按相似度取前 topK 个
+func searchHits(ctx context.Context, mc client.Client, queryVec []float32, filter SearchFilter, kindExpr string, topK int) ([]CodeHit, error) {
+	expr := kindExpr
+	if filterExpr := filter.Expr(); filterExpr != "" {
+		expr = filterExpr + " && (" + kindExpr + ")"
+	}
+	best := make(map[int64]CodeHit)
+	for _, field := range codeVectorFields {
+		hits, err := searchVectorField(ctx, mc, queryVec, field, filter.Collection, expr, topK)
+		if err != nil {
+			return nil, err
+		}
+		for id, hit := range hits {
+			if prev, ok := best[id]; !ok || hit.Score > prev.Score {
+				best[id] = hit
+			}
+		}
+	}
+
+	hits := make([]CodeHit, 0, len(best))
+	for _, hit := range best {
+		hits = append(hits, hit)
+	}
+	sortByScore(hits)
+	if len(hits) > topK {
+		hits = hits[:topK]
+	}
+	return hits, nil
+}
+
+// blendHits 按比例混合代码和文档片段：文档最多占 topK 的 ratio（四舍五入），任一方不足时由另一方补齐
+// 混合后按相似度排序
+func blendHits(code, docs []CodeHit, topK int, ratio float64) []CodeHit {
+	docCount := min(len(docs), int(math.Round(float64(topK)*min(ratio, 1))))
+	codeCount := min(len(code), topK-docCount)
+	docCount = min(len(docs), topK-codeCount)
+	hits := append(code[:codeCount:codeCount], docs[:docCount]...)
+	sortByScore(hits)
+	return hits
+}
+
+// sortByScore 按相似度从高到低排序，相同时按文件路径
+func sortByScore(hits []CodeHit) {
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Source < hits[j].Source
+	})
+}
+
+// searchVectorField 在单个向量字段上检索，返回主键到结果的映射
+func searchVectorField(ctx context.Context, mc client.Client, queryVec []float32, field, collection, expr string, topK int) (map[int64]CodeHit, error) {
+	searchParam, _ := entity.NewIndexHNSWSearchParam(64)
+	if collection == "" {
+		collection = CodeCollection
+	}
+	res, err := mc.Search(ctx, collection, []string{}, expr,
+		[]string{"content", "source", "summary", "acl", "dependency", "complexity", "findings", "line", "author", "modified", "doc_type", "section"}, []entity.Vector{entity.FloatVector(queryVec)},
+		field, entity.COSINE, topK, searchParam)
+	if err != nil {
+		return nil, fmt.Errorf("Milvus 搜索 %s 失败: %w", field, err)
+	}
+
+	hits := make(map[int64]CodeHit)
+	if len(res) == 0 {
+		return hits, nil
+	}
+	sr := res[0]
+	for i := 0; i < sr.IDs.Len(); i++ {
+		id, err := sr.IDs.GetAsInt64(i)
+		if err != nil {
+			continue
+		}
+		hit := CodeHit{}
+		if col := sr.Fields.GetColumn("content"); col != nil {
+			hit.Content, _ = col.GetAsString(i)
+		}
+		if col := sr.Fields.GetColumn("source"); col != nil {
+			hit.Source, _ = col.GetAsString(i)
+		}
+		if col := sr.Fields.GetColumn("summary"); col != nil {
+			hit.Summary, _ = col.GetAsString(i)
+		}
+		if col := sr.Fields.GetColumn("acl"); col != nil {
+			hit.ACL, _ = col.GetAsString(i)
+		}
+		if col, ok := sr.Fields.GetColumn("dependency").(*entity.ColumnBool); ok && i < col.Len() {
+			hit.Dependency = col.Data()[i]
+		}
+		if col := sr.Fields.GetColumn("complexity"); col != nil {
+			v, _ := col.GetAsInt64(i)
+			hit.Complexity = int(v)
+		}
+		if col := sr.Fields.GetColumn("findings"); col != nil {
+			v, _ := col.GetAsInt64(i)
+			hit.Findings = int(v)
+		}
+		if col := sr.Fields.GetColumn("line"); col != nil {
+			v, _ := col.GetAsInt64(i)
+			hit.Line = int(v)
+		}
+		if col := sr.Fields.GetColumn("author"); col != nil {
+			hit.Author, _ = col.GetAsString(i)
+		}
+		if col := sr.Fields.GetColumn("modified"); col != nil {
+			hit.Modified, _ = col.GetAsInt64(i)
+		}
+		if col := sr.Fields.GetColumn("doc_type"); col != nil {
+			hit.DocType, _ = col.GetAsString(i)
+		}
+		if col := sr.Fields.GetColumn("section"); col != nil {
+			hit.Section, _ = col.GetAsString(i)
+		}
+		if i < len(sr.Scores) {
+			hit.Score = sr.Scores[i]
+		}
+		hits[id] = hit
+	}
+	return hits, nil
+}
+
+// SortByRisk 按风险排序：问题数多的在前，其次是复杂度，最后是相似度
+func SortByRisk(hits []CodeHit) {
+	sort.SliceStable(hits, func(i, j int) bool {
+		a, b := hits[i], hits[j]
+		if a.Findings != b.Findings {
+			return a.Findings > b.Findings
+		}
+		if a.Complexity != b.Complexity {
+			return a.Complexity > b.Complexity
+		}
+		return a.Score > b.Score
+	})
+}
+
+// formatHits 把检索结果拼成提示词中的参考代码：重叠的片段先合并，再按 token 预算（budget <= 0 时为 DefaultContextBudget）取舍和截断
+// 已知行号的片段每行带上行号，回答时可以引用 文件:行号；文档片段标明类型和所属章节
+func formatHits(hits []CodeHit, budget int) string {
+	hits = packHits(mergeOverlappingHits(hits), budget)
+	var builder strings.Builder
+	for i, hit := range hits {
+		switch {
+		case hit.DocType != "":
+			builder.WriteString(fmt.Sprintf("\n文档片段 %d:\n", i+1))
+			builder.WriteString(fmt.Sprintf("来源: %s（%s）\n", hit.Location(), hit.DocType))
+			if hit.Section != "" {
+				builder.WriteString(fmt.Sprintf("章节: %s\n", hit.Section))
+			}
+		case hit.Dependency:
+			builder.WriteString(fmt.Sprintf("\n代码片段 %d:\n", i+1))
+			builder.WriteString(fmt.Sprintf("来源: %s（依赖模块源码）\n", hit.Location()))
+		default:
+			builder.WriteString(fmt.Sprintf("\n代码片段 %d:\n", i+1))
+			if hit.Source != "" {
+				builder.WriteString(fmt.Sprintf("来源: %s\n", hit.Location()))
+			}
+		}
+		if hit.Summary != "" {
+			builder.WriteString(fmt.Sprintf("摘要: %s\n", hit.Summary))
+		}
+		if modified := hit.LastModified(); modified != "" {
+			builder.WriteString(fmt.Sprintf("最后修改（git）: %s\n", modified))
+		}
+		builder.WriteString(hit.NumberedContent() + "\n")
+		if hit.omitted > 0 {
+			builder.WriteString(omittedMarker(hit.omitted) + "\n")
+		}
+	}
+	return builder.String()
+}
+
+// 片段重叠的判定：重叠行数占较短片段的比例达到 hitOverlapRatio，或首尾相接重叠了至少 hitAdjacentLines 行
+// addContext 会把函数之后的相邻代码一起放进片段，同一文件的相邻函数经常被检索出几乎相同的内容
+const (
+	hitOverlapRatio  = 0.5
+	hitAdjacentLines = 5
+)
+
+// mergeOverlappingHits 合并同一文件中内容重叠的检索结果：包含关系保留较长的片段，首尾重叠的片段拼接成连续的一段
+// 合并后的结果位于原先靠前的位置，相似度、复杂度和问题数取较大值
+func mergeOverlappingHits(hits []CodeHit) []CodeHit {
+	merged := make([]CodeHit, 0, len(hits))
+	for _, hit := range hits {
+		merged = append(merged, hit)
+		// 新结果与前面的结果合并后，合并出的片段可能又与其他结果重叠，继续合并直到不再变化
+		for cur := len(merged) - 1; ; {
+			other := -1
+			var content string
+			var line int
+			for i := range merged {
+				if i == cur || merged[i].Source != merged[cur].Source || merged[i].Dependency != merged[cur].Dependency {
+					continue
+				}
+				if c, l, ok := mergeOverlap(merged[i], merged[cur]); ok {
+					other, content, line = i, c, l
+					break
+				}
+			}
+			if other < 0 {
+				break
+			}
+			keep, drop := min(cur, other), max(cur, other)
+			merged[keep] = combineHits(merged[keep], merged[drop], content, line)
+			merged = append(merged[:drop], merged[drop+1:]...)
+			cur = keep
+		}
+	}
+	return merged
+}
+
+// combineHits 用合并后的内容和起始行号替换 a 的，相似度、复杂度和问题数取较大值，最后修改取较新的一次
+func combineHits(a, b CodeHit, content string, line int) CodeHit {
+	a.Content, a.Line = content, line
+	if b.Modified > a.Modified {
+		a.Author, a.Modified = b.Author, b.Modified
+	}
+	a.Score = max(a.Score, b.Score)
+	a.Complexity = max(a.Complexity, b.Complexity)
+	a.Findings = max(a.Findings, b.Findings)
+	if a.Summary == "" {
+		a.Summary = b.Summary
+	}
+	return a
+}
+
+// mergeOverlap 两段代码重叠足够多时返回合并后的内容及其起始行号：一段包含另一段时取较长的一段，否则把首尾重叠的两段拼接起来
+func mergeOverlap(a, b CodeHit) (string, int, bool) {
+	la, lb := strings.Split(a.Content, "\n"), strings.Split(b.Content, "\n")
+	if len(la) < len(lb) {
+		a, b, la, lb = b, a, lb, la
+	}
+	if containsLines(la, lb) {
+		return a.Content, a.Line, true
+	}
+	best, merged, line := 0, "", 0
+	if k := suffixPrefixOverlap(la, lb); k > best {
+		best, merged, line = k, strings.Join(append(la[:len(la):len(la)], lb[k:]...), "\n"), a.Line
+	}
+	if k := suffixPrefixOverlap(lb, la); k > best {
+		best, merged, line = k, strings.Join(append(lb[:len(lb):len(lb)], la[k:]...), "\n"), b.Line
+	}
+	if best == 0 || (float64(best)/float64(len(lb)) < hitOverlapRatio && best < hitAdjacentLines) {
+		return "", 0, false
+	}
+	return merged, line, true
+}
+
+// containsLines b 的所有行是否连续出现在 a 中
+func containsLines(a, b []string) bool {
+	for i := 0; i+len(b) <= len(a); i++ {
+		if linesEqual(a[i:i+len(b)], b) {
+			return true
+		}
+	}
+	return false
+}
+
+// suffixPrefixOverlap a 的结尾与 b 的开头相同的最大行数
+func suffixPrefixOverlap(a, b []string) int {
+	for k := min(len(a), len(b)); k > 0; k-- {
+		if linesEqual(a[len(a)-k:], b[:k]) {
+			return k
+		}
+	}
+	return 0
+}
+
+// linesEqual 逐行比较，忽略行尾空白
+func linesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if strings.TrimRight(a[i], " \t") != strings.TrimRight(b[i], " \t") {
+			return false
+		}
+	}
+	return true
+}
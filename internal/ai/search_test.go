@@ -0,0 +1,250 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// 测试过滤表达式中字符串字面量的转义：单引号和反斜杠不能截断字面量
+func TestQuoteExprString(t *testing.T) {
+	cases := map[string]string{
+		"internal/ai/search.go": `'internal/ai/search.go'`,
+		"it's.go":               `'it\'s.go'`,
+		`dir\file.go`:           `'dir\\file.go'`,
+		`x\' || true || '`:      `'x\\\' || true || \''`,
+		"":                      `''`,
+	}
+	for in, want := range cases {
+		if got := quoteExprString(in); got != want {
+			t.Errorf("quoteExprString(%q) = %s，期望 %s", in, got, want)
+		}
+	}
+}
+
+// 测试构造的文件路径不能扩大 ACL 范围：表达式的顶层只能是各自加了括号、用 && 连接的条件，ACL 条件原样保留
+func TestSearchFilterExprKeepsACL(t *testing.T) {
+	acl := "(acl == '' || acl in ['team-a'])"
+	for _, source := range []string{
+		"internal/ai/search.go",
+		`x' || true || '`,
+		`x\' || true || \'`,
+		`x') || (true`,
+		`a\`,
+	} {
+		filter := SearchFilter{Source: source, MinFindings: 1, Scopes: []string{"team-a"}}
+		conds, err := topLevelConds(filter.Expr())
+		if err != nil {
+			t.Errorf("Source=%q: %v（表达式 %s）", source, err, filter.Expr())
+			continue
+		}
+		want := []string{"(source == " + quoteExprString(source) + ")", "(findings >= 1)", acl}
+		if len(conds) != len(want) {
+			t.Errorf("Source=%q: 顶层条件 %q，期望 %q", source, conds, want)
+			continue
+		}
+		for i := range want {
+			if conds[i] != want[i] {
+				t.Errorf("Source=%q: 第 %d 个条件 %s，期望 %s", source, i+1, conds[i], want[i])
+			}
+		}
+	}
+
+	if got := (SearchFilter{}).Expr(); got != "(acl == '')" {
+		t.Errorf("没有访问范围时只能看到公开片段，实际 %s", got)
+	}
+	if got := (SearchFilter{Scopes: []string{"*"}}).Expr(); got != "" {
+		t.Errorf("* 表示全部可见，实际 %s", got)
+	}
+}
+
+// topLevelConds 按 Milvus 的字符串字面量规则（单引号、反斜杠转义）扫描表达式，拆出括号外用 && 连接的条件
+// 括号外出现其他运算符、括号或引号不配对时返回错误
+func topLevelConds(expr string) ([]string, error) {
+	var conds []string
+	depth, start := 0, 0
+	inString := false
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		if inString {
+			switch c {
+			case '\\':
+				i++
+			case '\'':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '\'':
+			inString = true
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("第 %d 个字符的右括号不配对", i+1)
+			}
+		default:
+			if depth > 0 {
+				continue
+			}
+			if strings.HasPrefix(expr[i:], " && ") {
+				conds = append(conds, expr[start:i])
+				i += len(" && ") - 1
+				start = i + 1
+				continue
+			}
+			if c != ' ' {
+				return nil, fmt.Errorf("第 %d 个字符 %q 在括号外", i+1, c)
+			}
+		}
+	}
+	if inString || depth != 0 {
+		return nil, fmt.Errorf("引号或括号不配对")
+	}
+	return append(conds, expr[start:]), nil
+}
+
+// 测试文档片段按比例混入检索结果：配额四舍五入，一方不足时由另一方补齐，结果按相似度排序
+func TestBlendHits(t *testing.T) {
+	hits := func(prefix string, scores ...float32) []CodeHit {
+		var list []CodeHit
+		for i, score := range scores {
+			list = append(list, CodeHit{Source: fmt.Sprintf("%s%d", prefix, i+1), Score: score})
+		}
+		return list
+	}
+	code := hits("code", 0.9, 0.8, 0.7, 0.6, 0.5, 0.4)
+	docs := hits("doc", 0.95, 0.85, 0.75, 0.65)
+	cases := []struct {
+		name  string
+		code  []CodeHit
+		docs  []CodeHit
+		topK  int
+		ratio float64
+		want  []string
+	}{
+		{"topK 4 比例 0.25 取 1 个文档", code, docs, 4, 0.25, []string{"doc1", "code1", "code2", "code3"}},
+		{"1.25 向下取整为 1", code, docs, 5, 0.25, []string{"doc1", "code1", "code2", "code3", "code4"}},
+		{"1.5 向上取整为 2", code, docs, 6, 0.25, []string{"doc1", "code1", "doc2", "code2", "code3", "code4"}},
+		{"比例过小取 0 个文档", code, docs, 4, 0.1, []string{"code1", "code2", "code3", "code4"}},
+		{"比例超过 1 按 1 处理", code, docs, 3, 1.5, []string{"doc1", "doc2", "doc3"}},
+		{"文档不足时代码补齐", code, docs[:1], 4, 0.5, []string{"doc1", "code1", "code2", "code3"}},
+		{"代码不足时文档补齐", code[:1], docs, 4, 0.25, []string{"doc1", "code1", "doc2", "doc3"}},
+		{"总数不足 topK", code[:1], docs[:1], 5, 0.5, []string{"doc1", "code1"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, hit := range blendHits(tc.code, tc.docs, tc.topK, tc.ratio) {
+				got = append(got, hit.Source)
+			}
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("结果 = %v，期望 %v", got, tc.want)
+			}
+		})
+	}
+	if len(code) != 6 || code[5].Source != "code6" {
+		t.Error("混合结果不应修改传入的代码片段列表")
+	}
+}
+
+// codeLines 生成第 from 到 to 行的内容（每行为 "line N"），用于构造重叠的片段
+func codeLines(from, to int) string {
+	var lines []string
+	for i := from; i <= to; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// 测试两个片段的合并：包含关系取较长的一段，首尾重叠按比例或相邻行数判定，合并后保留靠前片段的起始行号
+func TestMergeOverlap(t *testing.T) {
+	hit := func(from, to int) CodeHit {
+		return CodeHit{Source: "a.go", Content: codeLines(from, to), Line: from}
+	}
+	cases := []struct {
+		name     string
+		a, b     CodeHit
+		ok       bool
+		from, to int // 合并后的行范围
+	}{
+		{"包含", hit(1, 10), hit(3, 5), true, 1, 10},
+		{"被包含", hit(3, 5), hit(1, 10), true, 1, 10},
+		{"重叠一半", hit(1, 4), hit(3, 6), true, 1, 6},
+		{"重叠一半（顺序相反）", hit(3, 6), hit(1, 4), true, 1, 6},
+		{"相邻重叠 5 行", hit(1, 20), hit(16, 30), true, 1, 30},
+		{"相邻重叠 5 行（顺序相反）", hit(16, 30), hit(1, 20), true, 1, 30},
+		{"重叠不足", hit(1, 20), hit(17, 31), false, 0, 0},
+		{"相接但不重叠", hit(1, 4), hit(5, 8), false, 0, 0},
+		{"没有重叠", hit(1, 4), hit(10, 12), false, 0, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			content, line, ok := mergeOverlap(tc.a, tc.b)
+			if ok != tc.ok {
+				t.Fatalf("ok = %v，期望 %v", ok, tc.ok)
+			}
+			if !ok {
+				return
+			}
+			if line != tc.from || content != codeLines(tc.from, tc.to) {
+				t.Errorf("合并结果从第 %d 行开始:\n%s\n期望第 %d-%d 行", line, content, tc.from, tc.to)
+			}
+		})
+	}
+}
+
+// 测试合并检索结果：只合并同一文件（且同为项目代码或依赖）的片段，合并可以传递，结果留在靠前的位置
+func TestMergeOverlappingHits(t *testing.T) {
+	hit := func(source string, from, to int, score float32) CodeHit {
+		return CodeHit{Source: source, Content: codeLines(from, to), Line: from, Score: score, Complexity: from}
+	}
+	dep := hit("a.go", 1, 4, 0.9)
+	dep.Dependency = true
+
+	cases := []struct {
+		name string
+		hits []CodeHit
+		want []string // 每个结果的 source:line-末行
+	}{
+		{
+			name: "不同文件不合并",
+			hits: []CodeHit{hit("a.go", 1, 4, 0.9), hit("b.go", 1, 4, 0.8)},
+			want: []string{"a.go:1-4", "b.go:1-4"},
+		},
+		{
+			name: "依赖与项目代码不合并",
+			hits: []CodeHit{dep, hit("a.go", 1, 4, 0.8)},
+			want: []string{"a.go:1-4", "a.go:1-4"},
+		},
+		{
+			name: "后面的片段并入前面的位置",
+			hits: []CodeHit{hit("b.go", 1, 4, 0.9), hit("a.go", 5, 8, 0.8), hit("a.go", 1, 6, 0.7)},
+			want: []string{"b.go:1-4", "a.go:1-8"},
+		},
+		{
+			name: "传递合并",
+			hits: []CodeHit{hit("a.go", 1, 4, 0.7), hit("a.go", 7, 10, 0.9), hit("a.go", 3, 8, 0.8)},
+			want: []string{"a.go:1-10"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, h := range mergeOverlappingHits(tc.hits) {
+				last := strings.TrimPrefix(h.Content[strings.LastIndex(h.Content, "\n")+1:], "line ")
+				got = append(got, fmt.Sprintf("%s:%d-%s", h.Source, h.Line, last))
+			}
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("结果 = %v，期望 %v", got, tc.want)
+			}
+		})
+	}
+
+	merged := mergeOverlappingHits([]CodeHit{hit("a.go", 1, 4, 0.7), hit("a.go", 3, 6, 0.9)})
+	if len(merged) != 1 || merged[0].Score != 0.9 || merged[0].Complexity != 3 {
+		t.Errorf("合并后相似度和复杂度应取较大值: %+v", merged)
+	}
+}
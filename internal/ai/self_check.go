@@ -0,0 +1,108 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// 自检给出的置信度
+const (
+	ConfidenceHigh   = "high"
+	ConfidenceMedium = "medium"
+	ConfidenceLow    = "low"
+)
+
+// SelfCheckResult 回答自检结果
+type SelfCheckResult struct {
+	Confidence  string   `json:"confidence"`  // high、medium、low
+	Unsupported []string `json:"unsupported"` // 在检索到的代码中找不到依据的说法
+}
+
+// CheckAnswer 让模型对照检索到的代码逐条核对回答中的说法
+// 只判断"代码片段能否支持该说法"，不判断说法本身对不对：片段之外的事实（如标准库行为）也会被标出，
+// 由读者决定是否采信。主要用来拦住没有依据、却说得很肯定的架构描述
+func (e *SourceInsightEngine) CheckAnswer(ctx context.Context, question, answer, snippets string) (*SelfCheckResult, error) {
+	prompt := fmt.Sprintf(`你是一名严谨的代码审查员。下面是一个关于代码的问题、检索到的代码片段，以及另一位助手的回答。
+请逐条检查回答中关于代码的具体说法（调用关系、数据流向、模块职责、行为细节），找出在代码片段中找不到依据的说法。
+只输出 JSON，不要输出其他内容，格式：
+{"confidence": "high|medium|low", "unsupported": ["没有依据的说法（原文或简述）", ...]}
+全部有依据时 unsupported 为空数组、confidence 为 high；有个别说法没有依据时为 medium；主要结论没有依据时为 low。
+
+【问题】：
+%s
+
+【代码片段】：
+%s
+
+【回答】：
+%s`, question, snippets, answer)
+
+	resp, err := e.ChatModel.GenerateContent(ctx, []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, prompt),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("AI 请求失败: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("AI 响应中没有选择项")
+	}
+	return parseSelfCheck(resp.Choices[0].Content)
+}
+
+// parseSelfCheck 解析模型返回的自检 JSON（可能包在代码块或说明文字中）
+func parseSelfCheck(content string) (*SelfCheckResult, error) {
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start == -1 || end < start {
+		return nil, fmt.Errorf("自检结果不是 JSON: %s", content)
+	}
+	var result SelfCheckResult
+	if err := json.Unmarshal([]byte(content[start:end+1]), &result); err != nil {
+		return nil, fmt.Errorf("解析自检结果失败: %w", err)
+	}
+	result.Confidence = strings.ToLower(strings.TrimSpace(result.Confidence))
+	switch result.Confidence {
+	case ConfidenceHigh, ConfidenceMedium, ConfidenceLow:
+	default:
+		// 模型没有按要求给出置信度时按有无问题推断
+		result.Confidence = ConfidenceHigh
+		if len(result.Unsupported) > 0 {
+			result.Confidence = ConfidenceMedium
+		}
+	}
+	return &result, nil
+}
+
+// Note 附加在回答后面的置信度说明
+func (r *SelfCheckResult) Note() string {
+	labels := map[string]string{ConfidenceHigh: "高", ConfidenceMedium: "中", ConfidenceLow: "低"}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔎 自检：置信度 %s", labels[r.Confidence]))
+	if len(r.Unsupported) == 0 {
+		sb.WriteString("，回答中的说法都能在检索到的代码中找到依据")
+		return sb.String()
+	}
+	sb.WriteString("，以下说法在检索到的代码中找不到依据，请自行核实：")
+	for _, claim := range r.Unsupported {
+		sb.WriteString("\n  - " + claim)
+	}
+	return sb.String()
+}
+
+// appendSelfCheck 开启自检时核对回答并附加置信度说明；没有检索到代码或自检失败时原样返回
+func (e *SourceInsightEngine) appendSelfCheck(ctx context.Context, question, answer, snippets string) string {
+	if !e.SelfCheck || strings.TrimSpace(snippets) == "" {
+		return answer
+	}
+	result, err := e.CheckAnswer(ctx, question, answer, snippets)
+	if err != nil {
+		e.logger.Warn("回答自检失败", "error", err)
+		return answer
+	}
+	e.logger.Info("回答自检完成", "confidence", result.Confidence, "unsupported", len(result.Unsupported))
+	return answer + "\n\n" + result.Note()
+}
@@ -0,0 +1,86 @@
+package ai
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// fakeChatModel 返回固定内容的对话模型，记录收到的提示词
+type fakeChatModel struct {
+	reply   string
+	prompts []string
+}
+
+func (m *fakeChatModel) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	for _, msg := range messages {
+		for _, part := range msg.Parts {
+			if text, ok := part.(llms.TextContent); ok {
+				m.prompts = append(m.prompts, text.Text)
+			}
+		}
+	}
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: m.reply}}}, nil
+}
+
+func (m *fakeChatModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+// 测试自检：模型标出没有依据的说法时，置信度说明逐条列出这些说法
+func TestAppendSelfCheck(t *testing.T) {
+	model := &fakeChatModel{reply: "核对结果：\n```json\n{\"confidence\": \"Low\", \"unsupported\": [\"SearchCode 会缓存检索结果\"]}\n```"}
+	engine := NewEngine(nil, nil, model, NewLogger(slog.LevelError))
+	engine.SelfCheck = true
+
+	got := engine.appendSelfCheck(context.Background(), "SearchCode 做了什么", "它会缓存检索结果。", "func SearchCode() {}")
+	if !strings.HasPrefix(got, "它会缓存检索结果。\n\n🔎 自检：置信度 低") || !strings.Contains(got, "\n  - SearchCode 会缓存检索结果") {
+		t.Errorf("没有依据的说法应该列在回答后面:\n%s", got)
+	}
+	if len(model.prompts) != 1 || !strings.Contains(model.prompts[0], "func SearchCode() {}") {
+		t.Errorf("自检的提示词应该包含检索到的代码: %q", model.prompts)
+	}
+
+	// 没有检索到代码或关闭自检时不请求模型，回答原样返回
+	model.prompts = nil
+	if got := engine.appendSelfCheck(context.Background(), "q", "answer", " \n"); got != "answer" || len(model.prompts) != 0 {
+		t.Errorf("没有代码片段时不应自检: %q %d", got, len(model.prompts))
+	}
+	engine.SelfCheck = false
+	if got := engine.appendSelfCheck(context.Background(), "q", "answer", "code"); got != "answer" || len(model.prompts) != 0 {
+		t.Errorf("关闭自检时不应自检: %q %d", got, len(model.prompts))
+	}
+
+	// 自检结果无法解析时原样返回回答
+	engine.SelfCheck = true
+	model.reply = "无法判断"
+	if got := engine.appendSelfCheck(context.Background(), "q", "answer", "code"); got != "answer" {
+		t.Errorf("自检失败时应原样返回回答: %q", got)
+	}
+}
+
+// 测试解析自检结果：置信度缺失或无效时按有无问题推断
+func TestParseSelfCheck(t *testing.T) {
+	cases := []struct {
+		content     string
+		confidence  string
+		unsupported int
+	}{
+		{`{"confidence": "high", "unsupported": []}`, ConfidenceHigh, 0},
+		{`{"confidence": " MEDIUM ", "unsupported": ["a"]}`, ConfidenceMedium, 1},
+		{`{"unsupported": ["a", "b"]}`, ConfidenceMedium, 2},
+		{`{"confidence": "unsure"}`, ConfidenceHigh, 0},
+	}
+	for _, tc := range cases {
+		result, err := parseSelfCheck(tc.content)
+		if err != nil || result.Confidence != tc.confidence || len(result.Unsupported) != tc.unsupported {
+			t.Errorf("parseSelfCheck(%s) = %+v %v，期望 %s/%d", tc.content, result, err, tc.confidence, tc.unsupported)
+		}
+	}
+	if _, err := parseSelfCheck("没有 JSON"); err == nil {
+		t.Error("不是 JSON 时应返回错误")
+	}
+}
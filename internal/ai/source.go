@@ -0,0 +1,18 @@
+package ai
+
+import "strings"
+
+// utf8BOM UTF-8 字节序标记，Windows 上的一些编辑器保存时会加在文件开头
+const utf8BOM = "\uFEFF"
+
+// NormalizeSource 统一源码的编码和换行：去掉开头的 UTF-8 BOM，把 CRLF 和单独的 CR 换成 LF
+// Windows 上编辑的文件读入后先经过它，按 "\n" 切分出的行才不会带着 \r，
+// 第一行的列号也不会因为 BOM 偏移三个字节
+func NormalizeSource(src string) string {
+	src = strings.TrimPrefix(src, utf8BOM)
+	if !strings.Contains(src, "\r") {
+		return src
+	}
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	return strings.ReplaceAll(src, "\r", "\n")
+}
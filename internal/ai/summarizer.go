@@ -0,0 +1,74 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// summaryMaxRunes 摘要最大长度（与 summary 字段的 MaxLength 对应，按字符截断）
+const summaryMaxRunes = 500
+
+// SummarizeChunks 为每个代码片段生成一句自然语言摘要，写入片段元数据 MetaSummary
+// 摘要与原始代码分别向量化入库，自然语言提问即使与标识符没有共同词也能检索到
+// 单个片段生成失败只跳过该片段，返回成功生成的数量
+func SummarizeChunks(ctx context.Context, model llms.Model, chunks []schema.Document, logger *Logger) (int, error) {
+	if model == nil {
+		return 0, fmt.Errorf("未配置对话模型，无法生成摘要")
+	}
+
+	count := 0
+	for i := range chunks {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+		summary, err := summarizeChunk(ctx, model, chunks[i])
+		if err != nil {
+			if logger != nil {
+				source, _ := chunks[i].Metadata[MetaSource].(string)
+				logger.Warn("生成摘要失败", "source", source, "error", err)
+			}
+			continue
+		}
+		if chunks[i].Metadata == nil {
+			chunks[i].Metadata = map[string]any{}
+		}
+		chunks[i].Metadata[MetaSummary] = summary
+		count++
+	}
+	return count, nil
+}
+
+// summarizeChunk 调用模型为单个片段生成摘要
+func summarizeChunk(ctx context.Context, model llms.Model, chunk schema.Document) (string, error) {
+	function, _ := chunk.Metadata[MetaFunction].(string)
+	if function == "" {
+		function = "（无）"
+	}
+	prompt := fmt.Sprintf(`用一到两句中文概括下面这段 Go 代码做了什么（功能和用途，而不是逐行复述），不要输出代码或 Markdown。
+
+所在函数: %s
+代码:
+%s`, function, chunk.PageContent)
+
+	resp, err := model.GenerateContent(ctx, []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, prompt),
+	})
+	if err != nil {
+		return "", fmt.Errorf("AI 请求失败: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("AI 响应中没有选择项")
+	}
+	summary := strings.TrimSpace(resp.Choices[0].Content)
+	if summary == "" {
+		return "", fmt.Errorf("AI 返回了空摘要")
+	}
+	if runes := []rune(summary); len(runes) > summaryMaxRunes {
+		summary = string(runes[:summaryMaxRunes])
+	}
+	return summary, nil
+}
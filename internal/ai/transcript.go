@@ -0,0 +1,217 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// 会话记录的事件类型
+const (
+	TranscriptPrompt    = "prompt"    // 发给模型的完整消息
+	TranscriptResponse  = "response"  // 模型的回答（含工具调用）
+	TranscriptRetrieval = "retrieval" // 检索到的代码片段
+	TranscriptToolCall  = "tool_call" // 工具调用的参数和结果
+	TranscriptRoute     = "route"     // 问题路由结果
+	TranscriptError     = "error"     // 模型请求失败
+)
+
+// TranscriptEntry 会话记录中的一条事件
+type TranscriptEntry struct {
+	Time    time.Time `json:"time"`
+	Session string    `json:"session"`
+	Kind    string    `json:"kind"`
+	Name    string    `json:"name,omitempty"` // 工具名等
+	Content string    `json:"content"`        // 已脱敏的内容
+}
+
+// Transcript 调试用的会话记录：完整的提示词、检索结果、工具调用和回答，每个会话一个 JSONL 文件
+// 内容写入前经过 RedactSecrets 脱敏，但仍然包含源码片段，只应在排查问题时开启
+type Transcript struct {
+	mu      sync.Mutex
+	file    *os.File
+	path    string
+	session string
+}
+
+// defaultTranscript 进程级的会话记录，由 StartTranscript 设置
+var (
+	defaultTranscriptMu sync.Mutex
+	defaultTranscript   *Transcript
+)
+
+// StartTranscript 在 dir 下创建本次会话的记录文件，并设为进程级的会话记录
+// 之后 NewChatModel 创建的模型和引擎的检索、工具调用都会写入该文件
+func StartTranscript(dir string) (*Transcript, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("创建提示词日志目录失败: %w", err)
+	}
+	session := fmt.Sprintf("%s-%d", time.Now().Format("20060102-150405"), os.Getpid())
+	path := filepath.Join(dir, "session-"+session+".jsonl")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("创建提示词日志失败: %w", err)
+	}
+
+	t := &Transcript{file: file, path: path, session: session}
+	defaultTranscriptMu.Lock()
+	defaultTranscript = t
+	defaultTranscriptMu.Unlock()
+	return t, nil
+}
+
+// currentTranscript 返回进程级的会话记录，未开启时为 nil
+func currentTranscript() *Transcript {
+	defaultTranscriptMu.Lock()
+	defer defaultTranscriptMu.Unlock()
+	return defaultTranscript
+}
+
+// Path 记录文件路径
+func (t *Transcript) Path() string {
+	return t.path
+}
+
+// Record 脱敏后追加一条事件；t 为 nil（未开启）时什么也不做
+func (t *Transcript) Record(kind, name, content string) {
+	if t == nil {
+		return
+	}
+	entry := TranscriptEntry{
+		Time:    time.Now(),
+		Session: t.session,
+		Kind:    kind,
+		Name:    name,
+		Content: RedactSecrets(content),
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.file.Write(append(data, '\n'))
+}
+
+// Close 关闭记录文件，并取消进程级的会话记录
+func (t *Transcript) Close() error {
+	if t == nil {
+		return nil
+	}
+	defaultTranscriptMu.Lock()
+	if defaultTranscript == t {
+		defaultTranscript = nil
+	}
+	defaultTranscriptMu.Unlock()
+	return t.file.Close()
+}
+
+// redactedValue 替换密钥的占位符
+const redactedValue = "[REDACTED]"
+
+// secretPattern 一类需要脱敏的内容及其替换方式
+type secretPattern struct {
+	re   *regexp.Regexp
+	repl string
+}
+
+// secretPatterns 需要脱敏的内容，赋值、请求头等保留前缀，只替换密钥本身
+var secretPatterns = []secretPattern{
+	// 私钥块
+	{regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`), redactedValue},
+	// URL 中的用户名密码
+	{regexp.MustCompile(`://[^/\s:@]+:[^/\s@]+@`), "://" + redactedValue + "@"},
+	// Authorization 头中的令牌
+	{regexp.MustCompile(`(?i)(bearer\s+|basic\s+)[A-Za-z0-9._~+/=-]{8,}`), "${1}" + redactedValue},
+	// 常见的云服务和代码托管令牌
+	{regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`), redactedValue},
+	{regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{30,}\b`), redactedValue},
+	{regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{20,}\b`), redactedValue},
+	{regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\b`), redactedValue},
+}
+
+// secretAssignment password = "..."、api_key: ... 等赋值：1 为保留的前缀，2 为赋值符号，3 为引号，4 为值，5 为值后面的左括号
+var secretAssignment = regexp.MustCompile(`(?i)((?:password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key|private[_-]?key|credential)[A-Za-z_]*["']?\s*(:=|[:=])\s*)(["'` + "`" + `]?)([^"'` + "`" + `\s,;()]{4,})(\(?)`)
+
+// goSelector 形如 cfg.Password 的字段或包成员引用
+var goSelector = regexp.MustCompile(`^[A-Za-z_]\w*(\.[A-Za-z_]\w*)+$`)
+
+// RedactSecrets 把文本中的密钥、令牌和密码替换为 [REDACTED]
+func RedactSecrets(text string) string {
+	for _, p := range secretPatterns {
+		text = p.re.ReplaceAllString(text, p.repl)
+	}
+	return secretAssignment.ReplaceAllStringFunc(text, redactAssignment)
+}
+
+// redactAssignment 替换赋值中的值；会话记录里大多是源码，不带引号的值是 Go 表达式
+// （:= 声明、函数调用、字段引用）时原样保留，tokens := strings.Fields(line) 不是密钥
+func redactAssignment(match string) string {
+	m := secretAssignment.FindStringSubmatch(match)
+	prefix, op, quote, value, call := m[1], m[2], m[3], m[4], m[5]
+	if quote == "" && (op == ":=" || call != "" || goSelector.MatchString(value)) {
+		return match
+	}
+	return prefix + quote + redactedValue + call
+}
+
+// transcriptModel 把对话模型的请求和回答写入会话记录
+type transcriptModel struct {
+	llms.Model
+	transcript *Transcript
+}
+
+// GenerateContent 记录完整的消息、工具调用和回答
+func (m *transcriptModel) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	m.transcript.Record(TranscriptPrompt, "", formatMessages(messages))
+	resp, err := m.Model.GenerateContent(ctx, messages, options...)
+	if err != nil {
+		m.transcript.Record(TranscriptError, "", err.Error())
+		return resp, err
+	}
+	for _, choice := range resp.Choices {
+		m.transcript.Record(TranscriptResponse, "", choice.Content)
+		for _, call := range choice.ToolCalls {
+			if call.FunctionCall != nil {
+				m.transcript.Record(TranscriptToolCall, call.FunctionCall.Name, call.FunctionCall.Arguments)
+			}
+		}
+	}
+	return resp, nil
+}
+
+// Call 记录单条提示词和回答
+func (m *transcriptModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+// formatMessages 把消息列表展开为文本，每条消息以角色开头
+func formatMessages(messages []llms.MessageContent) string {
+	var out []byte
+	for _, msg := range messages {
+		out = append(out, fmt.Sprintf("[%s]\n", msg.Role)...)
+		for _, part := range msg.Parts {
+			switch p := part.(type) {
+			case llms.TextContent:
+				out = append(out, p.Text...)
+			case llms.ToolCallResponse:
+				out = append(out, fmt.Sprintf("工具 %s 的结果: %s", p.Name, p.Content)...)
+			case llms.ToolCall:
+				if p.FunctionCall != nil {
+					out = append(out, fmt.Sprintf("调用工具 %s: %s", p.FunctionCall.Name, p.FunctionCall.Arguments)...)
+				}
+			default:
+				out = append(out, fmt.Sprintf("%v", p)...)
+			}
+			out = append(out, '\n')
+		}
+	}
+	return string(out)
+}
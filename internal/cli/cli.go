@@ -2,11 +2,18 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"go-ai-study/internal/ai"
 	"go-ai-study/internal/cli/commands"
 	"go-ai-study/internal/cli/output"
 	"go-ai-study/internal/config"
+	"go-ai-study/internal/i18n"
 	"go-ai-study/internal/tools"
+	"os"
+	"path/filepath"
+	"strings"
 )
 
 // CLI 主 CLI 结构
@@ -15,43 +22,118 @@ type CLI struct {
 	commandRegistry *commands.CommandRegistry
 	config         *config.Config
 	formatter      output.Formatter
+	workspace      *tools.Workspace
+	discoverer     tools.FileDiscoverer
+}
+
+// CLIOptions 全局命令行参数，优先级高于配置文件；零值表示沿用配置文件中的设置
+type CLIOptions struct {
+	ConfigPath   string // 配置文件路径，为空时使用默认位置
+	Format       string // 输出格式 (json|text|template|html)，为空或 text 时使用配置文件中的 default_format
+	OutputPath   string // 输出文件路径
+	TemplatePath string // -f template 使用的模板文件，为空时使用配置文件中的 report_template
+	Verbose      bool   // 详细输出
+	LocalOnly    bool   // 只允许连接本机地址
+	DryRun       bool   // 不写入文件，只收集变更
+	OutDir       string // 生成的文件写到该目录
+	Lang         string // 输出语言
+	NoEmoji      bool   // 文本输出去掉 emoji
+	ASCII        bool   // 文本输出只使用 ASCII 符号
+	Schema       string // bug、security 结果的输出结构 (v1|v2)
+	FilesFrom    string // 从文件列表读取要分析的文件
+	Symlinks     string // 遍历目录时符号链接的处理方式
+	MaxDepth     int    // 遍历目录时进入子目录的层数上限，0 表示不覆盖
+
+	// 日志
+	LogLevel    string
+	LogFormat   string
+	LogOutput   string
+	LogFilePath string
+	LogPrompts  bool // 记录完整的提示词、检索片段和回答（脱敏后）
 }
 
 // NewCLI 创建 CLI
-func NewCLI(configPath, format string, outputPath string, verbose bool,
-	logLevel, logFormat, logOutput, logFilePath string) (*CLI, error) {
+func NewCLI(opts CLIOptions) (*CLI, error) {
 	// 加载配置
-	cfg, err := config.Load(configPath)
+	cfg, err := config.Load(opts.ConfigPath)
 	if err != nil {
 		return nil, fmt.Errorf("加载配置失败: %w", err)
 	}
 
 	// 命令行参数优先级高于配置文件
-	if format != "text" {
-		cfg.DefaultFormat = format
+	if opts.Format != "" && opts.Format != "text" {
+		cfg.DefaultFormat = opts.Format
 	}
-	if verbose {
+	if opts.Verbose {
 		cfg.Verbose = true
 	}
+	if opts.LocalOnly {
+		cfg.LocalOnly = true
+	}
+	if opts.Lang != "" {
+		cfg.Locale = opts.Lang
+	}
+	if opts.NoEmoji {
+		cfg.NoEmoji = true
+	}
+	if opts.ASCII {
+		cfg.ASCII = true
+	}
+	if opts.Schema != "" {
+		cfg.OutputSchema = opts.Schema
+	}
+	if cfg.OutputSchema, err = tools.ParseOutputSchema(cfg.OutputSchema); err != nil {
+		return nil, err
+	}
+	if opts.FilesFrom != "" {
+		cfg.Discovery.Mode = tools.DiscoveryList
+		cfg.Discovery.FileList = opts.FilesFrom
+	}
+	if opts.Symlinks != "" {
+		cfg.Discovery.Symlinks = opts.Symlinks
+	}
+	if opts.MaxDepth > 0 {
+		cfg.Discovery.MaxDepth = opts.MaxDepth
+	}
+	discoverer, err := tools.NewFileDiscoverer(cfg.Discovery)
+	if err != nil {
+		return nil, err
+	}
+	if err := i18n.SetLocale(cfg.Locale); err != nil {
+		return nil, err
+	}
+	if cfg.LocalOnly {
+		if err := enforceLocalOnly(cfg); err != nil {
+			return nil, err
+		}
+	}
 
 	// 日志配置：命令行参数优先级 > 配置文件
-	if logLevel != "" {
-		cfg.LogConfig.Level = logLevel
+	if opts.LogLevel != "" {
+		cfg.LogConfig.Level = opts.LogLevel
 	}
-	if logFormat != "" {
-		cfg.LogConfig.Format = logFormat
+	if opts.LogFormat != "" {
+		cfg.LogConfig.Format = opts.LogFormat
 	}
-	if logOutput != "" {
-		cfg.LogConfig.Output = logOutput
+	if opts.LogOutput != "" {
+		cfg.LogConfig.Output = opts.LogOutput
 	}
-	if logFilePath != "" {
-		cfg.LogConfig.FilePath = logFilePath
+	if opts.LogFilePath != "" {
+		cfg.LogConfig.FilePath = opts.LogFilePath
+	}
+	if opts.LogPrompts {
+		cfg.PromptLog.Enabled = true
+	}
+	if err := startPromptLog(cfg); err != nil {
+		return nil, err
 	}
 
 	// 创建输出格式化器
 	var formatter output.Formatter
 	outputOptions := output.Options{
 		Verbose: cfg.Verbose,
+		NoEmoji: cfg.NoEmoji,
+		ASCII:   cfg.ASCII,
 	}
 
 	switch cfg.DefaultFormat {
@@ -59,6 +141,17 @@ func NewCLI(configPath, format string, outputPath string, verbose bool,
 		formatter = output.NewJSONFormatter()
 	case "text":
 		formatter = output.NewTextFormatter(outputOptions)
+	case "html":
+		formatter = output.NewHTMLFormatter("")
+	case "template":
+		templatePath := opts.TemplatePath
+		if templatePath == "" {
+			templatePath = cfg.ReportTemplate
+		}
+		formatter, err = output.NewTemplateFormatter(templatePath)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("不支持的输出格式: %s", cfg.DefaultFormat)
 	}
@@ -67,29 +160,60 @@ func NewCLI(configPath, format string, outputPath string, verbose bool,
 	logger := tools.NewLoggerFactory(&cfg.LogConfig)
 	toolManager := tools.NewToolManager(logger)
 
-	// 注册所有工具
-	registerTools(toolManager)
+	// 规则插件先于规则设置加载，配置中可以关闭插件中的规则或覆盖其严重程度
+	if err := tools.LoadRulePlugins(cfg.Rules.Plugins); err != nil {
+		return nil, err
+	}
+
+	// 注册所有工具（规则设置有误时直接失败，而不是注册失败后找不到工具）
+	if _, err := tools.RuleSettingsFromConfig(ruleCustomConfig(cfg)); err != nil {
+		return nil, fmt.Errorf("规则配置无效: %w", err)
+	}
+	registerTools(toolManager, cfg)
+
+	// 工具执行审计日志
+	auditLog, err := tools.NewAuditLog(cfg.AuditLog)
+	if err != nil {
+		return nil, err
+	}
+	toolManager.SetAuditLog(auditLog)
+	toolManager.SetCrashReportDir(cfg.CrashReportDir)
 
 	// 创建命令注册表
 	commandRegistry := commands.NewCommandRegistry()
-	registerCommands(commandRegistry, toolManager)
+	registerCommands(commandRegistry, toolManager, cfg)
+
+	// 本次命令的工作区：--out-dir 重定向生成的文件，--dry-run 只收集变更
+	workspace := tools.NewWorkspace(opts.OutDir)
+	if opts.DryRun {
+		workspace.ChangeSet = tools.NewChangeSet()
+	}
 
 	return &CLI{
 		toolManager:    toolManager,
 		commandRegistry: commandRegistry,
 		config:         cfg,
 		formatter:      formatter,
+		workspace:      workspace,
+		discoverer:     discoverer,
 	}, nil
 }
 
 // registerTools 注册所有工具
-func registerTools(tm *tools.ToolManager) {
+func registerTools(tm *tools.ToolManager, cfg *config.Config) {
 	// 获取 ToolManager 的 logger
 	logger := tm.GetLogger()
 
-	// 注册测试生成器
-	tm.Register(
-		tools.NewTestGenerator(logger),
+	// 创建对话模型（只创建客户端，失败时相关工具退化为非 LLM 模式）
+	chatModel, err := ai.NewChatModel(cfg.OllamaEndpoint, cfg.ChatModel)
+	if err != nil {
+		logger.Warn("创建对话模型失败", "error", err)
+		chatModel = nil
+	}
+
+	// 注册测试生成器（会写文件，每次执行使用新实例）
+	tm.RegisterFactory(
+		func() tools.Tool { return tools.NewTestGenerator(logger) },
 		tools.DefaultToolConfig("test_generator"),
 	)
 
@@ -99,27 +223,223 @@ func registerTools(tm *tools.ToolManager) {
 		tools.DefaultToolConfig("complexity_analyzer"),
 	)
 
-	// 注册安全扫描器
+	// 注册重构建议（依赖复杂度分析器，由 ToolManager 先执行）
+	tm.Register(
+		tools.NewRefactorAdvisor(),
+		tools.DefaultToolConfig("refactor_advisor"),
+	)
+
+	// 注册错误处理覆盖率分析器（需要 go list 加载类型信息，放宽超时）
+	errorCoverageConfig := tools.DefaultToolConfig("error_coverage")
+	errorCoverageConfig.Timeout = 120000
+	tm.Register(
+		tools.NewErrorCoverageAnalyzer(),
+		errorCoverageConfig,
+	)
+
+	// 注册 Go 版本兼容性检查器（需要 go list 加载类型信息，放宽超时）
+	goCompatConfig := tools.DefaultToolConfig("go_compat")
+	goCompatConfig.Timeout = 120000
+	tm.Register(
+		tools.NewGoCompatChecker(),
+		goCompatConfig,
+	)
+
+	// 注册死代码分析器（需要 go list 加载类型信息，放宽超时）
+	deadCodeConfig := tools.DefaultToolConfig("deadcode_analyzer")
+	deadCodeConfig.Timeout = 120000
+	tm.Register(
+		tools.NewDeadCodeAnalyzer(),
+		deadCodeConfig,
+	)
+
+	// 注册现代化建议分析器
+	tm.Register(
+		tools.NewModernizeAnalyzer(),
+		tools.DefaultToolConfig("modernize"),
+	)
+
+	// 注册测试代码比例分析器
+	tm.Register(
+		tools.NewTestRatioAnalyzer(),
+		tools.DefaultToolConfig("test_ratio"),
+	)
+
+	// 注册架构检查器
+	tm.Register(
+		tools.NewArchChecker(),
+		tools.DefaultToolConfig("archcheck"),
+	)
+
+	// 注册模块清单工具（查询模块代理可能较慢）
+	inventoryConfig := tools.DefaultToolConfig("inventory")
+	inventoryConfig.Timeout = 120000
+	tm.Register(
+		tools.NewModuleInventory(),
+		inventoryConfig,
+	)
+
+	// 注册二进制体积分析器（需要执行 go build）
+	binSizeConfig := tools.DefaultToolConfig("binary_size")
+	binSizeConfig.Timeout = 300000
+	tm.Register(
+		tools.NewBinarySizeAnalyzer(),
+		binSizeConfig,
+	)
+
+	// 注册隐私审计工具
+	tm.Register(
+		tools.NewPrivacyAuditor(cfg),
+		tools.DefaultToolConfig("privacy_audit"),
+	)
+
+	// 注册环境自检工具
+	tm.Register(
+		tools.NewEnvironmentDoctor(cfg),
+		tools.DefaultToolConfig("environment_doctor"),
+	)
+
+	// 注册文档注释覆盖率分析器
+	tm.Register(
+		tools.NewDocCoverageAnalyzer(),
+		tools.DefaultToolConfig("doc_coverage"),
+	)
+
+	// 注册 Go 文档查询工具（供对话模型查询标准库文档）
+	tm.Register(
+		tools.NewGoDocLookup(),
+		tools.DefaultToolConfig("go_doc"),
+	)
+
+	// 注册安全扫描器（规则开关和严重程度覆盖见配置文件的 rules）
+	securityConfig := tools.DefaultToolConfig("security_scanner")
+	securityConfig.CustomConfig = ruleCustomConfig(cfg)
 	tm.Register(
 		tools.NewSecurityScanner(),
-		tools.DefaultToolConfig("security_scanner"),
+		securityConfig,
 	)
 
 	// 注册 Bug 检测器
+	bugConfig := tools.DefaultToolConfig("bug_detector")
+	bugConfig.CustomConfig = ruleCustomConfig(cfg)
 	tm.Register(
 		tools.NewBugDetector(),
-		tools.DefaultToolConfig("bug_detector"),
+		bugConfig,
 	)
+
+	// 注册图示生成器（LLM 生成较慢，放宽超时）
+	diagramConfig := tools.DefaultToolConfig("diagram_generator")
+	diagramConfig.Timeout = 120000
+	tm.Register(
+		tools.NewDiagramGenerator(chatModel, logger),
+		diagramConfig,
+	)
+
+	// 注册接口抽取工具
+	tm.Register(
+		tools.NewInterfaceExtractor(),
+		tools.DefaultToolConfig("interface_extractor"),
+	)
+
+	// 注册文档注释生成器（逐个标识符调用 LLM，放宽超时）
+	docGenConfig := tools.DefaultToolConfig("doc_generator")
+	docGenConfig.Timeout = 300000
+	tm.Register(
+		tools.NewDocGenerator(chatModel, logger),
+		docGenConfig,
+	)
+
+	// 注册修复生成器（部分修复需要 LLM 改写函数）
+	fixerConfig := tools.DefaultToolConfig("code_fixer")
+	fixerConfig.Timeout = 300000
+	tm.Register(
+		tools.NewCodeFixer(chatModel, logger),
+		fixerConfig,
+	)
+
+	// 注册严重程度裁决器（分批请求 LLM，放宽超时）
+	adjudicatorConfig := tools.DefaultToolConfig("severity_adjudicator")
+	adjudicatorConfig.Timeout = 300000
+	tm.Register(
+		tools.NewSeverityAdjudicator(chatModel, logger),
+		adjudicatorConfig,
+	)
+
+	applyToolLimits(tm, cfg.ToolLimits)
+}
+
+// ruleCustomConfig 把配置文件中的 rules 转换为规则引擎读取的 CustomConfig
+func ruleCustomConfig(cfg *config.Config) map[string]any {
+	return map[string]any{
+		tools.CustomConfigDisabledRules: cfg.Rules.Disabled,
+		tools.CustomConfigRuleSeverity:  cfg.Rules.Severity,
+		tools.CustomConfigCustomRules:   cfg.Rules.Custom,
+	}
+}
+
+// applyToolLimits 把配置文件中的资源上限应用到已注册的工具
+func applyToolLimits(tm *tools.ToolManager, limits map[string]config.ToolLimitConfig) {
+	if len(limits) == 0 {
+		return
+	}
+	for _, name := range tm.List() {
+		limit, ok := limits[name]
+		if !ok {
+			if limit, ok = limits["*"]; !ok {
+				continue
+			}
+		}
+		toolConfig, err := tm.GetConfig(name)
+		if err != nil {
+			continue
+		}
+		if limit.TimeoutMs > 0 {
+			toolConfig.Timeout = limit.TimeoutMs
+		}
+		toolConfig.Limits = tools.ResourceLimits{
+			MaxMemoryMB:   limit.MaxMemoryMB,
+			MaxFiles:      limit.MaxFiles,
+			MaxFileSizeKB: limit.MaxFileSizeKB,
+
+			MaxFindings:        limit.MaxFindings,
+			MaxFindingsPerRule: limit.MaxFindingsPerRule,
+		}
+		tm.UpdateConfig(name, toolConfig)
+	}
 }
 
 // registerCommands 注册所有命令
-func registerCommands(registry *commands.CommandRegistry, toolManager *tools.ToolManager) {
+func registerCommands(registry *commands.CommandRegistry, toolManager *tools.ToolManager, cfg *config.Config) {
 	registry.Register(commands.NewAnalyzeCommand(toolManager))
 	registry.Register(commands.NewTestCommand(toolManager))
 	registry.Register(commands.NewSecurityCommand(toolManager))
-	registry.Register(commands.NewBugCommand(toolManager))
+	registry.Register(commands.NewBugCommand(toolManager, cfg))
 	registry.Register(commands.NewComplexityCommand(toolManager))
-	registry.Register(commands.NewScanCommand())
+	registry.Register(commands.NewDiagramCommand(toolManager))
+	registry.Register(commands.NewExplainFindingCommand(toolManager, cfg))
+	registry.Register(commands.NewTriageCommand(toolManager))
+	registry.Register(commands.NewGateCommand(toolManager))
+	registry.Register(commands.NewPipelineCommand(toolManager))
+	registry.Register(commands.NewFixCommand(toolManager))
+	registry.Register(commands.NewExtractInterfaceCommand(toolManager))
+	registry.Register(commands.NewDocCoverageCommand(toolManager))
+	registry.Register(commands.NewAuditCommand(toolManager, cfg))
+	registry.Register(commands.NewArchCheckCommand(toolManager, cfg))
+	registry.Register(commands.NewInventoryCommand(toolManager))
+	registry.Register(commands.NewCompatCommand(toolManager))
+	registry.Register(commands.NewModernizeCommand(toolManager))
+	registry.Register(commands.NewDeadCodeCommand(toolManager))
+	registry.Register(commands.NewBinSizeCommand(toolManager))
+	registry.Register(commands.NewScanCommand(toolManager, cfg))
+	registry.Register(commands.NewSearchCommand(cfg))
+	registry.Register(commands.NewAskCommand(toolManager, cfg))
+	registry.Register(commands.NewSyncDocsCommand(cfg))
+	registry.Register(commands.NewIndexCommand(cfg))
+	registry.Register(commands.NewEvalCommand(cfg))
+	registry.Register(commands.NewEmbedCompareCommand(cfg))
+	registry.Register(commands.NewPrivacyCommand(toolManager, cfg))
+	registry.Register(commands.NewSchemaCommand(toolManager))
+	registry.Register(commands.NewDoctorCommand(toolManager))
 	registry.Register(commands.NewListCommand(registry))
 }
 
@@ -136,37 +456,141 @@ func (c *CLI) Run(ctx context.Context, args []string) error {
 	// 获取命令
 	cmd, ok := c.commandRegistry.Get(commandName)
 	if !ok {
-		return fmt.Errorf("未知命令: %s\n运行 'go-ai-insight list' 查看可用命令", commandName)
+		return errors.New(i18n.T("cli.unknown_command", commandName))
+	}
+
+	// 模板中可以按命令名选择不同的布局
+	switch f := c.formatter.(type) {
+	case *output.TemplateFormatter:
+		f.Command = commandName
+	case *output.HTMLFormatter:
+		f.Command = commandName
+	}
+
+	// 执行命令，所有工具共用本次命令的工作区
+	defer c.workspace.Cleanup()
+	ctx = tools.WithWorkspace(ctx, c.workspace)
+	ctx = tools.WithInvocation(ctx, strings.Join(args, " "))
+	ctx = tools.WithFileDiscoverer(ctx, c.discoverer)
+	ctx = tools.WithOutputSchema(ctx, c.config.OutputSchema)
+	c.warnDeprecatedSchema(commandName)
+	if err := cmd.Run(ctx, commandArgs, c.formatter); err != nil {
+		return err
+	}
+	if c.workspace.ChangeSet != nil {
+		c.printChangeSet()
+	}
+	return nil
+}
+
+// schemaCommands 输出 BugResult、SecurityResult 的命令，受 --schema 影响
+var schemaCommands = map[string]bool{"bug": true, "security": true}
+
+// warnDeprecatedSchema 结构化输出仍在使用 v1 结构时向标准错误输出弃用提示，不影响标准输出的结果
+func (c *CLI) warnDeprecatedSchema(commandName string) {
+	if !schemaCommands[commandName] || c.config.OutputSchema != tools.OutputSchemaV1 || !output.Structured(c.formatter) {
+		return
+	}
+	fmt.Fprintln(os.Stderr, i18n.T("cli.schema_v1_deprecated"))
+}
+
+// printChangeSet 输出 dry-run 模式下收集的文件变更
+// JSON 格式输出到标准错误，避免破坏命令本身的 JSON 结果
+func (c *CLI) printChangeSet() {
+	changes := c.workspace.ChangeSet.Changes()
+	if _, ok := c.formatter.(*output.JSONFormatter); ok {
+		data, err := json.MarshalIndent(map[string]any{"dry_run": true, "changes": changes}, "", "  ")
+		if err == nil {
+			fmt.Fprintln(os.Stderr, string(data))
+		}
+		return
+	}
+
+	var sb strings.Builder
+	if len(changes) == 0 {
+		sb.WriteString(i18n.T("cli.dry_run_none") + "\n")
+	} else {
+		sb.WriteString(i18n.T("cli.dry_run_changes", len(changes)) + "\n")
+	}
+	for _, change := range changes {
+		if change.Created {
+			sb.WriteString(i18n.T("cli.dry_run_created", change.Path) + "\n")
+		}
+		sb.WriteString(change.Diff)
 	}
+	fmt.Print(c.formatter.Format(strings.TrimSuffix(sb.String(), "\n")))
+}
 
-	// 执行命令
-	return cmd.Run(ctx, commandArgs, c.formatter)
+// helpCommands 帮助中列出的命令，说明文本在消息目录的 help.cmd.<name> 中
+var helpCommands = []string{
+	"scan", "search", "ask", "sync-docs", "index", "eval", "embed-compare", "analyze", "test", "security", "bug", "complexity", "diagram",
+	"explain-finding", "triage", "gate", "pipeline", "fix", "extract-interface", "doc-coverage", "audit", "archcheck",
+	"inventory", "compat", "modernize", "deadcode", "binsize", "privacy", "schema", "doctor", "list",
+}
+
+// startPromptLog 开启提示词日志：需要显式开启，并且日志级别为 debug
+// 之后创建的对话模型会把请求和回答写入本次会话的日志文件
+func startPromptLog(cfg *config.Config) error {
+	if !cfg.PromptLog.Enabled {
+		return nil
+	}
+	if cfg.LogConfig.Level != "debug" {
+		return fmt.Errorf("记录提示词需要 debug 日志级别（--log-level debug），当前为 %s", cfg.LogConfig.Level)
+	}
+	dir := cfg.PromptLog.Dir
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "go-ai-insight-prompts")
+	}
+	transcript, err := ai.StartTranscript(dir)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, i18n.T("cli.prompt_log", transcript.Path()))
+	return nil
+}
+
+// helpOptions 帮助中列出的全局选项，说明文本在消息目录的 help.opt.<key> 中
+var helpOptions = []struct {
+	flag string
+	key  string
+}{
+	{"-c, --config <file>", "config"},
+	{"-f, --format <format>", "format"},
+	{"--template <file>", "template"},
+	{"-o, --output <file>", "output"},
+	{"-v, --verbose", "verbose"},
+	{"--lang <locale>", "lang"},
+	{"--no-emoji", "no-emoji"},
+	{"--ascii", "ascii"},
+	{"--local-only", "local-only"},
+	{"--dry-run", "dry-run"},
+	{"--out-dir <dir>", "out-dir"},
+	{"--files-from <file>", "files-from"},
+	{"--symlinks <mode>", "symlinks"},
+	{"--max-depth <n>", "max-depth"},
+	{"--schema <v1|v2>", "schema"},
+	{"--log-prompts", "log-prompts"},
+	{"--version", "version"},
 }
 
 // printHelp 打印帮助信息
 func (c *CLI) printHelp() error {
-	fmt.Println("go-ai-insight - Go 代码分析和测试工具")
+	fmt.Println(i18n.T("help.title"))
 	fmt.Println("")
-	fmt.Println("使用:")
+	fmt.Println(i18n.T("help.usage"))
 	fmt.Println("  go-ai-insight <command> [options]")
 	fmt.Println("")
-	fmt.Println("命令:")
-	fmt.Println("  scan        扫描代码并存储")
-	fmt.Println("  analyze     分析代码")
-	fmt.Println("  test        生成测试")
-	fmt.Println("  security    安全扫描")
-	fmt.Println("  bug         Bug 检测")
-	fmt.Println("  complexity  复杂度分析")
-	fmt.Println("  list        列出所有可用工具")
+	fmt.Println(i18n.T("help.commands"))
+	for _, name := range helpCommands {
+		fmt.Printf("  %-18s %s\n", name, i18n.T("help.cmd."+name))
+	}
 	fmt.Println("")
-	fmt.Println("全局选项:")
-	fmt.Println("  -c, --config <file>   配置文件路径")
-	fmt.Println("  -f, --format <format> 输出格式 (json|text)")
-	fmt.Println("  -o, --output <file>   输出文件路径")
-	fmt.Println("  -v, --verbose         详细输出")
-	fmt.Println("  --version             显示版本信息")
+	fmt.Println(i18n.T("help.global_options"))
+	for _, opt := range helpOptions {
+		fmt.Printf("  %-22s %s\n", opt.flag, i18n.T("help.opt."+opt.key))
+	}
 	fmt.Println("")
-	fmt.Println("示例:")
+	fmt.Println(i18n.T("help.examples"))
 	fmt.Println("  go-ai-insight analyze ./myproject")
 	fmt.Println("  go-ai-insight test ./myproject -f json -o result.json")
 	fmt.Println("  go-ai-insight security ./myproject -v")
@@ -31,12 +31,26 @@ func (c *AnalyzeCommand) Description() string {
 }
 
 // Run 执行命令
+// 用法: analyze <file> [--report out.html] [--fail-on High]
+// --fail-on 按复杂度给函数定级（11-20 为 Medium，21-50 为 High，>50 为 Critical）
 func (c *AnalyzeCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
-	if len(args) == 0 {
+	fs := newFlagSet(c.Name())
+	report := fs.String("report", "", "同时把结果写成 HTML 报告")
+	failOn := failOnFlag(fs)
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+	threshold, err := parseFailOn(*failOn)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
 		return fmt.Errorf("需要指定路径或文件")
 	}
 
-	target := args[0]
+	target := positional[0]
 
 	// 读取文件内容
 	content, err := os.ReadFile(target)
@@ -57,5 +71,10 @@ func (c *AnalyzeCommand) Run(ctx context.Context, args []string, formatter outpu
 	// 输出结果
 	fmt.Println(formatter.Format(complexityResult.Result))
 
-	return nil
+	if *report != "" {
+		if err := writeHTMLReport(*report, c.Name(), complexityResult.Result, formatter); err != nil {
+			return err
+		}
+	}
+	return checkFailOn("complexity_analyzer", complexityResult.Result, threshold)
 }
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/config"
+	"go-ai-study/internal/tools"
+	"strings"
+)
+
+// ArchCheckCommand 架构检查命令
+type ArchCheckCommand struct {
+	toolManager *tools.ToolManager
+	config      *config.Config
+}
+
+// NewArchCheckCommand 创建架构检查命令
+func NewArchCheckCommand(toolManager *tools.ToolManager, cfg *config.Config) *ArchCheckCommand {
+	return &ArchCheckCommand{
+		toolManager: toolManager,
+		config:      cfg,
+	}
+}
+
+// Name 命令名称
+func (c *ArchCheckCommand) Name() string {
+	return "archcheck"
+}
+
+// Description 命令描述
+func (c *ArchCheckCommand) Description() string {
+	return "按配置的导入规则和分层声明检查包依赖（arch.import_rules / arch.layers）"
+}
+
+// Run 执行命令
+// 用法: archcheck [dir]，存在违规时返回错误
+func (c *ArchCheckCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	fs := newFlagSet(c.Name())
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+
+	dir := "."
+	if len(positional) > 0 {
+		dir = positional[0]
+	}
+
+	result, err := c.toolManager.Run(ctx, "archcheck", newArchCheckRequest(dir, c.config))
+	if err != nil {
+		return fmt.Errorf("架构检查失败: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("架构检查失败: %s", result.Error)
+	}
+
+	var check tools.ArchCheckResult
+	if err := json.Unmarshal([]byte(result.Result), &check); err != nil {
+		return fmt.Errorf("解析架构检查结果失败: %w", err)
+	}
+
+	if output.Structured(formatter) {
+		fmt.Println(formatter.Format(result.Result))
+	} else {
+		fmt.Println(formatter.Format(formatArchViolations(&check)))
+	}
+
+	if len(check.Violations) > 0 {
+		return fmt.Errorf("发现 %d 处架构违规", len(check.Violations))
+	}
+	return nil
+}
+
+// newArchCheckRequest 由配置构造架构检查请求
+func newArchCheckRequest(dir string, cfg *config.Config) tools.ArchCheckRequest {
+	return tools.ArchCheckRequest{
+		Directory:     dir,
+		ImportRules:   cfg.Arch.ImportRules,
+		ForbiddenDeps: cfg.Arch.ForbiddenDeps,
+		Layers:        cfg.Arch.Layers,
+	}
+}
+
+// formatArchViolations 生成架构违规的文本列表
+func formatArchViolations(check *tools.ArchCheckResult) string {
+	var sb strings.Builder
+	for _, v := range check.Violations {
+		sb.WriteString(fmt.Sprintf("⚠️ [%s] %s:%d %s\n", v.RuleID, v.File, v.Line, v.Message))
+	}
+	sb.WriteString(fmt.Sprintf("✅ %s", check.Summary))
+	return sb.String()
+}
@@ -0,0 +1,188 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/ai"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/config"
+	"go-ai-study/internal/tools"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// AskCommand 基于代码索引回答问题的命令，支持从文件批量读取问题
+type AskCommand struct {
+	toolManager *tools.ToolManager
+	config      *config.Config
+}
+
+// NewAskCommand 创建问答命令
+func NewAskCommand(toolManager *tools.ToolManager, cfg *config.Config) *AskCommand {
+	return &AskCommand{
+		toolManager: toolManager,
+		config:      cfg,
+	}
+}
+
+// Name 命令名称
+func (c *AskCommand) Name() string {
+	return "ask"
+}
+
+// Description 命令描述
+func (c *AskCommand) Description() string {
+	return "基于代码索引回答问题，--batch 从文件读取问题批量回答"
+}
+
+// Run 执行命令
+// 用法: ask <question> [--file path] [--self-check]
+//
+//	ask --batch questions.txt [--out results.json] [--self-check]
+func (c *AskCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	fs := newFlagSet(c.Name())
+	batch := fs.String("batch", "", "问题列表文件：每行一个问题，# 开头为注释")
+	file := fs.String("file", "", "只检索该文件的代码片段")
+	out := fs.String("out", "", "把结果写入 JSON 文件")
+	selfCheck := fs.Bool("self-check", false, "生成回答后让模型对照代码自检，附加置信度说明")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+
+	var questions []string
+	if *batch != "" {
+		if len(positional) > 0 {
+			return fmt.Errorf("--batch 与命令行问题不能同时使用")
+		}
+		if questions, err = tools.LoadQuestions(*batch); err != nil {
+			return err
+		}
+	} else {
+		question := strings.TrimSpace(strings.Join(positional, " "))
+		if question == "" {
+			return fmt.Errorf("需要指定问题，或用 --batch 指定问题列表文件")
+		}
+		questions = []string{question}
+	}
+
+	// 所有问题共用同一个代码索引连接和模型客户端
+	engine, closeEngine, err := c.newEngine(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeEngine()
+	engine.SelfCheck = *selfCheck
+
+	results := make([]tools.AskBatchItem, 0, len(questions))
+	for i, question := range questions {
+		if len(questions) > 1 && !output.Structured(formatter) {
+			fmt.Fprintf(os.Stderr, "[%d/%d] %s\n", i+1, len(questions), question)
+		}
+		// 批量问题彼此独立，不带上一个问题的对话记忆
+		engine.History = nil
+		start := time.Now()
+		answer, err := engine.Answer(ctx, question, *file)
+		item := tools.AskBatchItem{Seconds: time.Since(start).Seconds()}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			item.Question = question
+			item.Error = err.Error()
+		} else {
+			item.AskResult = *answer
+		}
+		results = append(results, item)
+	}
+	report := tools.SummarizeAskBatch(c.config.ChatModel, results)
+
+	jsonBytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化结果失败: %w", err)
+	}
+	if *out != "" {
+		if err := os.WriteFile(*out, jsonBytes, 0644); err != nil {
+			return fmt.Errorf("写入结果失败: %w", err)
+		}
+	}
+
+	if output.Structured(formatter) {
+		fmt.Println(formatter.Format(string(jsonBytes)))
+	} else {
+		for i, r := range report.Results {
+			if len(report.Results) > 1 {
+				fmt.Printf("\n## %d. %s\n\n", i+1, r.Question)
+			}
+			if r.Error != "" {
+				fmt.Printf("❌ 回答失败: %s\n", r.Error)
+				continue
+			}
+			fmt.Println(formatter.Format(r.Answer))
+			if len(r.Sources) > 0 {
+				fmt.Printf("\n参考代码: %s\n", strings.Join(r.Sources, ", "))
+			}
+			if len(r.Commits) > 0 {
+				fmt.Printf("参考提交: %s\n", strings.Join(r.Commits, ", "))
+			}
+			if len(r.Discussions) > 0 {
+				fmt.Printf("参考讨论: %s\n", strings.Join(r.Discussions, ", "))
+			}
+		}
+		if len(report.Results) > 1 {
+			fmt.Printf("\n%s\n", report.Summary)
+		}
+		if *out != "" {
+			fmt.Printf("结果已写入 %s\n", *out)
+		}
+	}
+	if report.Answered == 0 {
+		return fmt.Errorf("所有问题都回答失败")
+	}
+	return nil
+}
+
+// newEngine 创建分析引擎：问答依赖代码索引，索引不可用时直接报错
+func (c *AskCommand) newEngine(ctx context.Context) (*ai.SourceInsightEngine, func(), error) {
+	logger := ai.NewLogger(slog.LevelWarn)
+
+	chatModel, err := ai.NewChatModel(c.config.OllamaEndpoint, c.config.ChatModel)
+	if err != nil {
+		return nil, nil, err
+	}
+	embedder, err := ai.NewEmbedder(c.config.OllamaEndpoint, c.config.EmbeddingModel)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	connectCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	mc, err := ai.ConnectMilvus(connectCtx, c.config.MilvusEndpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := ai.CheckCodeIndex(ctx, mc, ai.CodeCollection, c.config.EmbeddingModel); err != nil {
+		mc.Close()
+		return nil, nil, err
+	}
+
+	engine := ai.NewEngine(mc, embedder, chatModel, logger)
+	engine.ContextBudget = c.config.ContextTokens
+	engine.Filter.Scopes = c.config.ACL.Scopes
+	engine.Filter.DocRatio = c.config.Docs.Ratio
+	engine.ExtraTools = c.toolManager.LLMTools()
+	engine.ToolRunner = c.toolManager.CallJSON
+	// scan --history 建立过提交历史时，历史类问题同时参考相关提交
+	if ai.HasCommitHistory(ctx, mc, c.config.EmbeddingModel) {
+		engine.CommitCollection = ai.CommitCollection
+	}
+	// sync-docs 同步过 issue/PR 时，历史类问题同时参考相关讨论
+	if ai.HasProjectDocs(ctx, mc, c.config.EmbeddingModel) {
+		engine.ProjectDocsCollection = ai.ProjectDocsCollection
+	}
+	return engine, func() { mc.Close() }, nil
+}
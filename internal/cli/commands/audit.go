@@ -0,0 +1,191 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/config"
+	"go-ai-study/internal/tools"
+	"strings"
+)
+
+// AuditCommand 代码库审计命令
+// 汇总多个度量工具的结果，生成一份审计报告
+type AuditCommand struct {
+	toolManager *tools.ToolManager
+	config      *config.Config
+}
+
+// NewAuditCommand 创建审计命令
+func NewAuditCommand(toolManager *tools.ToolManager, cfg *config.Config) *AuditCommand {
+	return &AuditCommand{
+		toolManager: toolManager,
+		config:      cfg,
+	}
+}
+
+// Name 命令名称
+func (c *AuditCommand) Name() string {
+	return "audit"
+}
+
+// Description 命令描述
+func (c *AuditCommand) Description() string {
+	return "生成代码库审计报告（测试比例、未测试的包、文档覆盖率、架构违规）"
+}
+
+// auditReport 审计报告
+type auditReport struct {
+	Directory string                   `json:"directory"`
+	Module    string                   `json:"module,omitempty"` // go.work 成员模块路径
+	Tests     *tools.TestRatioResult   `json:"tests,omitempty"`
+	Docs      *tools.DocCoverageResult `json:"docs,omitempty"`
+	Arch      *tools.ArchCheckResult   `json:"arch,omitempty"`
+	Modules   []*auditReport           `json:"modules,omitempty"` // 目录下有 go.work 时每个成员模块一节
+}
+
+// Run 执行命令
+// 用法: audit [dir|repo-url[@ref]] [--top 10]
+// 远程仓库浅克隆到临时目录后审计，结束后删除，适合在引入第三方依赖前评估
+func (c *AuditCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	fs := newFlagSet(c.Name())
+	top := fs.Int("top", 10, "列出测试比例最低的前 N 个包（0 表示全部）")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+
+	target := "."
+	if len(positional) > 0 {
+		target = positional[0]
+	}
+	dir, cleanup, err := resolveTarget(ctx, target, c.config, formatter)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	work, err := tools.LoadGoWork(dir)
+	if err != nil {
+		return err
+	}
+	// 报告中显示用户给出的路径或仓库地址，而不是克隆用的临时目录
+	report := &auditReport{Directory: target}
+	if work == nil {
+		if err := c.collect(ctx, report, dir); err != nil {
+			return err
+		}
+	} else {
+		// 每个成员模块单独审计，嵌套的成员模块不计入外层模块
+		ctx = tools.WithGoWork(ctx, work)
+		for _, m := range work.Modules {
+			label := target
+			if m.Rel != "." {
+				label = strings.TrimSuffix(target, "/") + "/" + m.Rel
+			}
+			section := &auditReport{Directory: label, Module: m.Path}
+			if err := c.collect(ctx, section, m.Dir); err != nil {
+				return fmt.Errorf("模块 %s: %w", m.Path, err)
+			}
+			report.Modules = append(report.Modules, section)
+		}
+	}
+
+	if output.Structured(formatter) {
+		jsonBytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("序列化审计报告失败: %w", err)
+		}
+		fmt.Println(formatter.Format(string(jsonBytes)))
+		return nil
+	}
+
+	if len(report.Modules) == 0 {
+		fmt.Println(formatter.Format(formatAuditReport(report, *top)))
+		return nil
+	}
+	sections := make([]string, 0, len(report.Modules))
+	for _, section := range report.Modules {
+		sections = append(sections, formatAuditReport(section, *top))
+	}
+	fmt.Println(formatter.Format(strings.Join(sections, "\n\n")))
+	return nil
+}
+
+// collect 对 dir 运行各度量工具，填充报告
+func (c *AuditCommand) collect(ctx context.Context, report *auditReport, dir string) error {
+	if err := c.runTool(ctx, "test_ratio", tools.TestRatioRequest{Directory: dir}, &report.Tests); err != nil {
+		return err
+	}
+	if err := c.runTool(ctx, "doc_coverage", tools.DocCoverageRequest{Directory: dir}, &report.Docs); err != nil {
+		return err
+	}
+
+	// 架构检查依赖 go.mod，不在模块中的目录跳过这一项
+	if err := c.runTool(ctx, "archcheck", newArchCheckRequest(dir, c.config), &report.Arch); err != nil {
+		report.Arch = nil
+	}
+	return nil
+}
+
+// runTool 运行一个度量工具并解析其 JSON 结果
+func (c *AuditCommand) runTool(ctx context.Context, name string, input any, out any) error {
+	result, err := c.toolManager.Run(ctx, name, input)
+	if err != nil {
+		return fmt.Errorf("%s 执行失败: %w", name, err)
+	}
+	if !result.Success {
+		return fmt.Errorf("%s 执行失败: %s", name, result.Error)
+	}
+	if err := json.Unmarshal([]byte(result.Result), out); err != nil {
+		return fmt.Errorf("解析 %s 结果失败: %w", name, err)
+	}
+	return nil
+}
+
+// formatAuditReport 生成文本审计报告
+func formatAuditReport(report *auditReport, top int) string {
+	var sb strings.Builder
+	if report.Module != "" {
+		sb.WriteString(fmt.Sprintf("📦 模块 %s（%s）\n", report.Module, report.Directory))
+	}
+	sb.WriteString(fmt.Sprintf("📝 审计报告: %s\n\n", report.Directory))
+
+	tests := report.Tests
+	sb.WriteString("测试比例（测试代码行数 / 业务代码行数，从低到高）:\n")
+	packages := tests.Packages
+	if top > 0 && len(packages) > top {
+		packages = packages[:top]
+	}
+	for _, pkg := range packages {
+		sb.WriteString(fmt.Sprintf("  %-45s %5.2f  (%d/%d 行)\n", pkg.Package, pkg.Ratio, pkg.TestLOC, pkg.CodeLOC))
+	}
+	if len(packages) < len(tests.Packages) {
+		sb.WriteString(fmt.Sprintf("  ... 其余 %d 个包省略（--top 0 显示全部）\n", len(tests.Packages)-len(packages)))
+	}
+	if len(tests.Untested) > 0 {
+		sb.WriteString(fmt.Sprintf("\n⚠️ 没有测试文件的包（%d 个）:\n", len(tests.Untested)))
+		for _, pkg := range tests.Untested {
+			sb.WriteString(fmt.Sprintf("  - %s\n", pkg))
+		}
+		sb.WriteString(fmt.Sprintf("  建议优先生成测试: go-ai-insight test %s --dir\n", tests.Untested[0]))
+	}
+	sb.WriteString(fmt.Sprintf("✅ %s\n\n", tests.Summary))
+
+	sb.WriteString("文档注释覆盖率:\n")
+	for _, pkg := range report.Docs.Packages {
+		if pkg.Exported == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("  %-45s %6.1f%%  (%d/%d)\n", pkg.Package, pkg.Coverage, pkg.Documented, pkg.Exported))
+	}
+	sb.WriteString(fmt.Sprintf("✅ %s", report.Docs.Summary))
+
+	if report.Arch != nil {
+		sb.WriteString("\n\n架构检查:\n")
+		sb.WriteString(formatArchViolations(report.Arch))
+	}
+	return sb.String()
+}
@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/tools"
+	"strings"
+)
+
+// BinSizeCommand 二进制体积分析命令
+type BinSizeCommand struct {
+	toolManager *tools.ToolManager
+}
+
+// NewBinSizeCommand 创建二进制体积分析命令
+func NewBinSizeCommand(toolManager *tools.ToolManager) *BinSizeCommand {
+	return &BinSizeCommand{
+		toolManager: toolManager,
+	}
+}
+
+// Name 命令名称
+func (c *BinSizeCommand) Name() string {
+	return "binsize"
+}
+
+// Description 命令描述
+func (c *BinSizeCommand) Description() string {
+	return "构建二进制并按依赖模块统计体积，标出过重的间接依赖"
+}
+
+// Run 执行命令
+// 用法: binsize [dir] [--pkg ./cmd] [--binary path] [--ldflags "-w"] [--threshold 5] [--top 20]
+func (c *BinSizeCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	fs := newFlagSet(c.Name())
+	pkg := fs.String("pkg", ".", "要构建的 main 包")
+	binary := fs.String("binary", "", "分析已构建的二进制（跳过构建）")
+	ldflags := fs.String("ldflags", "-w", "构建时的 -ldflags（需保留符号表，不要使用 -s）")
+	threshold := fs.Float64("threshold", 5, "依赖体积占比超过该百分比时标为过重")
+	top := fs.Int("top", 20, "列出体积最大的前 N 个包")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+
+	dir := "."
+	if len(positional) > 0 {
+		dir = positional[0]
+	}
+
+	result, err := c.toolManager.Run(ctx, "binary_size", tools.BinarySizeRequest{
+		Directory: dir,
+		Package:   *pkg,
+		Binary:    *binary,
+		Ldflags:   *ldflags,
+		Threshold: *threshold,
+		Top:       *top,
+	})
+	if err != nil {
+		return fmt.Errorf("二进制体积分析失败: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("二进制体积分析失败: %s", result.Error)
+	}
+
+	if output.Structured(formatter) {
+		fmt.Println(formatter.Format(result.Result))
+		return nil
+	}
+
+	var size tools.BinarySizeResult
+	if err := json.Unmarshal([]byte(result.Result), &size); err != nil {
+		return fmt.Errorf("解析体积分析结果失败: %w", err)
+	}
+	fmt.Println(formatter.Format(formatBinarySize(&size)))
+	return nil
+}
+
+// formatBinarySize 生成体积分析的文本报告
+func formatBinarySize(size *tools.BinarySizeResult) string {
+	var sb strings.Builder
+	sb.WriteString("📝 按模块统计:\n")
+	for _, mod := range size.Modules {
+		marker := ""
+		if mod.Heavy {
+			marker = "  ⚠ 过重"
+		}
+		sb.WriteString(fmt.Sprintf("  %-55s %-8s %10d  %5.1f%%%s\n", mod.Path, mod.Kind, mod.Size, mod.Percent, marker))
+	}
+
+	sb.WriteString("\n📝 体积最大的包:\n")
+	for _, pkg := range size.Packages {
+		sb.WriteString(fmt.Sprintf("  %-55s %10d  %5.1f%%\n", pkg.Package, pkg.Size, pkg.Percent))
+	}
+
+	for _, heavy := range size.Heavy {
+		sb.WriteString(fmt.Sprintf("\n⚠️ 间接依赖 %s 体积过重，可用 `go mod why -m %s` 查看引入路径", heavy, heavy))
+	}
+	sb.WriteString(fmt.Sprintf("\n✅ %s", size.Summary))
+	return sb.String()
+}
@@ -2,8 +2,10 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/config"
 	"go-ai-study/internal/tools"
 	"os"
 )
@@ -11,12 +13,14 @@ import (
 // BugCommand Bug 检测命令
 type BugCommand struct {
 	toolManager *tools.ToolManager
+	config      *config.Config
 }
 
 // NewBugCommand 创建 Bug 检测命令
-func NewBugCommand(toolManager *tools.ToolManager) *BugCommand {
+func NewBugCommand(toolManager *tools.ToolManager, cfg *config.Config) *BugCommand {
 	return &BugCommand{
 		toolManager: toolManager,
+		config:      cfg,
 	}
 }
 
@@ -31,27 +35,111 @@ func (c *BugCommand) Description() string {
 }
 
 // Run 执行命令
+// 用法: bug <file|dir> [--types] [--snapshot] [--snapshot-archive out.tar.gz] [--stream] [--min-confidence high|medium|low] [--category Concurrency,...] [--report out.html] [--fail-on High]
 func (c *BugCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
-	if len(args) == 0 {
+	fs := newFlagSet(c.Name())
+	stream := fs.Bool("stream", false, "每分析完一个文件就输出其中的问题（JSON 格式下输出 NDJSON）")
+	minConfidence := fs.String("min-confidence", "", "只报告不低于该置信度的问题：high、medium、low")
+	category := categoryFlag(fs)
+	report := fs.String("report", "", "同时把结果写成 HTML 报告")
+	typeCheck := fs.Bool("types", false, "加载类型信息，按真实类型判断以减少误报（需要 go 命令和 go.mod）")
+	snapshot := fs.Bool("snapshot", false, "在结果中记录分析的文件列表和内容哈希，便于之后复现")
+	snapshotArchive := fs.String("snapshot-archive", "", "同时把分析的文件打包为 tar.gz（隐含 --snapshot）")
+	failOn := failOnFlag(fs)
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+	threshold, err := parseFailOn(*failOn)
+	if err != nil {
+		return err
+	}
+	categories, err := tools.ParseCategories(*category)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
 		return fmt.Errorf("需要指定路径或文件")
 	}
 
-	target := args[0]
-
-	// 读取文件内容
-	content, err := os.ReadFile(target)
+	target := positional[0]
+	info, err := os.Stat(target)
 	if err != nil {
-		return fmt.Errorf("读取文件失败: %w", err)
+		return fmt.Errorf("读取路径失败: %w", err)
+	}
+
+	// 目录整体检测；单个文件按路径检测（go.mod 版本、DTO 包按文件所在目录判断）
+	input := tools.BugDetectorInput{
+		Tags:            c.config.StructTags,
+		MinConfidence:   *minConfidence,
+		Categories:      categories,
+		TypeCheck:       *typeCheck,
+		Snapshot:        *snapshot,
+		SnapshotArchive: *snapshotArchive,
+	}
+	if info.IsDir() {
+		input.Directory = target
+	} else {
+		input.Files = []string{target}
+	}
+
+	_, jsonOutput := formatter.(*output.JSONFormatter)
+	if *stream {
+		if jsonOutput {
+			ctx = tools.WithStream(ctx, tools.NDJSONStream(os.Stdout))
+		} else {
+			ctx = tools.WithStream(ctx, func(event tools.StreamEvent) {
+				printStreamEvent(formatter, event)
+			})
+		}
 	}
 
 	// 执行 Bug 检测
-	bugResult, err := c.toolManager.Run(ctx, "bug_detector", string(content))
+	bugResult, err := c.toolManager.Run(ctx, "bug_detector", input)
 	if err != nil {
 		return fmt.Errorf("Bug 检测失败: %w", err)
 	}
+	if !bugResult.Success {
+		return fmt.Errorf("Bug 检测失败: %s", bugResult.Error)
+	}
+
+	// 流式输出时问题已经逐个打印，最后只输出汇总
+	if *stream {
+		var result tools.BugResult
+		if err := json.Unmarshal([]byte(bugResult.Result), &result); err != nil {
+			return fmt.Errorf("解析 Bug 检测结果失败: %w", err)
+		}
+		if jsonOutput {
+			tools.NDJSONStream(os.Stdout)(tools.StreamEvent{Type: tools.StreamEventDone, Tool: "bug_detector", Findings: result.Total})
+		} else {
+			fmt.Println(formatter.Format(fmt.Sprintf("✅ %s", result.Summary)))
+		}
+	} else {
+		// 输出结果
+		fmt.Println(formatter.Format(bugResult.Result))
+	}
 
-	// 输出结果
-	fmt.Println(formatter.Format(bugResult.Result))
+	if *snapshotArchive != "" && !jsonOutput {
+		fmt.Println(formatter.Format(fmt.Sprintf("📦 分析快照已写入 %s", *snapshotArchive)))
+	}
+	if *report != "" {
+		if err := writeHTMLReport(*report, c.Name(), bugResult.Result, formatter); err != nil {
+			return err
+		}
+	}
+	return checkFailOn("bug_detector", bugResult.Result, threshold)
+}
 
-	return nil
+// printStreamEvent 以文本形式输出一条流式事件
+func printStreamEvent(formatter output.Formatter, event tools.StreamEvent) {
+	switch event.Type {
+	case tools.StreamEventFinding:
+		f := event.Finding
+		fmt.Printf("%s:%d  %-5s %-8s %s\n", f.File, f.Line, f.RuleID, f.Severity, f.Description)
+	case tools.StreamEventFileDone:
+		if event.Error != "" {
+			fmt.Println(formatter.Format(fmt.Sprintf("⚠️ %s: %s", event.File, event.Error)))
+		}
+	}
 }
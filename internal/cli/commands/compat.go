@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/tools"
+	"strings"
+)
+
+// CompatCommand Go 版本兼容性检查命令
+type CompatCommand struct {
+	toolManager *tools.ToolManager
+}
+
+// NewCompatCommand 创建 Go 版本兼容性检查命令
+func NewCompatCommand(toolManager *tools.ToolManager) *CompatCommand {
+	return &CompatCommand{
+		toolManager: toolManager,
+	}
+}
+
+// Name 命令名称
+func (c *CompatCommand) Name() string {
+	return "compat"
+}
+
+// Description 命令描述
+func (c *CompatCommand) Description() string {
+	return "检查代码与 go.mod 中 go 版本的兼容性，并提示当前版本可用的新写法"
+}
+
+// Run 执行命令
+// 用法: compat [dir] [--go 1.20]
+func (c *CompatCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	fs := newFlagSet(c.Name())
+	goVersion := fs.String("go", "", "按该 Go 版本检查（默认使用 go.mod 中的 go 指令）")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+
+	dir := "."
+	if len(positional) > 0 {
+		dir = positional[0]
+	}
+
+	result, err := c.toolManager.Run(ctx, "go_compat", tools.GoCompatRequest{Directory: dir, GoVersion: *goVersion})
+	if err != nil {
+		return fmt.Errorf("兼容性检查失败: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("兼容性检查失败: %s", result.Error)
+	}
+
+	if output.Structured(formatter) {
+		fmt.Println(formatter.Format(result.Result))
+		return nil
+	}
+
+	var compat tools.GoCompatResult
+	if err := json.Unmarshal([]byte(result.Result), &compat); err != nil {
+		return fmt.Errorf("解析兼容性检查结果失败: %w", err)
+	}
+	fmt.Println(formatter.Format(formatGoCompat(&compat)))
+	return nil
+}
+
+// formatGoCompat 生成文本报告：先列出过新的特性，再列出可用的新写法
+func formatGoCompat(result *tools.GoCompatResult) string {
+	var sb strings.Builder
+	sections := []struct {
+		kind  string
+		title string
+	}{
+		{"too_new", "❌ 使用了比目标版本更新的特性"},
+		{"modernize", "💡 当前版本可用的新写法"},
+	}
+	for _, section := range sections {
+		var lines []string
+		for _, issue := range result.Issues {
+			if issue.Kind == section.kind {
+				lines = append(lines, fmt.Sprintf("  %s:%d  %s\n      → %s\n", issue.File, issue.Line, issue.Message, issue.Suggestion))
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		sb.WriteString(section.title + "\n")
+		sb.WriteString(strings.Join(lines, ""))
+	}
+	sb.WriteString(fmt.Sprintf("✅ %s", result.Summary))
+	return sb.String()
+}
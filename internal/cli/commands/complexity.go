@@ -2,10 +2,12 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"go-ai-study/internal/cli/output"
 	"go-ai-study/internal/tools"
 	"os"
+	"path/filepath"
 )
 
 // ComplexityCommand 复杂度分析命令
@@ -31,21 +33,45 @@ func (c *ComplexityCommand) Description() string {
 }
 
 // Run 执行命令
+// 用法: complexity <file|dir> [--top N] [--history metrics.jsonl] [--no-errors]
 func (c *ComplexityCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
-	if len(args) == 0 {
+	fs := newFlagSet(c.Name())
+	top := fs.Int("top", 10, "分析目录时列出的最复杂函数个数")
+	history := fs.String("history", "", "将错误处理覆盖率追加到历史文件（JSONL），并显示与上次的变化")
+	noErrors := fs.Bool("no-errors", false, "不统计错误处理覆盖率")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+	if len(positional) == 0 {
 		return fmt.Errorf("需要指定路径或文件")
 	}
 
-	target := args[0]
-
-	// 读取文件内容
-	content, err := os.ReadFile(target)
+	target := patternDir(positional[0])
+	info, err := os.Stat(target)
 	if err != nil {
 		return fmt.Errorf("读取文件失败: %w", err)
 	}
 
+	// 错误处理覆盖率：文件统计所在包，目录统计目录下所有包
+	errorsReq := tools.ErrorCoverageRequest{Directory: target, Patterns: []string{"./..."}}
+
+	// 目录按文件、包汇总并列出最复杂的函数（跳过 vendor、testdata 和测试文件）
+	input := tools.ComplexityInput{Directory: target, Top: *top}
+	if !info.IsDir() {
+		errorsReq = tools.ErrorCoverageRequest{Directory: filepath.Dir(target), Patterns: []string{"."}}
+
+		// 读取文件内容
+		content, err := os.ReadFile(target)
+		if err != nil {
+			return fmt.Errorf("读取文件失败: %w", err)
+		}
+		input = tools.ComplexityInput{Code: string(content), File: target}
+	}
+
 	// 执行复杂度分析
-	complexityResult, err := c.toolManager.Run(ctx, "complexity_analyzer", string(content))
+	complexityResult, err := c.toolManager.Run(ctx, "complexity_analyzer", input)
 	if err != nil {
 		return fmt.Errorf("复杂度分析失败: %w", err)
 	}
@@ -57,6 +83,43 @@ func (c *ComplexityCommand) Run(ctx context.Context, args []string, formatter ou
 		fmt.Println("[ERROR] 分析失败")
 	}
 
-	return nil
+	if *noErrors {
+		return nil
+	}
+	return c.reportErrorCoverage(ctx, errorsReq, target, *history, formatter)
 }
 
+// reportErrorCoverage 统计并输出错误处理覆盖率，指定历史文件时追加快照
+func (c *ComplexityCommand) reportErrorCoverage(ctx context.Context, req tools.ErrorCoverageRequest, target, history string, formatter output.Formatter) error {
+	result, err := c.toolManager.Run(ctx, "error_coverage", req)
+	if err != nil {
+		return fmt.Errorf("错误处理覆盖率统计失败: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("错误处理覆盖率统计失败: %s", result.Error)
+	}
+
+	var coverage tools.ErrorCoverageResult
+	if err := json.Unmarshal([]byte(result.Result), &coverage); err != nil {
+		return fmt.Errorf("解析覆盖率结果失败: %w", err)
+	}
+
+	var previous *tools.ErrorCoverageSnapshot
+	if history != "" {
+		absTarget, err := filepath.Abs(target)
+		if err != nil {
+			absTarget = target
+		}
+		previous, err = tools.AppendErrorCoverageHistory(ctx, history, tools.NewErrorCoverageSnapshot(absTarget, &coverage))
+		if err != nil {
+			return err
+		}
+	}
+
+	if output.Structured(formatter) {
+		fmt.Println(formatter.Format(result.Result))
+		return nil
+	}
+	fmt.Println(formatter.Format(tools.FormatErrorCoverage(&coverage, previous)))
+	return nil
+}
@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/tools"
+	"strings"
+)
+
+// DeadCodeCommand 死代码分析命令
+type DeadCodeCommand struct {
+	toolManager *tools.ToolManager
+}
+
+// NewDeadCodeCommand 创建死代码分析命令
+func NewDeadCodeCommand(toolManager *tools.ToolManager) *DeadCodeCommand {
+	return &DeadCodeCommand{
+		toolManager: toolManager,
+	}
+}
+
+// Name 命令名称
+func (c *DeadCodeCommand) Name() string {
+	return "deadcode"
+}
+
+// Description 命令描述
+func (c *DeadCodeCommand) Description() string {
+	return "找出从未被引用的未导出函数、方法、常量和变量，以及 return/panic 之后不可达的代码"
+}
+
+// Run 执行命令
+// 用法: deadcode [dir]
+func (c *DeadCodeCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	fs := newFlagSet(c.Name())
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+
+	dir := "."
+	if len(positional) > 0 {
+		dir = positional[0]
+	}
+
+	result, err := c.toolManager.Run(ctx, "deadcode_analyzer", tools.DeadCodeRequest{Directory: dir})
+	if err != nil {
+		return fmt.Errorf("死代码分析失败: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("死代码分析失败: %s", result.Error)
+	}
+
+	if output.Structured(formatter) {
+		fmt.Println(formatter.Format(result.Result))
+		return nil
+	}
+
+	var deadCode tools.DeadCodeResult
+	if err := json.Unmarshal([]byte(result.Result), &deadCode); err != nil {
+		return fmt.Errorf("解析死代码分析结果失败: %w", err)
+	}
+	fmt.Println(formatter.Format(formatDeadCode(&deadCode)))
+	return nil
+}
+
+// formatDeadCode 生成文本报告：先列出未被引用的标识符，再列出不可达代码
+func formatDeadCode(result *tools.DeadCodeResult) string {
+	var sb strings.Builder
+	var unused, unreachable []string
+	for _, f := range result.Findings {
+		if f.Kind == "unreachable" {
+			unreachable = append(unreachable, fmt.Sprintf("  %s:%d  %s 之后的代码不会执行\n", f.File, f.Line, f.Name))
+		} else {
+			unused = append(unused, fmt.Sprintf("  %s:%d  %s %s\n", f.File, f.Line, f.Kind, f.Name))
+		}
+	}
+	if len(unused) > 0 {
+		sb.WriteString("🪦 未被引用的未导出标识符\n")
+		sb.WriteString(strings.Join(unused, ""))
+	}
+	if len(unreachable) > 0 {
+		sb.WriteString("🚫 不可达代码\n")
+		sb.WriteString(strings.Join(unreachable, ""))
+	}
+	for _, e := range result.Errors {
+		sb.WriteString(fmt.Sprintf("⚠️ %s\n", e))
+	}
+	if len(result.Errors) > 0 {
+		sb.WriteString("⚠️ 存在类型错误的包未参与分析\n")
+	}
+	sb.WriteString(fmt.Sprintf("✅ %s", result.Summary))
+	return sb.String()
+}
@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/tools"
+)
+
+// DiagramCommand 调用图示生成命令
+type DiagramCommand struct {
+	toolManager *tools.ToolManager
+}
+
+// NewDiagramCommand 创建图示生成命令
+func NewDiagramCommand(toolManager *tools.ToolManager) *DiagramCommand {
+	return &DiagramCommand{
+		toolManager: toolManager,
+	}
+}
+
+// Name 命令名称
+func (c *DiagramCommand) Name() string {
+	return "diagram"
+}
+
+// Description 命令描述
+func (c *DiagramCommand) Description() string {
+	return "为入口函数生成 Mermaid 时序图/流程图"
+}
+
+// Run 执行命令
+// 用法: diagram <dir> --entry <func> [--kind sequence|flowchart] [--depth 3] [--out file.md] [--no-llm]
+func (c *DiagramCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	fs := newFlagSet(c.Name())
+	entry := fs.String("entry", "", "入口函数")
+	kind := fs.String("kind", string(tools.DiagramSequence), "图示类型 (sequence|flowchart)")
+	depth := fs.Int("depth", 3, "最大调用深度")
+	out := fs.String("out", "", "输出的 Markdown 文件")
+	noLLM := fs.Bool("no-llm", false, "只根据调用图生成，不调用模型")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+	if *entry == "" {
+		return fmt.Errorf("需要通过 --entry 指定入口函数")
+	}
+
+	dir := "."
+	if len(positional) > 0 {
+		dir = positional[0]
+	}
+
+	req := tools.DiagramRequest{
+		Directory: dir,
+		Entry:     *entry,
+		Kind:      tools.DiagramKind(*kind),
+		MaxDepth:  *depth,
+		Output:    *out,
+		NoLLM:     *noLLM,
+	}
+
+	result, err := c.toolManager.Run(ctx, "diagram_generator", req)
+	if err != nil {
+		return fmt.Errorf("生成图示失败: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("生成图示失败: %s", result.Error)
+	}
+
+	fmt.Println(formatter.Format(result.Result))
+	return nil
+}
@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/tools"
+	"strings"
+)
+
+// DocCoverageCommand 文档注释覆盖率命令
+type DocCoverageCommand struct {
+	toolManager *tools.ToolManager
+}
+
+// NewDocCoverageCommand 创建文档注释覆盖率命令
+func NewDocCoverageCommand(toolManager *tools.ToolManager) *DocCoverageCommand {
+	return &DocCoverageCommand{
+		toolManager: toolManager,
+	}
+}
+
+// Name 命令名称
+func (c *DocCoverageCommand) Name() string {
+	return "doc-coverage"
+}
+
+// Description 命令描述
+func (c *DocCoverageCommand) Description() string {
+	return "统计导出标识符的文档注释覆盖率，可为缺口生成注释"
+}
+
+// Run 执行命令
+// 用法: doc-coverage <dir> [--fail-on 80] [--generate] [--write]
+func (c *DocCoverageCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	fs := newFlagSet(c.Name())
+	failOn := fs.Float64("fail-on", 0, "覆盖率低于该百分比时返回错误（0 表示不检查）")
+	generate := fs.Bool("generate", false, "调用 doc_generator 为缺少注释的标识符生成注释")
+	write := fs.Bool("write", false, "将生成的注释写回文件（配合 --generate）")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+
+	dir := "."
+	if len(positional) > 0 {
+		dir = positional[0]
+	}
+
+	result, err := c.toolManager.Run(ctx, "doc_coverage", tools.DocCoverageRequest{Directory: dir})
+	if err != nil {
+		return fmt.Errorf("文档覆盖率统计失败: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("文档覆盖率统计失败: %s", result.Error)
+	}
+
+	var coverage tools.DocCoverageResult
+	if err := json.Unmarshal([]byte(result.Result), &coverage); err != nil {
+		return fmt.Errorf("解析覆盖率结果失败: %w", err)
+	}
+
+	if output.Structured(formatter) {
+		fmt.Println(formatter.Format(result.Result))
+	} else {
+		fmt.Println(formatter.Format(formatDocCoverage(&coverage, *failOn)))
+	}
+
+	if *generate {
+		if err := c.generateDocs(ctx, &coverage, *write, formatter); err != nil {
+			return err
+		}
+	}
+
+	if *failOn > 0 && coverage.Coverage < *failOn {
+		return fmt.Errorf("文档注释覆盖率 %.1f%% 低于要求的 %.1f%%", coverage.Coverage, *failOn)
+	}
+	return nil
+}
+
+// generateDocs 将缺口交给 doc_generator 生成注释
+func (c *DocCoverageCommand) generateDocs(ctx context.Context, coverage *tools.DocCoverageResult, write bool, formatter output.Formatter) error {
+	var gaps []tools.DocGap
+	for _, pkg := range coverage.Packages {
+		gaps = append(gaps, pkg.Missing...)
+	}
+	if len(gaps) == 0 {
+		return nil
+	}
+
+	result, err := c.toolManager.Run(ctx, "doc_generator", tools.DocGenRequest{Gaps: gaps, Write: write})
+	if err != nil {
+		return fmt.Errorf("生成文档注释失败: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("生成文档注释失败: %s", result.Error)
+	}
+
+	if output.Structured(formatter) {
+		fmt.Println(formatter.Format(result.Result))
+		return nil
+	}
+
+	var genResult tools.DocGenResult
+	if err := json.Unmarshal([]byte(result.Result), &genResult); err != nil {
+		return fmt.Errorf("解析生成结果失败: %w", err)
+	}
+	for _, patch := range genResult.Patches {
+		fmt.Print(patch.Diff)
+	}
+	for _, skipped := range genResult.Skipped {
+		fmt.Println(formatter.Format("⚠️ 跳过: " + skipped))
+	}
+	fmt.Println(formatter.Format("✅ " + genResult.Summary))
+	return nil
+}
+
+// formatDocCoverage 生成文本报告，低于最低要求的包会被标记
+func formatDocCoverage(coverage *tools.DocCoverageResult, minimum float64) string {
+	var sb strings.Builder
+	sb.WriteString("📝 文档注释覆盖率\n")
+	for _, pkg := range coverage.Packages {
+		if pkg.Exported == 0 {
+			continue
+		}
+		marker := ""
+		if minimum > 0 && pkg.Coverage < minimum {
+			marker = "  ⚠ 低于要求"
+		}
+		sb.WriteString(fmt.Sprintf("  %-45s %6.1f%%  (%d/%d)%s\n", pkg.Package, pkg.Coverage, pkg.Documented, pkg.Exported, marker))
+	}
+	sb.WriteString(fmt.Sprintf("✅ %s", coverage.Summary))
+	return sb.String()
+}
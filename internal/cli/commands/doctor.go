@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/tools"
+	"strings"
+)
+
+// DoctorCommand 环境自检命令
+type DoctorCommand struct {
+	toolManager *tools.ToolManager
+}
+
+// NewDoctorCommand 创建环境自检命令
+func NewDoctorCommand(toolManager *tools.ToolManager) *DoctorCommand {
+	return &DoctorCommand{
+		toolManager: toolManager,
+	}
+}
+
+// Name 命令名称
+func (c *DoctorCommand) Name() string {
+	return "doctor"
+}
+
+// Description 命令描述
+func (c *DoctorCommand) Description() string {
+	return "检查运行环境（go 命令、配置、Ollama 模型、Milvus 索引、目录权限），并给出每个问题的处理建议"
+}
+
+// Run 执行命令
+// 用法: doctor [--offline] [--module path]，存在失败项时返回错误
+func (c *DoctorCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	fs := newFlagSet(c.Name())
+	offline := fs.Bool("offline", false, "跳过 Ollama、Milvus 的连接检查（只使用静态分析时）")
+	module := fs.String("module", "", "检查 go.work 成员模块的索引（模块路径）")
+	if _, err := parseFlags(fs, args); err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+
+	result, err := c.toolManager.Run(ctx, "environment_doctor", tools.DoctorRequest{Module: *module, Offline: *offline})
+	if err != nil {
+		return fmt.Errorf("环境自检失败: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("环境自检失败: %s", result.Error)
+	}
+
+	var report tools.DoctorResult
+	if err := json.Unmarshal([]byte(result.Result), &report); err != nil {
+		return fmt.Errorf("解析环境自检结果失败: %w", err)
+	}
+	if output.Structured(formatter) {
+		fmt.Println(formatter.Format(result.Result))
+	} else {
+		fmt.Println(formatter.Format(formatDoctor(&report)))
+	}
+
+	if report.Failed > 0 {
+		return fmt.Errorf("环境自检有 %d 项失败", report.Failed)
+	}
+	return nil
+}
+
+// formatDoctor 格式化环境自检结果，失败和警告项下方列出处理建议
+func formatDoctor(report *tools.DoctorResult) string {
+	icons := map[string]string{
+		tools.DoctorOK:   "✅",
+		tools.DoctorWarn: "⚠️",
+		tools.DoctorFail: "❌",
+		tools.DoctorSkip: "⏭️",
+	}
+	var b strings.Builder
+	b.WriteString("🩺 环境自检\n")
+	for _, check := range report.Checks {
+		b.WriteString(fmt.Sprintf("  %s %s", icons[check.Status], check.Name))
+		if check.Detail != "" {
+			b.WriteString(": " + check.Detail)
+		}
+		b.WriteString("\n")
+		if check.Fix != "" {
+			b.WriteString(fmt.Sprintf("      修复: %s\n", check.Fix))
+		}
+	}
+	b.WriteString("📝 " + report.Summary)
+	return b.String()
+}
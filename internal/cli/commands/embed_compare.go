@@ -0,0 +1,181 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/ai"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/config"
+	"go-ai-study/internal/tools"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// EmbedCompareCommand 向量模型对比命令
+type EmbedCompareCommand struct {
+	config *config.Config
+}
+
+// NewEmbedCompareCommand 创建向量模型对比命令
+func NewEmbedCompareCommand(cfg *config.Config) *EmbedCompareCommand {
+	return &EmbedCompareCommand{
+		config: cfg,
+	}
+}
+
+// Name 命令名称
+func (c *EmbedCompareCommand) Name() string {
+	return "embed-compare"
+}
+
+// Description 命令描述
+func (c *EmbedCompareCommand) Description() string {
+	return "用两个向量模型分别为代码样本建临时索引，按评测集比较检索质量和耗时"
+}
+
+// Run 执行命令
+// 用法: embed-compare <cases.yaml> <path> --models a,b [--sample N] [--top N] [--out report.json]
+func (c *EmbedCompareCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	fs := newFlagSet(c.Name())
+	models := fs.String("models", "", "参与比较的向量模型（逗号分隔），只给一个时与配置的 embedding_model 比较")
+	sample := fs.Int("sample", 200, "最多索引的文件数（评测集期望的文件总是包含在内），0 表示全部")
+	top := fs.Int("top", 0, "每个问题检索的片段数，默认取评测集的 top_k（5）")
+	out := fs.String("out", "", "把对比报告保存为 JSON")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+	if len(positional) < 2 {
+		return fmt.Errorf("需要指定评测集文件和代码路径")
+	}
+	set, err := tools.LoadEvalSet(positional[0])
+	if err != nil {
+		return err
+	}
+	if *top > 0 {
+		set.TopK = *top
+	}
+	names := splitList(*models)
+	if len(names) == 1 {
+		names = append([]string{c.config.EmbeddingModel}, names...)
+	}
+	if len(names) < 2 {
+		return fmt.Errorf("需要用 --models 指定至少两个向量模型")
+	}
+
+	files, err := tools.DiscoverGoFiles(ctx, positional[1])
+	if err != nil {
+		return fmt.Errorf("扫描源码失败: %w", err)
+	}
+	files = tools.SampleEvalFiles(files, set, *sample)
+	chunks, err := ai.NewCodeSplitter().SplitDocuments(ai.LoadCode(files))
+	if err != nil {
+		return fmt.Errorf("代码分块失败: %w", err)
+	}
+	if len(chunks) == 0 {
+		return fmt.Errorf("%s 下没有可索引的 Go 代码", positional[1])
+	}
+	// 受限片段同样打标签，检索时按当前的访问范围过滤，与正式索引一致
+	tools.AnnotateChunkACL(chunks, positional[1], c.config.ACL.Restricted)
+
+	connectCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	mc, err := ai.ConnectMilvus(connectCtx, c.config.MilvusEndpoint)
+	if err != nil {
+		return err
+	}
+	defer mc.Close()
+
+	comparison := &tools.EmbeddingComparison{Files: len(files), Chunks: len(chunks), TopK: set.TopK}
+	filter := ai.SearchFilter{Scopes: c.config.ACL.Scopes}
+	for _, name := range names {
+		if !output.Structured(formatter) {
+			fmt.Printf("⏳ %s: 正在为 %d 个片段建立临时索引...\n", name, len(chunks))
+		}
+		comparison.Models = append(comparison.Models, c.evaluate(ctx, mc, name, chunks, set, filter))
+	}
+	tools.CompareEmbeddingModels(comparison)
+
+	if *out != "" {
+		data, err := json.MarshalIndent(comparison, "", "  ")
+		if err != nil {
+			return fmt.Errorf("序列化对比报告失败: %w", err)
+		}
+		if err := os.WriteFile(*out, data, 0644); err != nil {
+			return fmt.Errorf("写入对比报告失败: %w", err)
+		}
+	}
+
+	if output.Structured(formatter) {
+		jsonBytes, err := json.MarshalIndent(comparison, "", "  ")
+		if err != nil {
+			return fmt.Errorf("序列化对比报告失败: %w", err)
+		}
+		fmt.Println(formatter.Format(string(jsonBytes)))
+		return nil
+	}
+	fmt.Println(formatEmbeddingComparison(comparison))
+	fmt.Println(formatter.Format("📊 " + comparison.Summary))
+	return nil
+}
+
+// evaluate 用一个模型建临时索引、跑完评测集后删除索引；建索引失败记录在 Error 中，不中断其他模型
+func (c *EmbedCompareCommand) evaluate(ctx context.Context, mc client.Client, model string, chunks []schema.Document, set *tools.EvalSet, filter ai.SearchFilter) tools.ModelEvalReport {
+	result := tools.ModelEvalReport{Model: model}
+	trial, err := ai.BuildEmbeddingTrial(ctx, mc, c.config.OllamaEndpoint, model, chunks)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer func() {
+		if err := trial.Drop(context.WithoutCancel(ctx), mc); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️ %v\n", err)
+		}
+	}()
+	result.Dim = trial.Dim
+	result.IndexSeconds = trial.IndexTime.Seconds()
+
+	results := make([]tools.EvalCaseResult, len(set.Cases))
+	var latencies []time.Duration
+	for i, ec := range set.Cases {
+		hits, elapsed, err := trial.Search(ctx, mc, ec.Question, filter, set.TopK)
+		if err != nil {
+			results[i] = tools.EvalCaseResult{Question: ec.Question, Error: err.Error()}
+			continue
+		}
+		latencies = append(latencies, elapsed)
+		sources := make([]string, len(hits))
+		for j, hit := range hits {
+			sources[j] = hit.Source
+		}
+		results[i] = tools.ScoreRetrieval(ec, sources)
+	}
+	result.Report = tools.SummarizeEval(set, results)
+	result.AvgQueryMillis, result.P95QueryMillis = tools.QueryLatency(latencies)
+	return result
+}
+
+// formatEmbeddingComparison 各模型的指标并排显示
+func formatEmbeddingComparison(c *tools.EmbeddingComparison) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%-24s %6s %10s %8s %12s %12s %12s\n", "模型", "维度", "检索命中率", "MRR", "建索引(s)", "平均检索(ms)", "P95(ms)"))
+	for _, m := range c.Models {
+		if m.Report == nil {
+			sb.WriteString(fmt.Sprintf("%-24s ❌ %s\n", m.Model, m.Error))
+			continue
+		}
+		mark := ""
+		if m.Model == c.Best {
+			mark = " ⭐"
+		}
+		sb.WriteString(fmt.Sprintf("%-24s %6d %9.0f%% %8.2f %12.1f %12.1f %12.1f%s\n",
+			m.Model, m.Dim, m.Report.RetrievalHitRate*100, m.Report.MRR, m.IndexSeconds, m.AvgQueryMillis, m.P95QueryMillis, mark))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
@@ -0,0 +1,198 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/ai"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/config"
+	"go-ai-study/internal/tools"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// EvalCommand RAG 质量评测命令
+type EvalCommand struct {
+	config *config.Config
+}
+
+// NewEvalCommand 创建 RAG 评测命令
+func NewEvalCommand(cfg *config.Config) *EvalCommand {
+	return &EvalCommand{
+		config: cfg,
+	}
+}
+
+// Name 命令名称
+func (c *EvalCommand) Name() string {
+	return "eval"
+}
+
+// Description 命令描述
+func (c *EvalCommand) Description() string {
+	return "按 YAML 评测集评估检索命中率和回答关键词覆盖率"
+}
+
+// Run 执行命令
+// 用法: eval <cases.yaml> [--top N] [--no-answer] [--module path] [--baseline report.json] [--out report.json]
+func (c *EvalCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	fs := newFlagSet(c.Name())
+	top := fs.Int("top", 0, "每个问题检索的片段数，默认取评测集的 top_k（5）")
+	noAnswer := fs.Bool("no-answer", false, "只评测检索，不生成回答（不需要对话模型）")
+	module := fs.String("module", "", "评测 go.work 成员模块的索引（模块路径）")
+	baselineFile := fs.String("baseline", "", "与之前保存的评测报告比较（--out 生成）")
+	out := fs.String("out", "", "把评测报告保存为 JSON，供之后 --baseline 比较")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+	if len(positional) == 0 {
+		return fmt.Errorf("需要指定评测集文件")
+	}
+	set, err := tools.LoadEvalSet(positional[0])
+	if err != nil {
+		return err
+	}
+	if *top > 0 {
+		set.TopK = *top
+	}
+
+	var baseline *tools.EvalReport
+	if *baselineFile != "" {
+		data, err := os.ReadFile(*baselineFile)
+		if err != nil {
+			return fmt.Errorf("读取基线报告失败: %w", err)
+		}
+		baseline = &tools.EvalReport{}
+		if err := json.Unmarshal(data, baseline); err != nil {
+			return fmt.Errorf("解析基线报告失败: %w", err)
+		}
+	}
+
+	embedder, err := ai.NewEmbedder(c.config.OllamaEndpoint, c.config.EmbeddingModel)
+	if err != nil {
+		return err
+	}
+	connectCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	mc, err := ai.ConnectMilvus(connectCtx, c.config.MilvusEndpoint)
+	if err != nil {
+		return err
+	}
+	defer mc.Close()
+	collection := ai.CodeCollectionName(*module)
+	if err := ai.CheckCodeIndex(ctx, mc, collection, c.config.EmbeddingModel); err != nil {
+		return err
+	}
+
+	var engine *ai.SourceInsightEngine
+	if !*noAnswer {
+		chatModel, err := ai.NewChatModel(c.config.OllamaEndpoint, c.config.ChatModel)
+		if err != nil {
+			return err
+		}
+		engine = ai.NewEngine(mc, embedder, chatModel, ai.NewLogger(slog.LevelWarn))
+		engine.ContextBudget = c.config.ContextTokens
+	}
+
+	filter := ai.SearchFilter{Scopes: c.config.ACL.Scopes, Collection: collection}
+	results := make([]tools.EvalCaseResult, len(set.Cases))
+	for i, ec := range set.Cases {
+		hits, err := ai.SearchCode(ctx, mc, embedder, ec.Question, filter, set.TopK)
+		if err != nil {
+			results[i] = tools.EvalCaseResult{Question: ec.Question, Error: err.Error()}
+			continue
+		}
+		sources := make([]string, len(hits))
+		for j, hit := range hits {
+			sources[j] = hit.Source
+		}
+		results[i] = tools.ScoreRetrieval(ec, sources)
+
+		if engine != nil && len(ec.ExpectedKeywords) > 0 {
+			answer, err := engine.AnswerWithHits(ctx, ec.Question, hits)
+			if err != nil {
+				results[i].Error = err.Error()
+				continue
+			}
+			tools.ScoreAnswer(ec, answer, &results[i])
+		}
+		if !output.Structured(formatter) {
+			fmt.Println(formatEvalCase(i+1, &results[i]))
+		}
+	}
+	report := tools.SummarizeEval(set, results)
+
+	if *out != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("序列化评测报告失败: %w", err)
+		}
+		if err := os.WriteFile(*out, data, 0644); err != nil {
+			return fmt.Errorf("写入评测报告失败: %w", err)
+		}
+	}
+
+	if output.Structured(formatter) {
+		jsonBytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("序列化评测报告失败: %w", err)
+		}
+		fmt.Println(formatter.Format(string(jsonBytes)))
+		return nil
+	}
+	fmt.Println(formatter.Format("📊 " + report.Summary))
+	if baseline != nil {
+		fmt.Println(formatEvalDelta(baseline, report))
+	}
+	return nil
+}
+
+// formatEvalCase 单个问题的文本结果
+func formatEvalCase(n int, r *tools.EvalCaseResult) string {
+	var sb strings.Builder
+	status := "✅"
+	if r.Error != "" || len(r.MissingSources) > 0 || len(r.MissingKeywords) > 0 {
+		status = "⚠️"
+	}
+	sb.WriteString(fmt.Sprintf("%s %d. %s\n", status, n, r.Question))
+	if r.Error != "" {
+		sb.WriteString(fmt.Sprintf("    错误: %s\n", r.Error))
+	}
+	if r.HitRank > 0 {
+		sb.WriteString(fmt.Sprintf("    检索命中: 第 %d 名\n", r.HitRank))
+	}
+	if len(r.MissingSources) > 0 {
+		sb.WriteString(fmt.Sprintf("    未检索到: %s\n", strings.Join(r.MissingSources, ", ")))
+		sb.WriteString(fmt.Sprintf("    实际检索到: %s\n", strings.Join(r.Retrieved, ", ")))
+	}
+	if len(r.MatchedKeywords)+len(r.MissingKeywords) > 0 {
+		sb.WriteString(fmt.Sprintf("    关键词覆盖: %.0f%%", r.KeywordCoverage*100))
+		if len(r.MissingKeywords) > 0 {
+			sb.WriteString(fmt.Sprintf("（缺少 %s）", strings.Join(r.MissingKeywords, ", ")))
+		}
+		sb.WriteString("\n")
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// formatEvalDelta 与基线报告比较各项指标
+func formatEvalDelta(baseline, current *tools.EvalReport) string {
+	row := func(name string, before, after float64, percent bool) string {
+		scale, unit := 1.0, ""
+		if percent {
+			scale, unit = 100, "%"
+		}
+		return fmt.Sprintf("  %-14s %.2f%s → %.2f%s (%+.2f%s)", name, before*scale, unit, after*scale, unit, (after-before)*scale, unit)
+	}
+	return strings.Join([]string{
+		"与基线比较:",
+		row("检索命中率", baseline.RetrievalHitRate, current.RetrievalHitRate, true),
+		row("MRR", baseline.MRR, current.MRR, false),
+		row("关键词覆盖率", baseline.KeywordCoverage, current.KeywordCoverage, true),
+	}, "\n")
+}
@@ -0,0 +1,194 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"go-ai-study/internal/ai"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/config"
+	"go-ai-study/internal/tools"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// ExplainFindingCommand 解释分析问题的命令
+type ExplainFindingCommand struct {
+	toolManager *tools.ToolManager
+	config      *config.Config
+}
+
+// NewExplainFindingCommand 创建问题解释命令
+func NewExplainFindingCommand(toolManager *tools.ToolManager, cfg *config.Config) *ExplainFindingCommand {
+	return &ExplainFindingCommand{
+		toolManager: toolManager,
+		config:      cfg,
+	}
+}
+
+// Name 命令名称
+func (c *ExplainFindingCommand) Name() string {
+	return "explain-finding"
+}
+
+// Description 命令描述
+func (c *ExplainFindingCommand) Description() string {
+	return "解释某个问题为什么被标记，并给出修复补丁"
+}
+
+// Run 执行命令
+// 用法: explain-finding <fingerprint> [path] [--context 8] [--no-rag] [--self-check]
+//
+//	explain-finding --list [path]
+func (c *ExplainFindingCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	fs := newFlagSet(c.Name())
+	list := fs.Bool("list", false, "列出所有问题及其指纹")
+	contextLines := fs.Int("context", 8, "问题代码前后展示的行数")
+	noRAG := fs.Bool("no-rag", false, "不检索代码索引")
+	selfCheck := fs.Bool("self-check", false, "生成解释后让模型对照代码自检，标出没有依据的说法并附加置信度")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+
+	var fingerprint string
+	target := "."
+	if *list {
+		if len(positional) > 0 {
+			target = positional[0]
+		}
+	} else {
+		if len(positional) == 0 {
+			return fmt.Errorf("需要指定问题指纹（可先运行 explain-finding --list 查看）")
+		}
+		fingerprint = positional[0]
+		if len(positional) > 1 {
+			target = positional[1]
+		}
+	}
+
+	findings, err := tools.CollectFindings(ctx, c.toolManager, target)
+	if err != nil {
+		return err
+	}
+
+	if *list {
+		for _, f := range findings {
+			fmt.Printf("%s  %-5s %-8s %s:%d  %s\n", f.Fingerprint, f.RuleID, f.Severity, f.File, f.Line, f.Description)
+		}
+		fmt.Printf("共 %d 个问题\n", len(findings))
+		return nil
+	}
+
+	finding, err := tools.FindFinding(findings, fingerprint)
+	if err != nil {
+		return err
+	}
+
+	snippet, err := readSnippet(finding.File, finding.Line, *contextLines)
+	if err != nil {
+		return err
+	}
+
+	findingCtx := ai.FindingContext{
+		RuleID:          finding.RuleID,
+		Severity:        finding.Severity,
+		Category:        finding.Category,
+		RuleDescription: finding.Description,
+		Suggestion:      finding.Suggestion,
+		File:            finding.File,
+		Line:            finding.Line,
+		Function:        finding.Function,
+		Snippet:         snippet,
+	}
+	if doc, ok := tools.LookupRuleDoc(finding.RuleID); ok {
+		findingCtx.RuleName = doc.Name
+	}
+
+	engine, closeEngine, err := c.newEngine(ctx, !*noRAG)
+	if err != nil {
+		return err
+	}
+	defer closeEngine()
+	engine.SelfCheck = *selfCheck
+
+	fmt.Print(formatter.Format(fmt.Sprintf("🔍 %s %s（%s）%s:%d", finding.RuleID, finding.Description, finding.Severity, finding.File, finding.Line)))
+	explanation, err := engine.ExplainFinding(ctx, findingCtx)
+	if err != nil {
+		return fmt.Errorf("生成解释失败: %w", err)
+	}
+
+	fmt.Println(formatter.Format(explanation))
+	return nil
+}
+
+// newEngine 创建分析引擎；代码索引不可用时退化为仅使用问题代码
+func (c *ExplainFindingCommand) newEngine(ctx context.Context, withRAG bool) (*ai.SourceInsightEngine, func(), error) {
+	logger := ai.NewLogger(slog.LevelWarn)
+
+	chatModel, err := ai.NewChatModel(c.config.OllamaEndpoint, c.config.ChatModel)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	engine := ai.NewEngine(nil, nil, chatModel, logger)
+	engine.ContextBudget = c.config.ContextTokens
+	closeEngine := func() {}
+	if !withRAG {
+		return engine, closeEngine, nil
+	}
+
+	embedder, err := ai.NewEmbedder(c.config.OllamaEndpoint, c.config.EmbeddingModel)
+	if err != nil {
+		logger.Warn("创建向量模型失败，跳过代码检索", "error", err)
+		return engine, closeEngine, nil
+	}
+
+	connectCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	mc, err := ai.ConnectMilvus(connectCtx, c.config.MilvusEndpoint)
+	if err != nil {
+		logger.Warn("代码索引不可用，跳过代码检索", "error", err)
+		return engine, closeEngine, nil
+	}
+	if err := ai.CheckCodeIndex(ctx, mc, ai.CodeCollection, c.config.EmbeddingModel); err != nil {
+		logger.Warn("代码索引不可用，跳过代码检索", "error", err)
+		mc.Close()
+		return engine, closeEngine, nil
+	}
+
+	engine.MilvusClient = mc
+	engine.Embedder = embedder
+	engine.Filter.Scopes = c.config.ACL.Scopes
+	return engine, func() { mc.Close() }, nil
+}
+
+// readSnippet 读取问题所在行前后的代码，带行号并标记问题行
+func readSnippet(file string, line, contextLines int) (string, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("读取文件失败: %w", err)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	start := line - contextLines
+	if start < 1 {
+		start = 1
+	}
+	end := line + contextLines
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	var sb strings.Builder
+	for i := start; i <= end; i++ {
+		marker := "  "
+		if i == line {
+			marker = "> "
+		}
+		sb.WriteString(fmt.Sprintf("%s%4d | %s\n", marker, i, lines[i-1]))
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/tools"
+)
+
+// ExtractInterfaceCommand 接口抽取命令
+type ExtractInterfaceCommand struct {
+	toolManager *tools.ToolManager
+}
+
+// NewExtractInterfaceCommand 创建接口抽取命令
+func NewExtractInterfaceCommand(toolManager *tools.ToolManager) *ExtractInterfaceCommand {
+	return &ExtractInterfaceCommand{
+		toolManager: toolManager,
+	}
+}
+
+// Name 命令名称
+func (c *ExtractInterfaceCommand) Name() string {
+	return "extract-interface"
+}
+
+// Description 命令描述
+func (c *ExtractInterfaceCommand) Description() string {
+	return "为具体类型抽取接口并更新注入点（默认只输出 diff）"
+}
+
+// Run 执行命令
+// 用法: extract-interface <dir> --type <Type|pkg.Type> [--name Iface] [--write]
+func (c *ExtractInterfaceCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	fs := newFlagSet(c.Name())
+	typeName := fs.String("type", "", "具体类型（如 ai.SourceInsightEngine）")
+	name := fs.String("name", "", "接口名（默认 <Type>API）")
+	write := fs.Bool("write", false, "将修改写回文件")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+	if *typeName == "" {
+		return fmt.Errorf("需要通过 --type 指定具体类型")
+	}
+
+	dir := "."
+	if len(positional) > 0 {
+		dir = positional[0]
+	}
+
+	req := tools.InterfaceRequest{
+		Directory: dir,
+		Type:      *typeName,
+		Name:      *name,
+		Write:     *write,
+	}
+
+	result, err := c.toolManager.Run(ctx, "interface_extractor", req)
+	if err != nil {
+		return fmt.Errorf("抽取接口失败: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("抽取接口失败: %s", result.Error)
+	}
+
+	if output.Structured(formatter) {
+		fmt.Println(formatter.Format(result.Result))
+		return nil
+	}
+
+	var ifaceResult tools.InterfaceResult
+	if err := json.Unmarshal([]byte(result.Result), &ifaceResult); err != nil {
+		return fmt.Errorf("解析抽取结果失败: %w", err)
+	}
+
+	for _, patch := range ifaceResult.Patches {
+		fmt.Print(patch.Diff)
+	}
+	for _, point := range ifaceResult.Skipped {
+		fmt.Println(formatter.Format(fmt.Sprintf("⚠️ 保留具体类型 %s %s（%s:%d）: %s", point.Kind, point.Name, point.File, point.Line, point.Reason)))
+	}
+	fmt.Println(formatter.Format("✅ " + ifaceResult.Summary))
+	return nil
+}
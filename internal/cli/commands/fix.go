@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/tools"
+	"os"
+	"strings"
+)
+
+// FixCommand 自动修复命令
+type FixCommand struct {
+	toolManager *tools.ToolManager
+}
+
+// NewFixCommand 创建自动修复命令
+func NewFixCommand(toolManager *tools.ToolManager) *FixCommand {
+	return &FixCommand{
+		toolManager: toolManager,
+	}
+}
+
+// Name 命令名称
+func (c *FixCommand) Name() string {
+	return "fix"
+}
+
+// Description 命令描述
+func (c *FixCommand) Description() string {
+	return "为可修复的问题生成修复补丁（默认只输出 diff）"
+}
+
+// Run 执行命令
+// 用法: fix <path> [--write] [--rules defer-close,G302] [--mechanical-only]
+func (c *FixCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	fs := newFlagSet(c.Name())
+	write := fs.Bool("write", false, "将修复写回文件")
+	rules := fs.String("rules", "", "只执行指定修复（修复名或规则ID，逗号分隔）")
+	mechanicalOnly := fs.Bool("mechanical-only", false, "只执行确定性的 AST 修复，不调用 LLM")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+
+	target := "."
+	if len(positional) > 0 {
+		target = positional[0]
+	}
+
+	req := tools.FixRequest{Write: *write, MechanicalOnly: *mechanicalOnly}
+	info, err := os.Stat(target)
+	if err != nil {
+		return fmt.Errorf("路径不存在: %w", err)
+	}
+	if info.IsDir() {
+		req.Directory = target
+	} else {
+		req.Files = []string{target}
+	}
+	if *rules != "" {
+		for _, name := range strings.Split(*rules, ",") {
+			req.Fixes = append(req.Fixes, strings.TrimSpace(name))
+		}
+	}
+
+	result, err := c.toolManager.Run(ctx, "code_fixer", req)
+	if err != nil {
+		return fmt.Errorf("生成修复失败: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("生成修复失败: %s", result.Error)
+	}
+
+	if output.Structured(formatter) {
+		fmt.Println(formatter.Format(result.Result))
+		return nil
+	}
+
+	var fixResult tools.FixResult
+	if err := json.Unmarshal([]byte(result.Result), &fixResult); err != nil {
+		return fmt.Errorf("解析修复结果失败: %w", err)
+	}
+
+	for _, patch := range fixResult.Patches {
+		fmt.Print(patch.Diff)
+	}
+	for _, skipped := range fixResult.Skipped {
+		fmt.Println(formatter.Format("⚠️ 跳过: " + skipped))
+	}
+	fmt.Println(formatter.Format("✅ " + fixResult.Summary))
+	return nil
+}
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"flag"
+	"go-ai-study/internal/tools"
+	"io"
+	"strings"
+)
+
+// newFlagSet 创建命令专用的参数集（错误由调用方处理，不直接退出）
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parseFlags 解析命令参数，允许选项出现在位置参数前后
+// 返回剩余的位置参数
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		// 第一个非选项参数作为位置参数，继续解析其后的选项
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	return positional, nil
+}
+
+// splitList 拆分逗号分隔的选项值，忽略空项
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// failOnFlag 注册 --fail-on 选项，供 CI 按问题的严重程度决定是否失败
+func failOnFlag(fs *flag.FlagSet) *string {
+	return fs.String("fail-on", "", "存在不低于该严重程度的问题时以非零状态退出：Low、Medium、High、Critical")
+}
+
+// parseFailOn 校验 --fail-on 的值，未指定时返回空
+func parseFailOn(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	return tools.ParseSeverity(value)
+}
+
+// categoryFlag 注册 --category 选项，只报告指定类别的问题（bug、security、triage 共用同一套类别）
+func categoryFlag(fs *flag.FlagSet) *string {
+	return fs.String("category", "", "只报告这些类别的问题，逗号分隔："+strings.Join(tools.Categories, "、"))
+}
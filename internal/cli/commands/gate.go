@@ -0,0 +1,141 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/tools"
+	"os"
+	"strings"
+)
+
+// GateCommand 合并门禁命令
+type GateCommand struct {
+	toolManager *tools.ToolManager
+}
+
+// NewGateCommand 创建合并门禁命令
+func NewGateCommand(toolManager *tools.ToolManager) *GateCommand {
+	return &GateCommand{
+		toolManager: toolManager,
+	}
+}
+
+// Name 命令名称
+func (c *GateCommand) Name() string {
+	return "gate"
+}
+
+// Description 命令描述
+func (c *GateCommand) Description() string {
+	return "与目标分支的基线比较，新增问题按严重程度加权后超过预算时失败"
+}
+
+// Run 执行命令
+// 用法: gate [dir] [--target main | --baseline baseline.json] [--budget 0] [--weights Critical=10,High=5] [--write-baseline file]
+func (c *GateCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	fs := newFlagSet(c.Name())
+	target := fs.String("target", "main", "作为基线的目标分支或其他 git 引用")
+	baselineFile := fs.String("baseline", "", "从文件读取基线（--write-baseline 生成），代替 --target")
+	budget := fs.Float64("budget", 0, "允许的新增问题加权分")
+	weightSpec := fs.String("weights", "", "严重程度权重，如 Critical=10,High=5,Medium=2,Low=1")
+	writeBaseline := fs.String("write-baseline", "", "把当前的问题保存为基线文件")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+
+	dir := "."
+	if len(positional) > 0 {
+		dir = positional[0]
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("路径不存在: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("gate 需要一个目录: %s", dir)
+	}
+
+	weights, err := tools.ParseGateWeights(*weightSpec)
+	if err != nil {
+		return err
+	}
+
+	current, err := tools.CollectFindings(ctx, c.toolManager, dir)
+	if err != nil {
+		return err
+	}
+	tools.RelativizeFindings(current, dir)
+
+	if *writeBaseline != "" {
+		data, err := json.MarshalIndent(current, "", "  ")
+		if err != nil {
+			return fmt.Errorf("序列化基线失败: %w", err)
+		}
+		if err := os.WriteFile(*writeBaseline, data, 0644); err != nil {
+			return fmt.Errorf("写入基线失败: %w", err)
+		}
+		fmt.Println(formatter.Format(fmt.Sprintf("✅ 已保存 %d 个问题到基线 %s", len(current), *writeBaseline)))
+		return nil
+	}
+
+	var baseline []tools.Finding
+	if *baselineFile != "" {
+		data, err := os.ReadFile(*baselineFile)
+		if err != nil {
+			return fmt.Errorf("读取基线失败: %w", err)
+		}
+		if err := json.Unmarshal(data, &baseline); err != nil {
+			return fmt.Errorf("解析基线失败: %w", err)
+		}
+	} else {
+		baseline, err = tools.CollectBaselineFindings(ctx, c.toolManager, dir, *target)
+		if err != nil {
+			return err
+		}
+	}
+
+	result := tools.EvaluateGate(baseline, current, tools.GateOptions{Budget: *budget, Weights: weights})
+	if output.Structured(formatter) {
+		data, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("序列化门禁结果失败: %w", err)
+		}
+		fmt.Println(formatter.Format(string(data)))
+	} else {
+		fmt.Println(formatter.Format(formatGate(result, weights)))
+	}
+
+	if !result.Passed {
+		return fmt.Errorf("新增问题加权分 %.1f 超过预算 %.1f", result.Score, result.Budget)
+	}
+	return nil
+}
+
+// formatGate 生成文本报告：列出新增问题及其权重，再给出修复数和结论
+func formatGate(result *tools.GateResult, weights map[string]float64) string {
+	var sb strings.Builder
+	if len(result.New) > 0 {
+		sb.WriteString("🆕 新增问题\n")
+		for _, f := range result.New {
+			w, ok := weights[f.Severity]
+			if !ok {
+				w = weights["Low"]
+			}
+			sb.WriteString(fmt.Sprintf("  +%-4g %s  %-5s %-8s %s:%d  %s\n", w, f.Fingerprint, f.RuleID, f.Severity, f.File, f.Line, f.Description))
+		}
+		sb.WriteString("\n")
+	}
+	if len(result.Fixed) > 0 {
+		sb.WriteString(fmt.Sprintf("🎉 已修复 %d 个基线问题\n", len(result.Fixed)))
+	}
+	icon := "✅"
+	if !result.Passed {
+		icon = "❌"
+	}
+	sb.WriteString(fmt.Sprintf("%s %s", icon, result.Summary))
+	return sb.String()
+}
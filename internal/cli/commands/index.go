@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/ai"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/config"
+	"time"
+)
+
+// IndexCommand 代码索引管理命令
+type IndexCommand struct {
+	config *config.Config
+}
+
+// NewIndexCommand 创建代码索引管理命令
+func NewIndexCommand(cfg *config.Config) *IndexCommand {
+	return &IndexCommand{
+		config: cfg,
+	}
+}
+
+// Name 命令名称
+func (c *IndexCommand) Name() string {
+	return "index"
+}
+
+// Description 命令描述
+func (c *IndexCommand) Description() string {
+	return "管理代码索引（status 查看版本，export/import 导出导入索引归档）"
+}
+
+// Run 执行命令
+// 用法: index status [--module path] | index export <file.tar.zst> [--module path] | index import <file.tar.zst> [--module path]
+func (c *IndexCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	if len(args) == 0 {
+		return fmt.Errorf("需要指定子命令: status、export、import")
+	}
+	switch args[0] {
+	case "status":
+		fs := newFlagSet(c.Name() + " status")
+		module := fs.String("module", "", "查看 go.work 成员模块的索引（模块路径）")
+		if _, err := parseFlags(fs, args[1:]); err != nil {
+			return fmt.Errorf("参数解析失败: %w", err)
+		}
+		return c.status(ctx, ai.CodeCollectionName(*module), formatter)
+	case "export", "import":
+		fs := newFlagSet(c.Name() + " " + args[0])
+		module := fs.String("module", "", "导出/导入 go.work 成员模块的索引（模块路径）")
+		positional, err := parseFlags(fs, args[1:])
+		if err != nil {
+			return fmt.Errorf("参数解析失败: %w", err)
+		}
+		if len(positional) == 0 {
+			return fmt.Errorf("需要指定归档文件路径")
+		}
+		return c.transfer(ctx, args[0], ai.CodeCollectionName(*module), positional[0], formatter)
+	default:
+		return fmt.Errorf("未知子命令: %s（可用: status、export、import）", args[0])
+	}
+}
+
+// transfer 导出或导入索引归档；导入会替换现有索引
+func (c *IndexCommand) transfer(ctx context.Context, action, collection, path string, formatter output.Formatter) error {
+	connectCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	mc, err := ai.ConnectMilvus(connectCtx, c.config.MilvusEndpoint)
+	if err != nil {
+		return err
+	}
+	defer mc.Close()
+
+	var manifest *ai.IndexManifest
+	if action == "export" {
+		manifest, err = ai.ExportCodeIndex(ctx, mc, collection, path)
+	} else {
+		manifest, err = ai.ImportCodeIndex(ctx, mc, collection, path, c.config.EmbeddingModel)
+	}
+	if err != nil {
+		return err
+	}
+
+	if output.Structured(formatter) {
+		jsonBytes, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return fmt.Errorf("序列化索引元数据失败: %w", err)
+		}
+		fmt.Println(formatter.Format(string(jsonBytes)))
+		return nil
+	}
+	verb := "导出到"
+	if action == "import" {
+		verb = "导入自"
+	}
+	fmt.Println(formatter.Format(fmt.Sprintf("✅ 已%s %s: %d 个代码片段（表结构版本 %d，向量模型 %s）",
+		verb, path, manifest.Rows, manifest.SchemaVersion, manifest.EmbeddingModel)))
+	return nil
+}
+
+// indexStatus index status 的输出
+type indexStatus struct {
+	*ai.IndexInfo
+	CurrentSchemaVersion int      `json:"current_schema_version"`
+	CurrentModel         string   `json:"current_embedding_model"`
+	Mismatches           []string `json:"mismatches,omitempty"`
+}
+
+// status 显示代码索引的版本信息，以及与当前程序、配置是否一致
+func (c *IndexCommand) status(ctx context.Context, collection string, formatter output.Formatter) error {
+	connectCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	mc, err := ai.ConnectMilvus(connectCtx, c.config.MilvusEndpoint)
+	if err != nil {
+		return err
+	}
+	defer mc.Close()
+
+	info, err := ai.DescribeCodeIndex(ctx, mc, collection)
+	if err != nil {
+		return err
+	}
+	status := indexStatus{
+		IndexInfo:            info,
+		CurrentSchemaVersion: ai.CodeSchemaVersion,
+		CurrentModel:         c.config.EmbeddingModel,
+	}
+	if info.Exists {
+		status.Mismatches = info.Mismatches(c.config.EmbeddingModel)
+	}
+
+	if output.Structured(formatter) {
+		jsonBytes, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return fmt.Errorf("序列化索引状态失败: %w", err)
+		}
+		fmt.Println(formatter.Format(string(jsonBytes)))
+		return nil
+	}
+
+	if !info.Exists {
+		fmt.Println(formatter.Format("⚠️ 代码索引不存在，请先运行 scan 建立索引"))
+		return nil
+	}
+	fmt.Printf("集合: %s\n", collection)
+	fmt.Printf("表结构版本: %d（当前 %d）\n", info.SchemaVersion, ai.CodeSchemaVersion)
+	fmt.Printf("向量模型: %s（当前配置 %s）\n", info.EmbeddingModel, c.config.EmbeddingModel)
+	fmt.Printf("片段数: %d\n", info.Rows)
+	if len(status.Mismatches) == 0 {
+		fmt.Println(formatter.Format("✅ 索引与当前版本一致"))
+		return nil
+	}
+	for _, reason := range status.Mismatches {
+		fmt.Println(formatter.Format("⚠️ " + reason))
+	}
+	fmt.Println(formatter.Format("⚠️ 索引已过期，请运行 scan <path> --reindex 重建"))
+	return nil
+}
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/tools"
+	"os"
+	"strings"
+)
+
+// InventoryCommand 模块清单命令
+type InventoryCommand struct {
+	toolManager *tools.ToolManager
+}
+
+// NewInventoryCommand 创建模块清单命令
+func NewInventoryCommand(toolManager *tools.ToolManager) *InventoryCommand {
+	return &InventoryCommand{
+		toolManager: toolManager,
+	}
+}
+
+// Name 命令名称
+func (c *InventoryCommand) Name() string {
+	return "inventory"
+}
+
+// Description 命令描述
+func (c *InventoryCommand) Description() string {
+	return "汇总 go 版本、模块依赖、replace 指令和构建标签（JSON/Markdown）"
+}
+
+// Run 执行命令
+// 用法: inventory [dir] [--updates] [--proxy url] [--out inventory.md|inventory.json]
+func (c *InventoryCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	fs := newFlagSet(c.Name())
+	updates := fs.Bool("updates", false, "查询模块代理，检查依赖是否有可用更新")
+	proxy := fs.String("proxy", "", "模块代理地址（默认取 GOPROXY）")
+	out := fs.String("out", "", "写入文件，.json 后缀输出 JSON，其余输出 Markdown")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+
+	dir := "."
+	if len(positional) > 0 {
+		dir = positional[0]
+	}
+
+	result, err := c.toolManager.Run(ctx, "inventory", tools.InventoryRequest{
+		Directory:    dir,
+		CheckUpdates: *updates,
+		Proxy:        *proxy,
+	})
+	if err != nil {
+		return fmt.Errorf("生成模块清单失败: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("生成模块清单失败: %s", result.Error)
+	}
+
+	var inv tools.InventoryResult
+	if err := json.Unmarshal([]byte(result.Result), &inv); err != nil {
+		return fmt.Errorf("解析模块清单失败: %w", err)
+	}
+
+	if *out != "" {
+		content := tools.FormatInventoryMarkdown(&inv)
+		if strings.HasSuffix(*out, ".json") {
+			content = result.Result + "\n"
+		}
+		if err := os.WriteFile(*out, []byte(content), 0644); err != nil {
+			return fmt.Errorf("写入清单文件失败: %w", err)
+		}
+		fmt.Println(formatter.Format("✅ 模块清单已写入 " + *out))
+		return nil
+	}
+
+	if output.Structured(formatter) {
+		fmt.Println(formatter.Format(result.Result))
+		return nil
+	}
+	fmt.Print(tools.FormatInventoryMarkdown(&inv))
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/i18n"
 )
 
 // ListCommand 列出所有命令
@@ -32,9 +33,9 @@ func (c *ListCommand) Description() string {
 func (c *ListCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
 	commands := c.registry.List()
 
-	fmt.Println("可用命令:")
+	fmt.Println(i18n.T("help.commands"))
 	for _, cmd := range commands {
-		fmt.Printf("  %-12s %s\n", cmd.Name(), cmd.Description())
+		fmt.Printf("  %-12s %s\n", cmd.Name(), i18n.Text("help.cmd."+cmd.Name(), cmd.Description()))
 	}
 
 	return nil
@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/tools"
+	"os"
+	"strings"
+)
+
+// ModernizeCommand 现代化建议命令
+type ModernizeCommand struct {
+	toolManager *tools.ToolManager
+}
+
+// NewModernizeCommand 创建现代化建议命令
+func NewModernizeCommand(toolManager *tools.ToolManager) *ModernizeCommand {
+	return &ModernizeCommand{
+		toolManager: toolManager,
+	}
+}
+
+// Name 命令名称
+func (c *ModernizeCommand) Name() string {
+	return "modernize"
+}
+
+// Description 命令描述
+func (c *ModernizeCommand) Description() string {
+	return "找出已废弃的标准库用法和旧写法，生成升级 Go 版本的检查清单"
+}
+
+// Run 执行命令
+// 用法: modernize [path] [--go 1.21] [--rules M101,M102] [--fix] [--write]
+func (c *ModernizeCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	fs := newFlagSet(c.Name())
+	goVersion := fs.String("go", "", "按该 Go 版本判断可用的新写法（默认使用 go.mod 中的 go 指令）")
+	rules := fs.String("rules", "", "只检查指定规则（规则ID，逗号分隔）")
+	fix := fs.Bool("fix", false, "对可确定性修复的问题生成补丁")
+	write := fs.Bool("write", false, "与 --fix 一起使用，将修复写回文件")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+
+	target := "."
+	if len(positional) > 0 {
+		target = positional[0]
+	}
+
+	req := tools.ModernizeRequest{GoVersion: *goVersion}
+	info, err := os.Stat(target)
+	if err != nil {
+		return fmt.Errorf("路径不存在: %w", err)
+	}
+	if info.IsDir() {
+		req.Directory = target
+	} else {
+		req.Files = []string{target}
+	}
+	if *rules != "" {
+		for _, id := range strings.Split(*rules, ",") {
+			req.Rules = append(req.Rules, strings.TrimSpace(id))
+		}
+	}
+
+	result, err := c.toolManager.Run(ctx, "modernize", req)
+	if err != nil {
+		return fmt.Errorf("现代化检查失败: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("现代化检查失败: %s", result.Error)
+	}
+
+	var modern tools.ModernizeResult
+	if err := json.Unmarshal([]byte(result.Result), &modern); err != nil {
+		return fmt.Errorf("解析现代化检查结果失败: %w", err)
+	}
+
+	if *fix {
+		return c.runFixes(ctx, req, &modern, *write, formatter)
+	}
+
+	if output.Structured(formatter) {
+		fmt.Println(formatter.Format(result.Result))
+		return nil
+	}
+	fmt.Println(formatter.Format(formatModernize(&modern)))
+	return nil
+}
+
+// runFixes 只对检查出可修复问题的规则调用 code_fixer，并且只执行确定性修复
+func (c *ModernizeCommand) runFixes(ctx context.Context, req tools.ModernizeRequest, modern *tools.ModernizeResult, write bool, formatter output.Formatter) error {
+	fixReq := tools.FixRequest{Files: req.Files, Directory: req.Directory, Write: write, MechanicalOnly: true}
+	for _, item := range modern.Checklist {
+		if item.Fixable > 0 {
+			fixReq.Fixes = append(fixReq.Fixes, item.RuleID)
+		}
+	}
+	if len(fixReq.Fixes) == 0 {
+		fmt.Println(formatter.Format("✅ 没有可以自动修复的问题"))
+		return nil
+	}
+
+	result, err := c.toolManager.Run(ctx, "code_fixer", fixReq)
+	if err != nil {
+		return fmt.Errorf("生成修复失败: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("生成修复失败: %s", result.Error)
+	}
+
+	if output.Structured(formatter) {
+		fmt.Println(formatter.Format(result.Result))
+		return nil
+	}
+
+	var fixResult tools.FixResult
+	if err := json.Unmarshal([]byte(result.Result), &fixResult); err != nil {
+		return fmt.Errorf("解析修复结果失败: %w", err)
+	}
+	for _, patch := range fixResult.Patches {
+		fmt.Print(patch.Diff)
+	}
+	fmt.Println(formatter.Format("✅ " + fixResult.Summary))
+	return nil
+}
+
+// formatModernize 生成文本报告：先输出按规则汇总的检查清单，再列出每处位置
+func formatModernize(result *tools.ModernizeResult) string {
+	var sb strings.Builder
+	if len(result.Checklist) > 0 {
+		sb.WriteString("📋 升级检查清单\n")
+		for _, item := range result.Checklist {
+			sb.WriteString(fmt.Sprintf("  [ ] %s %s（%s 起）: %d 处", item.RuleID, item.Description, item.Since, item.Count))
+			if item.Fixable > 0 {
+				sb.WriteString(fmt.Sprintf("，其中 %d 处可用 --fix 自动修复", item.Fixable))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+	for _, f := range result.Findings {
+		sb.WriteString(fmt.Sprintf("  %s:%d  [%s] %s\n      → %s\n", f.File, f.Line, f.RuleID, f.Message, f.Suggestion))
+	}
+	for _, skipped := range result.Skipped {
+		sb.WriteString(fmt.Sprintf("⚠️ 跳过: %s\n", skipped))
+	}
+	sb.WriteString(fmt.Sprintf("✅ %s", result.Summary))
+	return sb.String()
+}
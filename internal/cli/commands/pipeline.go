@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/tools"
+	"strings"
+)
+
+// PipelineCommand 流水线命令
+// 按 YAML 定义依次执行工具，团队不改代码就能组合自己的审计流程
+type PipelineCommand struct {
+	toolManager *tools.ToolManager
+}
+
+// NewPipelineCommand 创建流水线命令
+func NewPipelineCommand(toolManager *tools.ToolManager) *PipelineCommand {
+	return &PipelineCommand{
+		toolManager: toolManager,
+	}
+}
+
+// Name 命令名称
+func (c *PipelineCommand) Name() string {
+	return "pipeline"
+}
+
+// Description 命令描述
+func (c *PipelineCommand) Description() string {
+	return "按 YAML 定义的步骤依次执行工具（输入、过滤、结果输出和失败条件）"
+}
+
+// Run 执行命令
+// 用法: pipeline run <pipeline.yaml> [dir] [--var key=value,...]
+// 步骤输入中的 ${dir} 替换为 dir（默认当前目录），${key} 替换为 --var 指定的值
+func (c *PipelineCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	fs := newFlagSet(c.Name())
+	varSpec := fs.String("var", "", "步骤输入中可用的变量，如 pkg=./internal,base=main")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+	if len(positional) < 2 || positional[0] != "run" {
+		return fmt.Errorf("用法: pipeline run <pipeline.yaml> [dir]")
+	}
+	p, err := tools.LoadPipeline(positional[1])
+	if err != nil {
+		return err
+	}
+
+	vars := map[string]string{"dir": "."}
+	if len(positional) > 2 {
+		vars["dir"] = positional[2]
+	}
+	for _, kv := range splitList(*varSpec) {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("变量格式应为 key=value: %s", kv)
+		}
+		vars[key] = value
+	}
+
+	result, err := tools.RunPipeline(ctx, c.toolManager, p, vars)
+	if err != nil {
+		return err
+	}
+	if output.Structured(formatter) {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("序列化流水线结果失败: %w", err)
+		}
+		fmt.Println(formatter.Format(string(data)))
+	} else {
+		fmt.Println(formatter.Format(formatPipeline(result)))
+	}
+
+	if !result.Passed {
+		return fmt.Errorf("流水线 %s 未通过", result.Name)
+	}
+	return nil
+}
+
+// formatPipeline 生成文本报告：每步一行，失败的步骤附上原因
+func formatPipeline(result *tools.PipelineResult) string {
+	var sb strings.Builder
+	for i, step := range result.Steps {
+		icon := "✅"
+		switch {
+		case step.Skipped:
+			icon = "⏭️"
+		case !step.Success:
+			icon = "❌"
+		}
+		sb.WriteString(fmt.Sprintf("%s %d. %s (%s)", icon, i+1, step.Name, step.Tool))
+		if !step.Skipped {
+			sb.WriteString(fmt.Sprintf("  %dms", step.Duration))
+		}
+		if step.Output != "" {
+			sb.WriteString(" → " + step.Output)
+		}
+		sb.WriteString("\n")
+		if step.Error != "" {
+			sb.WriteString("    " + step.Error + "\n")
+		}
+	}
+	icon := "✅"
+	if !result.Passed {
+		icon = "❌"
+	}
+	sb.WriteString(fmt.Sprintf("%s %s", icon, result.Summary))
+	return sb.String()
+}
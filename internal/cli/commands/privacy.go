@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/config"
+	"go-ai-study/internal/tools"
+	"strings"
+)
+
+// PrivacyCommand 隐私审计命令
+type PrivacyCommand struct {
+	toolManager *tools.ToolManager
+	config      *config.Config
+}
+
+// NewPrivacyCommand 创建隐私审计命令
+func NewPrivacyCommand(toolManager *tools.ToolManager, cfg *config.Config) *PrivacyCommand {
+	return &PrivacyCommand{
+		toolManager: toolManager,
+		config:      cfg,
+	}
+}
+
+// Name 命令名称
+func (c *PrivacyCommand) Name() string {
+	return "privacy"
+}
+
+// Description 命令描述
+func (c *PrivacyCommand) Description() string {
+	return "列出当前配置可能访问的所有网络地址（privacy audit）"
+}
+
+// Run 执行命令
+// 用法: privacy audit，--local-only 模式下存在非本机地址时返回错误
+func (c *PrivacyCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	if len(args) == 0 || args[0] != "audit" {
+		return fmt.Errorf("需要指定子命令: audit")
+	}
+
+	result, err := c.toolManager.Run(ctx, "privacy_audit", tools.PrivacyAuditRequest{LocalOnly: c.config.LocalOnly})
+	if err != nil {
+		return fmt.Errorf("隐私审计失败: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("隐私审计失败: %s", result.Error)
+	}
+
+	var audit tools.PrivacyAuditResult
+	if err := json.Unmarshal([]byte(result.Result), &audit); err != nil {
+		return fmt.Errorf("解析隐私审计结果失败: %w", err)
+	}
+
+	if output.Structured(formatter) {
+		fmt.Println(formatter.Format(result.Result))
+	} else {
+		fmt.Println(formatter.Format(formatPrivacyAudit(&audit)))
+	}
+
+	if len(audit.Violations) > 0 {
+		return fmt.Errorf("--local-only 模式下存在 %d 个非本机地址", len(audit.Violations))
+	}
+	return nil
+}
+
+// formatPrivacyAudit 格式化隐私审计结果
+func formatPrivacyAudit(audit *tools.PrivacyAuditResult) string {
+	var b strings.Builder
+	b.WriteString("📝 可能访问的网络地址\n")
+	for _, dest := range audit.Destinations {
+		switch {
+		case dest.Endpoint == "":
+			b.WriteString(fmt.Sprintf("  [已关闭] %s", dest.Name))
+		case dest.Local:
+			b.WriteString(fmt.Sprintf("  [本机]   %s: %s", dest.Name, dest.Endpoint))
+		default:
+			b.WriteString(fmt.Sprintf("  [外部]   %s: %s", dest.Name, dest.Endpoint))
+		}
+		if dest.Note != "" {
+			b.WriteString(fmt.Sprintf("（%s）", dest.Note))
+		}
+		b.WriteString("\n")
+		if dest.Endpoint != "" && len(dest.UsedBy) > 0 {
+			b.WriteString(fmt.Sprintf("           使用方: %s\n", strings.Join(dest.UsedBy, "、")))
+		}
+	}
+	for _, v := range audit.Violations {
+		b.WriteString(fmt.Sprintf("⚠️ --local-only 不允许: %s\n", v))
+	}
+	if len(audit.Violations) == 0 && audit.Remote == 0 {
+		b.WriteString("✅ " + audit.Summary)
+	} else {
+		b.WriteString("📝 " + audit.Summary)
+	}
+	return b.String()
+}
@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/config"
+	"go-ai-study/internal/tools"
+	"os"
+)
+
+// resolveTarget 把命令的路径参数解析为本地目录
+// 远程仓库地址（如 https://github.com/org/repo@v1.2.3）先浅克隆到临时目录，cleanup 删除该目录；
+// 本地路径原样返回，cleanup 为空操作
+func resolveTarget(ctx context.Context, target string, cfg *config.Config, formatter output.Formatter) (dir string, cleanup func(), err error) {
+	repo, ok := tools.ParseRemoteRepo(target)
+	if !ok {
+		return target, func() {}, nil
+	}
+	if host := repo.Host(); cfg.LocalOnly && host != "" && !tools.IsLocalHost(host) {
+		return "", nil, fmt.Errorf("--local-only 模式下不能克隆远程仓库: %s", repo)
+	}
+
+	// 进度写到标准错误，不混进 -f json 等结构化输出
+	fmt.Fprintln(os.Stderr, formatter.Format(fmt.Sprintf("📥 正在克隆 %s ...", repo)))
+	return tools.CloneRemoteRepo(ctx, repo)
+}
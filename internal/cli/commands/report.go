@@ -0,0 +1,30 @@
+package commands
+
+import (
+	"fmt"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/tools"
+	"os"
+)
+
+// writeHTMLReport 把命令的 JSON 结果渲染为 HTML 报告写入 path（--report）
+func writeHTMLReport(path, command, result string, formatter output.Formatter) error {
+	html := output.NewHTMLFormatter(command).Format(result)
+	if err := os.WriteFile(path, []byte(html), 0644); err != nil {
+		return fmt.Errorf("写入 HTML 报告失败: %w", err)
+	}
+	fmt.Println(formatter.Format(fmt.Sprintf("📄 HTML 报告已写入 %s", path)))
+	return nil
+}
+
+// checkFailOn 按 --fail-on 检查工具结果，存在达到阈值的问题时返回错误
+func checkFailOn(tool, result, threshold string) error {
+	if threshold == "" {
+		return nil
+	}
+	severities, err := tools.ResultSeverities(tool, result)
+	if err != nil {
+		return err
+	}
+	return tools.CheckFailOn(severities, threshold)
+}
@@ -2,16 +2,34 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"go-ai-study/internal/ai"
 	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/config"
+	"go-ai-study/internal/tools"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
 )
 
 // ScanCommand 扫描命令
-type ScanCommand struct{}
+type ScanCommand struct {
+	toolManager *tools.ToolManager
+	config      *config.Config
+}
 
 // NewScanCommand 创建扫描命令
-func NewScanCommand() *ScanCommand {
-	return &ScanCommand{}
+func NewScanCommand(toolManager *tools.ToolManager, cfg *config.Config) *ScanCommand {
+	return &ScanCommand{
+		toolManager: toolManager,
+		config:      cfg,
+	}
 }
 
 // Name 命令名称
@@ -25,17 +43,314 @@ func (c *ScanCommand) Description() string {
 }
 
 // Run 执行命令
+// 用法: scan <path> [--no-metrics] [--no-git] [--no-docs] [--history] [--history-limit 500] [--summaries] [--reindex] [--deps module1,module2/pkg] | scan [path] --explain <file>
 func (c *ScanCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
-	if len(args) == 0 {
+	fs := newFlagSet(c.Name())
+	noMetrics := fs.Bool("no-metrics", false, "不计算复杂度和问题数（不写入标量字段）")
+	noGit := fs.Bool("no-git", false, "不读取 git 信息（片段最后一次修改的作者和时间）")
+	noDocs := fs.Bool("no-docs", c.config.Docs.Disabled, "不索引项目文档（README、docs/、ADR），默认取配置 docs.disabled")
+	history := fs.Bool("history", false, "同时把提交说明和改动摘要索引到提交历史集合，用于回答为什么改、什么时候改的")
+	historyLimit := fs.Int("history-limit", tools.DefaultHistoryLimit, "--history 读取的最近提交数")
+	reindex := fs.Bool("reindex", false, "删除现有索引并按当前表结构和向量模型重建")
+	summaries := fs.Bool("summaries", false, "用对话模型为每个片段生成摘要，与代码一起向量化（较慢）")
+	deps := fs.String("deps", strings.Join(c.config.IndexDependencies, ","), "同时索引这些依赖的源码（模块或包路径，逗号分隔），默认取配置 index_dependencies")
+	explain := fs.String("explain", "", "只输出该文件的切分结果（片段边界、大小、元数据），不建立索引")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+	if *explain != "" {
+		// 受限路径相对扫描根目录匹配，没有给出路径时以文件所在目录为根
+		root := filepath.Dir(*explain)
+		if len(positional) > 0 {
+			root = positional[0]
+		}
+		return c.explain(*explain, root, formatter)
+	}
+	if len(positional) == 0 {
 		return fmt.Errorf("需要指定路径")
 	}
+	target := positional[0]
+
+	// go.work 的每个成员模块是独立的分析单元，各自建索引集合，避免不同模块的片段混在一起
+	work, err := tools.LoadGoWork(target)
+	if err != nil {
+		return err
+	}
+	units := []*scanUnit{{dir: target}}
+	if work != nil {
+		ctx = tools.WithGoWork(ctx, work)
+		units = units[:0]
+		for _, m := range work.Modules {
+			units = append(units, &scanUnit{dir: m.Dir, module: m.Path})
+		}
+		fmt.Println(formatter.Format(fmt.Sprintf("📦 检测到 go.work，%d 个成员模块分别建立索引", len(units))))
+	}
+
+	for _, unit := range units {
+		if err := c.prepare(ctx, unit, work, !*noMetrics, !*noGit, !*noDocs, *summaries, formatter); err != nil {
+			return err
+		}
+		if err := c.prepareDependencies(ctx, unit, splitList(*deps), formatter); err != nil {
+			return err
+		}
+	}
+
+	embedder, err := ai.NewEmbedder(c.config.OllamaEndpoint, c.config.EmbeddingModel)
+	if err != nil {
+		return err
+	}
+	connectCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	mc, err := ai.ConnectMilvus(connectCtx, c.config.MilvusEndpoint)
+	if err != nil {
+		return err
+	}
+	defer mc.Close()
+
+	for _, unit := range units {
+		collection := ai.CodeCollectionName(unit.module)
+		// 表结构或向量模型变化后旧索引无法继续使用，需要显式 --reindex 重建
+		if *reindex {
+			fmt.Println(formatter.Format(fmt.Sprintf("⚠️ 正在删除旧索引 %s 并重建", collection)))
+			if err := ai.ResetCodeCollection(ctx, mc, collection, c.config.EmbeddingModel); err != nil {
+				return err
+			}
+		} else if err := ai.EnsureCodeCollection(ctx, mc, collection, c.config.EmbeddingModel); err != nil {
+			return err
+		}
+		if err := ai.IndexDocs(ctx, mc, collection, embedder, unit.chunks); err != nil {
+			return fmt.Errorf("写入索引失败: %w", err)
+		}
+
+		label := ""
+		if unit.module != "" {
+			label = fmt.Sprintf("模块 %s（集合 %s）: ", unit.module, collection)
+		}
+		summary := fmt.Sprintf("✅ %s已索引 %d 个文件，%d 个代码片段", label, unit.files, len(unit.chunks)-unit.docChunks)
+		if unit.docs > 0 {
+			summary += fmt.Sprintf("；%d 个文档，%d 个文档片段", unit.docs, unit.docChunks)
+		}
+		fmt.Println(formatter.Format(summary))
+	}
+
+	if *history {
+		return c.indexHistory(ctx, mc, embedder, target, *historyLimit, formatter)
+	}
+	return nil
+}
+
+// indexHistory 读取最近的提交并重建提交历史集合
+func (c *ScanCommand) indexHistory(ctx context.Context, mc client.Client, embedder embeddings.Embedder, dir string, limit int, formatter output.Formatter) error {
+	commits, err := tools.ReadCommitHistory(ctx, dir, limit)
+	if err != nil {
+		return err
+	}
+	if err := ai.ResetCommitCollection(ctx, mc, c.config.EmbeddingModel); err != nil {
+		return err
+	}
+	fmt.Printf("正在为 %d 次提交生成向量...\n", len(commits))
+	if err := ai.IndexCommits(ctx, mc, embedder, commits); err != nil {
+		return fmt.Errorf("写入提交历史失败: %w", err)
+	}
+	fmt.Println(formatter.Format(fmt.Sprintf("✅ 已索引 %d 次提交（集合 %s）", len(commits), ai.CommitCollection)))
+	return nil
+}
+
+// explain 输出单个文件的切分结果，ACL 标签与 scan 一致
+func (c *ScanCommand) explain(file, root string, formatter output.Formatter) error {
+	result, err := tools.ExplainChunks(file, ai.NewCodeSplitter(), func(chunks []schema.Document) {
+		tools.AnnotateChunkACL(chunks, root, c.config.ACL.Restricted)
+	})
+	if err != nil {
+		return err
+	}
+
+	if output.Structured(formatter) {
+		jsonBytes, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("序列化切分结果失败: %w", err)
+		}
+		fmt.Println(formatter.Format(string(jsonBytes)))
+		return nil
+	}
+
+	mode := "按函数切分"
+	if !result.Parsed {
+		mode = "解析失败，按固定行数切分"
+	}
+	fmt.Println(formatter.Format(fmt.Sprintf("📄 %s: %d 行，%s为 %d 个片段", result.File, result.Lines, mode, len(result.Chunks))))
+	for _, chunk := range result.Chunks {
+		lines := "位置未知"
+		if chunk.StartLine > 0 {
+			lines = fmt.Sprintf("L%d-%d", chunk.StartLine, chunk.EndLine)
+		}
+		fn := ""
+		if chunk.Function != "" {
+			fn = fmt.Sprintf("  %s（函数 L%d-%d）", chunk.Function, chunk.FuncStart, chunk.FuncEnd)
+		}
+		fmt.Printf("  #%-3d %-12s %4d 行 %6d 字节%s\n", chunk.Index, lines, chunk.Lines, chunk.Bytes, fn)
+		if chunk.Preview != "" {
+			fmt.Printf("        %s\n", chunk.Preview)
+		}
+		for _, warning := range chunk.Warnings {
+			fmt.Printf("        ⚠️ %s\n", warning)
+		}
+	}
+	if len(result.Uncovered) > 0 {
+		fmt.Println(formatter.Format(fmt.Sprintf("⚠️ %d 个顶层声明不在任何片段中，检索不到:", len(result.Uncovered))))
+		for _, decl := range result.Uncovered {
+			fmt.Printf("  L%d %s %s\n", decl.Line, decl.Kind, strings.Join(decl.Names, ", "))
+		}
+	}
+	return nil
+}
+
+// prepareDependencies 从模块缓存读取依赖的源码并切分，片段标记为 dependency=true
+// 依赖只读取、不分析，不写入复杂度和问题数；引用路径为 module@version/文件
+func (c *ScanCommand) prepareDependencies(ctx context.Context, unit *scanUnit, patterns []string, formatter output.Formatter) error {
+	sources, err := tools.ResolveDependencySources(ctx, unit.dir, patterns)
+	if err != nil {
+		return err
+	}
+	for _, dep := range sources {
+		files, err := dep.GoFiles(ctx)
+		if err != nil {
+			return err
+		}
+		docs := ai.LoadCode(files)
+		for _, doc := range docs {
+			source, _ := doc.Metadata[ai.MetaSource].(string)
+			doc.Metadata[ai.MetaSource] = dep.Source(filepath.FromSlash(source))
+			doc.Metadata[ai.MetaDependency] = true
+		}
+		chunks, err := ai.NewCodeSplitter().SplitDocuments(docs)
+		if err != nil {
+			return fmt.Errorf("代码分块失败: %w", err)
+		}
+		unit.chunks = append(unit.chunks, chunks...)
+		fmt.Println(formatter.Format(fmt.Sprintf("📚 依赖 %s@%s: %d 个文件，%d 个代码片段", dep.Pattern, dep.Version, len(docs), len(chunks))))
+	}
+	return nil
+}
+
+// scanUnit 一个分析单元：普通目录，或 go.work 的一个成员模块
+type scanUnit struct {
+	dir    string
+	module string // 成员模块路径，普通目录为空
+	files  int
+	chunks []schema.Document
+
+	docs      int // 项目文档数
+	docChunks int // chunks 中文档片段的个数
+}
+
+// prepare 扫描并切分一个分析单元，按需加入项目文档，写入分析指标、git 信息、ACL 标签和摘要
+func (c *ScanCommand) prepare(ctx context.Context, unit *scanUnit, work *tools.GoWork, metrics, gitInfo, withDocs, summaries bool, formatter output.Formatter) error {
+	// 文件经由配置的文件发现方式获取（默认遍历文件系统，也可以由 bazel query 或文件列表给出）
+	files, err := tools.DiscoverGoFiles(ctx, unit.dir)
+	if err != nil {
+		return fmt.Errorf("扫描源码失败: %w", err)
+	}
+	docs := ai.LoadCode(files)
+	// 嵌套的成员模块单独索引，从外层模块中去掉
+	if work != nil {
+		kept := docs[:0]
+		for _, doc := range docs {
+			source, _ := doc.Metadata[ai.MetaSource].(string)
+			if m := work.ModuleFor(filepath.FromSlash(source)); m != nil && m.Path == unit.module {
+				kept = append(kept, doc)
+			}
+		}
+		docs = kept
+	}
+	unit.files = len(docs)
 
-	target := args[0]
+	chunks, err := ai.NewCodeSplitter().SplitDocuments(docs)
+	if err != nil {
+		return fmt.Errorf("代码分块失败: %w", err)
+	}
+	if len(chunks) == 0 {
+		return fmt.Errorf("%s 下没有可索引的 Go 代码", unit.dir)
+	}
+	unit.chunks = chunks
+
+	// 文档片段与代码片段存入同一个集合，git 信息和 ACL 标签一样适用；指标和摘要只针对代码
+	if withDocs {
+		docChunks, err := c.prepareDocs(ctx, unit, work)
+		if err != nil {
+			return err
+		}
+		unit.chunks = append(chunks[:len(chunks):len(chunks)], docChunks...)
+		unit.docChunks = len(docChunks)
+	}
+
+	// 分析器指标作为标量字段入库，检索时可以按复杂度、问题数过滤
+	if metrics {
+		findings, err := tools.CollectFindings(ctx, c.toolManager, unit.dir)
+		if err != nil {
+			return fmt.Errorf("收集分析问题失败: %w", err)
+		}
+		tools.AnnotateChunkMetrics(chunks, findings)
+	}
+
+	// 最后一次修改的作者和时间让 "谁最后改了重试逻辑" 这类问题有据可查
+	if gitInfo {
+		count, err := tools.AnnotateChunkGit(ctx, unit.chunks)
+		if err != nil {
+			return fmt.Errorf("读取 git 信息失败: %w", err)
+		}
+		if count > 0 {
+			fmt.Println(formatter.Format(fmt.Sprintf("📝 %d 个代码片段记录了 git 最后修改信息", count)))
+		}
+	}
 
-	// TODO: 实现代码扫描和存储逻辑
-	// 这里需要调用向量数据库和嵌入模型
-	textFormatter := output.NewTextFormatter(output.Options{})
-	fmt.Println(textFormatter.FormatError(fmt.Errorf("扫描功能暂未实现，待后续开发: %s", target)))
+	// 受限路径下的片段带上 ACL 标签，访问范围不足的检索方看不到这些片段
+	if tagged := tools.AnnotateChunkACL(unit.chunks, unit.dir, c.config.ACL.Restricted); tagged > 0 {
+		fmt.Println(formatter.Format(fmt.Sprintf("📝 %d 个代码片段标记为受限", tagged)))
+	}
 
+	// 摘要向量让自然语言提问也能命中与标识符没有共同词的代码
+	if summaries {
+		chatModel, err := ai.NewChatModel(c.config.OllamaEndpoint, c.config.ChatModel)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("正在为 %d 个代码片段生成摘要...\n", len(chunks))
+		count, err := ai.SummarizeChunks(ctx, chatModel, chunks, ai.NewLogger(slog.LevelWarn))
+		if err != nil {
+			return fmt.Errorf("生成摘要失败: %w", err)
+		}
+		fmt.Println(formatter.Format(fmt.Sprintf("📝 已生成 %d/%d 条摘要", count, len(chunks))))
+	}
 	return nil
 }
+
+// prepareDocs 收集 README、docs/、ADR 等项目文档并按标题切分，片段带上文档类型
+// go.work 中嵌套的成员模块的文档归该模块索引
+func (c *ScanCommand) prepareDocs(ctx context.Context, unit *scanUnit, work *tools.GoWork) ([]schema.Document, error) {
+	files, err := tools.DiscoverDocFiles(ctx, unit.dir)
+	if err != nil {
+		return nil, fmt.Errorf("扫描项目文档失败: %w", err)
+	}
+	var docs []schema.Document
+	for _, file := range files {
+		if work != nil {
+			if m := work.ModuleFor(file.Path); m == nil || m.Path != unit.module {
+				continue
+			}
+		}
+		for _, doc := range ai.LoadCode([]string{file.Path}) {
+			doc.Metadata[ai.MetaDocType] = file.Type
+			docs = append(docs, doc)
+		}
+	}
+	unit.docs = len(docs)
+
+	chunks, err := ai.NewMarkdownSplitter().SplitDocuments(docs)
+	if err != nil {
+		return nil, fmt.Errorf("文档分块失败: %w", err)
+	}
+	return chunks, nil
+}
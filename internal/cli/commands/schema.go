@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/tools"
+)
+
+// SchemaCommand 工具输入 JSON Schema 导出命令
+type SchemaCommand struct {
+	toolManager *tools.ToolManager
+}
+
+// NewSchemaCommand 创建工具输入 JSON Schema 导出命令
+func NewSchemaCommand(toolManager *tools.ToolManager) *SchemaCommand {
+	return &SchemaCommand{
+		toolManager: toolManager,
+	}
+}
+
+// Name 命令名称
+func (c *SchemaCommand) Name() string {
+	return "schema"
+}
+
+// Description 命令描述
+func (c *SchemaCommand) Description() string {
+	return "导出工具输入参数的 JSON Schema（用于 LLM 函数调用和外部集成）"
+}
+
+// Run 执行命令
+// 用法: schema [tool]，不指定工具时导出全部已启用的工具
+func (c *SchemaCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	var value any
+	if len(args) > 0 {
+		schema, err := c.toolManager.Schema(args[0])
+		if err != nil {
+			return fmt.Errorf("获取工具 %s 失败: %w", args[0], err)
+		}
+		value = schema
+	} else {
+		value = c.toolManager.Schemas()
+	}
+
+	// Schema 本身就是 JSON，文本和 JSON 格式都直接输出
+	jsonBytes, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化 JSON Schema 失败: %w", err)
+	}
+	fmt.Println(string(jsonBytes))
+	return nil
+}
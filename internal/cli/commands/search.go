@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/ai"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/config"
+	"strings"
+	"time"
+)
+
+// SearchCommand 代码检索命令
+type SearchCommand struct {
+	config *config.Config
+}
+
+// NewSearchCommand 创建代码检索命令
+func NewSearchCommand(cfg *config.Config) *SearchCommand {
+	return &SearchCommand{
+		config: cfg,
+	}
+}
+
+// Name 命令名称
+func (c *SearchCommand) Name() string {
+	return "search"
+}
+
+// Description 命令描述
+func (c *SearchCommand) Description() string {
+	return "语义检索已索引的代码，可按复杂度和问题数过滤"
+}
+
+// Run 执行命令
+// 用法: search <query> [--min-complexity N] [--min-findings N] [--risky] [--top 5] [--file path] [--scope a,b] [--module path] [--deps include|exclude|only] [--doc-ratio 0.25]
+func (c *SearchCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	fs := newFlagSet(c.Name())
+	minComplexity := fs.Int("min-complexity", 0, "只返回圈复杂度不低于该值的函数")
+	minFindings := fs.Int("min-findings", 0, "只返回分析问题数不少于该值的函数")
+	risky := fs.Bool("risky", false, "在语义相关的结果中按风险（问题数、复杂度）排序")
+	top := fs.Int("top", 5, "返回结果数")
+	file := fs.String("file", "", "限定源文件")
+	scope := fs.String("scope", strings.Join(c.config.ACL.Scopes, ","), "访问范围（逗号分隔的 ACL 标签，* 表示全部），默认取配置 acl.scopes")
+	module := fs.String("module", "", "检索 go.work 成员模块的索引（模块路径），默认检索单模块索引")
+	deps := fs.String("deps", ai.DependenciesInclude, "依赖源码 (include|exclude|only)：一起检索、排除或只检索 scan --deps 索引的依赖")
+	docRatio := fs.Float64("doc-ratio", c.config.Docs.Ratio, "结果中项目文档片段（README、docs/、ADR）最多占的比例（0-1），0 只检索代码，默认取配置 docs.ratio")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+	if len(positional) == 0 {
+		return fmt.Errorf("需要指定检索内容")
+	}
+	query := strings.Join(positional, " ")
+	switch *deps {
+	case ai.DependenciesInclude, ai.DependenciesExclude, ai.DependenciesOnly:
+	default:
+		return fmt.Errorf("--deps 只支持 include、exclude、only，实际为 %q", *deps)
+	}
+	if *docRatio < 0 || *docRatio > 1 {
+		return fmt.Errorf("--doc-ratio 应在 0 到 1 之间，实际为 %g", *docRatio)
+	}
+
+	embedder, err := ai.NewEmbedder(c.config.OllamaEndpoint, c.config.EmbeddingModel)
+	if err != nil {
+		return err
+	}
+	connectCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	mc, err := ai.ConnectMilvus(connectCtx, c.config.MilvusEndpoint)
+	if err != nil {
+		return err
+	}
+	defer mc.Close()
+	collection := ai.CodeCollectionName(*module)
+	if err := ai.CheckCodeIndex(ctx, mc, collection, c.config.EmbeddingModel); err != nil {
+		return err
+	}
+
+	filter := ai.SearchFilter{
+		Source:        *file,
+		MinComplexity: *minComplexity,
+		MinFindings:   *minFindings,
+		Scopes:        splitList(*scope),
+		Collection:    collection,
+		Dependencies:  *deps,
+		DocRatio:      *docRatio,
+	}
+	limit := *top
+	if *risky {
+		limit *= 4 // 先取更多语义相关的候选，再按风险排序截断
+	}
+	hits, err := ai.SearchCode(ctx, mc, embedder, query, filter, limit)
+	if err != nil {
+		return err
+	}
+	if *risky {
+		ai.SortByRisk(hits)
+		if len(hits) > *top {
+			hits = hits[:*top]
+		}
+	}
+
+	if output.Structured(formatter) {
+		jsonBytes, err := json.MarshalIndent(hits, "", "  ")
+		if err != nil {
+			return fmt.Errorf("序列化检索结果失败: %w", err)
+		}
+		fmt.Println(formatter.Format(string(jsonBytes)))
+		return nil
+	}
+
+	if len(hits) == 0 {
+		fmt.Println(formatter.Format("⚠️ 没有找到匹配的代码"))
+		return nil
+	}
+	for i, hit := range hits {
+		if hit.DocType != "" {
+			fmt.Printf("%d. [文档/%s] %s  (相似度 %.3f)\n", i+1, hit.DocType, hit.Location(), hit.Score)
+			if hit.Section != "" {
+				fmt.Printf("   章节: %s\n", hit.Section)
+			}
+		} else if hit.Dependency {
+			fmt.Printf("%d. [依赖] %s  (相似度 %.3f)\n", i+1, hit.Location(), hit.Score)
+		} else {
+			fmt.Printf("%d. %s  (相似度 %.3f，复杂度 %d，问题 %d)\n", i+1, hit.Location(), hit.Score, hit.Complexity, hit.Findings)
+		}
+		if hit.Summary != "" {
+			fmt.Printf("   摘要: %s\n", hit.Summary)
+		}
+		if modified := hit.LastModified(); modified != "" {
+			fmt.Printf("   最后修改: %s\n", modified)
+		}
+		fmt.Println(truncateHit(hit.NumberedContent(), 12))
+		fmt.Println()
+	}
+	return nil
+}
+
+// truncateHit 截断过长的片段，只显示前几行
+func truncateHit(content string, maxLines int) string {
+	lines := strings.Split(content, "\n")
+	if len(lines) <= maxLines {
+		return content
+	}
+	return strings.Join(lines[:maxLines], "\n") + fmt.Sprintf("\n    ...（共 %d 行）", len(lines))
+}
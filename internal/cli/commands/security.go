@@ -2,10 +2,15 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/i18n"
 	"go-ai-study/internal/tools"
+	"maps"
 	"os"
+	"slices"
+	"strings"
 )
 
 // SecurityCommand 安全扫描命令
@@ -31,21 +36,55 @@ func (c *SecurityCommand) Description() string {
 }
 
 // Run 执行命令
+// 用法: security <file...|dir|dir/...> [--min-confidence high|medium|low] [--category Injection,...] [--no-group] [--max-locations 5] [--report out.html] [--fail-on High]
 func (c *SecurityCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
-	if len(args) == 0 {
+	fs := newFlagSet(c.Name())
+	minConfidence := fs.String("min-confidence", "", "只报告不低于该置信度的问题：high、medium、low")
+	category := categoryFlag(fs)
+	noGroup := fs.Bool("no-group", false, "扫描目录时逐条列出问题，不合并多个文件中相同的代码")
+	maxLocations := fs.Int("max-locations", 5, "每组问题最多列出的位置数（0 表示全部）")
+	report := fs.String("report", "", "同时把结果写成 HTML 报告")
+	failOn := failOnFlag(fs)
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+	threshold, err := parseFailOn(*failOn)
+	if err != nil {
+		return err
+	}
+	categories, err := tools.ParseCategories(*category)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
 		return fmt.Errorf("需要指定路径或文件")
 	}
 
-	target := args[0]
-
-	// 读取文件内容
-	content, err := os.ReadFile(target)
+	// 与 go 命令一致，dir/... 表示 dir 及其所有子目录（目录扫描本来就是递归的）
+	target := patternDir(positional[0])
+	info, err := os.Stat(target)
 	if err != nil {
-		return fmt.Errorf("读取文件失败: %w", err)
+		return fmt.Errorf("读取路径失败: %w", err)
+	}
+	if info.IsDir() {
+		if len(positional) > 1 {
+			return fmt.Errorf("扫描目录时只能指定一个路径")
+		}
+		return c.scanDirectory(ctx, target, *minConfidence, categories, *noGroup, *maxLocations, *report, threshold, formatter)
 	}
 
-	// 执行安全扫描
-	securityResult, err := c.toolManager.Run(ctx, "security_scanner", string(content))
+	// 执行安全扫描：单个文件按代码扫描，多个文件汇总为一个结果
+	input := tools.SecurityScanInput{Files: positional, MinConfidence: *minConfidence, Categories: categories}
+	if len(positional) == 1 {
+		content, err := os.ReadFile(target)
+		if err != nil {
+			return fmt.Errorf("读取文件失败: %w", err)
+		}
+		input = tools.SecurityScanInput{Code: string(content), File: target, MinConfidence: *minConfidence, Categories: categories}
+	}
+	securityResult, err := c.toolManager.Run(ctx, "security_scanner", input)
 	if err != nil {
 		return fmt.Errorf("安全扫描失败: %w", err)
 	}
@@ -53,5 +92,142 @@ func (c *SecurityCommand) Run(ctx context.Context, args []string, formatter outp
 	// 输出结果
 	fmt.Println(formatter.Format(securityResult.Result))
 
+	if *report != "" {
+		if err := writeHTMLReport(*report, c.Name(), securityResult.Result, formatter); err != nil {
+			return err
+		}
+	}
+	return checkFailOn("security_scanner", securityResult.Result, threshold)
+}
+
+// patternDir 把 go 包模式 dir/... 转为目录，其他路径原样返回
+func patternDir(path string) string {
+	if path == "..." {
+		return "."
+	}
+	if dir, ok := strings.CutSuffix(path, "/..."); ok {
+		if dir == "" {
+			return "/"
+		}
+		return dir
+	}
+	return path
+}
+
+// securityGroupReport 目录扫描的分组报告
+type securityGroupReport struct {
+	Directory string               `json:"directory"`
+	Total     int                  `json:"total"`               // 问题总数（含超出数量上限没有列出的问题）
+	Groups    []tools.FindingGroup `json:"groups"`              // 合并后的问题组
+	Truncated *tools.Truncation    `json:"truncated,omitempty"` // 超出数量上限没有列出的问题
+}
+
+// scanDirectory 逐个文件扫描目录，默认把多个文件中相同的问题代码合并为一组
+func (c *SecurityCommand) scanDirectory(ctx context.Context, dir, minConfidence string, categories []string, noGroup bool, maxLocations int, reportPath, threshold string, formatter output.Formatter) error {
+	if minConfidence != "" && minConfidence != tools.ConfidenceHigh && minConfidence != tools.ConfidenceMedium && minConfidence != tools.ConfidenceLow {
+		return fmt.Errorf("未知的置信度 %q（可选 high、medium、low）", minConfidence)
+	}
+	findings, truncated, err := tools.CollectSecurityFindings(ctx, c.toolManager, dir, minConfidence)
+	if err != nil {
+		return err
+	}
+	findings = tools.FilterFindingsByCategory(findings, categories)
+	severities := make([]string, len(findings))
+	for i, f := range findings {
+		severities[i] = f.Severity
+	}
+	severities = append(severities, truncated.Severities()...)
+
+	jsonOutput := output.Structured(formatter)
+	if noGroup {
+		if jsonOutput {
+			if err := printSecurityJSON(formatter, findings); err != nil {
+				return err
+			}
+		} else {
+			for _, f := range findings {
+				fmt.Printf("%s:%d  %-5s %-8s %s\n", f.File, f.Line, f.RuleID, f.Severity, f.Description)
+			}
+			fmt.Println(formatter.Format(i18n.T("security.total", len(severities)) + truncatedNote(truncated)))
+		}
+		if err := c.writeReport(reportPath, findings, formatter); err != nil {
+			return err
+		}
+		return tools.CheckFailOn(severities, threshold)
+	}
+
+	report := securityGroupReport{Directory: dir, Total: len(severities), Groups: tools.GroupFindings(findings), Truncated: truncated}
+	if jsonOutput {
+		if err := printSecurityJSON(formatter, report); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println(formatter.Format(formatSecurityGroups(&report, maxLocations)))
+	}
+	if err := c.writeReport(reportPath, report, formatter); err != nil {
+		return err
+	}
+	return tools.CheckFailOn(severities, threshold)
+}
+
+// writeReport 指定了 --report 时把目录扫描结果写成 HTML 报告
+func (c *SecurityCommand) writeReport(path string, v any, formatter output.Formatter) error {
+	if path == "" {
+		return nil
+	}
+	jsonBytes, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("序列化安全扫描结果失败: %w", err)
+	}
+	return writeHTMLReport(path, c.Name(), string(jsonBytes), formatter)
+}
+
+// printSecurityJSON 以 JSON 输出目录扫描结果
+func printSecurityJSON(formatter output.Formatter, v any) error {
+	jsonBytes, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化安全扫描结果失败: %w", err)
+	}
+	fmt.Println(formatter.Format(string(jsonBytes)))
 	return nil
 }
+
+// formatSecurityGroups 生成分组的文本报告，每组只列出前 maxLocations 个位置
+func formatSecurityGroups(report *securityGroupReport, maxLocations int) string {
+	var sb strings.Builder
+	for _, g := range report.Groups {
+		sb.WriteString(fmt.Sprintf("[%s] %s %s", g.RuleID, g.Severity, g.Description))
+		if g.Count > 1 {
+			sb.WriteString(i18n.T("security.group.occurrences", g.Count, g.Files))
+		}
+		sb.WriteString("\n")
+		if g.CodeSnippet != "" {
+			sb.WriteString(fmt.Sprintf("    %s\n", strings.TrimSpace(g.CodeSnippet)))
+		}
+		locations := g.Occurrences
+		if maxLocations > 0 && len(locations) > maxLocations {
+			locations = locations[:maxLocations]
+		}
+		for _, o := range locations {
+			sb.WriteString(fmt.Sprintf("    - %s:%d %s\n", o.File, o.Line, o.Function))
+		}
+		if len(locations) < len(g.Occurrences) {
+			sb.WriteString("    " + i18n.T("security.group.more", len(g.Occurrences)-len(locations)) + "\n")
+		}
+	}
+	sb.WriteString(i18n.T("security.group.total", report.Total, len(report.Groups)))
+	sb.WriteString(truncatedNote(report.Truncated))
+	return sb.String()
+}
+
+// truncatedNote 说明有多少问题超出数量上限没有列出，以及涉及的规则
+func truncatedNote(t *tools.Truncation) string {
+	if t == nil {
+		return ""
+	}
+	rules := make([]string, 0, len(t.ByRule))
+	for _, rule := range slices.Sorted(maps.Keys(t.ByRule)) {
+		rules = append(rules, fmt.Sprintf("%s %d", rule, t.ByRule[rule]))
+	}
+	return i18n.T("security.truncated", t.Total, strings.Join(rules, i18n.T("list.separator")))
+}
@@ -0,0 +1,211 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/ai"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/config"
+	"go-ai-study/internal/tools"
+	"net/http"
+	"time"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+// SyncDocsCommand 从 GitHub/GitLab 同步 issue 和 PR 描述到 project_docs 集合的命令
+type SyncDocsCommand struct {
+	config *config.Config
+}
+
+// NewSyncDocsCommand 创建 issue/PR 同步命令
+func NewSyncDocsCommand(cfg *config.Config) *SyncDocsCommand {
+	return &SyncDocsCommand{
+		config: cfg,
+	}
+}
+
+// Name 命令名称
+func (c *SyncDocsCommand) Name() string {
+	return "sync-docs"
+}
+
+// Description 命令描述
+func (c *SyncDocsCommand) Description() string {
+	return "从 GitHub/GitLab 同步 issue 和 PR 描述，供历史类问题参考促成改动的讨论"
+}
+
+// syncDocsResult 一个仓库一轮同步的结果
+type syncDocsResult struct {
+	Repo    string `json:"repo"`
+	Since   string `json:"since,omitempty"` // 增量同步的起点，全量同步时为空
+	Fetched int    `json:"fetched"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Run 执行命令
+// 用法: sync-docs [--repo owner/name] [--full] [--limit 500] [--watch] [--interval 1h]
+func (c *SyncDocsCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	settings := c.config.ProjectDocs
+	fs := newFlagSet(c.Name())
+	repoName := fs.String("repo", "", "只同步该仓库（未在 project_docs.repos 中配置时按 GitHub 仓库处理）")
+	full := fs.Bool("full", false, "删除已同步的数据，重新全量同步")
+	limit := fs.Int("limit", settings.MaxItems, "每个仓库每次最多同步的 issue/PR 数（默认 500）")
+	watch := fs.Bool("watch", false, "按同步周期持续增量同步，直到中断")
+	interval := fs.String("interval", settings.SyncInterval, "--watch 的同步周期（如 30m、6h），默认取配置 project_docs.sync_interval，未配置时为 1h")
+
+	if _, err := parseFlags(fs, args); err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+
+	repos, err := c.selectRepos(*repoName)
+	if err != nil {
+		return err
+	}
+	period := tools.DefaultProjectDocsInterval
+	if *interval != "" {
+		if period, err = time.ParseDuration(*interval); err != nil || period <= 0 {
+			return fmt.Errorf("同步周期无效: %q", *interval)
+		}
+	}
+
+	embedder, err := ai.NewEmbedder(c.config.OllamaEndpoint, c.config.EmbeddingModel)
+	if err != nil {
+		return err
+	}
+	connectCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	mc, err := ai.ConnectMilvus(connectCtx, c.config.MilvusEndpoint)
+	if err != nil {
+		return err
+	}
+	defer mc.Close()
+
+	// 表结构或向量模型变化后集合会重建，之后各仓库都从头同步
+	rebuilt, err := ai.EnsureProjectDocsCollection(ctx, mc, c.config.EmbeddingModel, *full)
+	if err != nil {
+		return err
+	}
+	if rebuilt {
+		fmt.Println(formatter.Format(fmt.Sprintf("📦 已创建集合 %s，全量同步", ai.ProjectDocsCollection)))
+	}
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	for {
+		results := c.syncOnce(ctx, mc, embedder, httpClient, repos, *limit)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err := c.report(results, formatter); err != nil {
+			return err
+		}
+		if !*watch {
+			for _, r := range results {
+				if r.Error != "" {
+					return fmt.Errorf("部分仓库同步失败")
+				}
+			}
+			return nil
+		}
+
+		// 持续同步时单个仓库失败（限流、网络）不退出，下一轮重试
+		fmt.Println(formatter.Format(fmt.Sprintf("⏳ %s 后再次同步（Ctrl+C 退出）", period)))
+		timer := time.NewTimer(period)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// selectRepos 要同步的仓库：--repo 指定时只同步该仓库，否则同步配置中的全部仓库
+// --local-only 模式下 API 地址必须在本机（如内网 GitLab 的本机代理）
+func (c *SyncDocsCommand) selectRepos(name string) ([]config.ProjectRepo, error) {
+	repos := c.config.ProjectDocs.Repos
+	if name != "" {
+		selected := []config.ProjectRepo{{Repo: name}}
+		for _, repo := range repos {
+			if repo.Repo == name {
+				selected = []config.ProjectRepo{repo}
+				break
+			}
+		}
+		repos = selected
+	}
+	if len(repos) == 0 {
+		return nil, fmt.Errorf("没有要同步的仓库：在配置文件的 project_docs.repos 中配置，或用 --repo 指定")
+	}
+
+	for _, repo := range repos {
+		if err := tools.ValidateProjectRepo(repo); err != nil {
+			return nil, err
+		}
+		api := tools.ProjectRepoAPI(repo)
+		if c.config.LocalOnly && !tools.IsLocalHost(tools.EndpointHost(api)) {
+			return nil, fmt.Errorf("--local-only 模式下不能访问 %s（%s）", api, repo.Repo)
+		}
+	}
+	return repos, nil
+}
+
+// syncOnce 对每个仓库执行一轮增量同步：只拉取集合中最近一条记录之后更新过的 issue/PR
+func (c *SyncDocsCommand) syncOnce(ctx context.Context, mc client.Client, embedder embeddings.Embedder, httpClient *http.Client, repos []config.ProjectRepo, limit int) []syncDocsResult {
+	results := make([]syncDocsResult, 0, len(repos))
+	for _, repo := range repos {
+		if ctx.Err() != nil {
+			break
+		}
+		result := syncDocsResult{Repo: repo.Repo}
+		err := func() error {
+			since, err := ai.ProjectDocsSyncedAt(ctx, mc, repo.Repo)
+			if err != nil {
+				return err
+			}
+			if since > 0 {
+				result.Since = time.Unix(since, 0).UTC().Format(time.RFC3339)
+			}
+			docs, err := tools.FetchProjectDocs(ctx, httpClient, repo, since, limit)
+			if err != nil {
+				return err
+			}
+			if err := ai.UpsertProjectDocs(ctx, mc, embedder, docs); err != nil {
+				return err
+			}
+			result.Fetched = len(docs)
+			return nil
+		}()
+		if err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// report 输出一轮同步的结果
+func (c *SyncDocsCommand) report(results []syncDocsResult, formatter output.Formatter) error {
+	if output.Structured(formatter) {
+		jsonBytes, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("序列化同步结果失败: %w", err)
+		}
+		fmt.Println(formatter.Format(string(jsonBytes)))
+		return nil
+	}
+
+	for _, r := range results {
+		switch {
+		case r.Error != "":
+			fmt.Println(formatter.Format(fmt.Sprintf("❌ %s 同步失败: %s", r.Repo, r.Error)))
+		case r.Since != "":
+			fmt.Println(formatter.Format(fmt.Sprintf("✅ %s: %s 之后更新的 issue/PR %d 个", r.Repo, r.Since, r.Fetched)))
+		default:
+			fmt.Println(formatter.Format(fmt.Sprintf("✅ %s: 已同步 issue/PR %d 个", r.Repo, r.Fetched)))
+		}
+	}
+	return nil
+}
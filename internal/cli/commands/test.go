@@ -48,7 +48,7 @@ func (c *TestCommand) Run(ctx context.Context, args []string, formatter output.F
 	if len(args) > 1 && args[1] == "--dir" {
 		req.DirPath = target
 	} else if len(args) > 1 && args[1] == "--function" && len(args) > 2 {
-		req.FilePath = args[2]
+		req.FilePath = target
 		req.FunctionName = args[2]
 	} else {
 		req.FilePath = target
@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/cli/output"
+	"go-ai-study/internal/tools"
+	"strings"
+)
+
+// TriageCommand 严重程度裁决命令
+type TriageCommand struct {
+	toolManager *tools.ToolManager
+}
+
+// NewTriageCommand 创建严重程度裁决命令
+func NewTriageCommand(toolManager *tools.ToolManager) *TriageCommand {
+	return &TriageCommand{
+		toolManager: toolManager,
+	}
+}
+
+// Name 命令名称
+func (c *TriageCommand) Name() string {
+	return "triage"
+}
+
+// Description 命令描述
+func (c *TriageCommand) Description() string {
+	return "让对话模型复核低置信度的问题，丢弃误报、提升被低估的问题，并记录理由"
+}
+
+// Run 执行命令
+// 用法: triage [path] [--confidence low] [--category ErrorHandling,...] [--batch 10] [--context 5]
+func (c *TriageCommand) Run(ctx context.Context, args []string, formatter output.Formatter) error {
+	fs := newFlagSet(c.Name())
+	confidence := fs.String("confidence", "low", "复核置信度不高于该级别的问题：high、medium、low")
+	category := categoryFlag(fs)
+	batch := fs.Int("batch", 10, "每次请求模型的问题数")
+	contextLines := fs.Int("context", 5, "问题行前后附带给模型的代码行数")
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("参数解析失败: %w", err)
+	}
+	categories, err := tools.ParseCategories(*category)
+	if err != nil {
+		return err
+	}
+
+	target := "."
+	if len(positional) > 0 {
+		target = positional[0]
+	}
+
+	findings, err := tools.CollectFindings(ctx, c.toolManager, target)
+	if err != nil {
+		return err
+	}
+	findings = tools.FilterFindingsByCategory(findings, categories)
+
+	result, err := c.toolManager.Run(ctx, "severity_adjudicator", tools.AdjudicationRequest{
+		Findings:     findings,
+		Confidence:   *confidence,
+		BatchSize:    *batch,
+		ContextLines: *contextLines,
+	})
+	if err != nil {
+		return fmt.Errorf("严重程度裁决失败: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("严重程度裁决失败: %s", result.Error)
+	}
+
+	if output.Structured(formatter) {
+		fmt.Println(formatter.Format(result.Result))
+		return nil
+	}
+
+	var adjudication tools.AdjudicationResult
+	if err := json.Unmarshal([]byte(result.Result), &adjudication); err != nil {
+		return fmt.Errorf("解析裁决结果失败: %w", err)
+	}
+	fmt.Println(formatter.Format(formatAdjudication(&adjudication)))
+	return nil
+}
+
+// formatAdjudication 生成文本报告：先输出裁决记录，再输出裁决后的问题列表
+func formatAdjudication(result *tools.AdjudicationResult) string {
+	var sb strings.Builder
+	if len(result.Decisions) > 0 {
+		sb.WriteString("⚖️ 裁决记录\n")
+		for _, d := range result.Decisions {
+			verdict := d.Decision
+			if d.Decision == tools.AdjudicationRaise {
+				verdict = fmt.Sprintf("raise %s → %s", d.Severity, d.NewSeverity)
+			}
+			sb.WriteString(fmt.Sprintf("  %s  %-5s %s:%d  %s\n      理由: %s\n", d.Fingerprint, d.RuleID, d.File, d.Line, verdict, d.Reason))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("📋 裁决后的问题\n")
+	for _, f := range result.Findings {
+		sb.WriteString(fmt.Sprintf("  %s  %-5s %-8s %s:%d  %s\n", f.Fingerprint, f.RuleID, f.Severity, f.File, f.Line, f.Description))
+	}
+	sb.WriteString(fmt.Sprintf("✅ %s", result.Summary))
+	return sb.String()
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"go-ai-study/internal/config"
+	"go-ai-study/internal/tools"
+	"net"
+	"net/http"
+	"os"
+)
+
+// enforceLocalOnly 启用只允许本机连接的模式
+// 配置的服务地址不在本机时直接失败；HTTP 请求在建立连接前检查目标主机；
+// 子进程中的 go 命令关闭模块代理、校验和数据库和工具链自动下载
+func enforceLocalOnly(cfg *config.Config) error {
+	if err := tools.CheckLocalOnly(cfg); err != nil {
+		return err
+	}
+
+	for key, value := range map[string]string{
+		"GOPROXY":     "off",
+		"GOSUMDB":     "off",
+		"GOTOOLCHAIN": "local",
+	} {
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("设置 %s 失败: %w", key, err)
+		}
+	}
+
+	// 直接修改 http.DefaultTransport：第三方库（如 langchaingo）在初始化时已持有它的引用
+	if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+		dial := transport.DialContext
+		if dial == nil {
+			dial = (&net.Dialer{}).DialContext
+		}
+		transport.Proxy = nil // 不经过 HTTP_PROXY 等代理
+		transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			host, _, err := net.SplitHostPort(addr)
+			if err != nil {
+				host = addr
+			}
+			if !tools.IsLocalHost(host) {
+				return nil, fmt.Errorf("--local-only 模式下拒绝连接非本机地址: %s", addr)
+			}
+			return dial(ctx, network, addr)
+		}
+	}
+	return nil
+}
@@ -8,4 +8,6 @@ type Formatter interface {
 // Options 格式化选项
 type Options struct {
 	Verbose bool
+	NoEmoji bool // 去掉 ✅、⚠️、📊 等 emoji（部分 CI 日志查看器和屏幕阅读器无法正确显示）
+	ASCII   bool // 只输出 ASCII 符号：全角标点、箭头、制表符替换为 ASCII 写法，同时去掉 emoji
 }
@@ -0,0 +1,375 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HTMLFormatter HTML 报告格式化器
+// 把工具的 JSON 结果渲染为单个自包含的 HTML 文件（样式内嵌，不引用外部资源）：
+// 顶部是摘要和按严重程度统计的表格，问题带严重程度徽标，代码片段可折叠
+type HTMLFormatter struct {
+	Command string // 当前执行的命令，由 CLI 在运行命令前设置
+}
+
+// NewHTMLFormatter 创建 HTML 报告格式化器
+func NewHTMLFormatter(command string) *HTMLFormatter {
+	return &HTMLFormatter{Command: command}
+}
+
+// htmlFinding 报告中的一个问题
+// 各工具的问题字段名一致（rule_id、severity、file、line……），从 JSON 中按字段名提取
+type htmlFinding struct {
+	RuleID      string
+	Severity    string
+	Category    string
+	Description string
+	Location    string
+	Function    string
+	Snippet     string
+	Suggestion  string
+	Count       int      // 合并后的问题组的出现次数
+	Locations   []string // 问题组中每次出现的位置
+}
+
+// htmlTable 结果中的其他对象列表（如复杂度分析的函数列表），按通用表格显示
+type htmlTable struct {
+	Title   string
+	Columns []string
+	Rows    [][]string
+}
+
+// htmlSeverityCount 某个严重程度的问题数
+type htmlSeverityCount struct {
+	Severity string
+	Count    int
+}
+
+// htmlCategoryCount 某个类别的问题数
+type htmlCategoryCount struct {
+	Category string
+	Count    int
+}
+
+// htmlReport 模板收到的报告模型
+type htmlReport struct {
+	Title       string
+	Command     string
+	Summary     string
+	GeneratedAt string
+	Fields      [][2]string // 顶层的标量字段
+	Severities  []htmlSeverityCount
+	Categories  []htmlCategoryCount // 按问题数从多到少
+	Findings    []htmlFinding
+	Tables      []htmlTable
+	Raw         string // 原始结果（JSON 缩进后），放在折叠区域
+}
+
+// htmlSeverityOrder 严重程度的显示顺序，不在列表中的排在最后
+var htmlSeverityOrder = []string{"critical", "high", "medium", "low", "info"}
+
+// Format 渲染 HTML 报告；结果不是 JSON 时原样放在 <pre> 中
+func (h *HTMLFormatter) Format(result string) string {
+	report := htmlReport{
+		Title:       "go-ai-insight 报告",
+		Command:     h.Command,
+		GeneratedAt: time.Now().Format("2006-01-02 15:04:05"),
+		Raw:         result,
+	}
+	if h.Command != "" {
+		report.Title = fmt.Sprintf("go-ai-insight %s 报告", h.Command)
+	}
+
+	var parsed any
+	if err := json.Unmarshal([]byte(result), &parsed); err == nil {
+		// 模板负责转义，JSON 中不再把 < > & 编码为 \u003c 等
+		var raw strings.Builder
+		enc := json.NewEncoder(&raw)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(parsed); err == nil {
+			report.Raw = strings.TrimSpace(raw.String())
+		}
+		collectHTMLReport(&report, parsed)
+	}
+
+	var sb strings.Builder
+	if err := htmlReportTemplate.Execute(&sb, report); err != nil {
+		return fmt.Sprintf("❌ 渲染 HTML 报告失败: %v", err)
+	}
+	return sb.String()
+}
+
+// collectHTMLReport 从解析后的 JSON 中提取摘要、问题和其他列表
+func collectHTMLReport(report *htmlReport, v any) {
+	if obj, ok := v.(map[string]any); ok {
+		if summary, ok := obj["summary"].(string); ok {
+			report.Summary = summary
+		}
+		for _, key := range sortedKeys(obj) {
+			if key == "summary" {
+				continue
+			}
+			if s, ok := scalarString(obj[key]); ok {
+				report.Fields = append(report.Fields, [2]string{key, s})
+			}
+		}
+	}
+
+	walkHTMLResult(report, "", v)
+
+	// 按严重程度排序后统计，不区分大小写，显示第一次出现的写法
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		return severityRank(report.Findings[i].Severity) < severityRank(report.Findings[j].Severity)
+	})
+	index := make(map[string]int)
+	for _, f := range report.Findings {
+		n := f.Count
+		if n == 0 {
+			n = 1
+		}
+		key := strings.ToLower(f.Severity)
+		i, ok := index[key]
+		if !ok {
+			i = len(report.Severities)
+			index[key] = i
+			report.Severities = append(report.Severities, htmlSeverityCount{Severity: f.Severity})
+		}
+		report.Severities[i].Count += n
+	}
+
+	categories := make(map[string]int)
+	for _, f := range report.Findings {
+		if f.Category == "" {
+			continue
+		}
+		n := f.Count
+		if n == 0 {
+			n = 1
+		}
+		i, ok := categories[f.Category]
+		if !ok {
+			i = len(report.Categories)
+			categories[f.Category] = i
+			report.Categories = append(report.Categories, htmlCategoryCount{Category: f.Category})
+		}
+		report.Categories[i].Count += n
+	}
+	sort.SliceStable(report.Categories, func(i, j int) bool {
+		return report.Categories[i].Count > report.Categories[j].Count
+	})
+}
+
+// walkHTMLResult 遍历 JSON：带 severity 的对象是问题，其他对象数组作为通用表格
+// 被 //insight:ignore 抑制的问题（suppressed）不计入问题统计，单独列为表格供审计
+func walkHTMLResult(report *htmlReport, name string, v any) {
+	switch val := v.(type) {
+	case map[string]any:
+		if isHTMLFinding(val) {
+			report.Findings = append(report.Findings, toHTMLFinding(val))
+			return
+		}
+		for _, key := range sortedKeys(val) {
+			walkHTMLResult(report, key, val[key])
+		}
+	case []any:
+		var rows []map[string]any
+		for _, item := range val {
+			obj, ok := item.(map[string]any)
+			if !ok {
+				return
+			}
+			if isHTMLFinding(obj) && name != "suppressed" {
+				report.Findings = append(report.Findings, toHTMLFinding(obj))
+				continue
+			}
+			rows = append(rows, obj)
+		}
+		if len(rows) > 0 {
+			report.Tables = append(report.Tables, toHTMLTable(name, rows))
+		}
+	}
+}
+
+// isHTMLFinding 对象是否是一个问题（或合并后的问题组）
+func isHTMLFinding(obj map[string]any) bool {
+	_, hasSeverity := obj["severity"].(string)
+	_, hasDescription := obj["description"].(string)
+	return hasSeverity && hasDescription
+}
+
+// toHTMLFinding 按通用字段名提取问题
+func toHTMLFinding(obj map[string]any) htmlFinding {
+	str := func(key string) string {
+		s, _ := scalarString(obj[key])
+		return s
+	}
+	f := htmlFinding{
+		RuleID:      str("rule_id"),
+		Severity:    str("severity"),
+		Category:    str("category"),
+		Description: str("description"),
+		Location:    htmlLocation(obj),
+		Function:    str("function"),
+		Snippet:     strings.TrimSpace(str("code_snippet")),
+		Suggestion:  str("suggestion"),
+	}
+	if count, ok := obj["count"].(float64); ok {
+		f.Count = int(count)
+	}
+	if occurrences, ok := obj["occurrences"].([]any); ok {
+		for _, o := range occurrences {
+			if occ, ok := o.(map[string]any); ok {
+				f.Locations = append(f.Locations, htmlLocation(occ))
+			}
+		}
+	}
+	return f
+}
+
+// htmlLocation file:line 形式的位置
+func htmlLocation(obj map[string]any) string {
+	file, _ := obj["file"].(string)
+	if file == "" {
+		return ""
+	}
+	if line, ok := obj["line"].(float64); ok && line > 0 {
+		return fmt.Sprintf("%s:%d", file, int(line))
+	}
+	return file
+}
+
+// toHTMLTable 对象数组转换为表格，列为所有对象的标量字段
+func toHTMLTable(name string, rows []map[string]any) htmlTable {
+	table := htmlTable{Title: name}
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		for _, key := range sortedKeys(row) {
+			if _, ok := scalarString(row[key]); ok && !seen[key] {
+				seen[key] = true
+				table.Columns = append(table.Columns, key)
+			}
+		}
+	}
+	for _, row := range rows {
+		cells := make([]string, len(table.Columns))
+		for i, col := range table.Columns {
+			cells[i], _ = scalarString(row[col])
+		}
+		table.Rows = append(table.Rows, cells)
+	}
+	return table
+}
+
+// scalarString 把 JSON 标量转换为字符串，对象和数组返回 false
+func scalarString(v any) (string, bool) {
+	switch val := v.(type) {
+	case string:
+		return val, true
+	case float64:
+		return fmt.Sprint(val), true
+	case bool:
+		return fmt.Sprint(val), true
+	}
+	return "", false
+}
+
+// severityRank 严重程度的排序位置
+func severityRank(severity string) int {
+	severity = strings.ToLower(severity)
+	for i, s := range htmlSeverityOrder {
+		if s == severity {
+			return i
+		}
+	}
+	return len(htmlSeverityOrder)
+}
+
+// sortedKeys 按字母顺序返回 map 的键，保证报告内容稳定
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// htmlReportTemplate 报告模板，样式内嵌，生成的文件可以直接作为 CI 产物打开
+var htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"lower": strings.ToLower,
+}).Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", "PingFang SC", "Microsoft YaHei", sans-serif; margin: 2em; color: #24292f; }
+h1 { font-size: 1.6em; margin-bottom: 0.2em; }
+.meta { color: #57606a; margin-bottom: 1.5em; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #d0d7de; padding: 4px 10px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+.badge { display: inline-block; padding: 1px 8px; border-radius: 10px; color: #fff; font-size: 0.85em; background: #6e7781; }
+.badge.critical { background: #8b0000; }
+.badge.high { background: #cf222e; }
+.badge.medium { background: #bf8700; }
+.badge.low { background: #0969da; }
+.badge.info { background: #6e7781; }
+.finding { border: 1px solid #d0d7de; border-radius: 6px; padding: 0.6em 1em; margin: 0.8em 0; }
+.finding .loc { font-family: monospace; color: #57606a; }
+pre { background: #f6f8fa; padding: 0.8em; overflow-x: auto; }
+details summary { cursor: pointer; color: #0969da; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="meta">生成时间 {{.GeneratedAt}}</div>
+{{if .Summary}}<p>{{.Summary}}</p>{{end}}
+{{if .Fields}}
+<table>
+{{range .Fields}}<tr><th>{{index . 0}}</th><td>{{index . 1}}</td></tr>
+{{end}}</table>
+{{end}}
+{{if .Severities}}
+<h2>按严重程度统计</h2>
+<table>
+<tr><th>严重程度</th><th>问题数</th></tr>
+{{range .Severities}}<tr><td><span class="badge {{lower .Severity}}">{{.Severity}}</span></td><td>{{.Count}}</td></tr>
+{{end}}</table>
+{{end}}
+{{if .Categories}}
+<h2>按类别统计</h2>
+<table>
+<tr><th>类别</th><th>问题数</th></tr>
+{{range .Categories}}<tr><td>{{.Category}}</td><td>{{.Count}}</td></tr>
+{{end}}</table>
+{{end}}
+{{if .Findings}}
+<h2>问题（{{len .Findings}}）</h2>
+{{range .Findings}}
+<div class="finding">
+<span class="badge {{lower .Severity}}">{{.Severity}}</span>
+{{if .RuleID}}<strong>[{{.RuleID}}]</strong>{{end}} {{.Description}}{{if gt .Count 1}}（{{.Count}} 处）{{end}}{{if .Category}} <span class="loc">{{.Category}}</span>{{end}}
+{{if .Location}}<div class="loc">{{.Location}}{{if .Function}} · {{.Function}}{{end}}</div>{{end}}
+{{if .Suggestion}}<div>建议：{{.Suggestion}}</div>{{end}}
+{{if .Snippet}}<details><summary>代码片段</summary><pre>{{.Snippet}}</pre></details>{{end}}
+{{if .Locations}}<details><summary>全部位置（{{len .Locations}}）</summary><ul>{{range .Locations}}<li class="loc">{{.}}</li>{{end}}</ul></details>{{end}}
+</div>
+{{end}}
+{{end}}
+{{range .Tables}}
+<h2>{{.Title}}</h2>
+<table>
+<tr>{{range .Columns}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</table>
+{{end}}
+<details><summary>原始结果</summary><pre>{{.Raw}}</pre></details>
+</body>
+</html>
+`))
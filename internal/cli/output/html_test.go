@@ -0,0 +1,38 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+// 测试 HTML 报告转义问题中的文本：描述、代码片段和原始 JSON 中的标签都不能原样输出
+func TestHTMLFormatter_EscapesFindings(t *testing.T) {
+	result := `{
+  "summary": "发现 1 个问题 <b>",
+  "bugs": [{
+    "rule_id": "B101",
+    "severity": "High",
+    "category": "errors",
+    "description": "<script>alert(1)</script>",
+    "file": "main.go",
+    "line": 3,
+    "code_snippet": "if a < b && c > d {",
+    "fix_suggestion": "<img src=x onerror=alert(2)>"
+  }]
+}`
+	html := NewHTMLFormatter("bug").Format(result)
+
+	for _, raw := range []string{"<script>alert(1)", "<img src=x", "<b>", "a < b && c > d"} {
+		if strings.Contains(html, raw) {
+			t.Errorf("报告中不应原样出现 %q", raw)
+		}
+	}
+	for _, escaped := range []string{"&lt;script&gt;alert(1)&lt;/script&gt;", "&lt;img src=x onerror=alert(2)&gt;", "a &lt; b &amp;&amp; c &gt; d", "main.go:3", "B101"} {
+		if !strings.Contains(html, escaped) {
+			t.Errorf("报告中缺少 %q", escaped)
+		}
+	}
+	if !strings.HasPrefix(strings.TrimSpace(html), "<!DOCTYPE html>") {
+		t.Errorf("应该生成完整的 HTML 文档: %.80s", html)
+	}
+}
@@ -0,0 +1,55 @@
+package output
+
+import "strings"
+
+// asciiReplacer 把常见的非 ASCII 符号替换为 ASCII 写法（全角标点、箭头、勾叉、制表符）
+var asciiReplacer = strings.NewReplacer(
+	"→", "->", "←", "<-", "↑", "^", "↓", "v",
+	"✓", "[x]", "✔", "[x]", "✗", "[ ]", "✘", "[ ]",
+	"•", "*", "…", "...",
+	"，", ", ", "。", ". ", "：", ": ", "；", "; ", "、", ", ",
+	"（", " (", "）", ")", "【", "[", "】", "]",
+	"“", "\"", "”", "\"", "‘", "'", "’", "'",
+	"─", "-", "━", "-", "═", "=", "│", "|", "┃", "|", "║", "|",
+	"┌", "+", "┐", "+", "└", "+", "┘", "+", "├", "+", "┤", "+", "┬", "+", "┴", "+", "┼", "+",
+)
+
+// isEmoji 判断字符是否为 emoji 或 emoji 变体选择符
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // 表情、符号和象形文字
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // 杂项符号和装饰符号（✅ ⚠ ❌ ⚖ 等）
+		return r != 0x2713 && r != 0x2714 && r != 0x2717 && r != 0x2718 // 勾叉在 ASCII 模式下另行替换
+	case r == 0xFE0F || r == 0x200D: // 变体选择符、零宽连接符
+		return true
+	}
+	return false
+}
+
+// stripEmoji 删除一行中的 emoji 和紧跟其后的一个空格，保留其余空白（对齐不变）
+func stripEmoji(line string) string {
+	if strings.IndexFunc(line, isEmoji) == -1 {
+		return line
+	}
+	var sb strings.Builder
+	skipSpace := false
+	for _, r := range line {
+		if isEmoji(r) {
+			skipSpace = true
+			continue
+		}
+		if skipSpace && r == ' ' {
+			skipSpace = false
+			continue
+		}
+		skipSpace = false
+		sb.WriteRune(r)
+	}
+	return strings.TrimRight(sb.String(), " ")
+}
+
+// toASCII 把一行中的常见符号替换为 ASCII 写法，行尾多余的空格一并去掉
+func toASCII(line string) string {
+	return strings.TrimRight(asciiReplacer.Replace(line), " ")
+}
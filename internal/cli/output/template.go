@@ -0,0 +1,106 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TemplateFormatter 模板格式化器
+// 把命令结果交给用户提供的 Go 模板渲染，团队可以在不改代码的情况下生成内部的报告格式。
+// .html/.htm 文件使用 html/template（自动转义），其余使用 text/template
+type TemplateFormatter struct {
+	tmpl    executor
+	Command string // 当前执行的命令，由 CLI 在运行命令前设置
+}
+
+// executor text/template 和 html/template 共同的执行接口
+type executor interface {
+	Execute(w io.Writer, data any) error
+}
+
+// ReportData 模板收到的报告模型
+type ReportData struct {
+	Command     string    // 命令名（如 bug、security、audit）
+	Result      any       // 命令结果：JSON 结果解析后的对象（map/slice），非 JSON 结果为字符串
+	Raw         string    // 原始结果文本
+	GeneratedAt time.Time // 生成时间
+}
+
+// templateFuncs 模板中可用的辅助函数
+var templateFuncs = map[string]any{
+	"json": func(v any) (string, error) {
+		data, err := json.MarshalIndent(v, "", "  ")
+		return string(data), err
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	"join": func(sep string, items []any) string {
+		parts := make([]string, len(items))
+		for i, item := range items {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, sep)
+	},
+}
+
+// NewTemplateFormatter 读取并解析模板文件
+func NewTemplateFormatter(path string) (*TemplateFormatter, error) {
+	if path == "" {
+		return nil, fmt.Errorf("-f template 需要用 --template 指定模板文件")
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取模板失败: %w", err)
+	}
+
+	name := filepath.Base(path)
+	var tmpl executor
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm":
+		tmpl, err = htmltemplate.New(name).Funcs(templateFuncs).Parse(string(content))
+	default:
+		tmpl, err = template.New(name).Funcs(templateFuncs).Parse(string(content))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析模板失败: %w", err)
+	}
+	return &TemplateFormatter{tmpl: tmpl}, nil
+}
+
+// Format 用模板渲染结果
+func (t *TemplateFormatter) Format(result string) string {
+	data := ReportData{
+		Command:     t.Command,
+		Result:      result,
+		Raw:         result,
+		GeneratedAt: time.Now(),
+	}
+	var parsed any
+	if err := json.Unmarshal([]byte(result), &parsed); err == nil {
+		data.Result = parsed
+	}
+
+	var sb strings.Builder
+	if err := t.tmpl.Execute(&sb, data); err != nil {
+		return fmt.Sprintf("❌ 渲染模板失败: %v", err)
+	}
+	return sb.String()
+}
+
+// Structured 格式化器是否需要结构化（JSON）结果
+// JSON、模板和 HTML 格式化器都应该收到命令的原始 JSON 结果，而不是文本报告
+func Structured(formatter Formatter) bool {
+	switch formatter.(type) {
+	case *JSONFormatter, *TemplateFormatter, *HTMLFormatter:
+		return true
+	}
+	return false
+}
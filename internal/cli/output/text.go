@@ -49,7 +49,23 @@ func (t *TextFormatter) Format(result string) string {
 		}
 	}
 
-	return formatted.String()
+	if !t.options.NoEmoji && !t.options.ASCII {
+		return formatted.String()
+	}
+	return t.plain(formatted.String())
+}
+
+// plain 按 NoEmoji/ASCII 选项逐行去掉 emoji、替换非 ASCII 符号
+func (t *TextFormatter) plain(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		line = stripEmoji(line)
+		if t.options.ASCII {
+			line = toASCII(line)
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
 }
 
 // FormatError 格式化错误信息
@@ -8,12 +8,49 @@ import (
 
 // Config 应用配置
 type Config struct {
-	DefaultOutput  string   `json:"default_output"`
-	DefaultFormat  string   `json:"default_format"`
-	Verbose        bool     `json:"verbose"`
-	OllamaEndpoint string   `json:"ollama_endpoint"`
-	MilvusEndpoint string   `json:"milvus_endpoint"`
-	LogConfig      LogConfig `json:"log_config"`
+	DefaultOutput  string          `json:"default_output"`
+	DefaultFormat  string          `json:"default_format"`
+	ReportTemplate string          `json:"report_template"` // default_format 为 template 时使用的模板文件
+	OutputSchema   string          `json:"output_schema"`   // bug、security 结果的输出结构：v1（默认，已弃用）、v2
+	Verbose        bool            `json:"verbose"`
+	LocalOnly      bool            `json:"local_only"` // 只允许连接本机地址
+	Locale         string          `json:"locale"`     // 输出语言：zh-CN（默认）、en-US
+	NoEmoji        bool            `json:"no_emoji"`   // 文本输出去掉 emoji
+	ASCII          bool            `json:"ascii"`      // 文本输出只使用 ASCII 符号（同时去掉 emoji）
+	OllamaEndpoint string          `json:"ollama_endpoint"`
+	MilvusEndpoint string          `json:"milvus_endpoint"`
+	ChatModel      string          `json:"chat_model"`
+	EmbeddingModel string          `json:"embedding_model"`
+	ContextTokens  int             `json:"context_tokens"` // 检索代码在提示词中的 token 预算，0 使用默认值（2000）
+	LogConfig      LogConfig       `json:"log_config"`
+	Arch           ArchConfig      `json:"arch"`
+	ACL            ACLConfig       `json:"acl"`
+	AuditLog       AuditLogConfig  `json:"audit_log"`
+	PromptLog      PromptLogConfig `json:"prompt_log"`
+	CrashReportDir string          `json:"crash_report_dir"` // 工具 panic 时写入崩溃报告的目录，为空表示不写
+	StructTags     TagConfig       `json:"struct_tags"`
+	Rules          RulesConfig     `json:"rules"`
+	Discovery      DiscoveryConfig `json:"discovery"`
+
+	// ProjectDocs sync-docs 从 GitHub/GitLab 同步 issue 和 PR 描述的仓库及同步周期
+	ProjectDocs ProjectDocsConfig `json:"project_docs"`
+
+	// Docs scan 时同时索引的项目文档（README、docs/、ADR），以及检索时文档片段的占比
+	Docs DocsConfig `json:"docs"`
+
+	// IndexDependencies scan 时同时索引这些依赖的源码（模块或包路径），从模块缓存只读读取
+	IndexDependencies []string `json:"index_dependencies"`
+
+	// ToolLimits 按工具名配置的资源上限，"*" 对所有没有单独配置的工具生效
+	ToolLimits map[string]ToolLimitConfig `json:"tool_limits"`
+}
+
+// RulesConfig 规则的开关和严重程度覆盖（对 bug 和 security 生效），以及自定义安全规则
+type RulesConfig struct {
+	Disabled []string          `json:"disabled"` // 关闭的规则 ID，如 ["B104", "G107"]
+	Severity map[string]string `json:"severity"` // 按规则 ID 覆盖严重程度，如 {"B103": "Medium"}
+	Custom   []string          `json:"custom"`   // YAML 自定义安全规则文件，如 ["rules/org.yaml"]
+	Plugins  []string          `json:"plugins"`  // Go 规则插件（.so），导出 InsightRules 注册 Bug 和安全规则
 }
 
 // LogConfig 日志配置
@@ -24,6 +61,121 @@ type LogConfig struct {
 	FilePath string `json:"file_path"` // 日志文件路径（当 output=file 时使用）
 }
 
+// ArchConfig 架构检查配置
+type ArchConfig struct {
+	ImportRules   []ImportRule `json:"import_rules"`   // 导入规则
+	ForbiddenDeps []string     `json:"forbidden_deps"` // 禁止使用的第三方模块（模块路径前缀）
+	Layers        []Layer      `json:"layers"`         // 分层架构声明
+}
+
+// Layer 架构层：Packages 中的包只能依赖本层和 MayUse 中列出的层
+// 包按声明顺序归属第一个匹配的层，不属于任何层的包（包括标准库和第三方库）不受约束
+type Layer struct {
+	Name     string   `json:"name"`
+	Packages []string `json:"packages"` // 包模式，规则同 ImportRule
+	MayUse   []string `json:"may_use"`  // 允许依赖的层名
+}
+
+// ImportRule 导入规则：匹配 From 的包不允许导入匹配 Deny 的包
+// 包模式可以是完整导入路径或相对模块路径，"/..." 后缀匹配子包，"*" 匹配所有包
+type ImportRule struct {
+	From   string   `json:"from"`
+	Deny   []string `json:"deny"`
+	Reason string   `json:"reason,omitempty"`
+}
+
+// ACLConfig 代码索引访问控制配置
+// 匹配 Restricted 的文件，其代码片段入库时带上 ACL 标签，只有拥有对应访问范围的调用方才能检索到
+type ACLConfig struct {
+	Restricted []RestrictedPath `json:"restricted"` // 受限路径
+	Scopes     []string         `json:"scopes"`     // 本地命令默认拥有的访问范围，"*" 表示全部
+}
+
+// RestrictedPath 受限路径：Pattern 是相对扫描根目录的 glob（"**" 匹配任意层目录），Tag 是 ACL 标签
+type RestrictedPath struct {
+	Pattern string `json:"pattern"`
+	Tag     string `json:"tag"`
+}
+
+// AuditLogConfig 工具执行审计日志配置
+// 每次工具调用追加一行 JSON 到 Path，文件超过 MaxSizeMB 后轮转为 Path.1、Path.2 ...
+type AuditLogConfig struct {
+	Path       string `json:"path"`        // 审计日志路径，为空表示不记录
+	MaxSizeMB  int    `json:"max_size_mb"` // 单个文件的最大大小（MB）
+	MaxBackups int    `json:"max_backups"` // 保留的历史文件数
+}
+
+// PromptLogConfig 调试用的提示词日志配置
+// 开启后每个会话把完整的提示词、检索到的片段、工具调用和回答（脱敏后）写入 Dir 下的一个 JSONL 文件；
+// 只在日志级别为 debug 时生效，避免误开后把源码写进日志
+type PromptLogConfig struct {
+	Enabled bool   `json:"enabled"` // 是否记录，也可用 --log-prompts 开启
+	Dir     string `json:"dir"`     // 日志目录，默认系统临时目录下的 go-ai-insight-prompts
+}
+
+// TagConfig 结构体标签检查配置（bug 检测的 json/yaml 标签规则）
+type TagConfig struct {
+	DTOPackages []string `json:"dto_packages"` // API DTO 所在的包：包名或目录路径后缀，其中导出字段必须有 json 标签
+	Case        string   `json:"case"`         // 标签名的命名风格：snake、camel、pascal、kebab，为空表示不检查
+}
+
+// DiscoveryConfig 目录扫描的文件发现配置
+// 构建系统生成的代码不在源码目录中时，改由 bazel query、文件列表或外部命令给出要分析的文件
+type DiscoveryConfig struct {
+	Mode       string   `json:"mode"`        // walk（默认，遍历文件系统）、list、bazel、command
+	FileList   string   `json:"file_list"`   // list 模式的文件列表，每行一个路径
+	Bazel      string   `json:"bazel"`       // bazel 可执行文件，默认 bazel
+	BazelQuery string   `json:"bazel_query"` // bazel 模式的查询表达式，{pkg} 替换为扫描目录对应的包模式
+	Command    []string `json:"command"`     // command 模式执行的命令及参数，标准输出每行一个文件
+
+	// 以下只对 walk 模式生效
+	Symlinks string `json:"symlinks"`  // 符号链接的处理方式：files（默认，只收集指向文件的链接）、skip、follow
+	MaxDepth int    `json:"max_depth"` // 进入子目录的层数上限，0 表示不限制
+	MaxFiles int    `json:"max_files"` // 遍历到的文件数上限，超过后扫描失败，0 表示不限制
+}
+
+// ProjectDocsConfig issue/PR 同步配置
+type ProjectDocsConfig struct {
+	Repos        []ProjectRepo `json:"repos"`
+	SyncInterval string        `json:"sync_interval"` // sync-docs --watch 的同步周期（Go duration，如 "6h"），默认 1h
+	MaxItems     int           `json:"max_items"`     // 每个仓库每次最多同步的 issue/PR 数，0 表示默认 500
+}
+
+// ProjectRepo 同步 issue/PR 的仓库
+// 访问令牌只从 TokenEnv 指定的环境变量读取，不写入配置文件；公开仓库可以不配置
+type ProjectRepo struct {
+	Provider string `json:"provider"`  // github（默认）、gitlab
+	Repo     string `json:"repo"`      // GitHub 为 owner/name，GitLab 为项目路径 group/sub/name
+	BaseURL  string `json:"base_url"`  // API 地址，默认 https://api.github.com 或 https://gitlab.com；自建实例需要配置
+	TokenEnv string `json:"token_env"` // 访问令牌所在的环境变量，默认 GITHUB_TOKEN 或 GITLAB_TOKEN
+}
+
+// DocsConfig 项目文档索引配置
+type DocsConfig struct {
+	Disabled bool    `json:"disabled"` // scan 时不索引 Markdown 文档
+	Ratio    float64 `json:"ratio"`    // ask、search 结果中文档片段最多占的比例（0-1），默认 0.25，0 表示只检索代码
+}
+
+// ToolLimitConfig 单个工具的资源上限，0 表示不限制（TimeoutMs 为 0 表示使用工具默认超时）
+type ToolLimitConfig struct {
+	TimeoutMs     int64 `json:"timeout_ms"`       // 执行超时（毫秒）
+	MaxMemoryMB   int   `json:"max_memory_mb"`    // 执行期间堆内存增长上限（MB）
+	MaxFiles      int   `json:"max_files"`        // 目录扫描的文件数上限
+	MaxFileSizeKB int   `json:"max_file_size_kb"` // 单个文件大小上限（KB），超过的文件跳过
+
+	MaxFindings        int `json:"max_findings"`          // 结果中列出的问题数上限，超出的只计数（bug、security）
+	MaxFindingsPerRule int `json:"max_findings_per_rule"` // 每条规则列出的问题数上限，超出的只计数（bug、security）
+}
+
+// DefaultArchConfig 默认架构检查配置：任何包都不允许导入 unsafe
+func DefaultArchConfig() ArchConfig {
+	return ArchConfig{
+		ImportRules: []ImportRule{
+			{From: "*", Deny: []string{"unsafe"}, Reason: "禁止使用 unsafe"},
+		},
+	}
+}
+
 // Load 加载配置
 func Load(configPath string) (*Config, error) {
 	// 默认配置
@@ -33,12 +185,22 @@ func Load(configPath string) (*Config, error) {
 		Verbose:        false,
 		OllamaEndpoint: "http://localhost:11434",
 		MilvusEndpoint: "http://localhost:19530",
+		ChatModel:      "llama3:latest",
+		EmbeddingModel: "bge-m3:latest",
 		LogConfig: LogConfig{
 			Level:    "info",
 			Format:   "text",
 			Output:   "stdout",
 			FilePath: "",
 		},
+		Arch: DefaultArchConfig(),
+		Docs: DocsConfig{
+			Ratio: 0.25,
+		},
+		AuditLog: AuditLogConfig{
+			MaxSizeMB:  10,
+			MaxBackups: 5,
+		},
 	}
 
 	// 如果指定了配置文件，则加载
@@ -62,6 +224,14 @@ func Load(configPath string) (*Config, error) {
 		cfg.DefaultFormat = val
 	}
 
+	if val := os.Getenv("GO_AI_INSIGHT_SCHEMA"); val != "" {
+		cfg.OutputSchema = val
+	}
+
+	if val := os.Getenv("GO_AI_INSIGHT_LOCALE"); val != "" {
+		cfg.Locale = val
+	}
+
 	// 从环境变量加载日志配置
 	if val := os.Getenv("GO_AI_INSIGHT_LOG_LEVEL"); val != "" {
 		cfg.LogConfig.Level = val
@@ -79,6 +249,18 @@ func Load(configPath string) (*Config, error) {
 		cfg.LogConfig.FilePath = val
 	}
 
+	if val := os.Getenv("GO_AI_INSIGHT_AUDIT_LOG"); val != "" {
+		cfg.AuditLog.Path = val
+	}
+
+	if val := os.Getenv("GO_AI_INSIGHT_PROMPT_LOG_DIR"); val != "" {
+		cfg.PromptLog.Dir = val
+	}
+
+	if val := os.Getenv("GO_AI_INSIGHT_CRASH_DIR"); val != "" {
+		cfg.CrashReportDir = val
+	}
+
 	return cfg, nil
 }
 
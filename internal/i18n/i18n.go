@@ -0,0 +1,159 @@
+// Package i18n 用户可见文本的消息目录
+//
+// 每种语言一个 JSON 文件（locales/<locale>.json），键为消息 ID，值为 fmt 格式串。
+// 当前语言通过配置文件的 locale、环境变量 GO_AI_INSIGHT_LOCALE 或 --lang 参数选择，
+// 缺失的消息依次回退到默认语言（zh-CN）和调用方给出的默认文本。
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// 支持的语言
+const (
+	ZhCN = "zh-CN"
+	EnUS = "en-US"
+
+	DefaultLocale = ZhCN
+)
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+var (
+	catalogsOnce sync.Once
+	catalogs     map[string]map[string]string
+	catalogsErr  error
+
+	mu      sync.RWMutex
+	current = DefaultLocale
+)
+
+// loadCatalogs 读取嵌入的所有消息目录，整个进程只读取一次
+func loadCatalogs() (map[string]map[string]string, error) {
+	catalogsOnce.Do(func() {
+		catalogs = make(map[string]map[string]string)
+		entries, err := localeFS.ReadDir("locales")
+		if err != nil {
+			catalogsErr = fmt.Errorf("读取消息目录失败: %w", err)
+			return
+		}
+		for _, entry := range entries {
+			data, err := localeFS.ReadFile(path.Join("locales", entry.Name()))
+			if err != nil {
+				catalogsErr = fmt.Errorf("读取消息目录失败: %w", err)
+				return
+			}
+			messages := make(map[string]string)
+			if err := json.Unmarshal(data, &messages); err != nil {
+				catalogsErr = fmt.Errorf("解析消息目录 %s 失败: %w", entry.Name(), err)
+				return
+			}
+			catalogs[strings.TrimSuffix(entry.Name(), ".json")] = messages
+		}
+	})
+	return catalogs, catalogsErr
+}
+
+// Locales 返回所有支持的语言
+func Locales() []string {
+	all, _ := loadCatalogs()
+	locales := make([]string, 0, len(all))
+	for locale := range all {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Keys 返回语言的消息目录中的所有消息 ID（已排序），不支持的语言返回空
+func Keys(locale string) []string {
+	all, _ := loadCatalogs()
+	keys := make([]string, 0, len(all[locale]))
+	for id := range all[locale] {
+		keys = append(keys, id)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Normalize 把 zh、en_US、en-us.UTF-8 等写法规范化为支持的语言，无法识别时返回 false
+func Normalize(locale string) (string, bool) {
+	locale = strings.TrimSpace(locale)
+	if i := strings.IndexAny(locale, ".@"); i != -1 {
+		locale = locale[:i]
+	}
+	locale = strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	switch {
+	case locale == "zh" || strings.HasPrefix(locale, "zh-"):
+		return ZhCN, true
+	case locale == "en" || strings.HasPrefix(locale, "en-"):
+		return EnUS, true
+	}
+	return "", false
+}
+
+// SetLocale 设置当前语言，空字符串表示默认语言
+func SetLocale(locale string) error {
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	normalized, ok := Normalize(locale)
+	if !ok {
+		return fmt.Errorf("不支持的语言 %q（可选 %s）", locale, strings.Join(Locales(), "、"))
+	}
+	if _, err := loadCatalogs(); err != nil {
+		return err
+	}
+	mu.Lock()
+	current = normalized
+	mu.Unlock()
+	return nil
+}
+
+// Locale 返回当前语言
+func Locale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// T 返回当前语言下的消息，args 非空时按 fmt 格式串展开
+// 当前语言和默认语言都没有该消息时返回消息 ID 本身
+func T(id string, args ...any) string {
+	return format(lookup(id, id), args)
+}
+
+// Text 返回当前语言下的消息，找不到时使用 fallback
+// 用于规则描述这类在代码中就有默认（中文）文本的场景
+func Text(id, fallback string, args ...any) string {
+	return format(lookup(id, fallback), args)
+}
+
+// lookup 依次在当前语言和默认语言中查找消息
+func lookup(id, fallback string) string {
+	all, err := loadCatalogs()
+	if err != nil {
+		return fallback
+	}
+	if msg, ok := all[Locale()][id]; ok {
+		return msg
+	}
+	if msg, ok := all[DefaultLocale][id]; ok {
+		return msg
+	}
+	return fallback
+}
+
+func format(msg string, args []any) string {
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"context"
+	"go/build"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// 分析器基准测试语料
+//
+// 默认使用本机 Go 安装中的标准库源码（GOROOT/src 下的 benchCorpusPackages），
+// 设置 GO_AI_INSIGHT_BENCH_CORPUS 可以改为任意目录（例如 vendor 下来的开源项目）。
+// 运行方式：
+//
+//	go test ./internal/tools -run '^$' -bench Analyzer -benchmem
+//
+// 除了 ns/op、B/op、allocs/op，每个基准还报告 files/s，发布前和上一版本对比即可发现规则引擎的性能回退。
+var benchCorpusPackages = []string{"net/http", "go", "encoding", "crypto/tls", "text/template"}
+
+// benchFile 语料中的一个文件
+type benchFile struct {
+	path string
+	code string
+}
+
+var (
+	benchCorpusOnce  sync.Once
+	benchCorpusFiles []benchFile
+	benchCorpusBytes int64
+)
+
+// loadBenchCorpus 读取语料中的 Go 文件（跳过 testdata 和测试文件），整个进程只读取一次
+func loadBenchCorpus(b *testing.B) ([]benchFile, int64) {
+	b.Helper()
+	benchCorpusOnce.Do(func() {
+		roots := []string{os.Getenv("GO_AI_INSIGHT_BENCH_CORPUS")}
+		if roots[0] == "" {
+			roots = roots[:0]
+			for _, pkg := range benchCorpusPackages {
+				roots = append(roots, filepath.Join(build.Default.GOROOT, "src", filepath.FromSlash(pkg)))
+			}
+		}
+		for _, root := range roots {
+			filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return nil
+				}
+				if d.IsDir() {
+					if d.Name() == "testdata" || d.Name() == "vendor" {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+				if filepath.Ext(path) != ".go" || strings.HasSuffix(path, "_test.go") {
+					return nil
+				}
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return nil
+				}
+				benchCorpusFiles = append(benchCorpusFiles, benchFile{path: path, code: string(data)})
+				benchCorpusBytes += int64(len(data))
+				return nil
+			})
+		}
+	})
+	if len(benchCorpusFiles) == 0 {
+		b.Skip("没有找到基准测试语料（设置 GO_AI_INSIGHT_BENCH_CORPUS 指定目录）")
+	}
+	return benchCorpusFiles, benchCorpusBytes
+}
+
+// runAnalyzerBenchmark 每次迭代用 analyze 分析一遍语料中的所有文件，报告吞吐量和内存分配
+// 解析失败的文件（例如语料中故意写错的代码）不影响计时
+func runAnalyzerBenchmark(b *testing.B, analyze func(f benchFile) error) {
+	files, size := loadBenchCorpus(b)
+	b.SetBytes(size)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, f := range files {
+			_ = analyze(f)
+		}
+	}
+	b.ReportMetric(float64(len(files)*b.N)/b.Elapsed().Seconds(), "files/s")
+}
+
+func BenchmarkAnalyzer_BugDetector(b *testing.B) {
+	detector := NewBugDetector()
+	runAnalyzerBenchmark(b, func(f benchFile) error {
+		_, err := detector.analyzeCode(context.Background(), f.code, f.path, ruleOptions{GoVersion: "1.21"})
+		return err
+	})
+}
+
+func BenchmarkAnalyzer_SecurityScanner(b *testing.B) {
+	scanner := NewSecurityScanner()
+	ctx := context.Background()
+	runAnalyzerBenchmark(b, func(f benchFile) error {
+		_, err := scanner.Execute(ctx, SecurityScanInput{Code: f.code, File: f.path})
+		return err
+	})
+}
+
+func BenchmarkAnalyzer_ComplexityAnalyzer(b *testing.B) {
+	analyzer := NewComplexityAnalyzer()
+	ctx := context.Background()
+	runAnalyzerBenchmark(b, func(f benchFile) error {
+		_, err := analyzer.Execute(ctx, ComplexityInput{Code: f.code})
+		return err
+	})
+}
@@ -0,0 +1,311 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"go-ai-study/internal/config"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// 架构检查规则 ID
+const (
+	ArchRuleImport    = "A101" // 违反导入规则
+	ArchRuleForbidden = "A102" // 使用了禁止的第三方依赖
+	ArchRuleLayer     = "A103" // 违反分层依赖
+)
+
+// ArchChecker 架构检查器
+// 按配置的导入规则检查包之间的依赖，例如禁止导入 unsafe、禁止 cli 直接依赖 ai；
+// 也可以声明分层（cli → tools → ai → infra），按允许矩阵检查层间依赖
+type ArchChecker struct {
+	*TypedTool[ArchCheckRequest, *ArchCheckResult]
+}
+
+// NewArchChecker 创建架构检查器
+func NewArchChecker() *ArchChecker {
+	ac := &ArchChecker{}
+	ac.TypedTool = NewTypedTool[ArchCheckRequest, *ArchCheckResult](
+		"archcheck",
+		"按配置的导入规则检查包依赖，报告违规的 import 语句",
+		ac,
+	)
+	return ac
+}
+
+// ArchCheckRequest 架构检查请求
+type ArchCheckRequest struct {
+	Directory     string              `json:"directory" jsonschema:"required"` // 扫描目录（需位于某个 Go 模块内）
+	ImportRules   []config.ImportRule `json:"import_rules"`                    // 导入规则
+	ForbiddenDeps []string            `json:"forbidden_deps"`                  // 禁止使用的第三方模块
+	Layers        []config.Layer      `json:"layers"`                          // 分层架构声明
+}
+
+// ArchViolation 架构违规
+type ArchViolation struct {
+	RuleID  string `json:"rule_id"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Package string `json:"package"`        // 违规的包
+	Import  string `json:"import"`         // 被禁止的导入
+	From    string `json:"from,omitempty"` // 导入方所在的层（分层检查）
+	To      string `json:"to,omitempty"`   // 被导入方所在的层（分层检查）
+	Message string `json:"message"`
+}
+
+// ArchCheckResult 架构检查结果
+type ArchCheckResult struct {
+	Module     string          `json:"module"`
+	Packages   int             `json:"packages"`
+	Violations []ArchViolation `json:"violations"`
+	Summary    string          `json:"summary"`
+}
+
+// ValidateInput 验证输入参数
+func (ac *ArchChecker) ValidateInput(req ArchCheckRequest) error {
+	if req.Directory == "" {
+		return fmt.Errorf("必须指定 Directory")
+	}
+	for _, rule := range req.ImportRules {
+		if rule.From == "" || len(rule.Deny) == 0 {
+			return fmt.Errorf("导入规则必须同时指定 from 和 deny: %+v", rule)
+		}
+	}
+	return validateLayers(req.Layers)
+}
+
+// Execute 执行架构检查
+func (ac *ArchChecker) Execute(ctx context.Context, req ArchCheckRequest) (*ArchCheckResult, error) {
+	return CheckArchitecture(ctx, req)
+}
+
+// packageImport 包中的一条 import 语句
+type packageImport struct {
+	Package string // 导入方的包路径
+	Path    string // 被导入的包路径
+	File    string
+	Line    int
+}
+
+// CheckArchitecture 检查目录下所有包的 import 是否符合规则
+func CheckArchitecture(ctx context.Context, req ArchCheckRequest) (*ArchCheckResult, error) {
+	_, module, err := findModule(req.Directory)
+	if err != nil {
+		return nil, err
+	}
+	imports, packages, err := collectImports(ctx, req.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ArchCheckResult{Module: module, Packages: packages, Violations: []ArchViolation{}}
+	for _, imp := range imports {
+		for _, rule := range req.ImportRules {
+			if !matchPackagePattern(rule.From, imp.Package, module) {
+				continue
+			}
+			for _, deny := range rule.Deny {
+				if !matchPackagePattern(deny, imp.Path, module) {
+					continue
+				}
+				message := fmt.Sprintf("%s 不允许导入 %s", imp.Package, imp.Path)
+				if rule.Reason != "" {
+					message += ": " + rule.Reason
+				}
+				result.Violations = append(result.Violations, ArchViolation{
+					RuleID: ArchRuleImport, File: imp.File, Line: imp.Line,
+					Package: imp.Package, Import: imp.Path, Message: message,
+				})
+			}
+		}
+		if v, ok := checkLayerEdge(req.Layers, imp, module); ok {
+			result.Violations = append(result.Violations, v)
+		}
+		for _, dep := range req.ForbiddenDeps {
+			if imp.Path == dep || strings.HasPrefix(imp.Path, dep+"/") {
+				result.Violations = append(result.Violations, ArchViolation{
+					RuleID: ArchRuleForbidden, File: imp.File, Line: imp.Line,
+					Package: imp.Package, Import: imp.Path,
+					Message: fmt.Sprintf("禁止使用依赖 %s", dep),
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(result.Violations, func(i, j int) bool {
+		a, b := result.Violations[i], result.Violations[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	result.Summary = fmt.Sprintf("检查 %d 个包，发现 %d 处架构违规", result.Packages, len(result.Violations))
+	return result, nil
+}
+
+// validateLayers 检查层声明：层名唯一且 may_use 只引用已声明的层
+func validateLayers(layers []config.Layer) error {
+	names := make(map[string]bool)
+	for _, layer := range layers {
+		if layer.Name == "" || len(layer.Packages) == 0 {
+			return fmt.Errorf("架构层必须指定 name 和 packages: %+v", layer)
+		}
+		if names[layer.Name] {
+			return fmt.Errorf("架构层重复声明: %s", layer.Name)
+		}
+		names[layer.Name] = true
+	}
+	for _, layer := range layers {
+		for _, use := range layer.MayUse {
+			if !names[use] {
+				return fmt.Errorf("架构层 %s 的 may_use 引用了未声明的层: %s", layer.Name, use)
+			}
+		}
+	}
+	return nil
+}
+
+// layerOf 返回包所属的层（按声明顺序第一个匹配的层）
+func layerOf(layers []config.Layer, pkgPath, module string) *config.Layer {
+	for i := range layers {
+		for _, pattern := range layers[i].Packages {
+			if matchPackagePattern(pattern, pkgPath, module) {
+				return &layers[i]
+			}
+		}
+	}
+	return nil
+}
+
+// checkLayerEdge 检查一条 import 边是否符合分层允许矩阵
+func checkLayerEdge(layers []config.Layer, imp packageImport, module string) (ArchViolation, bool) {
+	if len(layers) == 0 {
+		return ArchViolation{}, false
+	}
+	from := layerOf(layers, imp.Package, module)
+	to := layerOf(layers, imp.Path, module)
+	if from == nil || to == nil || from.Name == to.Name {
+		return ArchViolation{}, false
+	}
+	for _, use := range from.MayUse {
+		if use == to.Name {
+			return ArchViolation{}, false
+		}
+	}
+
+	allowed := "无"
+	if len(from.MayUse) > 0 {
+		allowed = strings.Join(from.MayUse, ", ")
+	}
+	return ArchViolation{
+		RuleID: ArchRuleLayer, File: imp.File, Line: imp.Line,
+		Package: imp.Package, Import: imp.Path, From: from.Name, To: to.Name,
+		Message: fmt.Sprintf("%s 层不允许依赖 %s 层: %s 导入了 %s（允许依赖: %s）",
+			from.Name, to.Name, imp.Package, imp.Path, allowed),
+	}, true
+}
+
+// findModule 从目录向上查找 go.mod，返回模块根目录和模块路径
+func findModule(dir string) (string, string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", fmt.Errorf("解析路径失败: %w", err)
+	}
+	for current := abs; ; current = filepath.Dir(current) {
+		data, err := os.ReadFile(filepath.Join(current, "go.mod"))
+		if err == nil {
+			module := modfile.ModulePath(data)
+			if module == "" {
+				return "", "", fmt.Errorf("%s 中没有 module 声明", filepath.Join(current, "go.mod"))
+			}
+			return current, module, nil
+		}
+		if filepath.Dir(current) == current {
+			return "", "", fmt.Errorf("%s 不在 Go 模块中（未找到 go.mod）", dir)
+		}
+	}
+}
+
+// collectImports 收集目录下所有 Go 文件的 import，返回 import 列表和包数量
+// 包路径由文件所属模块（最近的 go.mod）的路径和相对位置推出，嵌套模块按各自的模块路径计算
+func collectImports(ctx context.Context, dir string) ([]packageImport, int, error) {
+	files, err := collectGoFiles(ctx, dir)
+	if err != nil {
+		return nil, 0, fmt.Errorf("文件收集失败: %w", err)
+	}
+
+	var imports []packageImport
+	packages := make(map[string]string) // 目录 -> 包路径
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+
+		f, err := parser.ParseFile(fset, file, nil, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+
+		fileDir := filepath.Dir(file)
+		pkgPath, ok := packages[fileDir]
+		if !ok {
+			root, module, err := findModule(fileDir)
+			if err != nil {
+				return nil, 0, err
+			}
+			abs, err := filepath.Abs(fileDir)
+			if err != nil {
+				return nil, 0, fmt.Errorf("解析路径失败: %w", err)
+			}
+			pkgPath = module
+			if rel, err := filepath.Rel(root, abs); err == nil && rel != "." {
+				pkgPath = module + "/" + filepath.ToSlash(rel)
+			}
+			packages[fileDir] = pkgPath
+		}
+
+		for _, spec := range f.Imports {
+			path, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				continue
+			}
+			imports = append(imports, packageImport{
+				Package: pkgPath,
+				Path:    path,
+				File:    file,
+				Line:    fset.Position(spec.Pos()).Line,
+			})
+		}
+	}
+	return imports, len(packages), nil
+}
+
+// matchPackagePattern 判断包路径是否匹配模式
+// 模式可以是完整导入路径，也可以是相对模块路径（如 internal/ai）；"/..." 后缀匹配子包，"*" 匹配所有包
+func matchPackagePattern(pattern, pkgPath, module string) bool {
+	if pattern == "*" || pattern == "..." {
+		return true
+	}
+	candidates := []string{pattern}
+	if module != "" && !strings.HasPrefix(pattern, module) {
+		candidates = append(candidates, module+"/"+strings.TrimPrefix(pattern, "/"))
+	}
+	for _, p := range candidates {
+		if prefix, ok := strings.CutSuffix(p, "/..."); ok {
+			if pkgPath == prefix || strings.HasPrefix(pkgPath, prefix+"/") {
+				return true
+			}
+		} else if pkgPath == p {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package tools
+
+import (
+	"context"
+	"go-ai-study/internal/config"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// 测试导入规则与禁止依赖
+func TestCheckArchitecture(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":            "module example.com/app\n\ngo 1.21\n",
+		"cli/cli.go":        "package cli\n\nimport (\n\t\"fmt\"\n\n\t\"example.com/app/ai\"\n\t\"example.com/app/tools\"\n)\n\nvar _ = fmt.Sprint(ai.X, tools.Y)\n",
+		"cli/sub/sub.go":    "package sub\n\nimport \"example.com/app/ai/model\"\n\nvar _ = model.Z\n",
+		"tools/tools.go":    "package tools\n\nimport (\n\t\"unsafe\"\n\n\t\"github.com/pkg/errors\"\n)\n\nvar Y = unsafe.Sizeof(0)\nvar _ = errors.New\n",
+		"ai/ai.go":          "package ai\n\nvar X = 1\n",
+		"ai/model/model.go": "package model\n\nvar Z = 1\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("创建目录失败: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("写入测试文件失败: %v", err)
+		}
+	}
+
+	result, err := CheckArchitecture(context.Background(), ArchCheckRequest{
+		Directory: dir,
+		ImportRules: []config.ImportRule{
+			{From: "*", Deny: []string{"unsafe"}},
+			{From: "cli/...", Deny: []string{"ai/..."}},
+		},
+		ForbiddenDeps: []string{"github.com/pkg/errors"},
+	})
+	if err != nil {
+		t.Fatalf("检查失败: %v", err)
+	}
+	if result.Module != "example.com/app" || result.Packages != 5 {
+		t.Fatalf("模块信息不正确: %+v", result)
+	}
+
+	type key struct{ rule, pkg, imp string }
+	want := map[key]bool{
+		{ArchRuleImport, "example.com/app/cli", "example.com/app/ai"}:           true,
+		{ArchRuleImport, "example.com/app/cli/sub", "example.com/app/ai/model"}: true,
+		{ArchRuleImport, "example.com/app/tools", "unsafe"}:                     true,
+		{ArchRuleForbidden, "example.com/app/tools", "github.com/pkg/errors"}:   true,
+	}
+	if len(result.Violations) != len(want) {
+		t.Fatalf("应该发现 %d 处违规: %+v", len(want), result.Violations)
+	}
+	for _, v := range result.Violations {
+		if !want[key{v.RuleID, v.Package, v.Import}] {
+			t.Errorf("意外的违规: %+v", v)
+		}
+		if v.Line == 0 {
+			t.Errorf("违规应包含行号: %+v", v)
+		}
+	}
+}
+
+// 测试包模式匹配
+func TestMatchPackagePattern(t *testing.T) {
+	tests := []struct {
+		pattern, pkg string
+		want         bool
+	}{
+		{"*", "example.com/app/x", true},
+		{"internal/ai", "example.com/app/internal/ai", true},
+		{"internal/ai", "example.com/app/internal/ai/sub", false},
+		{"internal/ai/...", "example.com/app/internal/ai/sub", true},
+		{"example.com/app/internal/...", "example.com/app/internal", true},
+		{"unsafe", "unsafe", true},
+		{"internal/ai", "example.com/app/internal/aix", false},
+	}
+	for _, tt := range tests {
+		if got := matchPackagePattern(tt.pattern, tt.pkg, "example.com/app"); got != tt.want {
+			t.Errorf("matchPackagePattern(%q, %q) = %v, 期望 %v", tt.pattern, tt.pkg, got, tt.want)
+		}
+	}
+}
+
+// 测试分层依赖检查
+func TestCheckArchitecture_Layers(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":           "module example.com/app\n\ngo 1.21\n",
+		"cli/cli.go":       "package cli\n\nimport (\n\t\"example.com/app/ai\"\n\t\"example.com/app/tools\"\n)\n\nvar _ = ai.X + tools.Y\n",
+		"tools/tools.go":   "package tools\n\nimport (\n\t\"fmt\"\n\n\t\"example.com/app/ai\"\n)\n\nvar Y = ai.X\nvar _ = fmt.Sprint\n",
+		"ai/ai.go":         "package ai\n\nimport \"example.com/app/infra\"\n\nvar X = infra.Z\n",
+		"infra/infra.go":   "package infra\n\nimport \"example.com/app/cli\"\n\nvar Z = 1\nvar _ = cli.Name\n",
+		"util/util.go":     "package util\n\nimport \"example.com/app/cli\"\n\nvar _ = cli.Name\n",
+		"cli/name/name.go": "package name\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("创建目录失败: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("写入测试文件失败: %v", err)
+		}
+	}
+
+	layers := []config.Layer{
+		{Name: "cli", Packages: []string{"cli/..."}, MayUse: []string{"tools"}},
+		{Name: "tools", Packages: []string{"tools/..."}, MayUse: []string{"ai"}},
+		{Name: "ai", Packages: []string{"ai/..."}, MayUse: []string{"infra"}},
+		{Name: "infra", Packages: []string{"infra/..."}},
+	}
+	result, err := CheckArchitecture(context.Background(), ArchCheckRequest{Directory: dir, Layers: layers})
+	if err != nil {
+		t.Fatalf("检查失败: %v", err)
+	}
+
+	// cli → ai 跨层、infra → cli 反向依赖；util 不属于任何层，不受约束
+	if len(result.Violations) != 2 {
+		t.Fatalf("应该发现 2 处分层违规: %+v", result.Violations)
+	}
+	for i, want := range [][2]string{{"ai", "cli"}, {"cli", "infra"}} {
+		v := result.Violations[i]
+		if v.RuleID != ArchRuleLayer || v.To != want[0] || v.From != want[1] {
+			t.Errorf("第 %d 处违规不正确: %+v", i, v)
+		}
+	}
+
+	// may_use 引用未声明的层应当报错
+	bad := append([]config.Layer{}, layers...)
+	bad[3].MayUse = []string{"db"}
+	if err := NewArchChecker().Validate(ArchCheckRequest{Directory: dir, Layers: bad}); err == nil {
+		t.Error("引用未声明的层应该校验失败")
+	}
+}
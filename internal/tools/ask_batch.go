@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"bufio"
+	"fmt"
+	"go-ai-study/internal/ai"
+	"os"
+	"strings"
+)
+
+// AskBatchItem 批量问答中一个问题的结果
+type AskBatchItem struct {
+	ai.AskResult
+	Seconds float64 `json:"seconds"`         // 回答耗时
+	Error   string  `json:"error,omitempty"` // 检索或生成回答失败
+}
+
+// AskBatchReport 批量问答的结果（ask --batch），可以直接整理成 FAQ
+type AskBatchReport struct {
+	Model    string         `json:"model"`
+	Results  []AskBatchItem `json:"results"`
+	Answered int            `json:"answered"`
+	Failed   int            `json:"failed"`
+	Summary  string         `json:"summary"`
+}
+
+// LoadQuestions 读取问题列表：每行一个问题，忽略空行和 # 开头的注释行
+func LoadQuestions(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取问题列表失败: %w", err)
+	}
+	defer f.Close()
+
+	var questions []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		questions = append(questions, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取问题列表失败: %w", err)
+	}
+	if len(questions) == 0 {
+		return nil, fmt.Errorf("%w: 问题列表 %s 中没有问题", ErrInvalidInput, path)
+	}
+	return questions, nil
+}
+
+// SummarizeAskBatch 统计回答成功和失败的问题数
+func SummarizeAskBatch(model string, results []AskBatchItem) *AskBatchReport {
+	report := &AskBatchReport{Model: model, Results: results}
+	var seconds float64
+	for _, r := range results {
+		if r.Error != "" {
+			report.Failed++
+			continue
+		}
+		report.Answered++
+		seconds += r.Seconds
+	}
+	report.Summary = fmt.Sprintf("%d 个问题：回答 %d 个，失败 %d 个", len(results), report.Answered, report.Failed)
+	if report.Answered > 0 {
+		report.Summary += fmt.Sprintf("，平均耗时 %.1f 秒", seconds/float64(report.Answered))
+	}
+	return report
+}
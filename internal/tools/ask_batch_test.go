@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"errors"
+	"go-ai-study/internal/ai"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// 测试读取问题列表：跳过空行、注释和 BOM，空列表报错
+func TestLoadQuestions(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "questions.txt")
+	content := "\ufeff# FAQ 草稿\n代码是怎么切分的？\n\n  向量存在哪里？  \r\n# 末尾注释\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	questions, err := LoadQuestions(path)
+	if err != nil {
+		t.Fatalf("读取问题列表失败: %v", err)
+	}
+	want := []string{"代码是怎么切分的？", "向量存在哪里？"}
+	if !reflect.DeepEqual(questions, want) {
+		t.Errorf("问题列表 = %q，期望 %q", questions, want)
+	}
+
+	if err := os.WriteFile(path, []byte("# 只有注释\n\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadQuestions(path); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("没有问题时应该返回 ErrInvalidInput，实际 %v", err)
+	}
+	if _, err := LoadQuestions(filepath.Join(dir, "missing.txt")); err == nil {
+		t.Error("文件不存在时应该报错")
+	}
+}
+
+// 测试批量问答汇总：失败的问题不计入平均耗时
+func TestSummarizeAskBatch(t *testing.T) {
+	results := []AskBatchItem{
+		{AskResult: ai.AskResult{Question: "a", Answer: "x"}, Seconds: 2},
+		{AskResult: ai.AskResult{Question: "b"}, Seconds: 30, Error: "AI 请求失败"},
+		{AskResult: ai.AskResult{Question: "c", Answer: "y"}, Seconds: 4},
+	}
+	report := SummarizeAskBatch("qwen", results)
+	if report.Answered != 2 || report.Failed != 1 {
+		t.Errorf("统计错误: answered=%d failed=%d", report.Answered, report.Failed)
+	}
+	if want := "3 个问题：回答 2 个，失败 1 个，平均耗时 3.0 秒"; report.Summary != want {
+		t.Errorf("摘要 = %q，期望 %q", report.Summary, want)
+	}
+}
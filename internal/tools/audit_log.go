@@ -0,0 +1,168 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/config"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// auditInputLimit 审计日志中输入摘要的最大长度（字符）
+const auditInputLimit = 256
+
+// 审计记录的执行状态
+const (
+	AuditStatusSuccess  = "success"  // 执行成功
+	AuditStatusFailed   = "failed"   // 执行失败或超时
+	AuditStatusInvalid  = "invalid"  // 输入验证失败
+	AuditStatusRejected = "rejected" // 工具不存在或已禁用
+)
+
+// AuditEntry 一次工具调用的审计记录
+type AuditEntry struct {
+	Time        time.Time `json:"time"`
+	RunID       string    `json:"run_id,omitempty"`
+	User        string    `json:"user"`
+	Host        string    `json:"host"`
+	Command     string    `json:"command,omitempty"` // 触发调用的命令行
+	Tool        string    `json:"tool"`
+	Input       string    `json:"input"`        // 输入摘要，代码等字符串输入只记录长度
+	InputSHA256 string    `json:"input_sha256"` // 完整输入的哈希，用于核对
+	DurationMs  int64     `json:"duration_ms"`
+	Status      string    `json:"status"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// AuditLog 追加写入的 JSONL 审计日志，按大小轮转
+type AuditLog struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	user       string
+	host       string
+}
+
+// NewAuditLog 根据配置创建审计日志，未配置路径时返回 nil
+func NewAuditLog(cfg config.AuditLogConfig) (*AuditLog, error) {
+	if cfg.Path == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0755); err != nil {
+		return nil, fmt.Errorf("创建审计日志目录失败: %w", err)
+	}
+
+	al := &AuditLog{
+		path:       cfg.Path,
+		maxSize:    int64(cfg.MaxSizeMB) << 20,
+		maxBackups: cfg.MaxBackups,
+		user:       os.Getenv("USER"),
+	}
+	if u, err := user.Current(); err == nil {
+		al.user = u.Username
+	}
+	al.host, _ = os.Hostname()
+	return al, nil
+}
+
+// Record 追加一条审计记录，超过大小上限时先轮转
+func (al *AuditLog) Record(entry AuditEntry) error {
+	if entry.User == "" {
+		entry.User = al.user
+	}
+	if entry.Host == "" {
+		entry.Host = al.host
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("序列化审计记录失败: %w", err)
+	}
+	line = append(line, '\n')
+
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	if info, err := os.Stat(al.path); err == nil && al.maxSize > 0 && info.Size() > 0 &&
+		info.Size()+int64(len(line)) > al.maxSize {
+		if err := al.rotate(); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(al.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("打开审计日志失败: %w", err)
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return fmt.Errorf("写入审计日志失败: %w", err)
+	}
+	return f.Close()
+}
+
+// rotate 轮转日志：path.N-1 -> path.N ... path -> path.1，超出 maxBackups 的历史文件删除
+func (al *AuditLog) rotate() error {
+	if al.maxBackups <= 0 {
+		if err := os.Remove(al.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("轮转审计日志失败: %w", err)
+		}
+		return nil
+	}
+
+	os.Remove(fmt.Sprintf("%s.%d", al.path, al.maxBackups))
+	for i := al.maxBackups - 1; i >= 1; i-- {
+		from := fmt.Sprintf("%s.%d", al.path, i)
+		if _, err := os.Stat(from); err != nil {
+			continue
+		}
+		if err := os.Rename(from, fmt.Sprintf("%s.%d", al.path, i+1)); err != nil {
+			return fmt.Errorf("轮转审计日志失败: %w", err)
+		}
+	}
+	if err := os.Rename(al.path, al.path+".1"); err != nil {
+		return fmt.Errorf("轮转审计日志失败: %w", err)
+	}
+	return nil
+}
+
+// invocationKey 触发工具调用的命令在 context 中的键
+type invocationKey struct{}
+
+// WithInvocation 记录触发工具调用的命令行，写入审计日志
+func WithInvocation(ctx context.Context, command string) context.Context {
+	return context.WithValue(ctx, invocationKey{}, command)
+}
+
+// invocationFromContext 取出触发工具调用的命令行
+func invocationFromContext(ctx context.Context) string {
+	command, _ := ctx.Value(invocationKey{}).(string)
+	return command
+}
+
+// summarizeAuditInput 生成输入摘要和完整输入的哈希
+// 字符串输入通常是源代码，只记录长度；结构化输入记录截断后的 JSON
+func summarizeAuditInput(input any) (string, string) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		data = []byte(fmt.Sprintf("%v", input))
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	if s, ok := input.(string); ok {
+		return fmt.Sprintf("<string %d 字节>", len(s)), hash
+	}
+	summary := string(data)
+	if utf8.RuneCountInString(summary) > auditInputLimit {
+		summary = string([]rune(summary)[:auditInputLimit]) + "..."
+	}
+	return summary, hash
+}
@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"go-ai-study/internal/config"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readAuditEntries 读取 JSONL 审计日志
+func readAuditEntries(t *testing.T, path string) []AuditEntry {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("打开审计日志失败: %v", err)
+	}
+	defer f.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("审计记录不是合法 JSON: %s", scanner.Text())
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// 测试每次工具调用都写入审计记录
+func TestToolManager_AuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "tools.jsonl")
+	auditLog, err := NewAuditLog(config.AuditLogConfig{Path: path, MaxSizeMB: 1, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("创建审计日志失败: %v", err)
+	}
+
+	tm := NewToolManager(NewNoopLogger())
+	tm.SetAuditLog(auditLog)
+	tm.Register(NewMockTool("ok", nil), DefaultToolConfig("ok"))
+	tm.Register(NewMockTool("fail", func(ctx context.Context, input any) (string, error) {
+		return "", errors.New("boom")
+	}), DefaultToolConfig("fail"))
+
+	ctx := WithInvocation(context.Background(), "security ./repo")
+	tm.Run(ctx, "ok", "package main // secret code")
+	tm.Run(ctx, "fail", "x")
+	tm.Run(ctx, "ok", 42)
+	tm.Run(ctx, "missing", "x")
+
+	entries := readAuditEntries(t, path)
+	wantStatus := []string{AuditStatusSuccess, AuditStatusFailed, AuditStatusInvalid, AuditStatusRejected}
+	if len(entries) != len(wantStatus) {
+		t.Fatalf("应该有 %d 条审计记录，实际 %d", len(wantStatus), len(entries))
+	}
+	for i, entry := range entries {
+		if entry.Status != wantStatus[i] {
+			t.Errorf("第 %d 条记录状态应为 %s，实际 %s", i, wantStatus[i], entry.Status)
+		}
+		if entry.Command != "security ./repo" || entry.RunID == "" || entry.InputSHA256 == "" {
+			t.Errorf("审计记录缺少字段: %+v", entry)
+		}
+	}
+	if strings.Contains(entries[0].Input, "secret") {
+		t.Errorf("字符串输入不应记录原文: %s", entries[0].Input)
+	}
+	if entries[1].Error == "" {
+		t.Error("失败的调用应该记录错误")
+	}
+}
+
+// 测试审计日志按大小轮转
+func TestAuditLog_Rotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditLog, _ := NewAuditLog(config.AuditLogConfig{Path: path, MaxBackups: 2})
+	auditLog.maxSize = 300 // 每个文件约容纳一条记录
+
+	for i := 0; i < 5; i++ {
+		if err := auditLog.Record(AuditEntry{Tool: "t", Status: AuditStatusSuccess, Input: strings.Repeat("x", 100)}); err != nil {
+			t.Fatalf("写入失败: %v", err)
+		}
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		if len(readAuditEntries(t, name)) != 1 {
+			t.Errorf("%s 应该有 1 条记录", name)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("超出 MaxBackups 的历史文件应该被删除")
+	}
+}
+
+// 测试未配置路径时不记录
+func TestNewAuditLog_Disabled(t *testing.T) {
+	auditLog, err := NewAuditLog(config.AuditLogConfig{})
+	if auditLog != nil || err != nil {
+		t.Errorf("未配置路径应返回 nil: %v %v", auditLog, err)
+	}
+}
@@ -33,6 +33,11 @@ func (bt *BaseTool) InputType() reflect.Type {
 	return bt.inputType
 }
 
+// InputSchema 输入参数的 JSON Schema
+func (bt *BaseTool) InputSchema() map[string]any {
+	return InputSchema(bt.inputType)
+}
+
 // Validate 默认验证逻辑：检查输入类型和是否为空
 func (bt *BaseTool) Validate(input any) error {
 	if input == nil {
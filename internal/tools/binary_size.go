@@ -0,0 +1,331 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"debug/buildinfo"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// BinarySizeAnalyzer 二进制体积分析器
+// 构建（或读取已有的）二进制，用 go tool nm 的符号大小把体积归属到包和模块，标出体积过大的间接依赖
+type BinarySizeAnalyzer struct {
+	*TypedTool[BinarySizeRequest, *BinarySizeResult]
+}
+
+// NewBinarySizeAnalyzer 创建二进制体积分析器
+func NewBinarySizeAnalyzer() *BinarySizeAnalyzer {
+	ba := &BinarySizeAnalyzer{}
+	ba.TypedTool = NewTypedTool[BinarySizeRequest, *BinarySizeResult](
+		"binary_size",
+		"构建二进制并按包和依赖模块统计符号体积，标出体积过大的间接依赖",
+		ba,
+	)
+	return ba
+}
+
+// BinarySizeRequest 二进制体积分析请求
+type BinarySizeRequest struct {
+	Directory string  `json:"directory" jsonschema:"required"` // 模块目录，在该目录下执行 go build
+	Package   string  `json:"package,omitempty"`               // 要构建的 main 包，默认 "."
+	Binary    string  `json:"binary,omitempty"`                // 已构建的二进制，指定时跳过构建
+	Ldflags   string  `json:"ldflags,omitempty"`               // 构建时的 -ldflags，默认 "-w"（去掉 DWARF，保留符号表）
+	Threshold float64 `json:"threshold,omitempty"`             // 模块体积占比超过该百分比视为过重，默认 5
+	Top       int     `json:"top,omitempty"`                   // 返回体积最大的前 N 个包，默认 20
+}
+
+// ModuleWeight 模块体积
+type ModuleWeight struct {
+	Path     string  `json:"path"`
+	Version  string  `json:"version,omitempty"`
+	Kind     string  `json:"kind"` // main, std, direct, indirect, unknown
+	Size     int64   `json:"size"`
+	Percent  float64 `json:"percent"`
+	Packages int     `json:"packages"`
+	Heavy    bool    `json:"heavy"` // 占比超过阈值的依赖
+}
+
+// PackageWeight 包体积
+type PackageWeight struct {
+	Package string  `json:"package"`
+	Module  string  `json:"module"`
+	Size    int64   `json:"size"`
+	Percent float64 `json:"percent"`
+}
+
+// BinarySizeResult 二进制体积分析结果
+type BinarySizeResult struct {
+	Binary     string          `json:"binary"`
+	FileSize   int64           `json:"file_size"`   // 二进制文件大小
+	SymbolSize int64           `json:"symbol_size"` // 可归属到符号的大小
+	Modules    []ModuleWeight  `json:"modules"`
+	Packages   []PackageWeight `json:"packages"`
+	Heavy      []string        `json:"heavy"` // 过重的间接依赖
+	Summary    string          `json:"summary"`
+}
+
+// ValidateInput 验证输入参数
+func (ba *BinarySizeAnalyzer) ValidateInput(req BinarySizeRequest) error {
+	if req.Directory == "" && req.Binary == "" {
+		return fmt.Errorf("必须指定 Directory 或 Binary")
+	}
+	return nil
+}
+
+// Execute 执行二进制体积分析
+func (ba *BinarySizeAnalyzer) Execute(ctx context.Context, req BinarySizeRequest) (*BinarySizeResult, error) {
+	return AnalyzeBinarySize(ctx, req)
+}
+
+// AnalyzeBinarySize 构建并分析二进制体积
+func AnalyzeBinarySize(ctx context.Context, req BinarySizeRequest) (*BinarySizeResult, error) {
+	if req.Threshold <= 0 {
+		req.Threshold = 5
+	}
+	if req.Top <= 0 {
+		req.Top = 20
+	}
+
+	binary := req.Binary
+	if binary == "" {
+		tmpDir, err := os.MkdirTemp("", "insight-binsize-")
+		if err != nil {
+			return nil, fmt.Errorf("创建临时目录失败: %w", err)
+		}
+		defer os.RemoveAll(tmpDir)
+
+		binary = filepath.Join(tmpDir, "bin")
+		if err := buildBinary(ctx, req, binary); err != nil {
+			return nil, err
+		}
+	}
+
+	info, err := os.Stat(binary)
+	if err != nil {
+		return nil, fmt.Errorf("读取二进制失败: %w", err)
+	}
+	modules, err := binaryModules(binary, req.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := exec.CommandContext(ctx, "go", "tool", "nm", "-size", binary).Output()
+	if err != nil {
+		return nil, fmt.Errorf("go tool nm 执行失败: %w", commandError(err))
+	}
+	symbols := parseNmSizes(out)
+
+	result := &BinarySizeResult{Binary: req.Binary, FileSize: info.Size(), Heavy: []string{}}
+	if result.Binary == "" {
+		result.Binary = req.Package
+	}
+
+	pkgSizes := make(map[string]int64)
+	for name, size := range symbols {
+		pkgSizes[symbolPackage(name)] += size
+		result.SymbolSize += size
+	}
+
+	moduleIndex := make(map[string]*ModuleWeight)
+	for pkg, size := range pkgSizes {
+		mod := moduleOfPackage(pkg, modules)
+		weight, ok := moduleIndex[mod.Path]
+		if !ok {
+			copied := mod
+			weight = &copied
+			moduleIndex[mod.Path] = weight
+		}
+		weight.Size += size
+		weight.Packages++
+		result.Packages = append(result.Packages, PackageWeight{
+			Package: pkg, Module: mod.Path, Size: size, Percent: sizePercent(size, result.SymbolSize),
+		})
+	}
+
+	for _, weight := range moduleIndex {
+		weight.Percent = sizePercent(weight.Size, result.SymbolSize)
+		if (weight.Kind == "direct" || weight.Kind == "indirect") && weight.Percent >= req.Threshold {
+			weight.Heavy = true
+			if weight.Kind == "indirect" {
+				result.Heavy = append(result.Heavy, weight.Path)
+			}
+		}
+		result.Modules = append(result.Modules, *weight)
+	}
+	sort.Slice(result.Modules, func(i, j int) bool { return result.Modules[i].Size > result.Modules[j].Size })
+	sort.Slice(result.Packages, func(i, j int) bool { return result.Packages[i].Size > result.Packages[j].Size })
+	if len(result.Packages) > req.Top {
+		result.Packages = result.Packages[:req.Top]
+	}
+	sort.Strings(result.Heavy)
+
+	result.Summary = fmt.Sprintf("二进制 %s，符号 %s，%d 个模块，%d 个过重的间接依赖",
+		formatBytes(result.FileSize), formatBytes(result.SymbolSize), len(result.Modules), len(result.Heavy))
+	return result, nil
+}
+
+// buildBinary 在模块目录下执行 go build
+func buildBinary(ctx context.Context, req BinarySizeRequest, output string) error {
+	pkg := req.Package
+	if pkg == "" {
+		pkg = "."
+	}
+	ldflags := req.Ldflags
+	if ldflags == "" {
+		ldflags = "-w"
+	}
+
+	cmd := exec.CommandContext(ctx, "go", "build", "-ldflags", ldflags, "-o", output, pkg)
+	cmd.Dir = req.Directory
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("go build 失败: %w\n%s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// commandError 从 exec.ExitError 中取出 stderr
+func commandError(err error) error {
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return err
+}
+
+// binaryModules 读取二进制内嵌的构建信息，结合 go.mod 区分直接和间接依赖
+func binaryModules(binary, dir string) ([]ModuleWeight, error) {
+	bi, err := buildinfo.ReadFile(binary)
+	if err != nil {
+		return nil, fmt.Errorf("读取构建信息失败: %w", err)
+	}
+
+	direct := make(map[string]bool)
+	if dir != "" {
+		if root, _, err := findModule(dir); err == nil {
+			if data, err := os.ReadFile(filepath.Join(root, "go.mod")); err == nil {
+				if mf, err := modfile.Parse("go.mod", data, nil); err == nil {
+					for _, r := range mf.Require {
+						direct[r.Mod.Path] = !r.Indirect
+					}
+				}
+			}
+		}
+	}
+
+	modules := []ModuleWeight{{Path: bi.Main.Path, Version: bi.Main.Version, Kind: "main"}}
+	for _, dep := range bi.Deps {
+		kind := "indirect"
+		if direct[dep.Path] {
+			kind = "direct"
+		}
+		modules = append(modules, ModuleWeight{Path: dep.Path, Version: dep.Version, Kind: kind})
+	}
+	return modules, nil
+}
+
+// parseNmSizes 解析 `go tool nm -size` 输出，返回符号名到大小的映射
+// 跳过未定义符号（U）和不占文件空间的 BSS 符号（B/b）
+func parseNmSizes(out []byte) map[string]int64 {
+	symbols := make(map[string]int64)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil || size == 0 {
+			continue
+		}
+		switch fields[2] {
+		case "U", "B", "b":
+			continue
+		}
+		symbols[strings.Join(fields[3:], " ")] += size
+	}
+	return symbols
+}
+
+// symbolPackage 从符号名推出所属包路径，如 github.com/a/b.(*T).M → github.com/a/b
+// 无法归属的运行时生成符号（类型元数据、字符串常量等）归为 "<runtime-data>"
+func symbolPackage(name string) string {
+	for _, prefix := range []string{"type:", "go:itab.", "go:info.", "go:cuinfo.", "go:link.", "*"} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	name = strings.TrimLeft(name, "*[]")
+	if comma := strings.Index(name, ","); comma >= 0 {
+		name = name[:comma] // itab 符号按具体类型归属
+	}
+	if name == "" || strings.HasPrefix(name, "go:") || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "$") {
+		return "<runtime-data>"
+	}
+
+	slash := strings.LastIndex(name, "/")
+	// 包路径中的 "/" 必须出现在第一个 "." 之前（排除 "(*T).M/xxx" 之类的符号）
+	if paren := strings.IndexAny(name, "(["); paren >= 0 && slash > paren {
+		slash = strings.LastIndex(name[:paren], "/")
+	}
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 {
+		return "<runtime-data>"
+	}
+	return name[:slash+1+dot]
+}
+
+// moduleOfPackage 按最长前缀匹配包所属的模块；不含域名的包视为标准库
+func moduleOfPackage(pkg string, modules []ModuleWeight) ModuleWeight {
+	best := -1
+	for i, mod := range modules {
+		if mod.Path == "" {
+			continue
+		}
+		if pkg == mod.Path || strings.HasPrefix(pkg, mod.Path+"/") {
+			if best < 0 || len(mod.Path) > len(modules[best].Path) {
+				best = i
+			}
+		}
+	}
+	if best >= 0 {
+		return ModuleWeight{Path: modules[best].Path, Version: modules[best].Version, Kind: modules[best].Kind}
+	}
+	if pkg == "main" {
+		return ModuleWeight{Path: modules[0].Path, Version: modules[0].Version, Kind: "main"}
+	}
+	if pkg == "<runtime-data>" {
+		return ModuleWeight{Path: pkg, Kind: "unknown"}
+	}
+	first := strings.SplitN(pkg, "/", 2)[0]
+	if !strings.Contains(first, ".") {
+		return ModuleWeight{Path: "std", Kind: "std"}
+	}
+	return ModuleWeight{Path: pkg, Kind: "unknown"}
+}
+
+// sizePercent 计算体积占比，保留一位小数
+func sizePercent(size, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(int64(float64(size)*1000/float64(total)+0.5)) / 10
+}
+
+// formatBytes 格式化字节数
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// 测试符号名到包路径的归属
+func TestSymbolPackage(t *testing.T) {
+	tests := map[string]string{
+		"runtime.main":                                      "runtime",
+		"github.com/a/b.(*Client).Do":                       "github.com/a/b",
+		"github.com/a/b/v2.init.0":                          "github.com/a/b/v2",
+		"type:*github.com/a/b.T":                            "github.com/a/b",
+		"go:itab.*net/http.Transport,net/http.RoundTripper": "net/http",
+		"main.main":                                         "main",
+		"net/http.(*Server).Serve.func1":                    "net/http",
+		"go:string.*":                                       "<runtime-data>",
+		"$f64.3fe0000000000000":                             "<runtime-data>",
+		"type:.eq.[2]interface {}":                          "<runtime-data>",
+	}
+	for name, want := range tests {
+		if got := symbolPackage(name); got != want {
+			t.Errorf("symbolPackage(%q) = %q, 期望 %q", name, got, want)
+		}
+	}
+}
+
+// 测试 nm 输出解析与模块归属
+func TestParseNmSizesAndModules(t *testing.T) {
+	out := []byte(`  401000       120 T runtime.main
+  402000        80 T github.com/a/b/sub.F
+  403000        40 R github.com/a/b.T
+  404000      1000 B runtime.bss
+         0         0 U _cgo_init
+`)
+	symbols := parseNmSizes(out)
+	if len(symbols) != 3 || symbols["runtime.main"] != 120 {
+		t.Fatalf("解析结果不正确: %v", symbols)
+	}
+
+	modules := []ModuleWeight{
+		{Path: "example.com/app", Kind: "main"},
+		{Path: "github.com/a/b", Kind: "indirect"},
+		{Path: "github.com/a/b/sub", Kind: "direct"},
+	}
+	tests := map[string]string{
+		"github.com/a/b/sub": "github.com/a/b/sub", // 最长前缀优先
+		"github.com/a/b":     "github.com/a/b",
+		"runtime":            "std",
+		"main":               "example.com/app",
+		"example.com/app/x":  "example.com/app",
+	}
+	for pkg, want := range tests {
+		if got := moduleOfPackage(pkg, modules).Path; got != want {
+			t.Errorf("moduleOfPackage(%q) = %q, 期望 %q", pkg, got, want)
+		}
+	}
+}
+
+// 测试构建并分析一个不依赖第三方模块的二进制
+func TestAnalyzeBinarySize(t *testing.T) {
+	if testing.Short() {
+		t.Skip("需要执行 go build")
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":  "module example.com/tiny\n\ngo 1.21\n",
+		"main.go": "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(\"hi\") }\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("写入测试文件失败: %v", err)
+		}
+	}
+
+	result, err := AnalyzeBinarySize(context.Background(), BinarySizeRequest{Directory: dir})
+	if err != nil {
+		t.Fatalf("分析失败: %v", err)
+	}
+	if result.FileSize == 0 || result.SymbolSize == 0 {
+		t.Fatalf("体积统计为 0: %+v", result)
+	}
+
+	kinds := make(map[string]string)
+	for _, mod := range result.Modules {
+		kinds[mod.Path] = mod.Kind
+	}
+	if kinds["std"] != "std" || kinds["example.com/tiny"] != "main" {
+		t.Errorf("模块归属不正确: %+v", result.Modules)
+	}
+	if len(result.Heavy) != 0 {
+		t.Errorf("没有第三方依赖时不应有过重依赖: %v", result.Heavy)
+	}
+}
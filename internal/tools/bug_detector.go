@@ -2,47 +2,75 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
-	"os"
+	"go/types"
+	"go-ai-study/internal/ai"
+	"go-ai-study/internal/config"
+	"go-ai-study/internal/i18n"
 	"path/filepath"
-	"reflect"
 	"strings"
 )
 
 // BugDetector Bug 检测器
 // 检测 Go 代码中的常见 Bug（纯检测，不自动修复）
 type BugDetector struct {
-	*BaseTool
+	*TypedTool[BugDetectorInput, *BugResult]
 	ruleEngine *BugRuleEngine
 }
 
 // NewBugDetector 创建 Bug 检测器
 func NewBugDetector() *BugDetector {
-	detector := &BugDetector{
-		BaseTool: NewBaseTool(
-			"bug_detector",
-			"检测 Go 代码中的常见 Bug（忽略错误、资源泄漏、nil 引用等）",
-			reflect.TypeOf(""),
-		),
-	}
+	detector := &BugDetector{}
+	detector.TypedTool = NewTypedTool[BugDetectorInput, *BugResult](
+		"bug_detector",
+		"检测 Go 代码中的常见 Bug（忽略错误、资源泄漏、nil 引用等）",
+		detector,
+	)
 	detector.ruleEngine = NewBugRuleEngine()
 	detector.ruleEngine.RegisterAllRules()
 	return detector
 }
 
+// Configure 按 ToolConfig.CustomConfig 中的规则设置重新注册规则
+func (bd *BugDetector) Configure(config ToolConfig) error {
+	settings, err := RuleSettingsFromConfig(config.CustomConfig)
+	if err != nil {
+		return err
+	}
+	engine := NewBugRuleEngine()
+	engine.Settings = settings
+	engine.RegisterAllRules()
+	bd.ruleEngine = engine
+	return nil
+}
+
 // BugDetectorInput 支持多种输入方式
 type BugDetectorInput struct {
-	Code      string   `json:"code,omitempty"`      // 单文件代码字符串（向后兼容）
-	Files     []string `json:"files,omitempty"`     // 多个文件路径
-	Directory string   `json:"directory,omitempty"` // 目录路径
+	Code      string           `json:"code,omitempty"`       // 单文件代码字符串（向后兼容）
+	Files     []string         `json:"files,omitempty"`      // 多个文件路径
+	Directory string           `json:"directory,omitempty"`  // 目录路径
+	GoVersion string           `json:"go_version,omitempty"` // 代码所属模块的 go 版本（如 "1.21"），为空时从文件所在目录向上查找 go.mod
+	Tags      config.TagConfig `json:"tags,omitempty"`       // 结构体标签检查配置
+
+	MinConfidence string   `json:"min_confidence,omitempty"` // 只报告不低于该置信度的问题：high、medium、low，为空不过滤
+	Categories    []string `json:"categories,omitempty"`     // 只报告这些类别的问题（见 Categories），为空不过滤
+
+	// TypeCheck 用 go/packages 加载文件所在的包，规则按真实类型判断（如调用是否返回 error、接收者能否为 nil），
+	// 误报更少但需要 go 命令和完整的模块；加载失败的文件仍按语法检测。只对 Files、Directory 输入生效
+	TypeCheck bool `json:"type_check,omitempty"`
+
+	// Snapshot 在结果中记录分析的文件集合和内容哈希，SnapshotArchive 不为空时同时把这些文件打包为 tar.gz（隐含 Snapshot）。
+	// 只对 Files、Directory 输入生效
+	Snapshot        bool   `json:"snapshot,omitempty"`
+	SnapshotArchive string `json:"snapshot_archive,omitempty"`
 }
 
 // BugResult 完整的 Bug 检测结果
 type BugResult struct {
+	SchemaVersion   int          `json:"schema_version"`   // 结果结构版本，见 BugResultSchemaVersion
 	Language        string       `json:"language"`         // 检测的语言（go）
 	Status          string       `json:"status"`           // 状态：success, partial, error
 	TotalFiles      int          `json:"total_files"`      // 总文件数
@@ -51,6 +79,10 @@ type BugResult struct {
 	ErrorFiles      []FileStatus `json:"error_files"`      // 解析失败的文件
 	Total           int          `json:"total"`            // 总 Bug 数
 	Bugs            []BugIssue   `json:"bugs"`             // 所有 Bug
+	Suppressed      []BugIssue   `json:"suppressed,omitempty"` // 被 //insight:ignore 注释抑制的问题，不计入 Total
+	TypeChecked     int          `json:"type_checked_files,omitempty"` // 带类型信息检测的文件数（TypeCheck 时）
+	Truncated       *Truncation  `json:"truncated,omitempty"`  // 超出问题数量上限没有列出的问题，仍计入 Total 和 Statistics
+	Snapshot        *Snapshot    `json:"snapshot,omitempty"`   // 分析的文件集合和内容哈希（Snapshot 时）
 	Summary         string       `json:"summary"`          // 摘要
 	Statistics      BugStats     `json:"statistics"`       // 统计信息
 	Recommendations []string     `json:"recommendations"`  // 其他工具的建议
@@ -66,17 +98,26 @@ type FileStatus struct {
 
 // BugIssue 单个 Bug 问题
 type BugIssue struct {
-	ID           string `json:"id"`            // 问题唯一标识
+	ID           string `json:"id"`            // 问题唯一标识（由规则、文件和位置决定）
 	RuleID       string `json:"rule_id"`       // 规则ID
 	Severity     string `json:"severity"`      // 严重程度：High, Medium, Low
 	Category     string `json:"category"`     // 问题类别
 	Description  string `json:"description"`   // 问题描述
 	File         string `json:"file"`          // 文件名
 	Line         int    `json:"line"`          // 行号
+	Column       int    `json:"column"`        // 列号（从 1 开始，按字节计）
 	Function     string `json:"function"`      // 所在函数
+	Receiver     string `json:"receiver,omitempty"` // 所在方法的接收者类型（如 *Server）
+	Exported     bool   `json:"exported"`      // 所在函数是否导出
 	CodeSnippet  string `json:"code_snippet"`  // 代码片段
 	FixSuggestion string `json:"fix_suggestion"` // 修复建议（代码示例）
 	Confidence   string `json:"confidence"`    // 置信度：high, medium, low
+	SuppressionReason string `json:"suppression_reason,omitempty"` // 被 //insight:ignore 抑制时注释中写的原因
+
+	// 以下字段只在 --schema v2 中输出
+	Fingerprint string `json:"fingerprint,omitempty"` // 稳定指纹，不随行号移动而变化
+	Context     string `json:"context,omitempty"`     // 问题前后的代码（带行号）
+	CWE         string `json:"cwe,omitempty"`         // 对应的 CWE 编号
 }
 
 // BugStats Bug 统计
@@ -85,47 +126,70 @@ type BugStats struct {
 	High          int `json:"high"`
 	Medium        int `json:"medium"`
 	Low           int `json:"low"`
+	ByCategory    map[string]int `json:"by_category,omitempty"` // 各类别的问题数
 }
 
-// Run 执行 Bug 检测
-func (bd *BugDetector) Run(ctx context.Context, input any) (string, error) {
-	// 类型断言 - 支持字符串（向后兼容）或 BugDetectorInput
-	var detectorInput BugDetectorInput
-	
-	switch v := input.(type) {
-	case string:
-		detectorInput.Code = v
-	case BugDetectorInput:
-		detectorInput = v
-	default:
-		return "", fmt.Errorf("输入类型错误: 期望 string 或 BugDetectorInput, 实际 %T", input)
+// ConvertInput 兼容代码字符串输入
+func (bd *BugDetector) ConvertInput(input any) (BugDetectorInput, bool) {
+	code, ok := input.(string)
+	return BugDetectorInput{Code: code}, ok
+}
+
+// ValidateInput 验证输入：必须指定代码、文件或目录之一
+func (bd *BugDetector) ValidateInput(detectorInput BugDetectorInput) error {
+	if detectorInput.Code == "" && len(detectorInput.Files) == 0 && detectorInput.Directory == "" {
+		return ErrInvalidInput
+	}
+	if c := detectorInput.Tags.Case; c != "" && tagCases[c] == nil {
+		return fmt.Errorf("%w: 未知的标签命名风格 %q（可选 snake、camel、pascal、kebab）", ErrInvalidInput, c)
 	}
+	if err := validateCategories(detectorInput.Categories); err != nil {
+		return err
+	}
+	return validateMinConfidence(detectorInput.MinConfidence)
+}
 
+// Execute 执行 Bug 检测
+func (bd *BugDetector) Execute(ctx context.Context, detectorInput BugDetectorInput) (*BugResult, error) {
 	// 收集文件
-	goFiles, otherFiles, err := bd.collectFiles(detectorInput)
+	goFiles, otherFiles, err := bd.collectFiles(ctx, detectorInput)
 	if err != nil {
-		return "", fmt.Errorf("文件收集失败: %w", err)
+		return nil, fmt.Errorf("文件收集失败: %w", err)
 	}
 
 	// 如果没有 Go 文件
 	if len(goFiles) == 0 {
-		return bd.buildEmptyResult(len(otherFiles)), nil
+		result := bd.buildEmptyResult(len(otherFiles))
+		if outputSchemaV2(ctx) {
+			result.SchemaVersion = SchemaVersionV2
+		}
+		return result, nil
 	}
 
 	// 分析 Go 文件
-	var allBugs []BugIssue
+	var allBugs, suppressed []BugIssue
+	schemaV2 := outputSchemaV2(ctx)
 	var errorFiles []FileStatus
+	goVersions := make(goVersionCache)
+	var typed map[string]*typedFile
+	if detectorInput.TypeCheck && goFiles[0] != "<code>" {
+		typed = loadTypedFiles(ctx, detectorInput.Directory, goFiles)
+	}
+	typeChecked := 0
 
 	for _, file := range goFiles {
+		if ctx.Err() != nil {
+			return nil, context.Cause(ctx)
+		}
 		var code string
 		var err error
 
 		// 如果是虚拟文件（代码字符串输入），使用输入的代码
 		if file == "<code>" {
-			code = detectorInput.Code
+			code = ai.NormalizeSource(detectorInput.Code)
 		} else {
 			// 读取真实文件
-			fileContent, err := os.ReadFile(file)
+			code, err = readSource(file)
 			if err != nil {
 				errorFiles = append(errorFiles, FileStatus{
 					Path:     file,
@@ -133,13 +197,23 @@ func (bd *BugDetector) Run(ctx context.Context, input any) (string, error) {
 					Status:   "error",
 					Reason:   fmt.Sprintf("读取文件失败: %v", err),
 				})
+				emitFileFindings(ctx, "bug_detector", file, nil, err)
 				continue
 			}
-			code = string(fileContent)
 		}
 
 		// 解析和检测
-		bugs, err := bd.analyzeCode(code, file)
+		opts := ruleOptions{GoVersion: detectorInput.GoVersion, Tags: detectorInput.Tags}
+		if opts.GoVersion == "" && file != "<code>" {
+			opts.GoVersion = goVersions.lookup(filepath.Dir(file))
+		}
+		if opts.Typed = lookupTypedFile(typed, file); opts.Typed != nil {
+			typeChecked++
+		}
+		bugs, err := bd.analyzeCode(ctx, code, file, opts)
+		if ctx.Err() != nil {
+			return nil, context.Cause(ctx)
+		}
 		if err != nil {
 			errorFiles = append(errorFiles, FileStatus{
 				Path:     file,
@@ -147,9 +221,19 @@ func (bd *BugDetector) Run(ctx context.Context, input any) (string, error) {
 				Status:   "error",
 				Reason:   fmt.Sprintf("解析失败: %v", err),
 			})
+			emitFileFindings(ctx, "bug_detector", file, nil, err)
 			continue
 		}
 
+		bugs, fileSuppressed := suppressBugIssues(deduplicateBugIssues(bugs), parseSuppressions(code))
+		if schemaV2 {
+			enrichBugIssuesV2(bugs, code)
+			enrichBugIssuesV2(fileSuppressed, code)
+		}
+		suppressed = append(suppressed, fileSuppressed...)
+		bugs = filterBugsByConfidence(bugs, detectorInput.MinConfidence)
+		bugs = filterByCategory(bugs, detectorInput.Categories, func(b BugIssue) string { return b.Category })
+		bd.emitBugs(ctx, file, bugs)
 		allBugs = append(allBugs, bugs...)
 	}
 
@@ -158,6 +242,7 @@ func (bd *BugDetector) Run(ctx context.Context, input any) (string, error) {
 
 	// 构建结果
 	result := BugResult{
+		SchemaVersion:   BugResultSchemaVersion,
 		Language:        "go",
 		Status:          bd.determineStatus(len(goFiles), len(errorFiles)),
 		TotalFiles:      len(goFiles) + len(otherFiles) + len(errorFiles),
@@ -166,107 +251,94 @@ func (bd *BugDetector) Run(ctx context.Context, input any) (string, error) {
 		ErrorFiles:      errorFiles,
 		Total:           len(allBugs),
 		Bugs:            allBugs,
-		Summary:         bd.generateSummary(len(goFiles), len(allBugs), len(otherFiles)),
+		Suppressed:      suppressed,
+		TypeChecked:     typeChecked,
+		Summary:         bd.generateSummary(len(goFiles), len(allBugs), len(otherFiles)) + suppressedSummary(len(suppressed)),
 		Statistics:      bd.calculateBugStatistics(allBugs),
 		Recommendations: []string{
-			"编译错误请运行: go build ./...",
-			"类型检查请运行: go vet ./...",
-			"格式化代码请运行: go fmt ./...",
+			i18n.T("bug.recommendation.build"),
+			i18n.T("bug.recommendation.vet"),
+			i18n.T("bug.recommendation.fmt"),
 		},
 	}
-
-	// 序列化为 JSON
-	jsonBytes, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return "", fmt.Errorf("序列化结果失败: %w", err)
+	if schemaV2 {
+		result.SchemaVersion = SchemaVersionV2
 	}
-
-	return string(jsonBytes), nil
-}
-
-// collectFiles 收集文件
-func (bd *BugDetector) collectFiles(input BugDetectorInput) ([]string, []FileStatus, error) {
-	var goFiles []string
-	var otherFiles []FileStatus
-
-	// 方式 2: 文件列表（优先判断）
-	if len(input.Files) > 0 {
-		for _, file := range input.Files {
-			lang := DetectLanguage(file)
-			if lang == "go" {
-				if _, err := os.Stat(file); err == nil {
-					goFiles = append(goFiles, file)
-				}
-			} else {
-				otherFiles = append(otherFiles, FileStatus{
-					Path:     file,
-					Language: lang,
-					Status:   "skipped",
-					Reason:   "Bug 检测器仅支持 Go 语言",
-				})
-			}
+	if (detectorInput.Snapshot || detectorInput.SnapshotArchive != "") && goFiles[0] != "<code>" {
+		if result.Snapshot, err = takeSnapshot(ctx, detectorInput.Directory, goFiles, detectorInput.SnapshotArchive); err != nil {
+			return nil, fmt.Errorf("记录分析快照失败: %w", err)
 		}
-		return goFiles, otherFiles, nil
 	}
+	capBugResult(ctx, &result)
 
-	// 方式 3: 目录扫描
-	if input.Directory != "" {
-		err := filepath.Walk(input.Directory, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return nil // 忽略错误，继续扫描
-			}
-
-			if info.IsDir() {
-				// 跳过隐藏目录
-				if strings.HasPrefix(filepath.Base(path), ".") {
-					return filepath.SkipDir
-				}
-				return nil
-			}
+	return &result, nil
+}
 
-			// 只处理 .go 文件
-			lang := DetectLanguage(path)
-			if lang == "go" {
-				goFiles = append(goFiles, path)
-			} else if lang != "unknown" {
-				otherFiles = append(otherFiles, FileStatus{
-					Path:     path,
-					Language: lang,
-					Status:   "skipped",
-					Reason:   "Bug 检测器仅支持 Go 语言",
-				})
-			}
+// emitBugs 上报一个文件的检测结果（流式输出）
+func (bd *BugDetector) emitBugs(ctx context.Context, file string, bugs []BugIssue) {
+	if !streaming(ctx) {
+		return
+	}
+	findings := make([]Finding, 0, len(bugs))
+	for _, bug := range bugs {
+		findings = append(findings, bugFinding(bug))
+	}
+	emitFileFindings(ctx, "bug_detector", file, findings, nil)
+}
 
-			return nil
-		})
-		return goFiles, otherFiles, err
+// collectFiles 收集文件
+func (bd *BugDetector) collectFiles(ctx context.Context, input BugDetectorInput) ([]string, []FileStatus, error) {
+	if len(input.Files) > 0 || input.Directory != "" {
+		return collectSourceFiles(ctx, input.Files, input.Directory, "Bug 检测器仅支持 Go 语言")
 	}
 
-	// 方式 1: 单文件代码字符串（默认方式）
+	// 单文件代码字符串（默认方式）
 	return []string{"<code>"}, []FileStatus{}, nil
 }
 
+// ruleOptions 按文件传给规则的检测选项
+type ruleOptions struct {
+	GoVersion string           // 代码所属模块的 go 版本，未知时为空
+	Tags      config.TagConfig // 结构体标签检查配置
+	Typed     *typedFile       // 文件的类型信息，没有加载时为 nil
+}
+
 // analyzeCode 分析代码
-func (bd *BugDetector) analyzeCode(code, filename string) ([]BugIssue, error) {
+// 每进入一个函数声明检查一次 ctx，取消后停止遍历，由调用方返回取消原因
+func (bd *BugDetector) analyzeCode(ctx context.Context, code, filename string, opts ruleOptions) ([]BugIssue, error) {
 	fset := token.NewFileSet()
-
-	node, err := parser.ParseFile(fset, filename, code, parser.ParseComments)
-	if err != nil {
-		return nil, fmt.Errorf("解析失败: %w", err)
+	var node *ast.File
+	if opts.Typed != nil {
+		// 直接使用加载包时的语法树，类型信息中的节点与之对应
+		fset, node = opts.Typed.FSet, opts.Typed.File
+	} else {
+		var err error
+		node, err = parser.ParseFile(fset, filename, code, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("解析失败: %w", err)
+		}
 	}
 
 	var bugs []BugIssue
-	ruleCtx := &BugRuleContext{FSet: fset, Filename: filename}
+	ruleCtx := &BugRuleContext{FSet: fset, Filename: filename, File: node, GoVersion: opts.GoVersion, Tags: opts.Tags}
+	if opts.Typed != nil {
+		ruleCtx.Types, ruleCtx.Pkg, ruleCtx.typed = opts.Typed.Info, opts.Typed.Pkg, opts.Typed
+	}
 
 	ast.Inspect(node, func(n ast.Node) bool {
 		if n == nil {
 			return false
 		}
+		if _, ok := n.(*ast.FuncDecl); ok && ctx.Err() != nil {
+			return false
+		}
+		ruleCtx.CurrentFunc = enterFunc(ruleCtx.CurrentFunc, n)
 
 		// 应用所有规则
 		for _, rule := range bd.ruleEngine.Rules {
 			if rule.Match(n, ruleCtx) {
-				bug := buildBugIssue(rule, n, fset, code, filename)
+				bug := buildBugIssue(rule, n, fset, code, filename, ruleCtx.CurrentFunc)
+				bug.Severity = bd.ruleEngine.Settings.severity(rule.ID(), bug.Severity)
 				bugs = append(bugs, bug)
 			}
 		}
@@ -311,8 +383,9 @@ func (bd *BugDetector) determineStatus(goFiles, errorFiles int) string {
 }
 
 // buildEmptyResult 构建空结果（没有 Go 文件）
-func (bd *BugDetector) buildEmptyResult(skippedCount int) string {
-	result := BugResult{
+func (bd *BugDetector) buildEmptyResult(skippedCount int) *BugResult {
+	return &BugResult{
+		SchemaVersion:   BugResultSchemaVersion,
 		Language:        "go",
 		Status:          "success",
 		TotalFiles:      skippedCount,
@@ -321,34 +394,31 @@ func (bd *BugDetector) buildEmptyResult(skippedCount int) string {
 		ErrorFiles:      make([]FileStatus, 0),
 		Total:           0,
 		Bugs:            make([]BugIssue, 0),
-		Summary:         "未检测到 Go 文件",
+		Summary:         i18n.T("bug.summary.no_go_files"),
 		Statistics:      BugStats{},
 		Recommendations: []string{
-			"Bug 检测器仅支持 Go 语言",
+			i18n.T("bug.recommendation.go_only"),
 		},
 	}
-
-	jsonBytes, _ := json.MarshalIndent(result, "", "  ")
-	return string(jsonBytes)
 }
 
 // generateSummary 生成摘要
 func (bd *BugDetector) generateSummary(goFiles, bugCount, skippedCount int) string {
 	if goFiles == 0 {
-		return "未检测到 Go 文件"
+		return i18n.T("bug.summary.no_go_files")
 	}
 
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("分析完成，共 %d 个 Go 文件", goFiles))
+	sb.WriteString(i18n.T("bug.summary.analyzed", goFiles))
 
 	if bugCount > 0 {
-		sb.WriteString(fmt.Sprintf("，检测到 %d 个 Bug", bugCount))
+		sb.WriteString(i18n.T("bug.summary.found", bugCount))
 	} else {
-		sb.WriteString("，未检测到 Bug ✅")
+		sb.WriteString(i18n.T("bug.summary.none"))
 	}
 
 	if skippedCount > 0 {
-		sb.WriteString(fmt.Sprintf("，跳过 %d 个非 Go 文件", skippedCount))
+		sb.WriteString(i18n.T("bug.summary.skipped", skippedCount))
 	}
 
 	return sb.String()
@@ -358,6 +428,7 @@ func (bd *BugDetector) generateSummary(goFiles, bugCount, skippedCount int) stri
 func (bd *BugDetector) calculateBugStatistics(bugs []BugIssue) BugStats {
 	stats := BugStats{
 		TotalIssues: len(bugs),
+		ByCategory:  countByCategory(bugs, func(b BugIssue) string { return b.Category }),
 	}
 
 	for _, bug := range bugs {
@@ -376,13 +447,54 @@ func (bd *BugDetector) calculateBugStatistics(bugs []BugIssue) BugStats {
 
 // BugRuleContext Bug 规则检测上下文
 type BugRuleContext struct {
-	FSet     *token.FileSet
-	Filename string
+	FSet      *token.FileSet
+	Filename  string
+	File      *ast.File        // 当前文件的语法树，需要整个函数信息的规则使用
+	GoVersion string           // 文件所属模块 go.mod 中的 go 版本（如 "1.21"），未知时为空
+	Tags      config.TagConfig // 结构体标签检查配置
+	Types     *types.Info      // 类型信息（TypeCheck 且包加载成功时），否则为 nil，规则只能按语法判断
+	Pkg       *types.Package   // 当前文件所属的包，没有类型信息时为 nil
+	typed     *typedFile       // 加载到的类型信息，含同包的其他文件
+
+	CurrentFunc *ast.FuncDecl // 遍历中当前节点所在的函数声明，不在函数中时为 nil
+
+	memo map[string]any // 按文件缓存的分析结果
+}
+
+// Memo 返回本文件的分析结果，同一 key 只计算一次
+// 需要数据流信息的规则在第一次 Match 时分析整个文件，之后按节点查表
+func (ctx *BugRuleContext) Memo(key string, compute func() any) any {
+	if ctx.memo == nil {
+		ctx.memo = make(map[string]any)
+	}
+	if v, ok := ctx.memo[key]; ok {
+		return v
+	}
+	v := compute()
+	ctx.memo[key] = v
+	return v
+}
+
+// matchFileFinding 查表判断节点是否命中规则：find 一次分析整个文件，返回每个节点命中的规则 ID
+// 同一次分析覆盖多条规则时（按 key 缓存），各规则共享分析结果
+func matchFileFinding(node ast.Node, ctx *BugRuleContext, key string, find func(*ast.File) map[ast.Node][]string, ruleID string) bool {
+	if ctx.File == nil {
+		return false
+	}
+	found := ctx.Memo(key, func() any { return find(ctx.File) }).(map[ast.Node][]string)
+	for _, id := range found[node] {
+		if id == ruleID {
+			return true
+		}
+	}
+	return false
 }
 
 // BugRuleEngine Bug 规则引擎
+// 规则只在构造时注册，之后只读，可被并发的检测共享
 type BugRuleEngine struct {
-	Rules []BugRule
+	Rules    []BugRule
+	Settings RuleSettings // 注册前参考的规则开关和严重程度覆盖
 }
 
 // NewBugRuleEngine 创建规则引擎
@@ -392,17 +504,58 @@ func NewBugRuleEngine() *BugRuleEngine {
 	}
 }
 
-// Register 注册规则
+// Register 注册规则，配置中关闭的规则跳过
 func (bre *BugRuleEngine) Register(rule BugRule) {
+	if !bre.Settings.enabled(rule.ID()) {
+		return
+	}
 	bre.Rules = append(bre.Rules, rule)
 }
 
-// RegisterAllRules 注册所有默认规则
+// RegisterAllRules 注册所有默认规则，以及经由 RegisterBugRule 或规则插件注册的外部规则
 func (bre *BugRuleEngine) RegisterAllRules() {
+	bre.registerBuiltinRules()
+	for _, rule := range externalBugRules() {
+		bre.Register(rule)
+	}
+}
+
+// registerBuiltinRules 注册所有默认规则
+func (bre *BugRuleEngine) registerBuiltinRules() {
 	bre.Register(&IgnoredErrorRule{})
 	bre.Register(&ResourceNotClosedRule{})
 	bre.Register(&SwitchWithoutDefaultRule{})
 	bre.Register(&PotentialNilPointerRule{})
+	bre.Register(&ErrorfWithoutWrapRule{})
+	bre.Register(&IneffectiveAssignRule{})
+	bre.Register(&ErrShadowRule{})
+	bre.Register(&WriteOnlyFieldRule{})
+	bre.Register(&NilMapWriteRule{})
+	bre.Register(&WaitGroupAddInGoroutineRule{})
+	bre.Register(&NilChannelRule{})
+	bre.Register(&LoopVarCaptureRule{})
+	bre.Register(&WallClockElapsedRule{})
+	bre.Register(&TimeParseZoneRule{})
+	bre.Register(&DurationDoubleUnitRule{})
+	bre.Register(&MissingJSONTagRule{})
+	bre.Register(&DuplicateTagNameRule{})
+	bre.Register(&TagOptionMistakeRule{})
+	bre.Register(&TagNameCaseRule{})
+	bre.Register(&RowsNotClosedRule{})
+	bre.Register(&RowsErrNotCheckedRule{})
+	bre.Register(&ErrNoRowsUnhandledRule{})
+	bre.Register(&TxNoDeferRollbackRule{})
+	bre.Register(&ResponseBodyNotClosedRule{})
+	bre.Register(&HTTPClientPerRequestRule{})
+	bre.Register(&OutboundCallWithoutContextRule{})
+	bre.Register(&GRPCDialRule{})
+	bre.Register(&GoroutineLeakRule{})
+	bre.Register(&UncheckedTypeAssertRule{})
+	bre.Register(&DeferInLoopRule{})
+	bre.Register(&TickerNotStoppedRule{})
+	bre.Register(&PrintfMismatchRule{})
+	bre.Register(&ConcurrentMapWriteRule{})
+	bre.Register(&LockCopyRule{})
 }
 
 // BugRule Bug 规则接口
@@ -422,13 +575,17 @@ type IgnoredErrorRule struct{}
 func (r *IgnoredErrorRule) ID() string          { return "B101" }
 func (r *IgnoredErrorRule) Name() string        { return "Ignored Error Return Value" }
 func (r *IgnoredErrorRule) Severity() string    { return "High" }
-func (r *IgnoredErrorRule) Category() string    { return "Error Handling" }
+func (r *IgnoredErrorRule) Category() string    { return CategoryErrorHandling }
 func (r *IgnoredErrorRule) Description() string { return "忽略了错误返回值" }
 func (r *IgnoredErrorRule) GenerateSuggestion(node ast.Node) string {
 	return "检查错误：\nfile, err := os.Open(\"file.txt\")\nif err != nil {\n    return err\n}"
 }
 
 func (r *IgnoredErrorRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	// 有类型信息时只看被丢弃的返回值是否真的是 error
+	if assign, ok := node.(*ast.AssignStmt); ok && ctx.Typed() {
+		return ignoresErrorResult(assign, ctx)
+	}
 	if assign, ok := node.(*ast.AssignStmt); ok {
 		for _, lhs := range assign.Lhs {
 			if ident, ok := lhs.(*ast.Ident); ok {
@@ -456,22 +613,14 @@ type ResourceNotClosedRule struct{}
 func (r *ResourceNotClosedRule) ID() string          { return "B102" }
 func (r *ResourceNotClosedRule) Name() string        { return "Resource Not Closed" }
 func (r *ResourceNotClosedRule) Severity() string    { return "High" }
-func (r *ResourceNotClosedRule) Category() string    { return "Resource Management" }
-func (r *ResourceNotClosedRule) Description() string { return "打开文件/连接但没有 defer close()" }
+func (r *ResourceNotClosedRule) Category() string    { return CategoryResourceMgmt }
+func (r *ResourceNotClosedRule) Description() string { return "打开文件后既没有 defer Close，也没有在每条返回路径上 Close" }
 func (r *ResourceNotClosedRule) GenerateSuggestion(node ast.Node) string {
 	return "使用 defer 确保资源释放：\nfile, err := os.Open(\"file.txt\")\nif err != nil {\n    return err\n}\ndefer file.Close()"
 }
 
 func (r *ResourceNotClosedRule) Match(node ast.Node, ctx *BugRuleContext) bool {
-	if callExpr, ok := node.(*ast.CallExpr); ok {
-		// 检测打开文件的函数调用
-		if isFileOpenFunction(callExpr) {
-			// 检查下一个语句（简化版：10 行内）是否有 defer
-			// 注意：这是简化版，可能会误报
-			return true
-		}
-	}
-	return false
+	return matchUnreleased(node, ctx, "B102")
 }
 
 // 规则 3: switch 缺少 default
@@ -480,7 +629,7 @@ type SwitchWithoutDefaultRule struct{}
 func (r *SwitchWithoutDefaultRule) ID() string          { return "B103" }
 func (r *SwitchWithoutDefaultRule) Name() string        { return "Switch Without Default" }
 func (r *SwitchWithoutDefaultRule) Severity() string    { return "Low" }
-func (r *SwitchWithoutDefaultRule) Category() string    { return "Control Flow" }
+func (r *SwitchWithoutDefaultRule) Category() string    { return CategoryMaintainability }
 func (r *SwitchWithoutDefaultRule) Description() string { return "switch 语句没有 default 分支" }
 func (r *SwitchWithoutDefaultRule) GenerateSuggestion(node ast.Node) string {
 	return "添加 default 分支处理未知情况：\nswitch x {\ncase 1:\n    ...\ndefault:\n    ...\n}"
@@ -504,27 +653,139 @@ func (r *SwitchWithoutDefaultRule) Match(node ast.Node, ctx *BugRuleContext) boo
 	return false
 }
 
-// 规则 4: 可能的 nil 指针引用（简化版）
+// 规则 4: 可能的 nil 指针引用
 type PotentialNilPointerRule struct{}
 
 func (r *PotentialNilPointerRule) ID() string          { return "B104" }
 func (r *PotentialNilPointerRule) Name() string        { return "Potential Nil Pointer Dereference" }
 func (r *PotentialNilPointerRule) Severity() string    { return "Medium" }
-func (r *PotentialNilPointerRule) Category() string    { return "Null Safety" }
-func (r *PotentialNilPointerRule) Description() string { return "对可能为 nil 的指针调用方法" }
+func (r *PotentialNilPointerRule) Category() string    { return CategoryNullSafety }
+func (r *PotentialNilPointerRule) Description() string { return "可能为 nil 的指针或接口在没有判断 nil 的路径上被解引用" }
 func (r *PotentialNilPointerRule) GenerateSuggestion(node ast.Node) string {
-	return "检查 nil：\nif ptr != nil {\n    ptr.Method()\n}"
+	return "在所有路径上初始化变量，或使用前检查 nil：\nif ptr != nil {\n    ptr.Method()\n}"
 }
 
+// Match 基于 SSA 的数据流分析（见 nilnessAnalysis），需要能对文件做类型检查
 func (r *PotentialNilPointerRule) Match(node ast.Node, ctx *BugRuleContext) bool {
-	if callExpr, ok := node.(*ast.CallExpr); ok {
-		if _, ok := callExpr.Fun.(*ast.SelectorExpr); ok {
-			// 简化版：只检测明显场景
-			// 完整版需要数据流分析
-			return true
+	switch node.(type) {
+	case *ast.SelectorExpr, *ast.StarExpr, *ast.IndexExpr, *ast.CallExpr:
+	default:
+		return false
+	}
+	if ctx.File == nil {
+		return false
+	}
+	return ctx.nilDerefNodes()[node]
+}
+
+// 规则 5: fmt.Errorf 使用 %v 包装 error（丢失错误链）
+type ErrorfWithoutWrapRule struct{}
+
+func (r *ErrorfWithoutWrapRule) ID() string          { return "B105" }
+func (r *ErrorfWithoutWrapRule) Name() string        { return "Error Not Wrapped" }
+func (r *ErrorfWithoutWrapRule) Severity() string    { return "Low" }
+func (r *ErrorfWithoutWrapRule) Category() string    { return CategoryErrorHandling }
+func (r *ErrorfWithoutWrapRule) Description() string { return "fmt.Errorf 使用 %v 格式化 error，调用方无法用 errors.Is/As 判断" }
+func (r *ErrorfWithoutWrapRule) GenerateSuggestion(node ast.Node) string {
+	return "使用 %w 包装错误：\nreturn fmt.Errorf(\"读取配置失败: %w\", err)"
+}
+
+func (r *ErrorfWithoutWrapRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	callExpr, ok := node.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	_, ok = errorfUnwrappedVerb(callExpr)
+	return ok
+}
+
+// errorfUnwrappedVerb 查找 fmt.Errorf 中用 %v 格式化 error 变量的动词
+// 只有格式串中没有 %w 且恰好一个 error 参数使用 %v 时才返回
+func errorfUnwrappedVerb(callExpr *ast.CallExpr) (printfVerb, bool) {
+	selExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+	if !ok || selExpr.Sel.Name != "Errorf" {
+		return printfVerb{}, false
+	}
+	if ident, ok := selExpr.X.(*ast.Ident); !ok || ident.Name != "fmt" {
+		return printfVerb{}, false
+	}
+	if len(callExpr.Args) < 2 {
+		return printfVerb{}, false
+	}
+	lit, ok := callExpr.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return printfVerb{}, false
+	}
+
+	verbs, ok := parsePrintfVerbs(lit.Value)
+	if !ok {
+		return printfVerb{}, false
+	}
+
+	var found []printfVerb
+	for _, verb := range verbs {
+		if verb.Verb == 'w' {
+			return printfVerb{}, false
+		}
+		argIndex := verb.ArgIndex + 1
+		if verb.Verb != 'v' || argIndex >= len(callExpr.Args) {
+			continue
+		}
+		if ident, ok := callExpr.Args[argIndex].(*ast.Ident); ok && isErrorVarName(ident.Name) {
+			found = append(found, verb)
 		}
 	}
-	return false
+	if len(found) != 1 {
+		return printfVerb{}, false
+	}
+	return found[0], true
+}
+
+// printfVerb 格式串中的一个动词
+type printfVerb struct {
+	Verb     byte // 动词字符，如 'v'、'd'
+	Offset   int  // 动词字符在格式串中的字节偏移
+	ArgIndex int  // 对应的参数序号（从 0 开始，不含格式串本身）
+}
+
+// parsePrintfVerbs 解析格式串中的动词及其对应参数
+// 不支持显式参数索引（如 %[1]d），遇到时返回 false
+func parsePrintfVerbs(format string) ([]printfVerb, bool) {
+	var verbs []printfVerb
+	argIndex := 0
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		if i < len(format) && format[i] == '%' {
+			continue
+		}
+
+		// 标志、宽度、精度
+		for i < len(format) && strings.IndexByte("+-# 0123456789.*[", format[i]) != -1 {
+			switch format[i] {
+			case '[':
+				return nil, false
+			case '*':
+				argIndex++
+			}
+			i++
+		}
+		if i >= len(format) {
+			break
+		}
+
+		verbs = append(verbs, printfVerb{Verb: format[i], Offset: i, ArgIndex: argIndex})
+		argIndex++
+	}
+	return verbs, true
+}
+
+// isErrorVarName 判断变量名是否像 error 变量
+func isErrorVarName(name string) bool {
+	return name == "err" || strings.HasSuffix(name, "Err") || strings.HasSuffix(name, "Error")
 }
 
 // 辅助函数：判断是否是可能返回错误的函数
@@ -571,10 +832,10 @@ func isErrorReturningFunction(callExpr *ast.CallExpr) bool {
 func isFileOpenFunction(callExpr *ast.CallExpr) bool {
 	if selExpr, ok := callExpr.Fun.(*ast.SelectorExpr); ok {
 		if ident, ok := selExpr.X.(*ast.Ident); ok {
-			// os.Open, os.Create, os.OpenFile, os.WriteFile
+			// os.Open, os.Create, os.OpenFile
 			if ident.Name == "os" {
 				fun := selExpr.Sel.Name
-				openFuncs := []string{"Open", "Create", "OpenFile", "WriteFile"}
+				openFuncs := []string{"Open", "Create", "OpenFile"}
 				for _, f := range openFuncs {
 					if fun == f {
 						return true
@@ -587,7 +848,7 @@ func isFileOpenFunction(callExpr *ast.CallExpr) bool {
 }
 
 // 辅助函数：构建 Bug 问题
-func buildBugIssue(rule BugRule, node ast.Node, fset *token.FileSet, code, filename string) BugIssue {
+func buildBugIssue(rule BugRule, node ast.Node, fset *token.FileSet, code, filename string, fn *ast.FuncDecl) BugIssue {
 	position := fset.Position(node.Pos())
 	line := position.Line
 
@@ -601,51 +862,86 @@ func buildBugIssue(rule BugRule, node ast.Node, fset *token.FileSet, code, filen
 		}
 	}
 
-	// 查找所在函数
-	var funcName string
-	ast.Inspect(node, func(n ast.Node) bool {
-		if fn, ok := n.(*ast.FuncDecl); ok {
-			if fn.Pos() < node.Pos() && node.Pos() < fn.End() {
-				funcName = fn.Name.Name
-				return false
-			}
-		}
-		return true
-	})
+	funcName, receiver, exported := funcMetadata(fn)
 
 	// 确定置信度
-	confidence := "medium"
+	confidence := ConfidenceMedium
 	switch rule.ID() {
-	case "B101", "B103": // 明确的模式
-		confidence = "high"
+	case "B101", "B103", "B105", "B129", "B132": // 明确的模式
+		confidence = ConfidenceHigh
 	case "B102": // 可能误报
-		confidence = "medium"
-	case "B104": // 简化版，可能误报
-		confidence = "low"
+		confidence = ConfidenceMedium
+	case "B104": // 数据流分析不考虑条件之间的关联，可能误报
+		confidence = ConfidenceMedium
 	}
 
 	return BugIssue{
-		ID:           fmt.Sprintf("bug-%d", position.Offset),
+		ID:           bugIssueID(rule.ID(), filename, line, position.Column),
 		RuleID:       rule.ID(),
 		Severity:     rule.Severity(),
-		Category:     rule.Category(),
-		Description:  rule.Description(),
+		Category:     NormalizeCategory(rule.Category()),
+		Description:  ruleText(rule.ID(), "description", rule.Description()),
 		File:         filename,
 		Line:         line,
+		Column:       position.Column,
 		Function:     funcName,
+		Receiver:     receiver,
+		Exported:     exported,
 		CodeSnippet:  codeSnippet,
-		FixSuggestion: rule.GenerateSuggestion(node),
+		FixSuggestion: ruleText(rule.ID(), "suggestion", rule.GenerateSuggestion(node)),
 		Confidence:   confidence,
 	}
 }
 
+// bugIssueID 问题 ID，由规则、文件和位置决定：同一行上不同规则的问题、不同文件相同偏移处的问题 ID 都不相同
+func bugIssueID(ruleID, file string, line, column int) string {
+	return "bug-" + FindingFingerprint(ruleID, file, fmt.Sprintf("%d:%d", line, column))
+}
+
+// filterBugsByConfidence 只保留置信度不低于 min 的问题
+func filterBugsByConfidence(bugs []BugIssue, min string) []BugIssue {
+	if min == "" {
+		return bugs
+	}
+	kept := bugs[:0]
+	for _, bug := range bugs {
+		if meetsConfidence(bug.Confidence, min) {
+			kept = append(kept, bug)
+		}
+	}
+	return kept
+}
+
+// enterFunc 遍历到节点 n 时更新所在的函数声明
+// 函数声明只出现在文件顶层，节点位于当前函数之后说明已经离开该函数
+func enterFunc(current *ast.FuncDecl, n ast.Node) *ast.FuncDecl {
+	if fn, ok := n.(*ast.FuncDecl); ok {
+		return fn
+	}
+	if current != nil && n.Pos() >= current.End() {
+		return nil
+	}
+	return current
+}
+
+// funcMetadata 返回函数名、方法接收者类型和函数是否导出
+func funcMetadata(fn *ast.FuncDecl) (name, receiver string, exported bool) {
+	if fn == nil {
+		return "", "", false
+	}
+	if fn.Recv != nil && len(fn.Recv.List) > 0 {
+		receiver = types.ExprString(fn.Recv.List[0].Type)
+	}
+	return fn.Name.Name, receiver, fn.Name.IsExported()
+}
+
 // 辅助函数：去重 Bug 问题
 func deduplicateBugIssues(bugs []BugIssue) []BugIssue {
 	seen := make(map[string]bool)
 	result := []BugIssue{}
 
 	for _, bug := range bugs {
-		key := fmt.Sprintf("%s-%d-%d", bug.RuleID, bug.Line, len(bug.File))
+		key := fmt.Sprintf("%s|%s|%d", bug.RuleID, bug.File, bug.Line)
 		if !seen[key] {
 			seen[key] = true
 			result = append(result, bug)
@@ -0,0 +1,361 @@
+package tools
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"sort"
+	"sync"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// nilState 数据流中一个值是否为 nil
+type nilState int
+
+const (
+	nilUnknown nilState = iota
+	nilDefinite
+	nilNever
+)
+
+// nilFacts 沿支配树传递的已知事实：由 if x == nil / x != nil 的分支或之前的解引用得出
+type nilFacts map[ssa.Value]nilState
+
+// with 返回加入一条事实后的副本，兄弟分支之间互不影响
+func (f nilFacts) with(v ssa.Value, state nilState) nilFacts {
+	next := make(nilFacts, len(f)+1)
+	for k, s := range f {
+		next[k] = s
+	}
+	next[v] = state
+	return next
+}
+
+// nilnessCache 按包缓存的 nil 解引用位置，同一个包的文件共用一次 SSA 构建
+type nilnessCache struct {
+	once      sync.Once
+	positions map[token.Pos]bool
+}
+
+// get 第一次调用时构建 SSA 并分析，build 返回 nil 时没有结果
+func (c *nilnessCache) get(build func() *ssa.Package) map[token.Pos]bool {
+	c.once.Do(func() {
+		c.positions = map[token.Pos]bool{}
+		if pkg := build(); pkg != nil {
+			c.positions = findNilDerefs(pkg)
+		}
+	})
+	return c.positions
+}
+
+// lockedImporter 加锁后可被并发的检测共用的导入器
+type lockedImporter struct {
+	mu  sync.Mutex
+	imp types.Importer
+}
+
+func (l *lockedImporter) Import(path string) (*types.Package, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.imp.Import(path)
+}
+
+// fileImporter 单文件类型检查使用的导入器，按编译产物解析标准库等已编译的包，进程内缓存
+var fileImporter = &lockedImporter{imp: importer.Default()}
+
+// buildTypedSSA 用 go/packages 加载的整个包构建 SSA，导入的包只创建类型信息
+func buildTypedSSA(t *typedFile) *ssa.Package {
+	prog := ssa.NewProgram(t.FSet, 0)
+	created := make(map[*types.Package]bool)
+	var createAll func(pkgs []*types.Package)
+	createAll = func(pkgs []*types.Package) {
+		for _, p := range pkgs {
+			if !created[p] {
+				created[p] = true
+				prog.CreatePackage(p, nil, nil, true)
+				createAll(p.Imports())
+			}
+		}
+	}
+	createAll(t.Pkg.Imports())
+	pkg := prog.CreatePackage(t.Pkg, t.Syntax, t.Info, false)
+	pkg.Build()
+	return pkg
+}
+
+// buildFileSSA 单独对一个文件做类型检查并构建 SSA
+// 只能解析已编译的包（主要是标准库），引用同包其他文件或无法导入的包时类型检查失败，返回 nil
+func buildFileSSA(fset *token.FileSet, file *ast.File) *ssa.Package {
+	conf := &types.Config{Importer: fileImporter}
+	pkg := types.NewPackage(file.Name.Name, file.Name.Name)
+	ssaPkg, _, err := ssautil.BuildPackage(conf, fset, pkg, []*ast.File{file}, 0)
+	if err != nil {
+		return nil
+	}
+	return ssaPkg
+}
+
+// nilDerefNodes 当前文件中可能解引用 nil 的语法节点（B104）
+// 有类型信息时用加载的整个包构建 SSA，否则单独对本文件做类型检查，失败时没有结果
+func (ctx *BugRuleContext) nilDerefNodes() map[ast.Node]bool {
+	return ctx.Memo("nilness", func() any {
+		var positions map[token.Pos]bool
+		if t := ctx.typed; t != nil && t.nilness != nil {
+			positions = t.nilness.get(func() *ssa.Package { return buildTypedSSA(t) })
+		} else if pkg := buildFileSSA(ctx.FSet, ctx.File); pkg != nil {
+			positions = findNilDerefs(pkg)
+		}
+
+		// SSA 指令的位置落在选择器、解引用、下标或调用表达式上，取包含该位置的最内层此类节点
+		nodes := make(map[ast.Node]bool)
+		for pos := range positions {
+			if pos < ctx.File.FileStart || pos > ctx.File.FileEnd {
+				continue
+			}
+			path, _ := astutil.PathEnclosingInterval(ctx.File, pos, pos)
+			for _, n := range path {
+				switch n.(type) {
+				case *ast.SelectorExpr, *ast.StarExpr, *ast.IndexExpr, *ast.CallExpr:
+					nodes[n] = true
+				default:
+					continue
+				}
+				break
+			}
+		}
+		return nodes
+	}).(map[ast.Node]bool)
+}
+
+// nilnessAnalysis 在 SSA 上查找可能为 nil 的指针或接口被解引用的位置
+// 只跟踪明确为 nil 的来源（nil 常量，以及经过 phi 合并后可能是 nil 常量的变量），
+// 参数、函数返回值等来源未知的值不报告；支配当前位置的 if x != nil 分支和之前的解引用都说明 x 不为 nil
+type nilnessAnalysis struct {
+	receiverDeref map[*ssa.Function]bool // 方法在接收者为 nil 时是否会解引用它
+}
+
+// findNilDerefs 分析包中所有函数（含闭包），返回可能解引用 nil 的指令位置
+func findNilDerefs(pkg *ssa.Package) map[token.Pos]bool {
+	a := &nilnessAnalysis{receiverDeref: make(map[*ssa.Function]bool)}
+	found := make(map[token.Pos]bool)
+	for fn := range ssautil.AllFunctions(pkg.Prog) {
+		if fn.Pkg != pkg || fn.Synthetic != "" {
+			continue
+		}
+		a.walk(fn, nil, func(instr ssa.Instruction) bool {
+			if pos := instrPos(instr); pos.IsValid() {
+				found[pos] = true
+			}
+			return true
+		})
+	}
+	return found
+}
+
+// walk 按支配树遍历函数，可能为 nil 的值被解引用时调用 report，report 返回 false 时停止
+func (a *nilnessAnalysis) walk(fn *ssa.Function, facts nilFacts, report func(ssa.Instruction) bool) {
+	if len(fn.Blocks) == 0 {
+		return
+	}
+	atEnd := make(map[*ssa.BasicBlock]nilFacts)
+	stopped := false
+	var visit func(b *ssa.BasicBlock, facts nilFacts)
+	visit = func(b *ssa.BasicBlock, facts nilFacts) {
+		for _, instr := range b.Instrs {
+			v := a.dereferenced(instr)
+			if v == nil {
+				continue
+			}
+			if a.mayBeNil(v, facts, atEnd) && !report(instr) {
+				stopped = true
+				return
+			}
+			// 解引用之后的代码能执行到，说明 v 不为 nil
+			facts = facts.with(v, nilNever)
+		}
+		atEnd[b] = facts
+
+		// 按块序号访问，合并点的前驱通常先于合并点完成分析
+		children := append([]*ssa.BasicBlock(nil), b.Dominees()...)
+		sort.Slice(children, func(i, j int) bool { return children[i].Index < children[j].Index })
+		for _, child := range children {
+			if stopped {
+				return
+			}
+			next := facts
+			if len(child.Preds) == 1 {
+				var reachable bool
+				if next, reachable = edgeFacts(b, child, facts); !reachable {
+					continue // 与 nil 判断矛盾的分支执行不到，其中的解引用不报告
+				}
+			}
+			visit(child, next)
+		}
+	}
+	visit(fn.Blocks[0], facts)
+}
+
+// edgeFacts 从 from 跳转到 to 时成立的事实：from 以 if x == nil / x != nil 结束时按分支加入 x 的状态
+// 分支与已知的状态矛盾时（明确为 nil 的 x 走 x != nil 的分支）这条边执行不到，返回 false
+func edgeFacts(from, to *ssa.BasicBlock, facts nilFacts) (nilFacts, bool) {
+	if len(from.Instrs) == 0 {
+		return facts, true
+	}
+	ifInstr, ok := from.Instrs[len(from.Instrs)-1].(*ssa.If)
+	if !ok || from.Succs[0] == from.Succs[1] {
+		return facts, true
+	}
+	cond, ok := ifInstr.Cond.(*ssa.BinOp)
+	if !ok || (cond.Op != token.EQL && cond.Op != token.NEQ) {
+		return facts, true
+	}
+	x := cond.X
+	if isNilConst(x) {
+		x = cond.Y
+	} else if !isNilConst(cond.Y) {
+		return facts, true
+	}
+	// Succs[0] 是条件成立的分支
+	state := nilNever
+	if (to == from.Succs[0]) == (cond.Op == token.EQL) {
+		state = nilDefinite
+	}
+	if known := knownNilState(x, facts); known != nilUnknown && known != state {
+		return facts, false
+	}
+	return facts.with(x, state), true
+}
+
+// knownNilState 当前事实下 x 确定的状态：nil 常量明确为 nil，其余按已知事实
+func knownNilState(x ssa.Value, facts nilFacts) nilState {
+	if isNilConst(x) {
+		return nilDefinite
+	}
+	return facts[x]
+}
+
+// mayBeNil 在当前事实下 v 是否可能为 nil：nil 常量，或合并了 nil 常量且该路径上没有排除 nil 的 phi
+func (a *nilnessAnalysis) mayBeNil(v ssa.Value, facts nilFacts, atEnd map[*ssa.BasicBlock]nilFacts) bool {
+	if state, ok := facts[v]; ok {
+		return state == nilDefinite
+	}
+	seen := make(map[*ssa.Phi]bool)
+	var fromNil func(v ssa.Value) bool
+	fromNil = func(v ssa.Value) bool {
+		switch v := v.(type) {
+		case *ssa.Const:
+			return isNilConst(v)
+		case *ssa.Phi:
+			if seen[v] {
+				return false
+			}
+			seen[v] = true
+			for i, edge := range v.Edges {
+				// 前驱已经分析过时按该路径上的事实判断，如 if p == nil { p = new(T) } 之后的合并
+				if predFacts, done := atEnd[v.Block().Preds[i]]; done {
+					pathFacts, reachable := edgeFacts(v.Block().Preds[i], v.Block(), predFacts)
+					if !reachable {
+						continue
+					}
+					if state, ok := pathFacts[edge]; ok {
+						if state == nilDefinite {
+							return true
+						}
+						continue
+					}
+				}
+				if fromNil(edge) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return fromNil(v)
+}
+
+// dereferenced 指令会解引用的指针或接口，没有时返回 nil
+// 调用方法时接收者为 nil 不一定出错，只有方法本身在接收者为 nil 时会解引用它才算
+func (a *nilnessAnalysis) dereferenced(instr ssa.Instruction) ssa.Value {
+	switch i := instr.(type) {
+	case *ssa.FieldAddr:
+		return i.X
+	case *ssa.IndexAddr:
+		if _, ok := i.X.Type().Underlying().(*types.Pointer); ok {
+			return i.X
+		}
+	case *ssa.UnOp:
+		if i.Op == token.MUL {
+			return i.X
+		}
+	case *ssa.Store:
+		return i.Addr
+	case *ssa.Call:
+		if i.Call.IsInvoke() {
+			return i.Call.Value
+		}
+		if callee := i.Call.StaticCallee(); callee != nil && callee.Signature.Recv() != nil && len(i.Call.Args) > 0 && a.derefsReceiver(callee) {
+			return i.Call.Args[0]
+		}
+	}
+	return nil
+}
+
+// derefsReceiver 方法在接收者为 nil 时是否会解引用它（没有先判断 nil）
+// 只分析有函数体的方法；递归调用按不解引用处理
+func (a *nilnessAnalysis) derefsReceiver(fn *ssa.Function) bool {
+	if derefs, ok := a.receiverDeref[fn]; ok {
+		return derefs
+	}
+	a.receiverDeref[fn] = false
+	if len(fn.Params) == 0 || len(fn.Blocks) == 0 {
+		return false
+	}
+	recv := fn.Params[0]
+	derefs := false
+	a.walk(fn, nilFacts{recv: nilDefinite}, func(instr ssa.Instruction) bool {
+		if a.dereferenced(instr) == recv {
+			derefs = true
+			return false
+		}
+		return true
+	})
+	a.receiverDeref[fn] = derefs
+	return derefs
+}
+
+// isNilConst 是否是指针或接口类型的 nil 常量
+func isNilConst(v ssa.Value) bool {
+	c, ok := v.(*ssa.Const)
+	if !ok || !c.IsNil() {
+		return false
+	}
+	switch c.Type().Underlying().(type) {
+	case *types.Pointer, *types.Interface:
+		return true
+	}
+	return false
+}
+
+// instrPos 指令对应的源码位置；隐式的解引用没有位置时取使用它的指令的位置
+func instrPos(instr ssa.Instruction) token.Pos {
+	for range 4 {
+		if pos := instr.Pos(); pos.IsValid() {
+			return pos
+		}
+		v, ok := instr.(ssa.Value)
+		if !ok {
+			return token.NoPos
+		}
+		refs := v.Referrers()
+		if refs == nil || len(*refs) == 0 {
+			return token.NoPos
+		}
+		instr = (*refs)[0]
+	}
+	return token.NoPos
+}
@@ -0,0 +1,748 @@
+package tools
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// 规则 6: 无效赋值（赋的值在被覆盖或函数结束前从未读取）
+type IneffectiveAssignRule struct{}
+
+func (r *IneffectiveAssignRule) ID() string       { return "B106" }
+func (r *IneffectiveAssignRule) Name() string     { return "Ineffective Assignment" }
+func (r *IneffectiveAssignRule) Severity() string { return "Medium" }
+func (r *IneffectiveAssignRule) Category() string { return CategoryCorrectness }
+func (r *IneffectiveAssignRule) Description() string {
+	return "变量被赋值后从未读取，赋的值被覆盖或直接丢弃"
+}
+func (r *IneffectiveAssignRule) GenerateSuggestion(node ast.Node) string {
+	return "删除无效赋值，或检查是否遗漏了对该值的处理：\nresult, err := step1()\nif err != nil {\n    return err\n}\nresult, err = step2(result)\nif err != nil { // 缺少这一步时 step2 的错误被丢弃\n    return err\n}"
+}
+
+func (r *IneffectiveAssignRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	assign, ok := node.(*ast.AssignStmt)
+	if !ok || ctx.File == nil {
+		return false
+	}
+	found := ctx.Memo("B106", func() any { return findIneffectiveAssigns(ctx.File) }).(map[*ast.AssignStmt]bool)
+	return found[assign]
+}
+
+// 规则 7: err 在内层代码块或 if 的初始化语句中被 := 重新声明，外层 err 没有被赋值
+type ErrShadowRule struct{}
+
+func (r *ErrShadowRule) ID() string       { return "B107" }
+func (r *ErrShadowRule) Name() string     { return "Shadowed Error Variable" }
+func (r *ErrShadowRule) Severity() string { return "High" }
+func (r *ErrShadowRule) Category() string { return CategoryErrorHandling }
+func (r *ErrShadowRule) Description() string {
+	return "err 在 if/for 代码块内或 if err := f(); err != nil 中用 := 重新声明，遮蔽了外层 err，外层后续检查或返回的 err 不包含这里的错误"
+}
+func (r *ErrShadowRule) GenerateSuggestion(node ast.Node) string {
+	return "在代码块内使用 = 给外层 err 赋值，或在分支内直接返回错误：\nvar err error\nif cond {\n    var v T\n    v, err = load() // 不要写成 v, err := load()\n    use(v)\n}\nif err = save(); err != nil { // 不要写成 if err := save(); ...\n    log.Printf(\"save: %v\", err)\n}\nreturn err"
+}
+
+func (r *ErrShadowRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	assign, ok := node.(*ast.AssignStmt)
+	if !ok || assign.Tok != token.DEFINE || ctx.File == nil {
+		return false
+	}
+	found := ctx.Memo("B107", func() any { return findShadowedErrs(ctx.File) }).(map[*ast.AssignStmt]bool)
+	return found[assign]
+}
+
+// 规则 8: 函数结果写入从未读取的结构体字段
+type WriteOnlyFieldRule struct{}
+
+func (r *WriteOnlyFieldRule) ID() string       { return "B108" }
+func (r *WriteOnlyFieldRule) Name() string     { return "Result Stored In Unused Struct" }
+func (r *WriteOnlyFieldRule) Severity() string { return "Medium" }
+func (r *WriteOnlyFieldRule) Category() string { return CategoryMaintainability }
+func (r *WriteOnlyFieldRule) Description() string {
+	return "函数返回值写入局部结构体的字段，但该结构体之后从未被读取，结果实际被丢弃"
+}
+func (r *WriteOnlyFieldRule) GenerateSuggestion(node ast.Node) string {
+	return "使用该结构体（返回、传参或读取字段），或者删除这次调用：\nvar stats Stats\nstats.Total = count(items)\nreturn stats"
+}
+
+func (r *WriteOnlyFieldRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	assign, ok := node.(*ast.AssignStmt)
+	if !ok || ctx.File == nil {
+		return false
+	}
+	found := ctx.Memo("B108", func() any { return findWriteOnlyFields(ctx.File) }).(map[*ast.AssignStmt]bool)
+	return found[assign]
+}
+
+// varUseKind 局部变量一次出现的类型
+type varUseKind int
+
+const (
+	varRead       varUseKind = iota // 读取
+	varWrite                        // 整体赋值（= 或 :=）
+	varFieldWrite                   // 字段赋值 v.f = ...
+	varIndexWrite                   // 元素赋值 m[k] = ...、m[k]++
+	varChanOp                       // 通道收发 <-ch、ch <- v、for range ch（select 的 case 除外）
+)
+
+// varUse 局部变量在函数中的一次出现
+type varUse struct {
+	pos    token.Pos
+	kind   varUseKind
+	assign *ast.AssignStmt // 写入时所在的赋值语句
+	node   ast.Node        // 元素赋值和通道收发所在的语句或表达式
+}
+
+// stmtSlot 语句在所属语句列表中的位置
+type stmtSlot struct {
+	list  []ast.Stmt
+	index int
+}
+
+// funcVars 一个函数内声明的局部变量及其所有出现（按位置排序）
+// 依赖 parser 的标识符解析（ast.Object），不需要类型信息
+type funcVars struct {
+	uses    map[*ast.Object][]varUse
+	inits   map[*ast.Object][]ast.Expr // 声明和整体赋值的右值，nil 表示零值声明
+	types   map[*ast.Object]ast.Expr   // var 声明中写明的类型
+	unsafe  map[*ast.Object]bool       // 取地址或被闭包捕获，无法只看本函数判断
+	loops   []ast.Node                 // 本函数（不含闭包）中的循环
+	slots   map[ast.Stmt]stmtSlot
+	hasGoto bool // 含 goto 或标签时控制流不再按顺序，跳过分析
+}
+
+// forEachFunc 对文件中的每个函数和闭包调用 fn
+func forEachFunc(file *ast.File, fn func(ftype *ast.FuncType, body *ast.BlockStmt)) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch f := n.(type) {
+		case *ast.FuncDecl:
+			if f.Body != nil {
+				fn(f.Type, f.Body)
+			}
+		case *ast.FuncLit:
+			fn(f.Type, f.Body)
+		}
+		return true
+	})
+}
+
+// collectFuncVars 收集函数中声明的局部变量（不含命名返回值）及其出现
+// 闭包中声明的变量由闭包自己的 funcVars 负责
+func collectFuncVars(ftype *ast.FuncType, body *ast.BlockStmt) *funcVars {
+	fv := &funcVars{
+		uses:   make(map[*ast.Object][]varUse),
+		inits:  make(map[*ast.Object][]ast.Expr),
+		types:  make(map[*ast.Object]ast.Expr),
+		unsafe: make(map[*ast.Object]bool),
+		slots:  make(map[ast.Stmt]stmtSlot),
+	}
+
+	declared := make(map[*ast.Object]bool)
+	skip := make(map[*ast.Ident]bool) // 声明位置和 range 变量，既不算读也不算写
+	writes := make(map[*ast.Ident]*ast.AssignStmt)
+	fieldWrites := make(map[*ast.Ident]*ast.AssignStmt)
+	special := make(map[*ast.Ident]varUse) // 元素赋值和通道收发
+	var selectComms []ast.Node
+	inSelectComm := func(pos token.Pos) bool {
+		for _, comm := range selectComms {
+			if comm.Pos() <= pos && pos < comm.End() {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ftype.Params != nil {
+		for _, field := range ftype.Params.List {
+			for _, name := range field.Names {
+				if name.Obj != nil {
+					declared[name.Obj] = true
+				}
+			}
+		}
+	}
+
+	var stack []ast.Node
+	closures := 0
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			if _, ok := stack[len(stack)-1].(*ast.FuncLit); ok {
+				closures--
+			}
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, n)
+
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			closures++
+		case *ast.BlockStmt:
+			fv.addSlots(node.List)
+		case *ast.CaseClause:
+			fv.addSlots(node.Body)
+		case *ast.CommClause:
+			fv.addSlots(node.Body)
+			if node.Comm != nil {
+				selectComms = append(selectComms, node.Comm)
+			}
+		case *ast.IncDecStmt:
+			if index, ok := node.X.(*ast.IndexExpr); ok {
+				if ident, ok := index.X.(*ast.Ident); ok {
+					special[ident] = varUse{kind: varIndexWrite, node: node}
+				}
+			}
+		case *ast.SendStmt:
+			if ident, ok := node.Chan.(*ast.Ident); ok && !inSelectComm(node.Pos()) {
+				special[ident] = varUse{kind: varChanOp, node: node}
+			}
+		case *ast.LabeledStmt:
+			fv.hasGoto = true
+		case *ast.ForStmt:
+			if closures == 0 {
+				fv.loops = append(fv.loops, node)
+			}
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				if index, ok := lhs.(*ast.IndexExpr); ok {
+					if ident, ok := index.X.(*ast.Ident); ok {
+						special[ident] = varUse{kind: varIndexWrite, node: node}
+					}
+				}
+			}
+			if node.Tok != token.ASSIGN && node.Tok != token.DEFINE {
+				break
+			}
+			for i, lhs := range node.Lhs {
+				rhs := node.Rhs[0] // 多返回值调用
+				if len(node.Lhs) == len(node.Rhs) {
+					rhs = node.Rhs[i]
+				}
+				switch target := lhs.(type) {
+				case *ast.Ident:
+					writes[target] = node
+					if target.Obj == nil {
+						break
+					}
+					if closures == 0 && node.Tok == token.DEFINE && target.Obj.Decl == node {
+						declared[target.Obj] = true
+					}
+					fv.inits[target.Obj] = append(fv.inits[target.Obj], rhs)
+				case *ast.SelectorExpr:
+					if ident, ok := target.X.(*ast.Ident); ok {
+						fieldWrites[ident] = node
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for i, name := range node.Names {
+				skip[name] = true
+				if name.Obj == nil || closures > 0 {
+					continue
+				}
+				declared[name.Obj] = true
+				if node.Type != nil {
+					fv.types[name.Obj] = node.Type
+				}
+				var value ast.Expr // nil 表示零值声明
+				if len(node.Values) == len(node.Names) {
+					value = node.Values[i]
+				} else if len(node.Values) > 0 {
+					value = node.Values[0]
+				}
+				fv.inits[name.Obj] = append(fv.inits[name.Obj], value)
+			}
+		case *ast.RangeStmt:
+			if closures == 0 {
+				fv.loops = append(fv.loops, node)
+			}
+			if ident, ok := node.X.(*ast.Ident); ok {
+				special[ident] = varUse{kind: varChanOp, node: node}
+			}
+			for _, e := range []ast.Expr{node.Key, node.Value} {
+				if ident, ok := e.(*ast.Ident); ok {
+					skip[ident] = true
+					if ident.Obj != nil {
+						fv.unsafe[ident.Obj] = true
+					}
+				}
+			}
+		case *ast.TypeSwitchStmt:
+			if assign, ok := node.Assign.(*ast.AssignStmt); ok {
+				for _, lhs := range assign.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok {
+						skip[ident] = true
+					}
+				}
+			}
+		case *ast.UnaryExpr:
+			ident, ok := node.X.(*ast.Ident)
+			if !ok {
+				break
+			}
+			if node.Op == token.AND && ident.Obj != nil {
+				fv.unsafe[ident.Obj] = true
+			}
+			if node.Op == token.ARROW && !inSelectComm(node.Pos()) {
+				special[ident] = varUse{kind: varChanOp, node: node}
+			}
+		case *ast.Ident:
+			if node.Obj == nil || node.Obj.Kind != ast.Var {
+				break
+			}
+			if closures > 0 {
+				// 在闭包中出现的外层变量视为被捕获
+				fv.uses[node.Obj] = append(fv.uses[node.Obj], varUse{pos: node.Pos(), kind: varRead})
+				fv.unsafe[node.Obj] = true
+				break
+			}
+			if skip[node] {
+				break
+			}
+			use := varUse{pos: node.Pos(), kind: varRead}
+			if assign := writes[node]; assign != nil {
+				use = varUse{pos: node.Pos(), kind: varWrite, assign: assign}
+			} else if assign := fieldWrites[node]; assign != nil {
+				use = varUse{pos: node.Pos(), kind: varFieldWrite, assign: assign}
+			} else if s, ok := special[node]; ok {
+				use = varUse{pos: node.Pos(), kind: s.kind, node: s.node}
+			}
+			fv.uses[node.Obj] = append(fv.uses[node.Obj], use)
+		}
+		return true
+	})
+
+	for obj := range fv.uses {
+		if !declared[obj] || obj.Name == "_" {
+			delete(fv.uses, obj)
+			continue
+		}
+		uses := fv.uses[obj]
+		sort.Slice(uses, func(i, j int) bool { return uses[i].pos < uses[j].pos })
+	}
+	return fv
+}
+
+// addSlots 记录语句列表中每条语句的位置
+func (fv *funcVars) addSlots(list []ast.Stmt) {
+	for i, stmt := range list {
+		fv.slots[stmt] = stmtSlot{list: list, index: i}
+	}
+}
+
+// readsIn 变量在 [from, to) 范围内是否被读取（字段赋值会解引用变量，也算读取）
+func readsIn(uses []varUse, from, to token.Pos) bool {
+	for _, u := range uses {
+		if u.pos >= from && u.pos < to && u.kind != varWrite {
+			return true
+		}
+	}
+	return false
+}
+
+// findIneffectiveAssigns 查找赋值后从未读取的赋值语句
+func findIneffectiveAssigns(file *ast.File) map[*ast.AssignStmt]bool {
+	found := make(map[*ast.AssignStmt]bool)
+	forEachFunc(file, func(ftype *ast.FuncType, body *ast.BlockStmt) {
+		fv := collectFuncVars(ftype, body)
+		if fv.hasGoto {
+			return
+		}
+		for obj, uses := range fv.uses {
+			if fv.unsafe[obj] {
+				continue
+			}
+			for i, u := range uses {
+				if u.kind != varWrite {
+					continue
+				}
+				if fv.ineffective(uses, i, body.End()) {
+					found[u.assign] = true
+				}
+			}
+		}
+	})
+	return found
+}
+
+// ineffective 判断 uses[i] 这次写入是否无效
+func (fv *funcVars) ineffective(uses []varUse, i int, funcEnd token.Pos) bool {
+	assign := uses[i].assign
+
+	// 之后（包括所在循环的下一轮）再也没有读取
+	if !readsIn(uses, assign.End(), funcEnd) {
+		for _, loop := range fv.loops {
+			if loop.Pos() <= assign.Pos() && assign.End() <= loop.End() && readsIn(uses, loop.Pos(), loop.End()) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// 紧接着被同一语句列表中的另一次赋值覆盖，中间没有读取和跳转
+	for _, next := range uses[i+1:] {
+		if next.pos < assign.End() {
+			continue
+		}
+		if next.kind != varWrite || next.assign == assign {
+			return false
+		}
+		if readsIn(uses, assign.End(), next.assign.End()) {
+			return false
+		}
+		from, ok1 := fv.slots[assign]
+		to, ok2 := fv.slots[next.assign]
+		if !ok1 || !ok2 || &from.list[0] != &to.list[0] {
+			return false
+		}
+		return !containsBranch(from.list[from.index+1 : to.index])
+	}
+	return false
+}
+
+// containsBranch 语句中是否有 break/continue/goto
+func containsBranch(stmts []ast.Stmt) bool {
+	found := false
+	for _, stmt := range stmts {
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			if _, ok := n.(*ast.BranchStmt); ok {
+				found = true
+			}
+			return !found
+		})
+	}
+	return found
+}
+
+// findWriteOnlyFields 查找把函数结果写入从未读取的局部结构体字段的语句
+// 只检查由字面量、new 或零值声明创建的变量，这类变量没有别名，写入对外不可见
+func findWriteOnlyFields(file *ast.File) map[*ast.AssignStmt]bool {
+	found := make(map[*ast.AssignStmt]bool)
+	forEachFunc(file, func(ftype *ast.FuncType, body *ast.BlockStmt) {
+		fv := collectFuncVars(ftype, body)
+		for obj, uses := range fv.uses {
+			if fv.unsafe[obj] || !freshValues(fv.inits[obj]) {
+				continue
+			}
+			var fieldWrites []*ast.AssignStmt
+			read := false
+			for _, u := range uses {
+				switch u.kind {
+				case varWrite:
+				case varFieldWrite:
+					fieldWrites = append(fieldWrites, u.assign)
+				default:
+					read = true
+				}
+			}
+			if read {
+				continue
+			}
+			for _, assign := range fieldWrites {
+				if len(assign.Rhs) == 1 {
+					if _, ok := assign.Rhs[0].(*ast.CallExpr); ok {
+						found[assign] = true
+					}
+				}
+			}
+		}
+	})
+	return found
+}
+
+// freshValues 变量的所有初值都是新建的对象（复合字面量、&字面量、new 或零值）
+func freshValues(values []ast.Expr) bool {
+	if len(values) == 0 {
+		return false
+	}
+	for _, v := range values {
+		switch e := v.(type) {
+		case nil, *ast.CompositeLit:
+		case *ast.UnaryExpr:
+			if _, ok := e.X.(*ast.CompositeLit); !ok || e.Op != token.AND {
+				return false
+			}
+		case *ast.CallExpr:
+			if ident, ok := e.Fun.(*ast.Ident); !ok || ident.Name != "new" {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// findShadowedErrs 查找在代码块内用 := 重新声明 err、遮蔽外层 err 的赋值语句
+// 只有内层 err 没有在代码块内被返回或终止处理、而外层 err 在代码块结束后还会被读取
+// （或有裸 return 返回命名结果）时才报告
+// if err := f(); err != nil 是常见的惯用法，只在外层 err 之后被返回（而不只是被检查）时报告，
+// 这时 f 的错误在分支中被吞掉，调用方拿到的是外层 err 的旧值
+func findShadowedErrs(file *ast.File) map[*ast.AssignStmt]bool {
+	found := make(map[*ast.AssignStmt]bool)
+	forEachFunc(file, func(ftype *ast.FuncType, body *ast.BlockStmt) {
+		type errDecl struct {
+			obj    *ast.Object
+			scope  ast.Node // 声明所在的作用域节点
+			assign *ast.AssignStmt
+			ifInit bool // 声明在 if 的初始化语句中
+		}
+		var decls []errDecl
+		named := make(map[*ast.Object]bool)
+		uses := make(map[*ast.Object][]varUse)
+		var bareReturns []token.Pos
+		returned := make(map[token.Pos]bool) // 出现在 return 结果中的 err
+
+		for _, list := range []*ast.FieldList{ftype.Params, ftype.Results} {
+			if list == nil {
+				continue
+			}
+			for _, field := range list.List {
+				for _, name := range field.Names {
+					if name.Name == "err" && name.Obj != nil {
+						decls = append(decls, errDecl{obj: name.Obj, scope: body})
+						named[name.Obj] = list == ftype.Results
+					}
+				}
+			}
+		}
+
+		var stack []ast.Node
+		closures := 0
+		declIdents := make(map[*ast.Ident]bool)
+		assigned := make(map[*ast.Ident]bool)
+		ast.Inspect(body, func(n ast.Node) bool {
+			if n == nil {
+				if _, ok := stack[len(stack)-1].(*ast.FuncLit); ok {
+					closures--
+				}
+				stack = stack[:len(stack)-1]
+				return true
+			}
+			stack = append(stack, n)
+			if _, ok := n.(*ast.FuncLit); ok {
+				closures++
+			}
+			if closures > 0 {
+				return true
+			}
+
+			switch node := n.(type) {
+			case *ast.ReturnStmt:
+				if len(node.Results) == 0 {
+					bareReturns = append(bareReturns, node.Pos())
+				}
+				for _, result := range node.Results {
+					ast.Inspect(result, func(n ast.Node) bool {
+						if ident, ok := n.(*ast.Ident); ok && ident.Name == "err" {
+							returned[ident.Pos()] = true
+						}
+						return true
+					})
+				}
+			case *ast.AssignStmt:
+				if node.Tok == token.ASSIGN || node.Tok == token.DEFINE {
+					for _, lhs := range node.Lhs {
+						if ident, ok := lhs.(*ast.Ident); ok {
+							assigned[ident] = true
+						}
+					}
+				}
+				if node.Tok != token.DEFINE {
+					break
+				}
+				for _, lhs := range node.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok && ident.Name == "err" && ident.Obj != nil && ident.Obj.Decl == node {
+						decl := errDecl{obj: ident.Obj, scope: innermostScope(stack[:len(stack)-1])}
+						// 只关心代码块中的语句，不包括 if/for/switch 的初始化语句和 select 的 case
+						if comm, ok := decl.scope.(*ast.CommClause); isBlockScope(decl.scope) && (!ok || comm.Comm != node) {
+							decl.assign = node
+						}
+						if ifStmt, ok := decl.scope.(*ast.IfStmt); ok && ifStmt.Init == node {
+							decl.assign, decl.ifInit = node, true
+						}
+						decls = append(decls, decl)
+						declIdents[ident] = true
+					}
+				}
+			case *ast.ValueSpec:
+				for _, name := range node.Names {
+					if name.Name == "err" && name.Obj != nil {
+						decls = append(decls, errDecl{obj: name.Obj, scope: innermostScope(stack[:len(stack)-1])})
+						declIdents[name] = true
+					}
+				}
+			case *ast.Ident:
+				if node.Name == "err" && node.Obj != nil && !declIdents[node] {
+					use := varUse{pos: node.Pos(), kind: varRead}
+					if assigned[node] {
+						use.kind = varWrite
+					}
+					uses[node.Obj] = append(uses[node.Obj], use)
+				}
+			}
+			return true
+		})
+
+		for _, inner := range decls {
+			if inner.assign == nil || errHandled(inner.scope, inner.obj) {
+				continue
+			}
+			for _, outer := range decls {
+				if outer.obj == inner.obj || outer.scope == inner.scope {
+					continue
+				}
+				if outer.scope.Pos() > inner.assign.Pos() || inner.assign.End() > outer.scope.End() {
+					continue
+				}
+				if outer.assign != nil && outer.assign.Pos() > inner.assign.Pos() {
+					continue
+				}
+				// 代码块之后外层 err 先被读取（而不是先被重新赋值）；if 的初始化语句要求重新赋值之前被返回
+				usedAfter := false
+				for _, u := range uses[outer.obj] {
+					if u.pos <= inner.scope.End() {
+						continue
+					}
+					if !inner.ifInit || u.kind != varRead {
+						usedAfter = u.kind == varRead
+						break
+					}
+					if returned[u.pos] {
+						usedAfter = true
+						break
+					}
+				}
+				if named[outer.obj] {
+					for _, pos := range bareReturns {
+						if pos > inner.scope.End() {
+							usedAfter = true
+						}
+					}
+				}
+				if usedAfter {
+					found[inner.assign] = true
+				}
+			}
+		}
+	})
+	return found
+}
+
+// errHandled 判断 err 是否在代码块内被处理：作为返回值返回、赋给其他变量、交给非日志函数处理，
+// 在 if 条件中检查并以 return/break/continue/panic 结束分支，或者代码块本身不会继续向下执行
+func errHandled(scope ast.Node, obj *ast.Object) bool {
+	var list []ast.Stmt
+	switch s := scope.(type) {
+	case *ast.BlockStmt:
+		list = s.List
+	case *ast.CaseClause:
+		list = s.Body
+	case *ast.CommClause:
+		list = s.Body
+	}
+	if len(list) > 0 && isTerminating(list[len(list)-1]) {
+		return true
+	}
+
+	handled := false
+	ast.Inspect(scope, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			for _, result := range node.Results {
+				handled = handled || mentionsObject(result, obj)
+			}
+		case *ast.AssignStmt:
+			for _, rhs := range node.Rhs {
+				handled = handled || mentionsObject(rhs, obj)
+			}
+		case *ast.IfStmt:
+			if mentionsObject(node.Cond, obj) && len(node.Body.List) > 0 && isTerminating(node.Body.List[len(node.Body.List)-1]) {
+				handled = true
+			}
+		case *ast.ExprStmt:
+			if call, ok := node.X.(*ast.CallExpr); ok && !isLoggingCall(call) {
+				for _, arg := range call.Args {
+					handled = handled || mentionsObject(arg, obj)
+				}
+			}
+		}
+		return !handled
+	})
+	return handled
+}
+
+// isLoggingCall 是否是只打印不处理的日志调用（log.Printf、t.Errorf、logger.Warn 等）
+func isLoggingCall(call *ast.CallExpr) bool {
+	name := ""
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		name = fun.Name
+	case *ast.SelectorExpr:
+		name = fun.Sel.Name
+	}
+	for _, prefix := range []string{"Print", "Log", "Debug", "Info", "Warn", "Error"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// mentionsObject 表达式中是否引用了该变量
+func mentionsObject(expr ast.Node, obj *ast.Object) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && ident.Obj == obj {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// isTerminating 语句是否结束当前控制流（return、跳转、panic 或退出进程）
+func isTerminating(stmt ast.Stmt) bool {
+	switch s := stmt.(type) {
+	case *ast.ReturnStmt, *ast.BranchStmt:
+		return true
+	case *ast.ExprStmt:
+		call, ok := s.X.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		switch fun := call.Fun.(type) {
+		case *ast.Ident:
+			return fun.Name == "panic"
+		case *ast.SelectorExpr:
+			name := fun.Sel.Name
+			return name == "Exit" || strings.HasPrefix(name, "Fatal") || strings.HasPrefix(name, "Panic")
+		}
+	}
+	return false
+}
+
+// innermostScope 返回栈中最内层的作用域节点
+func innermostScope(stack []ast.Node) ast.Node {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch stack[i].(type) {
+		case *ast.BlockStmt, *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt,
+			*ast.TypeSwitchStmt, *ast.SelectStmt, *ast.CaseClause, *ast.CommClause:
+			return stack[i]
+		}
+	}
+	return nil
+}
+
+// isBlockScope 作用域是否是代码块本身（而不是 if/for/switch 的初始化语句）
+func isBlockScope(scope ast.Node) bool {
+	switch scope.(type) {
+	case *ast.BlockStmt, *ast.CaseClause, *ast.CommClause:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,18 @@
+package tools
+
+import "testing"
+
+// 测试无效赋值
+func TestBugDetector_IneffectiveAssign(t *testing.T) {
+	testRuleFixtures(t, "assign", ruleOptions{}, "B106")
+}
+
+// 测试 err 被内层 := 遮蔽
+func TestBugDetector_ErrShadow(t *testing.T) {
+	testRuleFixtures(t, "assign", ruleOptions{}, "B107")
+}
+
+// 测试函数结果写入从未读取的结构体
+func TestBugDetector_WriteOnlyField(t *testing.T) {
+	testRuleFixtures(t, "assign", ruleOptions{}, "B108")
+}
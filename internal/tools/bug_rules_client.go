@@ -0,0 +1,350 @@
+package tools
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// httpShortcuts net/http 中直接发请求、不接受 context 的函数及其参数个数
+var httpShortcuts = map[string]int{"Get": 1, "Head": 1, "Post": 3, "PostForm": 2}
+
+// 规则 24: http 响应的 Body 没有关闭
+type ResponseBodyNotClosedRule struct{}
+
+func (r *ResponseBodyNotClosedRule) ID() string       { return "B124" }
+func (r *ResponseBodyNotClosedRule) Name() string     { return "Response Body Not Closed" }
+func (r *ResponseBodyNotClosedRule) Severity() string { return "High" }
+func (r *ResponseBodyNotClosedRule) Category() string { return CategoryResourceMgmt }
+func (r *ResponseBodyNotClosedRule) Description() string {
+	return "http 响应的 Body 没有关闭（包括用 _ 丢弃响应、只检查错误的情况），底层连接无法复用，长时间运行会耗尽文件描述符"
+}
+func (r *ResponseBodyNotClosedRule) GenerateSuggestion(node ast.Node) string {
+	return "不要用 _ 丢弃响应，即使只关心错误也要关闭 Body；检查错误后立即 defer resp.Body.Close()：\nresp, err := client.Do(req)\nif err != nil {\n    return err\n}\ndefer resp.Body.Close()"
+}
+
+func (r *ResponseBodyNotClosedRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	return matchFileFinding(node, ctx, "client", findClientMisuses, "B124")
+}
+
+// 规则 25: 在处理请求或循环中创建 http.Client
+type HTTPClientPerRequestRule struct{}
+
+func (r *HTTPClientPerRequestRule) ID() string       { return "B125" }
+func (r *HTTPClientPerRequestRule) Name() string     { return "HTTP Client Created Per Request" }
+func (r *HTTPClientPerRequestRule) Severity() string { return "Medium" }
+func (r *HTTPClientPerRequestRule) Category() string { return CategoryPerformance }
+func (r *HTTPClientPerRequestRule) Description() string {
+	return "在 HTTP handler 或循环中创建 http.Client，每次都新建连接池，无法复用连接，高并发时耗尽端口"
+}
+func (r *HTTPClientPerRequestRule) GenerateSuggestion(node ast.Node) string {
+	return "创建一次并复用（http.Client 可以并发使用）：\nvar apiClient = &http.Client{Timeout: 10 * time.Second}\n\nfunc handler(w http.ResponseWriter, r *http.Request) {\n    resp, err := apiClient.Do(req)\n    ...\n}"
+}
+
+func (r *HTTPClientPerRequestRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	return matchFileFinding(node, ctx, "client", findClientMisuses, "B125")
+}
+
+// 规则 26: 有 context 可用时发出不带 context 的请求
+type OutboundCallWithoutContextRule struct{}
+
+func (r *OutboundCallWithoutContextRule) ID() string       { return "B126" }
+func (r *OutboundCallWithoutContextRule) Name() string     { return "Outbound Call Without Context" }
+func (r *OutboundCallWithoutContextRule) Severity() string { return "Medium" }
+func (r *OutboundCallWithoutContextRule) Category() string { return CategoryConcurrency }
+func (r *OutboundCallWithoutContextRule) Description() string {
+	return "函数有 context（ctx 参数或 *http.Request），但发出的 HTTP 请求没有带上，调用方取消或超时后请求仍会继续"
+}
+func (r *OutboundCallWithoutContextRule) GenerateSuggestion(node ast.Node) string {
+	return "使用带 context 的版本：\nreq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)\nif err != nil {\n    return err\n}\nresp, err := client.Do(req)"
+}
+
+func (r *OutboundCallWithoutContextRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	return matchFileFinding(node, ctx, "client", findClientMisuses, "B126")
+}
+
+// 规则 27: grpc.Dial 没有等待连接就绪，或等待但没有超时
+type GRPCDialRule struct{}
+
+func (r *GRPCDialRule) ID() string       { return "B127" }
+func (r *GRPCDialRule) Name() string     { return "gRPC Dial Without Block Or Timeout" }
+func (r *GRPCDialRule) Severity() string { return "Medium" }
+func (r *GRPCDialRule) Category() string { return CategoryConcurrency }
+func (r *GRPCDialRule) Description() string {
+	return "grpc.Dial 默认不等待连接建立，拿到连接后立即发起的 RPC 可能直接以 Unavailable 失败；使用 WithBlock 却没有超时则可能永久阻塞"
+}
+func (r *GRPCDialRule) GenerateSuggestion(node ast.Node) string {
+	return "用带超时的 context 阻塞等待连接就绪：\nctx, cancel := context.WithTimeout(ctx, 5*time.Second)\ndefer cancel()\nconn, err := grpc.DialContext(ctx, addr,\n    grpc.WithTransportCredentials(creds),\n    grpc.WithBlock(),\n)"
+}
+
+func (r *GRPCDialRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	return matchFileFinding(node, ctx, "client", findClientMisuses, "B127")
+}
+
+// findClientMisuses 按函数检查 HTTP/gRPC 客户端的常见误用
+func findClientMisuses(file *ast.File) map[ast.Node][]string {
+	found := make(map[ast.Node][]string)
+	forEachFunc(file, func(ftype *ast.FuncType, body *ast.BlockStmt) {
+		handler, hasCtx := funcContext(ftype)
+		var loops []ast.Node
+		inspectFuncBody(body, func(n ast.Node) {
+			switch n.(type) {
+			case *ast.ForStmt, *ast.RangeStmt:
+				loops = append(loops, n)
+			}
+		})
+		inLoop := func(n ast.Node) bool {
+			for _, loop := range loops {
+				if loop.Pos() < n.Pos() && n.End() <= loop.End() {
+					return true
+				}
+			}
+			return false
+		}
+
+		inspectFuncBody(body, func(n ast.Node) {
+			switch node := n.(type) {
+			case *ast.AssignStmt:
+				if len(node.Lhs) != 2 || len(node.Rhs) != 1 || !isHTTPRequestCall(node.Rhs[0]) {
+					return
+				}
+				if responseLeaked(body, node.Lhs[0]) {
+					found[node] = append(found[node], "B124")
+				}
+			case *ast.ValueSpec:
+				if len(node.Names) == 2 && len(node.Values) == 1 && isHTTPRequestCall(node.Values[0]) && responseLeaked(body, node.Names[0]) {
+					found[node] = append(found[node], "B124")
+				}
+			case *ast.ExprStmt:
+				// 整个结果都被丢弃：http.Get(url) 单独成句
+				if isDiscardedHTTPRequest(node.X) {
+					found[node] = append(found[node], "B124")
+				}
+			case *ast.CompositeLit:
+				if isPkgSelector(node.Type, "http", "Client") && (handler || inLoop(node)) {
+					found[node] = append(found[node], "B125")
+				}
+			case *ast.CallExpr:
+				if isNewHTTPClient(node) && (handler || inLoop(node)) {
+					found[node] = append(found[node], "B125")
+				}
+				if (handler || hasCtx) && isContextlessHTTPCall(node) {
+					found[node] = append(found[node], "B126")
+				}
+				if isGRPCDialMisuse(body, node) {
+					found[node] = append(found[node], "B127")
+				}
+			}
+		})
+	})
+	return found
+}
+
+// funcContext 函数是否是 HTTP handler（有 http.ResponseWriter 或 *http.Request 参数），以及是否有 context.Context 参数
+func funcContext(ftype *ast.FuncType) (handler, hasCtx bool) {
+	for _, param := range ftype.Params.List {
+		typ := param.Type
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ = star.X
+		}
+		switch {
+		case isPkgSelector(typ, "http", "ResponseWriter"), isPkgSelector(typ, "http", "Request"):
+			handler = true
+		case isPkgSelector(typ, "context", "Context"):
+			hasCtx = true
+		}
+	}
+	return handler, hasCtx
+}
+
+// isPkgSelector 表达式是否是 pkg.Name
+func isPkgSelector(expr ast.Expr, pkg, name string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && ident.Name == pkg && ident.Obj == nil
+}
+
+// isHTTPClientExpr 表达式看起来是否是 http.Client：http.DefaultClient 或名字中带 client 的变量/字段
+func isHTTPClientExpr(expr ast.Expr) bool {
+	if isPkgSelector(expr, "http", "DefaultClient") {
+		return true
+	}
+	var name string
+	switch x := expr.(type) {
+	case *ast.Ident:
+		name = x.Name
+	case *ast.SelectorExpr:
+		name = x.Sel.Name
+	}
+	return strings.Contains(strings.ToLower(name), "client")
+}
+
+// isHTTPRequestCall 是否是返回 *http.Response 的调用：http.Get 等、client.Get 等、任意 x.Do(req)
+func isHTTPRequestCall(expr ast.Expr) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	switch {
+	case sel.Sel.Name == "Do":
+		return len(call.Args) == 1
+	default:
+		return isHTTPShortcut(call, sel)
+	}
+}
+
+// isContextlessHTTPCall 是否是不带 context 的 HTTP 请求：http.Get 等、client.Get 等、http.NewRequest
+func isContextlessHTTPCall(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	if isPkgSelector(sel, "http", "NewRequest") {
+		return true
+	}
+	return isHTTPShortcut(call, sel)
+}
+
+// isHTTPShortcut 是否是 http.Get 等或 client.Get 等快捷请求（按参数个数排除其他同名方法）
+func isHTTPShortcut(call *ast.CallExpr, sel *ast.SelectorExpr) bool {
+	args, ok := httpShortcuts[sel.Sel.Name]
+	if !ok || len(call.Args) != args {
+		return false
+	}
+	return isPkgSelector(sel, "http", sel.Sel.Name) || isHTTPClientExpr(sel.X)
+}
+
+// isNewHTTPClient 是否是 new(http.Client)
+func isNewHTTPClient(call *ast.CallExpr) bool {
+	ident, ok := call.Fun.(*ast.Ident)
+	return ok && ident.Name == "new" && ident.Obj == nil && len(call.Args) == 1 && isPkgSelector(call.Args[0], "http", "Client")
+}
+
+// responseLeaked 接收响应的变量是否使 Body 无法关闭：用 _ 丢弃了响应（只检查了 err），或者 Body 既没有关闭也没有交给别处
+func responseLeaked(body *ast.BlockStmt, resp ast.Expr) bool {
+	if ident, ok := resp.(*ast.Ident); ok && ident.Name == "_" {
+		return true
+	}
+	obj := identObject(resp)
+	return obj != nil && !bodyClosed(body, obj) && !escapes(body, obj)
+}
+
+// isDiscardedHTTPRequest 单独成句的调用是否是 HTTP 请求；x.Do 只在 x 看起来是 http.Client 时算，排除 sync.Once.Do 等
+func isDiscardedHTTPRequest(expr ast.Expr) bool {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	if sel.Sel.Name == "Do" {
+		return len(call.Args) == 1 && isHTTPClientExpr(sel.X)
+	}
+	return isHTTPShortcut(call, sel)
+}
+
+// bodyClosed 响应的 Body 是否被关闭：resp.Body.Close()、把 resp.Body 传给名字带 close 的函数，或返回 resp.Body 由调用方关闭
+func bodyClosed(body *ast.BlockStmt, obj *ast.Object) bool {
+	closed := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if ret, ok := n.(*ast.ReturnStmt); ok {
+			for _, result := range ret.Results {
+				closed = closed || isRespBody(result, obj)
+			}
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return !closed
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Close" && isRespBody(sel.X, obj) {
+			closed = true
+		}
+		if strings.Contains(strings.ToLower(callName(call)), "close") {
+			for _, arg := range call.Args {
+				if isRespBody(arg, obj) {
+					closed = true
+				}
+			}
+		}
+		return !closed
+	})
+	return closed
+}
+
+// isRespBody 表达式是否是 resp.Body
+func isRespBody(expr ast.Expr, obj *ast.Object) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "Body" && identObject(sel.X) == obj
+}
+
+// callName 被调用函数的名字（x.F 返回 F）
+func callName(call *ast.CallExpr) string {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		return fun.Name
+	case *ast.SelectorExpr:
+		return fun.Sel.Name
+	}
+	return ""
+}
+
+// isGRPCDialMisuse grpc.Dial/DialContext 是否有问题：
+// 带 WithBlock 的 grpc.Dial 没有 WithTimeout（DialContext 的超时由 ctx 控制）；
+// 不带 WithBlock 且连接在本函数中立即用于创建客户端（传给 NewXxxClient）
+// 选项用 opts... 展开时无法判断，不报告
+func isGRPCDialMisuse(body *ast.BlockStmt, call *ast.CallExpr) bool {
+	dial := isPkgSelector(call.Fun, "grpc", "Dial")
+	if (!dial && !isPkgSelector(call.Fun, "grpc", "DialContext")) || call.Ellipsis.IsValid() {
+		return false
+	}
+	var block, timeout bool
+	for _, arg := range call.Args {
+		if opt, ok := arg.(*ast.CallExpr); ok {
+			block = block || isPkgSelector(opt.Fun, "grpc", "WithBlock")
+			timeout = timeout || isPkgSelector(opt.Fun, "grpc", "WithTimeout")
+		}
+	}
+	if block {
+		return dial && !timeout
+	}
+
+	conn := dialedConn(body, call)
+	if conn == nil {
+		return false
+	}
+	used := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if c, ok := n.(*ast.CallExpr); ok {
+			name := callName(c)
+			if strings.HasPrefix(name, "New") && strings.HasSuffix(name, "Client") {
+				for _, arg := range c.Args {
+					if identObject(arg) == conn {
+						used = true
+					}
+				}
+			}
+		}
+		return !used
+	})
+	return used
+}
+
+// dialedConn 返回接收 grpc.Dial 结果的变量
+func dialedConn(body *ast.BlockStmt, call *ast.CallExpr) *ast.Object {
+	var conn *ast.Object
+	ast.Inspect(body, func(n ast.Node) bool {
+		if assign, ok := n.(*ast.AssignStmt); ok && len(assign.Rhs) == 1 && assign.Rhs[0] == call && len(assign.Lhs) > 0 {
+			conn = identObject(assign.Lhs[0])
+		}
+		return conn == nil
+	})
+	return conn
+}
@@ -0,0 +1,8 @@
+package tools
+
+import "testing"
+
+// 测试 HTTP/gRPC 客户端误用规则
+func TestBugDetector_ClientMisuse(t *testing.T) {
+	testRuleFixtures(t, "client", ruleOptions{}, "B124", "B125", "B126", "B127")
+}
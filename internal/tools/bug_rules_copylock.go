@@ -0,0 +1,349 @@
+package tools
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+)
+
+// 规则 34: 包含锁的结构体按值复制（副本的锁与原值无关）
+type LockCopyRule struct{}
+
+func (r *LockCopyRule) ID() string       { return "B134" }
+func (r *LockCopyRule) Name() string     { return "Lock Copied By Value" }
+func (r *LockCopyRule) Severity() string { return "High" }
+func (r *LockCopyRule) Category() string { return CategoryConcurrency }
+func (r *LockCopyRule) Description() string {
+	return "包含 sync.Mutex、sync.WaitGroup 等同步原语的值被复制（值接收者、值参数、赋值、传参或返回），副本中的锁与原值互不相干，加锁形同虚设，Wait 也等不到原值上的 Done"
+}
+func (r *LockCopyRule) GenerateSuggestion(node ast.Node) string {
+	return "改用指针传递和指针接收者，保证所有调用方操作同一把锁：\nfunc (c *Cache) Get(key string) string {\n    c.mu.Lock()\n    defer c.mu.Unlock()\n    return c.data[key]\n}"
+}
+
+func (r *LockCopyRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	if ctx.File == nil {
+		return false
+	}
+	found := ctx.Memo("B134", func() any { return findLockCopies(ctx) }).(map[ast.Node]bool)
+	return found[node]
+}
+
+// syncLockTypes 不能复制的 sync 包类型
+var syncLockTypes = map[string]bool{"Mutex": true, "RWMutex": true, "WaitGroup": true, "Once": true, "Cond": true}
+
+// findLockCopies 查找复制锁的位置：值接收者和值参数报告在声明的字段上，赋值、传参、返回和 range 报告在被复制的表达式上
+// 只有已存在的值（变量、字段、解引用、下标）被复制时才报告，复合字面量和函数返回值是新值
+func findLockCopies(ctx *BugRuleContext) map[ast.Node]bool {
+	found := make(map[ast.Node]bool)
+	lc := newLockChecker(ctx)
+	if lc == nil {
+		return found
+	}
+	checkCopy := func(expr ast.Expr) {
+		if lc.copiesLock(expr) {
+			found[expr] = true
+		}
+	}
+	ast.Inspect(ctx.File, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			if n.Recv != nil {
+				for _, field := range n.Recv.List {
+					if lc.isLockType(field.Type) {
+						found[field] = true
+					}
+				}
+			}
+		case *ast.FuncType:
+			if n.Params != nil {
+				for _, field := range n.Params.List {
+					if lc.isLockType(field.Type) {
+						found[field] = true
+					}
+				}
+			}
+		case *ast.AssignStmt:
+			if len(n.Lhs) == len(n.Rhs) {
+				for i, rhs := range n.Rhs {
+					if !isBlankIdent(n.Lhs[i]) {
+						checkCopy(rhs)
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for i, value := range n.Values {
+				if i < len(n.Names) && n.Names[i].Name != "_" {
+					checkCopy(value)
+				}
+			}
+		case *ast.CallExpr:
+			for _, arg := range n.Args {
+				checkCopy(arg)
+			}
+		case *ast.ReturnStmt:
+			for _, result := range n.Results {
+				checkCopy(result)
+			}
+		case *ast.RangeStmt:
+			if n.Value != nil && !isBlankIdent(n.Value) && lc.rangeCopiesLock(n) {
+				found[n.Value] = true
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// isBlankIdent 是否为空白标识符 _
+func isBlankIdent(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "_"
+}
+
+// lockChecker 判断类型和表达式是否包含锁
+// 有类型信息时按真实类型判断（包括其他文件、其他包中定义的结构体）；
+// 没有时按语法推断：本文件中字段含 sync 锁类型的结构体，以及声明了这些类型的变量、参数和字段
+type lockChecker struct {
+	ctx      *BugRuleContext
+	syncName string              // sync 包在本文件中的导入名
+	structs  map[string]ast.Expr // 本文件声明的类型名 -> 类型表达式
+	locks    map[string]bool     // 本文件中包含锁的类型名
+}
+
+// newLockChecker 创建检查器；没有类型信息且本文件没有导入 sync 时返回 nil（语法上看不出任何锁）
+func newLockChecker(ctx *BugRuleContext) *lockChecker {
+	lc := &lockChecker{ctx: ctx}
+	if ctx.Typed() {
+		return lc
+	}
+	for _, imp := range ctx.File.Imports {
+		if path, _ := strconv.Unquote(imp.Path.Value); path == "sync" {
+			lc.syncName = "sync"
+			if imp.Name != nil {
+				lc.syncName = imp.Name.Name
+			}
+		}
+	}
+	if lc.syncName == "" || lc.syncName == "_" || lc.syncName == "." {
+		return nil
+	}
+
+	lc.structs = make(map[string]ast.Expr)
+	for _, decl := range ctx.File.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			if ts, ok := spec.(*ast.TypeSpec); ok && ts.TypeParams == nil {
+				lc.structs[ts.Name.Name] = ts.Type
+			}
+		}
+	}
+	// 结构体可以嵌套包含锁的结构体，反复传播直到不再变化
+	lc.locks = make(map[string]bool)
+	for changed := true; changed; {
+		changed = false
+		for name, typ := range lc.structs {
+			if !lc.locks[name] && lc.syntacticLockType(typ) {
+				lc.locks[name] = true
+				changed = true
+			}
+		}
+	}
+	return lc
+}
+
+// isLockType 类型表达式表示的类型是否按值包含锁（指针、切片、map 中的锁不算）
+func (lc *lockChecker) isLockType(typ ast.Expr) bool {
+	if lc.ctx.Typed() {
+		return containsLock(lc.ctx.TypeOf(typ), nil)
+	}
+	return lc.syntacticLockType(typ)
+}
+
+// syntacticLockType 按语法判断类型表达式：sync 锁类型、本文件中包含锁的类型、它们的数组和匿名结构体
+func (lc *lockChecker) syntacticLockType(typ ast.Expr) bool {
+	switch t := ast.Unparen(typ).(type) {
+	case *ast.SelectorExpr:
+		pkg, ok := t.X.(*ast.Ident)
+		return ok && pkg.Name == lc.syncName && syncLockTypes[t.Sel.Name]
+	case *ast.Ident:
+		return lc.locks[t.Name]
+	case *ast.ArrayType:
+		return t.Len != nil && lc.syntacticLockType(t.Elt)
+	case *ast.StructType:
+		for _, field := range t.Fields.List {
+			if lc.syntacticLockType(field.Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsLock 类型是否按值包含 sync 锁，seen 防止递归类型死循环
+func containsLock(t types.Type, seen map[*types.Named]bool) bool {
+	if t == nil {
+		return false
+	}
+	t = types.Unalias(t)
+	if named, ok := t.(*types.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "sync" && syncLockTypes[obj.Name()] {
+			return true
+		}
+		if seen[named] {
+			return false
+		}
+		if seen == nil {
+			seen = make(map[*types.Named]bool)
+		}
+		seen[named] = true
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			if containsLock(u.Field(i).Type(), seen) {
+				return true
+			}
+		}
+	case *types.Array:
+		return containsLock(u.Elem(), seen)
+	}
+	return false
+}
+
+// copiesLock 表达式是否复制了一个已存在的、包含锁的值
+func (lc *lockChecker) copiesLock(expr ast.Expr) bool {
+	expr = ast.Unparen(expr)
+	switch expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr, *ast.StarExpr, *ast.IndexExpr:
+	default:
+		return false
+	}
+	if lc.ctx.Typed() {
+		tv, ok := lc.ctx.Types.Types[expr]
+		return ok && tv.IsValue() && containsLock(tv.Type, nil)
+	}
+	typ := lc.exprType(expr)
+	return typ != nil && lc.syntacticLockType(typ)
+}
+
+// rangeCopiesLock range 的迭代变量是否复制了包含锁的元素
+func (lc *lockChecker) rangeCopiesLock(rs *ast.RangeStmt) bool {
+	if lc.ctx.Typed() {
+		return containsLock(lc.ctx.TypeOf(rs.Value), nil)
+	}
+	switch t := ast.Unparen(lc.exprType(rs.X)).(type) {
+	case *ast.ArrayType:
+		return lc.syntacticLockType(t.Elt)
+	case *ast.MapType:
+		return lc.syntacticLockType(t.Value)
+	}
+	return false
+}
+
+// exprType 按语法推断表达式的类型表达式，推断不出时为 nil
+// 变量的类型来自声明（参数、var、x := T{}），字段的类型来自本文件中的结构体声明
+func (lc *lockChecker) exprType(expr ast.Expr) ast.Expr {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		return identType(e)
+	case *ast.StarExpr:
+		if ptr, ok := ast.Unparen(lc.exprType(e.X)).(*ast.StarExpr); ok {
+			return ptr.X
+		}
+	case *ast.SelectorExpr:
+		typ := lc.exprType(e.X)
+		if ptr, ok := ast.Unparen(typ).(*ast.StarExpr); ok {
+			typ = ptr.X // 通过指针访问字段
+		}
+		return lc.fieldType(typ, e.Sel.Name)
+	case *ast.IndexExpr:
+		switch t := ast.Unparen(lc.exprType(e.X)).(type) {
+		case *ast.ArrayType:
+			return t.Elt
+		case *ast.MapType:
+			return t.Value
+		}
+	}
+	return nil
+}
+
+// fieldType 本文件中结构体 typ 的字段 name 的类型（含嵌入字段），找不到时为 nil
+func (lc *lockChecker) fieldType(typ ast.Expr, name string) ast.Expr {
+	if ident, ok := ast.Unparen(typ).(*ast.Ident); ok {
+		typ = lc.structs[ident.Name]
+	}
+	st, ok := typ.(*ast.StructType)
+	if !ok {
+		return nil
+	}
+	for _, field := range st.Fields.List {
+		if len(field.Names) == 0 {
+			// 嵌入字段的字段名是类型名：sync.Mutex 嵌入后为 Mutex
+			embedded := ast.Unparen(field.Type)
+			if ptr, ok := embedded.(*ast.StarExpr); ok {
+				embedded = ptr.X
+			}
+			switch e := embedded.(type) {
+			case *ast.Ident:
+				if e.Name == name {
+					return field.Type
+				}
+			case *ast.SelectorExpr:
+				if e.Sel.Name == name {
+					return field.Type
+				}
+			}
+			continue
+		}
+		for _, n := range field.Names {
+			if n.Name == name {
+				return field.Type
+			}
+		}
+	}
+	return nil
+}
+
+// identType 变量声明中的类型表达式：参数和 var 声明的类型，或 x := T{} / x := &T{} 的初始值类型
+func identType(ident *ast.Ident) ast.Expr {
+	if ident.Obj == nil || ident.Obj.Kind != ast.Var {
+		return nil
+	}
+	var value ast.Expr
+	switch decl := ident.Obj.Decl.(type) {
+	case *ast.Field:
+		return decl.Type
+	case *ast.ValueSpec:
+		if decl.Type != nil {
+			return decl.Type
+		}
+		for i, name := range decl.Names {
+			if name.Name == ident.Name && i < len(decl.Values) {
+				value = decl.Values[i]
+			}
+		}
+	case *ast.AssignStmt:
+		if len(decl.Lhs) != len(decl.Rhs) {
+			return nil
+		}
+		for i, lhs := range decl.Lhs {
+			if id, ok := lhs.(*ast.Ident); ok && id.Name == ident.Name {
+				value = decl.Rhs[i]
+			}
+		}
+	}
+	switch v := ast.Unparen(value).(type) {
+	case *ast.CompositeLit:
+		return v.Type
+	case *ast.UnaryExpr:
+		if lit, ok := v.X.(*ast.CompositeLit); ok && v.Op == token.AND && lit.Type != nil {
+			return &ast.StarExpr{X: lit.Type}
+		}
+	}
+	return nil
+}
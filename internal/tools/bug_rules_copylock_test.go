@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// 测试按值复制包含锁的结构体（按语法推断本文件中的类型）
+func TestBugDetector_LockCopy(t *testing.T) {
+	testRuleFixtures(t, "copylock", ruleOptions{}, "B134")
+}
+
+// 测试带类型信息时识别其他文件中定义的、包含锁的类型
+func TestBugDetector_LockCopyTyped(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/locks\n\ngo 1.21\n",
+		"types.go": `package main
+
+import "sync"
+
+type Counter struct {
+	mu sync.Mutex
+	n  int
+}
+`,
+		"main.go": `package main
+
+func (c Counter) Value() int { return c.n }
+
+func snapshot(c *Counter) Counter {
+	return *c
+}
+
+func main() {
+	var c Counter
+	_ = snapshot(&c).Value()
+}
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	detect := func(typeCheck bool) []int {
+		t.Helper()
+		out, err := NewBugDetector().Run(context.Background(), BugDetectorInput{Directory: dir, TypeCheck: typeCheck})
+		if err != nil {
+			t.Fatalf("检测失败: %v", err)
+		}
+		var result BugResult
+		if err := json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatal(err)
+		}
+		var lines []int
+		for _, bug := range result.Bugs {
+			if bug.RuleID == "B134" && filepath.Base(bug.File) == "main.go" {
+				lines = append(lines, bug.Line)
+			}
+		}
+		slices.Sort(lines)
+		return lines
+	}
+
+	if got := detect(false); len(got) != 0 {
+		t.Errorf("没有类型信息时看不到其他文件中的类型，不应报告: %v", got)
+	}
+	if got := detect(true); !slices.Equal(got, []int{3, 6}) {
+		t.Errorf("B134 = %v, want [3 6]（值接收者、返回 *c）", got)
+	}
+}
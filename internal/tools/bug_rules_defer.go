@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// 规则 30: 在循环中 defer（资源直到函数返回才释放）
+type DeferInLoopRule struct{}
+
+func (r *DeferInLoopRule) ID() string       { return "B130" }
+func (r *DeferInLoopRule) Name() string     { return "Defer Inside Loop" }
+func (r *DeferInLoopRule) Severity() string { return "Medium" }
+func (r *DeferInLoopRule) Category() string { return CategoryResourceMgmt }
+func (r *DeferInLoopRule) Description() string {
+	return "defer 写在 for/range 循环中，要到整个函数返回时才执行，每轮迭代打开的文件、连接或持有的锁会一直累积"
+}
+func (r *DeferInLoopRule) GenerateSuggestion(node ast.Node) string {
+	return "把循环体包进匿名函数，让 defer 在每轮迭代结束时执行，或在本轮末尾显式关闭：\nfor _, name := range names {\n    if err := func() error {\n        f, err := os.Open(name)\n        if err != nil {\n            return err\n        }\n        defer f.Close()\n        return process(f)\n    }(); err != nil {\n        return err\n    }\n}"
+}
+
+func (r *DeferInLoopRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	if _, ok := node.(*ast.DeferStmt); !ok {
+		return false
+	}
+	return matchFileFinding(node, ctx, "B130", findDefersInLoops, "B130")
+}
+
+// findDefersInLoops 查找属于循环体的 defer：defer 与循环之间没有函数边界（闭包中的 defer 在闭包返回时执行）
+// defer 之后同一语句列表中有 return、panic 或跳出该循环的 break 时，循环在 defer 之后不会进入下一轮，不报告
+func findDefersInLoops(file *ast.File) map[ast.Node][]string {
+	found := make(map[ast.Node][]string)
+	inspectWithParents(file, func(n ast.Node, parents []ast.Node) {
+		stmt, ok := n.(*ast.DeferStmt)
+		if !ok || len(parents) == 0 {
+			return
+		}
+		inLoop := false
+		breaksLoop := true // 列表中的 break 跳出的是不是这个循环（中间没有 switch/select）
+	walk:
+		for i := len(parents) - 1; i >= 0; i-- {
+			switch parents[i].(type) {
+			case *ast.FuncLit, *ast.FuncDecl:
+				break walk
+			case *ast.ForStmt, *ast.RangeStmt:
+				inLoop = true
+				break walk
+			case *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
+				breaksLoop = false
+			}
+		}
+		list, _ := stmtList(parents[len(parents)-1])
+		if inLoop && !exitsAfter(list, stmt, breaksLoop) {
+			found[stmt] = append(found[stmt], "B130")
+		}
+	})
+	return found
+}
+
+// exitsAfter stmt 之后的语句中是否一定会离开循环：return、panic/Exit/Fatal，或（breaksLoop 时）不带标签的 break
+func exitsAfter(list []ast.Stmt, stmt ast.Stmt, breaksLoop bool) bool {
+	after := false
+	for _, s := range list {
+		if s == stmt {
+			after = true
+			continue
+		}
+		if !after {
+			continue
+		}
+		switch s := s.(type) {
+		case *ast.ReturnStmt:
+			return true
+		case *ast.BranchStmt:
+			if s.Tok == token.BREAK && s.Label == nil && breaksLoop {
+				return true
+			}
+		case *ast.ExprStmt:
+			if isTerminating(s) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,8 @@
+package tools
+
+import "testing"
+
+// 测试循环中的 defer
+func TestBugDetector_DeferInLoop(t *testing.T) {
+	testRuleFixtures(t, "defer", ruleOptions{}, "B130")
+}
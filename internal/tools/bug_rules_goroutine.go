@@ -0,0 +1,259 @@
+package tools
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// 规则 28: goroutine 阻塞在无缓冲通道上永远无法退出
+type GoroutineLeakRule struct{}
+
+func (r *GoroutineLeakRule) ID() string       { return "B128" }
+func (r *GoroutineLeakRule) Name() string     { return "Goroutine Leak" }
+func (r *GoroutineLeakRule) Severity() string { return "High" }
+func (r *GoroutineLeakRule) Category() string { return CategoryConcurrency }
+func (r *GoroutineLeakRule) Description() string {
+	return "goroutine 在无缓冲通道上收发，但函数中没有对应的接收方/发送方，或者接收方在 select 中可能因超时、取消先返回，goroutine 会永久阻塞且无法回收"
+}
+func (r *GoroutineLeakRule) GenerateSuggestion(node ast.Node) string {
+	return "给通道留一个缓冲，或在 goroutine 中同时监听取消：\nch := make(chan result, 1)\ngo func() {\n    select {\n    case ch <- work():\n    case <-ctx.Done():\n    }\n}()"
+}
+
+func (r *GoroutineLeakRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	if _, ok := node.(*ast.GoStmt); !ok {
+		return false
+	}
+	return matchFileFinding(node, ctx, "B128", findGoroutineLeaks, "B128")
+}
+
+// chanOpKind 通道操作的种类
+type chanOpKind int
+
+const (
+	chanSend chanOpKind = iota
+	chanRecv
+	chanRange
+	chanClose
+	chanOther // 声明、len、cap 等不影响判断的用法
+)
+
+// chanOp 对局部通道的一次操作
+type chanOp struct {
+	kind    chanOpKind
+	owner   *ast.GoStmt // 操作所在的 go 语句（最内层），不在 goroutine 中时为 nil
+	guarded bool        // 是 select 的一个分支，且 select 还有其他分支（可以不经过这次操作就离开）
+	inLoop  bool        // 在循环中，会反复执行
+}
+
+// findGoroutineLeaks 查找会永久阻塞的 goroutine：
+// 只检查函数内 make 的无缓冲通道，通道传给其他函数、被返回或保存时无法判断，不报告
+func findGoroutineLeaks(file *ast.File) map[ast.Node][]string {
+	found := make(map[ast.Node][]string)
+	forEachFunc(file, func(ftype *ast.FuncType, body *ast.BlockStmt) {
+		chans := unbufferedChans(body)
+		if len(chans) == 0 {
+			return
+		}
+		ops := make(map[*ast.Object][]chanOp)
+		escaped := make(map[*ast.Object]bool)
+		inspectWithParents(body, func(n ast.Node, parents []ast.Node) {
+			ident, ok := n.(*ast.Ident)
+			if !ok || !chans[ident.Obj] || len(parents) == 0 {
+				return
+			}
+			kind, ok := chanOpOf(ident, parents[len(parents)-1])
+			if !ok {
+				escaped[ident.Obj] = true
+				return
+			}
+			if kind == chanOther {
+				return
+			}
+			op := chanOp{kind: kind}
+			for i := len(parents) - 1; i >= 0; i-- {
+				switch p := parents[i].(type) {
+				case *ast.GoStmt:
+					// 只有闭包体内的操作属于这个 goroutine，go f(<-ch) 的参数在启动前求值
+					if op.owner == nil && i+2 < len(parents) && parents[i+2] == p.Call.Fun {
+						op.owner = p
+					}
+				case *ast.ForStmt, *ast.RangeStmt:
+					if op.owner == nil {
+						op.inLoop = true
+					}
+				case *ast.CommClause:
+					if i >= 2 && p.Comm != nil && p.Comm.Pos() <= ident.Pos() && ident.End() <= p.Comm.End() {
+						if sel, ok := parents[i-2].(*ast.SelectStmt); ok && len(sel.Body.List) > 1 {
+							op.guarded = true
+						}
+					}
+				}
+			}
+			ops[ident.Obj] = append(ops[ident.Obj], op)
+		})
+
+		for obj, list := range ops {
+			if escaped[obj] {
+				continue
+			}
+			for _, g := range leakedGoroutines(list) {
+				found[g] = append(found[g], "B128")
+			}
+		}
+	})
+	return found
+}
+
+// unbufferedChans 函数体中（不含闭包）用 make(chan T) 或 make(chan T, 0) 创建的局部通道
+func unbufferedChans(body *ast.BlockStmt) map[*ast.Object]bool {
+	chans := make(map[*ast.Object]bool)
+	add := func(lhs []ast.Expr, rhs []ast.Expr) {
+		if len(lhs) != len(rhs) {
+			return
+		}
+		for i, r := range rhs {
+			if obj := identObject(lhs[i]); obj != nil && isUnbufferedMake(r) {
+				chans[obj] = true
+			}
+		}
+	}
+	inspectFuncBody(body, func(n ast.Node) {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if node.Tok == token.DEFINE {
+				add(node.Lhs, node.Rhs)
+			}
+		case *ast.ValueSpec:
+			names := make([]ast.Expr, len(node.Names))
+			for i, name := range node.Names {
+				names[i] = name
+			}
+			add(names, node.Values)
+		}
+	})
+	return chans
+}
+
+// isUnbufferedMake 表达式是否是 make(chan T) 或 make(chan T, 0)
+func isUnbufferedMake(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || !isBuiltin(call.Fun, "make") || len(call.Args) == 0 {
+		return false
+	}
+	if _, ok := call.Args[0].(*ast.ChanType); !ok {
+		return false
+	}
+	if len(call.Args) == 1 {
+		return true
+	}
+	lit, ok := call.Args[1].(*ast.BasicLit)
+	return ok && lit.Kind == token.INT && lit.Value == "0"
+}
+
+// chanOpOf 通道标识符在父节点中的用法，ok 为 false 表示通道被传出（作为参数、返回值或赋给其他变量）
+func chanOpOf(ident *ast.Ident, parent ast.Node) (kind chanOpKind, ok bool) {
+	switch p := parent.(type) {
+	case *ast.SendStmt:
+		if p.Chan == ident {
+			return chanSend, true
+		}
+	case *ast.UnaryExpr:
+		if p.Op == token.ARROW {
+			return chanRecv, true
+		}
+	case *ast.RangeStmt:
+		if p.X == ident {
+			return chanRange, true
+		}
+	case *ast.CallExpr:
+		if len(p.Args) == 1 && p.Args[0] == ident {
+			switch {
+			case isBuiltin(p.Fun, "close"):
+				return chanClose, true
+			case isBuiltin(p.Fun, "len"), isBuiltin(p.Fun, "cap"):
+				return chanOther, true
+			}
+		}
+	case *ast.AssignStmt:
+		for _, lhs := range p.Lhs {
+			if lhs == ident {
+				return chanOther, true
+			}
+		}
+	case *ast.ValueSpec:
+		for _, name := range p.Names {
+			if name == ident {
+				return chanOther, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// leakedGoroutines 按一个通道上的全部操作，找出会永久阻塞在该通道上的 goroutine：
+// 一定会发送，但 goroutine 外没有接收，或者接收只在一次性的 select 中（可能因超时、取消先返回）；
+// 一定会接收，但 goroutine 外既不发送也不关闭；range 通道，但没有任何地方关闭通道
+func leakedGoroutines(ops []chanOp) []*ast.GoStmt {
+	type usage struct {
+		send, recv, rangeOver bool
+	}
+	goroutines := make(map[*ast.GoStmt]*usage)
+	var order []*ast.GoStmt
+	closed := false
+	for _, op := range ops {
+		if op.kind == chanClose {
+			closed = true
+		}
+		if op.owner == nil || op.guarded {
+			continue
+		}
+		u := goroutines[op.owner]
+		if u == nil {
+			u = &usage{}
+			goroutines[op.owner] = u
+			order = append(order, op.owner)
+		}
+		switch op.kind {
+		case chanSend:
+			u.send = true
+		case chanRecv:
+			u.recv = true
+		case chanRange:
+			u.rangeOver = true
+		}
+	}
+
+	var leaked []*ast.GoStmt
+	for _, g := range order {
+		u := goroutines[g]
+		var reliableRecvs, sends int
+		for _, op := range ops {
+			if op.owner == g {
+				continue
+			}
+			switch op.kind {
+			case chanRecv, chanRange:
+				if !op.guarded || op.inLoop || op.kind == chanRange {
+					reliableRecvs++
+				}
+			case chanSend:
+				sends++
+			}
+		}
+		switch {
+		case u.send && reliableRecvs == 0:
+			leaked = append(leaked, g)
+		case u.recv && sends == 0 && !closed:
+			leaked = append(leaked, g)
+		case u.rangeOver && !closed:
+			leaked = append(leaked, g)
+		}
+	}
+	return leaked
+}
+
+// isBuiltin 表达式是否是名为 name 的内置函数（按名字判断，未处理同名的局部定义）
+func isBuiltin(expr ast.Expr, name string) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == name && ident.Obj == nil
+}
@@ -0,0 +1,8 @@
+package tools
+
+import "testing"
+
+// 测试 goroutine 泄漏规则
+func TestBugDetector_GoroutineLeak(t *testing.T) {
+	testRuleFixtures(t, "goroutine", ruleOptions{}, "B128")
+}
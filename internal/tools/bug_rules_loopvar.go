@@ -0,0 +1,183 @@
+package tools
+
+import (
+	"go/ast"
+	"go/token"
+	"go/version"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+)
+
+// loopVarPerIteration 从这个版本开始，for 循环变量每轮迭代重新声明
+const loopVarPerIteration = "go1.22"
+
+// 规则 12: 闭包捕获循环变量（go 1.22 之前所有迭代共享同一个变量）
+type LoopVarCaptureRule struct{}
+
+func (r *LoopVarCaptureRule) ID() string       { return "B112" }
+func (r *LoopVarCaptureRule) Name() string     { return "Loop Variable Captured By Closure" }
+func (r *LoopVarCaptureRule) Severity() string { return "High" }
+func (r *LoopVarCaptureRule) Category() string { return CategoryConcurrency }
+func (r *LoopVarCaptureRule) Description() string {
+	return "go/defer 或保存下来的闭包引用了循环变量，或把循环变量的地址交给 goroutine，go 1.22 之前所有迭代共享同一个变量，执行时读到的通常是最后一次的值"
+}
+func (r *LoopVarCaptureRule) GenerateSuggestion(node ast.Node) string {
+	return "在循环体开头重新声明循环变量（fix --rules B112 自动完成），或把它作为参数传入，也可以把 go.mod 升级到 go 1.22 及以上：\nfor _, item := range items {\n    item := item\n    go func() {\n        process(item)\n    }()\n}"
+}
+
+func (r *LoopVarCaptureRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	switch node.(type) {
+	case *ast.FuncLit, *ast.UnaryExpr:
+	default:
+		return false
+	}
+	if ctx.File == nil || !loopVarShared(ctx) {
+		return false
+	}
+	return len(loopVarCapturesOf(ctx)[node]) > 0
+}
+
+// loopVarCapturesOf 当前文件中引用了循环变量的闭包和取址表达式（按文件缓存）
+func loopVarCapturesOf(ctx *BugRuleContext) map[ast.Node][]*ast.Object {
+	return ctx.Memo("B112", func() any { return findLoopVarCaptures(ctx.File) }).(map[ast.Node][]*ast.Object)
+}
+
+// loopVarShared 当前文件的循环变量是否在所有迭代间共享
+// 模块版本未知时按新语义处理，避免对现代代码误报；文件的 //go:build go1.N 约束优先于 go.mod
+func loopVarShared(ctx *BugRuleContext) bool {
+	if ctx.GoVersion == "" {
+		return false
+	}
+	if v := ctx.File.GoVersion; v != "" && version.Compare(v, loopVarPerIteration) >= 0 {
+		return false
+	}
+	v := "go" + ctx.GoVersion
+	return version.IsValid(v) && version.Compare(v, loopVarPerIteration) < 0
+}
+
+// findLoopVarCaptures 查找在本轮迭代之后才可能用到循环变量的闭包和指针，返回每个节点引用的循环变量：
+// go/defer 调用的闭包、传给 Go 方法（errgroup 等）的闭包、赋值或 append 保存下来的闭包，
+// 以及作为 go/defer 调用参数或 append 保存下来的 &v（goroutine 拿到的是所有迭代共享的同一个变量）
+func findLoopVarCaptures(file *ast.File) map[ast.Node][]*ast.Object {
+	loopVars := make(map[*ast.Object]bool)
+	var deferred []*ast.FuncLit
+	var addrs []*ast.UnaryExpr
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ForStmt:
+			if init, ok := node.Init.(*ast.AssignStmt); ok && init.Tok == token.DEFINE {
+				for _, lhs := range init.Lhs {
+					addLoopVar(loopVars, lhs)
+				}
+			}
+		case *ast.RangeStmt:
+			if node.Tok == token.DEFINE {
+				addLoopVar(loopVars, node.Key)
+				addLoopVar(loopVars, node.Value)
+			}
+		case *ast.GoStmt:
+			deferred = appendFuncLit(deferred, node.Call.Fun)
+			addrs = appendAddrs(addrs, node.Call.Args)
+		case *ast.DeferStmt:
+			deferred = appendFuncLit(deferred, node.Call.Fun)
+			addrs = appendAddrs(addrs, node.Call.Args)
+		case *ast.CallExpr:
+			if sel, ok := node.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Go" {
+				for _, arg := range node.Args {
+					deferred = appendFuncLit(deferred, arg)
+				}
+			}
+			if ident, ok := node.Fun.(*ast.Ident); ok && ident.Name == "append" && ident.Obj == nil {
+				for _, arg := range node.Args {
+					deferred = appendFuncLit(deferred, arg)
+				}
+				if len(node.Args) > 1 {
+					addrs = appendAddrs(addrs, node.Args[1:])
+				}
+			}
+		case *ast.AssignStmt:
+			for _, rhs := range node.Rhs {
+				deferred = appendFuncLit(deferred, rhs)
+			}
+		}
+		return true
+	})
+
+	found := make(map[ast.Node][]*ast.Object)
+	if len(loopVars) == 0 {
+		return found
+	}
+	for _, lit := range deferred {
+		seen := make(map[*ast.Object]bool)
+		ast.Inspect(lit.Body, func(n ast.Node) bool {
+			if ident, ok := n.(*ast.Ident); ok && loopVars[ident.Obj] && !seen[ident.Obj] {
+				seen[ident.Obj] = true
+				found[lit] = append(found[lit], ident.Obj)
+			}
+			return true
+		})
+	}
+	for _, addr := range addrs {
+		if obj := identObject(ast.Unparen(addr.X)); obj != nil && loopVars[obj] {
+			found[addr] = []*ast.Object{obj}
+		}
+	}
+	return found
+}
+
+// appendAddrs 追加参数中的取址表达式 &x
+func appendAddrs(addrs []*ast.UnaryExpr, args []ast.Expr) []*ast.UnaryExpr {
+	for _, arg := range args {
+		if u, ok := ast.Unparen(arg).(*ast.UnaryExpr); ok && u.Op == token.AND {
+			addrs = append(addrs, u)
+		}
+	}
+	return addrs
+}
+
+// addLoopVar 记录循环头部声明的变量
+func addLoopVar(loopVars map[*ast.Object]bool, expr ast.Expr) {
+	if ident, ok := expr.(*ast.Ident); ok && ident.Name != "_" && ident.Obj != nil {
+		loopVars[ident.Obj] = true
+	}
+}
+
+// appendFuncLit expr 是闭包时追加到列表
+func appendFuncLit(lits []*ast.FuncLit, expr ast.Expr) []*ast.FuncLit {
+	if lit, ok := ast.Unparen(expr).(*ast.FuncLit); ok {
+		return append(lits, lit)
+	}
+	return lits
+}
+
+// goVersionCache 按目录缓存所属模块 go.mod 中的 go 版本
+type goVersionCache map[string]string
+
+// lookup 从目录向上查找 go.mod，返回 go 指令的版本（如 "1.21"）
+// go.mod 没有 go 指令时按 1.16 处理（与 go 命令一致），不在模块中时返回空
+func (c goVersionCache) lookup(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	if v, ok := c[abs]; ok {
+		return v
+	}
+
+	var v string
+	data, err := os.ReadFile(filepath.Join(abs, "go.mod"))
+	switch {
+	case err == nil:
+		v = "1.16"
+		if mf, err := modfile.ParseLax("go.mod", data, nil); err == nil && mf.Go != nil {
+			v = mf.Go.Version
+		}
+	case filepath.Dir(abs) != abs:
+		v = c.lookup(filepath.Dir(abs))
+	}
+	c[abs] = v
+	return v
+}
@@ -0,0 +1,127 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// loopVarFixture 闭包捕获循环变量的夹具，构建约束和 go.mod 测试复用它的代码
+const loopVarFixture = "testdata/rules/loopvar/loopvar.go"
+
+// 测试闭包捕获循环变量：只在 go 1.22 之前的模块中报告
+func TestBugDetector_LoopVarCapture(t *testing.T) {
+	for _, version := range []string{"1.21", "1.16"} {
+		testRuleFixtures(t, "loopvar", ruleOptions{GoVersion: version}, "B112")
+	}
+	for _, version := range []string{"1.22", "1.25.5", ""} { // 版本未知时不报告
+		testNoRuleFindings(t, "loopvar", ruleOptions{GoVersion: version}, "B112")
+	}
+}
+
+// 测试文件的 //go:build 版本约束优先于 go.mod
+func TestBugDetector_LoopVarCaptureBuildConstraint(t *testing.T) {
+	code := "//go:build go1.22\n\n" + readLoopVarFixture(t)
+	bugs, err := NewBugDetector().analyzeCode(context.Background(), code, "test.go", ruleOptions{GoVersion: "1.21"})
+	if err != nil {
+		t.Fatalf("检测失败: %v", err)
+	}
+	for _, bug := range bugs {
+		if bug.RuleID == "B112" {
+			t.Errorf("go1.22 构建约束下不应报告 B112: 第 %d 行", bug.Line)
+		}
+	}
+}
+
+// 测试目录扫描时从 go.mod 读取版本
+func TestBugDetector_LoopVarCaptureGoMod(t *testing.T) {
+	code := readLoopVarFixture(t)
+	for _, tt := range []struct {
+		gomod string
+		want  int
+	}{
+		{"module example.com/old\n\ngo 1.21\n", 4},
+		{"module example.com/old\n", 4}, // 没有 go 指令按 1.16 处理
+		{"module example.com/new\n\ngo 1.22\n", 0},
+	} {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(tt.gomod), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		sub := filepath.Join(dir, "pkg")
+		if err := os.Mkdir(sub, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(sub, "main.go"), []byte(code), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := NewBugDetector().Execute(context.Background(), BugDetectorInput{Directory: dir})
+		if err != nil {
+			t.Fatalf("检测失败: %v", err)
+		}
+		got := 0
+		for _, bug := range result.Bugs {
+			if bug.RuleID == "B112" {
+				got++
+			}
+		}
+		if got != tt.want {
+			t.Errorf("%q: B112 数量 = %d, want %d", tt.gomod, got, tt.want)
+		}
+	}
+}
+
+// 测试修复：在循环体开头重新声明被捕获的循环变量，修复后不再报告
+func TestLoopVarShadowFix(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/old\n\ngo 1.21\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(file, []byte(readLoopVarFixture(t)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := runCodeFixer(t, NewCodeFixer(nil, NewNoopLogger()), FixRequest{Files: []string{file}, Fixes: []string{"B112"}, Write: true})
+	if result.TotalFixes != 2 {
+		t.Fatalf("应该修复 2 个循环: %+v", result)
+	}
+	content, _ := os.ReadFile(file)
+	for _, want := range []string{"\tfor _, item := range items {\n\t\titem := item\n\t\twg.Add(1)", "\tfor i := 0; i < 3; i++ {\n\t\ti := i\n"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("修复结果缺少 %q:\n%s", want, content)
+		}
+	}
+
+	bugs, err := NewBugDetector().analyzeCode(context.Background(), string(content), "main.go", ruleOptions{GoVersion: "1.21"})
+	if err != nil {
+		t.Fatalf("检测失败: %v", err)
+	}
+	for _, bug := range bugs {
+		if bug.RuleID == "B112" {
+			t.Errorf("修复后不应再报告 B112: 第 %d 行", bug.Line)
+		}
+	}
+
+	// go 1.22 及以上的模块不需要修复
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/new\n\ngo 1.22\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	result = runCodeFixer(t, NewCodeFixer(nil, NewNoopLogger()), FixRequest{Files: []string{file}, Fixes: []string{"B112"}})
+	if result.TotalFixes != 0 {
+		t.Errorf("go 1.22 模块不应修复: %+v", result)
+	}
+}
+
+// readLoopVarFixture 读取闭包捕获循环变量夹具的代码
+func readLoopVarFixture(t *testing.T) string {
+	t.Helper()
+	data, err := os.ReadFile(loopVarFixture)
+	if err != nil {
+		t.Fatalf("读取夹具失败: %v", err)
+	}
+	return string(data)
+}
@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// printfFuncs fmt、log 包中的格式化函数及其格式串参数的位置
+var printfFuncs = map[string]map[string]int{
+	"fmt": {"Printf": 0, "Sprintf": 0, "Errorf": 0, "Fprintf": 1, "Appendf": 1},
+	"log": {"Printf": 0, "Fatalf": 0, "Panicf": 0},
+}
+
+// 规则 32: 格式化动词与参数个数不一致，或在 fmt.Errorf 之外使用 %w
+type PrintfMismatchRule struct{}
+
+func (r *PrintfMismatchRule) ID() string       { return "B132" }
+func (r *PrintfMismatchRule) Name() string     { return "Printf Format Mismatch" }
+func (r *PrintfMismatchRule) Severity() string { return "Medium" }
+func (r *PrintfMismatchRule) Category() string { return CategoryCorrectness }
+func (r *PrintfMismatchRule) Description() string {
+	return "Printf 类调用的格式化动词个数与参数个数不一致（输出中出现 %!d(MISSING) 或 %!(EXTRA ...)），或在 fmt.Errorf 之外使用 %w（输出 %!w(...)，也不会包装错误）"
+}
+func (r *PrintfMismatchRule) GenerateSuggestion(node ast.Node) string {
+	return "让每个动词对应一个参数，%% 输出百分号；只有 fmt.Errorf 支持 %w，其他函数用 %v：\nlog.Printf(\"同步 %s 失败: %v\", name, err)\nreturn fmt.Errorf(\"同步 %s 失败: %w\", name, err)"
+}
+
+func (r *PrintfMismatchRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	call, ok := node.(*ast.CallExpr)
+	return ok && printfMismatch(call)
+}
+
+// printfMismatch 格式串为字面量的格式化调用是否动词与参数个数不一致，或在 Errorf 之外使用了 %w
+// 参数用 ... 展开或格式串使用 [n] 显式指定参数时无法静态计数，不报告
+func printfMismatch(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || call.Ellipsis.IsValid() {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok || pkg.Obj != nil {
+		return false
+	}
+	formatIndex, ok := printfFuncs[pkg.Name][sel.Sel.Name]
+	if !ok || len(call.Args) <= formatIndex {
+		return false
+	}
+	lit, ok := call.Args[formatIndex].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return false
+	}
+	verbs, ok := parsePrintfVerbs(lit.Value)
+	if !ok {
+		return false
+	}
+
+	needed := 0
+	for _, verb := range verbs {
+		if verb.Verb == 'w' && (pkg.Name != "fmt" || sel.Sel.Name != "Errorf") {
+			return true
+		}
+		needed = verb.ArgIndex + 1
+	}
+	return needed != len(call.Args)-formatIndex-1
+}
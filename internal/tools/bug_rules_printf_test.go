@@ -0,0 +1,8 @@
+package tools
+
+import "testing"
+
+// 测试格式化动词与参数个数不一致、%w 用在 Errorf 之外
+func TestBugDetector_PrintfMismatch(t *testing.T) {
+	testRuleFixtures(t, "printf", ruleOptions{}, "B132")
+}
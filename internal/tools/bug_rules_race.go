@@ -0,0 +1,353 @@
+package tools
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// 规则 33: 多个 goroutine 并发写同一个 map（数据竞争）
+type ConcurrentMapWriteRule struct{}
+
+func (r *ConcurrentMapWriteRule) ID() string       { return "B133" }
+func (r *ConcurrentMapWriteRule) Name() string     { return "Concurrent Map Write" }
+func (r *ConcurrentMapWriteRule) Severity() string { return "High" }
+func (r *ConcurrentMapWriteRule) Category() string { return CategoryConcurrency }
+func (r *ConcurrentMapWriteRule) Description() string {
+	return "go func 闭包中写入外部的 map，写入前没有加锁，而循环中启动的其他 goroutine、另一个 goroutine 或启动它的函数也在同时写这个 map；map 不是并发安全的，运行时会报 concurrent map writes 并退出"
+}
+func (r *ConcurrentMapWriteRule) GenerateSuggestion(node ast.Node) string {
+	return "写入前加锁，或改用 sync.Map，或让 goroutine 通过通道把结果交给一个 goroutine 统一写入：\nvar mu sync.Mutex\ngo func() {\n    mu.Lock()\n    defer mu.Unlock()\n    m[key] = value\n}()"
+}
+
+func (r *ConcurrentMapWriteRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	switch node.(type) {
+	case *ast.AssignStmt, *ast.IncDecStmt, *ast.CallExpr:
+	default:
+		return false
+	}
+	if ctx.File == nil {
+		return false
+	}
+	found := ctx.Memo("B133", func() any { return findConcurrentMapWrites(ctx.File, ctx.Types) }).(map[ast.Node]bool)
+	return found[node]
+}
+
+// mapWrite 对 map 的一次写入：m[k] = v、m[k]++、delete(m, k)、clear(m)
+type mapWrite struct {
+	node   ast.Node // 报告问题的节点（赋值语句或调用）
+	target ast.Expr // 被写入的 map 表达式
+}
+
+// goMapWrite goroutine 中对外部 map 的一次写入
+type goMapWrite struct {
+	mapWrite
+	owner  *ast.GoStmt
+	key    any  // 写入的 map：局部变量为 *ast.Object，字段等为表达式文本
+	inLoop bool // go 语句在循环中，会启动多个写同一个 map 的 goroutine
+}
+
+// findConcurrentMapWrites 查找 go func 闭包中没有加锁的 map 写入，且同时存在其他写入方：
+// go 语句在循环中、同一个 map 被多个 goroutine 写入，或启动 goroutine 之后本函数在同步（Wait、接收、加锁）之前也写入了它
+// 只按源码顺序判断，不分析具名函数中的写入和通过通道完成的交接，作为启发式规则置信度为中
+func findConcurrentMapWrites(file *ast.File, info *types.Info) map[ast.Node]bool {
+	found := make(map[ast.Node]bool)
+	fields := mapFields(file)
+	forEachFunc(file, func(ftype *ast.FuncType, body *ast.BlockStmt) {
+		var writes []goMapWrite
+		var direct []mapWrite // 本函数中（goroutine 之外）的写入
+		var syncs []token.Pos // 本函数中的同步点
+		inspectWithParents(body, func(n ast.Node, parents []ast.Node) {
+			var loops []ast.Node
+			for _, p := range parents {
+				switch p.(type) {
+				case *ast.FuncLit:
+					return // 闭包由 forEachFunc 单独处理
+				case *ast.ForStmt, *ast.RangeStmt:
+					loops = append(loops, p)
+				}
+			}
+			if w, ok := mapWriteOf(n); ok {
+				direct = append(direct, w)
+			}
+			if isSyncPoint(n) {
+				syncs = append(syncs, n.Pos())
+			}
+			g, ok := n.(*ast.GoStmt)
+			if !ok {
+				return
+			}
+			lit, ok := g.Call.Fun.(*ast.FuncLit)
+			if !ok {
+				return
+			}
+			for _, w := range goroutineMapWrites(lit) {
+				target, local := capturedTarget(w.target, lit, g.Call.Args)
+				if local || !isMapExpr(target, info, fields) {
+					continue
+				}
+				writes = append(writes, goMapWrite{mapWrite: w, owner: g, key: mapWriteKey(target), inLoop: sharedAcrossLoop(target, loops)})
+			}
+		})
+
+		writers := make(map[any]map[*ast.GoStmt]bool)
+		for _, w := range writes {
+			if writers[w.key] == nil {
+				writers[w.key] = make(map[*ast.GoStmt]bool)
+			}
+			writers[w.key][w.owner] = true
+		}
+		reported := make(map[*ast.GoStmt]map[any]bool)
+		for _, w := range writes {
+			if reported[w.owner][w.key] {
+				continue
+			}
+			if !w.inLoop && len(writers[w.key]) < 2 && !writtenAfter(direct, syncs, w.owner, w.key) {
+				continue
+			}
+			found[w.node] = true
+			if reported[w.owner] == nil {
+				reported[w.owner] = make(map[any]bool)
+			}
+			reported[w.owner][w.key] = true
+		}
+	})
+	return found
+}
+
+// goroutineMapWrites goroutine 闭包中没有加锁的 map 写入（不含其中再启动的 goroutine，它们单独检查）
+// 写入之前闭包中调用过任意 Lock 即认为受保护
+func goroutineMapWrites(lit *ast.FuncLit) []mapWrite {
+	var locks []token.Pos
+	var writes []mapWrite
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.GoStmt); ok {
+			return false
+		}
+		if call, ok := n.(*ast.CallExpr); ok {
+			if _, ok := methodReceiver(call, "Lock"); ok {
+				locks = append(locks, call.Pos())
+			}
+		}
+		if w, ok := mapWriteOf(n); ok {
+			writes = append(writes, w)
+		}
+		return true
+	})
+
+	var unlocked []mapWrite
+	for _, w := range writes {
+		locked := false
+		for _, pos := range locks {
+			if pos < w.node.Pos() {
+				locked = true
+				break
+			}
+		}
+		if !locked {
+			unlocked = append(unlocked, w)
+		}
+	}
+	return unlocked
+}
+
+// mapWriteOf 节点是否是对索引表达式的写入，返回被写入的表达式（是否为 map 由调用方判断）
+func mapWriteOf(n ast.Node) (mapWrite, bool) {
+	switch node := n.(type) {
+	case *ast.AssignStmt:
+		for _, lhs := range node.Lhs {
+			if index, ok := ast.Unparen(lhs).(*ast.IndexExpr); ok {
+				return mapWrite{node: node, target: index.X}, true
+			}
+		}
+	case *ast.IncDecStmt:
+		if index, ok := ast.Unparen(node.X).(*ast.IndexExpr); ok {
+			return mapWrite{node: node, target: index.X}, true
+		}
+	case *ast.CallExpr:
+		if (isBuiltin(node.Fun, "delete") && len(node.Args) == 2) || (isBuiltin(node.Fun, "clear") && len(node.Args) == 1) {
+			return mapWrite{node: node, target: node.Args[0]}, true
+		}
+	}
+	return mapWrite{}, false
+}
+
+// capturedTarget 把闭包中被写入的表达式换算为闭包外的 map：闭包参数换成 go 语句对应的实参
+// local 为 true 表示 map 在闭包内声明，每个 goroutine 各有一份
+func capturedTarget(target ast.Expr, lit *ast.FuncLit, args []ast.Expr) (ast.Expr, bool) {
+	root := rootIdent(target)
+	if root == nil || root.Obj == nil {
+		return target, false
+	}
+	if !(lit.Pos() <= root.Obj.Pos() && root.Obj.Pos() < lit.End()) {
+		return target, false
+	}
+	// 参数按声明顺序对应实参；只换算直接写入参数本身的情况
+	if ident, ok := ast.Unparen(target).(*ast.Ident); ok {
+		i := 0
+		for _, field := range lit.Type.Params.List {
+			for _, name := range field.Names {
+				if name.Obj == ident.Obj && i < len(args) {
+					return ast.Unparen(args[i]), false
+				}
+				i++
+			}
+			if len(field.Names) == 0 {
+				i++
+			}
+		}
+	}
+	return target, true
+}
+
+// rootIdent 选择器表达式最左边的标识符，如 s.cache.items 的 s
+func rootIdent(expr ast.Expr) *ast.Ident {
+	for {
+		switch e := ast.Unparen(expr).(type) {
+		case *ast.Ident:
+			return e
+		case *ast.SelectorExpr:
+			expr = e.X
+		case *ast.StarExpr:
+			expr = e.X
+		default:
+			return nil
+		}
+	}
+}
+
+// isMapExpr 表达式是否是 map：有类型信息时按类型判断，否则按变量声明或本文件结构体字段的类型判断
+func isMapExpr(expr ast.Expr, info *types.Info, fields map[string]bool) bool {
+	if info != nil {
+		if t := info.TypeOf(expr); t != nil {
+			_, ok := t.Underlying().(*types.Map)
+			return ok
+		}
+	}
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		return e.Obj != nil && declaresMap(e.Obj)
+	case *ast.SelectorExpr:
+		return fields[e.Sel.Name]
+	}
+	return false
+}
+
+// declaresMap 变量的声明是否表明它是 map：声明类型为 map，或初值是 make(map...) / map 字面量
+func declaresMap(obj *ast.Object) bool {
+	isMapValue := func(expr ast.Expr) bool {
+		switch v := ast.Unparen(expr).(type) {
+		case *ast.CallExpr:
+			if isBuiltin(v.Fun, "make") && len(v.Args) > 0 {
+				_, ok := v.Args[0].(*ast.MapType)
+				return ok
+			}
+		case *ast.CompositeLit:
+			_, ok := v.Type.(*ast.MapType)
+			return ok
+		}
+		return false
+	}
+	switch decl := obj.Decl.(type) {
+	case *ast.ValueSpec:
+		if _, ok := decl.Type.(*ast.MapType); ok {
+			return true
+		}
+		for i, name := range decl.Names {
+			if name.Obj == obj && i < len(decl.Values) {
+				return isMapValue(decl.Values[i])
+			}
+		}
+	case *ast.AssignStmt:
+		if len(decl.Lhs) != len(decl.Rhs) {
+			return false
+		}
+		for i, lhs := range decl.Lhs {
+			if identObject(lhs) == obj {
+				return isMapValue(decl.Rhs[i])
+			}
+		}
+	case *ast.Field:
+		_, ok := decl.Type.(*ast.MapType)
+		return ok
+	}
+	return false
+}
+
+// mapFields 本文件结构体中类型为 map 的字段名（同名字段类型不一致时不计入）
+func mapFields(file *ast.File) map[string]bool {
+	fields := make(map[string]bool)
+	conflict := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		st, ok := n.(*ast.StructType)
+		if !ok {
+			return true
+		}
+		for _, field := range st.Fields.List {
+			_, isMap := field.Type.(*ast.MapType)
+			for _, name := range field.Names {
+				if isMap {
+					fields[name.Name] = true
+				} else {
+					conflict[name.Name] = true
+				}
+			}
+		}
+		return true
+	})
+	for name := range conflict {
+		delete(fields, name)
+	}
+	return fields
+}
+
+// sharedAcrossLoop 循环中启动的 goroutine 是否写同一个 map：map 在循环外声明（循环体内声明的每轮各有一份）
+func sharedAcrossLoop(target ast.Expr, loops []ast.Node) bool {
+	root := rootIdent(target)
+	for _, loop := range loops {
+		if root == nil || root.Obj == nil || !(loop.Pos() <= root.Obj.Pos() && root.Obj.Pos() < loop.End()) {
+			return true
+		}
+	}
+	return false
+}
+
+// mapWriteKey 标识被写入的 map：变量用声明对象，字段等用表达式文本
+func mapWriteKey(expr ast.Expr) any {
+	if ident, ok := ast.Unparen(expr).(*ast.Ident); ok && ident.Obj != nil {
+		return ident.Obj
+	}
+	return types.ExprString(expr)
+}
+
+// isSyncPoint 节点是否是启动 goroutine 的函数与它同步的方式：Wait、通道接收、select 或加锁
+func isSyncPoint(n ast.Node) bool {
+	switch node := n.(type) {
+	case *ast.CallExpr:
+		_, wait := methodReceiver(node, "Wait")
+		_, lock := methodReceiver(node, "Lock")
+		return wait || lock
+	case *ast.UnaryExpr:
+		return node.Op == token.ARROW
+	case *ast.SelectStmt:
+		return true
+	}
+	return false
+}
+
+// writtenAfter 启动 goroutine 之后、下一个同步点之前，本函数是否也写入了同一个 map
+func writtenAfter(direct []mapWrite, syncs []token.Pos, g *ast.GoStmt, key any) bool {
+	next := token.NoPos
+	for _, pos := range syncs {
+		if pos > g.End() && (next == token.NoPos || pos < next) {
+			next = pos
+		}
+	}
+	for _, w := range direct {
+		pos := w.node.Pos()
+		if pos > g.End() && (next == token.NoPos || pos < next) && mapWriteKey(w.target) == key {
+			return true
+		}
+	}
+	return false
+}
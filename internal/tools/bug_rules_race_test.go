@@ -0,0 +1,8 @@
+package tools
+
+import "testing"
+
+// 测试 goroutine 并发写 map
+func TestBugDetector_ConcurrentMapWrite(t *testing.T) {
+	testRuleFixtures(t, "race", ruleOptions{}, "B133")
+}
@@ -0,0 +1,339 @@
+package tools
+
+import (
+	"go/ast"
+	"go/version"
+)
+
+// resourcePair 一类需要成对释放的资源：获取资源的调用和对应的释放方法
+// B102（文件）、B120（rows）、B131（ticker）共用同一套路径检查
+type resourcePair struct {
+	ruleID  string
+	release string                                             // 释放方法名，如 Close、Stop
+	acquire func(ctx *BugRuleContext, call *ast.CallExpr) bool // 是否是获取资源的调用
+	// tracked 接收资源的变量是否确实是这类资源（按方法名识别调用时用变量的用法排除同名方法），为 nil 表示总是；
+	// 设置了 tracked 的资源无法判断被丢弃的结果，丢弃时不报告
+	tracked func(body *ast.BlockStmt, obj *ast.Object) bool
+	// handedOff 资源是否交给了其他代码负责释放
+	handedOff func(body *ast.BlockStmt, obj *ast.Object) bool
+}
+
+// tickerCollectedVersion 从这个版本开始，没有 Stop 的 Ticker 不再被引用后也会被回收
+const tickerCollectedVersion = "go1.23"
+
+// resourcePairsFor 当前文件需要检查的资源类型
+func resourcePairsFor(ctx *BugRuleContext) []resourcePair {
+	pairs := []resourcePair{
+		{
+			ruleID:  "B102",
+			release: "Close",
+			acquire: func(ctx *BugRuleContext, call *ast.CallExpr) bool {
+				// 有类型信息时确认调用的确实是 os 包的函数，而不是名为 os 的变量
+				if ctx.Typed() {
+					return isPackageFunc(ctx.Callee(call), "os", "Open", "Create", "OpenFile")
+				}
+				return isFileOpenFunction(call)
+			},
+			handedOff: handedOff,
+		},
+		{
+			ruleID:  "B120",
+			release: "Close",
+			acquire: func(ctx *BugRuleContext, call *ast.CallExpr) bool {
+				switch sqlMethod(call) {
+				case "Query", "QueryContext":
+					return true
+				}
+				return false
+			},
+			// 只检查用 Next 遍历的结果，排除其他同名的 Query 方法
+			tracked: func(body *ast.BlockStmt, obj *ast.Object) bool {
+				return methodCalls(body, obj)["Next"]
+			},
+			handedOff: escapes,
+		},
+	}
+	if !tickerCollected(ctx) {
+		pairs = append(pairs, resourcePair{
+			ruleID:  "B131",
+			release: "Stop",
+			acquire: func(ctx *BugRuleContext, call *ast.CallExpr) bool {
+				if ctx.Typed() {
+					return isPackageFunc(ctx.Callee(call), "time", "NewTicker")
+				}
+				return isTimeFunc(call.Fun, "NewTicker")
+			},
+			handedOff: escapes,
+		})
+	}
+	return pairs
+}
+
+// tickerCollected 当前文件的模块版本是否已知不低于 go 1.23（不再被引用的 Ticker 会被回收，不 Stop 也不会泄漏）
+func tickerCollected(ctx *BugRuleContext) bool {
+	if v := ctx.File.GoVersion; v != "" && version.Compare(v, tickerCollectedVersion) >= 0 {
+		return true
+	}
+	v := "go" + ctx.GoVersion
+	return ctx.GoVersion != "" && version.IsValid(v) && version.Compare(v, tickerCollectedVersion) >= 0
+}
+
+// findUnreleasedResources 按函数检查获取的资源是否释放（B102、B120、B131）
+// 跟踪接收资源的变量：defer x.Close()（包括 defer 的闭包中调用）、在之后每条 return 和语句块结束前都显式释放、
+// 或把资源交给其他代码时不报告；资源被丢弃（赋给 _ 或没有接收）时总是报告
+func findUnreleasedResources(ctx *BugRuleContext) map[ast.Node][]string {
+	found := make(map[ast.Node][]string)
+	pairs := resourcePairsFor(ctx)
+
+	forEachFunc(ctx.File, func(ftype *ast.FuncType, body *ast.BlockStmt) {
+		type located struct {
+			node    ast.Node
+			parents []ast.Node // 从函数体到直接父节点
+			pair    *resourcePair
+		}
+		var acquires, returns []located
+		inspectWithParents(body, func(n ast.Node, parents []ast.Node) {
+			// 闭包中获取的资源和 return 由闭包自己检查
+			for _, p := range parents {
+				if _, ok := p.(*ast.FuncLit); ok {
+					return
+				}
+			}
+			parents = append([]ast.Node(nil), parents...)
+			switch node := n.(type) {
+			case *ast.CallExpr:
+				for i := range pairs {
+					if pairs[i].acquire(ctx, node) {
+						acquires = append(acquires, located{node, parents, &pairs[i]})
+						break
+					}
+				}
+			case *ast.ReturnStmt:
+				returns = append(returns, located{node, parents, nil})
+			}
+		})
+
+		for _, acq := range acquires {
+			call, pair := acq.node.(*ast.CallExpr), acq.pair
+			var stmt ast.Stmt
+			var resVar, errVar ast.Expr
+			switch parent := acq.parents[len(acq.parents)-1].(type) {
+			case *ast.ExprStmt:
+				if pair.tracked == nil {
+					found[call] = append(found[call], pair.ruleID)
+				}
+				continue
+			case *ast.AssignStmt:
+				if len(parent.Rhs) != 1 {
+					continue
+				}
+				stmt, resVar = parent, parent.Lhs[0]
+				if len(parent.Lhs) > 1 {
+					errVar = parent.Lhs[1]
+				}
+			case *ast.ValueSpec:
+				if len(parent.Values) != 1 || len(acq.parents) < 3 {
+					continue
+				}
+				stmt, resVar = acq.parents[len(acq.parents)-3].(ast.Stmt), parent.Names[0]
+				if len(parent.Names) > 1 {
+					errVar = parent.Names[1]
+				}
+			default:
+				// 直接返回或作为参数传递，由接收方负责释放
+				continue
+			}
+
+			if ident, ok := resVar.(*ast.Ident); ok && ident.Name == "_" {
+				if pair.tracked == nil {
+					found[call] = append(found[call], pair.ruleID)
+				}
+				continue
+			}
+			obj := identObject(resVar)
+			if obj == nil || (pair.tracked != nil && !pair.tracked(body, obj)) ||
+				deferredCall(body, obj, pair.release) || pair.handedOff(body, obj) {
+				continue
+			}
+
+			// 变量所在的语句列表：之后的 return 和列表结束（函数结束、循环进入下一轮）都是退出点
+			scope := acq.parents[0]
+			for _, p := range acq.parents {
+				if _, ok := stmtList(p); ok && p.Pos() <= stmt.Pos() {
+					scope = p
+				}
+			}
+			errObj := identObject(errVar)
+			released := true
+			for _, ret := range returns {
+				r := ret.node.(*ast.ReturnStmt)
+				if r.Pos() < stmt.End() || r.End() > scope.End() || inErrCheck(r, ret.parents, errObj) {
+					continue
+				}
+				if !releasedBefore(r, ret.parents, stmt, scope, obj, pair.release) {
+					released = false
+					break
+				}
+			}
+			if list, _ := stmtList(scope); released && !terminates(list) && !releasedBefore(nil, []ast.Node{scope}, stmt, scope, obj, pair.release) {
+				released = false
+			}
+			if !released {
+				found[call] = append(found[call], pair.ruleID)
+			}
+		}
+	})
+	return found
+}
+
+// matchUnreleased 查表判断节点是否命中资源释放规则
+func matchUnreleased(node ast.Node, ctx *BugRuleContext, ruleID string) bool {
+	return matchFileFinding(node, ctx, "resource", func(*ast.File) map[ast.Node][]string { return findUnreleasedResources(ctx) }, ruleID)
+}
+
+// stmtList 语句块、case 和 select 分支中的语句列表
+func stmtList(n ast.Node) ([]ast.Stmt, bool) {
+	switch node := n.(type) {
+	case *ast.BlockStmt:
+		return node.List, true
+	case *ast.CaseClause:
+		return node.Body, true
+	case *ast.CommClause:
+		return node.Body, true
+	}
+	return nil, false
+}
+
+// releasedBefore 从 scope 到 exit 的每一层语句列表中，获取语句 open 与 exit 之间是否有顶层的 x.<release>() 调用
+// exit 为 nil 表示 scope 的语句列表结束处；exit 本身是 return x.Close() 也算释放
+func releasedBefore(exit ast.Stmt, parents []ast.Node, open ast.Stmt, scope ast.Node, obj *ast.Object, release string) bool {
+	if exit != nil && releases(exit, obj, release) {
+		return true
+	}
+	end := scope.End()
+	if exit != nil {
+		end = exit.Pos()
+	}
+	for _, p := range parents {
+		list, ok := stmtList(p)
+		if !ok || p.Pos() < scope.Pos() || p.End() > scope.End() {
+			continue
+		}
+		for _, s := range list {
+			if s.Pos() > open.Pos() && s.End() <= end && releases(s, obj, release) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// releases 语句本身是否调用了 x.<release>()：单独调用、err = x.Close()、if err := x.Close(); ... 或 return x.Close()
+func releases(stmt ast.Stmt, obj *ast.Object, release string) bool {
+	isClose := func(expr ast.Expr) bool {
+		call, ok := ast.Unparen(expr).(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		return ok && sel.Sel.Name == release && identObject(sel.X) == obj
+	}
+	anyClose := func(exprs []ast.Expr) bool {
+		for _, expr := range exprs {
+			if isClose(expr) {
+				return true
+			}
+		}
+		return false
+	}
+	switch s := stmt.(type) {
+	case *ast.ExprStmt:
+		return isClose(s.X)
+	case *ast.AssignStmt:
+		return anyClose(s.Rhs)
+	case *ast.ReturnStmt:
+		return anyClose(s.Results)
+	case *ast.IfStmt:
+		return s.Init != nil && releases(s.Init, obj, release)
+	}
+	return false
+}
+
+// inErrCheck return 是否在获取资源的错误检查 if err != nil { ... } 中，此时资源为 nil，不需要释放
+func inErrCheck(ret *ast.ReturnStmt, parents []ast.Node, errObj *ast.Object) bool {
+	if errObj == nil {
+		return false
+	}
+	for _, p := range parents {
+		ifStmt, ok := p.(*ast.IfStmt)
+		if !ok || ret.Pos() < ifStmt.Body.Pos() || ret.End() > ifStmt.Body.End() {
+			continue
+		}
+		if cond, ok := ifStmt.Cond.(*ast.BinaryExpr); ok && identObject(cond.X) == errObj && isNilIdent(cond.Y) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNilIdent 表达式是否是 nil
+func isNilIdent(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "nil"
+}
+
+// terminates 语句列表是否以 return 或 panic 结束（之后没有落到列表末尾的路径）
+func terminates(list []ast.Stmt) bool {
+	if len(list) == 0 {
+		return false
+	}
+	switch last := list[len(list)-1].(type) {
+	case *ast.ReturnStmt:
+		return true
+	case *ast.ExprStmt:
+		if call, ok := last.X.(*ast.CallExpr); ok {
+			if ident, ok := call.Fun.(*ast.Ident); ok && ident.Name == "panic" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// handedOff 文件是否交给了函数之外：被返回、赋给其他变量或字段、放入复合字面量或发送到 channel
+// 作为参数传给 io.Copy 等函数不算，调用方仍然负责关闭
+func handedOff(body *ast.BlockStmt, obj *ast.Object) bool {
+	isFile := func(expr ast.Expr) bool {
+		if u, ok := expr.(*ast.UnaryExpr); ok {
+			expr = u.X
+		}
+		return identObject(ast.Unparen(expr)) == obj
+	}
+	handed := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ReturnStmt:
+			for _, result := range node.Results {
+				handed = handed || isFile(result)
+			}
+		case *ast.AssignStmt:
+			for i, rhs := range node.Rhs {
+				if i < len(node.Lhs) && isFile(rhs) {
+					ident, ok := node.Lhs[i].(*ast.Ident)
+					handed = handed || !ok || ident.Name != "_"
+				}
+			}
+		case *ast.CompositeLit:
+			for _, elt := range node.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					elt = kv.Value
+				}
+				handed = handed || isFile(elt)
+			}
+		case *ast.SendStmt:
+			handed = handed || isFile(node.Value)
+		}
+		return !handed
+	})
+	return handed
+}
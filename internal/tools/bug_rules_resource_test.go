@@ -0,0 +1,16 @@
+package tools
+
+import "testing"
+
+// 测试文件打开后是否在每条路径上关闭
+func TestBugDetector_ResourceNotClosedPaths(t *testing.T) {
+	testRuleFixtures(t, "resource", ruleOptions{}, "B102")
+}
+
+// 测试 Ticker 没有 Stop：go 1.23 起不再被引用的 Ticker 会被回收，不再报告
+func TestBugDetector_TickerNotStopped(t *testing.T) {
+	for _, version := range []string{"1.22", ""} { // 版本未知时报告
+		testRuleFixtures(t, "resource", ruleOptions{GoVersion: version}, "B131")
+	}
+	testNoRuleFindings(t, "resource", ruleOptions{GoVersion: "1.23"}, "B131")
+}
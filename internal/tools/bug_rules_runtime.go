@@ -0,0 +1,231 @@
+package tools
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// 规则 9: 向未初始化的 map 写入（运行时 panic）
+type NilMapWriteRule struct{}
+
+func (r *NilMapWriteRule) ID() string       { return "B109" }
+func (r *NilMapWriteRule) Name() string     { return "Write To Nil Map" }
+func (r *NilMapWriteRule) Severity() string { return "High" }
+func (r *NilMapWriteRule) Category() string { return CategoryNullSafety }
+func (r *NilMapWriteRule) Description() string {
+	return "用 var 声明的 map 没有 make 就写入元素，运行时必然 panic"
+}
+func (r *NilMapWriteRule) GenerateSuggestion(node ast.Node) string {
+	return "写入前用 make 初始化：\nm := make(map[string]int)\nm[\"key\"] = 1"
+}
+
+func (r *NilMapWriteRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	return matchNilUse(node, ctx, "B109")
+}
+
+// 规则 10: 在被等待的 goroutine 内部调用 WaitGroup.Add
+type WaitGroupAddInGoroutineRule struct{}
+
+func (r *WaitGroupAddInGoroutineRule) ID() string       { return "B110" }
+func (r *WaitGroupAddInGoroutineRule) Name() string     { return "WaitGroup.Add Inside Goroutine" }
+func (r *WaitGroupAddInGoroutineRule) Severity() string { return "High" }
+func (r *WaitGroupAddInGoroutineRule) Category() string { return CategoryConcurrency }
+func (r *WaitGroupAddInGoroutineRule) Description() string {
+	return "WaitGroup.Add 在它所等待的 goroutine 内部调用，Wait 可能在 Add 之前返回"
+}
+func (r *WaitGroupAddInGoroutineRule) GenerateSuggestion(node ast.Node) string {
+	return "在启动 goroutine 之前调用 Add：\nwg.Add(1)\ngo func() {\n    defer wg.Done()\n    work()\n}()\nwg.Wait()"
+}
+
+func (r *WaitGroupAddInGoroutineRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	call, ok := node.(*ast.CallExpr)
+	if !ok || ctx.File == nil {
+		return false
+	}
+	found := ctx.Memo("B110", func() any { return findWaitGroupAddInGoroutine(ctx.File) }).(map[*ast.CallExpr]bool)
+	return found[call]
+}
+
+// 规则 11: 在未初始化的通道上收发（永久阻塞）
+type NilChannelRule struct{}
+
+func (r *NilChannelRule) ID() string       { return "B111" }
+func (r *NilChannelRule) Name() string     { return "Nil Channel Operation" }
+func (r *NilChannelRule) Severity() string { return "High" }
+func (r *NilChannelRule) Category() string { return CategoryConcurrency }
+func (r *NilChannelRule) Description() string {
+	return "用 var 声明的通道没有 make 就收发或 range，goroutine 会永久阻塞"
+}
+func (r *NilChannelRule) GenerateSuggestion(node ast.Node) string {
+	return "收发前用 make 创建通道：\nch := make(chan int, 1)\nch <- 1\nv := <-ch"
+}
+
+func (r *NilChannelRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	return matchNilUse(node, ctx, "B111")
+}
+
+// 规则 29: 单返回值的类型断言（断言失败时 panic）
+type UncheckedTypeAssertRule struct{}
+
+func (r *UncheckedTypeAssertRule) ID() string       { return "B129" }
+func (r *UncheckedTypeAssertRule) Name() string     { return "Unchecked Type Assertion" }
+func (r *UncheckedTypeAssertRule) Severity() string { return "Medium" }
+func (r *UncheckedTypeAssertRule) Category() string { return CategoryCorrectness }
+func (r *UncheckedTypeAssertRule) Description() string {
+	return "类型断言 v.(T) 没有使用 comma-ok 形式，v 的动态类型不是 T 时运行时 panic"
+}
+func (r *UncheckedTypeAssertRule) GenerateSuggestion(node ast.Node) string {
+	return "使用 comma-ok 形式并处理断言失败，或改用类型 switch：\nx, ok := v.(T)\nif !ok {\n    return fmt.Errorf(\"意外的类型 %T\", v)\n}"
+}
+
+func (r *UncheckedTypeAssertRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	assert, ok := node.(*ast.TypeAssertExpr)
+	// Type 为 nil 的是类型 switch 中的 v.(type)
+	if !ok || assert.Type == nil || ctx.File == nil {
+		return false
+	}
+	checked := ctx.Memo("B129", func() any { return findCommaOkAsserts(ctx.File) }).(map[*ast.TypeAssertExpr]bool)
+	return !checked[assert]
+}
+
+// findCommaOkAsserts 收集以 comma-ok 形式使用的类型断言：v, ok := x.(T)、v, ok = x.(T)、var v, ok = x.(T)
+func findCommaOkAsserts(file *ast.File) map[*ast.TypeAssertExpr]bool {
+	checked := make(map[*ast.TypeAssertExpr]bool)
+	add := func(lhs int, rhs []ast.Expr) {
+		if lhs != 2 || len(rhs) != 1 {
+			return
+		}
+		if assert, ok := ast.Unparen(rhs[0]).(*ast.TypeAssertExpr); ok {
+			checked[assert] = true
+		}
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			add(len(node.Lhs), node.Rhs)
+		case *ast.ValueSpec:
+			add(len(node.Names), node.Values)
+		}
+		return true
+	})
+	return checked
+}
+
+// matchNilUse 查表判断节点是否是对未初始化 map/通道的使用
+func matchNilUse(node ast.Node, ctx *BugRuleContext, ruleID string) bool {
+	switch node.(type) {
+	case *ast.AssignStmt, *ast.IncDecStmt, *ast.SendStmt, *ast.UnaryExpr, *ast.RangeStmt:
+	default:
+		return false
+	}
+	if ctx.File == nil {
+		return false
+	}
+	found := ctx.Memo("nil-use", func() any { return findNilUses(ctx.File) }).(map[ast.Node]string)
+	return found[node] == ruleID
+}
+
+// findNilUses 查找对零值 map 的元素写入（B109）和对零值通道的收发（B111）
+// 只检查 var 声明且没有初值的局部变量：在第一次整体赋值之前、且不在会重新赋值的循环中的使用必然出错
+func findNilUses(file *ast.File) map[ast.Node]string {
+	found := make(map[ast.Node]string)
+	forEachFunc(file, func(ftype *ast.FuncType, body *ast.BlockStmt) {
+		fv := collectFuncVars(ftype, body)
+		for obj, uses := range fv.uses {
+			if fv.unsafe[obj] || len(fv.inits[obj]) == 0 || fv.inits[obj][0] != nil {
+				continue
+			}
+			var kind varUseKind
+			var ruleID string
+			switch fv.types[obj].(type) {
+			case *ast.MapType:
+				kind, ruleID = varIndexWrite, "B109"
+			case *ast.ChanType:
+				kind, ruleID = varChanOp, "B111"
+			default:
+				continue
+			}
+
+			for _, u := range uses {
+				if u.kind == varWrite {
+					break
+				}
+				if u.kind == kind && !fv.writtenInLoop(uses, u.pos) {
+					found[u.node] = ruleID
+				}
+			}
+		}
+	})
+	return found
+}
+
+// writtenInLoop 包含 pos 的循环中是否有整体赋值（下一轮执行时变量可能已经初始化）
+func (fv *funcVars) writtenInLoop(uses []varUse, pos token.Pos) bool {
+	for _, loop := range fv.loops {
+		if pos < loop.Pos() || pos >= loop.End() {
+			continue
+		}
+		for _, u := range uses {
+			if u.kind == varWrite && loop.Pos() <= u.pos && u.pos < loop.End() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// findWaitGroupAddInGoroutine 查找 go func() { wg.Add(...) }() 中的 Add 调用，
+// 且同一个 wg 在启动它的函数中（goroutine 之外）调用了 Wait、但没有调用 Add
+// 外层已经 Add 过时 goroutine 本身被计数，在其中再 Add 是安全的嵌套用法
+func findWaitGroupAddInGoroutine(file *ast.File) map[*ast.CallExpr]bool {
+	found := make(map[*ast.CallExpr]bool)
+	forEachFunc(file, func(ftype *ast.FuncType, body *ast.BlockStmt) {
+		// 本函数中（不含闭包）调用了 Wait / Add 的接收者
+		waited := make(map[string]bool)
+		added := make(map[string]bool)
+		var goroutines []*ast.FuncLit
+		ast.Inspect(body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.GoStmt:
+				if lit, ok := node.Call.Fun.(*ast.FuncLit); ok {
+					goroutines = append(goroutines, lit)
+				}
+			case *ast.CallExpr:
+				if recv, ok := methodReceiver(node, "Wait"); ok {
+					waited[recv] = true
+				}
+				if recv, ok := methodReceiver(node, "Add"); ok {
+					added[recv] = true
+				}
+			}
+			return true
+		})
+
+		for _, lit := range goroutines {
+			ast.Inspect(lit.Body, func(n ast.Node) bool {
+				switch node := n.(type) {
+				case *ast.FuncLit:
+					return false
+				case *ast.CallExpr:
+					if recv, ok := methodReceiver(node, "Add"); ok && waited[recv] && !added[recv] && len(node.Args) == 1 {
+						found[node] = true
+					}
+				}
+				return true
+			})
+		}
+	})
+	return found
+}
+
+// methodReceiver 如果调用形如 x.Method(...)，返回接收者表达式的文本
+func methodReceiver(call *ast.CallExpr, method string) (string, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != method {
+		return "", false
+	}
+	return types.ExprString(sel.X), true
+}
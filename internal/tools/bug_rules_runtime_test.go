@@ -0,0 +1,23 @@
+package tools
+
+import "testing"
+
+// 测试向未初始化的 map 写入
+func TestBugDetector_NilMapWrite(t *testing.T) {
+	testRuleFixtures(t, "runtime", ruleOptions{}, "B109")
+}
+
+// 测试在被等待的 goroutine 内部调用 WaitGroup.Add
+func TestBugDetector_WaitGroupAddInGoroutine(t *testing.T) {
+	testRuleFixtures(t, "runtime", ruleOptions{}, "B110")
+}
+
+// 测试在未初始化的通道上收发
+func TestBugDetector_NilChannel(t *testing.T) {
+	testRuleFixtures(t, "runtime", ruleOptions{}, "B111")
+}
+
+// 测试单返回值的类型断言
+func TestBugDetector_UncheckedTypeAssert(t *testing.T) {
+	testRuleFixtures(t, "runtime", ruleOptions{}, "B129")
+}
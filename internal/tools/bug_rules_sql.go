@@ -0,0 +1,332 @@
+package tools
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// 规则 20: Query 返回的 rows 没有 Close
+type RowsNotClosedRule struct{}
+
+func (r *RowsNotClosedRule) ID() string       { return "B120" }
+func (r *RowsNotClosedRule) Name() string     { return "Rows Not Closed" }
+func (r *RowsNotClosedRule) Severity() string { return "High" }
+func (r *RowsNotClosedRule) Category() string { return CategoryResourceMgmt }
+func (r *RowsNotClosedRule) Description() string {
+	return "Query 返回的 rows 没有调用 Close，提前退出循环或出错时连接不会归还连接池"
+}
+func (r *RowsNotClosedRule) GenerateSuggestion(node ast.Node) string {
+	return "检查错误后立即 defer rows.Close()：\nrows, err := db.QueryContext(ctx, query, args...)\nif err != nil {\n    return err\n}\ndefer rows.Close()"
+}
+
+func (r *RowsNotClosedRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	return matchUnreleased(node, ctx, "B120")
+}
+
+// 规则 21: 遍历 rows 后没有检查 rows.Err()
+type RowsErrNotCheckedRule struct{}
+
+func (r *RowsErrNotCheckedRule) ID() string       { return "B121" }
+func (r *RowsErrNotCheckedRule) Name() string     { return "Rows Error Not Checked" }
+func (r *RowsErrNotCheckedRule) Severity() string { return "Medium" }
+func (r *RowsErrNotCheckedRule) Category() string { return CategoryErrorHandling }
+func (r *RowsErrNotCheckedRule) Description() string {
+	return "遍历 rows 后没有检查 rows.Err()，迭代中途出错（网络中断、超时）时结果被静默截断"
+}
+func (r *RowsErrNotCheckedRule) GenerateSuggestion(node ast.Node) string {
+	return "循环结束后检查 rows.Err()：\nfor rows.Next() {\n    ...\n}\nif err := rows.Err(); err != nil {\n    return err\n}"
+}
+
+func (r *RowsErrNotCheckedRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	return matchSQLMisuse(node, ctx, "B121")
+}
+
+// 规则 22: QueryRow().Scan 的错误没有区分 sql.ErrNoRows
+type ErrNoRowsUnhandledRule struct{}
+
+func (r *ErrNoRowsUnhandledRule) ID() string       { return "B122" }
+func (r *ErrNoRowsUnhandledRule) Name() string     { return "sql.ErrNoRows Not Handled" }
+func (r *ErrNoRowsUnhandledRule) Severity() string { return "Medium" }
+func (r *ErrNoRowsUnhandledRule) Category() string { return CategoryErrorHandling }
+func (r *ErrNoRowsUnhandledRule) Description() string {
+	return "QueryRow 的 Scan 错误既没有区分 sql.ErrNoRows，也没有原样返回给调用方，查不到数据会被当作数据库故障处理（或被忽略）"
+}
+func (r *ErrNoRowsUnhandledRule) GenerateSuggestion(node ast.Node) string {
+	return "用 errors.Is 区分没有数据的情况：\nerr := db.QueryRowContext(ctx, query, id).Scan(&user.Name)\nif errors.Is(err, sql.ErrNoRows) {\n    return nil, ErrUserNotFound\n}\nif err != nil {\n    return nil, fmt.Errorf(\"查询用户失败: %w\", err)\n}"
+}
+
+func (r *ErrNoRowsUnhandledRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	return matchSQLMisuse(node, ctx, "B122")
+}
+
+// 规则 23: 开启事务后没有 defer Rollback
+type TxNoDeferRollbackRule struct{}
+
+func (r *TxNoDeferRollbackRule) ID() string       { return "B123" }
+func (r *TxNoDeferRollbackRule) Name() string     { return "Transaction Without Deferred Rollback" }
+func (r *TxNoDeferRollbackRule) Severity() string { return "High" }
+func (r *TxNoDeferRollbackRule) Category() string { return CategoryResourceMgmt }
+func (r *TxNoDeferRollbackRule) Description() string {
+	return "Begin 开启事务后没有 defer tx.Rollback()，任何提前 return 或 panic 都会让事务一直持有连接和锁"
+}
+func (r *TxNoDeferRollbackRule) GenerateSuggestion(node ast.Node) string {
+	return "开启事务后立即 defer Rollback（Commit 之后再 Rollback 是无害的空操作）：\ntx, err := db.BeginTx(ctx, nil)\nif err != nil {\n    return err\n}\ndefer tx.Rollback()\n...\nreturn tx.Commit()"
+}
+
+func (r *TxNoDeferRollbackRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	return matchSQLMisuse(node, ctx, "B123")
+}
+
+// matchSQLMisuse 查表判断节点是否命中 database/sql 规则
+func matchSQLMisuse(node ast.Node, ctx *BugRuleContext, ruleID string) bool {
+	return matchFileFinding(node, ctx, "sql", findSQLMisuses, ruleID)
+}
+
+// findSQLMisuses 按函数检查 database/sql 的常见误用
+// 不依赖类型信息，按方法名识别：Query/QueryContext 返回 rows，Begin/BeginTx 返回 tx，QueryRow/QueryRowContext 返回 row；
+// 变量被返回或传给其他函数时由对方负责，不再报告
+func findSQLMisuses(file *ast.File) map[ast.Node][]string {
+	found := make(map[ast.Node][]string)
+	forEachFunc(file, func(ftype *ast.FuncType, body *ast.BlockStmt) {
+		mentionsErrNoRows := false
+		ast.Inspect(body, func(n ast.Node) bool {
+			if ident, ok := n.(*ast.Ident); ok && ident.Name == "ErrNoRows" {
+				mentionsErrNoRows = true
+			}
+			return !mentionsErrNoRows
+		})
+
+		inspectFuncBody(body, func(n ast.Node) {
+			switch node := n.(type) {
+			case *ast.AssignStmt:
+				if len(node.Lhs) != 2 || len(node.Rhs) != 1 {
+					return
+				}
+				obj := identObject(node.Lhs[0])
+				if obj == nil {
+					return
+				}
+				switch sqlMethod(node.Rhs[0]) {
+				case "Query", "QueryContext":
+					// 只检查用 Next 遍历的结果，排除其他同名的 Query 方法；是否 Close 由 findUnreleasedResources 检查（B120）
+					calls := methodCalls(body, obj)
+					if !calls["Next"] || escapes(body, obj) {
+						return
+					}
+					if !calls["Err"] {
+						found[node] = append(found[node], "B121")
+					}
+				case "Begin", "BeginTx":
+					if !escapes(body, obj) && !deferredCall(body, obj, "Rollback") {
+						found[node] = append(found[node], "B123")
+					}
+				}
+			}
+		})
+
+		if mentionsErrNoRows {
+			return
+		}
+		for _, scan := range unhandledScans(body) {
+			found[scan] = append(found[scan], "B122")
+		}
+	})
+	return found
+}
+
+// inspectFuncBody 遍历函数体，不进入闭包（闭包由 forEachFunc 单独处理）
+func inspectFuncBody(body *ast.BlockStmt, fn func(ast.Node)) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		if n != nil {
+			fn(n)
+		}
+		return true
+	})
+}
+
+// sqlMethod 返回 x.Method(...) 调用的方法名
+func sqlMethod(expr ast.Expr) string {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return ""
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	return sel.Sel.Name
+}
+
+// identObject 返回标识符解析到的对象（_ 和未解析的标识符返回 nil）
+func identObject(expr ast.Expr) *ast.Object {
+	ident, ok := expr.(*ast.Ident)
+	if !ok || ident.Name == "_" {
+		return nil
+	}
+	return ident.Obj
+}
+
+// methodCalls 收集对变量调用过的方法名（包括闭包和 defer 中的调用）
+func methodCalls(body *ast.BlockStmt, obj *ast.Object) map[string]bool {
+	calls := make(map[string]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && identObject(sel.X) == obj {
+				calls[sel.Sel.Name] = true
+			}
+		}
+		return true
+	})
+	return calls
+}
+
+// escapes 变量是否离开了本函数的控制：被返回、作为参数传递、赋值给其他变量或放入复合字面量
+// 只作为方法接收者或字段访问使用、以及被声明或重新赋值时不算
+func escapes(body *ast.BlockStmt, obj *ast.Object) bool {
+	allowed := make(map[*ast.Ident]bool)
+	escaped := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			if ident, ok := node.X.(*ast.Ident); ok {
+				allowed[ident] = true
+			}
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok {
+					allowed[ident] = true
+				}
+			}
+		case *ast.ValueSpec:
+			for _, name := range node.Names {
+				allowed[name] = true
+			}
+		case *ast.Ident:
+			if node.Obj == obj && !allowed[node] {
+				escaped = true
+			}
+		}
+		return !escaped
+	})
+	return escaped
+}
+
+// deferredCall 是否 defer 调用了变量的方法（直接 defer 或在 defer 的闭包中调用）
+func deferredCall(body *ast.BlockStmt, obj *ast.Object, method string) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		d, ok := n.(*ast.DeferStmt)
+		if !ok {
+			return !found
+		}
+		ast.Inspect(d.Call, func(m ast.Node) bool {
+			if sel, ok := m.(*ast.SelectorExpr); ok && sel.Sel.Name == method && identObject(sel.X) == obj {
+				found = true
+			}
+			return !found
+		})
+		return !found
+	})
+	return found
+}
+
+// unhandledScans 查找 QueryRow 结果的 Scan 调用中，错误被丢弃、或既没有返回也没有用 %w 包装的
+func unhandledScans(body *ast.BlockStmt) []*ast.CallExpr {
+	var scans []*ast.CallExpr
+	inspectFuncBody(body, func(n ast.Node) {
+		switch node := n.(type) {
+		case *ast.ExprStmt:
+			if scan := queryRowScan(node.X); scan != nil {
+				scans = append(scans, scan)
+			}
+		case *ast.AssignStmt:
+			if len(node.Lhs) != 1 || len(node.Rhs) != 1 {
+				return
+			}
+			scan := queryRowScan(node.Rhs[0])
+			if scan == nil {
+				return
+			}
+			obj := identObject(node.Lhs[0])
+			if obj == nil || !errPropagated(body, obj) {
+				scans = append(scans, scan)
+			}
+		}
+	})
+	return scans
+}
+
+// queryRowScan 表达式是否是 QueryRow 结果的 Scan 调用：db.QueryRow(...).Scan(...) 或 row.Scan(...)（row 来自 QueryRow）
+func queryRowScan(expr ast.Expr) *ast.CallExpr {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Scan" {
+		return nil
+	}
+	switch x := sel.X.(type) {
+	case *ast.CallExpr:
+		if isQueryRow(sqlMethod(x)) {
+			return call
+		}
+	case *ast.Ident:
+		if x.Obj == nil {
+			return nil
+		}
+		if assign, ok := x.Obj.Decl.(*ast.AssignStmt); ok && len(assign.Rhs) == 1 && isQueryRow(sqlMethod(assign.Rhs[0])) {
+			return call
+		}
+	}
+	return nil
+}
+
+// isQueryRow 方法名是否是 QueryRow/QueryRowContext
+func isQueryRow(method string) bool {
+	return method == "QueryRow" || method == "QueryRowContext"
+}
+
+// errPropagated 错误变量是否原样返回，或用 fmt.Errorf 的 %w 包装，由调用方判断 sql.ErrNoRows
+func errPropagated(body *ast.BlockStmt, obj *ast.Object) bool {
+	propagated := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ReturnStmt:
+			for _, result := range node.Results {
+				if identObject(result) == obj {
+					propagated = true
+				}
+			}
+		case *ast.CallExpr:
+			if !isErrorfCall(node) || len(node.Args) < 2 {
+				break
+			}
+			format, ok := node.Args[0].(*ast.BasicLit)
+			if !ok || format.Kind != token.STRING || !strings.Contains(format.Value, "%w") {
+				break
+			}
+			for _, arg := range node.Args[1:] {
+				if identObject(arg) == obj {
+					propagated = true
+				}
+			}
+		}
+		return !propagated
+	})
+	return propagated
+}
+
+// isErrorfCall 是否是 fmt.Errorf 调用
+func isErrorfCall(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Errorf" {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "fmt"
+}
@@ -0,0 +1,8 @@
+package tools
+
+import "testing"
+
+// 测试 database/sql 误用规则
+func TestBugDetector_SQLMisuse(t *testing.T) {
+	testRuleFixtures(t, "sql", ruleOptions{}, "B120", "B121", "B122", "B123")
+}
@@ -0,0 +1,274 @@
+package tools
+
+import (
+	"go/ast"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// tagKeys 检查的序列化标签
+var tagKeys = []string{"json", "yaml"}
+
+// tagCases 标签名命名风格
+var tagCases = map[string]*regexp.Regexp{
+	"snake":  regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`),
+	"camel":  regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`),
+	"pascal": regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`),
+	"kebab":  regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`),
+}
+
+// 规则 16: DTO 包中导出字段缺少 json 标签
+type MissingJSONTagRule struct{}
+
+func (r *MissingJSONTagRule) ID() string       { return "B116" }
+func (r *MissingJSONTagRule) Name() string     { return "Missing JSON Tag" }
+func (r *MissingJSONTagRule) Severity() string { return "Low" }
+func (r *MissingJSONTagRule) Category() string { return CategorySerialization }
+func (r *MissingJSONTagRule) Description() string {
+	return "API DTO 的导出字段没有 json 标签，序列化后的字段名随 Go 字段名变化，重命名字段会破坏接口兼容性"
+}
+func (r *MissingJSONTagRule) GenerateSuggestion(node ast.Node) string {
+	return "为每个导出字段写明 json 标签，不需要序列化的字段使用 \"-\"：\ntype UserResponse struct {\n    ID       int64  `json:\"id\"`\n    Name     string `json:\"name\"`\n    Password string `json:\"-\"`\n}"
+}
+
+func (r *MissingJSONTagRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	field, ok := node.(*ast.Field)
+	if !ok || ctx.File == nil || !isDTOFile(ctx) {
+		return false
+	}
+	info, ok := structTagInfo(ctx)[field]
+	if !ok || !info.exportedType || len(field.Names) == 0 || !hasExportedName(field) {
+		return false
+	}
+	_, tagged := fieldTag(field).Lookup("json")
+	return !tagged
+}
+
+// 规则 17: 同一结构体中序列化名重复
+type DuplicateTagNameRule struct{}
+
+func (r *DuplicateTagNameRule) ID() string       { return "B117" }
+func (r *DuplicateTagNameRule) Name() string     { return "Duplicate Tag Name" }
+func (r *DuplicateTagNameRule) Severity() string { return "High" }
+func (r *DuplicateTagNameRule) Category() string { return CategorySerialization }
+func (r *DuplicateTagNameRule) Description() string {
+	return "结构体中多个字段的 json/yaml 名相同，encoding/json 会静默忽略所有冲突字段，yaml 解析直接报错"
+}
+func (r *DuplicateTagNameRule) GenerateSuggestion(node ast.Node) string {
+	return "为每个字段使用不同的序列化名：\ntype Order struct {\n    ID       int64 `json:\"id\"`\n    OrderID  int64 `json:\"order_id\"` // 不要与 ID 一样写成 \"id\"\n}"
+}
+
+func (r *DuplicateTagNameRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	field, ok := node.(*ast.Field)
+	if !ok || ctx.File == nil {
+		return false
+	}
+	return structTagInfo(ctx)[field].duplicate
+}
+
+// 规则 18: 标签选项写错
+type TagOptionMistakeRule struct{}
+
+func (r *TagOptionMistakeRule) ID() string       { return "B118" }
+func (r *TagOptionMistakeRule) Name() string     { return "Struct Tag Option Mistake" }
+func (r *TagOptionMistakeRule) Severity() string { return "Medium" }
+func (r *TagOptionMistakeRule) Category() string { return CategorySerialization }
+func (r *TagOptionMistakeRule) Description() string {
+	return "json/yaml 标签写法有误：\"-,omitempty\" 会把字段序列化为名为 \"-\" 的键而不是忽略；omitempty 拼错或漏掉逗号不会生效；格式错误的标签被整体忽略"
+}
+func (r *TagOptionMistakeRule) GenerateSuggestion(node ast.Node) string {
+	return "忽略字段只写 \"-\"，选项写在逗号之后，冒号后不要有空格：\nSecret string `json:\"-\"`\nEmail  string `json:\"email,omitempty\"`\nPhone  string `json:\",omitempty\"` // 沿用字段名"
+}
+
+func (r *TagOptionMistakeRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	field, ok := node.(*ast.Field)
+	if !ok || field.Tag == nil || ctx.File == nil {
+		return false
+	}
+	if _, ok := structTagInfo(ctx)[field]; !ok {
+		return false
+	}
+	tag := fieldTag(field)
+	for _, key := range tagKeys {
+		value, ok := tag.Lookup(key)
+		if !ok {
+			// 标签中出现了 key 但无法解析（如 json: "name"、json:name）
+			if strings.Contains(string(tag), key+":") {
+				return true
+			}
+			continue
+		}
+		name, opts, hasOpts := strings.Cut(value, ",")
+		if name == "-" && hasOpts && opts != "" {
+			return true
+		}
+		if name == "omitempty" {
+			return true
+		}
+		for _, opt := range strings.Split(opts, ",") {
+			if opt != "omitempty" && strings.EqualFold(strings.ReplaceAll(opt, "_", ""), "omitempty") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// 规则 19: 标签名不符合配置的命名风格
+type TagNameCaseRule struct{}
+
+func (r *TagNameCaseRule) ID() string       { return "B119" }
+func (r *TagNameCaseRule) Name() string     { return "Tag Name Case Convention" }
+func (r *TagNameCaseRule) Severity() string { return "Low" }
+func (r *TagNameCaseRule) Category() string { return CategorySerialization }
+func (r *TagNameCaseRule) Description() string {
+	return "json/yaml 标签名不符合配置的命名风格，接口字段命名不一致"
+}
+func (r *TagNameCaseRule) GenerateSuggestion(node ast.Node) string {
+	return "按配置的命名风格（struct_tags.case）修改标签名，例如 snake：\nCreatedAt time.Time `json:\"created_at\"`"
+}
+
+func (r *TagNameCaseRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	field, ok := node.(*ast.Field)
+	if !ok || field.Tag == nil || ctx.File == nil {
+		return false
+	}
+	pattern := tagCases[ctx.Tags.Case]
+	if pattern == nil {
+		return false
+	}
+	if _, ok := structTagInfo(ctx)[field]; !ok {
+		return false
+	}
+	tag := fieldTag(field)
+	for _, key := range tagKeys {
+		value, ok := tag.Lookup(key)
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(value, ",")
+		if name != "" && name != "-" && !pattern.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// tagFieldInfo 结构体字段的标签分析结果
+type tagFieldInfo struct {
+	exportedType bool // 属于导出类型（包括其中嵌套的匿名结构体）
+	duplicate    bool // 与前面的字段序列化名重复
+}
+
+// structTagInfo 返回本文件所有结构体字段的标签分析结果，不在表中的 Field 不是结构体字段
+func structTagInfo(ctx *BugRuleContext) map[*ast.Field]tagFieldInfo {
+	return ctx.Memo("struct-tags", func() any { return analyzeStructTags(ctx.File) }).(map[*ast.Field]tagFieldInfo)
+}
+
+// analyzeStructTags 分析文件中所有结构体字段
+func analyzeStructTags(file *ast.File) map[*ast.Field]tagFieldInfo {
+	infos := make(map[*ast.Field]tagFieldInfo)
+	exported := make(map[*ast.StructType]bool)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			if ts, ok := spec.(*ast.TypeSpec); ok && ts.Name.IsExported() {
+				ast.Inspect(ts.Type, func(n ast.Node) bool {
+					if st, ok := n.(*ast.StructType); ok {
+						exported[st] = true
+					}
+					return true
+				})
+			}
+		}
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		st, ok := n.(*ast.StructType)
+		if !ok {
+			return true
+		}
+		seen := make(map[string]bool)
+		for _, field := range st.Fields.List {
+			info := tagFieldInfo{exportedType: exported[st]}
+			for _, key := range tagKeys {
+				for _, name := range serializedNames(field, key) {
+					if seen[key+":"+name] {
+						info.duplicate = true
+					}
+					seen[key+":"+name] = true
+				}
+			}
+			infos[field] = info
+		}
+		return true
+	})
+	return infos
+}
+
+// serializedNames 字段在 json/yaml 中的键名；嵌入字段、被忽略的字段和未导出字段没有键名
+func serializedNames(field *ast.Field, key string) []string {
+	value, tagged := fieldTag(field).Lookup(key)
+	name, _, _ := strings.Cut(value, ",")
+	if name == "-" && !strings.Contains(value, ",") {
+		return nil
+	}
+
+	var names []string
+	for _, ident := range field.Names {
+		switch {
+		case !ident.IsExported():
+		case tagged && name != "":
+			names = append(names, name)
+		case key == "yaml":
+			// yaml 默认使用小写的字段名
+			names = append(names, strings.ToLower(ident.Name))
+		default:
+			names = append(names, ident.Name)
+		}
+	}
+	return names
+}
+
+// fieldTag 解析字段的标签，没有标签或无法解析时返回空
+func fieldTag(field *ast.Field) reflect.StructTag {
+	if field.Tag == nil {
+		return ""
+	}
+	s, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(s)
+}
+
+// hasExportedName 字段是否有导出的名字
+func hasExportedName(field *ast.Field) bool {
+	for _, name := range field.Names {
+		if name.IsExported() {
+			return true
+		}
+	}
+	return false
+}
+
+// isDTOFile 当前文件是否属于配置的 API DTO 包（按包名或目录路径后缀匹配）
+func isDTOFile(ctx *BugRuleContext) bool {
+	dir := filepath.ToSlash(filepath.Dir(ctx.Filename))
+	for _, pkg := range ctx.Tags.DTOPackages {
+		pkg = strings.Trim(filepath.ToSlash(pkg), "/")
+		if pkg == "" {
+			continue
+		}
+		if pkg == ctx.File.Name.Name || dir == pkg || strings.HasSuffix(dir, "/"+pkg) {
+			return true
+		}
+	}
+	return false
+}
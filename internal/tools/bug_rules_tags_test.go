@@ -0,0 +1,47 @@
+package tools
+
+import (
+	"go-ai-study/internal/config"
+	"testing"
+)
+
+// 测试 DTO 包中缺少 json 标签（格式错误的标签等同于没有标签）
+func TestBugDetector_MissingJSONTag(t *testing.T) {
+	t.Run("包名匹配", func(t *testing.T) {
+		testRuleFixtures(t, "tags", ruleOptions{Tags: config.TagConfig{DTOPackages: []string{"dto"}}}, "B116")
+	})
+	t.Run("目录后缀匹配", func(t *testing.T) {
+		testRuleFixtures(t, "tags", ruleOptions{Tags: config.TagConfig{DTOPackages: []string{"rules/tags"}}}, "B116")
+	})
+	t.Run("不是 DTO 包", func(t *testing.T) {
+		testNoRuleFindings(t, "tags", ruleOptions{Tags: config.TagConfig{DTOPackages: []string{"model"}}}, "B116")
+	})
+	t.Run("未配置", func(t *testing.T) {
+		testNoRuleFindings(t, "tags", ruleOptions{}, "B116")
+	})
+}
+
+// 测试序列化名重复
+func TestBugDetector_DuplicateTagName(t *testing.T) {
+	testRuleFixtures(t, "tags", ruleOptions{}, "B117")
+}
+
+// 测试标签选项写错
+func TestBugDetector_TagOptionMistake(t *testing.T) {
+	testRuleFixtures(t, "tags", ruleOptions{}, "B118")
+}
+
+// 测试标签命名风格
+func TestBugDetector_TagNameCase(t *testing.T) {
+	testRuleFixtures(t, "tags", ruleOptions{Tags: config.TagConfig{Case: "snake"}}, "B119")
+	testNoRuleFindings(t, "tags", ruleOptions{Tags: config.TagConfig{Case: "camel"}}, "B119")
+	testNoRuleFindings(t, "tags", ruleOptions{}, "B119")
+}
+
+// 测试未知的命名风格
+func TestBugDetector_InvalidTagCase(t *testing.T) {
+	err := NewBugDetector().ValidateInput(BugDetectorInput{Code: "package p", Tags: config.TagConfig{Case: "upper"}})
+	if err == nil {
+		t.Error("未知的命名风格应该验证失败")
+	}
+}
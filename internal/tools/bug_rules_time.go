@@ -0,0 +1,296 @@
+package tools
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// timeUnits time 包中的 Duration 单位常量
+var timeUnits = map[string]bool{
+	"Nanosecond": true, "Microsecond": true, "Millisecond": true,
+	"Second": true, "Minute": true, "Hour": true,
+}
+
+// timeLayouts time 包中预定义的布局常量
+var timeLayouts = map[string]string{
+	"Layout":      "01/02 03:04:05PM '06 -0700",
+	"ANSIC":       "Mon Jan _2 15:04:05 2006",
+	"UnixDate":    "Mon Jan _2 15:04:05 MST 2006",
+	"RubyDate":    "Mon Jan 02 15:04:05 -0700 2006",
+	"RFC822":      "02 Jan 06 15:04 MST",
+	"RFC822Z":     "02 Jan 06 15:04 -0700",
+	"RFC850":      "Monday, 02-Jan-06 15:04:05 MST",
+	"RFC1123":     "Mon, 02 Jan 2006 15:04:05 MST",
+	"RFC1123Z":    "Mon, 02 Jan 2006 15:04:05 -0700",
+	"RFC3339":     "2006-01-02T15:04:05Z07:00",
+	"RFC3339Nano": "2006-01-02T15:04:05.999999999Z07:00",
+	"Kitchen":     "3:04PM",
+	"Stamp":       "Jan _2 15:04:05",
+	"StampMilli":  "Jan _2 15:04:05.000",
+	"StampMicro":  "Jan _2 15:04:05.000000",
+	"StampNano":   "Jan _2 15:04:05.000000000",
+	"DateTime":    "2006-01-02 15:04:05",
+	"DateOnly":    "2006-01-02",
+	"TimeOnly":    "15:04:05",
+}
+
+// 规则 13: 用墙上时钟的时间戳相减计算耗时
+type WallClockElapsedRule struct{}
+
+func (r *WallClockElapsedRule) ID() string       { return "B113" }
+func (r *WallClockElapsedRule) Name() string     { return "Wall Clock Elapsed Time" }
+func (r *WallClockElapsedRule) Severity() string { return "Medium" }
+func (r *WallClockElapsedRule) Category() string { return CategoryCorrectness }
+func (r *WallClockElapsedRule) Description() string {
+	return "用 time.Now().Unix() 等时间戳相减后与常量比较，丢弃了单调时钟，系统时间被调整（NTP、手动修改）时耗时会跳变甚至为负"
+}
+func (r *WallClockElapsedRule) GenerateSuggestion(node ast.Node) string {
+	return "保留 time.Time 并用 time.Since 计算耗时（使用单调时钟）：\nstart := time.Now()\n...\nif time.Since(start) > 30*time.Second {\n    ...\n}"
+}
+
+func (r *WallClockElapsedRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	cmp, ok := node.(*ast.BinaryExpr)
+	if !ok {
+		return false
+	}
+	switch cmp.Op {
+	case token.LSS, token.LEQ, token.GTR, token.GEQ:
+	default:
+		return false
+	}
+	return isWallClockElapsed(cmp.X) || isWallClockElapsed(cmp.Y)
+}
+
+// isWallClockElapsed 表达式是否形如 time.Now().Unix() - start
+func isWallClockElapsed(expr ast.Expr) bool {
+	sub, ok := ast.Unparen(expr).(*ast.BinaryExpr)
+	if !ok || sub.Op != token.SUB {
+		return false
+	}
+	call, ok := ast.Unparen(sub.X).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !strings.HasPrefix(sel.Sel.Name, "Unix") {
+		return false
+	}
+	now, ok := sel.X.(*ast.CallExpr)
+	return ok && isTimeFunc(now.Fun, "Now")
+}
+
+// 规则 14: time.Parse 解析不带时区偏移的时间
+type TimeParseZoneRule struct{}
+
+func (r *TimeParseZoneRule) ID() string       { return "B114" }
+func (r *TimeParseZoneRule) Name() string     { return "Time Parsed Without Zone Offset" }
+func (r *TimeParseZoneRule) Severity() string { return "Medium" }
+func (r *TimeParseZoneRule) Category() string { return CategoryCorrectness }
+func (r *TimeParseZoneRule) Description() string {
+	return "time.Parse 的布局包含时刻但没有数字时区偏移：不带时区时结果是 UTC 而不是本地时间，只有 MST 这类缩写时未知缩写的偏移按 0 处理"
+}
+func (r *TimeParseZoneRule) GenerateSuggestion(node ast.Node) string {
+	return "显式指定时区，或使用带数字偏移的布局：\nloc, err := time.LoadLocation(\"Asia/Shanghai\")\nif err != nil {\n    return err\n}\nt, err := time.ParseInLocation(\"2006-01-02 15:04:05\", s, loc)\n// 或者：time.Parse(time.RFC3339, s)"
+}
+
+func (r *TimeParseZoneRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	call, ok := node.(*ast.CallExpr)
+	if !ok || len(call.Args) != 2 || !isTimeFunc(call.Fun, "Parse") {
+		return false
+	}
+	layout, ok := timeLayout(call.Args[0])
+	if !ok {
+		return false
+	}
+	// 只有日期的布局按 UTC 解析通常符合预期
+	if !strings.Contains(layout, "15") && !strings.Contains(layout, "03") && !strings.Contains(layout, "3:") {
+		return false
+	}
+	return !strings.Contains(layout, "-07") && !strings.Contains(layout, "Z07")
+}
+
+// timeLayout 解析 time.Parse 的布局参数：字符串字面量或 time 包预定义常量
+func timeLayout(expr ast.Expr) (string, bool) {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		s, err := strconv.Unquote(e.Value)
+		return s, err == nil
+	case *ast.SelectorExpr:
+		if pkg, ok := e.X.(*ast.Ident); ok && pkg.Name == "time" && pkg.Obj == nil {
+			layout, ok := timeLayouts[e.Sel.Name]
+			return layout, ok
+		}
+	}
+	return "", false
+}
+
+// 规则 15: 已经是 Duration 的值再乘以时间单位
+type DurationDoubleUnitRule struct{}
+
+func (r *DurationDoubleUnitRule) ID() string       { return "B115" }
+func (r *DurationDoubleUnitRule) Name() string     { return "Duration Multiplied By Unit" }
+func (r *DurationDoubleUnitRule) Severity() string { return "High" }
+func (r *DurationDoubleUnitRule) Category() string { return CategoryCorrectness }
+func (r *DurationDoubleUnitRule) Description() string {
+	return "time.Duration 类型的值再乘以 time.Second 等单位，结果放大了 10^9 倍（5s 变成约 158 年）"
+}
+func (r *DurationDoubleUnitRule) GenerateSuggestion(node ast.Node) string {
+	return "Duration 已经带有单位，直接使用；只有整数才需要乘以单位：\nfunc wait(timeout time.Duration) {\n    ctx, cancel := context.WithTimeout(ctx, timeout) // 不要写 timeout * time.Second\n}\n\nseconds := 5\nd := time.Duration(seconds) * time.Second"
+}
+
+func (r *DurationDoubleUnitRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	mul, ok := node.(*ast.BinaryExpr)
+	if !ok || mul.Op != token.MUL || ctx.File == nil {
+		return false
+	}
+	var operand ast.Expr
+	switch {
+	case isTimeUnit(mul.Y):
+		operand = mul.X
+	case isTimeUnit(mul.X):
+		operand = mul.Y
+	default:
+		return false
+	}
+	fields := ctx.Memo("duration-fields", func() any { return durationFields(ctx.File) }).(map[string]bool)
+	return isDurationExpr(operand, fields, 0)
+}
+
+// 规则 31: time.NewTicker 创建后没有 Stop
+type TickerNotStoppedRule struct{}
+
+func (r *TickerNotStoppedRule) ID() string       { return "B131" }
+func (r *TickerNotStoppedRule) Name() string     { return "Ticker Not Stopped" }
+func (r *TickerNotStoppedRule) Severity() string { return "Medium" }
+func (r *TickerNotStoppedRule) Category() string { return CategoryResourceMgmt }
+func (r *TickerNotStoppedRule) Description() string {
+	return "time.NewTicker 创建的 Ticker 在函数退出的某条路径上没有 Stop，go 1.23 之前它永远不会被回收，每个周期继续触发"
+}
+func (r *TickerNotStoppedRule) GenerateSuggestion(node ast.Node) string {
+	return "创建后立即 defer Stop：\nticker := time.NewTicker(interval)\ndefer ticker.Stop()\nfor {\n    select {\n    case <-ctx.Done():\n        return\n    case <-ticker.C:\n        poll()\n    }\n}"
+}
+
+func (r *TickerNotStoppedRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	return matchUnreleased(node, ctx, "B131")
+}
+
+// isTimeFunc fun 是否是 time.<name>
+func isTimeFunc(fun ast.Expr, name string) bool {
+	sel, ok := ast.Unparen(fun).(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "time" && pkg.Obj == nil
+}
+
+// isTimeUnit 表达式是否是 time.Second 等单位常量
+func isTimeUnit(expr ast.Expr) bool {
+	sel, ok := ast.Unparen(expr).(*ast.SelectorExpr)
+	return ok && timeUnits[sel.Sel.Name] && isTimeFunc(sel, sel.Sel.Name)
+}
+
+// isDurationType 类型表达式是否是 time.Duration
+func isDurationType(expr ast.Expr) bool {
+	return expr != nil && isTimeFunc(expr, "Duration")
+}
+
+// durationFields 本文件结构体中类型为 time.Duration 的字段名
+// 同名字段在不同结构体中类型不一致时不记录，避免误报
+func durationFields(file *ast.File) map[string]bool {
+	fields := make(map[string]bool)
+	conflict := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		st, ok := n.(*ast.StructType)
+		if !ok {
+			return true
+		}
+		for _, field := range st.Fields.List {
+			for _, name := range field.Names {
+				if isDurationType(field.Type) {
+					fields[name.Name] = true
+				} else {
+					conflict[name.Name] = true
+				}
+			}
+		}
+		return true
+	})
+	for name := range conflict {
+		delete(fields, name)
+	}
+	return fields
+}
+
+// isDurationExpr 表达式的值是否已经是带单位的 Duration
+// 不依赖类型信息：识别单位常量、time.Since/Until、声明为 time.Duration 的变量和字段；
+// 乘以单位之前的 time.Duration(n) 只是计数，不算带单位
+func isDurationExpr(expr ast.Expr, fields map[string]bool, depth int) bool {
+	if depth > 5 {
+		return false
+	}
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.SelectorExpr:
+		if isTimeUnit(e) {
+			return true
+		}
+		if _, ok := e.X.(*ast.Ident); ok && isTimeFunc(e, e.Sel.Name) {
+			return false
+		}
+		return fields[e.Sel.Name]
+	case *ast.CallExpr:
+		// time.Duration(n) 只是类型转换，n 本身带单位时结果才带单位
+		if isTimeFunc(e.Fun, "Duration") && len(e.Args) == 1 {
+			return isDurationExpr(e.Args[0], fields, depth+1)
+		}
+		return isTimeFunc(e.Fun, "Since") || isTimeFunc(e.Fun, "Until")
+	case *ast.BinaryExpr:
+		x, y := isDurationExpr(e.X, fields, depth+1), isDurationExpr(e.Y, fields, depth+1)
+		switch e.Op {
+		case token.ADD, token.SUB, token.MUL:
+			return x || y
+		case token.QUO:
+			// d / time.Millisecond 得到的是计数
+			return x && !y
+		}
+	case *ast.Ident:
+		return e.Obj != nil && isDurationDecl(e, fields, depth)
+	}
+	return false
+}
+
+// isDurationDecl 根据标识符的声明判断变量是否是 Duration
+func isDurationDecl(ident *ast.Ident, fields map[string]bool, depth int) bool {
+	switch decl := ident.Obj.Decl.(type) {
+	case *ast.Field:
+		return isDurationType(decl.Type)
+	case *ast.ValueSpec:
+		if decl.Type != nil {
+			return isDurationType(decl.Type)
+		}
+		for i, name := range decl.Names {
+			if name.Obj == ident.Obj && i < len(decl.Values) {
+				return isDurationExpr(decl.Values[i], fields, depth+1)
+			}
+		}
+	case *ast.AssignStmt:
+		for i, lhs := range decl.Lhs {
+			if name, ok := lhs.(*ast.Ident); !ok || name.Obj != ident.Obj {
+				continue
+			}
+			if len(decl.Rhs) == len(decl.Lhs) {
+				return isDurationExpr(decl.Rhs[i], fields, depth+1)
+			}
+			// d, err := time.ParseDuration(s)
+			if call, ok := decl.Rhs[0].(*ast.CallExpr); ok && i == 0 {
+				return isTimeFunc(call.Fun, "ParseDuration")
+			}
+		}
+	}
+	return false
+}
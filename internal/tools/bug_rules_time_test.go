@@ -0,0 +1,18 @@
+package tools
+
+import "testing"
+
+// 测试用时间戳相减计算耗时
+func TestBugDetector_WallClockElapsed(t *testing.T) {
+	testRuleFixtures(t, "time", ruleOptions{}, "B113")
+}
+
+// 测试 time.Parse 不带时区偏移
+func TestBugDetector_TimeParseZone(t *testing.T) {
+	testRuleFixtures(t, "time", ruleOptions{}, "B114")
+}
+
+// 测试 Duration 再乘以时间单位
+func TestBugDetector_DurationDoubleUnit(t *testing.T) {
+	testRuleFixtures(t, "time", ruleOptions{}, "B115")
+}
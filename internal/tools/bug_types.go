@@ -0,0 +1,160 @@
+package tools
+
+import (
+	"context"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// typedFile 经由 go/packages 加载、带类型信息的文件
+// 语法树和位置信息来自加载时的解析，规则据此查询表达式的真实类型
+type typedFile struct {
+	FSet   *token.FileSet
+	File   *ast.File
+	Info   *types.Info
+	Pkg    *types.Package
+	Syntax []*ast.File // 同一个包的所有文件，构建 SSA 时使用
+
+	nilness *nilnessCache // 同一个包的文件共用，包有类型错误时为 nil（不构建 SSA）
+}
+
+// loadTypedFiles 用 go/packages 加载文件所在的包，返回按绝对路径索引的类型信息
+// 指定目录时加载目录下的 ./...，否则按文件所在目录逐个加载。加载失败（没有 go.mod、go 命令不可用）
+// 或不属于任何包的文件（如 _test.go）不在结果中，由调用方回退为只按语法检测
+func loadTypedFiles(ctx context.Context, dir string, files []string) map[string]*typedFile {
+	var pkgs []*packages.Package
+	if dir != "" {
+		pkgs, _ = loadTypedPackages(ctx, dir, nil)
+	} else {
+		var dirs []string
+		for _, file := range files {
+			if d := filepath.Dir(file); !slices.Contains(dirs, d) {
+				dirs = append(dirs, d)
+			}
+		}
+		for _, d := range dirs {
+			loaded, err := loadTypedPackages(ctx, d, []string{"."})
+			if err == nil {
+				pkgs = append(pkgs, loaded...)
+			}
+		}
+	}
+
+	typed := make(map[string]*typedFile)
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		var nilness *nilnessCache
+		if !pkg.IllTyped {
+			nilness = &nilnessCache{}
+		}
+		for _, file := range pkg.Syntax {
+			name := pkg.Fset.File(file.Pos()).Name()
+			typed[filepath.Clean(name)] = &typedFile{
+				FSet:    pkg.Fset,
+				File:    file,
+				Info:    pkg.TypesInfo,
+				Pkg:     pkg.Types,
+				Syntax:  pkg.Syntax,
+				nilness: nilness,
+			}
+		}
+	}
+	return typed
+}
+
+// lookupTypedFile 按路径查找加载到的类型信息
+func lookupTypedFile(typed map[string]*typedFile, file string) *typedFile {
+	if len(typed) == 0 {
+		return nil
+	}
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil
+	}
+	return typed[abs]
+}
+
+// Typed 是否有类型信息（BugDetectorInput.TypeCheck 且文件所在的包加载成功）
+// ctx 为 nil（如修复器直接调用 Match）时按没有类型信息处理
+func (ctx *BugRuleContext) Typed() bool {
+	return ctx != nil && ctx.Types != nil
+}
+
+// TypeOf 表达式的类型，没有类型信息时为 nil
+func (ctx *BugRuleContext) TypeOf(expr ast.Expr) types.Type {
+	if !ctx.Typed() {
+		return nil
+	}
+	return ctx.Types.TypeOf(expr)
+}
+
+// Callee 调用的函数或方法，没有类型信息、调用函数值或内置函数时为 nil
+func (ctx *BugRuleContext) Callee(call *ast.CallExpr) *types.Func {
+	if !ctx.Typed() {
+		return nil
+	}
+	var ident *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return nil
+	}
+	fn, _ := ctx.Types.Uses[ident].(*types.Func)
+	return fn
+}
+
+// errorType 内置的 error 接口
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+// isErrorType 类型是否实现 error
+func isErrorType(t types.Type) bool {
+	return t != nil && types.Implements(t, errorType)
+}
+
+// isPackageFunc 被调用的是否是 pkgPath 包中名为 names 之一的函数
+func isPackageFunc(fn *types.Func, pkgPath string, names ...string) bool {
+	if fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != pkgPath {
+		return false
+	}
+	if sig, ok := fn.Type().(*types.Signature); ok && sig.Recv() != nil {
+		return false
+	}
+	return slices.Contains(names, fn.Name())
+}
+
+// ignoresErrorResult 赋值语句是否用 _ 丢弃了 error 类型的返回值（按类型信息判断）
+func ignoresErrorResult(assign *ast.AssignStmt, ctx *BugRuleContext) bool {
+	if len(assign.Rhs) == 1 && len(assign.Lhs) > 1 {
+		// a, _ := f()：按位置对应多返回值
+		tuple, ok := ctx.TypeOf(assign.Rhs[0]).(*types.Tuple)
+		if !ok || tuple.Len() != len(assign.Lhs) {
+			return false
+		}
+		for i, lhs := range assign.Lhs {
+			if ident, ok := lhs.(*ast.Ident); ok && ident.Name == "_" && isErrorType(tuple.At(i).Type()) {
+				return true
+			}
+		}
+		return false
+	}
+	for i, lhs := range assign.Lhs {
+		ident, ok := lhs.(*ast.Ident)
+		if !ok || ident.Name != "_" || i >= len(assign.Rhs) {
+			continue
+		}
+		if _, ok := assign.Rhs[i].(*ast.CallExpr); ok && isErrorType(ctx.TypeOf(assign.Rhs[i])) {
+			return true
+		}
+	}
+	return false
+}
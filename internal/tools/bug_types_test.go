@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 测试带类型信息的检测：按真实的返回值类型判断 B101，B104 用加载的包构建 SSA
+func TestBugDetector_TypeCheck(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/typed\n\ngo 1.21\n",
+		"main.go": `package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+type Counter struct{ n int }
+
+func (c Counter) Value() int { return c.n }
+
+func run() {
+	n, _ := strconv.Atoi("1") // B101：只有类型信息才知道丢弃的是 error
+	_ = os.Getpid()           // 返回 int，不是 error
+	var c Counter
+	fmt.Println(n, c.Value()) // 包函数调用、值类型接收者
+	var p *Counter
+	_ = p.Value() // B104
+}
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	detect := func(typeCheck bool) (BugResult, map[string][]int) {
+		t.Helper()
+		out, err := NewBugDetector().Run(context.Background(), BugDetectorInput{Directory: dir, TypeCheck: typeCheck})
+		if err != nil {
+			t.Fatalf("检测失败: %v", err)
+		}
+		var result BugResult
+		if err := json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatal(err)
+		}
+		lines := make(map[string][]int)
+		for _, bug := range result.Bugs {
+			lines[bug.RuleID] = append(lines[bug.RuleID], bug.Line)
+		}
+		return result, lines
+	}
+
+	syntactic, before := detect(false)
+	if syntactic.TypeChecked != 0 {
+		t.Errorf("没有开启 TypeCheck 时 type_checked_files = %d", syntactic.TypeChecked)
+	}
+	if !containsLine(before["B101"], 15) || containsLine(before["B101"], 14) {
+		t.Fatalf("按语法检测的基线变化了: %v", before)
+	}
+	// 文件只导入标准库，没有加载包时 B104 也能单独对文件做类型检查
+	if got := before["B104"]; len(got) != 1 || got[0] != 19 {
+		t.Errorf("未开启 TypeCheck 时 B104 = %v, want [19]", got)
+	}
+
+	typed, after := detect(true)
+	if typed.TypeChecked != 1 {
+		t.Fatalf("type_checked_files = %d, want 1（go/packages 加载失败？）", typed.TypeChecked)
+	}
+	if got := after["B101"]; len(got) != 1 || got[0] != 14 {
+		t.Errorf("B101 = %v, want [14]", got)
+	}
+	if got := after["B104"]; len(got) != 1 || got[0] != 19 {
+		t.Errorf("B104 = %v, want [19]", got)
+	}
+	for _, bug := range typed.Bugs {
+		if bug.File == "" || !strings.HasSuffix(bug.File, "main.go") || bug.CodeSnippet == "" {
+			t.Errorf("%s 的位置或代码片段缺失: %+v", bug.RuleID, bug)
+		}
+	}
+}
+
+// containsLine 行号列表中是否有 line
+func containsLine(lines []int, line int) bool {
+	for _, l := range lines {
+		if l == line {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,279 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CallGraph 基于 AST 的轻量级调用图
+// 不依赖类型信息，方法调用通过接收者名和方法名唯一性做近似解析
+type CallGraph struct {
+	Nodes map[string]*CallNode
+}
+
+// CallNode 调用图中的函数节点
+type CallNode struct {
+	Key      string   // 唯一标识，如 "ai.SourceInsightEngine.Ask"
+	Package  string   // 包名
+	Receiver string   // 接收者类型（仅方法）
+	Name     string   // 函数名
+	File     string   // 所在文件
+	Line     int      // 起始行号
+	Source   string   // 函数源码
+	Calls    []string // 调用的函数 Key（按出现顺序去重）
+}
+
+// CallEdge 调用边
+type CallEdge struct {
+	From  string
+	To    string
+	Depth int // 相对入口的深度（从 1 开始）
+}
+
+// callGraphFunc 解析阶段暂存的函数声明
+type callGraphFunc struct {
+	node    *CallNode
+	decl    *ast.FuncDecl
+	imports map[string]string // 导入名 -> 包名
+	recv    string            // 接收者变量名
+}
+
+// BuildCallGraph 扫描目录构建调用图（跳过隐藏目录、vendor 和 _test.go）
+func BuildCallGraph(ctx context.Context, dir string) (*CallGraph, error) {
+	cg := &CallGraph{Nodes: make(map[string]*CallNode)}
+	var funcs []*callGraphFunc
+
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			base := filepath.Base(p)
+			if p != dir && (strings.HasPrefix(base, ".") || base == "vendor" || base == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(p, ".go") || strings.HasSuffix(p, "_test.go") {
+			return nil
+		}
+		if skip, err := ScanFile(ctx, info); err != nil || skip {
+			return err
+		}
+
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return nil
+		}
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, p, content, 0)
+		if err != nil {
+			return nil // 解析失败的文件不参与调用图
+		}
+		funcs = append(funcs, cg.collectFuncs(fset, file, content, filepath.ToSlash(p))...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	methodIndex := cg.methodIndex()
+	for _, fn := range funcs {
+		cg.resolveCalls(fn, methodIndex)
+	}
+	return cg, nil
+}
+
+// collectFuncs 收集单个文件中的函数声明
+func (cg *CallGraph) collectFuncs(fset *token.FileSet, file *ast.File, content []byte, filename string) []*callGraphFunc {
+	pkg := file.Name.Name
+
+	imports := make(map[string]string)
+	for _, imp := range file.Imports {
+		importPath, _ := strconv.Unquote(imp.Path.Value)
+		name := path.Base(importPath)
+		if imp.Name != nil {
+			if imp.Name.Name == "_" || imp.Name.Name == "." {
+				continue
+			}
+			imports[imp.Name.Name] = name
+		} else {
+			imports[name] = name
+		}
+	}
+
+	var funcs []*callGraphFunc
+	for _, decl := range file.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+
+		node := &CallNode{
+			Package: pkg,
+			Name:    fd.Name.Name,
+			File:    filename,
+			Line:    fset.Position(fd.Pos()).Line,
+		}
+		fn := &callGraphFunc{node: node, decl: fd, imports: imports}
+
+		if fd.Recv != nil && len(fd.Recv.List) > 0 {
+			field := fd.Recv.List[0]
+			node.Receiver = receiverTypeName(field.Type)
+			if len(field.Names) > 0 {
+				fn.recv = field.Names[0].Name
+			}
+			node.Key = pkg + "." + node.Receiver + "." + node.Name
+		} else {
+			node.Key = pkg + "." + node.Name
+		}
+
+		start := fset.Position(fd.Pos()).Offset
+		end := fset.Position(fd.End()).Offset
+		if start >= 0 && end <= len(content) && start < end {
+			node.Source = string(content[start:end])
+		}
+
+		// 同名函数（如不同目录下的 main）保留第一个
+		if _, exists := cg.Nodes[node.Key]; exists {
+			continue
+		}
+		cg.Nodes[node.Key] = node
+		funcs = append(funcs, fn)
+	}
+	return funcs
+}
+
+// methodIndex 方法名 -> 所有同名方法的 Key
+func (cg *CallGraph) methodIndex() map[string][]string {
+	index := make(map[string][]string)
+	for key, node := range cg.Nodes {
+		if node.Receiver != "" {
+			index[node.Name] = append(index[node.Name], key)
+		}
+	}
+	return index
+}
+
+// resolveCalls 解析函数体内的调用
+func (cg *CallGraph) resolveCalls(fn *callGraphFunc, methodIndex map[string][]string) {
+	if fn.decl.Body == nil {
+		return
+	}
+
+	seen := make(map[string]bool)
+	ast.Inspect(fn.decl.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		key := cg.resolveCall(fn, call, methodIndex)
+		if key != "" && key != fn.node.Key && !seen[key] {
+			seen[key] = true
+			fn.node.Calls = append(fn.node.Calls, key)
+		}
+		return true
+	})
+}
+
+// resolveCall 解析单个调用表达式，无法解析时返回空串
+func (cg *CallGraph) resolveCall(fn *callGraphFunc, call *ast.CallExpr, methodIndex map[string][]string) string {
+	switch f := call.Fun.(type) {
+	case *ast.Ident:
+		key := fn.node.Package + "." + f.Name
+		if _, ok := cg.Nodes[key]; ok {
+			return key
+		}
+	case *ast.SelectorExpr:
+		if ident, ok := f.X.(*ast.Ident); ok {
+			// 包级函数调用：pkg.Func()
+			if pkg, ok := fn.imports[ident.Name]; ok {
+				key := pkg + "." + f.Sel.Name
+				if _, ok := cg.Nodes[key]; ok {
+					return key
+				}
+				return ""
+			}
+			// 接收者自身的方法调用：e.method()
+			if fn.recv != "" && ident.Name == fn.recv {
+				key := fn.node.Package + "." + fn.node.Receiver + "." + f.Sel.Name
+				if _, ok := cg.Nodes[key]; ok {
+					return key
+				}
+			}
+		}
+		// 其他方法调用：只有方法名唯一时才解析
+		if keys := methodIndex[f.Sel.Name]; len(keys) == 1 {
+			return keys[0]
+		}
+	}
+	return ""
+}
+
+// Find 按名称查找函数节点
+// 支持完整 Key、"Type.Method"、"pkg.Func" 或单独的函数名
+func (cg *CallGraph) Find(name string) []*CallNode {
+	if node, ok := cg.Nodes[name]; ok {
+		return []*CallNode{node}
+	}
+
+	var matches []*CallNode
+	for key, node := range cg.Nodes {
+		if strings.HasSuffix(key, "."+name) {
+			matches = append(matches, node)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Key < matches[j].Key })
+	return matches
+}
+
+// Walk 从入口开始深度优先遍历调用边（按调用顺序，每个节点只展开一次）
+func (cg *CallGraph) Walk(entry string, maxDepth int) []CallEdge {
+	var edges []CallEdge
+	expanded := make(map[string]bool)
+
+	var visit func(key string, depth int)
+	visit = func(key string, depth int) {
+		if expanded[key] || depth > maxDepth {
+			return
+		}
+		expanded[key] = true
+
+		node, ok := cg.Nodes[key]
+		if !ok {
+			return
+		}
+		for _, callee := range node.Calls {
+			edges = append(edges, CallEdge{From: key, To: callee, Depth: depth})
+			visit(callee, depth+1)
+		}
+	}
+	visit(entry, 1)
+	return edges
+}
+
+// receiverTypeName 提取接收者类型名（去掉指针和泛型参数）
+func receiverTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(t.X)
+	case *ast.Ident:
+		return t.Name
+	case *ast.IndexExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(t.X)
+	default:
+		return fmt.Sprintf("%T", expr)
+	}
+}
@@ -0,0 +1,142 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 所有分析器共用的问题类别，内置规则的 Category() 返回其中之一
+// 跨工具按类别过滤（--category）和统计（statistics.by_category）都以此为准
+const (
+	CategoryErrorHandling   = "ErrorHandling"   // 错误被忽略、没有包装或没有区分
+	CategoryConcurrency     = "Concurrency"     // goroutine、channel、锁和 context 传递
+	CategoryInjection       = "Injection"       // SQL、命令等注入
+	CategoryCrypto          = "Crypto"          // 弱加密算法和不安全的随机数
+	CategorySecrets         = "Secrets"         // 硬编码的密码和密钥
+	CategoryResourceMgmt    = "ResourceMgmt"    // 文件、连接、事务等资源的释放
+	CategoryNullSafety      = "NullSafety"      // nil 指针、nil map 和 nil channel
+	CategoryCorrectness     = "Correctness"     // 无效赋值、时间计算等逻辑错误
+	CategorySerialization   = "Serialization"   // 结构体标签和编解码
+	CategoryPerformance     = "Performance"     // 不必要的开销
+	CategoryDataExposure    = "DataExposure"    // 敏感信息泄露
+	CategoryPermissions     = "Permissions"     // 文件权限
+	CategoryNetwork         = "Network"         // 不安全的网络通信
+	CategoryMaintainability = "Maintainability" // 不影响当前行为、但容易在修改时出错的写法
+	CategoryCustom          = "Custom"          // 自定义规则没有指定类别时的默认值
+)
+
+// Categories 所有类别，按帮助信息中的显示顺序
+var Categories = []string{
+	CategoryErrorHandling, CategoryConcurrency, CategoryInjection, CategoryCrypto, CategorySecrets,
+	CategoryResourceMgmt, CategoryNullSafety, CategoryCorrectness, CategorySerialization, CategoryPerformance,
+	CategoryDataExposure, CategoryPermissions, CategoryNetwork, CategoryMaintainability, CategoryCustom,
+}
+
+// categoryAliases 旧版本规则使用的类别名，自定义规则和插件中仍可能出现
+var categoryAliases = map[string]string{
+	"error handling":      CategoryErrorHandling,
+	"resource management": CategoryResourceMgmt,
+	"cryptography":        CategoryCrypto,
+	"credentials":         CategorySecrets,
+	"data privacy":        CategoryDataExposure,
+	"file system":         CategoryPermissions,
+	"network security":    CategoryNetwork,
+	"null safety":         CategoryNullSafety,
+	"logic":               CategoryCorrectness,
+	"time":                CategoryCorrectness,
+	"control flow":        CategoryMaintainability,
+}
+
+// NormalizeCategory 把类别名转换为统一的写法：不区分大小写，忽略空格、下划线和连字符，并识别旧版本的类别名
+// 不在分类中的类别（如插件自定义的类别）原样返回
+func NormalizeCategory(name string) string {
+	name = strings.TrimSpace(name)
+	if alias, ok := categoryAliases[strings.ToLower(name)]; ok {
+		return alias
+	}
+	key := categoryKey(name)
+	for _, c := range Categories {
+		if categoryKey(c) == key {
+			return c
+		}
+	}
+	return name
+}
+
+// categoryKey 比较类别名时使用的形式
+func categoryKey(name string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "_", "", "-", "").Replace(name))
+}
+
+// ParseCategories 解析逗号分隔的类别列表（--category），未知的类别返回 ErrInvalidInput
+func ParseCategories(value string) ([]string, error) {
+	var categories []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			categories = append(categories, part)
+		}
+	}
+	if err := validateCategories(categories); err != nil {
+		return nil, err
+	}
+	for i, c := range categories {
+		categories[i] = NormalizeCategory(c)
+	}
+	return categories, nil
+}
+
+// validateCategories 检查类别是否都在分类中
+func validateCategories(categories []string) error {
+	for _, c := range categories {
+		normalized := NormalizeCategory(c)
+		if !containsCategory(Categories, normalized) {
+			return fmt.Errorf("%w: 未知的问题类别 %q（可选 %s）", ErrInvalidInput, c, strings.Join(Categories, "、"))
+		}
+	}
+	return nil
+}
+
+// containsCategory 类别是否在列表中；列表为空表示不过滤，总是返回 true
+func containsCategory(categories []string, category string) bool {
+	if len(categories) == 0 {
+		return true
+	}
+	category = NormalizeCategory(category)
+	for _, c := range categories {
+		if NormalizeCategory(c) == category {
+			return true
+		}
+	}
+	return false
+}
+
+// filterByCategory 只保留属于指定类别的问题，categories 为空时原样返回
+func filterByCategory[T any](items []T, categories []string, category func(T) string) []T {
+	if len(categories) == 0 {
+		return items
+	}
+	kept := items[:0:0]
+	for _, item := range items {
+		if containsCategory(categories, category(item)) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// FilterFindingsByCategory 只保留属于指定类别的问题（跨工具的统一问题列表）
+func FilterFindingsByCategory(findings []Finding, categories []string) []Finding {
+	return filterByCategory(findings, categories, func(f Finding) string { return f.Category })
+}
+
+// countByCategory 按类别统计问题数，没有问题时返回 nil
+func countByCategory[T any](items []T, category func(T) string) map[string]int {
+	if len(items) == 0 {
+		return nil
+	}
+	counts := make(map[string]int)
+	for _, item := range items {
+		counts[category(item)]++
+	}
+	return counts
+}
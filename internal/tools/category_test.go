@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// 测试类别名的归一化：不区分大小写和分隔符，识别旧版本的类别名，未知类别原样保留
+func TestNormalizeCategory(t *testing.T) {
+	cases := map[string]string{
+		"concurrency":         CategoryConcurrency,
+		"error_handling":      CategoryErrorHandling,
+		"Resource Management": CategoryResourceMgmt,
+		"Cryptography":        CategoryCrypto,
+		"Credentials":         CategorySecrets,
+		" resource-mgmt ":     CategoryResourceMgmt,
+		"Policy":              "Policy",
+	}
+	for in, want := range cases {
+		if got := NormalizeCategory(in); got != want {
+			t.Errorf("NormalizeCategory(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// 测试 --category 的解析
+func TestParseCategories(t *testing.T) {
+	got, err := ParseCategories("concurrency, Injection,,")
+	if err != nil || len(got) != 2 || got[0] != CategoryConcurrency || got[1] != CategoryInjection {
+		t.Errorf("ParseCategories = %v, %v", got, err)
+	}
+	if got, err := ParseCategories(""); err != nil || got != nil {
+		t.Errorf("空值应该不过滤: %v, %v", got, err)
+	}
+	if _, err := ParseCategories("Concurrency,Threads"); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("未知的类别应该返回 ErrInvalidInput，实际 %v", err)
+	}
+}
+
+// 测试所有内置规则都映射到统一的类别
+func TestBuiltinRuleCategories(t *testing.T) {
+	bugEngine := NewBugRuleEngine()
+	bugEngine.RegisterAllRules()
+	for _, rule := range bugEngine.Rules {
+		if !containsCategory(Categories, rule.Category()) || rule.Category() != NormalizeCategory(rule.Category()) {
+			t.Errorf("规则 %s 的类别 %q 不在分类中", rule.ID(), rule.Category())
+		}
+	}
+	secEngine := NewRuleEngine()
+	secEngine.RegisterAllRules()
+	for _, rule := range secEngine.Rules {
+		if !containsCategory(Categories, rule.Category()) || rule.Category() != NormalizeCategory(rule.Category()) {
+			t.Errorf("规则 %s 的类别 %q 不在分类中", rule.ID(), rule.Category())
+		}
+	}
+}
+
+// 测试按类别过滤问题和按类别统计
+func TestBugDetector_Categories(t *testing.T) {
+	code := `package main
+
+import "os"
+
+func main() {
+	_ = os.Remove("a.txt")
+	var ch chan int
+	ch <- 1
+}
+`
+	detector := NewBugDetector()
+	all, err := detector.Execute(context.Background(), BugDetectorInput{Code: code})
+	if err != nil {
+		t.Fatalf("检测失败: %v", err)
+	}
+	if all.Statistics.ByCategory[CategoryErrorHandling] == 0 || all.Statistics.ByCategory[CategoryConcurrency] == 0 {
+		t.Fatalf("按类别统计错误: %v", all.Statistics.ByCategory)
+	}
+
+	filtered, err := detector.Execute(context.Background(), BugDetectorInput{Code: code, Categories: []string{"concurrency"}})
+	if err != nil {
+		t.Fatalf("检测失败: %v", err)
+	}
+	if filtered.Total == 0 || filtered.Total != all.Statistics.ByCategory[CategoryConcurrency] {
+		t.Fatalf("过滤后的问题数 = %d, want %d", filtered.Total, all.Statistics.ByCategory[CategoryConcurrency])
+	}
+	for _, bug := range filtered.Bugs {
+		if bug.Category != CategoryConcurrency {
+			t.Errorf("过滤后仍有 %s 类问题: %s", bug.Category, bug.RuleID)
+		}
+	}
+
+	if err := detector.ValidateInput(BugDetectorInput{Code: code, Categories: []string{"Threads"}}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("未知的类别应该返回 ErrInvalidInput，实际 %v", err)
+	}
+}
+
+// 测试跨工具的统一问题列表按类别过滤
+func TestFilterFindingsByCategory(t *testing.T) {
+	findings := []Finding{
+		{RuleID: "B101", Category: CategoryErrorHandling},
+		{RuleID: "G102", Category: CategoryInjection},
+		{RuleID: "ORG001", Category: "Policy"},
+	}
+	got := FilterFindingsByCategory(findings, []string{CategoryInjection})
+	if len(got) != 1 || got[0].RuleID != "G102" {
+		t.Errorf("过滤结果 = %+v", got)
+	}
+	if got := FilterFindingsByCategory(findings, nil); len(got) != 3 {
+		t.Errorf("不指定类别时不应过滤: %d", len(got))
+	}
+}
@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// FileChange 一次被拦截的文件写入
+type FileChange struct {
+	Path    string `json:"path"`
+	Created bool   `json:"created"` // 文件原本不存在
+	Diff    string `json:"diff"`
+}
+
+// ChangeSet 变更集：dry-run 模式下代替实际写入，收集所有文件变更
+// 同一个文件多次写入时只保留相对磁盘原始内容的最终差异
+type ChangeSet struct {
+	mu       sync.Mutex
+	original map[string]*string // 磁盘上的原始内容，nil 表示文件不存在
+	contents map[string]string  // 最后一次写入的内容
+}
+
+// NewChangeSet 创建空的变更集
+func NewChangeSet() *ChangeSet {
+	return &ChangeSet{
+		original: make(map[string]*string),
+		contents: make(map[string]string),
+	}
+}
+
+// Record 记录一次文件写入，不修改磁盘
+func (cs *ChangeSet) Record(path string, data []byte) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if _, ok := cs.original[path]; !ok {
+		src, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			text := string(src)
+			cs.original[path] = &text
+		case os.IsNotExist(err):
+			cs.original[path] = nil
+		default:
+			return fmt.Errorf("读取原文件失败: %w", err)
+		}
+	}
+	cs.contents[path] = string(data)
+	return nil
+}
+
+// Changes 返回有实际差异的文件变更，按路径排序
+func (cs *ChangeSet) Changes() []FileChange {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	changes := []FileChange{}
+	for path, content := range cs.contents {
+		change := FileChange{Path: path}
+		oldName, oldText := "a/"+path, ""
+		if orig := cs.original[path]; orig != nil {
+			oldText = *orig
+		} else {
+			change.Created = true
+			oldName = "/dev/null"
+		}
+		if isBinaryText(oldText) || isBinaryText(content) {
+			// 归档等二进制文件不逐行比较，与 git diff 一样只说明有变化
+			if change.Created || oldText != content {
+				change.Diff = fmt.Sprintf("Binary files %s and b/%s differ\n", oldName, path)
+			}
+		} else {
+			change.Diff = UnifiedDiff(oldName, "b/"+path, oldText, content)
+		}
+		if change.Diff == "" && !change.Created {
+			continue
+		}
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// isBinaryText 内容是否为二进制：包含 NUL 或不是合法的 UTF-8
+func isBinaryText(s string) bool {
+	return strings.IndexByte(s, 0) >= 0 || !utf8.ValidString(s)
+}
+
+// Diff 返回所有变更拼接成的 unified diff
+func (cs *ChangeSet) Diff() string {
+	var sb strings.Builder
+	for _, change := range cs.Changes() {
+		sb.WriteString(change.Diff)
+	}
+	return sb.String()
+}
@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 测试 dry-run 模式下写入只记录到变更集
+func TestChangeSet_DryRun(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "a.go")
+	if err := os.WriteFile(existing, []byte("package a\n\nvar x = 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ws := NewWorkspace("")
+	ws.ChangeSet = NewChangeSet()
+	ctx := WithWorkspace(context.Background(), ws)
+
+	WriteOutputFile(ctx, existing, []byte("package a\n\nvar x = 0\n"), 0644)
+	WriteOutputFile(ctx, existing, []byte("package a\n\nvar x = 2\n"), 0644) // 以最后一次写入为准
+	created := filepath.Join(dir, "a_test.go")
+	WriteOutputFile(ctx, created, []byte("package a\n"), 0644)
+	unchanged := filepath.Join(dir, "b.go")
+	os.WriteFile(unchanged, []byte("package a\n"), 0644)
+	WriteOutputFile(ctx, unchanged, []byte("package a\n"), 0644)
+
+	if data, _ := os.ReadFile(existing); string(data) != "package a\n\nvar x = 1\n" {
+		t.Errorf("dry-run 不应修改文件: %q", data)
+	}
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Error("dry-run 不应创建文件")
+	}
+
+	changes := ws.ChangeSet.Changes()
+	if len(changes) != 2 {
+		t.Fatalf("应该有 2 个变更（内容相同的文件不计入），实际 %+v", changes)
+	}
+	if changes[0].Path != existing || changes[0].Created ||
+		!strings.Contains(changes[0].Diff, "-var x = 1") || !strings.Contains(changes[0].Diff, "+var x = 2") {
+		t.Errorf("修改文件的变更错误: %+v", changes[0])
+	}
+	if changes[1].Path != created || !changes[1].Created || !strings.HasPrefix(changes[1].Diff, "--- /dev/null") {
+		t.Errorf("新建文件的变更错误: %+v", changes[1])
+	}
+	if diff := ws.ChangeSet.Diff(); !strings.Contains(diff, "+++ b/"+created) {
+		t.Errorf("合并的 diff 错误: %s", diff)
+	}
+}
@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"go-ai-study/internal/ai"
+	"go-ai-study/internal/config"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// AnnotateChunkACL 为受限路径下的代码片段打上 ACL 标签，写入片段元数据，返回打标签的片段数
+// 路径模式相对扫描根目录，按声明顺序取第一个匹配的规则
+func AnnotateChunkACL(chunks []schema.Document, root string, rules []config.RestrictedPath) int {
+	if len(rules) == 0 {
+		return 0
+	}
+	tagged := 0
+	for i := range chunks {
+		source, _ := chunks[i].Metadata[ai.MetaSource].(string)
+		if source == "" {
+			continue
+		}
+		rel := relativeSource(root, source)
+		for _, rule := range rules {
+			if rule.Tag != "" && matchPathGlob(rule.Pattern, rel) {
+				chunks[i].Metadata[ai.MetaACL] = rule.Tag
+				tagged++
+				break
+			}
+		}
+	}
+	return tagged
+}
+
+// relativeSource 返回源文件相对扫描根目录的斜杠路径；根目录本身是文件时返回文件名
+func relativeSource(root, source string) string {
+	rel, err := filepath.Rel(root, filepath.FromSlash(source))
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return filepath.Base(source)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// matchPathGlob 匹配斜杠分隔的路径，"**" 匹配任意层目录（包括零层），其余段按 path.Match 规则
+func matchPathGlob(pattern, name string) bool {
+	pattern = strings.Trim(filepath.ToSlash(pattern), "/")
+	name = strings.Trim(name, "/")
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// matchSegments 逐段匹配路径
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(rest, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"go-ai-study/internal/ai"
+	"go-ai-study/internal/config"
+	"testing"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// 测试受限路径下的片段打上 ACL 标签
+func TestAnnotateChunkACL(t *testing.T) {
+	chunk := func(source string) schema.Document {
+		return schema.Document{PageContent: "package x", Metadata: map[string]any{ai.MetaSource: source}}
+	}
+	chunks := []schema.Document{
+		chunk("repo/internal/algo/rank.go"),
+		chunk("repo/internal/algo/v2/score.go"),
+		chunk("repo/internal/api/handler.go"),
+		chunk("repo/secret_keys.go"),
+	}
+	rules := []config.RestrictedPath{
+		{Pattern: "internal/algo/**", Tag: "proprietary"},
+		{Pattern: "**/secret_*.go", Tag: "secrets"},
+		{Pattern: "internal/**", Tag: ""}, // 没有标签的规则忽略
+	}
+
+	tagged := AnnotateChunkACL(chunks, "repo", rules)
+	if tagged != 3 {
+		t.Errorf("应该有 3 个片段打上标签，实际 %d", tagged)
+	}
+	want := []string{"proprietary", "proprietary", "", "secrets"}
+	for i, w := range want {
+		got, _ := chunks[i].Metadata[ai.MetaACL].(string)
+		if got != w {
+			t.Errorf("%s 的标签应为 %q，实际 %q", chunks[i].Metadata[ai.MetaSource], w, got)
+		}
+	}
+}
+
+// 测试路径 glob 匹配
+func TestMatchPathGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"internal/algo/**", "internal/algo/rank.go", true},
+		{"internal/algo/**", "internal/algo/a/b/c.go", true},
+		{"internal/algo/**", "internal/api/x.go", false},
+		{"**/*_secret.go", "db_secret.go", true},
+		{"**/*_secret.go", "a/b/db_secret.go", true},
+		{"internal/*.go", "internal/a/b.go", false},
+		{"internal/*.go", "internal/b.go", true},
+		{"/internal/algo/", "internal/algo", true},
+	}
+	for _, tt := range tests {
+		if got := matchPathGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchPathGlob(%q, %q) = %v，期望 %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package tools
+
+import (
+	"fmt"
+	"go-ai-study/internal/ai"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ChunkExplanation 一个文件的切分结果（scan --explain），用于排查某些代码为什么检索不到，不写入索引
+type ChunkExplanation struct {
+	File      string          `json:"file"`
+	Lines     int             `json:"lines"`
+	Parsed    bool            `json:"parsed"` // false 表示解析失败，按固定行数切分
+	Chunks    []ChunkInfo     `json:"chunks"`
+	Uncovered []UncoveredDecl `json:"uncovered,omitempty"` // 不在任何片段中的顶层声明，检索不到
+}
+
+// ChunkInfo 一个代码片段的边界、大小和元数据
+type ChunkInfo struct {
+	Index     int            `json:"index"` // 从 1 开始
+	Function  string         `json:"function,omitempty"`
+	FuncStart int            `json:"func_start,omitempty"` // 所在函数的行范围（元数据中的 start_line/end_line）
+	FuncEnd   int            `json:"func_end,omitempty"`
+	StartLine int            `json:"start_line,omitempty"` // 片段内容在文件中的行范围，内容与源码不连续时为 0
+	EndLine   int            `json:"end_line,omitempty"`
+	Lines     int            `json:"lines"`
+	Bytes     int            `json:"bytes"`
+	Preview   string         `json:"preview,omitempty"` // 第一行代码
+	Metadata  map[string]any `json:"metadata"`
+	Warnings  []string       `json:"warnings,omitempty"`
+}
+
+// UncoveredDecl 没有被任何片段包含的顶层声明
+type UncoveredDecl struct {
+	Kind  string   `json:"kind"` // type、const、var
+	Names []string `json:"names"`
+	Line  int      `json:"line"`
+}
+
+// chunkPreviewLen 片段预览的最大长度
+const chunkPreviewLen = 80
+
+// ExplainChunks 按 scan 的方式读取并切分单个文件，返回每个片段的边界、大小和元数据
+// prepare 在切分后、解释前调用，用于补充 ACL 标签等与 scan 一致的元数据，可以为 nil
+func ExplainChunks(file string, splitter *ai.CodeSplitter, prepare func([]schema.Document)) (*ChunkExplanation, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %w", err)
+	}
+	// 与 ai.LoadCode 一致：统一换行，来源为斜杠路径
+	doc := schema.Document{
+		PageContent: ai.NormalizeSource(string(data)),
+		Metadata:    map[string]any{ai.MetaSource: filepath.ToSlash(file)},
+	}
+	chunks, err := splitter.SplitDocuments([]schema.Document{doc})
+	if err != nil {
+		return nil, fmt.Errorf("代码分块失败: %w", err)
+	}
+	if prepare != nil {
+		prepare(chunks)
+	}
+
+	src := doc.PageContent
+	lineOffsets := []int{0}
+	for i, c := range src {
+		if c == '\n' {
+			lineOffsets = append(lineOffsets, i+1)
+		}
+	}
+	lineOf := func(offset int) int {
+		line := 1
+		for line < len(lineOffsets) && lineOffsets[line] <= offset {
+			line++
+		}
+		return line
+	}
+
+	fset := token.NewFileSet()
+	parsed, parseErr := parser.ParseFile(fset, "", src, parser.ParseComments|parser.SkipObjectResolution)
+	result := &ChunkExplanation{File: file, Lines: strings.Count(strings.TrimSuffix(src, "\n"), "\n") + 1, Parsed: parseErr == nil, Chunks: []ChunkInfo{}}
+
+	// 大函数的子片段按顺序出现，从上一个子片段的结尾继续查找，避免 "}" 等短片段匹配到前面的位置
+	cursor, lastFunc := 0, -1
+	for i, chunk := range chunks {
+		info := ChunkInfo{
+			Index:     i + 1,
+			FuncStart: ai.MetadataInt(chunk.Metadata, ai.MetaStartLine),
+			FuncEnd:   ai.MetadataInt(chunk.Metadata, ai.MetaEndLine),
+			Lines:     strings.Count(chunk.PageContent, "\n") + 1,
+			Bytes:     len(chunk.PageContent),
+			Preview:   chunkPreview(chunk.PageContent),
+			Metadata:  chunk.Metadata,
+		}
+		info.Function, _ = chunk.Metadata[ai.MetaFunction].(string)
+
+		// 分块器记录了起始行号时直接使用，否则在源码中查找片段内容
+		from := cursor
+		if info.FuncStart != lastFunc {
+			from = commentBlockStart(src, lineOffsets, info.FuncStart)
+		}
+		if line := ai.MetadataInt(chunk.Metadata, ai.MetaLine); line > 0 && line <= len(lineOffsets) {
+			info.StartLine = line
+			info.EndLine = line + info.Lines - 1
+			cursor = min(lineOffsets[line-1]+len(chunk.PageContent), len(src))
+		} else if idx := strings.Index(src[from:], chunk.PageContent); idx >= 0 && chunk.PageContent != "" {
+			start := from + idx
+			info.StartLine = lineOf(start)
+			info.EndLine = info.StartLine + info.Lines - 1
+			cursor = start + len(chunk.PageContent)
+		}
+		lastFunc = info.FuncStart
+
+		if strings.TrimSpace(chunk.PageContent) == "" {
+			info.Warnings = append(info.Warnings, "片段为空，检索不到任何内容")
+		} else if info.StartLine == 0 {
+			info.Warnings = append(info.Warnings, "片段内容与源码不连续（如注释被重复拼接），无法定位行号")
+		}
+		if info.Bytes > ai.CodeContentMaxLength {
+			info.Warnings = append(info.Warnings, fmt.Sprintf("长度 %d 字节超过 content 字段上限 %d，写入索引时整批失败", info.Bytes, ai.CodeContentMaxLength))
+		}
+		if acl, _ := chunk.Metadata[ai.MetaACL].(string); acl != "" {
+			info.Warnings = append(info.Warnings, fmt.Sprintf("受限片段（acl=%s），访问范围不包含该标签时检索不到", acl))
+		}
+		result.Chunks = append(result.Chunks, info)
+	}
+
+	if parseErr == nil {
+		result.Uncovered = uncoveredDecls(fset, parsed, result.Chunks)
+	}
+	return result, nil
+}
+
+// commentBlockStart 函数起始行之前紧邻的注释块（允许空行）的起始偏移，与 CodeSplitter.addContext 向前查找注释的方式一致
+func commentBlockStart(src string, lineOffsets []int, funcStart int) int {
+	if funcStart <= 0 || funcStart > len(lineOffsets) {
+		return 0
+	}
+	start := funcStart - 1
+	for i := start - 1; i >= 0; i-- {
+		end := len(src)
+		if i+1 < len(lineOffsets) {
+			end = lineOffsets[i+1]
+		}
+		line := strings.TrimSpace(src[lineOffsets[i]:end])
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "//") {
+			break
+		}
+		start = i
+	}
+	return lineOffsets[start]
+}
+
+// chunkPreview 片段中第一行非空、非注释的代码
+func chunkPreview(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		if len([]rune(line)) > chunkPreviewLen {
+			line = string([]rune(line)[:chunkPreviewLen]) + "..."
+		}
+		return line
+	}
+	return ""
+}
+
+// uncoveredDecls 没有被任何片段完整包含的类型、常量和变量声明
+func uncoveredDecls(fset *token.FileSet, file *ast.File, chunks []ChunkInfo) []UncoveredDecl {
+	var uncovered []UncoveredDecl
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok == token.IMPORT {
+			continue
+		}
+		start, end := fset.Position(gen.Pos()).Line, fset.Position(gen.End()).Line
+		covered := false
+		for _, c := range chunks {
+			if c.StartLine > 0 && c.StartLine <= start && end <= c.EndLine {
+				covered = true
+				break
+			}
+		}
+		if covered {
+			continue
+		}
+		u := UncoveredDecl{Kind: gen.Tok.String(), Line: start}
+		for _, spec := range gen.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				u.Names = append(u.Names, s.Name.Name)
+			case *ast.ValueSpec:
+				for _, name := range s.Names {
+					u.Names = append(u.Names, name.Name)
+				}
+			}
+		}
+		uncovered = append(uncovered, u)
+	}
+	return uncovered
+}
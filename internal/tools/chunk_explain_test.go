@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"go-ai-study/internal/ai"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// 测试切分解释：片段行号、函数元数据，以及没有进入任何片段的顶层声明
+func TestExplainChunks(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.go")
+	code := `package main
+
+import "fmt"
+
+// Config 不在任何函数附近
+type Config struct {
+	Name string
+}
+
+var defaultName = "x"
+
+// Hello 打招呼
+func Hello(name string) string {
+	return fmt.Sprintf("hello %s", name)
+}
+`
+	if err := os.WriteFile(file, []byte(code), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ExplainChunks(file, ai.NewCodeSplitter(), func(chunks []schema.Document) {
+		for i := range chunks {
+			chunks[i].Metadata[ai.MetaACL] = "internal"
+		}
+	})
+	if err != nil {
+		t.Fatalf("解释切分失败: %v", err)
+	}
+	if !result.Parsed || result.Lines != 15 || len(result.Chunks) != 2 {
+		t.Fatalf("切分结果错误: %+v", result)
+	}
+	chunk := result.Chunks[0]
+	if chunk.Function != "Hello" || chunk.FuncStart != 13 || chunk.FuncEnd != 15 {
+		t.Errorf("函数元数据错误: %+v", chunk)
+	}
+	// 片段包含函数前的注释
+	if chunk.StartLine != 12 || chunk.EndLine != 15 || chunk.Preview != "func Hello(name string) string {" {
+		t.Errorf("片段边界错误: %+v", chunk)
+	}
+	if ai.MetadataInt(chunk.Metadata, ai.MetaLine) != 12 {
+		t.Errorf("片段应该记录内容的起始行号: %v", chunk.Metadata)
+	}
+	if len(chunk.Warnings) != 1 || !strings.Contains(chunk.Warnings[0], "acl=internal") {
+		t.Errorf("受限片段应该有提示: %v", chunk.Warnings)
+	}
+
+	// 函数之外的 import、类型和变量单独成块
+	residual := result.Chunks[1]
+	if residual.Function != "" || residual.StartLine != 1 || residual.EndLine != 10 || residual.FuncStart != 1 || residual.FuncEnd != 10 {
+		t.Errorf("函数之外的片段错误: %+v", residual)
+	}
+	if ai.MetadataInt(residual.Metadata, ai.MetaLine) != 1 {
+		t.Errorf("函数之外的片段应该记录起始行号: %v", residual.Metadata)
+	}
+	if len(result.Uncovered) != 0 {
+		t.Errorf("所有顶层声明都应该在片段中: %+v", result.Uncovered)
+	}
+
+	// 没有被片段完整包含的声明逐个列出
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseFile(fset, "", code, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uncovered := uncoveredDecls(fset, parsed, []ChunkInfo{{StartLine: 1, EndLine: 8}})
+	if len(uncovered) != 1 || uncovered[0].Kind != "var" || uncovered[0].Line != 10 || uncovered[0].Names[0] != "defaultName" {
+		t.Errorf("未覆盖的声明错误: %+v", uncovered)
+	}
+
+	// 解析失败时按固定行数切分
+	if err := os.WriteFile(file, []byte("not go code\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err = ExplainChunks(file, ai.NewCodeSplitter(), nil)
+	if err != nil {
+		t.Fatalf("解释切分失败: %v", err)
+	}
+	if result.Parsed || len(result.Chunks) != 1 || result.Chunks[0].StartLine != 1 {
+		t.Errorf("解析失败时应按行切分: %+v", result)
+	}
+}
@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"context"
+	"go-ai-study/internal/ai"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// blameLine git blame 中一行的最后修改信息
+type blameLine struct {
+	author string
+	time   int64 // 作者时间（Unix 秒），尚未提交的行为 0
+}
+
+// AnnotateChunkGit 为代码片段补充 git 信息（片段行范围内最近一次提交的作者和时间），写入片段元数据，返回补充的片段数
+// 每个文件执行一次 git blame；不在 git 仓库中、尚未纳入版本管理的文件，以及依赖模块的片段保持不变
+func AnnotateChunkGit(ctx context.Context, chunks []schema.Document) (int, error) {
+	blames := make(map[string][]blameLine) // 文件 -> 每行的最后修改，blame 失败时为 nil
+	annotated := 0
+	for i := range chunks {
+		metadata := chunks[i].Metadata
+		source, _ := metadata[ai.MetaSource].(string)
+		if dependency, _ := metadata[ai.MetaDependency].(bool); dependency || source == "" {
+			continue
+		}
+		start, end := chunkLineRange(chunks[i])
+		if start == 0 {
+			continue
+		}
+
+		lines, ok := blames[source]
+		if !ok {
+			if err := ctx.Err(); err != nil {
+				return annotated, err
+			}
+			lines = blameFile(ctx, source)
+			blames[source] = lines
+		}
+
+		var newest blameLine
+		for line := start; line <= end && line <= len(lines); line++ {
+			if b := lines[line-1]; b.time > newest.time {
+				newest = b
+			}
+		}
+		if newest.time == 0 {
+			continue
+		}
+		metadata[ai.MetaAuthor] = newest.author
+		metadata[ai.MetaModified] = newest.time
+		annotated++
+	}
+	return annotated, nil
+}
+
+// chunkLineRange 片段内容在源文件中的行范围（从 1 开始）
+// 内容与源码不连续时退回所在函数的行范围，都没有时返回 0
+func chunkLineRange(chunk schema.Document) (int, int) {
+	if line := ai.MetadataInt(chunk.Metadata, ai.MetaLine); line > 0 {
+		return line, line + strings.Count(chunk.PageContent, "\n")
+	}
+	start := ai.MetadataInt(chunk.Metadata, ai.MetaStartLine)
+	end := ai.MetadataInt(chunk.Metadata, ai.MetaEndLine)
+	if start <= 0 || end < start {
+		return 0, 0
+	}
+	return start, end
+}
+
+// blameFile 对文件执行 git blame，返回每行的最后修改；失败时（不在仓库中、未纳入版本管理）返回 nil
+func blameFile(ctx context.Context, file string) []blameLine {
+	out, err := gitOutput(ctx, filepath.Dir(filepath.FromSlash(file)), "blame", "--line-porcelain", "--", filepath.Base(file))
+	if err != nil {
+		return nil
+	}
+	return parseBlame(out)
+}
+
+// parseBlame 解析 git blame --line-porcelain 的输出，按行号顺序返回每行的作者和作者时间
+// 每行以提交头开始，以制表符开头的源码行结束；尚未提交的行（提交号全为 0）时间记为 0
+func parseBlame(out string) []blameLine {
+	var lines []blameLine
+	var cur blameLine
+	committed := false
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			if !committed {
+				cur.time = 0
+			}
+			lines = append(lines, cur)
+			cur, committed = blameLine{}, false
+		case strings.HasPrefix(line, "author "):
+			cur.author = strings.TrimPrefix(line, "author ")
+		case strings.HasPrefix(line, "author-time "):
+			cur.time, _ = strconv.ParseInt(strings.TrimPrefix(line, "author-time "), 10, 64)
+		default:
+			if sha, _, ok := strings.Cut(line, " "); ok && len(sha) >= 40 && isHex(sha) {
+				committed = strings.Trim(sha, "0") != ""
+			}
+		}
+	}
+	return lines
+}
+
+// isHex 是否全为十六进制字符
+func isHex(s string) bool {
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
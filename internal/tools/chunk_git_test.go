@@ -0,0 +1,91 @@
+package tools
+
+import (
+	"context"
+	"go-ai-study/internal/ai"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// 测试解析 git blame 输出：每行一条记录，未提交的行时间为 0
+func TestParseBlame(t *testing.T) {
+	out := "1111111111111111111111111111111111111111 1 1 2\n" +
+		"author alice\nauthor-mail <a@x>\nauthor-time 1700000000\nauthor-tz +0800\nsummary init\nfilename a.go\n\tpackage a\n" +
+		"1111111111111111111111111111111111111111 2 2\n" +
+		"author alice\nauthor-time 1700000000\nfilename a.go\n\t\n" +
+		"0000000000000000000000000000000000000000 3 3 1\n" +
+		"author Not Committed Yet\nauthor-time 1800000000\nfilename a.go\n\tfunc A() {}\n"
+	lines := parseBlame(out)
+	if len(lines) != 3 {
+		t.Fatalf("应该解析出 3 行，实际 %d", len(lines))
+	}
+	if lines[0].author != "alice" || lines[0].time != 1700000000 {
+		t.Errorf("第 1 行解析错误: %+v", lines[0])
+	}
+	if lines[2].time != 0 {
+		t.Errorf("未提交的行时间应该为 0: %+v", lines[2])
+	}
+}
+
+// 测试为片段补充 git 信息：取片段范围内最近一次提交，未纳入版本管理的文件和依赖片段不补充
+func TestAnnotateChunkGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("没有 git")
+	}
+	repo := t.TempDir()
+	git := func(author, date string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=" + author, "-c", "user.email=t@t", "-c", "commit.gpgsign=false"}, args...)...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v 失败: %v\n%s", args, err, out)
+		}
+	}
+	file := filepath.Join(repo, "retry.go")
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(file, "package retry\n\nfunc Do() {\n\tcall()\n}\n\nfunc Wait() {\n}\n")
+	git("alice", "2024-01-01T00:00:00Z", "init", "-q")
+	git("alice", "2024-01-01T00:00:00Z", "add", "-A")
+	git("alice", "2024-01-01T00:00:00Z", "commit", "-q", "-m", "init")
+	write(file, "package retry\n\nfunc Do() {\n\tcallWithBackoff()\n}\n\nfunc Wait() {\n}\n")
+	git("bob", "2024-03-01T00:00:00Z", "commit", "-q", "-am", "backoff")
+	untracked := filepath.Join(repo, "new.go")
+	write(untracked, "package retry\n")
+
+	source := filepath.ToSlash(file)
+	chunks := []schema.Document{
+		{PageContent: "func Do() {\n\tcallWithBackoff()\n}", Metadata: map[string]any{ai.MetaSource: source, ai.MetaLine: 3}},
+		{PageContent: "// 注释\nfunc Wait() {", Metadata: map[string]any{ai.MetaSource: source, ai.MetaStartLine: 7, ai.MetaEndLine: 8}},
+		{PageContent: "package retry", Metadata: map[string]any{ai.MetaSource: filepath.ToSlash(untracked), ai.MetaLine: 1}},
+		{PageContent: "func Do() {", Metadata: map[string]any{ai.MetaSource: source, ai.MetaLine: 3, ai.MetaDependency: true}},
+	}
+	count, err := AnnotateChunkGit(context.Background(), chunks)
+	if err != nil {
+		t.Fatalf("补充 git 信息失败: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("应该补充 2 个片段，实际 %d", count)
+	}
+	if chunks[0].Metadata[ai.MetaAuthor] != "bob" || ai.MetadataInt(chunks[0].Metadata, ai.MetaModified) != 1709251200 {
+		t.Errorf("Do 最后由 bob 修改: %v", chunks[0].Metadata)
+	}
+	if chunks[1].Metadata[ai.MetaAuthor] != "alice" {
+		t.Errorf("Wait 按函数范围取 alice 的提交: %v", chunks[1].Metadata)
+	}
+	for _, chunk := range chunks[2:] {
+		if _, ok := chunk.Metadata[ai.MetaAuthor]; ok {
+			t.Errorf("未纳入版本管理的文件和依赖片段不应补充 git 信息: %v", chunk.Metadata)
+		}
+	}
+}
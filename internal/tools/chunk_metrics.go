@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"go-ai-study/internal/ai"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// AnnotateChunkMetrics 为代码片段补充分析器指标（所在函数的圈复杂度和问题数），写入片段元数据
+// 片段需要带有 CodeSplitter 生成的 source / start_line / end_line 元数据，其余片段保持不变
+func AnnotateChunkMetrics(chunks []schema.Document, findings []Finding) {
+	complexities := make(map[string]map[int]int) // 文件 -> 函数起始行 -> 圈复杂度
+	findingLines := make(map[string][]int)       // 文件 -> 问题行号
+	for _, f := range findings {
+		file := filepath.ToSlash(filepath.Clean(f.File))
+		findingLines[file] = append(findingLines[file], f.Line)
+	}
+
+	for i := range chunks {
+		metadata := chunks[i].Metadata
+		source, _ := metadata[ai.MetaSource].(string)
+		start := ai.MetadataInt(metadata, ai.MetaStartLine)
+		end := ai.MetadataInt(metadata, ai.MetaEndLine)
+		if source == "" || start == 0 || end < start {
+			continue
+		}
+		source = filepath.ToSlash(filepath.Clean(source))
+
+		byLine, ok := complexities[source]
+		if !ok {
+			byLine = functionComplexities(source)
+			complexities[source] = byLine
+		}
+
+		count := 0
+		for _, line := range findingLines[source] {
+			if line >= start && line <= end {
+				count++
+			}
+		}
+		metadata[ai.MetaComplexity] = byLine[start]
+		metadata[ai.MetaFindings] = count
+	}
+}
+
+// functionComplexities 解析文件，返回每个函数起始行对应的圈复杂度
+func functionComplexities(file string) map[int]int {
+	result := make(map[int]int)
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, nil, 0)
+	if err != nil {
+		return result
+	}
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			result[fset.Position(fn.Pos()).Line] = calculateComplexity(fn)
+		}
+	}
+	return result
+}
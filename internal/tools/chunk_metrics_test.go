@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"go-ai-study/internal/ai"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// 测试为代码片段补充复杂度和问题数
+func TestAnnotateChunkMetrics(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "auth.go")
+	src := `package auth
+
+func Simple() int {
+	return 1
+}
+
+func (s *Service) Login(user string) error {
+	if user == "" {
+		return nil
+	}
+	for i := 0; i < 3; i++ {
+		if i == 2 {
+			return nil
+		}
+	}
+	return nil
+}
+`
+	if err := os.WriteFile(file, []byte(src), 0644); err != nil {
+		t.Fatalf("写入测试文件失败: %v", err)
+	}
+
+	docs := []schema.Document{{PageContent: src, Metadata: map[string]any{ai.MetaSource: filepath.ToSlash(file)}}}
+	chunks, err := ai.NewCodeSplitter().SplitDocuments(docs)
+	if err != nil || len(chunks) != 2 {
+		t.Fatalf("应该分成 2 个片段: %d %v", len(chunks), err)
+	}
+
+	findings := []Finding{
+		{File: file, Line: 8},
+		{File: file, Line: 12},
+		{File: file, Line: 30}, // 不在任何函数内
+	}
+	AnnotateChunkMetrics(chunks, findings)
+
+	want := map[string][2]int{"Simple": {1, 0}, "Service.Login": {4, 2}}
+	for _, chunk := range chunks {
+		name, _ := chunk.Metadata[ai.MetaFunction].(string)
+		got := [2]int{ai.MetadataInt(chunk.Metadata, ai.MetaComplexity), ai.MetadataInt(chunk.Metadata, ai.MetaFindings)}
+		if got != want[name] {
+			t.Errorf("%s 的指标 = %v, 期望 %v", name, got, want[name])
+		}
+	}
+	if _, shared := docs[0].Metadata[ai.MetaFunction]; shared {
+		t.Error("片段元数据不应修改原文档的元数据")
+	}
+}
@@ -0,0 +1,320 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms/fake"
+)
+
+func runCodeFixer(t *testing.T, fixer *CodeFixer, req FixRequest) FixResult {
+	t.Helper()
+	output, err := fixer.Run(context.Background(), req)
+	if err != nil {
+		t.Fatalf("生成修复失败: %v", err)
+	}
+	var result FixResult
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("解析结果失败: %v", err)
+	}
+	return result
+}
+
+// 测试确定性修复：只输出 diff，不修改文件
+func TestCodeFixer_DryRun(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.go")
+	code := `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func Save(name string, data []byte) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("open %s: %v", name, err)
+	}
+	_, _ = f.Stat()
+	return os.WriteFile(name, data, 0777)
+}
+`
+	if err := os.WriteFile(file, []byte(code), 0644); err != nil {
+		t.Fatalf("写入测试文件失败: %v", err)
+	}
+
+	result := runCodeFixer(t, NewCodeFixer(nil, NewNoopLogger()), FixRequest{Directory: dir})
+	if len(result.Patches) != 1 || result.TotalFixes != 3 {
+		t.Fatalf("应该生成 3 处修复: %+v", result)
+	}
+
+	diff := result.Patches[0].Diff
+	for _, want := range []string{
+		"+\tdefer f.Close()",
+		`+		return fmt.Errorf("open %s: %w", name, err)`,
+		"+\treturn os.WriteFile(name, data, 0600)",
+	} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("diff 缺少 %q:\n%s", want, diff)
+		}
+	}
+
+	content, _ := os.ReadFile(file)
+	if string(content) != code {
+		t.Fatal("未指定 Write 时不应修改文件")
+	}
+}
+
+// 测试按规则筛选并写回文件
+func TestCodeFixer_WriteSelected(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.go")
+	code := `package main
+
+import "os"
+
+func Save(name string, data []byte) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	return os.WriteFile(name+".bak", data, 0666)
+}
+`
+	if err := os.WriteFile(file, []byte(code), 0644); err != nil {
+		t.Fatalf("写入测试文件失败: %v", err)
+	}
+
+	result := runCodeFixer(t, NewCodeFixer(nil, NewNoopLogger()), FixRequest{Files: []string{file}, Fixes: []string{"G302"}, Write: true})
+	if !result.Applied || result.TotalFixes != 1 {
+		t.Fatalf("应该只应用 G302 修复: %+v", result)
+	}
+
+	content, _ := os.ReadFile(file)
+	if !strings.Contains(string(content), "0600") || strings.Contains(string(content), "defer") {
+		t.Fatalf("写回内容不正确:\n%s", content)
+	}
+}
+
+// 测试 LLM 改写弱随机数，并替换导入
+func TestCodeFixer_CryptoRandLLM(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "token.go")
+	code := `package main
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+func Token() string {
+	return fmt.Sprint(rand.Intn(1000000))
+}
+`
+	if err := os.WriteFile(file, []byte(code), 0644); err != nil {
+		t.Fatalf("写入测试文件失败: %v", err)
+	}
+
+	answer := "```go\nfunc Token() string {\n\tn, err := rand.Int(rand.Reader, big.NewInt(1000000))\n\tif err != nil {\n\t\tpanic(err)\n\t}\n\treturn fmt.Sprint(n)\n}\n```"
+	fixer := NewCodeFixer(fake.NewFakeLLM([]string{answer}), NewNoopLogger())
+
+	result := runCodeFixer(t, fixer, FixRequest{Files: []string{file}})
+	if len(result.Patches) != 1 || result.Patches[0].Fixes[0].Method != "llm" {
+		t.Fatalf("应该生成 LLM 修复: %+v", result)
+	}
+
+	diff := result.Patches[0].Diff
+	for _, want := range []string{`+	"crypto/rand"`, `+	"math/big"`, `-	"math/rand"`, "rand.Reader"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("diff 缺少 %q:\n%s", want, diff)
+		}
+	}
+}
+
+// 测试模型不可用时跳过 LLM 修复
+func TestCodeFixer_NoModel(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "token.go")
+	code := "package main\n\nimport \"math/rand\"\n\nfunc Token() int {\n\treturn rand.Int()\n}\n"
+	if err := os.WriteFile(file, []byte(code), 0644); err != nil {
+		t.Fatalf("写入测试文件失败: %v", err)
+	}
+
+	result := runCodeFixer(t, NewCodeFixer(nil, NewNoopLogger()), FixRequest{Files: []string{file}})
+	if len(result.Patches) != 0 || len(result.Skipped) != 1 {
+		t.Fatalf("没有模型时应该跳过 crypto-rand: %+v", result)
+	}
+}
+
+// 测试 unified diff 输出
+func TestUnifiedDiff(t *testing.T) {
+	oldText := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\n"
+	newText := "a\nb\nc\nD\ne\nf\ng\nh\ni\nj\nk\nl\n"
+
+	diff := UnifiedDiff("a/x.go", "b/x.go", oldText, newText)
+	want := "--- a/x.go\n+++ b/x.go\n" +
+		"@@ -1,7 +1,7 @@\n a\n b\n c\n-d\n+D\n e\n f\n g\n" +
+		"@@ -9,3 +9,4 @@\n i\n j\n k\n+l\n"
+	if diff != want {
+		t.Fatalf("diff 不正确:\n%s\n期望:\n%s", diff, want)
+	}
+
+	if UnifiedDiff("a", "b", oldText, oldText) != "" {
+		t.Fatal("内容相同时应返回空串")
+	}
+}
+
+// 测试确定性修复：错误检查、ioutil 替换；default 分支需要人补充，--mechanical-only 时不插入
+func TestCodeFixer_MechanicalOnly(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.go")
+	code := `package main
+
+import (
+	"io/ioutil"
+	"math/rand"
+	"os"
+)
+
+func Load(name string, kind int) (int, error) {
+	data, _ := ioutil.ReadFile(name)
+	_ = os.Remove(name)
+	home, _ := os.LookupEnv("HOME")
+	n, _ := ReadCount(string(data) + home)
+	switch kind {
+	case 1:
+		n++
+	}
+	return n + rand.Intn(10), nil
+}
+
+func ReadCount(s string) (int, error) {
+	return len(s), nil
+}
+`
+	if err := os.WriteFile(file, []byte(code), 0644); err != nil {
+		t.Fatalf("写入测试文件失败: %v", err)
+	}
+
+	answer := "```go\nfunc Load(name string, kind int) int {\n\treturn 0\n}\n```"
+	fixer := NewCodeFixer(fake.NewFakeLLM([]string{answer}), NewNoopLogger())
+
+	result := runCodeFixer(t, fixer, FixRequest{Files: []string{file}, MechanicalOnly: true, Write: true})
+	for _, fix := range result.Patches[0].Fixes {
+		if fix.Method != "ast" {
+			t.Fatalf("--mechanical-only 不应调用 LLM: %+v", fix)
+		}
+	}
+
+	content, _ := os.ReadFile(file)
+	want := `package main
+
+import (
+	"math/rand"
+	"os"
+)
+
+func Load(name string, kind int) (int, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.Remove(name); err != nil {
+		return 0, err
+	}
+	home, _ := os.LookupEnv("HOME")
+	n, err := ReadCount(string(data) + home)
+	if err != nil {
+		return 0, err
+	}
+	switch kind {
+	case 1:
+		n++
+	}
+	return n + rand.Intn(10), nil
+}
+
+func ReadCount(s string) (int, error) {
+	return len(s), nil
+}
+`
+	if string(content) != want {
+		t.Fatalf("修复结果不正确:\n%s", content)
+	}
+}
+
+// 测试错误检查修复的 return 语句和跳过条件：err 已声明、所在函数不返回 error、零值无法确定时不修改
+func TestErrCheckFix_Apply(t *testing.T) {
+	cases := []struct {
+		name string
+		body string // 函数声明，测试文件中另有 func ReadCount() (int, error)
+		want string // 期望的函数声明，为空表示不修改
+	}{
+		{
+			name: "零值",
+			body: "func h() (*int, []int, map[string]int, string, bool, error) {\n\t_ = os.Remove(\"x\")\n\treturn nil, nil, nil, \"\", false, nil\n}",
+			want: "func h() (*int, []int, map[string]int, string, bool, error) {\n\tif err := os.Remove(\"x\"); err != nil {\n\t\treturn nil, nil, nil, \"\", false, err\n\t}\n\treturn nil, nil, nil, \"\", false, nil\n}",
+		},
+		{
+			name: "有名返回值",
+			body: "func h() (n int, err error) {\n\t_ = os.Remove(\"x\")\n\treturn\n}",
+			want: "func h() (n int, err error) {\n\tif err := os.Remove(\"x\"); err != nil {\n\t\treturn n, err\n\t}\n\treturn\n}",
+		},
+		{
+			name: "闭包返回 error",
+			body: "func h() {\n\trun(func() error {\n\t\tv, _ := ReadCount()\n\t\treturn check(v)\n\t})\n}",
+			want: "func h() {\n\trun(func() error {\n\t\tv, err := ReadCount()\n\t\tif err != nil {\n\t\t\treturn err\n\t\t}\n\t\treturn check(v)\n\t})\n}",
+		},
+		{
+			name: "err 已声明为其他类型",
+			body: "func h() error {\n\terr := \"x\"\n\tv, _ := ReadCount()\n\treturn check(v + len(err))\n}",
+		},
+		{
+			name: "遮蔽外层 err",
+			body: "func h(ok bool) error {\n\tvar err error\n\tif ok {\n\t\tv, _ := ReadCount()\n\t\treturn check(v)\n\t}\n\treturn err\n}",
+		},
+		{
+			name: "err 是参数",
+			body: "func h(err error) error {\n\tv, _ := ReadCount()\n\treturn check(v)\n}",
+		},
+		{
+			name: "同一代码块后面声明 err",
+			body: "func h() error {\n\tv, _ := ReadCount()\n\terr := check(v)\n\treturn err\n}",
+		},
+		{
+			name: "函数不返回 error",
+			body: "func h() int {\n\tv, _ := ReadCount()\n\treturn v\n}",
+		},
+		{
+			name: "零值无法确定",
+			body: "func h() (time.Duration, error) {\n\t_ = os.Remove(\"x\")\n\treturn 0, nil\n}",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			src := "package p\n\nimport (\n\t\"os\"\n\t\"time\"\n)\n\nvar _ = time.Second\n\n" + tc.body +
+				"\n\nfunc ReadCount() (int, error) { return 0, nil }\n\nfunc check(int) error { return nil }\n\nfunc run(func() error) {}\n"
+			fctx, err := newFixContext("p.go", []byte(src))
+			if err != nil {
+				t.Fatalf("解析失败: %v", err)
+			}
+			got := string(applyFixEdits(fctx.Src, (&ErrCheckFix{}).Apply(fctx)))
+			want := tc.want
+			if want == "" {
+				want = tc.body
+			}
+			if !strings.Contains(got, want) {
+				t.Errorf("修复结果不正确:\n%s\n期望包含:\n%s", got, want)
+			}
+			if _, err := newFixContext("p.go", []byte(got)); err != nil {
+				t.Errorf("修复后无法解析: %v", err)
+			}
+		})
+	}
+}
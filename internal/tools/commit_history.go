@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"go-ai-study/internal/ai"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// 提交历史的读取上限
+const (
+	DefaultHistoryLimit     = 500 // 默认读取最近的提交数
+	maxCommitExcerptLines   = 20  // 每次提交节选的改动行数
+	maxCommitExcerptLineLen = 200 // 节选的改动行最大长度
+)
+
+// git log 输出中分隔提交和字段的控制字符，不会出现在提交说明中
+const (
+	commitRecordSep = "\x1e"
+	commitFieldSep  = "\x1f"
+)
+
+// ReadCommitHistory 读取 dir 所在仓库中涉及 dir 的最近 limit 次提交（不含合并提交），
+// 每次提交带提交说明、改动文件的增删行数、改动所在的函数和节选的改动行
+func ReadCommitHistory(ctx context.Context, dir string, limit int) ([]ai.CommitDoc, error) {
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+	format := commitRecordSep + strings.Join([]string{"%H", "%an", "%at", "%B"}, commitFieldSep) + commitFieldSep
+	out, err := gitOutput(ctx, dir, "log", "-n", strconv.Itoa(limit), "--no-merges", "--no-color", "--no-ext-diff",
+		"-U0", "--format="+format, "--", ".")
+	if err != nil {
+		return nil, fmt.Errorf("读取提交历史失败: %w", err)
+	}
+	return parseCommitLog(out), nil
+}
+
+// parseCommitLog 解析 ReadCommitHistory 格式的 git log 输出：
+// 每次提交以记录分隔符开始，依次是提交号、作者、作者时间、提交说明，之后是 -U0 的补丁
+func parseCommitLog(out string) []ai.CommitDoc {
+	var commits []ai.CommitDoc
+	for _, record := range strings.Split(out, commitRecordSep) {
+		fields := strings.SplitN(record, commitFieldSep, 5)
+		if len(fields) < 5 {
+			continue
+		}
+		commit := ai.CommitDoc{
+			Hash:    strings.TrimSpace(fields[0]),
+			Author:  fields[1],
+			Message: strings.TrimSpace(fields[3]),
+		}
+		commit.Date, _ = strconv.ParseInt(strings.TrimSpace(fields[2]), 10, 64)
+		commit.Changes, commit.Excerpt = summarizePatch(fields[4])
+		commits = append(commits, commit)
+	}
+	return commits
+}
+
+// summarizePatch 从补丁中统计每个文件的增删行数和改动所在的函数，并节选前几行非空的改动
+func summarizePatch(patch string) ([]ai.FileChange, []string) {
+	var changes []ai.FileChange
+	var excerpt []string
+	var cur *ai.FileChange
+	for _, line := range strings.Split(patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			path := line[strings.LastIndex(line, " b/")+3:]
+			changes = append(changes, ai.FileChange{Path: path})
+			cur = &changes[len(changes)-1]
+		case cur == nil, strings.HasPrefix(line, "+++ "), strings.HasPrefix(line, "--- "):
+		case strings.HasPrefix(line, "@@"):
+			// @@ -a,b +c,d @@ 之后是块所在的函数
+			if i := strings.Index(line[2:], "@@"); i >= 0 {
+				if fn := strings.TrimSpace(line[i+4:]); fn != "" && !slices.Contains(cur.Functions, fn) {
+					cur.Functions = append(cur.Functions, fn)
+				}
+			}
+		case strings.HasPrefix(line, "+"), strings.HasPrefix(line, "-"):
+			if line[0] == '+' {
+				cur.Added++
+			} else {
+				cur.Deleted++
+			}
+			if len(excerpt) < maxCommitExcerptLines && strings.TrimSpace(line[1:]) != "" {
+				if len(line) > maxCommitExcerptLineLen {
+					line = strings.ToValidUTF8(line[:maxCommitExcerptLineLen], "") + "..."
+				}
+				excerpt = append(excerpt, line)
+			}
+		}
+	}
+	return changes, excerpt
+}
@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 测试读取提交历史：提交说明、增删行数、改动所在的函数和节选的改动行
+func TestReadCommitHistory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("没有 git")
+	}
+	repo := t.TempDir()
+	git := func(author, date string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=" + author, "-c", "user.email=t@t", "-c", "commit.gpgsign=false"}, args...)...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v 失败: %v\n%s", args, err, out)
+		}
+	}
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, "index.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("package index\n\nfunc build() {\n\tidx := NewIndexHNSW(L2, 16, 64)\n\t_ = idx\n}\n")
+	git("alice", "2024-01-01T00:00:00Z", "init", "-q")
+	git("alice", "2024-01-01T00:00:00Z", "add", "-A")
+	git("alice", "2024-01-01T00:00:00Z", "commit", "-q", "-m", "init")
+	write("package index\n\nfunc build() {\n\tidx := NewIndexHNSW(COSINE, 16, 64)\n\t_ = idx\n}\n")
+	git("bob", "2024-03-01T00:00:00Z", "commit", "-q", "-am", "Switch HNSW metric to COSINE\n\nbge-m3 vectors are normalized.")
+
+	commits, err := ReadCommitHistory(context.Background(), repo, 0)
+	if err != nil {
+		t.Fatalf("读取提交历史失败: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("应该读取 2 次提交，实际 %d", len(commits))
+	}
+	latest := commits[0]
+	if len(latest.Hash) != 40 || latest.Author != "bob" || latest.Date != 1709251200 {
+		t.Errorf("提交信息错误: %+v", latest)
+	}
+	if latest.Message != "Switch HNSW metric to COSINE\n\nbge-m3 vectors are normalized." {
+		t.Errorf("提交说明 = %q", latest.Message)
+	}
+	if len(latest.Changes) != 1 || latest.Changes[0].Path != "index.go" || latest.Changes[0].Added != 1 || latest.Changes[0].Deleted != 1 {
+		t.Fatalf("改动统计错误: %+v", latest.Changes)
+	}
+	if fns := latest.Changes[0].Functions; len(fns) != 1 || fns[0] != "func build() {" {
+		t.Errorf("改动所在的函数 = %q", fns)
+	}
+	if len(latest.Excerpt) != 2 || !strings.Contains(latest.Excerpt[1], "+\tidx := NewIndexHNSW(COSINE") {
+		t.Errorf("节选的改动行 = %q", latest.Excerpt)
+	}
+	if text := latest.Text(); !strings.Contains(text, "index.go (+1 -1) func build() {") || !strings.Contains(text, "作者: bob  日期: 2024-03-01") {
+		t.Errorf("提交文本缺少改动摘要:\n%s", text)
+	}
+
+	if commits, err := ReadCommitHistory(context.Background(), repo, 1); err != nil || len(commits) != 1 {
+		t.Errorf("limit 为 1 时应该只读取 1 次提交: %d %v", len(commits), err)
+	}
+	if _, err := ReadCommitHistory(context.Background(), t.TempDir(), 0); err == nil {
+		t.Error("不在 git 仓库中应该报错")
+	}
+}
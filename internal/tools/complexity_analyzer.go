@@ -2,47 +2,91 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
-	"reflect"
+	"go-ai-study/internal/ai"
+	"go-ai-study/internal/i18n"
 	"strings"
 )
 
 // ComplexityAnalyzer 代码复杂度分析器
-// 分析 Go 代码的圈复杂度，识别过于复杂的函数
+// 分析 Go 代码的圈复杂度和认知复杂度，识别过于复杂、嵌套过深的函数
 type ComplexityAnalyzer struct {
-	*BaseTool
+	*TypedTool[ComplexityInput, *ComplexityResult]
 }
 
 // NewComplexityAnalyzer 创建复杂度分析器
 func NewComplexityAnalyzer() *ComplexityAnalyzer {
-	return &ComplexityAnalyzer{
-		BaseTool: NewBaseTool(
-			"complexity_analyzer",
-			"分析 Go 代码的圈复杂度，识别过于复杂的函数（圈复杂度 > 10）",
-			reflect.TypeOf(""),
-		),
-	}
+	ca := &ComplexityAnalyzer{}
+	ca.TypedTool = NewTypedTool[ComplexityInput, *ComplexityResult](
+		"complexity_analyzer",
+		"分析 Go 代码的圈复杂度和认知复杂度（按嵌套层数加权），识别过于复杂的函数（圈复杂度 > 10 或认知复杂度 > 15）",
+		ca,
+	)
+	return ca
+}
+
+// ComplexityInput 复杂度分析的输入：单个文件的代码，或整个目录
+type ComplexityInput struct {
+	Code      string `json:"input,omitempty"`     // 单文件代码（沿用字符串输入时的字段名 input）
+	File      string `json:"file,omitempty"`      // 代码所在的文件名，只用于结果展示
+	Directory string `json:"directory,omitempty"` // 目录路径，递归分析其中的 Go 文件（跳过 vendor、testdata 和 _test.go）
+	Top       int    `json:"top,omitempty"`       // 分析目录时列出的最复杂函数个数，默认 10
 }
 
-// Run 执行复杂度分析
-func (ca *ComplexityAnalyzer) Run(ctx context.Context, input any) (string, error) {
-	// 类型断言
+// ConvertInput 兼容代码字符串输入
+func (ca *ComplexityAnalyzer) ConvertInput(input any) (ComplexityInput, bool) {
 	code, ok := input.(string)
-	if !ok {
-		return "", fmt.Errorf("输入类型错误: 期望 string, 实际 %T", input)
+	return ComplexityInput{Code: code}, ok
+}
+
+// ValidateInput 验证输入：必须指定代码或目录之一
+func (ca *ComplexityAnalyzer) ValidateInput(input ComplexityInput) error {
+	if input.Code == "" && input.Directory == "" {
+		return ErrInvalidInput
+	}
+	if input.Top < 0 {
+		return fmt.Errorf("%w: top 不能为负数", ErrInvalidInput)
+	}
+	return nil
+}
+
+// Execute 执行复杂度分析
+func (ca *ComplexityAnalyzer) Execute(ctx context.Context, input ComplexityInput) (*ComplexityResult, error) {
+	if input.Directory != "" {
+		return ca.analyzeDirectory(ctx, input)
+	}
+
+	functionResults, _, err := analyzeFunctions(token.NewFileSet(), ai.NormalizeSource(input.Code), input.File)
+	if err != nil {
+		return nil, err
+	}
+	totalComplexity := 0
+	for _, fn := range functionResults {
+		totalComplexity += fn.Complexity
+	}
+
+	// 构建结果
+	result := ComplexityResult{
+		SchemaVersion: ComplexityResultSchemaVersion,
+		File:       input.File,
+		Total:      totalComplexity,
+		Functions:  functionResults,
+		Summary:    generateSummary(functionResults),
+		Statistics: calculateStatistics(functionResults),
 	}
 
-	// 创建文件集
-	fset := token.NewFileSet()
+	return &result, nil
+}
 
+// analyzeFunctions 解析一个文件的代码，计算每个函数的复杂度，同时返回包名
+func analyzeFunctions(fset *token.FileSet, code, filename string) ([]FunctionResult, string, error) {
 	// 解析 Go 代码
-	node, err := parser.ParseFile(fset, "", code, parser.ParseComments)
+	node, err := parser.ParseFile(fset, filename, code, parser.ParseComments)
 	if err != nil {
-		return "", fmt.Errorf("解析 Go 代码失败: %w", err)
+		return nil, "", fmt.Errorf("解析 Go 代码失败: %w", err)
 	}
 
 	// 收集所有函数
@@ -56,65 +100,61 @@ func (ca *ComplexityAnalyzer) Run(ctx context.Context, input any) (string, error
 
 	// 分析每个函数
 	var functionResults []FunctionResult
-	totalComplexity := 0
 
 	for _, fn := range functions {
 		// 计算复杂度
 		complexity := calculateComplexity(fn)
+		cognitive := calculateCognitiveComplexity(fn)
 
 		// 计算行数
 		line := fset.Position(fn.Pos()).Line
 		lines := calculateLines(fset, fn)
 
 		// 生成问题列表
-		issues := generateIssues(complexity, lines)
+		issues := generateIssues(complexity, cognitive, lines)
 
 		result := FunctionResult{
 			Name:       fn.Name.Name,
+			File:       filename,
 			Line:       line,
 			Complexity: complexity,
+			Cognitive:  cognitive,
 			Lines:      lines,
 			Issues:     issues,
 		}
 
 		functionResults = append(functionResults, result)
-		totalComplexity += complexity
 	}
 
-	// 构建结果
-	result := ComplexityResult{
-		File:       "",
-		Total:      totalComplexity,
-		Functions:  functionResults,
-		Summary:    generateSummary(functionResults),
-		Statistics: calculateStatistics(functionResults),
-	}
-
-	// 序列化为 JSON
-	jsonBytes, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return "", fmt.Errorf("序列化结果失败: %w", err)
-	}
-
-	return string(jsonBytes), nil
+	return functionResults, node.Name.Name, nil
 }
 
 // FunctionResult 单个函数的分析结果
 type FunctionResult struct {
 	Name       string   `json:"name"`       // 函数名
+	File       string   `json:"file,omitempty"` // 所在文件（提供了文件名或分析目录时）
 	Line       int      `json:"line"`       // 起始行号
 	Complexity int      `json:"complexity"` // 圈复杂度
+	Cognitive  int      `json:"cognitive"`  // 认知复杂度（嵌套的分支按层数加权）
 	Lines      int      `json:"lines"`      // 函数行数
 	Issues     []string `json:"issues"`     // 问题列表
 }
 
 // ComplexityResult 完整的分析结果
 type ComplexityResult struct {
+	SchemaVersion int            `json:"schema_version"` // 结果结构版本，见 ComplexityResultSchemaVersion
 	File       string           `json:"file"`       // 文件名（如果提供）
 	Total      int              `json:"total"`      // 总复杂度
 	Functions  []FunctionResult `json:"functions"`  // 所有函数
 	Summary    string           `json:"summary"`    // 摘要
 	Statistics Statistics       `json:"statistics"` // 统计信息
+
+	// 以下字段只在分析目录时输出
+	TotalFiles   int                 `json:"total_files,omitempty"`   // 分析的 Go 文件数
+	ErrorFiles   []FileStatus        `json:"error_files,omitempty"`   // 读取或解析失败的文件
+	Files        []FileComplexity    `json:"files,omitempty"`         // 每个文件的汇总，按总复杂度从高到低
+	Packages     []PackageComplexity `json:"packages,omitempty"`      // 每个包（目录）的汇总，按总复杂度从高到低
+	TopFunctions []FunctionResult    `json:"top_functions,omitempty"` // 整个项目中最复杂的函数
 }
 
 // Statistics 统计信息
@@ -187,8 +227,8 @@ func calculateLines(fset *token.FileSet, fn *ast.FuncDecl) int {
 	return end - start + 1
 }
 
-// generateIssues 根据复杂度和行数生成问题列表
-func generateIssues(complexity, lines int) []string {
+// generateIssues 根据圈复杂度、认知复杂度和行数生成问题列表
+func generateIssues(complexity, cognitive, lines int) []string {
 	var issues []string
 
 	// 复杂度检查
@@ -200,6 +240,13 @@ func generateIssues(complexity, lines int) []string {
 		issues = append(issues, "⚠️ 圈复杂度偏高（>10），可能需要重构")
 	}
 
+	// 认知复杂度检查：分支不多但嵌套很深的函数圈复杂度不高，靠它识别
+	if cognitive > 30 {
+		issues = append(issues, "🧠 认知复杂度过高（>30），嵌套过深，必须简化")
+	} else if cognitive > 15 {
+		issues = append(issues, "🧠 认知复杂度偏高（>15），建议提前返回或拆分嵌套分支")
+	}
+
 	// 行数检查（辅助指标）
 	if lines > 100 {
 		issues = append(issues, "📏 函数过长（>100行），建议拆分")
@@ -221,15 +268,17 @@ func generateIssues(complexity, lines int) []string {
 // generateSummary 生成摘要信息
 func generateSummary(results []FunctionResult) string {
 	if len(results) == 0 {
-		return "未找到任何函数"
+		return i18n.T("complexity.summary.no_functions")
 	}
 
 	// 计算平均复杂度
-	total := 0
+	total, cognitive := 0, 0
 	for _, r := range results {
 		total += r.Complexity
+		cognitive += r.Cognitive
 	}
 	avg := float64(total) / float64(len(results))
+	avgCognitive := float64(cognitive) / float64(len(results))
 
 	// 统计问题函数
 	problemCount := 0
@@ -240,12 +289,12 @@ func generateSummary(results []FunctionResult) string {
 	}
 
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("分析完成，共 %d 个函数，平均复杂度 %.1f", len(results), avg))
+	sb.WriteString(i18n.T("complexity.summary.analyzed", len(results), avg, avgCognitive))
 
 	if problemCount > 0 {
-		sb.WriteString(fmt.Sprintf("，发现 %d 个函数存在潜在问题", problemCount))
+		sb.WriteString(i18n.T("complexity.summary.found", problemCount))
 	} else {
-		sb.WriteString("，所有函数复杂度正常 ✅")
+		sb.WriteString(i18n.T("complexity.summary.none"))
 	}
 
 	return sb.String()
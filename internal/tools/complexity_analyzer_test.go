@@ -1,6 +1,9 @@
 package tools
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -16,4 +19,43 @@ func TestNewComplexityAnalyzer(t *testing.T) {
 	}
 }
 
+// 测试分析目录：按文件、包汇总，跳过测试文件和 vendor，并给出最复杂函数的排名
+func TestComplexityAnalyzer_Directory(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":           "package main\n\nfunc a(x int) int {\n\tif x > 0 {\n\t\treturn 1\n\t}\n\treturn 0\n}\n",
+		"util/util.go":      "package util\n\nfunc b(x int) int {\n\tfor i := 0; i < x; i++ {\n\t\tif i > 2 && x > 3 {\n\t\t\treturn i\n\t\t}\n\t}\n\treturn 0\n}\n\nfunc c() {}\n",
+		"util/util_test.go": "package util\n\nfunc d(x int) { if x > 0 { if x > 1 { if x > 2 {} } } }\n",
+		"vendor/v/v.go":     "package v\n\nfunc e(x int) { if x > 0 { if x > 1 { if x > 2 {} } } }\n",
+		"broken/broken.go":  "package broken\n\nfunc {\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
 
+	result, err := NewComplexityAnalyzer().Execute(context.Background(), ComplexityInput{Directory: dir, Top: 2})
+	if err != nil {
+		t.Fatalf("分析失败: %v", err)
+	}
+	if result.TotalFiles != 3 || len(result.ErrorFiles) != 1 || len(result.Functions) != 3 {
+		t.Fatalf("文件统计错误: total_files=%d errors=%d functions=%d", result.TotalFiles, len(result.ErrorFiles), len(result.Functions))
+	}
+	if len(result.Packages) != 2 || result.Packages[0].Package != "util" || result.Packages[0].Files != 1 || result.Packages[0].Total != 5 {
+		t.Errorf("包汇总错误: %+v", result.Packages)
+	}
+	if len(result.Files) != 2 || result.Files[0].Max != 4 {
+		t.Errorf("文件汇总错误: %+v", result.Files)
+	}
+	if len(result.TopFunctions) != 2 || result.TopFunctions[0].Name != "b" || result.TopFunctions[1].Name != "a" {
+		t.Errorf("最复杂函数排名错误: %+v", result.TopFunctions)
+	}
+	if result.Total != 7 {
+		t.Errorf("总复杂度 = %d, want 7", result.Total)
+	}
+}
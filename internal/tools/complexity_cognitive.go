@@ -0,0 +1,162 @@
+package tools
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// calculateCognitiveComplexity 计算函数的认知复杂度（SonarSource 定义）
+// 与圈复杂度不同，嵌套的分支按所在的嵌套层数额外加分，深层嵌套的代码得分明显更高：
+//   - if、switch、select、for 以及 goto、带标签的 break/continue 各 +1
+//   - if、switch、select、for 位于嵌套结构内时再加嵌套层数
+//   - else if、else 各 +1，不加嵌套层数
+//   - 连续的同一种逻辑运算符（a && b && c）算一次，运算符每切换一次 +1
+//   - 直接递归调用 +1
+//
+// 分支体、循环体和函数字面量使嵌套层数加一；switch 的 case 不单独计分
+func calculateCognitiveComplexity(fn *ast.FuncDecl) int {
+	if fn.Body == nil {
+		return 0
+	}
+	c := &cognitiveCounter{name: fn.Name.Name}
+	if fn.Recv != nil && len(fn.Recv.List) > 0 && len(fn.Recv.List[0].Names) > 0 {
+		c.recv = fn.Recv.List[0].Names[0].Name
+	}
+	c.walk(fn.Body, 0)
+	return c.score
+}
+
+// cognitiveCounter 累计一个函数的认知复杂度
+type cognitiveCounter struct {
+	name  string // 函数或方法名，用于识别递归调用
+	recv  string // 方法接收者变量名，函数为空
+	score int
+}
+
+// walk 遍历 n 的子树，nesting 为 n 所在的嵌套层数
+func (c *cognitiveCounter) walk(n ast.Node, nesting int) {
+	if n == nil {
+		return
+	}
+	ast.Inspect(n, func(node ast.Node) bool {
+		switch s := node.(type) {
+		case *ast.IfStmt:
+			c.ifStmt(s, nesting, false)
+			return false
+		case *ast.ForStmt:
+			c.score += 1 + nesting
+			c.walkAll(nesting, s.Init, s.Cond, s.Post)
+			c.walk(s.Body, nesting+1)
+			return false
+		case *ast.RangeStmt:
+			c.score += 1 + nesting
+			c.walk(s.X, nesting)
+			c.walk(s.Body, nesting+1)
+			return false
+		case *ast.SwitchStmt:
+			c.score += 1 + nesting
+			c.walkAll(nesting, s.Init, s.Tag)
+			c.walk(s.Body, nesting+1)
+			return false
+		case *ast.TypeSwitchStmt:
+			c.score += 1 + nesting
+			c.walkAll(nesting, s.Init, s.Assign)
+			c.walk(s.Body, nesting+1)
+			return false
+		case *ast.SelectStmt:
+			c.score += 1 + nesting
+			c.walk(s.Body, nesting+1)
+			return false
+		case *ast.FuncLit:
+			c.walk(s.Body, nesting+1)
+			return false
+		case *ast.BranchStmt:
+			if s.Tok == token.GOTO || s.Label != nil {
+				c.score++
+			}
+		case *ast.BinaryExpr:
+			if s.Op != token.LAND && s.Op != token.LOR {
+				return true
+			}
+			var ops []token.Token
+			var operands []ast.Expr
+			flattenLogical(s, &ops, &operands)
+			for i, op := range ops {
+				if i == 0 || op != ops[i-1] {
+					c.score++
+				}
+			}
+			for _, operand := range operands {
+				c.walk(operand, nesting)
+			}
+			return false
+		case *ast.CallExpr:
+			if c.isRecursiveCall(s) {
+				c.score++
+			}
+		}
+		return true
+	})
+}
+
+// walkAll 依次遍历多个节点（跳过为空的）
+func (c *cognitiveCounter) walkAll(nesting int, nodes ...ast.Node) {
+	for _, n := range nodes {
+		if n != nil {
+			c.walk(n, nesting)
+		}
+	}
+}
+
+// ifStmt if 语句计分：else if 和 else 只 +1，不加嵌套层数，但其中的代码仍按 if 所在层数加一嵌套
+func (c *cognitiveCounter) ifStmt(s *ast.IfStmt, nesting int, elseIf bool) {
+	if elseIf {
+		c.score++
+	} else {
+		c.score += 1 + nesting
+	}
+	if s.Init != nil {
+		c.walk(s.Init, nesting)
+	}
+	c.walk(s.Cond, nesting)
+	c.walk(s.Body, nesting+1)
+
+	switch e := s.Else.(type) {
+	case *ast.IfStmt:
+		c.ifStmt(e, nesting, true)
+	case *ast.BlockStmt:
+		c.score++
+		c.walk(e, nesting+1)
+	}
+}
+
+// isRecursiveCall 是否直接调用函数自身：函数按名字调用，方法通过接收者变量调用同名方法
+func (c *cognitiveCounter) isRecursiveCall(call *ast.CallExpr) bool {
+	switch f := call.Fun.(type) {
+	case *ast.Ident:
+		return c.recv == "" && f.Name == c.name
+	case *ast.SelectorExpr:
+		x, ok := f.X.(*ast.Ident)
+		return ok && c.recv != "" && x.Name == c.recv && f.Sel.Name == c.name
+	}
+	return false
+}
+
+// flattenLogical 按出现顺序展开逻辑运算（穿过括号），ops 为运算符序列，operands 为非逻辑运算的操作数
+func flattenLogical(e ast.Expr, ops *[]token.Token, operands *[]ast.Expr) {
+	switch x := e.(type) {
+	case *ast.ParenExpr:
+		if inner, ok := x.X.(*ast.BinaryExpr); ok && (inner.Op == token.LAND || inner.Op == token.LOR) {
+			flattenLogical(inner, ops, operands)
+			return
+		}
+	case *ast.BinaryExpr:
+		if x.Op == token.LAND || x.Op == token.LOR {
+			flattenLogical(x.X, ops, operands)
+			*ops = append(*ops, x.Op)
+			flattenLogical(x.Y, ops, operands)
+			return
+		}
+	}
+	*operands = append(*operands, e)
+}
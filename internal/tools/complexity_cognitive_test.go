@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+// 测试认知复杂度：嵌套按层数加权，else/else if 不加层数，逻辑运算符按序列计分，递归和跳转 +1
+func TestCalculateCognitiveComplexity(t *testing.T) {
+	src := `package demo
+
+// 平铺的分支：圈复杂度与嵌套版本相同，认知复杂度更低
+func flat(a, b, c bool) int {
+	if a { // +1
+		return 1
+	}
+	if b { // +1
+		return 2
+	}
+	if c { // +1
+		return 3
+	}
+	return 0
+}
+
+func nested(a, b, c bool) int {
+	if a { // +1
+		if b { // +2
+			if c { // +3
+				return 3
+			}
+		}
+	}
+	return 0
+}
+
+func chain(items []int, ok bool) int {
+	total := 0
+	for _, v := range items { // +1
+		if v > 0 && ok || v < -10 { // +2（嵌套）+2（&& 与 || 两个序列）
+			total += v
+		} else if v == 0 { // +1
+			continue
+		} else { // +1
+			break
+		}
+	}
+	return total
+}
+
+func sum(n int) int {
+	if n == 0 { // +1
+		return 0
+	}
+	return n + sum(n-1) // +1 递归
+}
+
+func worker(ch chan int) {
+	go func() {
+		for { // +2（函数字面量内嵌套一层）
+			select { // +3
+			case v := <-ch:
+				switch { // +4
+				case v > 0:
+				}
+			}
+		}
+	}()
+outer:
+	for i := 0; i < 3; i++ { // +1
+		for j := 0; j < 3; j++ { // +2
+			if j == i { // +3
+				continue outer // +1
+			}
+		}
+	}
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "demo.go", src, 0)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	want := map[string]int{"flat": 3, "nested": 6, "chain": 7, "sum": 2, "worker": 16}
+	for _, decl := range file.Decls {
+		fn := decl.(*ast.FuncDecl)
+		if got := calculateCognitiveComplexity(fn); got != want[fn.Name.Name] {
+			t.Errorf("%s 的认知复杂度应该为 %d，实际 %d", fn.Name.Name, want[fn.Name.Name], got)
+		}
+	}
+
+	// 平铺和嵌套的圈复杂度相同，认知复杂度区分出嵌套
+	results, _, err := analyzeFunctions(token.NewFileSet(), src, "demo.go")
+	if err != nil {
+		t.Fatalf("分析失败: %v", err)
+	}
+	if results[0].Complexity != results[1].Complexity || results[0].Cognitive >= results[1].Cognitive {
+		t.Errorf("嵌套版本的认知复杂度应该更高: %+v %+v", results[0], results[1])
+	}
+}
@@ -0,0 +1,161 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// defaultComplexityTop 分析目录时默认列出的最复杂函数个数
+const defaultComplexityTop = 10
+
+// FileComplexity 单个文件的复杂度汇总
+type FileComplexity struct {
+	File      string  `json:"file"`
+	Package   string  `json:"package"`   // 所在包（相对目录）
+	Functions int     `json:"functions"` // 函数数
+	Total     int     `json:"total"`     // 总复杂度
+	Average   float64 `json:"average"`   // 平均复杂度
+	Max       int     `json:"max"`       // 最复杂函数的复杂度
+}
+
+// PackageComplexity 单个包（目录）的复杂度汇总
+type PackageComplexity struct {
+	Package   string  `json:"package"`   // 相对分析目录的路径，根目录为 "."
+	Name      string  `json:"name"`      // 包名
+	Files     int     `json:"files"`     // 文件数
+	Functions int     `json:"functions"` // 函数数
+	Total     int     `json:"total"`     // 总复杂度
+	Average   float64 `json:"average"`   // 平均复杂度
+	Max       int     `json:"max"`       // 最复杂函数的复杂度
+}
+
+// analyzeDirectory 逐个分析目录中的 Go 文件，按文件、包汇总，并给出整个项目中最复杂的函数
+// 读取或解析失败的文件记入 ErrorFiles，不影响其他文件
+func (ca *ComplexityAnalyzer) analyzeDirectory(ctx context.Context, input ComplexityInput) (*ComplexityResult, error) {
+	paths, err := collectGoFiles(ctx, input.Directory)
+	if err != nil {
+		return nil, fmt.Errorf("文件收集失败: %w", err)
+	}
+
+	fset := token.NewFileSet()
+	functions := make([]FunctionResult, 0)
+	files := make([]FileComplexity, 0)
+	packages := make(map[string]*PackageComplexity)
+	var errorFiles []FileStatus
+	for _, path := range paths {
+		if !complexityTarget(input.Directory, path) {
+			continue
+		}
+		if ctx.Err() != nil {
+			return nil, context.Cause(ctx)
+		}
+		content, err := readSource(path)
+		if err != nil {
+			errorFiles = append(errorFiles, FileStatus{Path: path, Language: "go", Status: "error", Reason: fmt.Sprintf("读取文件失败: %v", err)})
+			continue
+		}
+		fileFunctions, name, err := analyzeFunctions(fset, content, path)
+		if err != nil {
+			errorFiles = append(errorFiles, FileStatus{Path: path, Language: "go", Status: "error", Reason: err.Error()})
+			continue
+		}
+		functions = append(functions, fileFunctions...)
+
+		pkgPath := packagePath(input.Directory, path)
+		file := FileComplexity{File: path, Package: pkgPath, Functions: len(fileFunctions)}
+		for _, fn := range fileFunctions {
+			file.Total += fn.Complexity
+			file.Max = max(file.Max, fn.Complexity)
+		}
+		file.Average = averageComplexity(file.Total, file.Functions)
+		files = append(files, file)
+
+		pkg := packages[pkgPath]
+		if pkg == nil {
+			pkg = &PackageComplexity{Package: pkgPath, Name: name}
+			packages[pkgPath] = pkg
+		}
+		pkg.Files++
+		pkg.Functions += file.Functions
+		pkg.Total += file.Total
+		pkg.Max = max(pkg.Max, file.Max)
+	}
+
+	pkgList := make([]PackageComplexity, 0, len(packages))
+	for _, pkg := range packages {
+		pkg.Average = averageComplexity(pkg.Total, pkg.Functions)
+		pkgList = append(pkgList, *pkg)
+	}
+	slices.SortFunc(files, func(a, b FileComplexity) int {
+		return cmp.Or(cmp.Compare(b.Total, a.Total), cmp.Compare(a.File, b.File))
+	})
+	slices.SortFunc(pkgList, func(a, b PackageComplexity) int {
+		return cmp.Or(cmp.Compare(b.Total, a.Total), cmp.Compare(a.Package, b.Package))
+	})
+
+	total := 0
+	for _, fn := range functions {
+		total += fn.Complexity
+	}
+	top := input.Top
+	if top == 0 {
+		top = defaultComplexityTop
+	}
+	ranked := slices.Clone(functions)
+	slices.SortStableFunc(ranked, func(a, b FunctionResult) int {
+		return cmp.Compare(b.Complexity, a.Complexity)
+	})
+
+	return &ComplexityResult{
+		SchemaVersion: ComplexityResultSchemaVersion,
+		File:          input.Directory,
+		Total:         total,
+		Functions:     functions,
+		Summary:       fmt.Sprintf("%d 个文件、%d 个包：", len(files), len(pkgList)) + generateSummary(functions),
+		Statistics:    calculateStatistics(functions),
+		TotalFiles:    len(files) + len(errorFiles),
+		ErrorFiles:    errorFiles,
+		Files:         files,
+		Packages:      pkgList,
+		TopFunctions:  ranked[:min(top, len(ranked))],
+	}, nil
+}
+
+// complexityTarget 是否计入复杂度：跳过测试文件以及 vendor、testdata 下的代码
+func complexityTarget(dir, path string) bool {
+	if strings.HasSuffix(path, "_test.go") {
+		return false
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		rel = path
+	}
+	for _, part := range strings.Split(filepath.ToSlash(filepath.Dir(rel)), "/") {
+		if part == "vendor" || part == "testdata" {
+			return false
+		}
+	}
+	return true
+}
+
+// packagePath 文件所在目录相对分析目录的路径，根目录为 "."
+func packagePath(dir, path string) string {
+	rel, err := filepath.Rel(dir, filepath.Dir(path))
+	if err != nil {
+		return filepath.ToSlash(filepath.Dir(path))
+	}
+	return filepath.ToSlash(rel)
+}
+
+// averageComplexity 平均复杂度，没有函数时为 0
+func averageComplexity(total, functions int) float64 {
+	if functions == 0 {
+		return 0
+	}
+	return float64(total) / float64(functions)
+}
@@ -0,0 +1,30 @@
+package tools
+
+import "fmt"
+
+// 置信度级别
+const (
+	ConfidenceHigh   = "high"
+	ConfidenceMedium = "medium"
+	ConfidenceLow    = "low"
+)
+
+// confidenceRanks 置信度从低到高的排序
+var confidenceRanks = map[string]int{
+	ConfidenceLow:    1,
+	ConfidenceMedium: 2,
+	ConfidenceHigh:   3,
+}
+
+// validateMinConfidence 检查最低置信度参数，空值表示不过滤
+func validateMinConfidence(min string) error {
+	if min != "" && confidenceRanks[min] == 0 {
+		return fmt.Errorf("%w: 未知的置信度 %q（可选 high、medium、low）", ErrInvalidInput, min)
+	}
+	return nil
+}
+
+// meetsConfidence 置信度是否不低于 min；min 为空时总是满足
+func meetsConfidence(confidence, min string) bool {
+	return min == "" || confidenceRanks[confidence] >= confidenceRanks[min]
+}
@@ -0,0 +1,203 @@
+package tools
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CustomRuleFile YAML 自定义安全规则文件
+// 团队可以用它补充组织内部的规则（禁用的包、内部密钥的变量名等），不需要重新编译
+type CustomRuleFile struct {
+	Rules []CustomRuleSpec `yaml:"rules"`
+}
+
+// CustomRuleSpec 一条自定义规则，call、import、identifier 三种匹配方式必须且只能选一种
+type CustomRuleSpec struct {
+	ID         string `yaml:"id"`         // 规则 ID，不能与内置规则重复
+	Name       string `yaml:"name"`       // 规则名称，默认同 ID
+	Category   string `yaml:"category"`   // 问题类别，默认 Custom
+	Severity   string `yaml:"severity"`   // Low、Medium、High、Critical
+	Message    string `yaml:"message"`    // 问题描述
+	Suggestion string `yaml:"suggestion"` // 修复建议
+
+	Call          string `yaml:"call"`           // 被调用的函数，如 exec.Command、unsafe.*（通配符同 path.Match）
+	Import        string `yaml:"import"`         // 导入路径，如 github.com/pkg/errors、golang.org/x/exp/*
+	Identifier    string `yaml:"identifier"`     // 被赋值或声明的变量名（正则表达式）
+	StringLiteral bool   `yaml:"string_literal"` // 与 identifier 一起使用：只在赋值为字符串字面量时报告
+}
+
+// CustomRule 由 YAML 定义的安全规则
+type CustomRule struct {
+	spec       CustomRuleSpec
+	identifier *regexp.Regexp
+}
+
+func (r *CustomRule) ID() string          { return r.spec.ID }
+func (r *CustomRule) Name() string        { return r.spec.Name }
+func (r *CustomRule) Category() string    { return r.spec.Category }
+func (r *CustomRule) Severity() string    { return r.spec.Severity }
+func (r *CustomRule) Description() string { return r.spec.Message }
+func (r *CustomRule) Suggestion() string  { return r.spec.Suggestion }
+
+// Match 按规则声明的匹配方式检查节点
+func (r *CustomRule) Match(node ast.Node, ctx *RuleContext) bool {
+	switch {
+	case r.spec.Call != "":
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		name := calleeName(call.Fun)
+		matched, _ := path.Match(r.spec.Call, name)
+		return name != "" && matched
+	case r.spec.Import != "":
+		spec, ok := node.(*ast.ImportSpec)
+		if !ok {
+			return false
+		}
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return false
+		}
+		matched, _ := path.Match(r.spec.Import, importPath)
+		return matched
+	case r.identifier != nil:
+		return r.matchIdentifier(node)
+	}
+	return false
+}
+
+// matchIdentifier 检查赋值语句和 var/const 声明左侧的变量名
+func (r *CustomRule) matchIdentifier(node ast.Node) bool {
+	var names []*ast.Ident
+	var values []ast.Expr
+	switch n := node.(type) {
+	case *ast.AssignStmt:
+		for _, lhs := range n.Lhs {
+			ident, _ := lhs.(*ast.Ident)
+			names = append(names, ident)
+		}
+		values = n.Rhs
+	case *ast.ValueSpec:
+		names = n.Names
+		values = n.Values
+	default:
+		return false
+	}
+	for i, ident := range names {
+		if ident == nil || ident.Name == "_" || !r.identifier.MatchString(ident.Name) {
+			continue
+		}
+		if !r.spec.StringLiteral {
+			return true
+		}
+		if len(values) == len(names) {
+			if lit, ok := values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// calleeName 被调用函数的名称：本包函数为 Name，包函数或方法为 X.Name，其他形式返回空
+func calleeName(fun ast.Expr) string {
+	switch f := fun.(type) {
+	case *ast.Ident:
+		return f.Name
+	case *ast.SelectorExpr:
+		if x, ok := f.X.(*ast.Ident); ok {
+			return x.Name + "." + f.Sel.Name
+		}
+	}
+	return ""
+}
+
+// LoadCustomRules 读取并校验 YAML 自定义规则文件
+// 规则 ID 不能与内置规则或其他文件中的规则重复
+func LoadCustomRules(paths ...string) ([]*CustomRule, error) {
+	var rules []*CustomRule
+	seen := make(map[string]string)
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("读取自定义规则失败: %w", err)
+		}
+		var file CustomRuleFile
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("%w: 解析自定义规则 %s 失败: %v", ErrInvalidInput, p, err)
+		}
+		for i, spec := range file.Rules {
+			rule, err := newCustomRule(spec)
+			if err != nil {
+				return nil, fmt.Errorf("%s 第 %d 条规则: %w", p, i+1, err)
+			}
+			if prev, ok := seen[rule.ID()]; ok {
+				return nil, fmt.Errorf("%w: %s 中的规则 %s 与 %s 重复", ErrInvalidInput, p, rule.ID(), prev)
+			}
+			seen[rule.ID()] = p
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// newCustomRule 校验规则定义并补全默认值
+func newCustomRule(spec CustomRuleSpec) (*CustomRule, error) {
+	spec.ID = strings.TrimSpace(spec.ID)
+	if spec.ID == "" {
+		return nil, fmt.Errorf("%w: 缺少 id", ErrInvalidInput)
+	}
+	if _, ok := LookupRuleDoc(spec.ID); ok {
+		return nil, fmt.Errorf("%w: 规则 %s 与内置规则重复", ErrInvalidInput, spec.ID)
+	}
+	if strings.TrimSpace(spec.Message) == "" {
+		return nil, fmt.Errorf("%w: 规则 %s 缺少 message", ErrInvalidInput, spec.ID)
+	}
+	level, err := ParseSeverity(spec.Severity)
+	if err != nil {
+		return nil, fmt.Errorf("规则 %s: %w", spec.ID, err)
+	}
+	spec.Severity = level
+	if spec.Name == "" {
+		spec.Name = spec.ID
+	}
+	if spec.Category == "" {
+		spec.Category = CategoryCustom
+	}
+	spec.Category = NormalizeCategory(spec.Category)
+
+	matchers := 0
+	for _, m := range []string{spec.Call, spec.Import, spec.Identifier} {
+		if m != "" {
+			matchers++
+		}
+	}
+	if matchers != 1 {
+		return nil, fmt.Errorf("%w: 规则 %s 必须且只能指定 call、import、identifier 中的一种", ErrInvalidInput, spec.ID)
+	}
+	for _, pattern := range []string{spec.Call, spec.Import} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("%w: 规则 %s 的匹配模式 %q 无效", ErrInvalidInput, spec.ID, pattern)
+		}
+	}
+	if spec.StringLiteral && spec.Identifier == "" {
+		return nil, fmt.Errorf("%w: 规则 %s 的 string_literal 只能与 identifier 一起使用", ErrInvalidInput, spec.ID)
+	}
+
+	rule := &CustomRule{spec: spec}
+	if spec.Identifier != "" {
+		if rule.identifier, err = regexp.Compile(spec.Identifier); err != nil {
+			return nil, fmt.Errorf("%w: 规则 %s 的 identifier 不是有效的正则表达式: %v", ErrInvalidInput, spec.ID, err)
+		}
+	}
+	return rule, nil
+}
@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const customRulesYAML = `rules:
+  - id: ORG001
+    severity: medium
+    message: 禁止使用 github.com/pkg/errors
+    import: github.com/pkg/errors
+  - id: ORG002
+    category: Policy
+    severity: High
+    message: 禁止直接执行外部命令
+    call: exec.Command*
+  - id: ORG003
+    severity: Critical
+    message: 内部密钥硬编码
+    identifier: (?i)^corp_?secret
+    string_literal: true
+`
+
+func writeCustomRules(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// 测试自定义规则的加载和匹配
+func TestCustomRules(t *testing.T) {
+	path := writeCustomRules(t, customRulesYAML)
+	rules, err := LoadCustomRules(path)
+	if err != nil {
+		t.Fatalf("加载自定义规则失败: %v", err)
+	}
+	if len(rules) != 3 || rules[0].Severity() != "Medium" || rules[0].Category() != "Custom" || rules[0].Name() != "ORG001" {
+		t.Fatalf("规则默认值或严重程度规范化错误: %+v", rules[0].spec)
+	}
+
+	config := DefaultToolConfig("security_scanner")
+	config.CustomConfig[CustomConfigCustomRules] = []string{path}
+	config.CustomConfig[CustomConfigRuleSeverity] = map[string]string{"ORG002": "Low"}
+	tm := NewToolManager(nil)
+	scanner := NewSecurityScanner()
+	if err := tm.Register(scanner, config); err != nil {
+		t.Fatalf("注册失败: %v", err)
+	}
+
+	code := `package main
+
+import (
+	"os/exec"
+
+	"github.com/pkg/errors"
+)
+
+var corpSecret = "s3cr3t"
+
+func main() {
+	corp_secret := load()
+	_ = exec.CommandContext(nil, "ls")
+	_ = errors.New(corp_secret)
+}
+`
+	result, err := scanner.Execute(context.Background(), SecurityScanInput{Code: code, File: "main.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string][]SecurityIssue)
+	for _, issue := range result.Issues {
+		got[issue.RuleID] = append(got[issue.RuleID], issue)
+	}
+	if len(got["ORG001"]) != 1 || got["ORG001"][0].Line != 6 {
+		t.Errorf("ORG001 应在第 6 行报告一次: %+v", got["ORG001"])
+	}
+	if len(got["ORG002"]) != 1 || got["ORG002"][0].Severity != "Low" || got["ORG002"][0].Category != "Policy" {
+		t.Errorf("ORG002 应报告一次且严重程度被覆盖为 Low: %+v", got["ORG002"])
+	}
+	// corp_secret := load() 不是字符串字面量，不报告
+	if len(got["ORG003"]) != 1 || got["ORG003"][0].Line != 9 {
+		t.Errorf("ORG003 应只在第 9 行报告: %+v", got["ORG003"])
+	}
+}
+
+// 测试无效的自定义规则
+func TestLoadCustomRules_Invalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"缺少 id":      "rules:\n  - severity: High\n    message: m\n    call: f\n",
+		"与内置规则重复":    "rules:\n  - id: G101\n    severity: High\n    message: m\n    call: f\n",
+		"缺少 message": "rules:\n  - id: X1\n    severity: High\n    call: f\n",
+		"严重程度无效":     "rules:\n  - id: X1\n    severity: urgent\n    message: m\n    call: f\n",
+		"没有匹配方式":     "rules:\n  - id: X1\n    severity: High\n    message: m\n",
+		"多种匹配方式":     "rules:\n  - id: X1\n    severity: High\n    message: m\n    call: f\n    import: p\n",
+		"正则无效":       "rules:\n  - id: X1\n    severity: High\n    message: m\n    identifier: '('\n",
+		"通配模式无效":     "rules:\n  - id: X1\n    severity: High\n    message: m\n    call: '['\n",
+		"字面量限制无匹配对象": "rules:\n  - id: X1\n    severity: High\n    message: m\n    call: f\n    string_literal: true\n",
+		"ID 重复":      "rules:\n  - id: X1\n    severity: High\n    message: m\n    call: f\n  - id: X1\n    severity: Low\n    message: m\n    call: g\n",
+		"YAML 格式错误":  "rules: [",
+	} {
+		if _, err := LoadCustomRules(writeCustomRules(t, content)); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("%s: 应该返回 ErrInvalidInput，实际 %v", name, err)
+		}
+	}
+
+	// 配置中引用自定义规则 ID 时需要先加载对应文件
+	if _, err := RuleSettingsFromConfig(map[string]any{CustomConfigDisabledRules: []string{"ORG001"}}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("未加载的自定义规则应视为未知规则，实际 %v", err)
+	}
+}
@@ -0,0 +1,330 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// DeadCodeAnalyzer 死代码分析器
+// 基于 go/types 找出从未被引用的未导出函数、方法、常量和变量，以及 return/panic 之后不可达的语句
+type DeadCodeAnalyzer struct {
+	*TypedTool[DeadCodeRequest, *DeadCodeResult]
+}
+
+// NewDeadCodeAnalyzer 创建死代码分析器
+func NewDeadCodeAnalyzer() *DeadCodeAnalyzer {
+	da := &DeadCodeAnalyzer{}
+	da.TypedTool = NewTypedTool[DeadCodeRequest, *DeadCodeResult](
+		"deadcode_analyzer",
+		"找出从未被引用的未导出函数、方法、常量和变量，以及 return/panic 之后不可达的代码（基于类型信息）",
+		da,
+	)
+	return da
+}
+
+// DeadCodeRequest 死代码分析请求
+type DeadCodeRequest struct {
+	Directory string   `json:"directory" jsonschema:"required"` // 模块内的目录
+	Patterns  []string `json:"patterns,omitempty"`              // 包模式，默认 ./...
+}
+
+// 死代码的种类
+const (
+	deadKindFunc        = "func"
+	deadKindMethod      = "method"
+	deadKindConst       = "const"
+	deadKindVar         = "var"
+	deadKindUnreachable = "unreachable"
+)
+
+// DeadCodeFinding 一处死代码
+type DeadCodeFinding struct {
+	Package string `json:"package"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Kind    string `json:"kind"` // func, method, const, var, unreachable
+	Name    string `json:"name"` // 方法为 Type.method；不可达代码为之前的 return/panic
+}
+
+// DeadCodeResult 死代码分析结果
+type DeadCodeResult struct {
+	Findings    []DeadCodeFinding `json:"findings"`
+	Unused      int               `json:"unused"`           // 未被引用的标识符
+	Unreachable int               `json:"unreachable"`      // 不可达的语句
+	Errors      []string          `json:"errors,omitempty"` // 包加载/类型错误
+	Summary     string            `json:"summary"`
+}
+
+// ValidateInput 验证输入参数
+func (da *DeadCodeAnalyzer) ValidateInput(req DeadCodeRequest) error {
+	if req.Directory == "" {
+		return fmt.Errorf("必须指定 Directory")
+	}
+	return nil
+}
+
+// Execute 执行死代码分析
+func (da *DeadCodeAnalyzer) Execute(ctx context.Context, req DeadCodeRequest) (*DeadCodeResult, error) {
+	return AnalyzeDeadCode(ctx, req)
+}
+
+// AnalyzeDeadCode 加载目录下的包并找出死代码
+// 不加载测试文件：只被测试引用的标识符同样会被报告
+func AnalyzeDeadCode(ctx context.Context, req DeadCodeRequest) (*DeadCodeResult, error) {
+	pkgs, err := loadTypedPackages(ctx, req.Directory, req.Patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DeadCodeResult{Findings: []DeadCodeFinding{}, Errors: packageErrors(pkgs)}
+	for _, pkg := range pkgs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// 类型检查失败的包引用信息不完整，不参与分析，避免误报
+		if pkg.TypesInfo == nil || pkg.Types == nil || len(pkg.Errors) > 0 {
+			continue
+		}
+		result.Findings = append(result.Findings, unusedIdentifiers(pkg)...)
+		for _, file := range pkg.Syntax {
+			if ast.IsGenerated(file) {
+				continue
+			}
+			result.Findings = append(result.Findings, unreachableStatements(pkg, file)...)
+		}
+	}
+
+	sort.SliceStable(result.Findings, func(i, j int) bool {
+		a, b := result.Findings[i], result.Findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	for _, finding := range result.Findings {
+		if finding.Kind == deadKindUnreachable {
+			result.Unreachable++
+		} else {
+			result.Unused++
+		}
+	}
+	result.Summary = fmt.Sprintf("%d 个包，%d 个未被引用的未导出标识符，%d 处不可达代码",
+		len(pkgs), result.Unused, result.Unreachable)
+	return result, nil
+}
+
+// deadCandidate 可能未被引用的声明；span 为声明自身的范围，范围内的引用（递归调用）不算
+type deadCandidate struct {
+	obj  types.Object
+	kind string
+	name string
+	span [2]token.Pos
+}
+
+// unusedIdentifiers 找出包内从未被引用的未导出函数、方法、常量和变量
+// 未导出标识符只能在包内引用，因此只需要统计本包的 Uses
+func unusedIdentifiers(pkg *packages.Package) []DeadCodeFinding {
+	candidates := make(map[types.Object]*deadCandidate)
+	for _, file := range pkg.Syntax {
+		if ast.IsGenerated(file) {
+			continue
+		}
+		for _, decl := range file.Decls {
+			collectDeadCandidates(pkg, decl, candidates)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	for ident, obj := range pkg.TypesInfo.Uses {
+		// 泛型类型实例化后的方法指向原始声明
+		if fn, ok := obj.(*types.Func); ok {
+			obj = fn.Origin()
+		}
+		c, ok := candidates[obj]
+		if !ok {
+			continue
+		}
+		if ident.Pos() >= c.span[0] && ident.Pos() < c.span[1] {
+			continue
+		}
+		delete(candidates, obj)
+	}
+
+	// 方法可能通过包内接口被调用，Uses 中记录的是接口方法，此时无法确定是否被引用
+	interfaceMethods := packageInterfaceMethods(pkg)
+
+	var findings []DeadCodeFinding
+	for _, c := range candidates {
+		if c.kind == deadKindMethod && interfaceMethods[c.obj.Name()] {
+			continue
+		}
+		pos := pkg.Fset.Position(c.obj.Pos())
+		findings = append(findings, DeadCodeFinding{
+			Package: pkg.PkgPath,
+			File:    pos.Filename,
+			Line:    pos.Line,
+			Kind:    c.kind,
+			Name:    c.name,
+		})
+	}
+	return findings
+}
+
+// collectDeadCandidates 收集声明中的未导出函数、方法和包级常量、变量
+func collectDeadCandidates(pkg *packages.Package, decl ast.Decl, candidates map[types.Object]*deadCandidate) {
+	add := func(ident *ast.Ident, kind, name string, span [2]token.Pos) {
+		if ident.Name == "_" || ident.IsExported() {
+			return
+		}
+		if obj := pkg.TypesInfo.Defs[ident]; obj != nil {
+			candidates[obj] = &deadCandidate{obj: obj, kind: kind, name: name, span: span}
+		}
+	}
+
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		// 通过 //go:linkname 或 cgo //export 暴露的函数在 Go 代码中看不到引用
+		if hasLinkDirective(d.Doc) {
+			return
+		}
+		span := [2]token.Pos{d.Pos(), d.End()}
+		if d.Recv == nil {
+			if d.Name.Name == "init" || (d.Name.Name == "main" && pkg.Name == "main") {
+				return
+			}
+			add(d.Name, deadKindFunc, d.Name.Name, span)
+			return
+		}
+		if len(d.Recv.List) > 0 {
+			add(d.Name, deadKindMethod, receiverTypeName(d.Recv.List[0].Type)+"."+d.Name.Name, span)
+		}
+	case *ast.GenDecl:
+		kind := deadKindVar
+		switch d.Tok {
+		case token.CONST:
+			kind = deadKindConst
+		case token.VAR:
+		default:
+			return
+		}
+		for _, spec := range d.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for _, name := range vs.Names {
+				add(name, kind, name.Name, [2]token.Pos{name.Pos(), name.End()})
+			}
+		}
+	}
+}
+
+// hasLinkDirective 注释中是否有 //go:linkname 或 cgo 的 //export 指令
+// CommentGroup.Text 会去掉指令行，因此逐行检查
+func hasLinkDirective(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, c := range doc.List {
+		if strings.HasPrefix(c.Text, "//go:linkname ") || strings.HasPrefix(c.Text, "//export ") {
+			return true
+		}
+	}
+	return false
+}
+
+// packageInterfaceMethods 包中所有接口类型（包括匿名接口和嵌入的方法）的方法名
+func packageInterfaceMethods(pkg *packages.Package) map[string]bool {
+	names := make(map[string]bool)
+	addInterface := func(t types.Type) {
+		if t == nil {
+			return
+		}
+		iface, ok := t.Underlying().(*types.Interface)
+		if !ok {
+			return
+		}
+		for i := 0; i < iface.NumMethods(); i++ {
+			names[iface.Method(i).Name()] = true
+		}
+	}
+	for _, tv := range pkg.TypesInfo.Types {
+		addInterface(tv.Type)
+	}
+	for _, obj := range pkg.TypesInfo.Defs {
+		if tn, ok := obj.(*types.TypeName); ok {
+			addInterface(tn.Type())
+		}
+	}
+	return names
+}
+
+// unreachableStatements 找出语句列表中紧跟在 return 或 panic 之后的语句，每个列表只报告第一条
+// 带标签的语句可以通过 goto 到达，不算不可达
+func unreachableStatements(pkg *packages.Package, file *ast.File) []DeadCodeFinding {
+	var findings []DeadCodeFinding
+	check := func(list []ast.Stmt) {
+		for i, stmt := range list[:max(len(list)-1, 0)] {
+			terminator := terminatorName(pkg.TypesInfo, stmt)
+			if terminator == "" {
+				continue
+			}
+			next := list[i+1]
+			if _, ok := next.(*ast.LabeledStmt); ok {
+				return
+			}
+			if _, ok := next.(*ast.EmptyStmt); ok {
+				return
+			}
+			pos := pkg.Fset.Position(next.Pos())
+			findings = append(findings, DeadCodeFinding{
+				Package: pkg.PkgPath,
+				File:    pos.Filename,
+				Line:    pos.Line,
+				Kind:    deadKindUnreachable,
+				Name:    terminator,
+			})
+			return
+		}
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.BlockStmt:
+			check(s.List)
+		case *ast.CaseClause:
+			check(s.Body)
+		case *ast.CommClause:
+			check(s.Body)
+		}
+		return true
+	})
+	return findings
+}
+
+// terminatorName 语句是 return 或调用内置 panic 时返回其名称，否则返回空串
+func terminatorName(info *types.Info, stmt ast.Stmt) string {
+	switch s := stmt.(type) {
+	case *ast.ReturnStmt:
+		return "return"
+	case *ast.ExprStmt:
+		call, ok := s.X.(*ast.CallExpr)
+		if !ok {
+			return ""
+		}
+		ident, ok := ast.Unparen(call.Fun).(*ast.Ident)
+		if !ok {
+			return ""
+		}
+		if _, ok := info.Uses[ident].(*types.Builtin); ok && ident.Name == "panic" {
+			return "panic"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// 测试找出未被引用的未导出标识符和 return/panic 之后的不可达代码
+func TestAnalyzeDeadCode(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/demo\n\ngo 1.21\n",
+		"store/store.go": `package store
+
+import "errors"
+
+const (
+	maxRetries = 3
+	oldLimit   = 10
+)
+
+var cache = map[string]int{}
+
+var legacyNames []string
+
+type speaker interface {
+	speak() string
+}
+
+type dog struct{}
+
+func (dog) speak() string { return "woof" }
+
+func (d dog) bark() string { return d.speak() }
+
+type queue[T any] struct{ items []T }
+
+func (q *queue[T]) push(v T) { q.items = append(q.items, v) }
+
+func (q *queue[T]) drop() { q.items = nil }
+
+func Save(name string) error {
+	var s speaker = dog{}
+	_ = s
+	var q queue[string]
+	q.push(name)
+	for i := 0; i < maxRetries; i++ {
+		cache[name] = i
+	}
+	if name == "" {
+		return errors.New("empty")
+		cache[name] = -1
+	}
+	switch name {
+	case "x":
+		panic("x")
+		return nil
+	}
+	goto done
+done:
+	return nil
+}
+
+func countdown(n int) int {
+	if n == 0 {
+		return 0
+	}
+	return countdown(n - 1)
+}
+
+func init() {}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("创建目录失败: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("写入测试文件失败: %v", err)
+		}
+	}
+
+	result, err := AnalyzeDeadCode(context.Background(), DeadCodeRequest{Directory: dir})
+	if err != nil {
+		t.Fatalf("分析失败: %v", err)
+	}
+	if len(result.Errors) > 0 {
+		t.Fatalf("测试模块不应有类型错误: %v", result.Errors)
+	}
+
+	got := make(map[string]string)
+	for _, f := range result.Findings {
+		if f.Kind == deadKindUnreachable {
+			continue
+		}
+		got[f.Name] = f.Kind
+	}
+	// speak 可能通过 speaker 接口调用，countdown 只有递归调用，init 和导出的 Save 不报告
+	want := map[string]string{
+		"oldLimit":    deadKindConst,
+		"legacyNames": deadKindVar,
+		"dog.bark":    deadKindMethod,
+		"queue.drop":  deadKindMethod,
+		"countdown":   deadKindFunc,
+	}
+	if len(got) != len(want) {
+		t.Errorf("未被引用的标识符应该为 %v，实际 %v", want, got)
+	}
+	for name, kind := range want {
+		if got[name] != kind {
+			t.Errorf("%s 应该报告为 %s，实际 %v", name, kind, got)
+		}
+	}
+
+	// return 之后的赋值、panic 之后的 return；带标签的语句可以通过 goto 到达
+	if result.Unreachable != 2 {
+		t.Fatalf("应该有 2 处不可达代码: %+v", result.Findings)
+	}
+	var terminators []string
+	for _, f := range result.Findings {
+		if f.Kind == deadKindUnreachable {
+			terminators = append(terminators, f.Name)
+		}
+	}
+	if terminators[0] != "return" || terminators[1] != "panic" {
+		t.Errorf("不可达代码之前的语句应该依次为 return、panic: %v", terminators)
+	}
+}
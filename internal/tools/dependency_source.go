@@ -0,0 +1,133 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// DependencySource 模块缓存中一个依赖的源码位置
+type DependencySource struct {
+	Pattern string `json:"pattern"` // 用户给出的模块或包路径
+	Module  string `json:"module"`  // 所属模块路径
+	Version string `json:"version"` // 构建列表中的版本
+	Dir     string `json:"dir"`     // 模块缓存中的目录（只读）
+	SubDir  string `json:"subdir"`  // 给出的是包路径时，包相对模块根目录的路径
+}
+
+// Root 要索引的目录：给出包路径时只索引该包所在的子目录
+func (d DependencySource) Root() string {
+	return filepath.Join(d.Dir, filepath.FromSlash(d.SubDir))
+}
+
+// Source 依赖中文件的引用路径，形如 module@version/pkg/file.go，
+// 比模块缓存中的绝对路径更适合作为引用出处
+func (d DependencySource) Source(file string) string {
+	rel, err := filepath.Rel(d.Dir, file)
+	if err != nil {
+		rel = filepath.Base(file)
+	}
+	return path.Join(d.Module+"@"+d.Version, filepath.ToSlash(rel))
+}
+
+// listedModule go list -m -json 的输出
+type listedModule struct {
+	Path    string
+	Version string
+	Dir     string
+	Main    bool
+	Replace *listedModule
+}
+
+// ResolveDependencySources 在 dir 所在模块的构建列表中查找依赖，返回其在模块缓存中的目录
+// patterns 可以是模块路径，也可以是模块中的包路径（如 github.com/tmc/langchaingo/textsplitter）；
+// 只读取已经下载到模块缓存的依赖，不在缓存中时返回错误，提示先执行 go mod download
+func ResolveDependencySources(ctx context.Context, dir string, patterns []string) ([]DependencySource, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	out, err := commandOutput(ctx, dir, "go", "list", "-m", "-json", "all")
+	if err != nil {
+		return nil, fmt.Errorf("读取依赖列表失败: %w", err)
+	}
+	var modules []listedModule
+	dec := json.NewDecoder(strings.NewReader(out))
+	for {
+		var m listedModule
+		if err := dec.Decode(&m); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("解析依赖列表失败: %w", err)
+		}
+		if m.Replace != nil {
+			m.Version, m.Dir = m.Replace.Version, m.Replace.Dir
+		}
+		if !m.Main {
+			modules = append(modules, m)
+		}
+	}
+	return matchDependencySources(modules, patterns)
+}
+
+// matchDependencySources 为每个路径选出路径前缀最长的依赖模块
+func matchDependencySources(modules []listedModule, patterns []string) ([]DependencySource, error) {
+	var sources []DependencySource
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(strings.TrimSpace(pattern), "/...")
+		if pattern == "" {
+			continue
+		}
+		var best *listedModule
+		for i := range modules {
+			m := &modules[i]
+			if pattern != m.Path && !strings.HasPrefix(pattern, m.Path+"/") {
+				continue
+			}
+			if best == nil || len(m.Path) > len(best.Path) {
+				best = m
+			}
+		}
+		if best == nil {
+			return nil, fmt.Errorf("%w: %s 不是当前模块的依赖", ErrInvalidInput, pattern)
+		}
+		if best.Dir == "" {
+			return nil, fmt.Errorf("依赖 %s@%s 不在模块缓存中，请先执行 go mod download %s", best.Path, best.Version, best.Path)
+		}
+		sources = append(sources, DependencySource{
+			Pattern: pattern,
+			Module:  best.Path,
+			Version: best.Version,
+			Dir:     best.Dir,
+			SubDir:  strings.TrimPrefix(strings.TrimPrefix(pattern, best.Path), "/"),
+		})
+	}
+	return sources, nil
+}
+
+// GoFiles 列出依赖中要索引的 Go 文件，跳过测试文件和 testdata
+// 依赖总是遍历模块缓存，不经过为项目配置的文件发现方式
+func (d DependencySource) GoFiles(ctx context.Context) ([]string, error) {
+	paths, err := WalkDiscoverer{}.Discover(ctx, d.Root())
+	if err != nil {
+		return nil, fmt.Errorf("读取依赖 %s 的源码失败: %w", d.Pattern, err)
+	}
+	var files []string
+	for _, p := range paths {
+		if DetectLanguage(p) != "go" || strings.HasSuffix(p, "_test.go") {
+			continue
+		}
+		if strings.Contains(filepath.ToSlash(p), "/testdata/") {
+			continue
+		}
+		files = append(files, p)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("依赖 %s 中没有 Go 源码", d.Pattern)
+	}
+	return files, nil
+}
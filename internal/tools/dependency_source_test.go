@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// 测试按最长模块前缀匹配依赖，包路径定位到子目录
+func TestMatchDependencySources(t *testing.T) {
+	modules := []listedModule{
+		{Path: "github.com/tmc/langchaingo", Version: "v0.1.12", Dir: "/cache/github.com/tmc/langchaingo@v0.1.12"},
+		{Path: "github.com/milvus-io/milvus-sdk-go/v2", Version: "v2.4.0", Dir: "/cache/milvus-sdk-go/v2@v2.4.0"},
+		{Path: "github.com/milvus-io/milvus-sdk-go/v2/extra", Version: "v0.1.0", Dir: "/cache/extra@v0.1.0"},
+		{Path: "golang.org/x/tools", Version: "v0.20.0"},
+	}
+
+	sources, err := matchDependencySources(modules, []string{
+		"github.com/tmc/langchaingo/textsplitter/...",
+		"github.com/milvus-io/milvus-sdk-go/v2/extra/pkg",
+	})
+	if err != nil {
+		t.Fatalf("匹配依赖失败: %v", err)
+	}
+	if len(sources) != 2 {
+		t.Fatalf("应该匹配 2 个依赖，实际 %d", len(sources))
+	}
+	if s := sources[0]; s.Module != "github.com/tmc/langchaingo" || s.SubDir != "textsplitter" {
+		t.Errorf("包路径应定位到模块的子目录: %+v", s)
+	}
+	if s := sources[1]; s.Module != "github.com/milvus-io/milvus-sdk-go/v2/extra" || s.SubDir != "pkg" {
+		t.Errorf("应该选择路径前缀最长的模块: %+v", s)
+	}
+
+	if _, err := matchDependencySources(modules, []string{"example.com/unknown"}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("不是依赖时应该返回 ErrInvalidInput，实际 %v", err)
+	}
+	if _, err := matchDependencySources(modules, []string{"golang.org/x/tools"}); err == nil {
+		t.Error("不在模块缓存中的依赖应该返回错误")
+	}
+}
+
+// 测试依赖文件的引用路径和源码文件筛选
+func TestDependencySourceFiles(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"split/split.go":            "package split\n",
+		"split/split_test.go":       "package split\n",
+		"split/testdata/fixture.go": "package fixture\n",
+		"split/README.md":           "# split\n",
+		"other/other.go":            "package other\n",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dep := DependencySource{Pattern: "example.com/lib/split", Module: "example.com/lib", Version: "v1.2.0", Dir: dir, SubDir: "split"}
+	files, err := dep.GoFiles(context.Background())
+	if err != nil {
+		t.Fatalf("读取依赖源码失败: %v", err)
+	}
+	if len(files) != 1 || filepath.Base(files[0]) != "split.go" {
+		t.Fatalf("应该只包含 split.go，实际 %v", files)
+	}
+	if got := dep.Source(files[0]); got != "example.com/lib@v1.2.0/split/split.go" {
+		t.Errorf("引用路径错误: %s", got)
+	}
+}
@@ -0,0 +1,315 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// DiagramGenerator 调用图示生成器
+// 基于调用图（可选结合 LLM）为入口函数生成 Mermaid 时序图或流程图
+type DiagramGenerator struct {
+	*TypedTool[DiagramRequest, *DiagramResult]
+	model  llms.Model
+	logger Logger
+}
+
+// NewDiagramGenerator 创建图示生成器
+// model 为 nil 时只根据调用图生成
+func NewDiagramGenerator(model llms.Model, logger Logger) *DiagramGenerator {
+	dg := &DiagramGenerator{
+		model:  model,
+		logger: logger,
+	}
+	dg.TypedTool = NewTypedTool[DiagramRequest, *DiagramResult](
+		"diagram_generator",
+		"根据调用图为入口函数生成 Mermaid 时序图/流程图，并写入 Markdown 文件",
+		dg,
+	)
+	return dg
+}
+
+// DiagramKind 图示类型
+type DiagramKind string
+
+const (
+	DiagramSequence  DiagramKind = "sequence"  // 时序图
+	DiagramFlowchart DiagramKind = "flowchart" // 流程图
+)
+
+// DiagramRequest 图示生成请求
+type DiagramRequest struct {
+	Directory string      `json:"directory" jsonschema:"required"`                                              // 项目目录
+	Entry     string      `json:"entry" jsonschema:"required,description=入口函数，如 Ask 或 SourceInsightEngine.Ask"` // 入口函数（如 "Ask" 或 "SourceInsightEngine.Ask"）
+	Kind      DiagramKind `json:"kind" jsonschema:"enum=sequence|flowchart"`                                    // 图示类型
+	MaxDepth  int         `json:"max_depth"`                                                                    // 最大调用深度
+	Output    string      `json:"output,omitempty"`                                                             // 输出的 .md 文件
+	NoLLM     bool        `json:"no_llm,omitempty"`                                                             // 不使用 LLM，只用调用图
+}
+
+// DiagramResult 图示生成结果
+type DiagramResult struct {
+	Entry   string      `json:"entry"`   // 解析后的入口函数
+	Kind    DiagramKind `json:"kind"`    // 图示类型
+	Output  string      `json:"output"`  // 写入的文件
+	Source  string      `json:"source"`  // 生成来源：callgraph, llm
+	Nodes   int         `json:"nodes"`   // 涉及的函数数
+	Edges   int         `json:"edges"`   // 调用边数
+	Mermaid string      `json:"mermaid"` // Mermaid 源码
+}
+
+// ValidateInput 验证输入参数
+func (dg *DiagramGenerator) ValidateInput(req DiagramRequest) error {
+	if req.Entry == "" {
+		return fmt.Errorf("必须指定入口函数")
+	}
+	if req.Kind != "" && req.Kind != DiagramSequence && req.Kind != DiagramFlowchart {
+		return fmt.Errorf("不支持的图示类型: %s", req.Kind)
+	}
+	if req.Directory != "" {
+		if _, err := os.Stat(req.Directory); os.IsNotExist(err) {
+			return fmt.Errorf("目录不存在: %s", req.Directory)
+		}
+	}
+	return nil
+}
+
+// Execute 执行图示生成
+func (dg *DiagramGenerator) Execute(ctx context.Context, req DiagramRequest) (*DiagramResult, error) {
+	if req.Directory == "" {
+		req.Directory = "."
+	}
+	if req.Kind == "" {
+		req.Kind = DiagramSequence
+	}
+	if req.MaxDepth <= 0 {
+		req.MaxDepth = 3
+	}
+
+	graph, err := BuildCallGraph(ctx, req.Directory)
+	if err != nil {
+		return nil, fmt.Errorf("构建调用图失败: %w", err)
+	}
+
+	matches := graph.Find(req.Entry)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("未找到入口函数: %s", req.Entry)
+	}
+	if len(matches) > 1 {
+		var keys []string
+		for _, m := range matches {
+			keys = append(keys, m.Key)
+		}
+		return nil, fmt.Errorf("入口函数不唯一，请使用完整名称: %s", strings.Join(keys, ", "))
+	}
+	entry := matches[0]
+	edges := graph.Walk(entry.Key, req.MaxDepth)
+
+	result := DiagramResult{
+		Entry:   entry.Key,
+		Kind:    req.Kind,
+		Source:  "callgraph",
+		Nodes:   countDiagramNodes(entry.Key, edges),
+		Edges:   len(edges),
+		Mermaid: renderMermaid(req.Kind, entry.Key, edges),
+	}
+
+	// 使用 LLM 补充调用语义，失败时回退到调用图结果
+	if dg.model != nil && !req.NoLLM {
+		mermaid, err := dg.generateWithLLM(ctx, req.Kind, graph, entry, edges)
+		if err != nil {
+			if dg.logger != nil {
+				dg.logger.Warn("LLM 生成图示失败，使用调用图结果", "error", err)
+			}
+		} else {
+			result.Mermaid = mermaid
+			result.Source = "llm"
+		}
+	}
+
+	result.Output = req.Output
+	if result.Output == "" {
+		result.Output = defaultDiagramOutput(entry.Key, req.Kind)
+	}
+	output, err := WriteOutputFile(ctx, result.Output, []byte(renderDiagramMarkdown(result, req.MaxDepth)), 0644)
+	if err != nil {
+		return nil, fmt.Errorf("写入图示文件失败: %w", err)
+	}
+	result.Output = output
+
+	return &result, nil
+}
+
+// generateWithLLM 让模型根据调用链和源码生成 Mermaid 图
+func (dg *DiagramGenerator) generateWithLLM(ctx context.Context, kind DiagramKind, graph *CallGraph, entry *CallNode, edges []CallEdge) (string, error) {
+	var calls strings.Builder
+	for _, edge := range edges {
+		calls.WriteString(fmt.Sprintf("%s%s -> %s\n", strings.Repeat("  ", edge.Depth-1), edge.From, edge.To))
+	}
+
+	// 附带涉及函数的源码，控制总长度
+	var sources strings.Builder
+	keys := []string{entry.Key}
+	for _, edge := range edges {
+		keys = append(keys, edge.To)
+	}
+	written := make(map[string]bool)
+	for _, key := range keys {
+		node, ok := graph.Nodes[key]
+		if !ok || written[key] || sources.Len() > 12000 {
+			continue
+		}
+		written[key] = true
+		sources.WriteString(fmt.Sprintf("// %s (%s:%d)\n%s\n\n", node.Key, node.File, node.Line, truncateLines(node.Source, 60)))
+	}
+
+	header := "sequenceDiagram"
+	if kind == DiagramFlowchart {
+		header = "flowchart TD"
+	}
+
+	prompt := fmt.Sprintf(`你是一名 Go 架构师，正在为新成员编写入门文档。
+请根据下面的【调用链】和【函数源码】，为入口函数 %s 生成一张 Mermaid 图。
+要求：
+1. 图示类型必须是 %s，第一行为 "%s"。
+2. 只描述调用链中出现的函数，用简短中文说明每一步做了什么。
+3. 只输出一个 mermaid 代码块，不要输出其他内容。
+
+【调用链】：
+%s
+【函数源码】：
+%s`, entry.Key, kind, header, calls.String(), sources.String())
+
+	answer, err := llms.GenerateFromSinglePrompt(ctx, dg.model, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	mermaid := extractMermaidBlock(answer)
+	if !strings.HasPrefix(mermaid, "sequenceDiagram") && !strings.HasPrefix(mermaid, "flowchart") && !strings.HasPrefix(mermaid, "graph") {
+		return "", fmt.Errorf("模型输出不是有效的 Mermaid 图")
+	}
+	return mermaid, nil
+}
+
+// renderMermaid 根据调用边生成确定性的 Mermaid 图
+func renderMermaid(kind DiagramKind, entry string, edges []CallEdge) string {
+	var sb strings.Builder
+
+	if kind == DiagramFlowchart {
+		ids := map[string]string{}
+		nodeID := func(key string) string {
+			if id, ok := ids[key]; ok {
+				return id
+			}
+			id := fmt.Sprintf("n%d", len(ids))
+			ids[key] = id
+			sb.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", id, key))
+			return id
+		}
+
+		sb.WriteString("flowchart TD\n")
+		nodeID(entry)
+		for _, edge := range edges {
+			from := nodeID(edge.From)
+			to := nodeID(edge.To)
+			sb.WriteString(fmt.Sprintf("    %s --> %s\n", from, to))
+		}
+		return strings.TrimRight(sb.String(), "\n")
+	}
+
+	// 时序图：参与者为 "包.类型" 或 "包"
+	participants := map[string]string{}
+	var order []string
+	participantOf := func(key string) string {
+		name := diagramParticipant(key)
+		if id, ok := participants[name]; ok {
+			return id
+		}
+		id := fmt.Sprintf("p%d", len(participants))
+		participants[name] = id
+		order = append(order, name)
+		return id
+	}
+
+	participantOf(entry)
+	var messages strings.Builder
+	for _, edge := range edges {
+		from := participantOf(edge.From)
+		to := participantOf(edge.To)
+		messages.WriteString(fmt.Sprintf("    %s->>%s: %s()\n", from, to, diagramFuncName(edge.To)))
+	}
+
+	sb.WriteString("sequenceDiagram\n")
+	for _, name := range order {
+		sb.WriteString(fmt.Sprintf("    participant %s as %s\n", participants[name], name))
+	}
+	sb.WriteString(messages.String())
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// renderDiagramMarkdown 生成写入文件的 Markdown 内容
+func renderDiagramMarkdown(result DiagramResult, maxDepth int) string {
+	title := "调用时序图"
+	if result.Kind == DiagramFlowchart {
+		title = "调用流程图"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# %s %s\n\n", result.Entry, title))
+	sb.WriteString(fmt.Sprintf("> 由 go-ai-insight diagram 生成（来源：%s，最大深度：%d，函数数：%d）\n\n", result.Source, maxDepth, result.Nodes))
+	sb.WriteString("```mermaid\n")
+	sb.WriteString(result.Mermaid)
+	sb.WriteString("\n```\n")
+	return sb.String()
+}
+
+// extractMermaidBlock 从模型回答中提取 mermaid 代码块
+func extractMermaidBlock(answer string) string {
+	return extractCodeBlock(answer, "mermaid")
+}
+
+// diagramParticipant 时序图参与者名称（去掉函数名）
+func diagramParticipant(key string) string {
+	if idx := strings.LastIndex(key, "."); idx != -1 {
+		return key[:idx]
+	}
+	return key
+}
+
+// diagramFuncName 函数短名
+func diagramFuncName(key string) string {
+	if idx := strings.LastIndex(key, "."); idx != -1 {
+		return key[idx+1:]
+	}
+	return key
+}
+
+// countDiagramNodes 统计图中涉及的函数数
+func countDiagramNodes(entry string, edges []CallEdge) int {
+	nodes := map[string]bool{entry: true}
+	for _, edge := range edges {
+		nodes[edge.From] = true
+		nodes[edge.To] = true
+	}
+	return len(nodes)
+}
+
+// defaultDiagramOutput 默认输出文件名
+func defaultDiagramOutput(entry string, kind DiagramKind) string {
+	name := strings.ReplaceAll(entry, ".", "_")
+	return filepath.Join(".", fmt.Sprintf("%s_%s.md", name, kind))
+}
+
+// truncateLines 截断过长的源码
+func truncateLines(source string, maxLines int) string {
+	lines := strings.Split(source, "\n")
+	if len(lines) <= maxLines {
+		return source
+	}
+	return strings.Join(lines[:maxLines], "\n") + "\n// ..."
+}
@@ -0,0 +1,132 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms/fake"
+)
+
+const diagramFixture = `package app
+
+type Engine struct{}
+
+func Main() {
+	e := &Engine{}
+	e.Ask("q")
+}
+
+func (e *Engine) Ask(q string) {
+	e.search(q)
+	render(q)
+}
+
+func (e *Engine) search(q string) {}
+
+func render(s string) {}
+`
+
+func writeDiagramFixture(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.go"), []byte(diagramFixture), 0644); err != nil {
+		t.Fatalf("写入测试文件失败: %v", err)
+	}
+	return dir
+}
+
+// 测试调用图解析
+func TestBuildCallGraph(t *testing.T) {
+	dir := writeDiagramFixture(t)
+
+	graph, err := BuildCallGraph(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("构建调用图失败: %v", err)
+	}
+
+	ask, ok := graph.Nodes["app.Engine.Ask"]
+	if !ok {
+		t.Fatal("应该包含方法 app.Engine.Ask")
+	}
+	want := []string{"app.Engine.search", "app.render"}
+	if strings.Join(ask.Calls, ",") != strings.Join(want, ",") {
+		t.Fatalf("调用解析错误: 期望 %v, 实际 %v", want, ask.Calls)
+	}
+
+	edges := graph.Walk("app.Main", 3)
+	if len(edges) != 3 {
+		t.Fatalf("期望 3 条调用边, 实际 %d: %v", len(edges), edges)
+	}
+}
+
+// 测试不使用 LLM 生成时序图
+func TestDiagramGenerator_Sequence(t *testing.T) {
+	dir := writeDiagramFixture(t)
+	out := filepath.Join(t.TempDir(), "ask.md")
+
+	generator := NewDiagramGenerator(nil, NewNoopLogger())
+	req := DiagramRequest{Directory: dir, Entry: "Engine.Ask", Kind: DiagramSequence, Output: out}
+	if err := generator.Validate(req); err != nil {
+		t.Fatalf("验证失败: %v", err)
+	}
+
+	raw, err := generator.Run(context.Background(), req)
+	if err != nil {
+		t.Fatalf("生成失败: %v", err)
+	}
+
+	var result DiagramResult
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		t.Fatalf("解析结果失败: %v", err)
+	}
+	if result.Source != "callgraph" || result.Edges != 2 {
+		t.Fatalf("结果不符合预期: %+v", result)
+	}
+
+	content, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("读取输出文件失败: %v", err)
+	}
+	if !strings.Contains(string(content), "```mermaid\nsequenceDiagram") {
+		t.Fatalf("输出文件缺少 Mermaid 时序图:\n%s", content)
+	}
+	if !strings.Contains(string(content), "p0->>p0: search()") {
+		t.Fatalf("缺少方法调用消息:\n%s", content)
+	}
+}
+
+// 测试 LLM 输出的提取和无效输出回退
+func TestDiagramGenerator_LLM(t *testing.T) {
+	dir := writeDiagramFixture(t)
+
+	model := fake.NewFakeLLM([]string{"下面是流程图：\n```mermaid\nflowchart TD\n    A[Ask] --> B[search]\n```"})
+	generator := NewDiagramGenerator(model, NewNoopLogger())
+	raw, err := generator.Run(context.Background(), DiagramRequest{
+		Directory: dir, Entry: "Ask", Kind: DiagramFlowchart, Output: filepath.Join(t.TempDir(), "f.md"),
+	})
+	if err != nil {
+		t.Fatalf("生成失败: %v", err)
+	}
+	var result DiagramResult
+	json.Unmarshal([]byte(raw), &result)
+	if result.Source != "llm" || !strings.HasPrefix(result.Mermaid, "flowchart TD") {
+		t.Fatalf("应该使用 LLM 结果: %+v", result)
+	}
+
+	model = fake.NewFakeLLM([]string{"抱歉，我无法生成"})
+	generator = NewDiagramGenerator(model, NewNoopLogger())
+	raw, err = generator.Run(context.Background(), DiagramRequest{
+		Directory: dir, Entry: "Ask", Kind: DiagramFlowchart, Output: filepath.Join(t.TempDir(), "f.md"),
+	})
+	if err != nil {
+		t.Fatalf("生成失败: %v", err)
+	}
+	json.Unmarshal([]byte(raw), &result)
+	if result.Source != "callgraph" {
+		t.Fatalf("无效的 LLM 输出应该回退到调用图: %+v", result)
+	}
+}
@@ -0,0 +1,346 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"go-ai-study/internal/config"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 文件发现方式
+const (
+	DiscoveryWalk    = "walk"    // 遍历文件系统（默认）
+	DiscoveryList    = "list"    // 读取文件列表
+	DiscoveryBazel   = "bazel"   // 通过 bazel query 查询 Go 规则的 srcs
+	DiscoveryCommand = "command" // 执行外部命令（如 plz query），输出每行一个文件
+)
+
+// 遍历文件系统时符号链接的处理方式
+const (
+	SymlinkFiles  = "files"  // 收集指向文件的链接，不进入指向目录的链接（默认）
+	SymlinkSkip   = "skip"   // 忽略所有符号链接
+	SymlinkFollow = "follow" // 进入指向目录的链接，同一个真实目录只遍历一次，链接成环时不会死循环
+)
+
+// FileDiscoverer 目录扫描时的文件发现方式
+// 构建系统生成的代码不在源码目录里，或者以符号链接重复出现，直接遍历会漏掉或重复统计，
+// 这时由构建系统给出文件列表
+type FileDiscoverer interface {
+	// Discover 返回 dir 下要分析的文件（包含非 Go 文件，由调用方按语言过滤）
+	Discover(ctx context.Context, dir string) ([]string, error)
+}
+
+// NewFileDiscoverer 按配置创建文件发现方式
+func NewFileDiscoverer(cfg config.DiscoveryConfig) (FileDiscoverer, error) {
+	switch cfg.Symlinks {
+	case "", SymlinkFiles, SymlinkSkip, SymlinkFollow:
+	default:
+		return nil, fmt.Errorf("%w: 未知的符号链接处理方式 %q（可选 files、skip、follow）", ErrInvalidInput, cfg.Symlinks)
+	}
+	if cfg.MaxDepth < 0 || cfg.MaxFiles < 0 {
+		return nil, fmt.Errorf("%w: discovery.max_depth 和 discovery.max_files 不能为负数", ErrInvalidInput)
+	}
+
+	switch cfg.Mode {
+	case "", DiscoveryWalk:
+		return WalkDiscoverer{Symlinks: cfg.Symlinks, MaxDepth: cfg.MaxDepth, MaxFiles: cfg.MaxFiles}, nil
+	case DiscoveryList:
+		if cfg.FileList == "" {
+			return nil, fmt.Errorf("%w: discovery.mode 为 list 时需要 discovery.file_list", ErrInvalidInput)
+		}
+		return FileListDiscoverer{Path: cfg.FileList}, nil
+	case DiscoveryBazel:
+		return BazelDiscoverer{Binary: cfg.Bazel, Query: cfg.BazelQuery}, nil
+	case DiscoveryCommand:
+		if len(cfg.Command) == 0 {
+			return nil, fmt.Errorf("%w: discovery.mode 为 command 时需要 discovery.command", ErrInvalidInput)
+		}
+		return CommandDiscoverer{Command: cfg.Command}, nil
+	default:
+		return nil, fmt.Errorf("%w: 未知的文件发现方式 %q（可选 walk、list、bazel、command）", ErrInvalidInput, cfg.Mode)
+	}
+}
+
+// discovererKey 文件发现方式在 context 中的键
+type discovererKey struct{}
+
+// WithFileDiscoverer 把文件发现方式放入 context，之后的目录扫描都经由它获取文件
+func WithFileDiscoverer(ctx context.Context, d FileDiscoverer) context.Context {
+	return context.WithValue(ctx, discovererKey{}, d)
+}
+
+// discoverFiles 用 context 中的文件发现方式获取 dir 下的文件，没有时遍历文件系统
+// 同一个文件经由不同路径（符号链接、生成目录）出现多次时只保留第一次
+func discoverFiles(ctx context.Context, dir string) ([]string, error) {
+	d, _ := ctx.Value(discovererKey{}).(FileDiscoverer)
+	if d == nil {
+		d = WalkDiscoverer{}
+	}
+	files, err := d.Discover(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(files))
+	unique := files[:0]
+	for _, file := range files {
+		key := file
+		if resolved, err := filepath.EvalSymlinks(file); err == nil {
+			key = resolved
+		}
+		if abs, err := filepath.Abs(key); err == nil {
+			key = abs
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, file)
+	}
+	return unique, nil
+}
+
+// WalkDiscoverer 遍历文件系统，跳过隐藏目录和 go.work 中的其他成员模块
+// 用户把主目录这类很大的目录当作分析目标时，MaxDepth 和 MaxFiles 让遍历尽早停下
+type WalkDiscoverer struct {
+	Symlinks string // 符号链接的处理方式：files（默认）、skip、follow
+	MaxDepth int    // 进入子目录的层数上限，更深的目录跳过；0 表示不限制
+	MaxFiles int    // 遍历到的文件数上限（含非 Go 文件），超过后以 ErrResourceLimit 失败；0 表示不限制
+}
+
+// Discover 遍历 dir 下的文件，按路径字典序返回
+func (w WalkDiscoverer) Discover(ctx context.Context, dir string) ([]string, error) {
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		return []string{dir}, nil
+	}
+	walker := &dirWalker{WalkDiscoverer: w, ctx: ctx, root: dir, visited: make(map[string]bool)}
+	walker.enter(dir)
+	err := walker.walk(dir, 0)
+	return walker.files, err
+}
+
+// dirWalker 一次遍历的状态
+type dirWalker struct {
+	WalkDiscoverer
+	ctx     context.Context
+	root    string
+	files   []string
+	visited map[string]bool // follow 模式下已遍历的真实目录
+}
+
+// enter 记录即将遍历的目录，返回 false 表示该真实目录已经遍历过（只在 follow 模式下检查）
+func (w *dirWalker) enter(dir string) bool {
+	if w.Symlinks != SymlinkFollow {
+		return true
+	}
+	real, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return false
+	}
+	if w.visited[real] {
+		return false
+	}
+	w.visited[real] = true
+	return true
+}
+
+// walk 遍历一个目录，depth 为相对 root 的层数；读取失败的目录跳过
+func (w *dirWalker) walk(dir string, depth int) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	for _, entry := range entries {
+		if w.ctx.Err() != nil {
+			return context.Cause(w.ctx)
+		}
+		path := filepath.Join(dir, entry.Name())
+		isDir := entry.IsDir()
+		if entry.Type()&os.ModeSymlink != 0 {
+			if w.Symlinks == SymlinkSkip {
+				continue
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				continue // 断开的链接
+			}
+			if info.IsDir() && w.Symlinks != SymlinkFollow {
+				continue
+			}
+			isDir = info.IsDir()
+		}
+
+		if isDir {
+			if strings.HasPrefix(entry.Name(), ".") || skipMemberDir(w.ctx, w.root, path) {
+				continue
+			}
+			if w.MaxDepth > 0 && depth >= w.MaxDepth {
+				continue
+			}
+			if !w.enter(path) {
+				continue
+			}
+			if err := w.walk(path, depth+1); err != nil {
+				return err
+			}
+			continue
+		}
+
+		w.files = append(w.files, path)
+		if w.MaxFiles > 0 && len(w.files) > w.MaxFiles {
+			return fmt.Errorf("%w: %s 下的文件数超过 %d（discovery.max_files），请缩小分析范围或调大上限", ErrResourceLimit, w.root, w.MaxFiles)
+		}
+	}
+	return nil
+}
+
+// FileListDiscoverer 从文件列表读取要分析的文件
+// 每行一个路径，空行和 # 开头的行忽略；相对路径相对列表文件所在目录。
+// 列表给出的就是分析范围，不按 dir 过滤（生成的文件通常不在源码目录下）
+type FileListDiscoverer struct {
+	Path string
+}
+
+// Discover 读取文件列表
+func (d FileListDiscoverer) Discover(ctx context.Context, dir string) ([]string, error) {
+	f, err := os.Open(d.Path)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件列表失败: %w", err)
+	}
+	defer f.Close()
+	files, err := readFileList(f, filepath.Dir(d.Path))
+	if err != nil {
+		return nil, fmt.Errorf("读取文件列表失败: %w", err)
+	}
+	return files, nil
+}
+
+// readFileList 按行读取路径，相对路径相对 base
+func readFileList(r io.Reader, base string) ([]string, error) {
+	var files []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		path := filepath.FromSlash(line)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(base, path)
+		}
+		files = append(files, path)
+	}
+	return files, scanner.Err()
+}
+
+// CommandDiscoverer 执行外部命令获取文件列表（如 plz query input //pkg/...）
+// 命令在 dir 中执行，标准输出每行一个路径，相对路径相对 dir
+type CommandDiscoverer struct {
+	Command []string
+}
+
+// Discover 执行命令
+func (d CommandDiscoverer) Discover(ctx context.Context, dir string) ([]string, error) {
+	out, err := commandOutput(ctx, dir, d.Command[0], d.Command[1:]...)
+	if err != nil {
+		return nil, fmt.Errorf("执行文件发现命令 %s 失败: %w", d.Command[0], err)
+	}
+	return readFileList(strings.NewReader(out), dir)
+}
+
+// BazelDiscoverer 通过 bazel query 获取 Go 规则的源文件
+// 源文件映射到工作区目录，生成的文件映射到 bazel-bin 下（需要先构建），外部仓库的文件忽略
+type BazelDiscoverer struct {
+	Binary string // bazel 可执行文件，默认 bazel
+	Query  string // 查询表达式，{pkg} 替换为 dir 对应的包模式；默认查询 dir 下所有 go_* 规则的 srcs
+}
+
+// defaultBazelQuery 默认查询：dir 下所有 Go 规则的 srcs
+const defaultBazelQuery = `labels(srcs, kind("go_.* rule", {pkg}))`
+
+// Discover 执行 bazel query
+func (d BazelDiscoverer) Discover(ctx context.Context, dir string) ([]string, error) {
+	bin := d.Binary
+	if bin == "" {
+		bin = "bazel"
+	}
+	info, err := commandOutput(ctx, dir, bin, "info", "workspace", "bazel-bin")
+	if err != nil {
+		return nil, fmt.Errorf("bazel info 失败: %w", err)
+	}
+	var workspace, bazelBin string
+	for _, line := range strings.Split(info, "\n") {
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "workspace":
+			workspace = strings.TrimSpace(value)
+		case "bazel-bin":
+			bazelBin = strings.TrimSpace(value)
+		}
+	}
+	if workspace == "" {
+		return nil, fmt.Errorf("bazel info 没有返回工作区目录")
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := filepath.Rel(workspace, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("%s 不在 bazel 工作区 %s 中", dir, workspace)
+	}
+	pkg := "//..."
+	if rel != "." {
+		pkg = "//" + filepath.ToSlash(rel) + "/..."
+	}
+	query := d.Query
+	if query == "" {
+		query = defaultBazelQuery
+	}
+	query = strings.ReplaceAll(query, "{pkg}", pkg)
+
+	out, err := commandOutput(ctx, dir, bin, "query", query, "--output=label_kind")
+	if err != nil {
+		return nil, fmt.Errorf("bazel query 失败: %w", err)
+	}
+	return parseBazelLabels(out, workspace, bazelBin), nil
+}
+
+// parseBazelLabels 把 --output=label_kind 的输出（如 "source file //pkg:a.go"）转换为文件路径
+func parseBazelLabels(out, workspace, bazelBin string) []string {
+	var files []string
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		idx := strings.Index(line, "//")
+		if idx <= 0 {
+			continue
+		}
+		kind := strings.TrimSpace(line[:idx])
+		pkg, name, ok := strings.Cut(line[idx+2:], ":")
+		if !ok {
+			continue
+		}
+		root := workspace
+		switch kind {
+		case "source file":
+		case "generated file":
+			if bazelBin == "" {
+				continue
+			}
+			root = bazelBin
+		default:
+			// 外部仓库（kind 后面跟着 @repo）或其他类型
+			continue
+		}
+		files = append(files, filepath.Join(root, filepath.FromSlash(pkg), filepath.FromSlash(name)))
+	}
+	return files
+}
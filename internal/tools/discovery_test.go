@@ -0,0 +1,172 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"go-ai-study/internal/config"
+)
+
+// 测试文件列表：相对路径相对列表文件，目录外的生成文件也会分析，符号链接重复的文件只保留一次
+func TestFileListDiscoverer(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "src")
+	gen := filepath.Join(root, "bazel-bin", "src")
+	for _, dir := range []string{src, gen} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string]string{
+		filepath.Join(src, "main.go"):   "package main\n\nimport \"os\"\n\nfunc main() {\n\t_ = os.Remove(\"a.txt\")\n}\n",
+		filepath.Join(gen, "gen.pb.go"): "package main\n\nimport \"os\"\n\nfunc gen() {\n\t_ = os.Remove(\"b.txt\")\n}\n",
+		filepath.Join(src, "skip.go"):   "package main\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	list := filepath.Join(root, "files.txt")
+	content := "# 由构建系统生成\nsrc/main.go\n\nbazel-bin/src/gen.pb.go\nsrc/README.md\n"
+	if runtime.GOOS != "windows" {
+		if err := os.Symlink(filepath.Join(src, "main.go"), filepath.Join(gen, "main.go")); err != nil {
+			t.Fatal(err)
+		}
+		content += "bazel-bin/src/main.go\n"
+	}
+	if err := os.WriteFile(list, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := WithFileDiscoverer(context.Background(), FileListDiscoverer{Path: list})
+	goFiles, err := collectGoFiles(ctx, src)
+	if err != nil {
+		t.Fatalf("收集文件失败: %v", err)
+	}
+	if len(goFiles) != 2 || goFiles[0] != filepath.Join(src, "main.go") || goFiles[1] != filepath.Join(gen, "gen.pb.go") {
+		t.Errorf("文件列表错误: %v", goFiles)
+	}
+
+	// Bug 检测的目录扫描同样经由文件列表
+	bugs, err := NewBugDetector().Execute(ctx, BugDetectorInput{Directory: src})
+	if err != nil {
+		t.Fatalf("Bug 检测失败: %v", err)
+	}
+	if bugs.AnalyzedFiles != 2 {
+		t.Errorf("应该分析 2 个文件，实际 %d: %+v", bugs.AnalyzedFiles, bugs.SkippedFiles)
+	}
+}
+
+// 测试 bazel query --output=label_kind 输出的解析
+func TestParseBazelLabels(t *testing.T) {
+	out := "source file //svc/api:handler.go\n" +
+		"generated file //svc/api:api.pb.go\n" +
+		"source file @com_github_pkg_errors//:errors.go\n" +
+		"go_library rule //svc/api:api\n"
+	files := parseBazelLabels(out, "/ws", "/ws/bazel-out/bin")
+	want := []string{
+		filepath.Join("/ws", "svc", "api", "handler.go"),
+		filepath.Join("/ws/bazel-out/bin", "svc", "api", "api.pb.go"),
+	}
+	if len(files) != len(want) || files[0] != want[0] || files[1] != want[1] {
+		t.Errorf("parseBazelLabels = %v, want %v", files, want)
+	}
+}
+
+// 测试外部命令给出文件列表
+func TestCommandDiscoverer(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("需要 sh")
+	}
+	dir := t.TempDir()
+	files, err := CommandDiscoverer{Command: []string{"sh", "-c", "echo a.go; echo sub/b.go"}}.Discover(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("执行命令失败: %v", err)
+	}
+	if len(files) != 2 || files[1] != filepath.Join(dir, "sub", "b.go") {
+		t.Errorf("命令输出解析错误: %v", files)
+	}
+}
+
+// 测试按配置创建文件发现方式
+func TestNewFileDiscoverer(t *testing.T) {
+	if d, err := NewFileDiscoverer(config.DiscoveryConfig{}); err != nil || d != (WalkDiscoverer{}) {
+		t.Errorf("默认应该遍历文件系统: %v %v", d, err)
+	}
+	for _, cfg := range []config.DiscoveryConfig{{Mode: "list"}, {Mode: "command"}, {Mode: "make"}} {
+		if _, err := NewFileDiscoverer(cfg); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("%+v 应该返回 ErrInvalidInput，实际 %v", cfg, err)
+		}
+	}
+}
+
+// 测试符号链接策略、成环链接和遍历深度、文件数上限
+func TestWalkDiscoverer_Symlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("创建符号链接需要额外权限")
+	}
+	dir := t.TempDir()
+	outside := t.TempDir()
+	write := func(path string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("package p\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(dir, "a.go"))
+	write(filepath.Join(dir, "sub", "deep", "b.go"))
+	write(filepath.Join(outside, "lib", "c.go"))
+	for link, target := range map[string]string{
+		filepath.Join(dir, "linked.go"):   filepath.Join(dir, "a.go"),
+		filepath.Join(dir, "lib"):         filepath.Join(outside, "lib"),
+		filepath.Join(dir, "sub", "loop"): dir,
+	} {
+		if err := os.Symlink(target, link); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	discover := func(d WalkDiscoverer) []string {
+		t.Helper()
+		files, err := d.Discover(context.Background(), dir)
+		if err != nil {
+			t.Fatalf("%+v 遍历失败: %v", d, err)
+		}
+		rel := make([]string, len(files))
+		for i, f := range files {
+			rel[i], _ = filepath.Rel(dir, f)
+			rel[i] = filepath.ToSlash(rel[i])
+		}
+		return rel
+	}
+	for _, tc := range []struct {
+		walker WalkDiscoverer
+		want   []string
+	}{
+		{WalkDiscoverer{}, []string{"a.go", "linked.go", "sub/deep/b.go"}},
+		{WalkDiscoverer{Symlinks: SymlinkSkip}, []string{"a.go", "sub/deep/b.go"}},
+		// 指向根目录的 loop 成环，只遍历一次
+		{WalkDiscoverer{Symlinks: SymlinkFollow}, []string{"a.go", "lib/c.go", "linked.go", "sub/deep/b.go"}},
+		{WalkDiscoverer{MaxDepth: 1}, []string{"a.go", "linked.go"}},
+	} {
+		if got := discover(tc.walker); !slices.Equal(got, tc.want) {
+			t.Errorf("%+v = %v, want %v", tc.walker, got, tc.want)
+		}
+	}
+
+	if _, err := (WalkDiscoverer{MaxFiles: 2}).Discover(context.Background(), dir); !errors.Is(err, ErrResourceLimit) {
+		t.Errorf("超过文件数上限应返回 ErrResourceLimit，实际 %v", err)
+	}
+	if _, err := NewFileDiscoverer(config.DiscoveryConfig{Symlinks: "always"}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("未知的符号链接处理方式应返回 ErrInvalidInput，实际 %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DocCoverageAnalyzer 文档注释覆盖率分析器
+// 统计每个包中导出标识符带有文档注释的比例，并列出缺失注释的位置
+type DocCoverageAnalyzer struct {
+	*TypedTool[DocCoverageRequest, *DocCoverageResult]
+}
+
+// NewDocCoverageAnalyzer 创建文档注释覆盖率分析器
+func NewDocCoverageAnalyzer() *DocCoverageAnalyzer {
+	da := &DocCoverageAnalyzer{}
+	da.TypedTool = NewTypedTool[DocCoverageRequest, *DocCoverageResult](
+		"doc_coverage",
+		"统计每个包中导出标识符的文档注释覆盖率，并列出缺失注释的位置",
+		da,
+	)
+	return da
+}
+
+// DocCoverageRequest 文档注释覆盖率请求
+type DocCoverageRequest struct {
+	Directory string `json:"directory" jsonschema:"required"` // 扫描目录
+}
+
+// DocGap 缺少文档注释的导出标识符
+type DocGap struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Kind string `json:"kind"` // func, method, type, const, var
+	Name string `json:"name"` // 方法为 Type.Method
+}
+
+// PackageDocCoverage 单个包的文档注释覆盖率
+type PackageDocCoverage struct {
+	Package    string   `json:"package"` // 包目录
+	Name       string   `json:"name"`    // 包名
+	Exported   int      `json:"exported"`
+	Documented int      `json:"documented"`
+	Coverage   float64  `json:"coverage"` // 百分比，没有导出标识符时为 100
+	Missing    []DocGap `json:"missing,omitempty"`
+}
+
+// DocCoverageResult 文档注释覆盖率结果
+type DocCoverageResult struct {
+	Packages   []PackageDocCoverage `json:"packages"`
+	Exported   int                  `json:"exported"`
+	Documented int                  `json:"documented"`
+	Coverage   float64              `json:"coverage"`
+	Summary    string               `json:"summary"`
+}
+
+// ValidateInput 验证输入参数
+func (da *DocCoverageAnalyzer) ValidateInput(req DocCoverageRequest) error {
+	if req.Directory == "" {
+		return fmt.Errorf("必须指定 Directory")
+	}
+	return nil
+}
+
+// Execute 执行文档注释覆盖率分析
+func (da *DocCoverageAnalyzer) Execute(ctx context.Context, req DocCoverageRequest) (*DocCoverageResult, error) {
+	files, err := collectGoFiles(ctx, req.Directory)
+	if err != nil {
+		return nil, fmt.Errorf("文件收集失败: %w", err)
+	}
+
+	packages := make(map[string]*PackageDocCoverage)
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+
+		src, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, file, src, parser.ParseComments)
+		if err != nil {
+			continue // 解析失败的文件不参与统计
+		}
+
+		dir := filepath.Dir(file)
+		pkg, ok := packages[dir]
+		if !ok {
+			pkg = &PackageDocCoverage{Package: dir, Name: f.Name.Name}
+			packages[dir] = pkg
+		}
+		for _, item := range exportedDocItems(fset, f) {
+			pkg.Exported++
+			if item.documented {
+				pkg.Documented++
+			} else {
+				pkg.Missing = append(pkg.Missing, item.gap)
+			}
+		}
+	}
+
+	result := DocCoverageResult{Packages: []PackageDocCoverage{}}
+	for _, pkg := range packages {
+		pkg.Coverage = docCoveragePercent(pkg.Documented, pkg.Exported)
+		result.Packages = append(result.Packages, *pkg)
+		result.Exported += pkg.Exported
+		result.Documented += pkg.Documented
+	}
+	sort.Slice(result.Packages, func(i, j int) bool {
+		return result.Packages[i].Package < result.Packages[j].Package
+	})
+	result.Coverage = docCoveragePercent(result.Documented, result.Exported)
+	result.Summary = fmt.Sprintf("%d 个包，%d 个导出标识符，%d 个有文档注释，覆盖率 %.1f%%",
+		len(result.Packages), result.Exported, result.Documented, result.Coverage)
+
+	return &result, nil
+}
+
+// docItem 导出标识符及其是否有文档注释
+type docItem struct {
+	gap        DocGap
+	documented bool
+}
+
+// exportedDocItems 收集文件中的导出标识符
+// 分组声明（const/var/type 的括号形式）中，声明组的注释对组内所有标识符生效
+func exportedDocItems(fset *token.FileSet, f *ast.File) []docItem {
+	filename := fset.Position(f.Pos()).Filename
+	var items []docItem
+	add := func(pos token.Pos, kind, name string, doc *ast.CommentGroup) {
+		items = append(items, docItem{
+			gap:        DocGap{File: filename, Line: fset.Position(pos).Line, Kind: kind, Name: name},
+			documented: doc != nil && strings.TrimSpace(doc.Text()) != "",
+		})
+	}
+
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if !d.Name.IsExported() {
+				continue
+			}
+			if d.Recv == nil {
+				add(d.Pos(), "func", d.Name.Name, d.Doc)
+				continue
+			}
+			recv := receiverTypeName(d.Recv.List[0].Type)
+			if ast.IsExported(recv) {
+				add(d.Pos(), "method", recv+"."+d.Name.Name, d.Doc)
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if s.Name.IsExported() {
+						add(s.Pos(), "type", s.Name.Name, firstComment(s.Doc, d.Doc))
+					}
+				case *ast.ValueSpec:
+					kind := "var"
+					if d.Tok == token.CONST {
+						kind = "const"
+					}
+					for _, name := range s.Names {
+						if name.IsExported() {
+							add(name.Pos(), kind, name.Name, firstComment(s.Doc, s.Comment, d.Doc))
+						}
+					}
+				}
+			}
+		}
+	}
+	return items
+}
+
+// firstComment 返回第一个非空的注释
+func firstComment(groups ...*ast.CommentGroup) *ast.CommentGroup {
+	for _, g := range groups {
+		if g != nil {
+			return g
+		}
+	}
+	return nil
+}
+
+// docCoveragePercent 计算覆盖率
+func docCoveragePercent(documented, exported int) float64 {
+	if exported == 0 {
+		return 100
+	}
+	return float64(documented) * 100 / float64(exported)
+}
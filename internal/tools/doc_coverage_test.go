@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const docCoverageSample = `package demo
+
+// Client 客户端
+type Client struct{}
+
+// Get 获取数据
+func (c *Client) Get() {}
+
+func (c *Client) Put() {}
+
+type inner struct{}
+
+func (i inner) Exported() {}
+
+func New() *Client { return nil }
+
+// 状态常量
+const (
+	StateA = iota
+	StateB
+)
+
+var (
+	// Timeout 超时
+	Timeout = 1
+	Retries = 3
+	hidden  = 0
+)
+`
+
+// 测试导出标识符的文档注释统计
+func TestDocCoverageAnalyzer(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "demo.go"), []byte(docCoverageSample), 0644); err != nil {
+		t.Fatalf("写入测试文件失败: %v", err)
+	}
+	// 测试文件不参与统计
+	if err := os.WriteFile(filepath.Join(dir, "demo_test.go"), []byte("package demo\n\nfunc Helper() {}\n"), 0644); err != nil {
+		t.Fatalf("写入测试文件失败: %v", err)
+	}
+
+	output, err := NewDocCoverageAnalyzer().Run(context.Background(), DocCoverageRequest{Directory: dir})
+	if err != nil {
+		t.Fatalf("统计失败: %v", err)
+	}
+	var result DocCoverageResult
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("解析结果失败: %v", err)
+	}
+	if len(result.Packages) != 1 {
+		t.Fatalf("应该统计 1 个包: %+v", result.Packages)
+	}
+
+	// Client、Client.Get、StateA、StateB、Timeout 有注释；Client.Put、New、Retries 缺失
+	pkg := result.Packages[0]
+	if pkg.Exported != 8 || pkg.Documented != 5 {
+		t.Fatalf("统计不正确: %+v", pkg)
+	}
+	missing := make(map[string]string)
+	for _, gap := range pkg.Missing {
+		missing[gap.Name] = gap.Kind
+	}
+	for name, kind := range map[string]string{"Client.Put": "method", "New": "func", "Retries": "var"} {
+		if missing[name] != kind {
+			t.Errorf("缺少 %s (%s): %+v", name, kind, pkg.Missing)
+		}
+	}
+	if result.Coverage != 62.5 {
+		t.Errorf("覆盖率应为 62.5，实际 %.1f", result.Coverage)
+	}
+}
@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"context"
+	"go-ai-study/internal/ai"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// DocFile 一个项目文档
+type DocFile struct {
+	Path string `json:"path"`
+	Type string `json:"type"` // ai.DocTypeReadme、ai.DocTypeADR、ai.DocTypeGuide
+}
+
+// adrFilePattern ADR 的常见文件名，如 adr-001-use-milvus.md、ADR0002.md
+var adrFilePattern = regexp.MustCompile(`^adr[-_]?\d+`)
+
+// DiscoverDocFiles 收集目录下的项目文档：任意目录中的 README、docs/（或 doc/）下的 Markdown，以及 ADR
+// 构建系统给出的文件列表通常只有源文件，所以总是遍历文件系统（walk 模式的深度和文件数上限仍然生效）
+func DiscoverDocFiles(ctx context.Context, dir string) ([]DocFile, error) {
+	walker, _ := ctx.Value(discovererKey{}).(WalkDiscoverer)
+	files, err := walker.Discover(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	var docs []DocFile
+	for _, file := range files {
+		if docType := ClassifyDoc(relativeSource(dir, file)); docType != "" {
+			docs = append(docs, DocFile{Path: file, Type: docType})
+		}
+	}
+	return docs, nil
+}
+
+// ClassifyDoc 按相对扫描根目录的斜杠路径判断文档类型，不是项目文档时返回空
+// vendor、testdata、node_modules 下的文件不算项目文档
+func ClassifyDoc(rel string) string {
+	base := strings.ToLower(path.Base(rel))
+	if ext := path.Ext(base); ext != ".md" && ext != ".markdown" {
+		return ""
+	}
+	dirs := strings.Split(strings.ToLower(path.Dir(rel)), "/")
+	for _, dir := range dirs {
+		if dir == "vendor" || dir == "testdata" || dir == "node_modules" {
+			return ""
+		}
+	}
+
+	if strings.Contains(base, "readme") {
+		return ai.DocTypeReadme
+	}
+	if adrFilePattern.MatchString(base) {
+		return ai.DocTypeADR
+	}
+	for _, dir := range dirs {
+		if dir == "adr" || dir == "adrs" || dir == "decisions" {
+			return ai.DocTypeADR
+		}
+	}
+	if dirs[0] == "docs" || dirs[0] == "doc" {
+		return ai.DocTypeGuide
+	}
+	return ""
+}
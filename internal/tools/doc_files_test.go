@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"context"
+	"go-ai-study/internal/ai"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// 测试文档类型的判断：README 在任意目录，docs/ 下的是文档，ADR 按目录或文件名识别
+func TestClassifyDoc(t *testing.T) {
+	cases := map[string]string{
+		"README.md":                       ai.DocTypeReadme,
+		"CLI_README.md":                   ai.DocTypeReadme,
+		"internal/ai/readme.markdown":     ai.DocTypeReadme,
+		"docs/guide/install.md":           ai.DocTypeGuide,
+		"doc/faq.md":                      ai.DocTypeGuide,
+		"docs/adr/0001-use-milvus.md":     ai.DocTypeADR,
+		"architecture/decisions/retry.md": ai.DocTypeADR,
+		"ADR-002-split-docs.md":           ai.DocTypeADR,
+		"CHANGELOG.md":                    "",
+		"internal/ai/notes.md":            "",
+		"docs/diagram.png":                "",
+		"vendor/github.com/x/README.md":   "",
+		"testdata/docs/a.md":              "",
+	}
+	for rel, want := range cases {
+		if got := ClassifyDoc(rel); got != want {
+			t.Errorf("ClassifyDoc(%q) = %q，期望 %q", rel, got, want)
+		}
+	}
+}
+
+// 测试收集文档：跳过隐藏目录和非文档文件
+func TestDiscoverDocFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, file := range []string{"README.md", "main.go", "docs/usage.md", "docs/adr/0001-a.md", ".github/README.md", "pkg/notes.md"} {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("# 标题\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	docs, err := DiscoverDocFiles(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("收集失败: %v", err)
+	}
+	got := make(map[string]string)
+	for _, doc := range docs {
+		got[relativeSource(dir, doc.Path)] = doc.Type
+	}
+	want := map[string]string{"README.md": ai.DocTypeReadme, "docs/usage.md": ai.DocTypeGuide, "docs/adr/0001-a.md": ai.DocTypeADR}
+	if len(got) != len(want) {
+		t.Fatalf("应该收集到 %v，实际 %v", want, got)
+	}
+	for rel, docType := range want {
+		if got[rel] != docType {
+			t.Errorf("%s 的类型应该是 %q，实际 %q", rel, docType, got[rel])
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// DocGenerator 文档注释生成器
+// 为缺少文档注释的导出标识符生成注释（来自 doc_coverage 的缺口列表），输出补丁
+type DocGenerator struct {
+	*TypedTool[DocGenRequest, *DocGenResult]
+	model  llms.Model
+	logger Logger
+}
+
+// NewDocGenerator 创建文档注释生成器
+// model 为 nil 时生成 TODO 占位注释
+func NewDocGenerator(model llms.Model, logger Logger) *DocGenerator {
+	dg := &DocGenerator{
+		model:  model,
+		logger: logger,
+	}
+	dg.TypedTool = NewTypedTool[DocGenRequest, *DocGenResult](
+		"doc_generator",
+		"为缺少文档注释的导出标识符生成注释，输出补丁",
+		dg,
+	)
+	return dg
+}
+
+// DocGenRequest 文档注释生成请求
+type DocGenRequest struct {
+	Gaps  []DocGap `json:"gaps" jsonschema:"required"` // 缺少注释的标识符（通常来自 doc_coverage）
+	Write bool     `json:"write,omitempty"`            // 是否写回文件（默认只生成 diff）
+}
+
+// DocGenResult 文档注释生成结果
+type DocGenResult struct {
+	Generated int         `json:"generated"` // 生成的注释数
+	Patches   []FilePatch `json:"patches"`
+	Skipped   []string    `json:"skipped"`
+	Applied   bool        `json:"applied"`
+	Summary   string      `json:"summary"`
+}
+
+// ValidateInput 验证输入参数
+func (dg *DocGenerator) ValidateInput(req DocGenRequest) error {
+	if len(req.Gaps) == 0 {
+		return fmt.Errorf("没有需要生成注释的标识符")
+	}
+	return nil
+}
+
+// Execute 执行文档注释生成
+func (dg *DocGenerator) Execute(ctx context.Context, req DocGenRequest) (*DocGenResult, error) {
+	byFile := make(map[string][]DocGap)
+	var files []string
+	for _, gap := range req.Gaps {
+		if _, ok := byFile[gap.File]; !ok {
+			files = append(files, gap.File)
+		}
+		byFile[gap.File] = append(byFile[gap.File], gap)
+	}
+	sort.Strings(files)
+
+	result := DocGenResult{Patches: []FilePatch{}, Skipped: []string{}}
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		src, err := os.ReadFile(file)
+		if err != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s: 读取失败: %v", file, err))
+			continue
+		}
+		fctx, err := newFixContext(file, src)
+		if err != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s: 解析失败: %v", file, err))
+			continue
+		}
+
+		var edits []FixEdit
+		seen := make(map[int]bool)
+		for _, gap := range byFile[file] {
+			anchor, decl := findDocAnchor(fctx, gap)
+			if anchor == nil {
+				result.Skipped = append(result.Skipped, fmt.Sprintf("%s:%d %s: 未找到声明", file, gap.Line, gap.Name))
+				continue
+			}
+
+			offset := lineStart(src, fctx.FSet.Position(anchor.Pos()).Offset)
+			if seen[offset] {
+				continue // 同一个声明中的多个标识符只生成一条注释
+			}
+			seen[offset] = true
+
+			comment := dg.generateComment(ctx, fctx, gap, decl)
+			edits = append(edits, FixEdit{
+				Start:   offset,
+				End:     offset,
+				NewText: lineIndent(src, offset) + "// " + comment + "\n",
+				Line:    gap.Line,
+			})
+		}
+		if len(edits) == 0 {
+			continue
+		}
+
+		updated := keepGofmt(src, applyFixEdits(src, edits))
+		result.Generated += len(edits)
+		result.Patches = append(result.Patches, FilePatch{
+			File: file,
+			Diff: UnifiedDiff("a/"+file, "b/"+file, string(src), string(updated)),
+		})
+		if req.Write {
+			if _, err := WriteOutputFile(ctx, file, updated, 0644); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result.Applied = req.Write && result.Generated > 0
+	result.Summary = fmt.Sprintf("生成 %d 条文档注释（%d 个文件）", result.Generated, len(result.Patches))
+
+	return &result, nil
+}
+
+// findDocAnchor 找到注释应插入的节点（注释写在它的上一行）和完整声明
+// 非分组的 type/const/var 注释写在声明关键字之前，分组声明写在组内的 spec 之前
+func findDocAnchor(fctx *FixContext, gap DocGap) (ast.Node, ast.Node) {
+	shortName := gap.Name[strings.LastIndex(gap.Name, ".")+1:]
+	line := func(pos token.Pos) int { return fctx.FSet.Position(pos).Line }
+
+	for _, decl := range fctx.File.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Name.Name == shortName && line(d.Pos()) == gap.Line {
+				return d, d
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				var names []*ast.Ident
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					names = []*ast.Ident{s.Name}
+				case *ast.ValueSpec:
+					names = s.Names
+				}
+				for _, name := range names {
+					if name.Name != shortName || line(name.Pos()) != gap.Line {
+						continue
+					}
+					if d.Lparen.IsValid() {
+						return spec, spec
+					}
+					return d, d
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// generateComment 生成注释内容（不含 //），按仓库习惯以标识符名开头
+func (dg *DocGenerator) generateComment(ctx context.Context, fctx *FixContext, gap DocGap, decl ast.Node) string {
+	shortName := gap.Name[strings.LastIndex(gap.Name, ".")+1:]
+	fallback := shortName + " TODO: 补充文档注释"
+	if dg.model == nil {
+		return fallback
+	}
+
+	start := fctx.FSet.Position(decl.Pos()).Offset
+	end := fctx.FSet.Position(decl.End()).Offset
+	source := truncateLines(string(fctx.Src[start:end]), 30)
+
+	prompt := fmt.Sprintf(`为下面的 Go %s %s 写一行中文文档注释。
+要求：以标识符名 %s 开头，说明它做什么，不超过 40 个字；只输出注释文字本身，不要输出 // 和代码。
+
+%s`, gap.Kind, gap.Name, shortName, source)
+
+	answer, err := llms.GenerateFromSinglePrompt(ctx, dg.model, prompt)
+	if err != nil {
+		if dg.logger != nil {
+			dg.logger.Warn("生成文档注释失败", "name", gap.Name, "error", err)
+		}
+		return fallback
+	}
+
+	comment := ""
+	for _, l := range strings.Split(answer, "\n") {
+		l = strings.TrimSpace(strings.Trim(strings.TrimSpace(l), "`"))
+		l = strings.TrimSpace(strings.TrimPrefix(l, "//"))
+		if l != "" {
+			comment = l
+			break
+		}
+	}
+	if comment == "" {
+		return fallback
+	}
+	if !strings.HasPrefix(comment, shortName) {
+		comment = shortName + " " + comment
+	}
+	return comment
+}
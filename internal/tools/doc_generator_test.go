@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms/fake"
+)
+
+// runDocGenerator 统计覆盖率缺口并交给 doc_generator
+func runDocGenerator(t *testing.T, gen *DocGenerator, dir string, write bool) DocGenResult {
+	t.Helper()
+
+	output, err := NewDocCoverageAnalyzer().Run(context.Background(), DocCoverageRequest{Directory: dir})
+	if err != nil {
+		t.Fatalf("统计失败: %v", err)
+	}
+	var coverage DocCoverageResult
+	if err := json.Unmarshal([]byte(output), &coverage); err != nil {
+		t.Fatalf("解析覆盖率失败: %v", err)
+	}
+	var gaps []DocGap
+	for _, pkg := range coverage.Packages {
+		gaps = append(gaps, pkg.Missing...)
+	}
+
+	output, err = gen.Run(context.Background(), DocGenRequest{Gaps: gaps, Write: write})
+	if err != nil {
+		t.Fatalf("生成失败: %v", err)
+	}
+	var result DocGenResult
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("解析结果失败: %v", err)
+	}
+	return result
+}
+
+// 测试没有模型时生成 TODO 占位注释并写回
+func TestDocGenerator_Placeholder(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "demo.go")
+	if err := os.WriteFile(path, []byte(docCoverageSample), 0644); err != nil {
+		t.Fatalf("写入测试文件失败: %v", err)
+	}
+
+	result := runDocGenerator(t, NewDocGenerator(nil, NewNoopLogger()), dir, true)
+	if result.Generated != 3 || !result.Applied {
+		t.Fatalf("应该生成 3 条注释: %+v", result)
+	}
+
+	updated, _ := os.ReadFile(path)
+	for _, want := range []string{
+		"// Put TODO: 补充文档注释\nfunc (c *Client) Put()",
+		"// New TODO: 补充文档注释\nfunc New()",
+		"\t// Retries TODO: 补充文档注释\n\tRetries = 3",
+	} {
+		if !strings.Contains(string(updated), want) {
+			t.Errorf("缺少注释 %q:\n%s", want, updated)
+		}
+	}
+
+	// 补全后覆盖率应为 100%
+	output, _ := NewDocCoverageAnalyzer().Run(context.Background(), DocCoverageRequest{Directory: dir})
+	var coverage DocCoverageResult
+	if err := json.Unmarshal([]byte(output), &coverage); err != nil || coverage.Coverage != 100 {
+		t.Fatalf("补全后覆盖率应为 100: %s", output)
+	}
+}
+
+// 测试使用 LLM 生成注释（只输出 diff，不写回）
+func TestDocGenerator_LLM(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "demo.go")
+	src := "package demo\n\nfunc Sum(a, b int) int { return a + b }\n"
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatalf("写入测试文件失败: %v", err)
+	}
+
+	gen := NewDocGenerator(fake.NewFakeLLM([]string{"```\n// 计算两数之和\n```"}), NewNoopLogger())
+	result := runDocGenerator(t, gen, dir, false)
+	if result.Generated != 1 || result.Applied {
+		t.Fatalf("应该生成 1 条注释且不写回: %+v", result)
+	}
+	if !strings.Contains(result.Patches[0].Diff, "+// Sum 计算两数之和") {
+		t.Errorf("diff 应包含生成的注释:\n%s", result.Patches[0].Diff)
+	}
+	if current, _ := os.ReadFile(path); string(current) != src {
+		t.Error("未指定 Write 时不应修改文件")
+	}
+}
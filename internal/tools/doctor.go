@@ -0,0 +1,345 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/ai"
+	"go-ai-study/internal/config"
+	"go-ai-study/internal/i18n"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 自检项的状态
+const (
+	DoctorOK   = "ok"
+	DoctorWarn = "warn" // 不影响静态分析，但部分功能不可用
+	DoctorFail = "fail"
+	DoctorSkip = "skip"
+)
+
+// doctorServiceTimeout 连接 Ollama、Milvus 的超时
+const doctorServiceTimeout = 5 * time.Second
+
+// EnvironmentDoctor 环境自检
+// 检查 go 命令、配置、Ollama 和模型、Milvus 和代码索引、缓存目录的写权限，每个失败项附带处理建议
+type EnvironmentDoctor struct {
+	*TypedTool[DoctorRequest, *DoctorResult]
+	config *config.Config
+}
+
+// NewEnvironmentDoctor 创建环境自检工具
+func NewEnvironmentDoctor(cfg *config.Config) *EnvironmentDoctor {
+	d := &EnvironmentDoctor{
+		config: cfg,
+	}
+	d.TypedTool = NewTypedTool[DoctorRequest, *DoctorResult](
+		"environment_doctor",
+		"检查运行环境：go 命令、配置、Ollama 模型、Milvus 索引和缓存目录权限",
+		d,
+	)
+	return d
+}
+
+// DoctorRequest 环境自检请求
+type DoctorRequest struct {
+	Module  string `json:"module,omitempty"`  // 检查 go.work 成员模块的索引（模块路径），为空检查默认索引
+	Offline bool   `json:"offline,omitempty"` // 跳过 Ollama、Milvus 的连接检查（只使用静态分析时）
+}
+
+// DoctorCheck 一项检查的结果
+type DoctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"` // ok、warn、fail、skip
+	Detail string `json:"detail,omitempty"`
+	Fix    string `json:"fix,omitempty"` // 没有通过时的处理建议
+}
+
+// DoctorResult 环境自检结果
+type DoctorResult struct {
+	Checks   []DoctorCheck `json:"checks"`
+	Failed   int           `json:"failed"`
+	Warnings int           `json:"warnings"`
+	Summary  string        `json:"summary"`
+}
+
+// ValidateInput 验证输入
+func (d *EnvironmentDoctor) ValidateInput(req DoctorRequest) error {
+	return nil
+}
+
+// Execute 依次执行各项检查，检查失败记入结果，不中断后续检查
+func (d *EnvironmentDoctor) Execute(ctx context.Context, req DoctorRequest) (*DoctorResult, error) {
+	result := &DoctorResult{}
+	add := func(checks ...DoctorCheck) {
+		result.Checks = append(result.Checks, checks...)
+	}
+
+	add(checkGoToolchain(ctx))
+	add(checkConfig(d.config)...)
+	if req.Offline {
+		add(DoctorCheck{Name: "ollama", Status: DoctorSkip, Detail: "--offline"},
+			DoctorCheck{Name: "milvus", Status: DoctorSkip, Detail: "--offline"})
+	} else {
+		add(checkOllama(ctx, d.config.OllamaEndpoint, d.config.ChatModel, d.config.EmbeddingModel)...)
+		add(checkMilvus(ctx, d.config.MilvusEndpoint, ai.CodeCollectionName(req.Module), d.config.EmbeddingModel)...)
+	}
+	for _, dir := range doctorDirs(d.config) {
+		add(checkWritableDir(dir.name, dir.path, dir.key))
+	}
+	if ctx.Err() != nil {
+		return nil, context.Cause(ctx)
+	}
+
+	for _, c := range result.Checks {
+		switch c.Status {
+		case DoctorFail:
+			result.Failed++
+		case DoctorWarn:
+			result.Warnings++
+		}
+	}
+	result.Summary = fmt.Sprintf("共 %d 项检查：%d 项失败，%d 项警告", len(result.Checks), result.Failed, result.Warnings)
+	return result, nil
+}
+
+// checkGoToolchain 检查 go 命令（--types、compat、inventory、依赖源码索引等功能需要）
+func checkGoToolchain(ctx context.Context) DoctorCheck {
+	check := DoctorCheck{Name: "go"}
+	path, err := exec.LookPath("go")
+	if err != nil {
+		check.Status = DoctorFail
+		check.Detail = "找不到 go 命令"
+		check.Fix = "安装 Go（https://go.dev/dl/）并把 go 所在目录加入 PATH；--types、compat、inventory 等功能需要 go 命令"
+		return check
+	}
+	out, err := exec.CommandContext(ctx, path, "env", "GOVERSION").Output()
+	if err != nil {
+		check.Status = DoctorFail
+		check.Detail = fmt.Sprintf("执行 %s env 失败: %v", path, err)
+		check.Fix = "运行 go env 查看具体错误，通常是 GOROOT 或 GOTOOLCHAIN 配置有误"
+		return check
+	}
+	check.Status = DoctorOK
+	check.Detail = fmt.Sprintf("%s（%s）", strings.TrimSpace(string(out)), path)
+	return check
+}
+
+// checkConfig 检查启动时没有校验、要到具体命令执行时才会报错的配置项
+func checkConfig(cfg *config.Config) []DoctorCheck {
+	var checks []DoctorCheck
+	fail := func(detail, fix string) {
+		checks = append(checks, DoctorCheck{Name: "config", Status: DoctorFail, Detail: detail, Fix: fix})
+	}
+
+	switch cfg.DefaultFormat {
+	case "json", "text", "html":
+	case "template":
+		if cfg.ReportTemplate == "" {
+			fail("default_format 为 template 但没有配置 report_template", "配置 report_template，或用 --template 指定模板文件")
+		} else if _, err := os.Stat(cfg.ReportTemplate); err != nil {
+			fail(fmt.Sprintf("读取报告模板失败: %v", err), "检查 report_template 的路径")
+		}
+	default:
+		fail(fmt.Sprintf("不支持的输出格式 %q", cfg.DefaultFormat), "default_format 改为 text、json、html 或 template")
+	}
+	if _, err := ParseOutputSchema(cfg.OutputSchema); err != nil {
+		fail(err.Error(), "output_schema 改为 v1 或 v2")
+	}
+	if cfg.Locale != "" {
+		if _, ok := i18n.Normalize(cfg.Locale); !ok {
+			fail(fmt.Sprintf("不支持的语言 %q", cfg.Locale), "locale 改为 "+strings.Join(i18n.Locales(), " 或 "))
+		}
+	}
+	if c := cfg.StructTags.Case; c != "" && tagCases[c] == nil {
+		fail(fmt.Sprintf("未知的标签命名风格 %q", c), "struct_tags.case 改为 snake、camel、pascal 或 kebab")
+	}
+	if _, err := RuleSettingsFromConfig(map[string]any{
+		CustomConfigDisabledRules: cfg.Rules.Disabled,
+		CustomConfigRuleSeverity:  cfg.Rules.Severity,
+		CustomConfigCustomRules:   cfg.Rules.Custom,
+	}); err != nil {
+		fail(fmt.Sprintf("规则配置无效: %v", err), "检查 rules.disabled、rules.severity 中的规则 ID 和 rules.custom 中的规则文件（list 命令列出所有规则）")
+	}
+	if err := validateLayers(cfg.Arch.Layers); err != nil {
+		fail(err.Error(), "修改 arch.layers：层名唯一，may_use 只引用已声明的层")
+	}
+	if _, err := NewFileDiscoverer(cfg.Discovery); err != nil {
+		fail(err.Error(), "检查 discovery 配置（mode 为 walk、list、bazel 或 command）")
+	} else if cfg.Discovery.Mode == DiscoveryList {
+		if _, err := os.Stat(cfg.Discovery.FileList); err != nil {
+			fail(fmt.Sprintf("读取文件列表失败: %v", err), "检查 discovery.file_list 的路径，或用 --files-from 指定")
+		}
+	}
+	if cfg.ChatModel == "" || cfg.EmbeddingModel == "" {
+		fail("没有配置 chat_model 或 embedding_model", "在配置中指定模型，如 \"chat_model\": \"llama3:latest\"")
+	}
+	if cfg.LocalOnly {
+		if err := CheckLocalOnly(cfg); err != nil {
+			fail(err.Error(), "把 ollama_endpoint、milvus_endpoint 改为本机地址，或去掉 local_only")
+		}
+	}
+
+	if len(checks) == 0 {
+		checks = append(checks, DoctorCheck{Name: "config", Status: DoctorOK, Detail: "配置有效"})
+	}
+	return checks
+}
+
+// checkOllama 检查 Ollama 能否连接，以及对话模型和向量模型是否已下载
+func checkOllama(ctx context.Context, endpoint string, models ...string) []DoctorCheck {
+	if endpoint == "" {
+		return []DoctorCheck{{Name: "ollama", Status: DoctorFail, Detail: "没有配置 ollama_endpoint", Fix: "在配置中指定 ollama_endpoint，如 http://localhost:11434"}}
+	}
+	pulled, err := ollamaModels(ctx, endpoint)
+	if err != nil {
+		return []DoctorCheck{{
+			Name:   "ollama",
+			Status: DoctorFail,
+			Detail: err.Error(),
+			Fix:    fmt.Sprintf("启动 Ollama（ollama serve），或把 ollama_endpoint 改为正确的地址（当前 %s）", endpoint),
+		}}
+	}
+
+	checks := []DoctorCheck{{Name: "ollama", Status: DoctorOK, Detail: fmt.Sprintf("%s，已下载 %d 个模型", endpoint, len(pulled))}}
+	for _, model := range models {
+		if model == "" {
+			continue
+		}
+		check := DoctorCheck{Name: "model " + model, Status: DoctorOK, Detail: "已下载"}
+		if !pulled[ollamaModelName(model)] {
+			check.Status = DoctorFail
+			check.Detail = "模型没有下载"
+			check.Fix = "ollama pull " + model
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// ollamaModels 读取 Ollama 已下载的模型（/api/tags）
+func ollamaModels(ctx context.Context, endpoint string) (map[string]bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, doctorServiceTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(endpoint, "/")+"/api/tags", nil)
+	if err != nil {
+		return nil, fmt.Errorf("ollama_endpoint 无效: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("连接 Ollama 失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Ollama 返回 %s", resp.Status)
+	}
+	var tags struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, fmt.Errorf("解析 Ollama 模型列表失败: %w", err)
+	}
+	pulled := make(map[string]bool, len(tags.Models))
+	for _, m := range tags.Models {
+		pulled[ollamaModelName(m.Name)] = true
+	}
+	return pulled, nil
+}
+
+// ollamaModelName 补全模型标签：没有写标签的模型名与 :latest 等价
+func ollamaModelName(name string) string {
+	if !strings.Contains(name, ":") {
+		return name + ":latest"
+	}
+	return name
+}
+
+// checkMilvus 检查 Milvus 能否连接，以及代码索引是否存在、表结构版本和向量模型是否与当前一致
+func checkMilvus(ctx context.Context, endpoint, collection, embeddingModel string) []DoctorCheck {
+	connectCtx, cancel := context.WithTimeout(ctx, doctorServiceTimeout)
+	defer cancel()
+	m, err := ai.ConnectMilvus(connectCtx, endpoint)
+	if err != nil {
+		return []DoctorCheck{{
+			Name:   "milvus",
+			Status: DoctorFail,
+			Detail: err.Error(),
+			Fix:    fmt.Sprintf("启动 Milvus（如 docker compose up -d milvus-standalone），或把 milvus_endpoint 改为正确的地址（当前 %s）", endpoint),
+		}}
+	}
+	defer m.Close()
+
+	checks := []DoctorCheck{{Name: "milvus", Status: DoctorOK, Detail: endpoint}}
+	index := DoctorCheck{Name: "index " + collection}
+	describeCtx, cancel := context.WithTimeout(ctx, doctorServiceTimeout)
+	defer cancel()
+	info, err := ai.DescribeCodeIndex(describeCtx, m, collection)
+	switch {
+	case err != nil:
+		index.Status, index.Detail = DoctorFail, err.Error()
+		index.Fix = "确认 Milvus 服务正常（查看 Milvus 日志），必要时运行 scan --reindex 重建索引"
+	case !info.Exists:
+		index.Status, index.Detail = DoctorWarn, "代码索引不存在，search、ask 等命令不可用"
+		index.Fix = "运行 scan <目录> 建立索引"
+	case len(info.Mismatches(embeddingModel)) > 0:
+		index.Status, index.Detail = DoctorFail, "代码索引已过期："+strings.Join(info.Mismatches(embeddingModel), "；")
+		index.Fix = "运行 scan --reindex <目录> 重建索引"
+	default:
+		index.Status, index.Detail = DoctorOK, fmt.Sprintf("表结构版本 %d，向量模型 %s，%d 条记录", info.SchemaVersion, info.EmbeddingModel, info.Rows)
+	}
+	return append(checks, index)
+}
+
+// doctorDir 需要写权限的目录
+type doctorDir struct {
+	name string
+	path string
+	key  string // 对应的配置项，用于处理建议
+}
+
+// doctorDirs 程序会写入的目录：临时目录（工作区、go doc），以及配置了的崩溃报告、审计日志、提示词日志和日志文件目录
+func doctorDirs(cfg *config.Config) []doctorDir {
+	dirs := []doctorDir{{name: "temp dir", path: os.TempDir(), key: "TMPDIR 环境变量"}}
+	if cfg.CrashReportDir != "" {
+		dirs = append(dirs, doctorDir{name: "crash report dir", path: cfg.CrashReportDir, key: "crash_report_dir"})
+	}
+	if cfg.AuditLog.Path != "" {
+		dirs = append(dirs, doctorDir{name: "audit log dir", path: filepath.Dir(cfg.AuditLog.Path), key: "audit_log.path"})
+	}
+	if cfg.PromptLog.Enabled {
+		dir := cfg.PromptLog.Dir
+		if dir == "" {
+			dir = filepath.Join(os.TempDir(), "go-ai-insight-prompts")
+		}
+		dirs = append(dirs, doctorDir{name: "prompt log dir", path: dir, key: "prompt_log.dir"})
+	}
+	if cfg.LogConfig.Output == "file" && cfg.LogConfig.FilePath != "" {
+		dirs = append(dirs, doctorDir{name: "log file dir", path: filepath.Dir(cfg.LogConfig.FilePath), key: "log_config.file_path"})
+	}
+	return dirs
+}
+
+// checkWritableDir 在目录中创建并删除一个临时文件，确认有写权限（目录不存在时先创建）
+func checkWritableDir(name, dir, key string) DoctorCheck {
+	check := DoctorCheck{Name: name, Status: DoctorOK, Detail: dir}
+	fix := fmt.Sprintf("为当前用户开放写权限（如 chmod u+w %s），或通过 %s 改用可写的目录", dir, key)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		check.Status, check.Detail, check.Fix = DoctorFail, fmt.Sprintf("创建目录 %s 失败: %v", dir, err), fix
+		return check
+	}
+	f, err := os.CreateTemp(dir, ".insight-doctor-*")
+	if err != nil {
+		check.Status, check.Detail, check.Fix = DoctorFail, fmt.Sprintf("目录 %s 不可写: %v", dir, err), fix
+		return check
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return check
+}
@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"context"
+	"go-ai-study/internal/config"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 测试配置检查：每个问题单独列出并附带处理建议
+func TestCheckConfig(t *testing.T) {
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatalf("加载默认配置失败: %v", err)
+	}
+	if checks := checkConfig(cfg); len(checks) != 1 || checks[0].Status != DoctorOK {
+		t.Fatalf("默认配置应该有效: %+v", checks)
+	}
+
+	cfg.OutputSchema = "v3"
+	cfg.Rules.Disabled = []string{"B999"}
+	cfg.Arch.Layers = []config.Layer{{Name: "app", Packages: []string{"internal/app"}, MayUse: []string{"domain"}}}
+	checks := checkConfig(cfg)
+	if len(checks) != 3 {
+		t.Fatalf("应该列出 3 个问题: %+v", checks)
+	}
+	for _, c := range checks {
+		if c.Status != DoctorFail || c.Fix == "" {
+			t.Errorf("失败项缺少处理建议: %+v", c)
+		}
+	}
+}
+
+// 测试 Ollama 检查：服务不可达、模型没有下载
+func TestCheckOllama(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tags" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"models":[{"name":"llama3:latest"},{"name":"bge-m3:latest"}]}`))
+	}))
+	defer server.Close()
+
+	checks := checkOllama(context.Background(), server.URL, "llama3", "nomic-embed-text:v1.5")
+	if len(checks) != 3 || checks[0].Status != DoctorOK || checks[1].Status != DoctorOK {
+		t.Fatalf("检查结果错误: %+v", checks)
+	}
+	if checks[2].Status != DoctorFail || checks[2].Fix != "ollama pull nomic-embed-text:v1.5" {
+		t.Errorf("没有下载的模型应该提示 ollama pull: %+v", checks[2])
+	}
+
+	url := server.URL
+	server.Close()
+	checks = checkOllama(context.Background(), url, "llama3")
+	if len(checks) != 1 || checks[0].Status != DoctorFail || !strings.Contains(checks[0].Fix, "ollama serve") {
+		t.Errorf("服务不可达时应该提示启动 Ollama: %+v", checks)
+	}
+}
+
+// 测试目录写权限检查
+func TestCheckWritableDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "crash")
+	if c := checkWritableDir("crash report dir", dir, "crash_report_dir"); c.Status != DoctorOK {
+		t.Fatalf("可写目录检查失败: %+v", c)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("检查后应该删除临时文件: %v", entries)
+	}
+
+	file := filepath.Join(t.TempDir(), "file")
+	os.WriteFile(file, nil, 0644)
+	if c := checkWritableDir("crash report dir", file, "crash_report_dir"); c.Status != DoctorFail || !strings.Contains(c.Fix, "crash_report_dir") {
+		t.Errorf("不是目录时应该失败: %+v", c)
+	}
+}
+
+// 测试 --offline 跳过服务检查，并统计失败项
+func TestEnvironmentDoctor_Offline(t *testing.T) {
+	cfg, _ := config.Load("")
+	cfg.CrashReportDir = t.TempDir()
+	cfg.StructTags.Case = "screaming"
+	result, err := NewEnvironmentDoctor(cfg).Execute(context.Background(), DoctorRequest{Offline: true})
+	if err != nil {
+		t.Fatalf("自检失败: %v", err)
+	}
+	skipped := 0
+	for _, c := range result.Checks {
+		if c.Status == DoctorSkip {
+			skipped++
+		}
+	}
+	if skipped != 2 {
+		t.Errorf("--offline 应该跳过 Ollama 和 Milvus: %+v", result.Checks)
+	}
+	if result.Failed < 1 {
+		t.Errorf("应该统计配置中的失败项: %+v", result)
+	}
+}
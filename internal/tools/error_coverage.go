@@ -0,0 +1,367 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// ErrorCoverageAnalyzer 错误处理覆盖率分析器
+// 基于 go/types 统计每个包中返回 error 的调用有多少被检查、多少被忽略
+type ErrorCoverageAnalyzer struct {
+	*TypedTool[ErrorCoverageRequest, *ErrorCoverageResult]
+}
+
+// NewErrorCoverageAnalyzer 创建错误处理覆盖率分析器
+func NewErrorCoverageAnalyzer() *ErrorCoverageAnalyzer {
+	ea := &ErrorCoverageAnalyzer{}
+	ea.TypedTool = NewTypedTool[ErrorCoverageRequest, *ErrorCoverageResult](
+		"error_coverage",
+		"统计每个包中 error 返回值被检查与被忽略的比例（基于类型信息）",
+		ea,
+	)
+	return ea
+}
+
+// ErrorCoverageRequest 错误处理覆盖率请求
+type ErrorCoverageRequest struct {
+	Directory    string   `json:"directory" jsonschema:"required"` // 模块内的目录
+	Patterns     []string `json:"patterns,omitempty"`              // 包模式，默认 ./...
+	MaxLocations int      `json:"max_locations,omitempty"`         // 每个包最多列出的未检查位置，默认 20
+}
+
+// ErrorCoverageStats 错误处理统计
+type ErrorCoverageStats struct {
+	Total     int     `json:"total"`     // 返回 error 的调用数
+	Checked   int     `json:"checked"`   // 已检查
+	Unchecked int     `json:"unchecked"` // 未检查（以下三类之和）
+	Discarded int     `json:"discarded"` // 作为语句调用，返回值整体丢弃
+	Blank     int     `json:"blank"`     // error 赋给 _
+	Deferred  int     `json:"deferred"`  // defer/go 调用，error 无法被检查
+	Coverage  float64 `json:"coverage"`  // 覆盖率（百分比，无调用时为 100）
+}
+
+// PackageErrorCoverage 单个包的错误处理覆盖率
+type PackageErrorCoverage struct {
+	Package string `json:"package"`
+	ErrorCoverageStats
+	Locations []UncheckedError `json:"locations,omitempty"` // 未检查的位置
+}
+
+// UncheckedError 未检查的 error
+type UncheckedError struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Call string `json:"call"` // 被调用的函数
+	Kind string `json:"kind"` // discarded, blank, deferred
+}
+
+// ErrorCoverageResult 错误处理覆盖率结果
+type ErrorCoverageResult struct {
+	Packages []PackageErrorCoverage `json:"packages"`
+	Total    ErrorCoverageStats     `json:"total"`
+	Errors   []string               `json:"errors,omitempty"` // 包加载/类型错误
+	Summary  string                 `json:"summary"`
+}
+
+// errcheckExcluded 约定上不需要检查 error 的函数（与 errcheck 默认排除项一致）
+var errcheckExcluded = map[string]bool{
+	"fmt.Print":                      true,
+	"fmt.Printf":                     true,
+	"fmt.Println":                    true,
+	"(*bytes.Buffer).Write":          true,
+	"(*bytes.Buffer).WriteByte":      true,
+	"(*bytes.Buffer).WriteRune":      true,
+	"(*bytes.Buffer).WriteString":    true,
+	"(*strings.Builder).Write":       true,
+	"(*strings.Builder).WriteByte":   true,
+	"(*strings.Builder).WriteRune":   true,
+	"(*strings.Builder).WriteString": true,
+	"(*math/rand.Rand).Read":         true,
+	"(hash.Hash).Write":              true,
+	"(*text/tabwriter.Writer).Flush": true,
+}
+
+// ValidateInput 验证输入参数
+func (ea *ErrorCoverageAnalyzer) ValidateInput(req ErrorCoverageRequest) error {
+	if req.Directory == "" {
+		return fmt.Errorf("必须指定 Directory")
+	}
+	return nil
+}
+
+// Execute 执行错误处理覆盖率分析
+func (ea *ErrorCoverageAnalyzer) Execute(ctx context.Context, req ErrorCoverageRequest) (*ErrorCoverageResult, error) {
+	return AnalyzeErrorCoverage(ctx, req)
+}
+
+// AnalyzeErrorCoverage 计算错误处理覆盖率
+func AnalyzeErrorCoverage(ctx context.Context, req ErrorCoverageRequest) (*ErrorCoverageResult, error) {
+	pkgs, err := loadTypedPackages(ctx, req.Directory, req.Patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	maxLocations := req.MaxLocations
+	if maxLocations <= 0 {
+		maxLocations = 20
+	}
+
+	result := &ErrorCoverageResult{Packages: []PackageErrorCoverage{}, Errors: packageErrors(pkgs)}
+	for _, pkg := range pkgs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if pkg.TypesInfo == nil || len(pkg.Syntax) == 0 {
+			continue
+		}
+
+		cov := analyzePackageErrors(pkg, maxLocations)
+		result.Packages = append(result.Packages, cov)
+		result.Total.add(cov.ErrorCoverageStats)
+	}
+	result.Total.finish()
+
+	sort.Slice(result.Packages, func(i, j int) bool {
+		return result.Packages[i].Package < result.Packages[j].Package
+	})
+	result.Summary = fmt.Sprintf("%d 个包，%d 个返回 error 的调用，已检查 %d，未检查 %d，覆盖率 %.1f%%",
+		len(result.Packages), result.Total.Total, result.Total.Checked, result.Total.Unchecked, result.Total.Coverage)
+	return result, nil
+}
+
+// analyzePackageErrors 统计单个包
+func analyzePackageErrors(pkg *packages.Package, maxLocations int) PackageErrorCoverage {
+	cov := PackageErrorCoverage{Package: pkg.PkgPath}
+	errorType := types.Universe.Lookup("error").Type()
+
+	for _, file := range pkg.Syntax {
+		inspectWithParents(file, func(n ast.Node, parents []ast.Node) {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(parents) == 0 {
+				return
+			}
+			errIndexes := errorResultIndexes(pkg.TypesInfo.TypeOf(call), errorType)
+			if len(errIndexes) == 0 {
+				return
+			}
+
+			callee := "?"
+			if fn := typeutil.Callee(pkg.TypesInfo, call); fn != nil {
+				if f, ok := fn.(*types.Func); ok {
+					callee = f.FullName()
+				} else {
+					callee = fn.Name()
+				}
+			}
+			if errcheckExcluded[callee] {
+				return
+			}
+
+			kind := uncheckedKind(call, parents[len(parents)-1], errIndexes)
+			cov.Total++
+			if kind == "" {
+				cov.Checked++
+				return
+			}
+
+			cov.Unchecked++
+			switch kind {
+			case "discarded":
+				cov.Discarded++
+			case "blank":
+				cov.Blank++
+			case "deferred":
+				cov.Deferred++
+			}
+			if len(cov.Locations) < maxLocations {
+				pos := pkg.Fset.Position(call.Pos())
+				cov.Locations = append(cov.Locations, UncheckedError{File: pos.Filename, Line: pos.Line, Call: callee, Kind: kind})
+			}
+		})
+	}
+
+	cov.finish()
+	return cov
+}
+
+// errorResultIndexes 返回调用结果中 error 的位置
+func errorResultIndexes(t types.Type, errorType types.Type) []int {
+	if t == nil {
+		return nil
+	}
+	if tuple, ok := t.(*types.Tuple); ok {
+		var idx []int
+		for i := 0; i < tuple.Len(); i++ {
+			if types.Identical(tuple.At(i).Type(), errorType) {
+				idx = append(idx, i)
+			}
+		}
+		return idx
+	}
+	if types.Identical(t, errorType) {
+		return []int{0}
+	}
+	return nil
+}
+
+// uncheckedKind 根据调用所在的语法位置判断 error 是否被忽略，被检查时返回空串
+func uncheckedKind(call *ast.CallExpr, parent ast.Node, errIndexes []int) string {
+	switch p := parent.(type) {
+	case *ast.ExprStmt:
+		return "discarded"
+	case *ast.DeferStmt, *ast.GoStmt:
+		return "deferred"
+	case *ast.AssignStmt:
+		return blankKind(p.Lhs, p.Rhs, call, errIndexes)
+	case *ast.ValueSpec:
+		lhs := make([]ast.Expr, len(p.Names))
+		for i, name := range p.Names {
+			lhs[i] = name
+		}
+		return blankKind(lhs, p.Values, call, errIndexes)
+	}
+	return ""
+}
+
+// blankKind 判断赋值语句中 error 对应的左值是否为 _
+func blankKind(lhs, rhs []ast.Expr, call *ast.CallExpr, errIndexes []int) string {
+	for i, r := range rhs {
+		if r != call {
+			continue
+		}
+		// 多返回值调用：左值与返回值一一对应；单返回值：左值与右值一一对应
+		targets := []int{i}
+		if len(rhs) == 1 {
+			targets = errIndexes
+		}
+		for _, idx := range targets {
+			if idx < len(lhs) {
+				if ident, ok := lhs[idx].(*ast.Ident); ok && ident.Name == "_" {
+					return "blank"
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// add 累加统计
+func (s *ErrorCoverageStats) add(other ErrorCoverageStats) {
+	s.Total += other.Total
+	s.Checked += other.Checked
+	s.Unchecked += other.Unchecked
+	s.Discarded += other.Discarded
+	s.Blank += other.Blank
+	s.Deferred += other.Deferred
+}
+
+// finish 计算覆盖率
+func (s *ErrorCoverageStats) finish() {
+	s.Coverage = 100
+	if s.Total > 0 {
+		s.Coverage = float64(s.Checked) * 100 / float64(s.Total)
+	}
+}
+
+// ==================== 历史记录 ====================
+
+// ErrorCoverageSnapshot 某次分析的覆盖率快照（按行追加到 JSONL 历史文件）
+type ErrorCoverageSnapshot struct {
+	Time     time.Time          `json:"time"`
+	Target   string             `json:"target"`
+	Total    ErrorCoverageStats `json:"total"`
+	Packages map[string]float64 `json:"packages"` // 包 -> 覆盖率
+}
+
+// NewErrorCoverageSnapshot 由分析结果生成快照
+func NewErrorCoverageSnapshot(target string, result *ErrorCoverageResult) ErrorCoverageSnapshot {
+	snapshot := ErrorCoverageSnapshot{
+		Time:     time.Now(),
+		Target:   target,
+		Total:    result.Total,
+		Packages: make(map[string]float64),
+	}
+	for _, pkg := range result.Packages {
+		snapshot.Packages[pkg.Package] = pkg.Coverage
+	}
+	return snapshot
+}
+
+// AppendErrorCoverageHistory 将快照追加到历史文件，返回同一目标的上一次快照（没有时为 nil）
+// 历史文件经由 ctx 中的工作区读写：指定 --out-dir 时位于输出目录下，dry-run 时只记录到变更集
+func AppendErrorCoverageHistory(ctx context.Context, historyFile string, snapshot ErrorCoverageSnapshot) (*ErrorCoverageSnapshot, error) {
+	var previous *ErrorCoverageSnapshot
+
+	data, err := os.ReadFile(WorkspaceFromContext(ctx).OutputPath(historyFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("读取历史记录失败: %w", err)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var s ErrorCoverageSnapshot
+		if json.Unmarshal(scanner.Bytes(), &s) == nil && s.Target == snapshot.Target {
+			previous = &s
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取历史记录失败: %w", err)
+	}
+
+	line, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("序列化快照失败: %w", err)
+	}
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+	if _, err := WriteOutputFile(ctx, historyFile, append(append(data, line...), '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("写入历史记录失败: %w", err)
+	}
+	return previous, nil
+}
+
+// FormatErrorCoverage 生成文本报告，previous 不为空时显示与上一次的差值
+func FormatErrorCoverage(result *ErrorCoverageResult, previous *ErrorCoverageSnapshot) string {
+	var sb strings.Builder
+	sb.WriteString("📈 错误处理覆盖率\n")
+	for _, pkg := range result.Packages {
+		if pkg.Total == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("  %-50s %6.1f%%  (%d/%d)%s\n", pkg.Package, pkg.Coverage, pkg.Checked, pkg.Total, coverageDelta(pkg.Coverage, previous, pkg.Package)))
+	}
+	sb.WriteString(fmt.Sprintf("  %-50s %6.1f%%  (%d/%d)%s\n", "合计", result.Total.Coverage, result.Total.Checked, result.Total.Total, coverageDelta(result.Total.Coverage, previous, "")))
+	if result.Total.Unchecked > 0 {
+		sb.WriteString(fmt.Sprintf("⚠️ 未检查 %d 处：丢弃返回值 %d，赋给 _ %d，defer/go %d\n",
+			result.Total.Unchecked, result.Total.Discarded, result.Total.Blank, result.Total.Deferred))
+	}
+	return sb.String()
+}
+
+// coverageDelta 与上一次快照的差值，pkg 为空表示合计
+func coverageDelta(current float64, previous *ErrorCoverageSnapshot, pkg string) string {
+	if previous == nil {
+		return ""
+	}
+	before := previous.Total.Coverage
+	if pkg != "" {
+		var ok bool
+		if before, ok = previous.Packages[pkg]; !ok {
+			return "  新增"
+		}
+	}
+	return fmt.Sprintf("  %+.1f", current-before)
+}
@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 测试按包统计 error 检查情况
+func TestAnalyzeErrorCoverage(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/demo\n\ngo 1.21\n",
+		"store/store.go": `package store
+
+import (
+	"fmt"
+	"os"
+)
+
+func Save(name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	os.Remove(name + ".bak")
+	_ = os.Chmod(name, 0600)
+	n, _ := f.WriteString("data")
+	fmt.Println(n)
+	return f.Sync()
+}
+`,
+		"util/util.go": `package util
+
+func Add(a, b int) int {
+	return a + b
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("创建目录失败: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("写入测试文件失败: %v", err)
+		}
+	}
+
+	result, err := AnalyzeErrorCoverage(context.Background(), ErrorCoverageRequest{Directory: dir})
+	if err != nil {
+		t.Fatalf("统计失败: %v", err)
+	}
+	if len(result.Packages) != 2 {
+		t.Fatalf("应该统计 2 个包: %+v", result.Packages)
+	}
+
+	store := result.Packages[0]
+	if store.Package != "example.com/demo/store" {
+		t.Fatalf("包排序不正确: %+v", result.Packages)
+	}
+	// os.Create、f.Sync 已检查；defer f.Close、os.Remove、_ = os.Chmod、n, _ := WriteString 未检查；fmt.Println 排除
+	if store.Total != 6 || store.Checked != 2 || store.Deferred != 1 || store.Discarded != 1 || store.Blank != 2 {
+		t.Fatalf("统计不正确: %+v", store.ErrorCoverageStats)
+	}
+	if len(store.Locations) != 4 {
+		t.Fatalf("应该列出 4 个未检查位置: %+v", store.Locations)
+	}
+
+	if util := result.Packages[1]; util.Total != 0 || util.Coverage != 100 {
+		t.Fatalf("没有 error 调用的包覆盖率应为 100: %+v", util)
+	}
+}
+
+// 测试历史记录与差值
+func TestErrorCoverageHistory(t *testing.T) {
+	history := filepath.Join(t.TempDir(), "metrics", "errors.jsonl")
+
+	first := &ErrorCoverageResult{
+		Packages: []PackageErrorCoverage{{Package: "a", ErrorCoverageStats: ErrorCoverageStats{Total: 4, Checked: 2, Coverage: 50}}},
+		Total:    ErrorCoverageStats{Total: 4, Checked: 2, Coverage: 50},
+	}
+	previous, err := AppendErrorCoverageHistory(context.Background(), history, NewErrorCoverageSnapshot("/repo", first))
+	if err != nil || previous != nil {
+		t.Fatalf("首次记录不应有上一次快照: %v %v", previous, err)
+	}
+
+	second := &ErrorCoverageResult{
+		Packages: []PackageErrorCoverage{
+			{Package: "a", ErrorCoverageStats: ErrorCoverageStats{Total: 4, Checked: 3, Coverage: 75}},
+			{Package: "b", ErrorCoverageStats: ErrorCoverageStats{Total: 1, Checked: 1, Coverage: 100}},
+		},
+		Total: ErrorCoverageStats{Total: 5, Checked: 4, Coverage: 80},
+	}
+	previous, err = AppendErrorCoverageHistory(context.Background(), history, NewErrorCoverageSnapshot("/repo", second))
+	if err != nil || previous == nil || previous.Total.Coverage != 50 {
+		t.Fatalf("应该返回上一次快照: %+v %v", previous, err)
+	}
+
+	report := FormatErrorCoverage(second, previous)
+	for _, want := range []string{"+25.0", "新增", "+30.0"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("报告缺少 %q:\n%s", want, report)
+		}
+	}
+}
+
+// 测试历史记录经由工作区写入：dry-run 时不创建文件，仍能读到已有的上一次快照
+func TestErrorCoverageHistory_DryRun(t *testing.T) {
+	history := filepath.Join(t.TempDir(), "metrics", "errors.jsonl")
+	result := &ErrorCoverageResult{Total: ErrorCoverageStats{Total: 2, Checked: 1, Coverage: 50}}
+
+	ws := NewWorkspace("")
+	ws.ChangeSet = NewChangeSet()
+	ctx := WithWorkspace(context.Background(), ws)
+	if _, err := AppendErrorCoverageHistory(ctx, history, NewErrorCoverageSnapshot("/repo", result)); err != nil {
+		t.Fatalf("记录失败: %v", err)
+	}
+	if _, err := os.Stat(history); !os.IsNotExist(err) {
+		t.Errorf("dry-run 不应创建历史文件: %v", err)
+	}
+	if changes := ws.ChangeSet.Changes(); len(changes) != 1 || !changes[0].Created || !strings.Contains(changes[0].Diff, `+{"time"`) {
+		t.Errorf("dry-run 应该记录历史文件的变更: %+v", changes)
+	}
+
+	if _, err := AppendErrorCoverageHistory(context.Background(), history, NewErrorCoverageSnapshot("/repo", result)); err != nil {
+		t.Fatalf("记录失败: %v", err)
+	}
+	before, _ := os.ReadFile(history)
+	previous, err := AppendErrorCoverageHistory(ctx, history, NewErrorCoverageSnapshot("/repo", result))
+	if err != nil || previous == nil || previous.Total.Coverage != 50 {
+		t.Fatalf("dry-run 也应该返回上一次快照: %+v %v", previous, err)
+	}
+	if after, _ := os.ReadFile(history); string(after) != string(before) {
+		t.Errorf("dry-run 不应修改历史文件")
+	}
+}
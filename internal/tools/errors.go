@@ -10,6 +10,8 @@ var (
 	ErrToolTimeout     = errors.New("工具执行超时")
 	ErrToolExecution   = errors.New("工具执行失败")
 	ErrInputValidation = errors.New("输入验证失败")
+	ErrToolPanic       = errors.New("工具执行崩溃")
+	ErrResourceLimit   = errors.New("超出工具资源限制")
 )
 
 // IsToolError 判断是否是工具相关错误
@@ -19,5 +21,7 @@ func IsToolError(err error) bool {
 		errors.Is(err, ErrInvalidInput) ||
 		errors.Is(err, ErrToolTimeout) ||
 		errors.Is(err, ErrToolExecution) ||
-		errors.Is(err, ErrInputValidation)
+		errors.Is(err, ErrInputValidation) ||
+		errors.Is(err, ErrToolPanic) ||
+		errors.Is(err, ErrResourceLimit)
 }
@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ParseSeverity 解析严重程度名称（不区分大小写），返回规范写法（Low、Medium、High、Critical）
+func ParseSeverity(name string) (string, error) {
+	level := severityLevels[severityIndex(name)]
+	if !strings.EqualFold(strings.TrimSpace(name), level) {
+		return "", fmt.Errorf("%w: 未知的严重程度 %q（可选 %s）", ErrInvalidInput, name, strings.Join(severityLevels, "、"))
+	}
+	return level, nil
+}
+
+// ComplexitySeverity 按复杂度区间给函数定级，与 Statistics 的分档一致：
+// 简单函数（1-10）不算问题，中等为 Medium，复杂为 High，非常复杂（>50）为 Critical
+func ComplexitySeverity(complexity int) string {
+	switch {
+	case complexity > 50:
+		return "Critical"
+	case complexity > 20:
+		return "High"
+	case complexity > 10:
+		return "Medium"
+	default:
+		return ""
+	}
+}
+
+// ResultSeverities 取出工具结果 JSON 中每个问题的严重程度
+// 支持 bug_detector、security_scanner 和 complexity_analyzer（按 ComplexitySeverity 定级）
+// 超出问题数量上限没有列出的问题也计入
+func ResultSeverities(tool, result string) ([]string, error) {
+	var severities []string
+	switch tool {
+	case "bug_detector":
+		var r BugResult
+		if err := json.Unmarshal([]byte(result), &r); err != nil {
+			return nil, fmt.Errorf("解析 Bug 检测结果失败: %w", err)
+		}
+		for _, bug := range r.Bugs {
+			severities = append(severities, bug.Severity)
+		}
+		severities = append(severities, r.Truncated.Severities()...)
+	case "security_scanner":
+		var r SecurityResult
+		if err := json.Unmarshal([]byte(result), &r); err != nil {
+			return nil, fmt.Errorf("解析安全扫描结果失败: %w", err)
+		}
+		for _, issue := range r.Issues {
+			severities = append(severities, issue.Severity)
+		}
+		severities = append(severities, r.Truncated.Severities()...)
+	case "complexity_analyzer":
+		var r ComplexityResult
+		if err := json.Unmarshal([]byte(result), &r); err != nil {
+			return nil, fmt.Errorf("解析复杂度分析结果失败: %w", err)
+		}
+		for _, fn := range r.Functions {
+			if severity := ComplexitySeverity(fn.Complexity); severity != "" {
+				severities = append(severities, severity)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%w: 工具 %s 的结果没有严重程度", ErrInvalidInput, tool)
+	}
+	return severities, nil
+}
+
+// CountAtOrAbove 统计严重程度不低于 threshold 的问题数
+func CountAtOrAbove(severities []string, threshold string) int {
+	floor := severityIndex(threshold)
+	count := 0
+	for _, severity := range severities {
+		if severityIndex(severity) >= floor {
+			count++
+		}
+	}
+	return count
+}
+
+// CheckFailOn 实现 --fail-on：存在严重程度不低于 threshold 的问题时返回错误，使进程以非零状态退出
+// threshold 为空时不检查
+func CheckFailOn(severities []string, threshold string) error {
+	if threshold == "" {
+		return nil
+	}
+	if n := CountAtOrAbove(severities, threshold); n > 0 {
+		return fmt.Errorf("发现 %d 个严重程度不低于 %s 的问题（--fail-on %s）", n, threshold, threshold)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"errors"
+	"testing"
+)
+
+// 测试严重程度名称的解析
+func TestParseSeverity(t *testing.T) {
+	level, err := ParseSeverity(" high ")
+	if err != nil || level != "High" {
+		t.Errorf("ParseSeverity(high) = %q, %v, want High", level, err)
+	}
+	if _, err := ParseSeverity("urgent"); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("未知的严重程度应该返回 ErrInvalidInput，实际 %v", err)
+	}
+}
+
+// 测试按阈值统计问题和 --fail-on 的判断
+func TestCheckFailOn(t *testing.T) {
+	severities := []string{"Low", "Medium", "High", "Critical", "high"}
+	if n := CountAtOrAbove(severities, "High"); n != 3 {
+		t.Errorf("不低于 High 的问题数 = %d, want 3", n)
+	}
+	if n := CountAtOrAbove(severities, "Low"); n != 5 {
+		t.Errorf("不低于 Low 的问题数 = %d, want 5", n)
+	}
+	if err := CheckFailOn(severities, ""); err != nil {
+		t.Errorf("未指定阈值时不应失败: %v", err)
+	}
+	if err := CheckFailOn([]string{"Low", "Medium"}, "High"); err != nil {
+		t.Errorf("没有达到阈值的问题时不应失败: %v", err)
+	}
+	if err := CheckFailOn(severities, "Critical"); err == nil {
+		t.Error("存在 Critical 问题时应该失败")
+	}
+}
+
+// 测试从工具结果中取出严重程度
+func TestResultSeverities(t *testing.T) {
+	severities, err := ResultSeverities("bug_detector", `{"bugs":[{"severity":"High"},{"severity":"Low"}]}`)
+	if err != nil || len(severities) != 2 || severities[0] != "High" {
+		t.Errorf("bug_detector 严重程度 = %v, %v", severities, err)
+	}
+	severities, err = ResultSeverities("security_scanner", `{"issues":[{"severity":"Critical"}]}`)
+	if err != nil || len(severities) != 1 || severities[0] != "Critical" {
+		t.Errorf("security_scanner 严重程度 = %v, %v", severities, err)
+	}
+	severities, err = ResultSeverities("complexity_analyzer", `{"functions":[{"complexity":3},{"complexity":15},{"complexity":60}]}`)
+	if err != nil || len(severities) != 2 || severities[0] != "Medium" || severities[1] != "Critical" {
+		t.Errorf("complexity_analyzer 严重程度 = %v, %v", severities, err)
+	}
+	if _, err := ResultSeverities("search_file", `{}`); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("没有严重程度的工具应该返回 ErrInvalidInput，实际 %v", err)
+	}
+}
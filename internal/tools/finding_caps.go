@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"go-ai-study/internal/i18n"
+	"maps"
+	"slices"
+)
+
+// Truncation 超出问题数量上限、没有在结果中列出的问题
+// 结果的 Total 和 Statistics 仍包含这些问题，只是不再逐条输出
+type Truncation struct {
+	Total      int            `json:"total"`                 // 未列出的问题数
+	ByRule     map[string]int `json:"by_rule"`               // 每条规则未列出的问题数
+	BySeverity map[string]int `json:"by_severity,omitempty"` // 每个严重程度未列出的问题数，--fail-on 仍计入
+}
+
+// Severities 未列出问题的严重程度，每个问题一项
+func (t *Truncation) Severities() []string {
+	if t == nil {
+		return nil
+	}
+	var severities []string
+	for _, severity := range slices.Sorted(maps.Keys(t.BySeverity)) {
+		for range t.BySeverity[severity] {
+			severities = append(severities, severity)
+		}
+	}
+	return severities
+}
+
+// findingCaps context 中的问题数量上限（见 ResourceLimits），0 表示不限制
+func findingCaps(ctx context.Context) (total, perRule int) {
+	budget, _ := ctx.Value(limitsKey{}).(*scanBudget)
+	if budget == nil {
+		return 0, 0
+	}
+	return budget.limits.MaxFindings, budget.limits.MaxFindingsPerRule
+}
+
+// capFindings 先按规则、再按总数截断问题列表，保留的问题保持原有顺序
+// 超出总数上限时优先保留严重程度高的问题；没有截断时返回 nil
+func capFindings[T any](items []T, ruleSeverity func(T) (string, string), total, perRule int) ([]T, *Truncation) {
+	keep := make([]bool, len(items))
+	perRuleCount := make(map[string]int)
+	var candidates []int
+	for i, item := range items {
+		rule, _ := ruleSeverity(item)
+		perRuleCount[rule]++
+		if perRule > 0 && perRuleCount[rule] > perRule {
+			continue
+		}
+		candidates = append(candidates, i)
+	}
+	if total > 0 && len(candidates) > total {
+		slices.SortStableFunc(candidates, func(a, b int) int {
+			_, sa := ruleSeverity(items[a])
+			_, sb := ruleSeverity(items[b])
+			return severityIndex(sb) - severityIndex(sa)
+		})
+		candidates = candidates[:total]
+	}
+	for _, i := range candidates {
+		keep[i] = true
+	}
+	if len(candidates) == len(items) {
+		return items, nil
+	}
+
+	kept := make([]T, 0, len(candidates))
+	truncation := &Truncation{ByRule: make(map[string]int), BySeverity: make(map[string]int)}
+	for i, item := range items {
+		if keep[i] {
+			kept = append(kept, item)
+			continue
+		}
+		rule, severity := ruleSeverity(item)
+		truncation.Total++
+		truncation.ByRule[rule]++
+		truncation.BySeverity[severity]++
+	}
+	return kept, truncation
+}
+
+// truncatedSummary 摘要中说明有多少问题超出上限没有列出
+func truncatedSummary(t *Truncation) string {
+	if t == nil {
+		return ""
+	}
+	return i18n.T("findings.summary.truncated", t.Total)
+}
+
+// capBugResult 按 context 中的上限截断 Bug 检测结果，Total 和 Statistics 保持截断前的数量
+func capBugResult(ctx context.Context, result *BugResult) {
+	total, perRule := findingCaps(ctx)
+	if total <= 0 && perRule <= 0 {
+		return
+	}
+	result.Bugs, result.Truncated = capFindings(result.Bugs, func(b BugIssue) (string, string) { return b.RuleID, b.Severity }, total, perRule)
+	result.Summary += truncatedSummary(result.Truncated)
+}
+
+// capSecurityResult 按 context 中的上限截断安全扫描结果，Total 和 Statistics 保持截断前的数量
+func capSecurityResult(ctx context.Context, result *SecurityResult) {
+	total, perRule := findingCaps(ctx)
+	if total <= 0 && perRule <= 0 {
+		return
+	}
+	result.Issues, result.Truncated = capFindings(result.Issues, func(i SecurityIssue) (string, string) { return i.RuleID, i.Severity }, total, perRule)
+	result.Summary += truncatedSummary(result.Truncated)
+}
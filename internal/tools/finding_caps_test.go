@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// 测试按规则和总数截断问题：未列出的问题记入 truncated，total、statistics 和 --fail-on 仍按全部问题计算
+func TestFindingCaps(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("package main\n\nimport \"crypto/md5\"\n\n")
+	for _, name := range []string{"A", "B", "C", "D", "E"} {
+		sb.WriteString("func " + name + "() {\n\tpassword := \"admin123\"\n\t_ = password\n}\n\n")
+	}
+	sb.WriteString("func F() { md5.New() }\n")
+	code := sb.String()
+
+	scanner := NewSecurityScanner()
+	scan := func(limits ResourceLimits) (SecurityResult, string) {
+		t.Helper()
+		ctx := WithResourceLimits(context.Background(), limits)
+		out, err := scanner.Run(ctx, SecurityScanInput{Code: code, File: "main.go"})
+		if err != nil {
+			t.Fatalf("扫描失败: %v", err)
+		}
+		var result SecurityResult
+		if err := json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatalf("解析结果失败: %v", err)
+		}
+		return result, out
+	}
+
+	full, _ := scan(ResourceLimits{})
+	if full.Truncated != nil || full.Total != 6 {
+		t.Fatalf("没有上限时不应截断: total=%d truncated=%+v", full.Total, full.Truncated)
+	}
+
+	perRule, _ := scan(ResourceLimits{MaxFindingsPerRule: 2})
+	if len(perRule.Issues) != 3 || perRule.Truncated == nil || perRule.Truncated.Total != 3 || perRule.Truncated.ByRule["G101"] != 3 {
+		t.Fatalf("按规则截断错误: issues=%d truncated=%+v", len(perRule.Issues), perRule.Truncated)
+	}
+	if perRule.Total != full.Total || !reflect.DeepEqual(perRule.Statistics, full.Statistics) {
+		t.Errorf("截断后 total/statistics 应保持不变: %d %+v", perRule.Total, perRule.Statistics)
+	}
+	if !strings.Contains(perRule.Summary, "3") {
+		t.Errorf("摘要中没有截断说明: %s", perRule.Summary)
+	}
+
+	// 总数上限优先保留严重程度高的问题
+	global, out := scan(ResourceLimits{MaxFindings: 1})
+	if len(global.Issues) != 1 || global.Truncated.Total != 5 {
+		t.Fatalf("按总数截断错误: issues=%d truncated=%+v", len(global.Issues), global.Truncated)
+	}
+	for _, issue := range full.Issues {
+		if severityIndex(issue.Severity) > severityIndex(global.Issues[0].Severity) {
+			t.Errorf("保留了 %s(%s)，丢弃了更严重的 %s(%s)", global.Issues[0].RuleID, global.Issues[0].Severity, issue.RuleID, issue.Severity)
+		}
+	}
+	severities, err := ResultSeverities("security_scanner", out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(severities) != full.Total {
+		t.Errorf("--fail-on 的问题数 = %d, want %d", len(severities), full.Total)
+	}
+}
@@ -0,0 +1,414 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/i18n"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Finding 跨工具的统一问题视图（bug_detector / security_scanner）
+type Finding struct {
+	Tool        string `json:"tool"`               // 来源工具
+	Fingerprint string `json:"fingerprint"`        // 稳定指纹
+	RuleID      string `json:"rule_id"`            // 规则ID
+	Severity    string `json:"severity"`           // 严重程度
+	Category    string `json:"category"`           // 问题类别
+	Description string `json:"description"`        // 问题描述
+	File        string `json:"file"`               // 文件名
+	Line        int    `json:"line"`               // 行号
+	Column      int    `json:"column"`             // 列号（从 1 开始）
+	Function    string `json:"function"`           // 所在函数
+	Receiver    string `json:"receiver,omitempty"` // 所在方法的接收者类型
+	Exported    bool   `json:"exported"`           // 所在函数是否导出
+	CodeSnippet string `json:"code_snippet"`       // 代码片段
+	Suggestion  string `json:"suggestion"`         // 修复建议
+	Confidence  string `json:"confidence"`         // 置信度
+}
+
+// RuleDoc 规则文档
+type RuleDoc struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Tool        string `json:"tool"`
+	Category    string `json:"category"`
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+	Suggestion  string `json:"suggestion"`
+}
+
+// ruleText 返回当前语言下规则的描述或建议（field 为 description、suggestion）
+// 规则代码中的中文文本就是默认语言的文本，消息目录中只需要提供其他语言的翻译
+func ruleText(ruleID, field, fallback string) string {
+	return i18n.Text("rule."+ruleID+"."+field, fallback)
+}
+
+// FindingFingerprint 计算问题指纹
+// 由规则、文件和归一化后的代码片段决定，不随行号移动而变化
+func FindingFingerprint(ruleID, file, snippet string) string {
+	normalized := strings.Join(strings.Fields(snippet), " ")
+	sum := sha1.Sum([]byte(ruleID + "|" + filepath.ToSlash(file) + "|" + normalized))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// LookupRuleDoc 查找内置规则的文档
+func LookupRuleDoc(ruleID string) (RuleDoc, bool) {
+	bugEngine := NewBugRuleEngine()
+	bugEngine.RegisterAllRules()
+	for _, rule := range bugEngine.Rules {
+		if rule.ID() == ruleID {
+			return RuleDoc{
+				ID:          rule.ID(),
+				Name:        rule.Name(),
+				Tool:        "bug_detector",
+				Category:    NormalizeCategory(rule.Category()),
+				Severity:    rule.Severity(),
+				Description: ruleText(rule.ID(), "description", rule.Description()),
+				Suggestion:  ruleText(rule.ID(), "suggestion", rule.GenerateSuggestion(nil)),
+			}, true
+		}
+	}
+
+	secEngine := NewRuleEngine()
+	secEngine.RegisterAllRules()
+	for _, rule := range secEngine.Rules {
+		if rule.ID() == ruleID {
+			return RuleDoc{
+				ID:          rule.ID(),
+				Name:        rule.Name(),
+				Tool:        "security_scanner",
+				Category:    NormalizeCategory(rule.Category()),
+				Severity:    rule.Severity(),
+				Description: ruleText(rule.ID(), "description", rule.Description()),
+				Suggestion:  ruleText(rule.ID(), "suggestion", rule.Suggestion()),
+			}, true
+		}
+	}
+	return RuleDoc{}, false
+}
+
+// CollectFindings 对文件或目录运行 Bug 检测和安全扫描，汇总为统一的问题列表
+func CollectFindings(ctx context.Context, tm *ToolManager, target string) ([]Finding, error) {
+	info, err := os.Stat(target)
+	if err != nil {
+		return nil, fmt.Errorf("读取路径失败: %w", err)
+	}
+
+	var findings []Finding
+
+	// Bug 检测
+	bugInput := BugDetectorInput{Files: []string{target}}
+	if info.IsDir() {
+		bugInput = BugDetectorInput{Directory: target}
+	}
+	bugResult, err := tm.Run(ctx, "bug_detector", bugInput)
+	if err != nil {
+		return nil, fmt.Errorf("Bug 检测失败: %w", err)
+	}
+	if !bugResult.Success {
+		return nil, fmt.Errorf("Bug 检测失败: %s", bugResult.Error)
+	}
+	var result BugResult
+	if err := json.Unmarshal([]byte(bugResult.Result), &result); err != nil {
+		return nil, fmt.Errorf("解析 Bug 检测结果失败: %w", err)
+	}
+	for _, bug := range result.Bugs {
+		findings = append(findings, bugFinding(bug))
+	}
+
+	// 安全扫描
+	secFindings, _, err := scanSecurityFindings(ctx, tm, target, info.IsDir(), "")
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, secFindings...)
+
+	sortFindings(findings)
+	return findings, nil
+}
+
+// CollectSecurityFindings 对文件或目录运行安全扫描，返回统一的问题列表和超出数量上限没有列出的问题
+// minConfidence 非空时只保留不低于该置信度的问题
+func CollectSecurityFindings(ctx context.Context, tm *ToolManager, target, minConfidence string) ([]Finding, *Truncation, error) {
+	info, err := os.Stat(target)
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取路径失败: %w", err)
+	}
+	findings, truncated, err := scanSecurityFindings(ctx, tm, target, info.IsDir(), minConfidence)
+	if err != nil {
+		return nil, nil, err
+	}
+	sortFindings(findings)
+	return findings, truncated, nil
+}
+
+// scanSecurityFindings 运行安全扫描：目录交给扫描器逐个文件扫描，单个文件按文件列表扫描
+func scanSecurityFindings(ctx context.Context, tm *ToolManager, target string, isDir bool, minConfidence string) ([]Finding, *Truncation, error) {
+	input := SecurityScanInput{Files: []string{target}, MinConfidence: minConfidence}
+	if isDir {
+		input = SecurityScanInput{Directory: target, MinConfidence: minConfidence}
+	}
+	secResult, err := tm.Run(ctx, "security_scanner", input)
+	if err != nil {
+		return nil, nil, fmt.Errorf("安全扫描失败: %w", err)
+	}
+	if !secResult.Success {
+		return nil, nil, fmt.Errorf("安全扫描失败: %s", secResult.Error)
+	}
+	var result SecurityResult
+	if err := json.Unmarshal([]byte(secResult.Result), &result); err != nil {
+		return nil, nil, fmt.Errorf("解析安全扫描结果失败: %w", err)
+	}
+	findings := make([]Finding, 0, len(result.Issues))
+	for _, issue := range result.Issues {
+		findings = append(findings, securityFinding(issue))
+	}
+	return findings, result.Truncated, nil
+}
+
+// sortFindings 按文件和行号排序，并保证指纹唯一
+func sortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	disambiguateFingerprints(findings)
+}
+
+// FindingGroup 相同规则、相同代码片段的一组问题
+// 同一段不安全的代码被复制到多个文件时合并为一条，避免报告被几十条相同的问题淹没
+type FindingGroup struct {
+	Fingerprint string              `json:"fingerprint"`  // 组指纹（由规则和归一化后的代码片段决定，与文件无关）
+	RuleID      string              `json:"rule_id"`      // 规则ID
+	Severity    string              `json:"severity"`     // 严重程度
+	Category    string              `json:"category"`     // 问题类别
+	Description string              `json:"description"`  // 问题描述
+	CodeSnippet string              `json:"code_snippet"` // 代码片段
+	Suggestion  string              `json:"suggestion"`   // 修复建议
+	Count       int                 `json:"count"`        // 出现次数
+	Files       int                 `json:"files"`        // 涉及的文件数
+	Occurrences []FindingOccurrence `json:"occurrences"`  // 每次出现的位置
+}
+
+// FindingOccurrence 问题组中的一次出现
+type FindingOccurrence struct {
+	Fingerprint string `json:"fingerprint"` // 该问题自身的指纹
+	File        string `json:"file"`
+	Line        int    `json:"line"`
+	Function    string `json:"function"`
+}
+
+// GroupFindings 按规则和归一化后的代码片段合并问题
+// 没有代码片段的问题无法判断是否相同，各自成组；结果按出现次数从多到少排列，次数相同时保持原顺序
+func GroupFindings(findings []Finding) []FindingGroup {
+	var groups []FindingGroup
+	index := make(map[string]int)
+	files := make(map[string]map[string]bool)
+	for _, f := range findings {
+		snippet := strings.Join(strings.Fields(f.CodeSnippet), " ")
+		key := f.RuleID + "|" + snippet
+		i, ok := index[key]
+		if !ok || snippet == "" {
+			i = len(groups)
+			index[key] = i
+			files[key] = make(map[string]bool)
+			fingerprint := f.Fingerprint
+			if snippet != "" {
+				fingerprint = FindingFingerprint(f.RuleID, "", snippet)
+			}
+			groups = append(groups, FindingGroup{
+				Fingerprint: fingerprint,
+				RuleID:      f.RuleID,
+				Severity:    f.Severity,
+				Category:    f.Category,
+				Description: f.Description,
+				CodeSnippet: f.CodeSnippet,
+				Suggestion:  f.Suggestion,
+			})
+		}
+		g := &groups[i]
+		g.Count++
+		if !files[key][f.File] {
+			files[key][f.File] = true
+			g.Files++
+		}
+		g.Occurrences = append(g.Occurrences, FindingOccurrence{
+			Fingerprint: f.Fingerprint,
+			File:        f.File,
+			Line:        f.Line,
+			Function:    f.Function,
+		})
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Count > groups[j].Count
+	})
+	return groups
+}
+
+// bugFinding 把 Bug 检测结果转换为统一的问题视图
+func bugFinding(bug BugIssue) Finding {
+	return Finding{
+		Tool:        "bug_detector",
+		Fingerprint: FindingFingerprint(bug.RuleID, bug.File, bug.CodeSnippet),
+		RuleID:      bug.RuleID,
+		Severity:    bug.Severity,
+		Category:    bug.Category,
+		Description: bug.Description,
+		File:        bug.File,
+		Line:        bug.Line,
+		Column:      bug.Column,
+		Function:    bug.Function,
+		Receiver:    bug.Receiver,
+		Exported:    bug.Exported,
+		CodeSnippet: bug.CodeSnippet,
+		Suggestion:  bug.FixSuggestion,
+		Confidence:  bug.Confidence,
+	}
+}
+
+// securityFinding 把安全扫描结果转换为统一的问题视图
+func securityFinding(issue SecurityIssue) Finding {
+	return Finding{
+		Tool:        "security_scanner",
+		Fingerprint: FindingFingerprint(issue.RuleID, issue.File, issue.CodeSnippet),
+		RuleID:      issue.RuleID,
+		Severity:    issue.Severity,
+		Category:    issue.Category,
+		Description: issue.Description,
+		File:        issue.File,
+		Line:        issue.Line,
+		Column:      issue.Column,
+		Function:    issue.Function,
+		Receiver:    issue.Receiver,
+		Exported:    issue.Exported,
+		CodeSnippet: issue.CodeSnippet,
+		Suggestion:  issue.Suggestion,
+		Confidence:  issue.Confidence,
+	}
+}
+
+// disambiguateFingerprints 同一文件中相同代码片段的问题按出现顺序追加序号，保证指纹唯一
+func disambiguateFingerprints(findings []Finding) {
+	seen := make(map[string]int)
+	for i := range findings {
+		fp := findings[i].Fingerprint
+		if n := seen[fp]; n > 0 {
+			findings[i].Fingerprint = FindingFingerprint(findings[i].RuleID, findings[i].File,
+				fmt.Sprintf("%s#%d", findings[i].CodeSnippet, n))
+		}
+		seen[fp]++
+	}
+}
+
+// FindFinding 按指纹（或指纹前缀）查找问题
+func FindFinding(findings []Finding, fingerprint string) (*Finding, error) {
+	var matches []*Finding
+	for i := range findings {
+		if strings.HasPrefix(findings[i].Fingerprint, fingerprint) {
+			matches = append(matches, &findings[i])
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("未找到指纹为 %s 的问题", fingerprint)
+	case 1:
+		return matches[0], nil
+	default:
+		var ids []string
+		for _, m := range matches {
+			ids = append(ids, fmt.Sprintf("%s(%s:%d)", m.Fingerprint, m.File, m.Line))
+		}
+		return nil, fmt.Errorf("指纹前缀 %s 匹配到多个问题: %s", fingerprint, strings.Join(ids, ", "))
+	}
+}
+
+// DiscoverGoFiles 收集目录下的 Go 文件，经由 context 中的文件发现方式（默认遍历文件系统，跳过隐藏目录）
+func DiscoverGoFiles(ctx context.Context, dir string) ([]string, error) {
+	return collectGoFiles(ctx, dir)
+}
+
+// collectSourceFiles 按文件列表或目录收集要分析的 Go 文件，其他语言的文件记为跳过（原因为 skipReason）
+// 文件列表优先；列表中不存在的文件忽略。目录经由 context 中的文件发现方式获取，受资源上限约束
+func collectSourceFiles(ctx context.Context, files []string, dir, skipReason string) ([]string, []FileStatus, error) {
+	var goFiles []string
+	var otherFiles []FileStatus
+	skipped := func(path, lang string) FileStatus {
+		return FileStatus{Path: path, Language: lang, Status: "skipped", Reason: skipReason}
+	}
+
+	if len(files) > 0 {
+		for _, file := range files {
+			lang := DetectLanguage(file)
+			if lang == "go" {
+				if _, err := os.Stat(file); err == nil {
+					goFiles = append(goFiles, file)
+				}
+			} else {
+				otherFiles = append(otherFiles, skipped(file, lang))
+			}
+		}
+		return goFiles, otherFiles, nil
+	}
+
+	// 文件经由 context 中的文件发现方式获取（默认遍历文件系统，跳过隐藏目录和 go.work 中的其他成员模块）
+	paths, err := discoverFiles(ctx, dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, path := range paths {
+		if ctx.Err() != nil {
+			return nil, nil, context.Cause(ctx)
+		}
+		lang := DetectLanguage(path)
+		if lang == "go" {
+			info, statErr := os.Stat(path)
+			if statErr != nil || info.IsDir() {
+				continue
+			}
+			skip, err := ScanFile(ctx, info)
+			if err != nil {
+				return goFiles, otherFiles, err
+			}
+			if !skip {
+				goFiles = append(goFiles, path)
+			}
+		} else if lang != "unknown" {
+			otherFiles = append(otherFiles, skipped(path, lang))
+		}
+	}
+	return goFiles, otherFiles, nil
+}
+
+// collectGoFiles 收集目录下的 Go 文件，受 context 中的资源上限约束
+func collectGoFiles(ctx context.Context, dir string) ([]string, error) {
+	paths, err := discoverFiles(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, path := range paths {
+		if DetectLanguage(path) != "go" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if skip, err := ScanFile(ctx, info); err != nil {
+			return nil, err
+		} else if skip {
+			continue
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
@@ -0,0 +1,268 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"go-ai-study/internal/i18n"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newFindingsToolManager() *ToolManager {
+	tm := NewToolManager(NewNoopLogger())
+	tm.Register(NewBugDetector(), DefaultToolConfig("bug_detector"))
+	tm.Register(NewSecurityScanner(), DefaultToolConfig("security_scanner"))
+	return tm
+}
+
+// 测试跨工具收集问题和指纹查找
+func TestCollectFindings(t *testing.T) {
+	dir := t.TempDir()
+	code := `package main
+
+import "os"
+
+func Load() {
+	_ = os.Remove("a.txt")
+	_ = os.Remove("a.txt")
+	password := "admin123"
+	_ = password
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), 0644); err != nil {
+		t.Fatalf("写入测试文件失败: %v", err)
+	}
+
+	findings, err := CollectFindings(context.Background(), newFindingsToolManager(), dir)
+	if err != nil {
+		t.Fatalf("收集问题失败: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	var b101, g101 *Finding
+	for i, f := range findings {
+		if seen[f.Fingerprint] {
+			t.Fatalf("指纹重复: %s", f.Fingerprint)
+		}
+		seen[f.Fingerprint] = true
+		switch f.RuleID {
+		case "B101":
+			b101 = &findings[i]
+		case "G101":
+			g101 = &findings[i]
+		}
+	}
+	if b101 == nil || g101 == nil {
+		t.Fatalf("应该同时包含 B101 和 G101: %+v", findings)
+	}
+	if g101.File == "" || g101.Tool != "security_scanner" {
+		t.Fatalf("安全问题应该带有文件名: %+v", g101)
+	}
+
+	found, err := FindFinding(findings, g101.Fingerprint[:8])
+	if err != nil {
+		t.Fatalf("按指纹前缀查找失败: %v", err)
+	}
+	if found.RuleID != "G101" {
+		t.Fatalf("查找到错误的问题: %+v", found)
+	}
+
+	if _, err := FindFinding(findings, "zzzz"); err == nil {
+		t.Fatal("不存在的指纹应该返回错误")
+	}
+}
+
+// 测试 Bug 检测失败时返回错误，而不是只返回安全扫描的结果
+func TestCollectFindings_BugDetectorFailed(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("写入测试文件失败: %v", err)
+	}
+
+	tm := NewToolManager(NewNoopLogger())
+	failing := &MockTool{
+		BaseTool: NewBaseTool("bug_detector", "总是失败的 Bug 检测", reflect.TypeOf(BugDetectorInput{})),
+		runFunc: func(ctx context.Context, input any) (string, error) {
+			return "", fmt.Errorf("分析中断")
+		},
+	}
+	tm.Register(failing, DefaultToolConfig("bug_detector"))
+	tm.Register(NewSecurityScanner(), DefaultToolConfig("security_scanner"))
+
+	_, err := CollectFindings(context.Background(), tm, dir)
+	if err == nil || !strings.Contains(err.Error(), "分析中断") {
+		t.Fatalf("Bug 检测失败时应该返回错误，实际: %v", err)
+	}
+}
+
+// 测试复制到多个文件的相同安全问题合并为一组
+func TestGroupFindings(t *testing.T) {
+	dir := t.TempDir()
+	helper := `package %s
+
+import "crypto/md5"
+
+func hash(data []byte) []byte {
+	h := md5.New()
+	h.Write(data)
+	return h.Sum(nil)
+}
+`
+	for _, name := range []string{"a", "b", "c"} {
+		if err := os.WriteFile(filepath.Join(dir, name+".go"), []byte(fmt.Sprintf(helper, name)), 0644); err != nil {
+			t.Fatalf("写入测试文件失败: %v", err)
+		}
+	}
+	extra := "package d\n\nimport \"os\"\n\nfunc save() {\n\tos.WriteFile(\"a.txt\", nil, 0777)\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "d.go"), []byte(extra), 0644); err != nil {
+		t.Fatalf("写入测试文件失败: %v", err)
+	}
+
+	findings, _, err := CollectSecurityFindings(context.Background(), newFindingsToolManager(), dir, "")
+	if err != nil {
+		t.Fatalf("收集问题失败: %v", err)
+	}
+	groups := GroupFindings(findings)
+	if len(groups) != 2 {
+		t.Fatalf("应该合并为 2 组，实际 %d: %+v", len(groups), groups)
+	}
+	md5Group := groups[0]
+	if md5Group.RuleID != "G501" || md5Group.Count != 3 || md5Group.Files != 3 || len(md5Group.Occurrences) != 3 {
+		t.Errorf("G501 组错误: %+v", md5Group)
+	}
+	if groups[1].RuleID != "G302" || groups[1].Count != 1 {
+		t.Errorf("G302 组错误: %+v", groups[1])
+	}
+	for _, o := range md5Group.Occurrences {
+		if o.Fingerprint == md5Group.Fingerprint || o.Function != "hash" {
+			t.Errorf("出现位置错误: %+v", o)
+		}
+	}
+}
+
+// 测试指纹不随行号变化
+func TestFindingFingerprint_Stable(t *testing.T) {
+	a := FindingFingerprint("B101", "main.go", "_ = os.Open(\"a\")")
+	b := FindingFingerprint("B101", "main.go", "  _ = os.Open(\"a\")  ")
+	if a != b {
+		t.Fatalf("空白差异不应改变指纹: %s != %s", a, b)
+	}
+	if a == FindingFingerprint("B101", "other.go", "_ = os.Open(\"a\")") {
+		t.Fatal("不同文件的指纹应该不同")
+	}
+}
+
+// 测试规则文档查找
+func TestLookupRuleDoc(t *testing.T) {
+	doc, ok := LookupRuleDoc("G401")
+	if !ok || doc.Tool != "security_scanner" || doc.Suggestion == "" {
+		t.Fatalf("G401 文档不完整: %+v", doc)
+	}
+	if _, ok := LookupRuleDoc("X999"); ok {
+		t.Fatal("未知规则不应返回文档")
+	}
+}
+
+// 测试按语言输出规则文本和摘要：en-US 使用消息目录，zh-CN 回退到规则自带的文本
+func TestRuleText_Locale(t *testing.T) {
+	t.Cleanup(func() { i18n.SetLocale(i18n.DefaultLocale) })
+
+	zh, _ := LookupRuleDoc("G501")
+	if err := i18n.SetLocale("en_US.UTF-8"); err != nil {
+		t.Fatalf("设置语言失败: %v", err)
+	}
+	en, _ := LookupRuleDoc("G501")
+	if en.Description == zh.Description || !strings.Contains(en.Description, "Weak cryptographic") {
+		t.Errorf("en-US 规则描述错误: %q", en.Description)
+	}
+
+	result, err := NewSecurityScanner().Execute(context.Background(), SecurityScanInput{Code: "package main\n\nimport \"crypto/md5\"\n\nfunc f() { md5.New() }\n"})
+	if err != nil {
+		t.Fatalf("扫描失败: %v", err)
+	}
+	if result.Summary != "Found 1 security issues (1 High)" {
+		t.Errorf("en-US 摘要 = %q", result.Summary)
+	}
+
+	// 每条消息在两种语言中都要有
+	for _, locale := range i18n.Locales() {
+		if err := i18n.SetLocale(locale); err != nil {
+			t.Fatalf("设置语言失败: %v", err)
+		}
+		for _, id := range []string{"help.title", "bug.summary.analyzed", "security.summary.found"} {
+			if msg := i18n.T(id); msg == id {
+				t.Errorf("%s 缺少消息 %s", locale, id)
+			}
+		}
+	}
+	if err := i18n.SetLocale("fr-FR"); err == nil {
+		t.Error("不支持的语言应该返回错误")
+	}
+}
+
+// 测试消息目录对称：每种语言的消息 ID 相同，每条内置规则都有描述和建议，zh-CN 的规则文本与规则自带的文本一致
+func TestRuleText_Catalogs(t *testing.T) {
+	t.Cleanup(func() { i18n.SetLocale(i18n.DefaultLocale) })
+
+	want := strings.Join(i18n.Keys(i18n.DefaultLocale), "\n")
+	for _, locale := range i18n.Locales() {
+		if got := strings.Join(i18n.Keys(locale), "\n"); got != want {
+			t.Errorf("%s 与 %s 的消息 ID 不一致", locale, i18n.DefaultLocale)
+		}
+	}
+
+	texts := make(map[string]string)
+	bugEngine := NewBugRuleEngine()
+	bugEngine.RegisterAllRules()
+	for _, rule := range bugEngine.Rules {
+		texts["rule."+rule.ID()+".description"] = rule.Description()
+		texts["rule."+rule.ID()+".suggestion"] = rule.GenerateSuggestion(nil)
+	}
+	secEngine := NewRuleEngine()
+	secEngine.RegisterAllRules()
+	for _, rule := range secEngine.Rules {
+		texts["rule."+rule.ID()+".description"] = rule.Description()
+		texts["rule."+rule.ID()+".suggestion"] = rule.Suggestion()
+	}
+	for _, rule := range DefaultModernizeRules() {
+		texts["rule."+rule.ID()+".description"] = rule.Description()
+		texts["rule."+rule.ID()+".suggestion"] = rule.Suggestion()
+	}
+	keys := make(map[string]bool)
+	for _, id := range i18n.Keys(i18n.DefaultLocale) {
+		keys[id] = true
+	}
+	for id, text := range texts {
+		if !keys[id] {
+			t.Errorf("消息目录缺少 %s", id)
+			continue
+		}
+		if got := i18n.Text(id, ""); got != text {
+			t.Errorf("zh-CN 的 %s 与规则自带的文本不一致:\n%s\n%s", id, got, text)
+		}
+	}
+
+	// en-US 下 Bug 建议和复杂度摘要不再混入中文
+	if err := i18n.SetLocale(i18n.EnUS); err != nil {
+		t.Fatalf("设置语言失败: %v", err)
+	}
+	result, err := NewBugDetector().Execute(context.Background(), BugDetectorInput{Code: "package main\n\nimport \"os\"\n\nfunc main() {\n\t_ = os.Remove(\"a\")\n}\n"})
+	if err != nil {
+		t.Fatalf("检测失败: %v", err)
+	}
+	if len(result.Bugs) == 0 || !strings.HasPrefix(result.Bugs[0].FixSuggestion, "Check the error") {
+		t.Errorf("en-US Bug 建议错误: %+v", result.Bugs)
+	}
+	if len(result.Recommendations) == 0 || !strings.HasPrefix(result.Recommendations[0], "For compile errors") {
+		t.Errorf("en-US 工具建议 = %q", result.Recommendations)
+	}
+	if doc := modernizeChecklist(DefaultModernizeRules(), []ModernizeFinding{{RuleID: "M101"}}); len(doc) != 1 || !strings.HasPrefix(doc[0].Description, "io/ioutil is deprecated") {
+		t.Errorf("en-US 现代化清单 = %+v", doc)
+	}
+	if got := generateSummary([]FunctionResult{{Complexity: 3, Cognitive: 2}}); !strings.HasPrefix(got, "Analyzed 1 functions") {
+		t.Errorf("en-US 复杂度摘要 = %q", got)
+	}
+}
@@ -0,0 +1,237 @@
+package tools
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultGateWeights 各严重程度的新增问题在门禁中的默认权重
+var DefaultGateWeights = map[string]float64{
+	"Critical": 10,
+	"High":     5,
+	"Medium":   2,
+	"Low":      1,
+}
+
+// GateOptions 合并门禁选项
+type GateOptions struct {
+	Budget  float64            // 允许的新增问题加权分，超过时门禁失败
+	Weights map[string]float64 // 严重程度权重，为空使用 DefaultGateWeights；未列出的严重程度按 Low 计
+}
+
+// GateResult 合并门禁结果
+type GateResult struct {
+	Score      float64        `json:"score"`       // 新增问题的加权分
+	Budget     float64        `json:"budget"`      // 预算
+	Passed     bool           `json:"passed"`      // 加权分是否在预算内
+	BySeverity map[string]int `json:"by_severity"` // 各严重程度的新增问题数
+	New        []Finding      `json:"new"`         // 基线中没有的问题
+	Fixed      []Finding      `json:"fixed"`       // 基线中有、当前已消失的问题
+	Unchanged  int            `json:"unchanged"`   // 两边都有的问题数
+	Summary    string         `json:"summary"`
+}
+
+// EvaluateGate 按指纹比较基线和当前的问题，计算新增问题的加权分
+// 指纹与行号无关，代码移动不会被当作新增问题；同一指纹出现多次时按次数比较
+func EvaluateGate(baseline, current []Finding, opts GateOptions) *GateResult {
+	weights := opts.Weights
+	if len(weights) == 0 {
+		weights = DefaultGateWeights
+	}
+
+	remaining := make(map[string]int)
+	for _, f := range baseline {
+		remaining[f.Fingerprint]++
+	}
+
+	result := &GateResult{Budget: opts.Budget, BySeverity: make(map[string]int)}
+	for _, f := range current {
+		if remaining[f.Fingerprint] > 0 {
+			remaining[f.Fingerprint]--
+			result.Unchanged++
+			continue
+		}
+		result.New = append(result.New, f)
+		result.BySeverity[f.Severity]++
+		result.Score += gateWeight(weights, f.Severity)
+	}
+	for _, f := range baseline {
+		if remaining[f.Fingerprint] > 0 {
+			remaining[f.Fingerprint]--
+			result.Fixed = append(result.Fixed, f)
+		}
+	}
+
+	result.Passed = result.Score <= opts.Budget
+	status := "通过"
+	if !result.Passed {
+		status = "未通过"
+	}
+	result.Summary = fmt.Sprintf("门禁%s：新增 %d 个问题，加权分 %.1f（预算 %.1f），修复 %d 个",
+		status, len(result.New), result.Score, result.Budget, len(result.Fixed))
+	return result
+}
+
+// gateWeight 返回严重程度的权重，未配置的按 Low 计
+func gateWeight(weights map[string]float64, severity string) float64 {
+	if w, ok := weights[severity]; ok {
+		return w
+	}
+	return weights["Low"]
+}
+
+// ParseGateWeights 解析 "Critical=10,High=5" 形式的权重，未列出的严重程度沿用默认权重
+func ParseGateWeights(spec string) (map[string]float64, error) {
+	weights := make(map[string]float64, len(DefaultGateWeights))
+	for k, v := range DefaultGateWeights {
+		weights[k] = v
+	}
+	if strings.TrimSpace(spec) == "" {
+		return weights, nil
+	}
+	for _, part := range strings.Split(spec, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: 权重格式应为 严重程度=数值: %q", ErrInvalidInput, part)
+		}
+		var w float64
+		if _, err := fmt.Sscanf(strings.TrimSpace(value), "%g", &w); err != nil || w < 0 {
+			return nil, fmt.Errorf("%w: 无效的权重 %q", ErrInvalidInput, part)
+		}
+		level := severityLevels[severityIndex(name)]
+		if !strings.EqualFold(strings.TrimSpace(name), level) {
+			return nil, fmt.Errorf("%w: 未知的严重程度 %q（可选 %s）", ErrInvalidInput, name, strings.Join(severityLevels, "、"))
+		}
+		weights[level] = w
+	}
+	return weights, nil
+}
+
+// RelativizeFindings 把问题的文件路径改写为相对 root 的路径并重新计算指纹
+// 当前目录和基线（临时目录）中同一文件的路径不同，改写后两边的指纹才能对应
+func RelativizeFindings(findings []Finding, root string) {
+	for i := range findings {
+		if rel, err := filepath.Rel(root, findings[i].File); err == nil {
+			findings[i].File = filepath.ToSlash(rel)
+		}
+		findings[i].Fingerprint = FindingFingerprint(findings[i].RuleID, findings[i].File, findings[i].CodeSnippet)
+	}
+	sortFindings(findings)
+}
+
+// CollectBaselineFindings 收集 git 引用 ref（如 origin/main）上 dir 目录的问题
+// 用 git archive 把该引用的目录导出到临时目录后分析，不修改工作区和仓库状态；返回的路径相对 dir
+func CollectBaselineFindings(ctx context.Context, tm *ToolManager, dir, ref string) ([]Finding, error) {
+	prefix, err := gitOutput(ctx, dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, fmt.Errorf("%s 不在 git 仓库中: %w", dir, err)
+	}
+	prefix = strings.TrimSpace(prefix)
+	top, err := gitOutput(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("%s 不在 git 仓库中: %w", dir, err)
+	}
+	top = strings.TrimSpace(top)
+	if _, err := gitOutput(ctx, dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err != nil {
+		return nil, fmt.Errorf("%w: 无效的 git 引用 %q", ErrInvalidInput, ref)
+	}
+	// ref:prefix 指向该引用中 dir 对应的子树，导出的路径直接相对 dir
+	tree := ref + ":" + prefix
+	if _, err := gitOutput(ctx, dir, "cat-file", "-e", tree); err != nil {
+		// 目标引用上还没有这个目录，基线为空
+		return nil, nil
+	}
+	archive, err := gitOutput(ctx, top, "archive", "--format=tar", tree)
+	if err != nil {
+		return nil, fmt.Errorf("导出 %s 失败: %w", ref, err)
+	}
+
+	root, err := os.MkdirTemp("", "go-ai-insight-baseline-")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	defer os.RemoveAll(root)
+	if err := extractTar(strings.NewReader(archive), root); err != nil {
+		return nil, fmt.Errorf("解压 %s 失败: %w", ref, err)
+	}
+
+	findings, err := CollectFindings(ctx, tm, root)
+	if err != nil {
+		return nil, err
+	}
+	RelativizeFindings(findings, root)
+	return findings, nil
+}
+
+// gitOutput 在 dir 中执行 git 命令，返回标准输出
+// 关闭终端交互，需要凭据的远程仓库直接失败而不是等待输入
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	return runCommand(cmd)
+}
+
+// commandOutput 在 dir 中执行命令，返回标准输出
+func commandOutput(ctx context.Context, dir, name string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	return runCommand(cmd)
+}
+
+// runCommand 执行命令并返回标准输出；失败时错误中带上标准错误的内容
+func runCommand(cmd *exec.Cmd) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
+
+// extractTar 把 tar 流中的普通文件解压到 dir，忽略链接等特殊文件和越界路径
+func extractTar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		target := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(target, filepath.Clean(dir)+string(os.PathSeparator)) {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(f, tr)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// 测试新增问题的加权分和预算判断
+func TestEvaluateGate(t *testing.T) {
+	baseline := []Finding{
+		{Fingerprint: "a", Severity: "High"},
+		{Fingerprint: "b", Severity: "Medium"},
+		{Fingerprint: "b", Severity: "Medium"},
+	}
+	current := []Finding{
+		{Fingerprint: "a", Severity: "High"},
+		{Fingerprint: "b", Severity: "Medium"},
+		{Fingerprint: "c", Severity: "Critical"},
+		{Fingerprint: "d", Severity: "Low"},
+		{Fingerprint: "e", Severity: "Unknown"},
+	}
+
+	result := EvaluateGate(baseline, current, GateOptions{Budget: 12})
+	if result.Score != 12 || !result.Passed {
+		t.Errorf("加权分 = %v passed=%v, want 12 true", result.Score, result.Passed)
+	}
+	if len(result.New) != 3 || len(result.Fixed) != 1 || result.Unchanged != 2 {
+		t.Errorf("新增/修复/未变 = %d/%d/%d, want 3/1/2", len(result.New), len(result.Fixed), result.Unchanged)
+	}
+
+	result = EvaluateGate(baseline, current, GateOptions{Budget: 11.5})
+	if result.Passed {
+		t.Error("加权分超过预算时门禁应该失败")
+	}
+
+	result = EvaluateGate(nil, current[:2], GateOptions{Weights: map[string]float64{"High": 1, "Medium": 0, "Low": 0}, Budget: 1})
+	if result.Score != 1 || !result.Passed {
+		t.Errorf("自定义权重加权分 = %v, want 1", result.Score)
+	}
+}
+
+// 测试权重解析
+func TestParseGateWeights(t *testing.T) {
+	weights, err := ParseGateWeights("critical=20, Low=0.5")
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if weights["Critical"] != 20 || weights["Low"] != 0.5 || weights["High"] != DefaultGateWeights["High"] {
+		t.Errorf("权重错误: %v", weights)
+	}
+	for _, spec := range []string{"High", "High=x", "Urgent=3", "Low=-1"} {
+		if _, err := ParseGateWeights(spec); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("%q 应该返回 ErrInvalidInput，实际 %v", spec, err)
+		}
+	}
+}
+
+// 测试从 git 引用收集基线：已提交的问题不算新增，工作区新增的问题计入加权分
+func TestCollectBaselineFindings(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("没有 git")
+	}
+	repo := t.TempDir()
+	dir := filepath.Join(repo, "svc")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@t", "-c", "commit.gpgsign=false"}, args...)...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v 失败: %v\n%s", args, err, out)
+		}
+	}
+
+	old := "package svc\n\nimport \"os\"\n\nfunc Clean() {\n\t_ = os.Remove(\"a.txt\")\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "svc.go"), []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "init")
+
+	// 原有问题下移两行，并新增一个硬编码密码
+	updated := "package svc\n\nimport \"os\"\n\n// Clean 清理\n// 临时文件\nfunc Clean() {\n\t_ = os.Remove(\"a.txt\")\n\tpassword := \"admin123\"\n\t_ = password\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "svc.go"), []byte(updated), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tm := newFindingsToolManager()
+	ctx := context.Background()
+	baseline, err := CollectBaselineFindings(ctx, tm, dir, "HEAD")
+	if err != nil {
+		t.Fatalf("收集基线失败: %v", err)
+	}
+	if len(baseline) == 0 {
+		t.Fatal("基线应该包含已提交的问题")
+	}
+	if baseline[0].File != "svc.go" {
+		t.Errorf("基线路径应该相对目录: %s", baseline[0].File)
+	}
+
+	current, err := CollectFindings(ctx, tm, dir)
+	if err != nil {
+		t.Fatalf("收集当前问题失败: %v", err)
+	}
+	RelativizeFindings(current, dir)
+
+	result := EvaluateGate(baseline, current, GateOptions{})
+	if result.Unchanged != len(baseline) || len(result.Fixed) != 0 {
+		t.Errorf("移动过的原有问题不应算作新增或修复: %+v", result)
+	}
+	if len(result.New) == 0 || result.Passed {
+		t.Errorf("新增的问题应该使预算为 0 的门禁失败: %+v", result)
+	}
+}
@@ -0,0 +1,394 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"go/version"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// GoCompatChecker Go 版本兼容性检查器
+// 对照 go.mod 中的 go 指令，找出用到更新版本语言特性/标准库的代码，
+// 反过来也列出当前版本已经可用、但代码仍沿用旧写法的地方
+type GoCompatChecker struct {
+	*TypedTool[GoCompatRequest, *GoCompatResult]
+}
+
+// NewGoCompatChecker 创建 Go 版本兼容性检查器
+func NewGoCompatChecker() *GoCompatChecker {
+	gc := &GoCompatChecker{}
+	gc.TypedTool = NewTypedTool[GoCompatRequest, *GoCompatResult](
+		"go_compat",
+		"检查代码是否用到了比 go.mod 中 go 指令更新的语言特性和标准库（泛型、slices/maps 包、min/max 等），并提示当前版本可用的新写法",
+		gc,
+	)
+	return gc
+}
+
+// GoCompatRequest Go 版本兼容性检查请求
+type GoCompatRequest struct {
+	Directory string   `json:"directory" jsonschema:"required"` // 模块内的目录
+	Patterns  []string `json:"patterns,omitempty"`              // 包模式，默认 ./...
+	GoVersion string   `json:"go_version,omitempty"`            // 按该版本检查（如 1.20），为空时使用 go.mod 中的版本
+}
+
+// GoCompatIssue 单个兼容性问题
+type GoCompatIssue struct {
+	Kind       string `json:"kind"`     // too_new：特性比目标版本新；modernize：目标版本已支持更新的写法
+	Feature    string `json:"feature"`  // 特性名称
+	Requires   string `json:"requires"` // 特性要求的最低版本（如 go1.21）
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	Column     int    `json:"column"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion"`
+}
+
+// GoCompatResult Go 版本兼容性检查结果
+type GoCompatResult struct {
+	GoVersion string          `json:"go_version"` // 检查使用的目标版本（多个模块时为第一个）
+	Issues    []GoCompatIssue `json:"issues"`
+	TooNew    int             `json:"too_new"`
+	Modernize int             `json:"modernize"`
+	Errors    []string        `json:"errors,omitempty"` // 包加载/类型错误（目标版本过低导致的错误也会出现在这里）
+	Summary   string          `json:"summary"`
+}
+
+const (
+	compatTooNew    = "too_new"
+	compatModernize = "modernize"
+)
+
+// goFeature 带版本要求的语言特性或标准库
+type goFeature struct {
+	Name        string
+	Version     string // 最低版本，go/version 格式
+	Alternative string // 低版本下的替代写法
+}
+
+// stdlibPackageVersions 较新版本加入的标准库包
+var stdlibPackageVersions = map[string]goFeature{
+	"crypto/ecdh":      {"crypto/ecdh 包", "go1.20", "使用 golang.org/x/crypto/curve25519 或 crypto/elliptic"},
+	"slices":           {"slices 包", "go1.21", "使用 golang.org/x/exp/slices 或 sort 包"},
+	"maps":             {"maps 包", "go1.21", "使用 golang.org/x/exp/maps 或手写循环"},
+	"cmp":              {"cmp 包", "go1.21", "使用 golang.org/x/exp/constraints 或手写比较函数"},
+	"log/slog":         {"log/slog 包", "go1.21", "使用 golang.org/x/exp/slog"},
+	"math/rand/v2":     {"math/rand/v2 包", "go1.22", "使用 math/rand"},
+	"go/version":       {"go/version 包", "go1.22", "使用 golang.org/x/mod/semver"},
+	"iter":             {"iter 包", "go1.23", "使用回调函数或切片代替迭代器"},
+	"unique":           {"unique 包", "go1.23", "使用 map 手动做值驻留"},
+	"structs":          {"structs 包", "go1.23", "删除 structs.HostLayout 字段"},
+	"weak":             {"weak 包", "go1.24", "使用 runtime.SetFinalizer 或强引用缓存"},
+	"crypto/hkdf":      {"crypto/hkdf 包", "go1.24", "使用 golang.org/x/crypto/hkdf"},
+	"crypto/pbkdf2":    {"crypto/pbkdf2 包", "go1.24", "使用 golang.org/x/crypto/pbkdf2"},
+	"crypto/sha3":      {"crypto/sha3 包", "go1.24", "使用 golang.org/x/crypto/sha3"},
+	"crypto/mlkem":     {"crypto/mlkem 包", "go1.24", "使用 filippo.io/mlkem768"},
+	"testing/synctest": {"testing/synctest 包", "go1.25", "使用真实时间和显式同步"},
+}
+
+// builtinVersions 较新版本加入的内置函数
+var builtinVersions = map[string]goFeature{
+	"min":   {"内置函数 min", "go1.21", "手写比较或使用 math.Min（浮点数）"},
+	"max":   {"内置函数 max", "go1.21", "手写比较或使用 math.Max（浮点数）"},
+	"clear": {"内置函数 clear", "go1.21", "用循环删除 map 元素或把切片元素置零"},
+}
+
+var (
+	featureGenerics     = goFeature{"泛型（类型参数）", "go1.18", "为具体类型分别实现，或使用 interface{} 加类型断言"}
+	featureAny          = goFeature{"any 类型别名", "go1.18", "使用 interface{}"}
+	featureRangeInt     = goFeature{"range 整数", "go1.22", "使用 for i := 0; i < n; i++"}
+	featureRangeFunc    = goFeature{"range 迭代器函数", "go1.23", "直接调用函数并传入回调"}
+	featureExpToStdlib  = goFeature{"标准库替代 golang.org/x/exp", "go1.21", ""}
+	featureSortToSlices = goFeature{"slices 排序函数", "go1.21", ""}
+)
+
+// expReplacements golang.org/x/exp 中已进入标准库的包
+var expReplacements = map[string]string{
+	"golang.org/x/exp/slices":      "slices",
+	"golang.org/x/exp/maps":        "maps",
+	"golang.org/x/exp/slog":        "log/slog",
+	"golang.org/x/exp/constraints": "cmp（cmp.Ordered）",
+}
+
+// sortReplacements sort 包中可以换成 slices 的函数
+var sortReplacements = map[string]string{
+	"Slice":       "slices.SortFunc(s, func(a, b T) int { return cmp.Compare(a.X, b.X) })",
+	"SliceStable": "slices.SortStableFunc(s, func(a, b T) int { return cmp.Compare(a.X, b.X) })",
+	"Strings":     "slices.Sort(s)",
+	"Ints":        "slices.Sort(s)",
+	"Float64s":    "slices.Sort(s)",
+}
+
+// ValidateInput 验证输入参数
+func (gc *GoCompatChecker) ValidateInput(req GoCompatRequest) error {
+	if req.Directory == "" {
+		return fmt.Errorf("必须指定 Directory")
+	}
+	if req.GoVersion != "" && !version.IsValid(goLangVersion(req.GoVersion)) {
+		return fmt.Errorf("%w: 无效的 Go 版本 %q", ErrInvalidInput, req.GoVersion)
+	}
+	return nil
+}
+
+// Execute 执行兼容性检查
+func (gc *GoCompatChecker) Execute(ctx context.Context, req GoCompatRequest) (*GoCompatResult, error) {
+	return CheckGoCompat(ctx, req)
+}
+
+// CheckGoCompat 检查目录下的包与目标 Go 版本的兼容性
+func CheckGoCompat(ctx context.Context, req GoCompatRequest) (*GoCompatResult, error) {
+	pkgs, err := loadTypedPackages(ctx, req.Directory, req.Patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GoCompatResult{Issues: []GoCompatIssue{}, Errors: packageErrors(pkgs)}
+	versions := make(goVersionCache)
+	for _, pkg := range pkgs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		for _, file := range pkg.Syntax {
+			filename := pkg.Fset.Position(file.Pos()).Filename
+			target := req.GoVersion
+			if target == "" {
+				target = versions.lookup(filepath.Dir(filename))
+			}
+			if target == "" {
+				continue
+			}
+			target = goLangVersion(target)
+			// //go:build go1.N 约束的文件只在更新的版本下编译
+			if file.GoVersion != "" && version.Compare(file.GoVersion, target) > 0 {
+				target = file.GoVersion
+			}
+			if result.GoVersion == "" {
+				result.GoVersion = target
+			}
+			result.Issues = append(result.Issues, checkFileCompat(pkg, file, target)...)
+		}
+	}
+
+	sort.SliceStable(result.Issues, func(i, j int) bool {
+		a, b := result.Issues[i], result.Issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	for _, issue := range result.Issues {
+		if issue.Kind == compatTooNew {
+			result.TooNew++
+		} else {
+			result.Modernize++
+		}
+	}
+	result.Summary = fmt.Sprintf("目标版本 %s：%d 处使用了更新版本的特性，%d 处可以改用当前版本的新写法",
+		result.GoVersion, result.TooNew, result.Modernize)
+	return result, nil
+}
+
+// goLangVersion 把 "1.21" 形式的版本转换为 go/version 使用的 "go1.21"
+func goLangVersion(v string) string {
+	if strings.HasPrefix(v, "go") {
+		return v
+	}
+	return "go" + v
+}
+
+// checkFileCompat 检查单个文件
+func checkFileCompat(pkg *packages.Package, file *ast.File, target string) []GoCompatIssue {
+	info := pkg.TypesInfo
+	var issues []GoCompatIssue
+	tooNew := func(node ast.Node, feature goFeature) {
+		if version.Compare(feature.Version, target) <= 0 {
+			return
+		}
+		issues = append(issues, newCompatIssue(pkg.Fset, node, compatTooNew, feature,
+			fmt.Sprintf("%s 需要 %s，目标版本是 %s", feature.Name, feature.Version, target),
+			fmt.Sprintf("把 go.mod 中的 go 指令升级到 %s 以上，或%s", strings.TrimPrefix(feature.Version, "go"), feature.Alternative)))
+	}
+	modernize := func(node ast.Node, feature goFeature, message, suggestion string) {
+		if version.Compare(feature.Version, target) > 0 {
+			return
+		}
+		issues = append(issues, newCompatIssue(pkg.Fset, node, compatModernize, feature, message, suggestion))
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ImportSpec:
+			path, err := strconv.Unquote(node.Path.Value)
+			if err != nil {
+				return true
+			}
+			if feature, ok := stdlibPackageVersions[path]; ok {
+				tooNew(node, feature)
+			}
+			if std, ok := expReplacements[path]; ok {
+				modernize(node, featureExpToStdlib,
+					fmt.Sprintf("%s 已经进入标准库", path),
+					fmt.Sprintf("改用标准库 %s，去掉对 golang.org/x/exp 的依赖", std))
+			}
+
+		case *ast.FuncDecl:
+			if node.Type.TypeParams != nil {
+				tooNew(node.Type.TypeParams, featureGenerics)
+			}
+
+		case *ast.TypeSpec:
+			if node.TypeParams != nil {
+				tooNew(node.TypeParams, featureGenerics)
+			}
+
+		case *ast.Ident:
+			switch obj := info.Uses[node].(type) {
+			case *types.Builtin:
+				if feature, ok := builtinVersions[obj.Name()]; ok {
+					tooNew(node, feature)
+				}
+			case *types.TypeName, nil:
+				// 目标版本低于 1.18 时类型检查不记录 any 的引用，按名字判断预声明标识符
+				if node.Name == "any" && info.Defs[node] == nil && (obj == nil || obj.Pkg() == nil) &&
+					pkg.Types.Scope().Lookup("any") == nil {
+					tooNew(node, featureAny)
+				}
+			}
+
+		case *ast.RangeStmt:
+			if t := info.TypeOf(node.X); t != nil {
+				switch u := t.Underlying().(type) {
+				case *types.Basic:
+					if u.Info()&types.IsInteger != 0 {
+						tooNew(node.X, featureRangeInt)
+					}
+				case *types.Signature:
+					tooNew(node.X, featureRangeFunc)
+				}
+			}
+
+		case *ast.ForStmt:
+			if i, n, ok := countingLoop(info, node); ok {
+				modernize(node, featureRangeInt,
+					fmt.Sprintf("计数循环可以改写为 range %s", types.ExprString(n)),
+					fmt.Sprintf("for %s := range %s { ... }", i.Name, types.ExprString(n)))
+			}
+
+		case *ast.CallExpr:
+			sel, ok := node.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			fn, ok := info.Uses[sel.Sel].(*types.Func)
+			if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "sort" {
+				return true
+			}
+			if replacement, ok := sortReplacements[fn.Name()]; ok {
+				modernize(node, featureSortToSlices,
+					fmt.Sprintf("sort.%s 可以换成类型安全的 slices 函数", fn.Name()),
+					replacement)
+			}
+		}
+		return true
+	})
+	return issues
+}
+
+// newCompatIssue 创建兼容性问题
+func newCompatIssue(fset *token.FileSet, node ast.Node, kind string, feature goFeature, message, suggestion string) GoCompatIssue {
+	pos := fset.Position(node.Pos())
+	return GoCompatIssue{
+		Kind:       kind,
+		Feature:    feature.Name,
+		Requires:   feature.Version,
+		File:       pos.Filename,
+		Line:       pos.Line,
+		Column:     pos.Column,
+		Message:    message,
+		Suggestion: suggestion,
+	}
+}
+
+// countingLoop 判断 for 循环是否是 for i := 0; i < n; i++ 形式，且可以安全改写为 for i := range n：
+// n 是 int 类型的变量、常量或 len(x)，循环体中不修改 i 和 n
+func countingLoop(info *types.Info, loop *ast.ForStmt) (*ast.Ident, ast.Expr, bool) {
+	init, ok := loop.Init.(*ast.AssignStmt)
+	if !ok || init.Tok != token.DEFINE || len(init.Lhs) != 1 || len(init.Rhs) != 1 {
+		return nil, nil, false
+	}
+	i, ok := init.Lhs[0].(*ast.Ident)
+	if lit, isLit := init.Rhs[0].(*ast.BasicLit); !ok || !isLit || lit.Value != "0" {
+		return nil, nil, false
+	}
+	cond, ok := loop.Cond.(*ast.BinaryExpr)
+	if !ok || cond.Op != token.LSS {
+		return nil, nil, false
+	}
+	if x, ok := cond.X.(*ast.Ident); !ok || x.Name != i.Name {
+		return nil, nil, false
+	}
+	post, ok := loop.Post.(*ast.IncDecStmt)
+	if !ok || post.Tok != token.INC {
+		return nil, nil, false
+	}
+	if x, ok := post.X.(*ast.Ident); !ok || x.Name != i.Name {
+		return nil, nil, false
+	}
+	if !types.Identical(info.TypeOf(cond.Y), types.Typ[types.Int]) && !types.Identical(info.TypeOf(cond.Y), types.Typ[types.UntypedInt]) {
+		return nil, nil, false
+	}
+
+	// 上界只能是变量、常量或 len(变量)
+	var bound *ast.Ident
+	switch y := cond.Y.(type) {
+	case *ast.Ident:
+		bound = y
+	case *ast.BasicLit:
+	case *ast.CallExpr:
+		fn, ok := y.Fun.(*ast.Ident)
+		if !ok || fn.Name != "len" || len(y.Args) != 1 {
+			return nil, nil, false
+		}
+		if bound, ok = y.Args[0].(*ast.Ident); !ok {
+			return nil, nil, false
+		}
+	default:
+		return nil, nil, false
+	}
+
+	modified := map[types.Object]bool{}
+	ast.Inspect(loop.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				if id, ok := lhs.(*ast.Ident); ok {
+					modified[info.ObjectOf(id)] = true
+				}
+			}
+		case *ast.IncDecStmt:
+			if id, ok := node.X.(*ast.Ident); ok {
+				modified[info.ObjectOf(id)] = true
+			}
+		case *ast.UnaryExpr:
+			if id, ok := node.X.(*ast.Ident); ok && node.Op == token.AND {
+				modified[info.ObjectOf(id)] = true
+			}
+		}
+		return true
+	})
+	if modified[info.ObjectOf(i)] || (bound != nil && modified[info.ObjectOf(bound)]) {
+		return nil, nil, false
+	}
+	return i, cond.Y, true
+}
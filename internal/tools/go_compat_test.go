@@ -0,0 +1,140 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const goCompatCode = `package demo
+
+import (
+	"slices"
+	"sort"
+)
+
+type Set[T comparable] map[T]struct{}
+
+func Largest(xs []int) int {
+	best := 0
+	for i := 0; i < len(xs); i++ {
+		best = max(best, xs[i])
+	}
+	for i := range 3 {
+		_ = i
+	}
+	sort.Ints(xs)
+	return best
+}
+
+func Keep(xs []any) bool {
+	return slices.Contains(xs, nil)
+}
+
+func Skip(xs []int) {
+	for i := 0; i < len(xs); i++ {
+		if xs[i] < 0 {
+			i++
+		}
+	}
+}
+`
+
+// writeGoCompatModule 写入一个声明 go 版本为 goVersion 的模块
+func writeGoCompatModule(t *testing.T, goVersion string) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":  "module example.com/demo\n\ngo " + goVersion + "\n",
+		"demo.go": goCompatCode,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("写入测试文件失败: %v", err)
+		}
+	}
+	return dir
+}
+
+// compatFeatures 按 kind 统计每个特性出现的行号
+func compatFeatures(result *GoCompatResult, kind string) map[string][]int {
+	features := make(map[string][]int)
+	for _, issue := range result.Issues {
+		if issue.Kind == kind {
+			features[issue.Feature] = append(features[issue.Feature], issue.Line)
+		}
+	}
+	return features
+}
+
+// 测试找出比 go.mod 版本更新的特性
+func TestCheckGoCompat_TooNew(t *testing.T) {
+	dir := writeGoCompatModule(t, "1.17")
+	result, err := CheckGoCompat(context.Background(), GoCompatRequest{Directory: dir})
+	if err != nil {
+		t.Fatalf("检查失败: %v", err)
+	}
+	if result.GoVersion != "go1.17" {
+		t.Errorf("目标版本 = %s, want go1.17", result.GoVersion)
+	}
+
+	got := compatFeatures(result, compatTooNew)
+	want := map[string]int{
+		"slices 包": 4,
+		"泛型（类型参数）": 8,
+		"内置函数 max": 13,
+		"range 整数": 15,
+		"any 类型别名": 22,
+	}
+	for feature, line := range want {
+		if lines := got[feature]; len(lines) != 1 || lines[0] != line {
+			t.Errorf("%s: 行号 = %v, want [%d]", feature, lines, line)
+		}
+	}
+	if len(compatFeatures(result, compatModernize)) != 0 {
+		t.Errorf("低版本不应提示新写法: %+v", result.Issues)
+	}
+}
+
+// 测试当前版本可用的新写法
+func TestCheckGoCompat_Modernize(t *testing.T) {
+	dir := writeGoCompatModule(t, "1.22")
+	result, err := CheckGoCompat(context.Background(), GoCompatRequest{Directory: dir})
+	if err != nil {
+		t.Fatalf("检查失败: %v", err)
+	}
+	if result.TooNew != 0 {
+		t.Errorf("go 1.22 下不应有过新的特性: %+v", result.Issues)
+	}
+
+	got := compatFeatures(result, compatModernize)
+	// 第 36 行的循环体修改了 i，不能改写为 range
+	if lines := got["range 整数"]; len(lines) != 1 || lines[0] != 12 {
+		t.Errorf("range 整数: 行号 = %v, want [12]", lines)
+	}
+	if lines := got["slices 排序函数"]; len(lines) != 1 || lines[0] != 18 {
+		t.Errorf("slices 排序函数: 行号 = %v, want [18]", lines)
+	}
+
+	// 指定版本覆盖 go.mod
+	override, err := CheckGoCompat(context.Background(), GoCompatRequest{Directory: dir, GoVersion: "1.21"})
+	if err != nil {
+		t.Fatalf("检查失败: %v", err)
+	}
+	if lines := compatFeatures(override, compatTooNew)["range 整数"]; len(lines) != 1 {
+		t.Errorf("按 1.21 检查应该报告 range 整数: %+v", override.Issues)
+	}
+}
+
+// 测试无效的目标版本
+func TestGoCompatChecker_ValidateInput(t *testing.T) {
+	checker := NewGoCompatChecker()
+	if err := checker.ValidateInput(GoCompatRequest{Directory: ".", GoVersion: "latest"}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("无效版本应该返回 ErrInvalidInput，实际 %v", err)
+	}
+	if err := checker.ValidateInput(GoCompatRequest{Directory: ".", GoVersion: "1.21.3"}); err != nil {
+		t.Errorf("1.21.3 应该是有效版本: %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// GoDocLookup 查询 Go 文档
+// 在本地执行 go doc，让模型回答标准库（或模块依赖）的行为时以文档为准，而不是凭记忆猜测
+type GoDocLookup struct {
+	*TypedTool[GoDocRequest, *GoDocResult]
+}
+
+// NewGoDocLookup 创建 Go 文档查询工具
+func NewGoDocLookup() *GoDocLookup {
+	gd := &GoDocLookup{}
+	gd.TypedTool = NewTypedTool[GoDocRequest, *GoDocResult](
+		"go_doc",
+		"查询 Go 标准库或依赖包的文档（本地 go doc），如 net/http.Client、strings.Cut、context.WithTimeout；回答标准库行为相关的问题前先调用",
+		gd,
+	)
+	return gd
+}
+
+// GoDocRequest Go 文档查询请求
+type GoDocRequest struct {
+	Symbol    string `json:"symbol" jsonschema:"required"` // 包、类型、函数或方法，如 io、net/http.Client、http.Client.Do
+	Directory string `json:"directory,omitempty"`          // 在该目录所在的模块中解析（查询依赖包时需要），为空时只能查询标准库
+	All       bool   `json:"all,omitempty"`                // 显示包的全部文档（go doc -all）
+	Source    bool   `json:"source,omitempty"`             // 显示源码（go doc -src）
+}
+
+// GoDocResult Go 文档查询结果
+type GoDocResult struct {
+	Symbol    string `json:"symbol"`
+	Doc       string `json:"doc"`
+	Truncated bool   `json:"truncated,omitempty"` // 文档过长被截断
+}
+
+// maxGoDocBytes 返回给模型的文档上限，-all 的输出可能有几百 KB
+const maxGoDocBytes = 16 * 1024
+
+// goDocSymbol 允许的符号形式：导入路径和标识符，不能以 - 开头（避免被当成 go doc 的参数）
+var goDocSymbol = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_./~-]*$`)
+
+// ValidateInput 验证输入参数
+func (gd *GoDocLookup) ValidateInput(req GoDocRequest) error {
+	symbol := strings.TrimSpace(req.Symbol)
+	if symbol == "" {
+		return fmt.Errorf("%w: symbol 不能为空", ErrInvalidInput)
+	}
+	if !goDocSymbol.MatchString(symbol) {
+		return fmt.Errorf("%w: 无效的符号 %q", ErrInvalidInput, req.Symbol)
+	}
+	return nil
+}
+
+// Execute 执行 go doc
+func (gd *GoDocLookup) Execute(ctx context.Context, req GoDocRequest) (*GoDocResult, error) {
+	symbol := strings.TrimSpace(req.Symbol)
+	args := []string{"doc"}
+	if req.All {
+		args = append(args, "-all")
+	}
+	if req.Source {
+		args = append(args, "-src")
+	}
+	args = append(args, symbol)
+
+	// 不指定目录时在临时目录中执行：当前目录所在的模块与查询无关，解析它的依赖只会拖慢查询
+	dir := req.Directory
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	out, err := commandOutput(ctx, dir, "go", args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: go doc %s 失败: %v", ErrToolExecution, symbol, err)
+	}
+
+	result := &GoDocResult{Symbol: symbol, Doc: strings.TrimSpace(out)}
+	if len(result.Doc) > maxGoDocBytes {
+		cut := strings.LastIndex(result.Doc[:maxGoDocBytes], "\n")
+		if cut <= 0 {
+			cut = maxGoDocBytes
+		}
+		result.Doc = result.Doc[:cut] + "\n..."
+		result.Truncated = true
+	}
+	return result, nil
+}
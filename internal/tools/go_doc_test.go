@@ -0,0 +1,37 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// 测试查询标准库文档
+func TestGoDocLookup(t *testing.T) {
+	lookup := NewGoDocLookup()
+	out, err := lookup.Run(context.Background(), GoDocRequest{Symbol: "strings.Cut"})
+	if err != nil {
+		t.Fatalf("查询文档失败: %v", err)
+	}
+	if !strings.Contains(out, "func Cut(s, sep string)") {
+		t.Errorf("文档中应该包含函数签名: %s", out)
+	}
+
+	if _, err := lookup.Run(context.Background(), GoDocRequest{Symbol: "strings.NoSuchFunc"}); !errors.Is(err, ErrToolExecution) {
+		t.Errorf("不存在的符号应该返回 ErrToolExecution，实际 %v", err)
+	}
+}
+
+// 测试拒绝会被当成 go doc 参数的符号
+func TestGoDocLookup_Validate(t *testing.T) {
+	lookup := NewGoDocLookup()
+	for _, symbol := range []string{"", "-u", "strings Cut", "fmt;ls"} {
+		if err := lookup.Validate(GoDocRequest{Symbol: symbol}); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("符号 %q 应该被拒绝，实际 %v", symbol, err)
+		}
+	}
+	if err := lookup.Validate(GoDocRequest{Symbol: "net/http.Client.Do"}); err != nil {
+		t.Errorf("合法的符号被拒绝: %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// GoWork go.work 描述的多模块工作区
+// 每个 use 的成员模块是一个独立的分析单元：单独收集问题、单独建索引集合、在报告中单独成节
+type GoWork struct {
+	Dir     string         `json:"dir"`     // go.work 所在目录
+	Go      string         `json:"go"`      // go 指令版本
+	Modules []GoWorkModule `json:"modules"` // use 指令列出的成员模块，按目录排序
+}
+
+// GoWorkModule go.work 的成员模块
+type GoWorkModule struct {
+	Path string `json:"path"` // 模块路径（go.mod 中的 module）
+	Dir  string `json:"dir"`  // 模块目录
+	Rel  string `json:"rel"`  // 相对 go.work 所在目录的路径
+}
+
+// LoadGoWork 读取 dir 下的 go.work，没有 go.work 时返回 nil
+func LoadGoWork(dir string) (*GoWork, error) {
+	path := filepath.Join(dir, "go.work")
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取 go.work 失败: %w", err)
+	}
+	wf, err := modfile.ParseWork(path, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("解析 go.work 失败: %w", err)
+	}
+
+	work := &GoWork{Dir: dir}
+	if wf.Go != nil {
+		work.Go = wf.Go.Version
+	}
+	for _, use := range wf.Use {
+		moduleDir := filepath.Clean(filepath.Join(dir, filepath.FromSlash(use.Path)))
+		if filepath.IsAbs(use.Path) {
+			moduleDir = filepath.Clean(use.Path)
+		}
+		gomod, err := os.ReadFile(filepath.Join(moduleDir, "go.mod"))
+		if err != nil {
+			return nil, fmt.Errorf("go.work 成员 %s 没有可读的 go.mod: %w", use.Path, err)
+		}
+		rel, err := filepath.Rel(dir, moduleDir)
+		if err != nil {
+			rel = moduleDir
+		}
+		work.Modules = append(work.Modules, GoWorkModule{
+			Path: modfile.ModulePath(gomod),
+			Dir:  moduleDir,
+			Rel:  filepath.ToSlash(rel),
+		})
+	}
+	return work, nil
+}
+
+// ModuleFor 返回 path 所属的成员模块（目录最深的那个），不属于任何成员时返回 nil
+func (w *GoWork) ModuleFor(path string) *GoWorkModule {
+	var best *GoWorkModule
+	for i := range w.Modules {
+		m := &w.Modules[i]
+		if !withinDir(m.Dir, path) {
+			continue
+		}
+		if best == nil || len(m.Dir) > len(best.Dir) {
+			best = m
+		}
+	}
+	return best
+}
+
+// isMemberDir dir 是否是某个成员模块的根目录
+func (w *GoWork) isMemberDir(dir string) bool {
+	dir = filepath.Clean(dir)
+	for _, m := range w.Modules {
+		if m.Dir == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// withinDir path 是否在 dir 下（含 dir 本身）
+func withinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// goWorkKey go.work 在 context 中的键
+type goWorkKey struct{}
+
+// WithGoWork 把 go.work 放入 context
+// 之后的目录扫描遇到其他成员模块的目录时跳过，嵌套的成员模块（如根模块下的 tools/）不会被重复分析
+func WithGoWork(ctx context.Context, w *GoWork) context.Context {
+	return context.WithValue(ctx, goWorkKey{}, w)
+}
+
+// skipMemberDir 目录扫描时 dir 是否属于另一个成员模块，root 是本次扫描的根目录
+func skipMemberDir(ctx context.Context, root, dir string) bool {
+	w, _ := ctx.Value(goWorkKey{}).(*GoWork)
+	if w == nil || filepath.Clean(dir) == filepath.Clean(root) {
+		return false
+	}
+	return w.isMemberDir(dir)
+}
+
+// ModuleFindings 一个成员模块的问题
+type ModuleFindings struct {
+	Module   GoWorkModule `json:"module"`
+	Findings []Finding    `json:"findings"`
+}
+
+// CollectGoWorkFindings 对 go.work 的每个成员模块分别收集问题
+func CollectGoWorkFindings(ctx context.Context, tm *ToolManager, w *GoWork) ([]ModuleFindings, error) {
+	ctx = WithGoWork(ctx, w)
+	results := make([]ModuleFindings, 0, len(w.Modules))
+	for _, m := range w.Modules {
+		findings, err := CollectFindings(ctx, tm, m.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("模块 %s: %w", m.Path, err)
+		}
+		results = append(results, ModuleFindings{Module: m, Findings: findings})
+	}
+	return results, nil
+}
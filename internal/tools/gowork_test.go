@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeGoWorkFixture 根模块 example.com/app 下嵌套成员模块 tools，另有一个兄弟模块 lib
+func writeGoWorkFixture(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.work":        "go 1.22\n\nuse (\n\t.\n\t./tools\n\t./lib\n)\n",
+		"go.mod":         "module example.com/app\n\ngo 1.22\n",
+		"main.go":        "package main\n\nimport \"os\"\n\nfunc main() {\n\t_ = os.Remove(\"a.txt\")\n}\n",
+		"tools/go.mod":   "module example.com/app/tools\n\ngo 1.22\n",
+		"tools/gen.go":   "package tools\n\nimport \"os\"\n\nfunc Gen() {\n\t_ = os.Remove(\"b.txt\")\n}\n",
+		"lib/go.mod":     "module example.com/lib\n\ngo 1.22\n",
+		"lib/lib.go":     "package lib\n\nfunc Lib() {\n\tpassword := \"admin123\"\n\t_ = password\n}\n",
+		"lib/sub/sub.go": "package sub\n\nfunc Sub() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// 测试解析 go.work 和按目录归属成员模块
+func TestLoadGoWork(t *testing.T) {
+	dir := writeGoWorkFixture(t)
+	work, err := LoadGoWork(dir)
+	if err != nil {
+		t.Fatalf("解析 go.work 失败: %v", err)
+	}
+	if work.Go != "1.22" || len(work.Modules) != 3 {
+		t.Fatalf("go.work 解析错误: %+v", work)
+	}
+	if work.Modules[1].Path != "example.com/app/tools" || work.Modules[1].Rel != "tools" {
+		t.Errorf("成员模块错误: %+v", work.Modules[1])
+	}
+
+	tests := map[string]string{
+		"main.go":        "example.com/app",
+		"tools/gen.go":   "example.com/app/tools",
+		"lib/sub/sub.go": "example.com/lib",
+	}
+	for file, want := range tests {
+		if m := work.ModuleFor(filepath.Join(dir, file)); m == nil || m.Path != want {
+			t.Errorf("ModuleFor(%s) = %v, want %s", file, m, want)
+		}
+	}
+	if m := work.ModuleFor(filepath.Dir(dir)); m != nil {
+		t.Errorf("工作区外的路径不应属于任何模块: %+v", m)
+	}
+
+	if work, err := LoadGoWork(filepath.Join(dir, "lib")); err != nil || work != nil {
+		t.Errorf("没有 go.work 时应该返回 nil: %v %v", work, err)
+	}
+}
+
+// 测试每个成员模块单独收集问题，嵌套模块的问题不计入外层模块
+func TestCollectGoWorkFindings(t *testing.T) {
+	dir := writeGoWorkFixture(t)
+	work, err := LoadGoWork(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := CollectGoWorkFindings(context.Background(), newFindingsToolManager(), work)
+	if err != nil {
+		t.Fatalf("收集问题失败: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("应该有 3 个模块的结果，实际 %d", len(results))
+	}
+	for _, r := range results {
+		if len(r.Findings) == 0 {
+			t.Errorf("模块 %s 应该有问题", r.Module.Path)
+		}
+		for _, f := range r.Findings {
+			if m := work.ModuleFor(f.File); m == nil || m.Path != r.Module.Path {
+				t.Errorf("模块 %s 混入了其他模块的问题: %s", r.Module.Path, f.File)
+			}
+		}
+	}
+}
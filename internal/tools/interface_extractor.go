@@ -0,0 +1,587 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// InterfaceExtractor 接口抽取重构助手
+// 根据调用点统计具体类型实际被使用的方法，生成接口，并把字段/参数等注入点改为接口类型
+type InterfaceExtractor struct {
+	*TypedTool[InterfaceRequest, *InterfaceResult]
+}
+
+// NewInterfaceExtractor 创建接口抽取工具
+func NewInterfaceExtractor() *InterfaceExtractor {
+	ie := &InterfaceExtractor{}
+	ie.TypedTool = NewTypedTool[InterfaceRequest, *InterfaceResult](
+		"interface_extractor",
+		"为具体类型抽取接口（按调用点统计方法集），并更新注入点，输出可审查的补丁",
+		ie,
+	)
+	return ie
+}
+
+// InterfaceRequest 接口抽取请求
+type InterfaceRequest struct {
+	Directory string `json:"directory" jsonschema:"required"`                                                            // 扫描目录（通常是模块根目录）
+	Type      string `json:"type" jsonschema:"required,description=具体类型，如 SourceInsightEngine 或 ai.SourceInsightEngine"` // 具体类型，如 SourceInsightEngine 或 ai.SourceInsightEngine
+	Name      string `json:"name,omitempty"`                                                                             // 接口名，默认 <Type>API
+	Write     bool   `json:"write,omitempty"`                                                                            // 是否写回文件（默认只生成 diff）
+}
+
+// InterfaceResult 接口抽取结果
+type InterfaceResult struct {
+	Type        string           `json:"type"`        // 具体类型
+	Interface   string           `json:"interface"`   // 接口名
+	File        string           `json:"file"`        // 接口所在文件
+	Methods     []string         `json:"methods"`     // 接口方法
+	Declaration string           `json:"declaration"` // 接口声明
+	Updated     []InjectionPoint `json:"updated"`     // 已改为接口类型的注入点
+	Skipped     []InjectionPoint `json:"skipped"`     // 无法改为接口类型的注入点
+	Patches     []FilePatch      `json:"patches"`     // 补丁
+	Applied     bool             `json:"applied"`     // 是否已写回
+	Summary     string           `json:"summary"`     // 摘要
+}
+
+// InjectionPoint 具体类型的注入点（结构体字段或函数参数）
+type InjectionPoint struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Kind   string `json:"kind"` // field, param
+	Name   string `json:"name"`
+	Reason string `json:"reason,omitempty"` // 跳过原因
+}
+
+// ifaceFile 参与分析的源文件
+type ifaceFile struct {
+	path      string
+	dir       string
+	file      *ast.File
+	src       []byte
+	qualifier string // 引用目标类型时使用的包名，同包为空
+	imported  bool   // 是否能引用目标类型（同包或导入了目标包）
+}
+
+// ifaceMethod 目标类型的方法
+type ifaceMethod struct {
+	decl *ast.FuncDecl
+	file *ifaceFile
+}
+
+// ifaceInjection 注入点候选
+type ifaceInjection struct {
+	point InjectionPoint
+	file  *ifaceFile
+	expr  ast.Expr    // *T 类型表达式
+	obj   *ast.Object // 参数对象
+	body  *ast.BlockStmt
+}
+
+// interfaceExtraction 单次抽取的分析状态
+type interfaceExtraction struct {
+	fset       *token.FileSet
+	files      []*ifaceFile
+	typeName   string
+	typeDir    string
+	typeFile   *ifaceFile
+	typeDecl   *ast.GenDecl
+	methods    map[string]*ifaceMethod
+	order      []string
+	params     []*ifaceInjection
+	fields     []*ifaceInjection
+	objects    map[*ast.Object]bool // 类型为 *T 的参数和局部变量
+	fieldNames map[string]bool      // 类型为 *T 的字段名
+	used       map[string]bool      // 调用点用到的方法
+}
+
+// ValidateInput 验证输入参数
+func (ie *InterfaceExtractor) ValidateInput(req InterfaceRequest) error {
+	if req.Directory == "" || req.Type == "" {
+		return fmt.Errorf("必须指定 Directory 和 Type")
+	}
+	return nil
+}
+
+// Execute 执行接口抽取
+func (ie *InterfaceExtractor) Execute(ctx context.Context, req InterfaceRequest) (*InterfaceResult, error) {
+	qualifier, typeName := "", req.Type
+	if idx := strings.LastIndex(req.Type, "."); idx != -1 {
+		qualifier, typeName = req.Type[:idx], req.Type[idx+1:]
+	}
+	ifaceName := req.Name
+	if ifaceName == "" {
+		ifaceName = typeName + "API"
+	}
+
+	ex := &interfaceExtraction{
+		fset:       token.NewFileSet(),
+		typeName:   typeName,
+		methods:    make(map[string]*ifaceMethod),
+		objects:    make(map[*ast.Object]bool),
+		fieldNames: make(map[string]bool),
+		used:       make(map[string]bool),
+	}
+	if err := ex.parseFiles(ctx, req.Directory); err != nil {
+		return nil, err
+	}
+	if err := ex.locateType(qualifier, ifaceName); err != nil {
+		return nil, err
+	}
+	ex.collectMethods()
+	ex.resolveQualifiers()
+	ex.collectTypedNames()
+	ex.collectUsedMethods()
+
+	if len(ex.used) == 0 {
+		return nil, fmt.Errorf("未找到 %s 的方法调用点", req.Type)
+	}
+
+	result := InterfaceResult{
+		Type:      req.Type,
+		Interface: ifaceName,
+		File:      ex.typeFile.path,
+		Updated:   []InjectionPoint{},
+		Skipped:   []InjectionPoint{},
+		Patches:   []FilePatch{},
+	}
+	for _, name := range ex.order {
+		if ex.used[name] {
+			result.Methods = append(result.Methods, name)
+		}
+	}
+	result.Declaration = ex.renderInterface(ifaceName, result.Methods)
+
+	// 接口声明插在类型声明之后
+	edits := make(map[*ifaceFile][]FixEdit)
+	declEnd := ex.fset.Position(ex.typeDecl.End()).Offset
+	edits[ex.typeFile] = append(edits[ex.typeFile], FixEdit{Start: declEnd, End: declEnd, NewText: "\n\n" + result.Declaration})
+
+	for _, inj := range append(append([]*ifaceInjection{}, ex.fields...), ex.params...) {
+		if reason := ex.checkInjection(inj); reason != "" {
+			inj.point.Reason = reason
+			result.Skipped = append(result.Skipped, inj.point)
+			continue
+		}
+		newType := ifaceName
+		if inj.file.qualifier != "" {
+			newType = inj.file.qualifier + "." + ifaceName
+		}
+		edits[inj.file] = append(edits[inj.file], FixEdit{
+			Start:   ex.fset.Position(inj.expr.Pos()).Offset,
+			End:     ex.fset.Position(inj.expr.End()).Offset,
+			NewText: newType,
+			Line:    inj.point.Line,
+		})
+		result.Updated = append(result.Updated, inj.point)
+	}
+
+	for _, f := range ex.files {
+		fileEdits, ok := edits[f]
+		if !ok {
+			continue
+		}
+		updated := keepGofmt(f.src, applyFixEdits(f.src, fileEdits))
+		result.Patches = append(result.Patches, FilePatch{
+			File: f.path,
+			Diff: UnifiedDiff("a/"+f.path, "b/"+f.path, string(f.src), string(updated)),
+		})
+		if req.Write {
+			if _, err := WriteOutputFile(ctx, f.path, updated, 0644); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result.Updated = sortedInjectionPoints(result.Updated)
+	result.Skipped = sortedInjectionPoints(result.Skipped)
+	result.Applied = req.Write
+	result.Summary = fmt.Sprintf("接口 %s 包含 %d 个方法，更新 %d 个注入点，跳过 %d 个", ifaceName, len(result.Methods), len(result.Updated), len(result.Skipped))
+
+	return &result, nil
+}
+
+// parseFiles 解析目录下的源文件（跳过隐藏目录、vendor、testdata 和 _test.go）
+func (ex *interfaceExtraction) parseFiles(ctx context.Context, dir string) error {
+	return filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			base := filepath.Base(p)
+			if p != dir && (strings.HasPrefix(base, ".") || base == "vendor" || base == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(p, ".go") || strings.HasSuffix(p, "_test.go") {
+			return nil
+		}
+		if skip, err := ScanFile(ctx, info); err != nil || skip {
+			return err
+		}
+
+		src, err := os.ReadFile(p)
+		if err != nil {
+			return nil
+		}
+		file, err := parser.ParseFile(ex.fset, p, src, parser.ParseComments)
+		if err != nil {
+			return nil // 解析失败的文件不参与分析
+		}
+		ex.files = append(ex.files, &ifaceFile{path: p, dir: filepath.Dir(p), file: file, src: src})
+		return nil
+	})
+}
+
+// locateType 定位目标类型声明，并检查接口名是否冲突
+func (ex *interfaceExtraction) locateType(qualifier, ifaceName string) error {
+	dirs := make(map[string]bool)
+	for _, f := range ex.files {
+		if qualifier != "" && f.file.Name.Name != qualifier {
+			continue
+		}
+		for _, decl := range f.file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				if spec.(*ast.TypeSpec).Name.Name == ex.typeName {
+					ex.typeFile, ex.typeDecl, ex.typeDir = f, gen, f.dir
+					dirs[f.dir] = true
+				}
+			}
+		}
+	}
+
+	switch {
+	case len(dirs) == 0:
+		return fmt.Errorf("未找到类型 %s", ex.typeName)
+	case len(dirs) > 1:
+		return fmt.Errorf("类型 %s 不唯一，请使用 包名.类型 指定", ex.typeName)
+	}
+
+	for _, f := range ex.files {
+		if f.dir == ex.typeDir && f.file.Scope.Lookup(ifaceName) != nil {
+			return fmt.Errorf("包中已存在 %s", ifaceName)
+		}
+	}
+	return nil
+}
+
+// collectMethods 收集目标类型的方法（按声明顺序）
+func (ex *interfaceExtraction) collectMethods() {
+	for _, f := range ex.files {
+		if f.dir != ex.typeDir {
+			continue
+		}
+		for _, decl := range f.file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || !ex.isTypeMethod(fn) {
+				continue
+			}
+			ex.methods[fn.Name.Name] = &ifaceMethod{decl: fn, file: f}
+			ex.order = append(ex.order, fn.Name.Name)
+		}
+	}
+}
+
+// isTypeMethod 判断函数是否为目标类型的方法
+func (ex *interfaceExtraction) isTypeMethod(fn *ast.FuncDecl) bool {
+	return fn.Recv != nil && len(fn.Recv.List) > 0 && receiverTypeName(fn.Recv.List[0].Type) == ex.typeName
+}
+
+// resolveQualifiers 计算每个文件引用目标类型时使用的包名
+func (ex *interfaceExtraction) resolveQualifiers() {
+	typePkg := filepath.Base(ex.typeDir)
+	for _, f := range ex.files {
+		if f.dir == ex.typeDir {
+			f.imported = true
+			continue
+		}
+		for _, imp := range f.file.Imports {
+			importPath, _ := strconv.Unquote(imp.Path.Value)
+			if path.Base(importPath) != typePkg {
+				continue
+			}
+			f.qualifier = path.Base(importPath)
+			if imp.Name != nil {
+				f.qualifier = imp.Name.Name
+			}
+			f.imported = f.qualifier != "_" && f.qualifier != "."
+		}
+	}
+}
+
+// isTargetPointer 判断类型表达式是否为 *T（其他包中为 *pkg.T）
+func (ex *interfaceExtraction) isTargetPointer(f *ifaceFile, expr ast.Expr) bool {
+	star, ok := expr.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	if f.qualifier == "" {
+		ident, ok := star.X.(*ast.Ident)
+		return ok && ident.Name == ex.typeName
+	}
+	sel, ok := star.X.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != ex.typeName {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && ident.Name == f.qualifier
+}
+
+// isTargetValue 判断表达式是否构造了目标类型（&T{...} 或返回 *T 的构造函数）
+func (ex *interfaceExtraction) isTargetValue(f *ifaceFile, expr ast.Expr, constructors map[string]bool) bool {
+	switch e := expr.(type) {
+	case *ast.UnaryExpr:
+		if lit, ok := e.X.(*ast.CompositeLit); ok && e.Op == token.AND {
+			return ex.isTargetPointer(f, &ast.StarExpr{X: lit.Type})
+		}
+	case *ast.CallExpr:
+		switch fun := e.Fun.(type) {
+		case *ast.Ident:
+			return f.qualifier == "" && constructors[fun.Name]
+		case *ast.SelectorExpr:
+			ident, ok := fun.X.(*ast.Ident)
+			return ok && f.qualifier != "" && ident.Name == f.qualifier && constructors[fun.Sel.Name]
+		}
+	}
+	return false
+}
+
+// collectTypedNames 收集类型为 *T 的参数、字段和局部变量
+func (ex *interfaceExtraction) collectTypedNames() {
+	// 返回 *T 的构造函数
+	constructors := make(map[string]bool)
+	for _, f := range ex.files {
+		if f.dir != ex.typeDir {
+			continue
+		}
+		for _, decl := range f.file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if ok && fn.Recv == nil && fn.Type.Results != nil && ex.isTargetPointer(f, fn.Type.Results.List[0].Type) {
+				constructors[fn.Name.Name] = true
+			}
+		}
+	}
+
+	for _, f := range ex.files {
+		if !f.imported {
+			continue
+		}
+
+		ast.Inspect(f.file, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.FuncDecl:
+				// 目标类型自身的方法不是注入点
+				if f.dir == ex.typeDir && ex.isTypeMethod(node) {
+					return false
+				}
+				ex.collectParams(f, node.Type, node.Body)
+			case *ast.FuncLit:
+				ex.collectParams(f, node.Type, node.Body)
+			case *ast.TypeSpec:
+				st, ok := node.Type.(*ast.StructType)
+				if !ok {
+					return true
+				}
+				for _, field := range st.Fields.List {
+					if !ex.isTargetPointer(f, field.Type) {
+						continue
+					}
+					for _, name := range field.Names {
+						ex.fieldNames[name.Name] = true
+						ex.fields = append(ex.fields, &ifaceInjection{
+							point: InjectionPoint{File: f.path, Line: ex.fset.Position(name.Pos()).Line, Kind: "field", Name: node.Name.Name + "." + name.Name},
+							file:  f,
+							expr:  field.Type,
+						})
+					}
+				}
+			case *ast.ValueSpec:
+				if node.Type != nil && ex.isTargetPointer(f, node.Type) {
+					for _, name := range node.Names {
+						ex.objects[name.Obj] = true
+					}
+				}
+			case *ast.AssignStmt:
+				if node.Tok != token.DEFINE || len(node.Lhs) != len(node.Rhs) {
+					return true
+				}
+				for i, rhs := range node.Rhs {
+					if ident, ok := node.Lhs[i].(*ast.Ident); ok && ident.Obj != nil && ex.isTargetValue(f, rhs, constructors) {
+						ex.objects[ident.Obj] = true
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+// collectParams 收集类型为 *T 的函数参数
+func (ex *interfaceExtraction) collectParams(f *ifaceFile, ft *ast.FuncType, body *ast.BlockStmt) {
+	if ft.Params == nil || body == nil {
+		return
+	}
+	for _, field := range ft.Params.List {
+		if !ex.isTargetPointer(f, field.Type) {
+			continue
+		}
+		for _, name := range field.Names {
+			if name.Obj == nil {
+				continue
+			}
+			ex.objects[name.Obj] = true
+			ex.params = append(ex.params, &ifaceInjection{
+				point: InjectionPoint{File: f.path, Line: ex.fset.Position(name.Pos()).Line, Kind: "param", Name: name.Name},
+				file:  f,
+				expr:  field.Type,
+				obj:   name.Obj,
+				body:  body,
+			})
+		}
+	}
+}
+
+// collectUsedMethods 统计调用点用到的方法
+func (ex *interfaceExtraction) collectUsedMethods() {
+	for _, f := range ex.files {
+		if !f.imported {
+			continue
+		}
+		ast.Inspect(f.file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || ex.methods[sel.Sel.Name] == nil {
+				return true
+			}
+			switch x := sel.X.(type) {
+			case *ast.Ident:
+				if x.Obj != nil && ex.objects[x.Obj] {
+					ex.used[sel.Sel.Name] = true
+				}
+			case *ast.SelectorExpr:
+				if ex.fieldNames[x.Sel.Name] {
+					ex.used[sel.Sel.Name] = true
+				}
+			}
+			return true
+		})
+	}
+}
+
+// checkInjection 检查注入点的所有用法是否都能通过接口完成，不能时返回原因
+func (ex *interfaceExtraction) checkInjection(inj *ifaceInjection) string {
+	reason := ""
+	check := func(use ast.Node, parents []ast.Node) {
+		if reason != "" || len(parents) == 0 {
+			return
+		}
+		parent := parents[len(parents)-1]
+		if assign, ok := parent.(*ast.AssignStmt); ok && inj.point.Kind == "field" {
+			for _, lhs := range assign.Lhs {
+				if lhs == use {
+					return // 字段赋值：*T 实现了接口，仍然合法
+				}
+			}
+		}
+		if sel, ok := parent.(*ast.SelectorExpr); ok && sel.X == use && len(parents) > 1 {
+			if call, ok := parents[len(parents)-2].(*ast.CallExpr); ok && call.Fun == sel && ex.used[sel.Sel.Name] {
+				return
+			}
+			reason = fmt.Sprintf("%s:%d 直接访问了 %s", inj.file.path, ex.fset.Position(use.Pos()).Line, sel.Sel.Name)
+			return
+		}
+		reason = fmt.Sprintf("%s:%d 以具体类型使用", inj.file.path, ex.fset.Position(use.Pos()).Line)
+	}
+
+	if inj.point.Kind == "param" {
+		inspectWithParents(inj.body, func(n ast.Node, parents []ast.Node) {
+			if ident, ok := n.(*ast.Ident); ok && ident.Obj == inj.obj {
+				check(ident, parents)
+			}
+		})
+		return reason
+	}
+
+	field := inj.point.Name[strings.LastIndex(inj.point.Name, ".")+1:]
+	for _, f := range ex.files {
+		inspectWithParents(f.file, func(n ast.Node, parents []ast.Node) {
+			if sel, ok := n.(*ast.SelectorExpr); ok && sel.Sel.Name == field {
+				check(sel, parents)
+			}
+		})
+	}
+	return reason
+}
+
+// renderInterface 生成接口声明和编译期断言
+func (ex *interfaceExtraction) renderInterface(name string, methods []string) string {
+	pointer := false
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("// %s 是 %s 对外使用的方法集，便于在测试中替换实现\n", name, ex.typeName))
+	sb.WriteString(fmt.Sprintf("type %s interface {\n", name))
+	for _, m := range methods {
+		method := ex.methods[m]
+		if _, ok := method.decl.Recv.List[0].Type.(*ast.StarExpr); ok {
+			pointer = true
+		}
+		if method.decl.Doc != nil {
+			if doc := strings.SplitN(strings.TrimSpace(method.decl.Doc.Text()), "\n", 2)[0]; doc != "" {
+				sb.WriteString("\t// " + doc + "\n")
+			}
+		}
+		ft := method.decl.Type
+		start := ex.fset.Position(ft.Params.Pos()).Offset
+		end := ex.fset.Position(ft.End()).Offset
+		sb.WriteString("\t" + m + string(method.file.src[start:end]) + "\n")
+	}
+	sb.WriteString("}\n\n")
+
+	if pointer {
+		sb.WriteString(fmt.Sprintf("var _ %s = (*%s)(nil)", name, ex.typeName))
+	} else {
+		sb.WriteString(fmt.Sprintf("var _ %s = %s{}", name, ex.typeName))
+	}
+	return sb.String()
+}
+
+// inspectWithParents 遍历 AST，并提供当前节点的祖先链
+func inspectWithParents(root ast.Node, fn func(n ast.Node, parents []ast.Node)) {
+	var stack []ast.Node
+	ast.Inspect(root, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return false
+		}
+		fn(n, stack)
+		stack = append(stack, n)
+		return true
+	})
+}
+
+// sortedInjectionPoints 按文件和行号排序
+func sortedInjectionPoints(points []InjectionPoint) []InjectionPoint {
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].File != points[j].File {
+			return points[i].File < points[j].File
+		}
+		return points[i].Line < points[j].Line
+	})
+	return points
+}
@@ -0,0 +1,147 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeInterfaceProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"svc/engine.go": `package svc
+
+import "context"
+
+// Engine 问答引擎
+type Engine struct {
+	Name string
+}
+
+// NewEngine 创建引擎
+func NewEngine() *Engine {
+	return &Engine{}
+}
+
+// Ask 提问
+// 返回模型回答
+func (e *Engine) Ask(ctx context.Context, question string) (string, error) {
+	return e.normalize(question), nil
+}
+
+// Index 建立索引
+func (e *Engine) Index(dir string) error {
+	return nil
+}
+
+func (e *Engine) normalize(s string) string {
+	return s
+}
+`,
+		"app/app.go": `package app
+
+import (
+	"context"
+	"example.com/demo/svc"
+)
+
+// App 应用
+type App struct {
+	engine *svc.Engine
+	backup *svc.Engine
+}
+
+func (a *App) Run(ctx context.Context) (string, error) {
+	return a.engine.Ask(ctx, "hi")
+}
+
+func (a *App) Label() string {
+	return a.backup.Name
+}
+
+func Answer(ctx context.Context, e *svc.Engine) (string, error) {
+	return e.Ask(ctx, "q")
+}
+
+func Setup() {
+	e := svc.NewEngine()
+	_ = e.Index(".")
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("创建目录失败: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("写入测试文件失败: %v", err)
+		}
+	}
+	return dir
+}
+
+// 测试按调用点抽取接口并更新注入点
+func TestInterfaceExtractor_Extract(t *testing.T) {
+	dir := writeInterfaceProject(t)
+	extractor := NewInterfaceExtractor()
+
+	output, err := extractor.Run(context.Background(), InterfaceRequest{Directory: dir, Type: "svc.Engine", Write: true})
+	if err != nil {
+		t.Fatalf("抽取接口失败: %v", err)
+	}
+
+	var result InterfaceResult
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("解析结果失败: %v", err)
+	}
+
+	if strings.Join(result.Methods, ",") != "Ask,Index" {
+		t.Fatalf("方法集应该只包含被调用的方法: %v", result.Methods)
+	}
+	if len(result.Updated) != 2 || len(result.Skipped) != 1 || result.Skipped[0].Name != "App.backup" {
+		t.Fatalf("注入点处理不正确: updated=%+v skipped=%+v", result.Updated, result.Skipped)
+	}
+	if len(result.Patches) != 2 {
+		t.Fatalf("应该生成 2 个文件的补丁: %+v", result.Patches)
+	}
+
+	engine, _ := os.ReadFile(filepath.Join(dir, "svc/engine.go"))
+	for _, want := range []string{
+		"type EngineAPI interface {",
+		"\t// Ask 提问\n\tAsk(ctx context.Context, question string) (string, error)",
+		"\tIndex(dir string) error\n}",
+		"var _ EngineAPI = (*Engine)(nil)",
+	} {
+		if !strings.Contains(string(engine), want) {
+			t.Errorf("接口声明缺少 %q:\n%s", want, engine)
+		}
+	}
+
+	app, _ := os.ReadFile(filepath.Join(dir, "app/app.go"))
+	for _, want := range []string{"engine svc.EngineAPI", "backup *svc.Engine", "e svc.EngineAPI)"} {
+		if !strings.Contains(string(app), want) {
+			t.Errorf("注入点未正确更新，缺少 %q:\n%s", want, app)
+		}
+	}
+}
+
+// 测试类型不存在和接口名冲突
+func TestInterfaceExtractor_Errors(t *testing.T) {
+	dir := writeInterfaceProject(t)
+	extractor := NewInterfaceExtractor()
+
+	if _, err := extractor.Run(context.Background(), InterfaceRequest{Directory: dir, Type: "Missing"}); err == nil {
+		t.Fatal("类型不存在时应该返回错误")
+	}
+	if _, err := extractor.Run(context.Background(), InterfaceRequest{Directory: dir, Type: "Engine", Name: "NewEngine"}); err == nil {
+		t.Fatal("接口名冲突时应该返回错误")
+	}
+	if err := extractor.Validate(InterfaceRequest{Directory: dir}); err == nil {
+		t.Fatal("缺少 Type 时应该校验失败")
+	}
+}
@@ -0,0 +1,394 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/build/constraint"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// defaultModuleProxy 未配置 GOPROXY 时使用的模块代理
+const defaultModuleProxy = "https://proxy.golang.org"
+
+// ModuleInventory 模块与构建工具清单
+// 汇总 go 版本、依赖及其可用更新、replace 指令和使用中的构建标签，用于合规快照
+type ModuleInventory struct {
+	*TypedTool[InventoryRequest, *InventoryResult]
+	client *http.Client
+}
+
+// NewModuleInventory 创建模块清单工具
+func NewModuleInventory() *ModuleInventory {
+	mi := &ModuleInventory{
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+	mi.TypedTool = NewTypedTool[InventoryRequest, *InventoryResult](
+		"inventory",
+		"汇总 go 版本、模块依赖（含可用更新）、replace 指令和构建标签",
+		mi,
+	)
+	return mi
+}
+
+// InventoryRequest 模块清单请求
+type InventoryRequest struct {
+	Directory    string `json:"directory" jsonschema:"required"` // 模块目录（向上查找 go.mod）
+	CheckUpdates bool   `json:"check_updates,omitempty"`         // 是否查询模块代理获取最新版本
+	Proxy        string `json:"proxy,omitempty"`                 // 模块代理地址，默认取 GOPROXY
+}
+
+// InventoryDependency 模块依赖
+type InventoryDependency struct {
+	Path     string `json:"path"`
+	Version  string `json:"version"`
+	Indirect bool   `json:"indirect"`
+	Latest   string `json:"latest,omitempty"`       // 代理上的最新版本
+	Update   bool   `json:"update_available"`       // 是否有更新
+	Error    string `json:"update_error,omitempty"` // 查询更新失败的原因
+}
+
+// InventoryReplace replace 指令
+type InventoryReplace struct {
+	Old        string `json:"old"`
+	OldVersion string `json:"old_version,omitempty"`
+	New        string `json:"new"`
+	NewVersion string `json:"new_version,omitempty"`
+}
+
+// BuildTagUsage 构建标签的使用情况
+type BuildTagUsage struct {
+	Tag   string   `json:"tag"`
+	Files []string `json:"files"`
+}
+
+// InventoryResult 模块清单结果
+type InventoryResult struct {
+	Module       string                `json:"module"`
+	GoMod        string                `json:"go_mod"`              // go.mod 路径
+	GoVersion    string                `json:"go_version"`          // go 指令
+	Toolchain    string                `json:"toolchain,omitempty"` // toolchain 指令
+	LocalGo      string                `json:"local_go,omitempty"`  // 本机 go 版本
+	Dependencies []InventoryDependency `json:"dependencies"`
+	Replaces     []InventoryReplace    `json:"replaces"`
+	Excludes     []string              `json:"excludes"`
+	BuildTags    []BuildTagUsage       `json:"build_tags"`
+	Proxy        string                `json:"proxy,omitempty"` // 查询更新使用的代理
+	Updates      int                   `json:"updates"`         // 有更新的依赖数
+	Summary      string                `json:"summary"`
+}
+
+// ValidateInput 验证输入参数
+func (mi *ModuleInventory) ValidateInput(req InventoryRequest) error {
+	if req.Directory == "" {
+		return fmt.Errorf("必须指定 Directory")
+	}
+	return nil
+}
+
+// Execute 生成模块清单
+func (mi *ModuleInventory) Execute(ctx context.Context, req InventoryRequest) (*InventoryResult, error) {
+	root, _, err := findModule(req.Directory)
+	if err != nil {
+		return nil, err
+	}
+	goMod := filepath.Join(root, "go.mod")
+	data, err := os.ReadFile(goMod)
+	if err != nil {
+		return nil, fmt.Errorf("读取 go.mod 失败: %w", err)
+	}
+	mf, err := modfile.Parse(goMod, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("解析 go.mod 失败: %w", err)
+	}
+
+	result := InventoryResult{
+		Module:       mf.Module.Mod.Path,
+		GoMod:        goMod,
+		LocalGo:      localGoVersion(ctx),
+		Dependencies: []InventoryDependency{},
+		Replaces:     []InventoryReplace{},
+		Excludes:     []string{},
+	}
+	if mf.Go != nil {
+		result.GoVersion = mf.Go.Version
+	}
+	if mf.Toolchain != nil {
+		result.Toolchain = mf.Toolchain.Name
+	}
+	for _, r := range mf.Require {
+		result.Dependencies = append(result.Dependencies, InventoryDependency{
+			Path: r.Mod.Path, Version: r.Mod.Version, Indirect: r.Indirect,
+		})
+	}
+	for _, r := range mf.Replace {
+		result.Replaces = append(result.Replaces, InventoryReplace{
+			Old: r.Old.Path, OldVersion: r.Old.Version, New: r.New.Path, NewVersion: r.New.Version,
+		})
+	}
+	for _, e := range mf.Exclude {
+		result.Excludes = append(result.Excludes, e.Mod.Path+"@"+e.Mod.Version)
+	}
+
+	if result.BuildTags, err = collectBuildTags(ctx, root); err != nil {
+		return nil, err
+	}
+
+	if req.CheckUpdates {
+		result.Proxy = moduleProxy(req.Proxy)
+		if result.Proxy == "" {
+			result.Proxy = "off"
+		} else {
+			mi.checkUpdates(ctx, result.Proxy, result.Dependencies)
+		}
+		for _, dep := range result.Dependencies {
+			if dep.Update {
+				result.Updates++
+			}
+		}
+	}
+
+	result.Summary = fmt.Sprintf("模块 %s（go %s）：%d 个依赖，%d 条 replace，%d 个构建标签",
+		result.Module, result.GoVersion, len(result.Dependencies), len(result.Replaces), len(result.BuildTags))
+	if req.CheckUpdates {
+		result.Summary += fmt.Sprintf("，%d 个依赖有可用更新", result.Updates)
+	}
+
+	return &result, nil
+}
+
+// localGoVersion 获取本机 go 工具链版本，go 不可用时返回空
+func localGoVersion(ctx context.Context) string {
+	out, err := exec.CommandContext(ctx, "go", "env", "GOVERSION").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// moduleProxy 返回用于查询的模块代理地址；GOPROXY 为 off 或只有 direct 时返回空
+func moduleProxy(proxy string) string {
+	if proxy == "" {
+		proxy = os.Getenv("GOPROXY")
+	}
+	if proxy == "" {
+		return defaultModuleProxy
+	}
+	for _, p := range strings.FieldsFunc(proxy, func(r rune) bool { return r == ',' || r == '|' }) {
+		if p == "off" {
+			return ""
+		}
+		if p != "direct" {
+			return strings.TrimSuffix(p, "/")
+		}
+	}
+	return ""
+}
+
+// checkUpdates 并发查询模块代理的 @latest，填充最新版本
+func (mi *ModuleInventory) checkUpdates(ctx context.Context, proxy string, deps []InventoryDependency) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, 8)
+	for i := range deps {
+		wg.Add(1)
+		go func(dep *InventoryDependency) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			latest, err := mi.latestVersion(ctx, proxy, dep.Path)
+			if err != nil {
+				dep.Error = err.Error()
+				return
+			}
+			dep.Latest = latest
+			dep.Update = semver.Compare(latest, dep.Version) > 0
+		}(&deps[i])
+	}
+	wg.Wait()
+}
+
+// latestVersion 查询模块代理上的最新版本（GOPROXY 协议的 @latest 接口）
+func (mi *ModuleInventory) latestVersion(ctx context.Context, proxy, path string) (string, error) {
+	escaped, err := module.EscapePath(path)
+	if err != nil {
+		return "", fmt.Errorf("模块路径无效: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, proxy+"/"+escaped+"/@latest", nil)
+	if err != nil {
+		return "", fmt.Errorf("创建请求失败: %w", err)
+	}
+	resp, err := mi.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("查询代理失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("代理返回 %s", resp.Status)
+	}
+
+	var info struct {
+		Version string `json:"Version"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return "", fmt.Errorf("解析代理响应失败: %w", err)
+	}
+	return info.Version, nil
+}
+
+// collectBuildTags 收集 //go:build 约束中使用的标签及其文件
+func collectBuildTags(ctx context.Context, root string) ([]BuildTagUsage, error) {
+	files, err := collectGoFiles(ctx, root)
+	if err != nil {
+		return nil, fmt.Errorf("文件收集失败: %w", err)
+	}
+
+	tagFiles := make(map[string][]string)
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		src, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(src), "\n") {
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, "package ") {
+				break // 构建约束只能出现在 package 子句之前
+			}
+			if !constraint.IsGoBuild(line) {
+				continue
+			}
+			expr, err := constraint.Parse(line)
+			if err != nil {
+				continue
+			}
+			rel, err := filepath.Rel(root, file)
+			if err != nil {
+				rel = file
+			}
+			for _, tag := range constraintTags(expr) {
+				tagFiles[tag] = append(tagFiles[tag], filepath.ToSlash(rel))
+			}
+		}
+	}
+
+	usages := []BuildTagUsage{}
+	for tag, files := range tagFiles {
+		usages = append(usages, BuildTagUsage{Tag: tag, Files: files})
+	}
+	sort.Slice(usages, func(i, j int) bool { return usages[i].Tag < usages[j].Tag })
+	return usages, nil
+}
+
+// constraintTags 提取构建约束表达式中的标签（去重）
+func constraintTags(expr constraint.Expr) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	var walk func(constraint.Expr)
+	walk = func(e constraint.Expr) {
+		switch x := e.(type) {
+		case *constraint.TagExpr:
+			if !seen[x.Tag] {
+				seen[x.Tag] = true
+				tags = append(tags, x.Tag)
+			}
+		case *constraint.NotExpr:
+			walk(x.X)
+		case *constraint.AndExpr:
+			walk(x.X)
+			walk(x.Y)
+		case *constraint.OrExpr:
+			walk(x.X)
+			walk(x.Y)
+		}
+	}
+	walk(expr)
+	return tags
+}
+
+// FormatInventoryMarkdown 将模块清单渲染为 Markdown
+func FormatInventoryMarkdown(inv *InventoryResult) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# 模块清单: %s\n\n", inv.Module))
+	sb.WriteString(fmt.Sprintf("- go.mod: `%s`\n", inv.GoMod))
+	sb.WriteString(fmt.Sprintf("- go 指令: %s\n", inv.GoVersion))
+	if inv.Toolchain != "" {
+		sb.WriteString(fmt.Sprintf("- toolchain: %s\n", inv.Toolchain))
+	}
+	if inv.LocalGo != "" {
+		sb.WriteString(fmt.Sprintf("- 本机 go: %s\n", inv.LocalGo))
+	}
+
+	checked := inv.Proxy != "" && inv.Proxy != "off"
+	sb.WriteString(fmt.Sprintf("\n## 依赖（%d）\n\n", len(inv.Dependencies)))
+	if inv.Proxy == "off" {
+		sb.WriteString("模块代理已关闭（GOPROXY=off），跳过更新检查\n\n")
+	}
+	if checked {
+		sb.WriteString(fmt.Sprintf("更新来源: %s\n\n", inv.Proxy))
+		sb.WriteString("| 模块 | 版本 | 间接 | 最新版本 |\n|------|------|------|----------|\n")
+	} else {
+		sb.WriteString("| 模块 | 版本 | 间接 |\n|------|------|------|\n")
+	}
+	for _, dep := range inv.Dependencies {
+		indirect := ""
+		if dep.Indirect {
+			indirect = "是"
+		}
+		if !checked {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", dep.Path, dep.Version, indirect))
+			continue
+		}
+		latest := dep.Latest
+		switch {
+		case dep.Error != "":
+			latest = "查询失败"
+		case dep.Update:
+			latest = "**" + dep.Latest + "**"
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", dep.Path, dep.Version, indirect, latest))
+	}
+
+	if len(inv.Replaces) > 0 {
+		sb.WriteString(fmt.Sprintf("\n## replace 指令（%d）\n\n", len(inv.Replaces)))
+		for _, r := range inv.Replaces {
+			sb.WriteString(fmt.Sprintf("- %s => %s\n", joinModuleVersion(r.Old, r.OldVersion), joinModuleVersion(r.New, r.NewVersion)))
+		}
+	}
+	if len(inv.Excludes) > 0 {
+		sb.WriteString(fmt.Sprintf("\n## exclude 指令（%d）\n\n", len(inv.Excludes)))
+		for _, e := range inv.Excludes {
+			sb.WriteString(fmt.Sprintf("- %s\n", e))
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("\n## 构建标签（%d）\n\n", len(inv.BuildTags)))
+	if len(inv.BuildTags) == 0 {
+		sb.WriteString("未使用构建标签\n")
+	}
+	for _, tag := range inv.BuildTags {
+		sb.WriteString(fmt.Sprintf("- `%s`: %s\n", tag.Tag, strings.Join(tag.Files, ", ")))
+	}
+	return sb.String()
+}
+
+// joinModuleVersion 拼接模块路径和版本
+func joinModuleVersion(path, version string) string {
+	if version == "" {
+		return path
+	}
+	return path + " " + version
+}
@@ -0,0 +1,119 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 测试解析 go.mod、构建标签，并通过模块代理检查更新
+func TestModuleInventory(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": `module example.com/app
+
+go 1.22
+
+toolchain go1.22.3
+
+require (
+	github.com/Foo/bar v1.2.0
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace github.com/Foo/bar => ../bar
+
+exclude golang.org/x/text v0.13.0
+`,
+		"main.go":        "package main\n\nfunc main() {}\n",
+		"linux.go":       "//go:build linux && !cgo\n\npackage main\n",
+		"debug/debug.go": "//go:build debug || linux\n\npackage debug\n",
+		"notag/notag.go": "package notag\n\n//go:build ignored\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("创建目录失败: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("写入测试文件失败: %v", err)
+		}
+	}
+
+	// 模块代理：大写字母按 GOPROXY 协议转义为 !小写
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/github.com/!foo/bar/@latest":
+			w.Write([]byte(`{"Version":"v1.3.1"}`))
+		case "/golang.org/x/text/@latest":
+			w.Write([]byte(`{"Version":"v0.14.0"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer proxy.Close()
+
+	output, err := NewModuleInventory().Run(context.Background(), InventoryRequest{
+		Directory:    filepath.Join(dir, "debug"),
+		CheckUpdates: true,
+		Proxy:        proxy.URL + ",direct",
+	})
+	if err != nil {
+		t.Fatalf("生成清单失败: %v", err)
+	}
+	var inv InventoryResult
+	if err := json.Unmarshal([]byte(output), &inv); err != nil {
+		t.Fatalf("解析结果失败: %v", err)
+	}
+
+	if inv.Module != "example.com/app" || inv.GoVersion != "1.22" || inv.Toolchain != "go1.22.3" {
+		t.Errorf("模块信息不正确: %+v", inv)
+	}
+	if len(inv.Dependencies) != 2 || inv.Updates != 1 {
+		t.Fatalf("依赖或更新数不正确: %+v", inv.Dependencies)
+	}
+	if dep := inv.Dependencies[0]; !dep.Update || dep.Latest != "v1.3.1" {
+		t.Errorf("github.com/Foo/bar 应有更新: %+v", dep)
+	}
+	if dep := inv.Dependencies[1]; dep.Update || !dep.Indirect {
+		t.Errorf("golang.org/x/text 已是最新且为间接依赖: %+v", dep)
+	}
+	if len(inv.Replaces) != 1 || inv.Replaces[0].New != "../bar" || len(inv.Excludes) != 1 {
+		t.Errorf("replace/exclude 不正确: %+v %+v", inv.Replaces, inv.Excludes)
+	}
+
+	tags := make(map[string]int)
+	for _, usage := range inv.BuildTags {
+		tags[usage.Tag] = len(usage.Files)
+	}
+	if len(tags) != 3 || tags["linux"] != 2 || tags["cgo"] != 1 || tags["debug"] != 1 {
+		t.Errorf("构建标签不正确: %+v", inv.BuildTags)
+	}
+
+	md := FormatInventoryMarkdown(&inv)
+	for _, want := range []string{"# 模块清单: example.com/app", "**v1.3.1**", "github.com/Foo/bar => ../bar", "`linux`"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown 缺少 %q:\n%s", want, md)
+		}
+	}
+}
+
+// 测试 GOPROXY 解析
+func TestModuleProxy(t *testing.T) {
+	tests := map[string]string{
+		"https://goproxy.cn,direct":    "https://goproxy.cn",
+		"direct":                       "",
+		"off":                          "",
+		"https://a.example/|https://b": "https://a.example",
+	}
+	for in, want := range tests {
+		if got := moduleProxy(in); got != want {
+			t.Errorf("moduleProxy(%q) = %q, 期望 %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime/metrics"
+	"sync/atomic"
+	"time"
+)
+
+// memoryPollInterval 内存上限的检查间隔
+const memoryPollInterval = 50 * time.Millisecond
+
+// ResourceLimits 单次工具执行的资源上限，零值表示不限制
+// 执行时间仍由 ToolConfig.Timeout 控制
+type ResourceLimits struct {
+	MaxMemoryMB   int // 执行期间堆内存增长的上限，超过后取消执行
+	MaxFiles      int // 目录扫描的文件数上限，超过后扫描失败
+	MaxFileSizeKB int // 单个文件的大小上限，超过的文件在扫描时跳过
+
+	MaxFindings        int // bug_detector、security_scanner 结果中列出的问题数上限，超出的只计数
+	MaxFindingsPerRule int // 每条规则列出的问题数上限，超出的只计数
+}
+
+// IsZero 是否没有设置任何上限
+func (l ResourceLimits) IsZero() bool {
+	return l == ResourceLimits{}
+}
+
+// limitsKey 资源预算在 context 中的键
+type limitsKey struct{}
+
+// scanBudget 单次执行的资源预算，记录已扫描的文件数
+type scanBudget struct {
+	limits ResourceLimits
+	files  atomic.Int64
+}
+
+// WithResourceLimits 把资源上限放入 context，目录扫描通过 ScanFile 检查
+func WithResourceLimits(ctx context.Context, limits ResourceLimits) context.Context {
+	return context.WithValue(ctx, limitsKey{}, &scanBudget{limits: limits})
+}
+
+// ScanFile 目录扫描中登记一个文件
+// ctx 已取消时返回取消原因，超过文件数上限时返回 ErrResourceLimit；
+// skip 为 true 表示文件超过大小上限，调用方应跳过
+func ScanFile(ctx context.Context, info os.FileInfo) (skip bool, err error) {
+	if ctx.Err() != nil {
+		return false, context.Cause(ctx)
+	}
+	budget, _ := ctx.Value(limitsKey{}).(*scanBudget)
+	if budget == nil {
+		return false, nil
+	}
+	if kb := budget.limits.MaxFileSizeKB; kb > 0 && info != nil && info.Size() > int64(kb)<<10 {
+		return true, nil
+	}
+	if max := budget.limits.MaxFiles; max > 0 && budget.files.Add(1) > int64(max) {
+		return false, fmt.Errorf("%w: 扫描文件数超过 %d", ErrResourceLimit, max)
+	}
+	return false, nil
+}
+
+// watchMemory 监控执行期间的堆内存增长，超过上限时以 ErrResourceLimit 取消执行
+// 堆内存是进程级的，并发执行的其他工具也会计入，上限应按整个进程预留余量
+func watchMemory(ctx context.Context, limitMB int, cancel context.CancelCauseFunc) (stop func()) {
+	if limitMB <= 0 {
+		return func() {}
+	}
+	baseline := heapBytes()
+	limit := uint64(limitMB) << 20
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(memoryPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if used := heapBytes(); used > baseline && used-baseline > limit {
+					cancel(fmt.Errorf("%w: 内存增长 %d MB，超过 %d MB", ErrResourceLimit, (used-baseline)>>20, limitMB))
+					return
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// heapBytes 当前堆上存活对象占用的字节数
+func heapBytes() uint64 {
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
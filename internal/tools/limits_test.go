@@ -0,0 +1,150 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 测试目录扫描的文件数和文件大小上限
+func TestScanFile_Limits(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 3; i++ {
+		os.WriteFile(filepath.Join(dir, fmt.Sprintf("f%d.go", i)), []byte("package x\n"), 0644)
+	}
+	os.WriteFile(filepath.Join(dir, "big.go"), []byte("package x\n"+strings.Repeat("// x\n", 1000)), 0644)
+
+	// 不限制
+	files, err := collectGoFiles(context.Background(), dir)
+	if err != nil || len(files) != 4 {
+		t.Fatalf("不限制时应该收集全部文件: %v %v", files, err)
+	}
+
+	// 超过大小上限的文件跳过
+	ctx := WithResourceLimits(context.Background(), ResourceLimits{MaxFileSizeKB: 1})
+	files, err = collectGoFiles(ctx, dir)
+	if err != nil || len(files) != 3 {
+		t.Errorf("大文件应该跳过: %v %v", files, err)
+	}
+
+	// 超过文件数上限时失败
+	ctx = WithResourceLimits(context.Background(), ResourceLimits{MaxFiles: 2})
+	if _, err := collectGoFiles(ctx, dir); !errors.Is(err, ErrResourceLimit) {
+		t.Errorf("超过文件数上限应该返回 ErrResourceLimit，实际 %v", err)
+	}
+}
+
+// 测试 ToolManager 按配置施加资源上限，超限时不重试
+func TestToolManager_ResourceLimits(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 5; i++ {
+		os.WriteFile(filepath.Join(dir, fmt.Sprintf("f%d.go", i)), []byte("package x\n"), 0644)
+	}
+
+	tm := NewToolManager(NewNoopLogger())
+	calls := 0
+	tool := NewMockTool("scan_tool", func(ctx context.Context, input any) (string, error) {
+		calls++
+		files, err := collectGoFiles(ctx, dir)
+		if err != nil {
+			return "", fmt.Errorf("扫描失败: %w", err)
+		}
+		return fmt.Sprintf("%d", len(files)), nil
+	})
+	config := DefaultToolConfig("scan_tool")
+	config.MaxRetries = 2
+	config.Limits = ResourceLimits{MaxFiles: 3}
+	tm.Register(tool, config)
+
+	result, err := tm.Run(context.Background(), "scan_tool", "input")
+	if err != nil || result.Success {
+		t.Fatalf("超过文件数上限应该失败: %+v %v", result, err)
+	}
+	if !strings.Contains(result.Error, ErrResourceLimit.Error()) {
+		t.Errorf("错误信息应该说明超出资源限制: %s", result.Error)
+	}
+	if calls != 1 {
+		t.Errorf("超出资源限制不应该重试，实际执行 %d 次", calls)
+	}
+
+	// 放宽上限后成功
+	config.Limits.MaxFiles = 10
+	tm.UpdateConfig("scan_tool", config)
+	result, _ = tm.Run(context.Background(), "scan_tool", "input")
+	if !result.Success || result.Result != "5" {
+		t.Errorf("未超限时应该成功: %+v", result)
+	}
+}
+
+// 测试内存超过上限时取消执行
+func TestToolManager_MemoryLimit(t *testing.T) {
+	tm := NewToolManager(NewNoopLogger())
+	var hold [][]byte
+	tool := NewMockTool("memory_tool", func(ctx context.Context, input any) (string, error) {
+		for {
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(5 * time.Millisecond):
+				hold = append(hold, make([]byte, 4<<20))
+			}
+		}
+	})
+	config := DefaultToolConfig("memory_tool")
+	config.Timeout = 10000
+	config.Limits = ResourceLimits{MaxMemoryMB: 32}
+	tm.Register(tool, config)
+
+	result, err := tm.Run(context.Background(), "memory_tool", "input")
+	if err != nil || result.Success {
+		t.Fatalf("超过内存上限应该失败: %+v %v", result, err)
+	}
+	if !strings.Contains(result.Error, ErrResourceLimit.Error()) {
+		t.Errorf("错误信息应该说明超出资源限制: %s", result.Error)
+	}
+	hold = nil
+}
+
+// 测试取消后目录扫描、单文件分析和 ToolManager 的重试都会停止
+func TestCancellation(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 3; i++ {
+		os.WriteFile(filepath.Join(dir, fmt.Sprintf("f%d.go", i)), []byte("package x\n\nfunc f() {}\n"), 0644)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := NewBugDetector().Execute(ctx, BugDetectorInput{Directory: dir}); !errors.Is(err, context.Canceled) {
+		t.Errorf("目录扫描应返回 context.Canceled, 实际 %v", err)
+	}
+	if _, err := NewBugDetector().Execute(ctx, BugDetectorInput{Code: "package x\n\nfunc f() {}\n"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("单文件分析应返回 context.Canceled, 实际 %v", err)
+	}
+	if _, err := NewSecurityScanner().Execute(ctx, SecurityScanInput{Code: "package x\n\nfunc f() {}\n"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("安全扫描应返回 context.Canceled, 实际 %v", err)
+	}
+
+	tm := NewToolManager(NewNoopLogger())
+	attempts := 0
+	tool := NewMockTool("slow", func(ctx context.Context, input any) (string, error) {
+		attempts++
+		return "", ctx.Err()
+	})
+	config := DefaultToolConfig("slow")
+	config.MaxRetries = 3
+	if err := tm.Register(tool, config); err != nil {
+		t.Fatal(err)
+	}
+	result, err := tm.Run(ctx, "slow", "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Success || attempts != 1 {
+		t.Errorf("取消后不应重试: success=%v attempts=%d", result.Success, attempts)
+	}
+}
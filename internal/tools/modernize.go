@@ -0,0 +1,500 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"go-ai-study/internal/i18n"
+	"go/ast"
+	"go/token"
+	"go/version"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ModernizeAnalyzer 现代化建议分析器
+// 找出已废弃的标准库用法和可以被新版本写法替代的旧惯用法，按规则汇总为升级清单；
+// 有确定性修复的规则可以交给 code_fixer 自动改写
+type ModernizeAnalyzer struct {
+	*TypedTool[ModernizeRequest, *ModernizeResult]
+	rules []ModernizeRule
+}
+
+// NewModernizeAnalyzer 创建现代化建议分析器
+func NewModernizeAnalyzer() *ModernizeAnalyzer {
+	ma := &ModernizeAnalyzer{rules: DefaultModernizeRules()}
+	ma.TypedTool = NewTypedTool[ModernizeRequest, *ModernizeResult](
+		"modernize",
+		"找出已废弃的标准库用法（io/ioutil、rand.Seed 等）和可替换的旧写法（interface{} → any、手动合并错误 → errors.Join），生成升级清单",
+		ma,
+	)
+	return ma
+}
+
+// ModernizeRequest 现代化建议请求
+type ModernizeRequest struct {
+	Files     []string `json:"files,omitempty"`      // 文件列表
+	Directory string   `json:"directory,omitempty"`  // 目录
+	GoVersion string   `json:"go_version,omitempty"` // 按该版本判断可用的写法（如 1.21），为空时使用 go.mod 中的版本
+	Rules     []string `json:"rules,omitempty"`      // 只检查指定规则（规则ID），为空表示全部
+}
+
+// ModernizeFinding 一处可现代化的代码
+type ModernizeFinding struct {
+	RuleID     string `json:"rule_id"`
+	Name       string `json:"name"`
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	Column     int    `json:"column"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion"`
+	Since      string `json:"since"`   // 新写法要求的最低 Go 版本
+	Fixable    bool   `json:"fixable"` // 是否可以由 code_fixer 确定性修复
+}
+
+// ModernizeChecklistItem 升级清单中的一项（按规则汇总）
+type ModernizeChecklistItem struct {
+	RuleID      string `json:"rule_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Since       string `json:"since"`
+	Count       int    `json:"count"`   // 出现次数
+	Fixable     int    `json:"fixable"` // 其中可自动修复的次数
+}
+
+// ModernizeResult 现代化建议结果
+type ModernizeResult struct {
+	TotalFiles int                      `json:"total_files"`
+	Findings   []ModernizeFinding       `json:"findings"`
+	Checklist  []ModernizeChecklistItem `json:"checklist"`
+	Skipped    []string                 `json:"skipped,omitempty"` // 无法解析的文件
+	Summary    string                   `json:"summary"`
+}
+
+// ModernizeRule 现代化规则
+type ModernizeRule interface {
+	ID() string
+	Name() string
+	Since() string // 新写法要求的最低 Go 版本（go/version 格式），目标版本更低时不报告
+	Description() string
+	Suggestion() string
+	// Check 返回文件中命中的位置
+	Check(fctx *FixContext) []ModernizeMatch
+}
+
+// ModernizeMatch 规则命中的一处代码
+type ModernizeMatch struct {
+	Node    ast.Node
+	Message string // 为空时使用规则描述
+	Fixable bool
+}
+
+// DefaultModernizeRules 内置现代化规则
+func DefaultModernizeRules() []ModernizeRule {
+	return []ModernizeRule{
+		&IoutilRule{},
+		&EmptyInterfaceRule{},
+		&ErrorsJoinRule{},
+		&RandSeedRule{},
+		&StringsTitleRule{},
+		&ReflectPtrRule{},
+	}
+}
+
+// ValidateInput 验证输入参数
+func (ma *ModernizeAnalyzer) ValidateInput(req ModernizeRequest) error {
+	if len(req.Files) == 0 && req.Directory == "" {
+		return fmt.Errorf("必须指定 Files 或 Directory")
+	}
+	if req.GoVersion != "" && !version.IsValid(goLangVersion(req.GoVersion)) {
+		return fmt.Errorf("%w: 无效的 Go 版本 %q", ErrInvalidInput, req.GoVersion)
+	}
+	return nil
+}
+
+// Execute 执行分析
+func (ma *ModernizeAnalyzer) Execute(ctx context.Context, req ModernizeRequest) (*ModernizeResult, error) {
+	files := req.Files
+	if req.Directory != "" {
+		dirFiles, err := collectGoFiles(ctx, req.Directory)
+		if err != nil {
+			return nil, fmt.Errorf("文件收集失败: %w", err)
+		}
+		files = append(files, dirFiles...)
+	}
+
+	rules := ma.selectRules(req.Rules)
+	versions := make(goVersionCache)
+	result := &ModernizeResult{TotalFiles: len(files), Findings: []ModernizeFinding{}, Checklist: []ModernizeChecklistItem{}}
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		src, err := os.ReadFile(file)
+		if err != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s: 读取失败: %v", file, err))
+			continue
+		}
+		fctx, err := newFixContext(file, src)
+		if err != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s: 解析失败: %v", file, err))
+			continue
+		}
+
+		target := req.GoVersion
+		if target == "" {
+			target = versions.lookup(filepath.Dir(file))
+		}
+		for _, rule := range rules {
+			if target != "" && version.Compare(rule.Since(), goLangVersion(target)) > 0 {
+				continue
+			}
+			for _, m := range rule.Check(fctx) {
+				result.Findings = append(result.Findings, newModernizeFinding(fctx.FSet, rule, m))
+			}
+		}
+	}
+
+	sort.SliceStable(result.Findings, func(i, j int) bool {
+		a, b := result.Findings[i], result.Findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	result.Checklist = modernizeChecklist(rules, result.Findings)
+
+	fixable := 0
+	for _, f := range result.Findings {
+		if f.Fixable {
+			fixable++
+		}
+	}
+	result.Summary = fmt.Sprintf("检查 %d 个文件，%d 处可以现代化（%d 类），其中 %d 处可自动修复",
+		result.TotalFiles, len(result.Findings), len(result.Checklist), fixable)
+	return result, nil
+}
+
+// selectRules 按规则ID筛选规则
+func (ma *ModernizeAnalyzer) selectRules(ids []string) []ModernizeRule {
+	if len(ids) == 0 {
+		return ma.rules
+	}
+	var selected []ModernizeRule
+	for _, rule := range ma.rules {
+		for _, id := range ids {
+			if strings.EqualFold(id, rule.ID()) {
+				selected = append(selected, rule)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// newModernizeFinding 创建现代化建议
+func newModernizeFinding(fset *token.FileSet, rule ModernizeRule, m ModernizeMatch) ModernizeFinding {
+	pos := fset.Position(m.Node.Pos())
+	message := m.Message
+	if message == "" {
+		message = ruleText(rule.ID(), "description", rule.Description())
+	}
+	return ModernizeFinding{
+		RuleID:     rule.ID(),
+		Name:       rule.Name(),
+		File:       pos.Filename,
+		Line:       pos.Line,
+		Column:     pos.Column,
+		Message:    message,
+		Suggestion: ruleText(rule.ID(), "suggestion", rule.Suggestion()),
+		Since:      rule.Since(),
+		Fixable:    m.Fixable,
+	}
+}
+
+// modernizeChecklist 按规则汇总，顺序与规则列表一致
+func modernizeChecklist(rules []ModernizeRule, findings []ModernizeFinding) []ModernizeChecklistItem {
+	items := []ModernizeChecklistItem{}
+	for _, rule := range rules {
+		item := ModernizeChecklistItem{RuleID: rule.ID(), Name: rule.Name(), Description: ruleText(rule.ID(), "description", rule.Description()), Since: rule.Since()}
+		for _, f := range findings {
+			if f.RuleID != rule.ID() {
+				continue
+			}
+			item.Count++
+			if f.Fixable {
+				item.Fixable++
+			}
+		}
+		if item.Count > 0 {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ==================== 内置规则 ====================
+
+// IoutilRule io/ioutil 自 Go 1.16 起废弃（修复：IoutilFix）
+type IoutilRule struct{}
+
+func (r *IoutilRule) ID() string    { return "M101" }
+func (r *IoutilRule) Name() string  { return "Deprecated io/ioutil" }
+func (r *IoutilRule) Since() string { return "go1.16" }
+func (r *IoutilRule) Description() string {
+	return "io/ioutil 已废弃，其中的函数都有 os/io 中的等价实现"
+}
+func (r *IoutilRule) Suggestion() string {
+	return "ioutil.ReadFile → os.ReadFile，ioutil.ReadAll → io.ReadAll，ioutil.TempDir → os.MkdirTemp，ioutil.ReadDir → os.ReadDir（返回 fs.DirEntry）"
+}
+
+func (r *IoutilRule) Check(fctx *FixContext) []ModernizeMatch {
+	name := fileImportName(fctx.File, "io/ioutil")
+	if name == "" || name == "_" || name == "." {
+		return nil
+	}
+	var matches []ModernizeMatch
+	ast.Inspect(fctx.File, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == name {
+			_, fixable := ioutilReplacements[sel.Sel.Name]
+			matches = append(matches, ModernizeMatch{
+				Node:    sel,
+				Message: i18n.Text("rule.M101.message", "ioutil.%s 已废弃", sel.Sel.Name),
+				Fixable: fixable,
+			})
+		}
+		return true
+	})
+	return matches
+}
+
+// EmptyInterfaceRule interface{} 可以写成 any（修复：AnyFix）
+type EmptyInterfaceRule struct{}
+
+func (r *EmptyInterfaceRule) ID() string    { return "M102" }
+func (r *EmptyInterfaceRule) Name() string  { return "Empty Interface" }
+func (r *EmptyInterfaceRule) Since() string { return "go1.18" }
+func (r *EmptyInterfaceRule) Description() string {
+	return "interface{} 可以写成预声明的别名 any"
+}
+func (r *EmptyInterfaceRule) Suggestion() string {
+	return "func Print(v interface{}) → func Print(v any)"
+}
+
+func (r *EmptyInterfaceRule) Check(fctx *FixContext) []ModernizeMatch {
+	fixable := !anyShadowed(fctx.File)
+	var matches []ModernizeMatch
+	for _, iface := range emptyInterfaces(fctx.File) {
+		matches = append(matches, ModernizeMatch{Node: iface, Fixable: fixable})
+	}
+	return matches
+}
+
+// emptyInterfaces 返回文件中所有的 interface{} 类型字面量
+func emptyInterfaces(file *ast.File) []*ast.InterfaceType {
+	var found []*ast.InterfaceType
+	ast.Inspect(file, func(n ast.Node) bool {
+		if iface, ok := n.(*ast.InterfaceType); ok && len(iface.Methods.List) == 0 {
+			found = append(found, iface)
+		}
+		return true
+	})
+	return found
+}
+
+// anyShadowed 文件中是否把 any 声明成了别的东西（这时不能把 interface{} 替换为 any）
+// 解析器只为文件内声明的标识符设置 Obj，预声明的 any 没有 Obj
+func anyShadowed(file *ast.File) bool {
+	shadowed := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && ident.Name == "any" && ident.Obj != nil {
+			shadowed = true
+		}
+		return !shadowed
+	})
+	return shadowed
+}
+
+// ErrorsJoinRule 手动合并多个错误，可以改用 errors.Join
+type ErrorsJoinRule struct{}
+
+func (r *ErrorsJoinRule) ID() string    { return "M103" }
+func (r *ErrorsJoinRule) Name() string  { return "Manual Error Join" }
+func (r *ErrorsJoinRule) Since() string { return "go1.20" }
+func (r *ErrorsJoinRule) Description() string {
+	return "手动拼接多个错误（或使用第三方 multierror 库），可以改用标准库 errors.Join，errors.Is/As 能匹配其中每个错误"
+}
+func (r *ErrorsJoinRule) Suggestion() string {
+	return "fmt.Errorf(\"%v; %v\", err1, err2) → errors.Join(err1, err2)\nmultierror.Append(err, e) → errors.Join(err, e)"
+}
+
+// multiErrorPackages 可以被 errors.Join 替代的第三方库
+var multiErrorPackages = map[string]bool{
+	"github.com/hashicorp/go-multierror": true,
+	"go.uber.org/multierr":               true,
+}
+
+// errorListFormat 只由格式化动词和分隔符组成的格式串，例如 "%v; %v"、"%w\n%w"
+var errorListFormat = regexp.MustCompile(`^%[vws](\s*(;|,|\||\\n)?\s*%[vws])+$`)
+
+func (r *ErrorsJoinRule) Check(fctx *FixContext) []ModernizeMatch {
+	var matches []ModernizeMatch
+	for _, imp := range fctx.File.Imports {
+		if path, err := strconv.Unquote(imp.Path.Value); err == nil && multiErrorPackages[path] {
+			matches = append(matches, ModernizeMatch{Node: imp, Message: i18n.Text("rule.M103.message_package", "%s 可以用 errors.Join 替代", path)})
+		}
+	}
+
+	fmtName := fileImportName(fctx.File, "fmt")
+	if fmtName == "" {
+		return matches
+	}
+	ast.Inspect(fctx.File, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) < 3 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Errorf" {
+			return true
+		}
+		if ident, ok := sel.X.(*ast.Ident); !ok || ident.Name != fmtName {
+			return true
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		format := strings.Trim(lit.Value, "\"`")
+		if !errorListFormat.MatchString(format) {
+			return true
+		}
+		for _, arg := range call.Args[1:] {
+			if !looksLikeError(arg) {
+				return true
+			}
+		}
+		matches = append(matches, ModernizeMatch{Node: call, Message: i18n.Text("rule.M103.message_format", "只用分隔符拼接多个错误，可以改用 errors.Join")})
+		return true
+	})
+	return matches
+}
+
+// looksLikeError 按命名约定判断表达式是否是 error（err、errX、xxxErr）
+func looksLikeError(expr ast.Expr) bool {
+	var name string
+	switch e := expr.(type) {
+	case *ast.Ident:
+		name = e.Name
+	case *ast.SelectorExpr:
+		name = e.Sel.Name
+	default:
+		return false
+	}
+	lower := strings.ToLower(name)
+	return strings.HasPrefix(lower, "err") || strings.HasSuffix(lower, "err")
+}
+
+// RandSeedRule math/rand.Seed 自 Go 1.20 起废弃
+type RandSeedRule struct{}
+
+func (r *RandSeedRule) ID() string    { return "M104" }
+func (r *RandSeedRule) Name() string  { return "Deprecated rand.Seed" }
+func (r *RandSeedRule) Since() string { return "go1.20" }
+func (r *RandSeedRule) Description() string {
+	return "rand.Seed 已废弃：Go 1.20 起全局随机数源自动随机播种"
+}
+func (r *RandSeedRule) Suggestion() string {
+	return "删除 rand.Seed(time.Now().UnixNano())；需要可复现的序列时使用 rand.New(rand.NewSource(seed))"
+}
+
+func (r *RandSeedRule) Check(fctx *FixContext) []ModernizeMatch {
+	return pkgFuncCalls(fctx.File, "math/rand", "Seed")
+}
+
+// StringsTitleRule strings.Title 自 Go 1.18 起废弃
+type StringsTitleRule struct{}
+
+func (r *StringsTitleRule) ID() string    { return "M105" }
+func (r *StringsTitleRule) Name() string  { return "Deprecated strings.Title" }
+func (r *StringsTitleRule) Since() string { return "go1.18" }
+func (r *StringsTitleRule) Description() string {
+	return "strings.Title 已废弃：不能正确处理 Unicode 标点和词边界"
+}
+func (r *StringsTitleRule) Suggestion() string {
+	return "使用 golang.org/x/text/cases：cases.Title(language.English).String(s)"
+}
+
+func (r *StringsTitleRule) Check(fctx *FixContext) []ModernizeMatch {
+	return pkgFuncCalls(fctx.File, "strings", "Title")
+}
+
+// ReflectPtrRule reflect.PtrTo / reflect.Ptr 已改名为 PointerTo / Pointer（修复：ReflectPointerFix）
+type ReflectPtrRule struct{}
+
+func (r *ReflectPtrRule) ID() string    { return "M106" }
+func (r *ReflectPtrRule) Name() string  { return "Deprecated reflect.PtrTo" }
+func (r *ReflectPtrRule) Since() string { return "go1.18" }
+func (r *ReflectPtrRule) Description() string {
+	return "reflect.PtrTo 和 reflect.Ptr 是旧名称，Go 1.18 起改名为 reflect.PointerTo 和 reflect.Pointer"
+}
+func (r *ReflectPtrRule) Suggestion() string {
+	return "reflect.PtrTo(t) → reflect.PointerTo(t)，reflect.Ptr → reflect.Pointer"
+}
+
+func (r *ReflectPtrRule) Check(fctx *FixContext) []ModernizeMatch {
+	var matches []ModernizeMatch
+	for _, sel := range pkgSelectors(fctx.File, "reflect", reflectRenames) {
+		matches = append(matches, ModernizeMatch{
+			Node:    sel,
+			Message: i18n.Text("rule.M106.message", "reflect.%s 已改名为 reflect.%s", sel.Sel.Name, reflectRenames[sel.Sel.Name]),
+			Fixable: true,
+		})
+	}
+	return matches
+}
+
+// reflectRenames reflect 包中改名的标识符
+var reflectRenames = map[string]string{
+	"PtrTo": "PointerTo",
+	"Ptr":   "Pointer",
+}
+
+// pkgFuncCalls 返回对 pkg.name 的调用
+func pkgFuncCalls(file *ast.File, pkg, name string) []ModernizeMatch {
+	var matches []ModernizeMatch
+	for _, sel := range pkgSelectors(file, pkg, map[string]string{name: ""}) {
+		matches = append(matches, ModernizeMatch{Node: sel})
+	}
+	return matches
+}
+
+// pkgSelectors 返回文件中引用 pkg 包里 names 中标识符的选择器表达式
+func pkgSelectors(file *ast.File, pkg string, names map[string]string) []*ast.SelectorExpr {
+	pkgName := fileImportName(file, pkg)
+	if pkgName == "" || pkgName == "_" || pkgName == "." {
+		return nil
+	}
+	var found []*ast.SelectorExpr
+	ast.Inspect(file, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		// 包名被局部变量遮蔽时 Obj 不为空
+		if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == pkgName && ident.Obj == nil {
+			if _, ok := names[sel.Sel.Name]; ok {
+				found = append(found, sel)
+			}
+		}
+		return true
+	})
+	return found
+}
@@ -0,0 +1,151 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const modernizeCode = `package demo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"reflect"
+	"strings"
+	"time"
+)
+
+func Load(name string, v interface{}) (map[string]interface{}, error) {
+	rand.Seed(time.Now().UnixNano())
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = ioutil.ReadDir(".")
+	t := reflect.PtrTo(reflect.TypeOf(v))
+	_ = t.Kind() == reflect.Ptr
+	return map[string]interface{}{"title": strings.Title(string(data))}, nil
+}
+
+func Close(a, b interface{ Close() error }) error {
+	errA, errB := a.Close(), b.Close()
+	if errA != nil || errB != nil {
+		return fmt.Errorf("%v; %v", errA, errB)
+	}
+	return fmt.Errorf("close %s: %v", "a", errA)
+}
+`
+
+// writeModernizeModule 写入一个声明 go 版本为 goVersion 的模块，返回源文件路径
+func writeModernizeModule(t *testing.T, goVersion string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/demo\n\ngo "+goVersion+"\n"), 0644); err != nil {
+		t.Fatalf("写入测试文件失败: %v", err)
+	}
+	file := filepath.Join(dir, "demo.go")
+	if err := os.WriteFile(file, []byte(modernizeCode), 0644); err != nil {
+		t.Fatalf("写入测试文件失败: %v", err)
+	}
+	return file
+}
+
+// modernizeLines 按规则统计命中的行号
+func modernizeLines(result *ModernizeResult) map[string][]int {
+	lines := make(map[string][]int)
+	for _, f := range result.Findings {
+		lines[f.RuleID] = append(lines[f.RuleID], f.Line)
+	}
+	return lines
+}
+
+// 测试各条现代化规则
+func TestModernizeAnalyzer_Rules(t *testing.T) {
+	file := writeModernizeModule(t, "1.21")
+	result, err := NewModernizeAnalyzer().Execute(context.Background(), ModernizeRequest{Files: []string{file}})
+	if err != nil {
+		t.Fatalf("分析失败: %v", err)
+	}
+
+	want := map[string][]int{
+		"M101": {14, 18},
+		"M102": {12, 12, 21},
+		"M103": {27},
+		"M104": {13},
+		"M105": {21},
+		"M106": {19, 20},
+	}
+	if got := modernizeLines(result); !reflect.DeepEqual(got, want) {
+		t.Errorf("行号 = %v, want %v", got, want)
+	}
+
+	// ioutil.ReadDir 返回类型不同，不能自动修复
+	for _, f := range result.Findings {
+		if f.RuleID == "M101" && f.Line == 18 && f.Fixable {
+			t.Errorf("ioutil.ReadDir 不应标记为可修复")
+		}
+	}
+	if len(result.Checklist) != 6 || result.Checklist[0].RuleID != "M101" || result.Checklist[0].Fixable != 1 {
+		t.Errorf("清单错误: %+v", result.Checklist)
+	}
+}
+
+// 测试按 go.mod 版本过滤：新写法在目标版本不可用时不报告
+func TestModernizeAnalyzer_GoVersion(t *testing.T) {
+	file := writeModernizeModule(t, "1.17")
+	result, err := NewModernizeAnalyzer().Execute(context.Background(), ModernizeRequest{Files: []string{file}})
+	if err != nil {
+		t.Fatalf("分析失败: %v", err)
+	}
+	got := modernizeLines(result)
+	for _, id := range []string{"M102", "M103", "M104", "M105", "M106"} {
+		if len(got[id]) > 0 {
+			t.Errorf("go 1.17 下不应报告 %s: %v", id, got[id])
+		}
+	}
+	if len(got["M101"]) != 2 {
+		t.Errorf("go 1.17 下应该报告 M101: %v", got)
+	}
+
+	override, err := NewModernizeAnalyzer().Execute(context.Background(), ModernizeRequest{Files: []string{file}, GoVersion: "1.20", Rules: []string{"m103"}})
+	if err != nil {
+		t.Fatalf("分析失败: %v", err)
+	}
+	if got := modernizeLines(override); !reflect.DeepEqual(got, map[string][]int{"M103": {27}}) {
+		t.Errorf("指定版本和规则后行号 = %v", got)
+	}
+}
+
+// 测试现代化规则的确定性修复
+func TestCodeFixer_Modernize(t *testing.T) {
+	file := writeModernizeModule(t, "1.21")
+	fixer := NewCodeFixer(nil, NewNoopLogger())
+	result := runCodeFixer(t, fixer, FixRequest{Files: []string{file}, Fixes: []string{"M101", "M102", "M106"}, Write: true})
+	if result.TotalFixes != 6 {
+		t.Errorf("修复数 = %d, want 6: %+v", result.TotalFixes, result.Patches)
+	}
+
+	content, _ := os.ReadFile(file)
+	code := string(content)
+	for _, want := range []string{
+		"func Load(name string, v any) (map[string]any, error) {",
+		"data, err := os.ReadFile(name)",
+		"_, _ = ioutil.ReadDir(\".\")",
+		"t := reflect.PointerTo(reflect.TypeOf(v))",
+		"_ = t.Kind() == reflect.Pointer",
+		"return map[string]any{",
+		"func Close(a, b interface{ Close() error }) error {",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("修复结果缺少 %q:\n%s", want, code)
+		}
+	}
+	if !strings.Contains(code, "\"io/ioutil\"\n\t\"math/rand\"\n\t\"os\"") {
+		t.Errorf("import 调整错误:\n%s", code)
+	}
+}
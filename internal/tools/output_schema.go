@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// 结果的输出结构，由 --schema 选择
+const (
+	OutputSchemaV1 = "v1" // 兼容旧脚本的结构，已弃用
+	OutputSchemaV2 = "v2" // 问题额外带 fingerprint、context、cwe
+)
+
+// SchemaVersionV2 v2 结构下 BugResult、SecurityResult 的 schema_version
+const SchemaVersionV2 = 2
+
+// issueContextLines v2 中问题前后各带的代码行数
+const issueContextLines = 2
+
+// ruleCWE 规则对应的 CWE 编号，没有明确对应的规则不列出
+var ruleCWE = map[string]string{
+	"G101": "CWE-798", // 硬编码凭据
+	"G104": "CWE-200", // 敏感信息泄露
+	"G107": "CWE-319", // 明文传输
+	"G201": "CWE-89",  // SQL 注入
+	"G302": "CWE-732", // 文件权限过宽
+	"G401": "CWE-338", // 不安全的随机数
+	"G501": "CWE-327", // 弱加密算法
+	"B101": "CWE-252", // 未检查返回值
+	"B102": "CWE-772", // 资源未释放
+	"B103": "CWE-478", // switch 缺少 default
+	"B104": "CWE-476", // 空指针解引用
+	"B106": "CWE-563", // 赋值后未使用
+	"B110": "CWE-362", // 竞态
+	"B120": "CWE-772",
+	"B121": "CWE-252",
+	"B124": "CWE-772",
+	"B128": "CWE-401", // goroutine 泄漏
+	"B129": "CWE-704", // 未检查的类型断言
+	"B130": "CWE-772", // 资源未及时释放
+	"B131": "CWE-772", // Ticker 没有 Stop
+	"B132": "CWE-628", // 格式化参数不一致
+	"B133": "CWE-362", // 并发写 map
+	"B134": "CWE-667", // 锁被复制
+}
+
+// RuleCWE 返回规则对应的 CWE 编号，没有时为空
+func RuleCWE(ruleID string) string {
+	return ruleCWE[ruleID]
+}
+
+// ParseOutputSchema 校验 --schema 的值，为空时使用 v1
+func ParseOutputSchema(schema string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(schema)) {
+	case "", OutputSchemaV1:
+		return OutputSchemaV1, nil
+	case OutputSchemaV2:
+		return OutputSchemaV2, nil
+	}
+	return "", fmt.Errorf("%w: 未知的输出结构 %q（可选 v1、v2）", ErrInvalidInput, schema)
+}
+
+// outputSchemaKey 输出结构在 context 中的键
+type outputSchemaKey struct{}
+
+// WithOutputSchema 选择 bug_detector、security_scanner 结果的输出结构
+func WithOutputSchema(ctx context.Context, schema string) context.Context {
+	return context.WithValue(ctx, outputSchemaKey{}, schema)
+}
+
+// outputSchemaV2 context 中是否选择了 v2 结构，默认 v1
+func outputSchemaV2(ctx context.Context) bool {
+	schema, _ := ctx.Value(outputSchemaKey{}).(string)
+	return schema == OutputSchemaV2
+}
+
+// issueContext 问题所在行前后的代码，每行带行号
+func issueContext(lines []string, line int) string {
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	var sb strings.Builder
+	for i := max(1, line-issueContextLines); i <= min(len(lines), line+issueContextLines); i++ {
+		fmt.Fprintf(&sb, "%d: %s\n", i, strings.TrimRight(lines[i-1], "\r"))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// enrichBugIssuesV2 为 v2 结构补充指纹、上下文和 CWE
+func enrichBugIssuesV2(bugs []BugIssue, code string) {
+	lines := strings.Split(code, "\n")
+	for i := range bugs {
+		bugs[i].Fingerprint = FindingFingerprint(bugs[i].RuleID, bugs[i].File, bugs[i].CodeSnippet)
+		bugs[i].Context = issueContext(lines, bugs[i].Line)
+		bugs[i].CWE = RuleCWE(bugs[i].RuleID)
+	}
+}
+
+// enrichSecurityIssuesV2 为 v2 结构补充指纹、上下文和 CWE
+func enrichSecurityIssuesV2(issues []SecurityIssue, code string) {
+	lines := strings.Split(code, "\n")
+	for i := range issues {
+		issues[i].Fingerprint = FindingFingerprint(issues[i].RuleID, issues[i].File, issues[i].CodeSnippet)
+		issues[i].Context = issueContext(lines, issues[i].Line)
+		issues[i].CWE = RuleCWE(issues[i].RuleID)
+	}
+}
@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// 测试 --schema 的取值
+func TestParseOutputSchema(t *testing.T) {
+	for input, want := range map[string]string{"": OutputSchemaV1, "v1": OutputSchemaV1, "V2": OutputSchemaV2} {
+		if got, err := ParseOutputSchema(input); err != nil || got != want {
+			t.Errorf("ParseOutputSchema(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseOutputSchema("v3"); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("未知的结构应该返回 ErrInvalidInput，实际 %v", err)
+	}
+}
+
+// 测试 v1 保持原有结构，v2 增加指纹、上下文和 CWE
+func TestOutputSchema_V2Fields(t *testing.T) {
+	code := `package main
+
+import "math/rand"
+
+func main() {
+	n := rand.Intn(10)
+	_ = n
+}
+`
+	scanner := NewSecurityScanner()
+
+	v1, err := scanner.Execute(context.Background(), SecurityScanInput{Code: code, File: "main.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(v1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{`"fingerprint"`, `"context"`, `"cwe"`} {
+		if strings.Contains(string(data), field) {
+			t.Errorf("v1 结果不应包含 %s: %s", field, data)
+		}
+	}
+	if v1.SchemaVersion != SecurityResultSchemaVersion {
+		t.Errorf("v1 的 schema_version = %d", v1.SchemaVersion)
+	}
+
+	ctx := WithOutputSchema(context.Background(), OutputSchemaV2)
+	v2, err := scanner.Execute(ctx, SecurityScanInput{Code: code, File: "main.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v2.SchemaVersion != SchemaVersionV2 || len(v2.Issues) == 0 {
+		t.Fatalf("v2 结果错误: %+v", v2)
+	}
+	issue := v2.Issues[0]
+	if issue.Fingerprint != FindingFingerprint(issue.RuleID, issue.File, issue.CodeSnippet) {
+		t.Errorf("指纹应与统一问题视图一致: %s", issue.Fingerprint)
+	}
+	if issue.CWE != "CWE-338" {
+		t.Errorf("G401 的 CWE = %q, want CWE-338", issue.CWE)
+	}
+	if !strings.Contains(issue.Context, "6: \tn := rand.Intn(10)") || !strings.HasPrefix(issue.Context, "4: ") {
+		t.Errorf("上下文应包含前后两行并带行号: %q", issue.Context)
+	}
+
+	bugs, err := NewBugDetector().Execute(ctx, BugDetectorInput{Code: code})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bugs.SchemaVersion != SchemaVersionV2 {
+		t.Errorf("bug_detector v2 的 schema_version = %d", bugs.SchemaVersion)
+	}
+}
@@ -0,0 +1,120 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// PanicError 工具执行中发生的 panic，已被 ToolManager 恢复
+type PanicError struct {
+	Tool  string // 工具名
+	Value any    // panic 的值
+	Stack string // 发生 panic 时的调用栈
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("工具 %s 发生 panic: %v", e.Tool, e.Value)
+}
+
+// Unwrap 使 errors.Is(err, ErrToolPanic) 成立
+func (e *PanicError) Unwrap() error {
+	return ErrToolPanic
+}
+
+// safeValidate 调用 Validate，panic 转换为 *PanicError
+func safeValidate(tool Tool, input any) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Tool: tool.Name(), Value: v, Stack: string(debug.Stack())}
+		}
+	}()
+	return tool.Validate(input)
+}
+
+// safeRun 调用 Run，panic 转换为 *PanicError
+func safeRun(ctx context.Context, tool Tool, input any) (result string, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			result, err = "", &PanicError{Tool: tool.Name(), Value: v, Stack: string(debug.Stack())}
+		}
+	}()
+	return tool.Run(ctx, input)
+}
+
+// SetCrashReportDir 设置崩溃报告目录，工具发生 panic 时写入报告文件；为空表示不写
+func (tm *ToolManager) SetCrashReportDir(dir string) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.crashDir = dir
+}
+
+// panicResult 把 panic 转换为失败的执行结果，调用栈放在 Metadata 中
+func (tm *ToolManager) panicResult(ctx context.Context, perr *PanicError, input any, executionTime int64) *ToolResult {
+	result := NewToolResult(false, "", perr.Error(), executionTime)
+	result.Metadata["panic"] = true
+	result.Metadata["stack"] = perr.Stack
+	if ws := WorkspaceFromContext(ctx); ws != nil {
+		result.Metadata["run_id"] = ws.RunID
+	}
+	if tm.logger != nil {
+		tm.logger.Error("工具执行发生 panic", "tool", perr.Tool, "panic", perr.Value)
+	}
+
+	tm.mu.RLock()
+	dir := tm.crashDir
+	tm.mu.RUnlock()
+	if dir == "" {
+		return result
+	}
+	path, err := writeCrashReport(ctx, dir, perr, input)
+	if err != nil {
+		if tm.logger != nil {
+			tm.logger.Error("写入崩溃报告失败", "tool", perr.Tool, "error", err)
+		}
+		return result
+	}
+	result.Metadata["crash_report"] = path
+	result.Error += fmt.Sprintf("（崩溃报告: %s）", path)
+	return result
+}
+
+// writeCrashReport 写入崩溃报告，内容可直接附在 issue 中
+// 输入只记录摘要和哈希，不包含源代码原文
+func writeCrashReport(ctx context.Context, dir string, perr *PanicError, input any) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("创建崩溃报告目录失败: %w", err)
+	}
+
+	now := time.Now()
+	runID := now.Format("20060102-150405")
+	if ws := WorkspaceFromContext(ctx); ws != nil {
+		runID = ws.RunID
+	}
+	summary, hash := summarizeAuditInput(input)
+
+	var sb strings.Builder
+	sb.WriteString("# 崩溃报告\n\n")
+	sb.WriteString(fmt.Sprintf("- 工具: %s\n", perr.Tool))
+	sb.WriteString(fmt.Sprintf("- 时间: %s\n", now.Format(time.RFC3339)))
+	sb.WriteString(fmt.Sprintf("- 执行 ID: %s\n", runID))
+	if command := invocationFromContext(ctx); command != "" {
+		sb.WriteString(fmt.Sprintf("- 命令: %s\n", command))
+	}
+	sb.WriteString(fmt.Sprintf("- Go 版本: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH))
+	sb.WriteString(fmt.Sprintf("- 输入摘要: %s\n", summary))
+	sb.WriteString(fmt.Sprintf("- 输入 SHA-256: %s\n", hash))
+	sb.WriteString(fmt.Sprintf("\n## panic\n\n```\n%v\n```\n", perr.Value))
+	sb.WriteString(fmt.Sprintf("\n## 调用栈\n\n```\n%s```\n", perr.Stack))
+
+	path := filepath.Join(dir, fmt.Sprintf("crash-%s-%s.md", perr.Tool, runID))
+	if err := os.WriteFile(path, []byte(sb.String()), 0600); err != nil {
+		return "", fmt.Errorf("写入崩溃报告失败: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// panicValidateTool 在 Validate 中 panic 的工具
+type panicValidateTool struct {
+	*MockTool
+}
+
+func (t *panicValidateTool) Validate(input any) error {
+	panic("validate boom")
+}
+
+// 测试 Run 中的 panic 被转换为失败结果，并写入崩溃报告
+func TestToolManager_RunRecoversPanic(t *testing.T) {
+	tm := NewToolManager(NewNoopLogger())
+	dir := t.TempDir()
+	tm.SetCrashReportDir(dir)
+
+	calls := 0
+	tool := NewMockTool("panic_tool", func(ctx context.Context, input any) (string, error) {
+		calls++
+		var m map[string]int
+		m["x"] = 1
+		return "", nil
+	})
+	config := DefaultToolConfig("panic_tool")
+	config.MaxRetries = 2
+	tm.Register(tool, config)
+
+	result, err := tm.Run(context.Background(), "panic_tool", "input")
+	if err != nil {
+		t.Fatalf("panic 不应该作为错误返回: %v", err)
+	}
+	if result.Success {
+		t.Fatal("发生 panic 的执行应该失败")
+	}
+	if calls != 1 {
+		t.Errorf("panic 不应该重试，实际执行 %d 次", calls)
+	}
+	if !strings.Contains(result.Error, "panic_tool") {
+		t.Errorf("错误信息应该包含工具名: %s", result.Error)
+	}
+	stack, _ := result.Metadata["stack"].(string)
+	if !strings.Contains(stack, "panic_test.go") {
+		t.Errorf("Metadata 应该包含调用栈: %q", stack)
+	}
+
+	path, _ := result.Metadata["crash_report"].(string)
+	report, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("崩溃报告未写入: %v", err)
+	}
+	for _, want := range []string{"panic_tool", "assignment to entry in nil map", "调用栈"} {
+		if !strings.Contains(string(report), want) {
+			t.Errorf("崩溃报告缺少 %q", want)
+		}
+	}
+	if !strings.Contains(string(report), "<string 5 字节>") {
+		t.Error("崩溃报告应该只包含输入摘要")
+	}
+}
+
+// 测试 Validate 中的 panic 同样被恢复
+func TestToolManager_ValidatePanic(t *testing.T) {
+	tm := NewToolManager(NewNoopLogger())
+	tool := &panicValidateTool{MockTool: NewMockTool("panic_validate", nil)}
+	tm.Register(tool, DefaultToolConfig("panic_validate"))
+
+	result, err := tm.Run(context.Background(), "panic_validate", "input")
+	if err != nil || result.Success {
+		t.Fatalf("Validate panic 应该返回失败结果: %+v %v", result, err)
+	}
+	if result.Metadata["panic"] != true {
+		t.Error("Metadata 应该标记 panic")
+	}
+	if _, ok := result.Metadata["crash_report"]; ok {
+		t.Error("未设置崩溃报告目录时不应该写报告")
+	}
+}
+
+// 测试 PanicError 可以用 errors.Is 识别
+func TestPanicError(t *testing.T) {
+	_, err := safeRun(context.Background(), NewMockTool("p", func(ctx context.Context, input any) (string, error) {
+		panic(errors.New("boom"))
+	}), nil)
+	if !errors.Is(err, ErrToolPanic) || !IsToolError(err) {
+		t.Errorf("应该识别为 ErrToolPanic: %v", err)
+	}
+	var perr *PanicError
+	if !errors.As(err, &perr) || perr.Tool != "p" || perr.Stack == "" {
+		t.Errorf("PanicError 字段错误: %+v", perr)
+	}
+}
@@ -0,0 +1,387 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Pipeline 用户定义的流水线：按顺序执行一组工具，不需要改代码就能组合自己的审计流程
+type Pipeline struct {
+	Name  string         `yaml:"name" json:"name"`
+	Steps []PipelineStep `yaml:"steps" json:"steps"`
+}
+
+// PipelineStep 流水线中的一步
+type PipelineStep struct {
+	Name            string          `yaml:"name" json:"name"`                           // 步骤名，默认同工具名，后续步骤用它引用结果
+	Tool            string          `yaml:"tool" json:"tool"`                           // 执行的工具
+	Input           any             `yaml:"input" json:"input"`                         // 工具输入，字符串中的 ${var} 会被替换
+	Filter          *PipelineFilter `yaml:"filter" json:"filter,omitempty"`             // 过滤结果中的问题
+	Output          string          `yaml:"output" json:"output,omitempty"`             // 把（过滤后的）结果写入该文件
+	FailOn          string          `yaml:"fail_on" json:"fail_on,omitempty"`           // 存在不低于该严重程度的问题时流水线失败
+	ContinueOnError bool            `yaml:"continue_on_error" json:"continue_on_error"` // 本步失败时继续执行后续步骤
+}
+
+// PipelineFilter 过滤结果中的问题（结果顶层带 severity 或 rule_id 的对象列表，如 bugs、issues）
+type PipelineFilter struct {
+	MinSeverity  string   `yaml:"min_severity" json:"min_severity,omitempty"`   // 只保留不低于该严重程度的问题
+	Rules        []string `yaml:"rules" json:"rules,omitempty"`                 // 只保留这些规则的问题
+	ExcludeRules []string `yaml:"exclude_rules" json:"exclude_rules,omitempty"` // 去掉这些规则的问题
+}
+
+// PipelineStepResult 一步的执行结果
+type PipelineStepResult struct {
+	Name     string          `json:"name"`
+	Tool     string          `json:"tool"`
+	Success  bool            `json:"success"`
+	Error    string          `json:"error,omitempty"`
+	Skipped  bool            `json:"skipped,omitempty"` // 前面的步骤失败，本步没有执行
+	Result   json.RawMessage `json:"result,omitempty"`
+	Output   string          `json:"output,omitempty"` // 结果实际写入的路径
+	Duration int64           `json:"duration_ms"`
+}
+
+// PipelineResult 流水线执行结果
+type PipelineResult struct {
+	Name    string               `json:"name"`
+	Passed  bool                 `json:"passed"`
+	Steps   []PipelineStepResult `json:"steps"`
+	Summary string               `json:"summary"`
+}
+
+// pipelineVarPattern 输入中的变量引用：${dir}、${steps.<name>.result}
+var pipelineVarPattern = regexp.MustCompile(`\$\{([A-Za-z0-9_.-]+)\}`)
+
+// LoadPipeline 读取并校验 YAML 流水线定义
+func LoadPipeline(path string) (*Pipeline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取流水线定义失败: %w", err)
+	}
+	var p Pipeline
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("%w: 解析流水线定义失败: %v", ErrInvalidInput, err)
+	}
+	if p.Name == "" {
+		p.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// validate 检查步骤定义：工具名必填，步骤名不重复，只能引用前面的步骤，严重程度有效
+func (p *Pipeline) validate() error {
+	if len(p.Steps) == 0 {
+		return fmt.Errorf("%w: 流水线 %s 中没有步骤", ErrInvalidInput, p.Name)
+	}
+	seen := make(map[string]bool)
+	for i := range p.Steps {
+		step := &p.Steps[i]
+		if step.Tool == "" {
+			return fmt.Errorf("%w: 第 %d 步缺少 tool", ErrInvalidInput, i+1)
+		}
+		if step.Name == "" {
+			step.Name = step.Tool
+		}
+		if seen[step.Name] {
+			return fmt.Errorf("%w: 步骤名 %s 重复，请用 name 区分", ErrInvalidInput, step.Name)
+		}
+		for _, ref := range pipelineStepRefs(step.Input) {
+			if !seen[ref] {
+				return fmt.Errorf("%w: 步骤 %s 引用了不在它之前的步骤 %s", ErrInvalidInput, step.Name, ref)
+			}
+		}
+		seen[step.Name] = true
+
+		if step.FailOn != "" {
+			level, err := ParseSeverity(step.FailOn)
+			if err != nil {
+				return fmt.Errorf("步骤 %s 的 fail_on: %w", step.Name, err)
+			}
+			step.FailOn = level
+		}
+		if step.Filter != nil && step.Filter.MinSeverity != "" {
+			level, err := ParseSeverity(step.Filter.MinSeverity)
+			if err != nil {
+				return fmt.Errorf("步骤 %s 的 filter.min_severity: %w", step.Name, err)
+			}
+			step.Filter.MinSeverity = level
+		}
+	}
+	return nil
+}
+
+// pipelineStepRefs 输入中引用的步骤名
+func pipelineStepRefs(input any) []string {
+	var refs []string
+	walkPipelineStrings(input, func(s string) string {
+		for _, m := range pipelineVarPattern.FindAllStringSubmatch(s, -1) {
+			if name, ok := pipelineStepRef(m[1]); ok {
+				refs = append(refs, name)
+			}
+		}
+		return s
+	})
+	return refs
+}
+
+// pipelineStepRef 解析 steps.<name>.result 形式的变量
+func pipelineStepRef(v string) (string, bool) {
+	if !strings.HasPrefix(v, "steps.") || !strings.HasSuffix(v, ".result") {
+		return "", false
+	}
+	name := strings.TrimSuffix(strings.TrimPrefix(v, "steps."), ".result")
+	return name, name != ""
+}
+
+// walkPipelineStrings 对输入中的每个字符串调用 fn 并用返回值替换，返回新的输入
+func walkPipelineStrings(v any, fn func(string) string) any {
+	switch val := v.(type) {
+	case string:
+		return fn(val)
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			out[k] = walkPipelineStrings(item, fn)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = walkPipelineStrings(item, fn)
+		}
+		return out
+	}
+	return v
+}
+
+// RunPipeline 按顺序执行流水线的步骤
+// vars 为输入中可用的变量（如 dir），另外 ${steps.<name>.result} 替换为前面步骤（过滤后）的结果。
+// 某一步失败或触发 fail_on 时流水线失败，除非该步设置了 continue_on_error，否则后续步骤跳过
+func RunPipeline(ctx context.Context, tm *ToolManager, p *Pipeline, vars map[string]string) (*PipelineResult, error) {
+	result := &PipelineResult{Name: p.Name, Passed: true}
+	outputs := make(map[string]string)
+	stopped := false
+	for _, step := range p.Steps {
+		if stopped {
+			result.Steps = append(result.Steps, PipelineStepResult{Name: step.Name, Tool: step.Tool, Skipped: true})
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		stepResult := runPipelineStep(ctx, tm, step, vars, outputs)
+		if !stepResult.Success {
+			result.Passed = false
+			stopped = !step.ContinueOnError
+		}
+		result.Steps = append(result.Steps, stepResult)
+	}
+
+	var succeeded, failed, skipped int
+	for _, s := range result.Steps {
+		switch {
+		case s.Skipped:
+			skipped++
+		case s.Success:
+			succeeded++
+		default:
+			failed++
+		}
+	}
+	status := "通过"
+	if !result.Passed {
+		status = "未通过"
+	}
+	result.Summary = fmt.Sprintf("流水线 %s %s：%d 步成功，%d 步失败，%d 步跳过", p.Name, status, succeeded, failed, skipped)
+	return result, nil
+}
+
+// runPipelineStep 执行一步：替换变量、执行工具、过滤结果、写出结果、检查 fail_on
+func runPipelineStep(ctx context.Context, tm *ToolManager, step PipelineStep, vars, outputs map[string]string) PipelineStepResult {
+	stepResult := PipelineStepResult{Name: step.Name, Tool: step.Tool}
+	start := time.Now()
+	fail := func(err error) PipelineStepResult {
+		stepResult.Error = err.Error()
+		stepResult.Duration = time.Since(start).Milliseconds()
+		return stepResult
+	}
+
+	input := walkPipelineStrings(normalizeYAML(step.Input), func(s string) string {
+		return pipelineVarPattern.ReplaceAllStringFunc(s, func(ref string) string {
+			name := ref[2 : len(ref)-1]
+			if step, ok := pipelineStepRef(name); ok {
+				return outputs[step]
+			}
+			if v, ok := vars[name]; ok {
+				return v
+			}
+			return ref
+		})
+	})
+
+	toolResult, err := tm.Run(ctx, step.Tool, input)
+	if err != nil {
+		return fail(err)
+	}
+	if !toolResult.Success {
+		return fail(fmt.Errorf("%s", toolResult.Error))
+	}
+
+	out := toolResult.Result
+	if step.Filter != nil {
+		if out, err = filterPipelineResult(out, step.Filter); err != nil {
+			return fail(err)
+		}
+	}
+	outputs[step.Name] = out
+	if json.Valid([]byte(out)) {
+		stepResult.Result = json.RawMessage(out)
+	} else {
+		// 结果不是 JSON（如生成的文本）时作为字符串输出
+		quoted, _ := json.Marshal(out)
+		stepResult.Result = quoted
+	}
+
+	if step.Output != "" {
+		written, err := WriteOutputFile(ctx, step.Output, []byte(out), 0644)
+		if err != nil {
+			return fail(err)
+		}
+		stepResult.Output = written
+	}
+
+	if step.FailOn != "" {
+		severities, err := ResultSeverities(step.Tool, out)
+		if err != nil {
+			severities = pipelineSeverities(out)
+		}
+		if err := CheckFailOn(severities, step.FailOn); err != nil {
+			return fail(err)
+		}
+	}
+	stepResult.Success = true
+	stepResult.Duration = time.Since(start).Milliseconds()
+	return stepResult
+}
+
+// normalizeYAML 把 YAML 解码得到的 map[any]any 等转换为 JSON 兼容的类型
+func normalizeYAML(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			out[k] = normalizeYAML(item)
+		}
+		return out
+	case map[any]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			out[fmt.Sprint(k)] = normalizeYAML(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = normalizeYAML(item)
+		}
+		return out
+	}
+	return v
+}
+
+// isPipelineFinding 是否为问题对象（带 severity 或 rule_id）
+func isPipelineFinding(item any) (map[string]any, bool) {
+	obj, ok := item.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	_, hasSeverity := obj["severity"]
+	_, hasRule := obj["rule_id"]
+	return obj, hasSeverity || hasRule
+}
+
+// filterPipelineResult 过滤结果顶层的问题列表，同名的 total 字段随之更新
+func filterPipelineResult(result string, filter *PipelineFilter) (string, error) {
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(result), &obj); err != nil {
+		return "", fmt.Errorf("%w: 结果不是 JSON 对象，不能过滤", ErrInvalidInput)
+	}
+	for key, value := range obj {
+		list, ok := value.([]any)
+		if !ok || len(list) == 0 {
+			continue
+		}
+		if _, ok := isPipelineFinding(list[0]); !ok {
+			continue
+		}
+		kept := make([]any, 0, len(list))
+		for _, item := range list {
+			finding, ok := isPipelineFinding(item)
+			if !ok || filter.keep(finding) {
+				kept = append(kept, item)
+			}
+		}
+		obj[key] = kept
+		if _, ok := obj["total"].(float64); ok && key != "suppressed" {
+			obj["total"] = len(kept)
+		}
+	}
+	data, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("序列化过滤后的结果失败: %w", err)
+	}
+	return string(data), nil
+}
+
+// keep 问题是否保留
+func (f *PipelineFilter) keep(finding map[string]any) bool {
+	rule, _ := finding["rule_id"].(string)
+	if len(f.Rules) > 0 && !slices.Contains(f.Rules, rule) {
+		return false
+	}
+	if slices.Contains(f.ExcludeRules, rule) {
+		return false
+	}
+	if f.MinSeverity != "" {
+		severity, _ := finding["severity"].(string)
+		if severityIndex(severity) < severityIndex(f.MinSeverity) {
+			return false
+		}
+	}
+	return true
+}
+
+// pipelineSeverities 取出结果顶层问题列表中的严重程度，用于没有专门解析的工具
+func pipelineSeverities(result string) []string {
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(result), &obj); err != nil {
+		return nil
+	}
+	var severities []string
+	for key, value := range obj {
+		list, ok := value.([]any)
+		if !ok || key == "suppressed" {
+			continue
+		}
+		for _, item := range list {
+			if finding, ok := isPipelineFinding(item); ok {
+				if severity, ok := finding["severity"].(string); ok {
+					severities = append(severities, severity)
+				}
+			}
+		}
+	}
+	return severities
+}
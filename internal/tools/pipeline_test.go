@@ -0,0 +1,159 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// anyInputTool 接受任意输入的模拟工具，流水线步骤的输入是 YAML 解码后的任意值
+type anyInputTool struct {
+	*MockTool
+}
+
+func (t *anyInputTool) Validate(input any) error {
+	return nil
+}
+
+func newAnyInputTool(name string, run func(ctx context.Context, input any) (string, error)) *anyInputTool {
+	return &anyInputTool{MockTool: NewMockTool(name, run)}
+}
+
+func writePipeline(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "build-check.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// 测试流水线的变量替换、结果引用、过滤、输出和失败条件
+func TestRunPipeline(t *testing.T) {
+	tm := NewToolManager(NewNoopLogger())
+	findings := `{"total": 3, "issues": [
+		{"rule_id": "X1", "severity": "Critical"},
+		{"rule_id": "X2", "severity": "Medium"},
+		{"rule_id": "X3", "severity": "Low"}]}`
+	var echoed any
+	registerAll(t, tm,
+		newAnyInputTool("scan", func(ctx context.Context, input any) (string, error) {
+			if got := input.(map[string]any)["directory"]; got != "./src" {
+				t.Errorf("${dir} 应替换为 ./src, 实际 %v", got)
+			}
+			return findings, nil
+		}),
+		newAnyInputTool("echo", func(ctx context.Context, input any) (string, error) {
+			echoed = input
+			return "done", nil
+		}),
+		newAnyInputTool("broken", func(ctx context.Context, input any) (string, error) {
+			return "", errors.New("boom")
+		}),
+	)
+
+	out := filepath.Join(t.TempDir(), "reports", "scan.json")
+	p, err := LoadPipeline(writePipeline(t, `
+steps:
+  - tool: scan
+    input: {directory: "${dir}"}
+    filter: {min_severity: medium, exclude_rules: [X1]}
+    output: `+out+`
+    fail_on: High
+  - tool: echo
+    input: ["${steps.scan.result}", "${pkg}", "${unknown}"]
+`))
+	if err != nil {
+		t.Fatalf("加载流水线失败: %v", err)
+	}
+	if p.Name != "build-check" {
+		t.Errorf("未指定 name 时应使用文件名, 实际 %q", p.Name)
+	}
+
+	result, err := RunPipeline(context.Background(), tm, p, map[string]string{"dir": "./src", "pkg": "tools"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Passed || len(result.Steps) != 2 {
+		t.Fatalf("流水线应通过（过滤后没有 High 以上的问题）: %+v", result)
+	}
+
+	var filtered struct {
+		Total  int `json:"total"`
+		Issues []struct {
+			RuleID string `json:"rule_id"`
+		} `json:"issues"`
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("应写出结果文件: %v", err)
+	}
+	if err := json.Unmarshal(data, &filtered); err != nil {
+		t.Fatal(err)
+	}
+	if filtered.Total != 1 || len(filtered.Issues) != 1 || filtered.Issues[0].RuleID != "X2" {
+		t.Errorf("过滤后应只剩 X2: %s", data)
+	}
+
+	args, _ := echoed.([]any)
+	if len(args) != 3 || args[0] != string(data) || args[1] != "tools" || args[2] != "${unknown}" {
+		t.Errorf("echo 的输入替换错误: %v", echoed)
+	}
+
+	// 不过滤时 Critical 问题触发 fail_on，后续步骤跳过；continue_on_error 的步骤失败后继续
+	p, err = LoadPipeline(writePipeline(t, `
+name: strict
+steps:
+  - tool: broken
+    continue_on_error: true
+  - tool: scan
+    input: {directory: "${dir}"}
+    fail_on: High
+  - tool: echo
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err = RunPipeline(context.Background(), tm, p, map[string]string{"dir": "./src"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Passed {
+		t.Error("流水线应失败")
+	}
+	steps := result.Steps
+	if steps[0].Success || !strings.Contains(steps[0].Error, "boom") {
+		t.Errorf("broken 应失败: %+v", steps[0])
+	}
+	if steps[1].Success || steps[1].Skipped || !strings.Contains(steps[1].Error, "High") {
+		t.Errorf("scan 应因 fail_on 失败: %+v", steps[1])
+	}
+	if !steps[2].Skipped {
+		t.Errorf("echo 应被跳过: %+v", steps[2])
+	}
+	if !strings.Contains(result.Summary, "0 步成功，2 步失败，1 步跳过") {
+		t.Errorf("摘要错误: %s", result.Summary)
+	}
+}
+
+// 测试无效的流水线定义
+func TestLoadPipeline_Invalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"没有步骤":      "name: empty\n",
+		"缺少工具":      "steps:\n  - name: a\n",
+		"步骤名重复":     "steps:\n  - tool: a\n  - tool: a\n",
+		"引用后面的步骤":   "steps:\n  - tool: a\n    input: '${steps.b.result}'\n  - tool: b\n",
+		"引用不存在的步骤":  "steps:\n  - tool: a\n    input: {code: '${steps.missing.result}'}\n",
+		"严重程度无效":    "steps:\n  - tool: a\n    fail_on: urgent\n",
+		"过滤严重程度无效":  "steps:\n  - tool: a\n    filter: {min_severity: urgent}\n",
+		"YAML 格式错误": "steps: [",
+	} {
+		if _, err := LoadPipeline(writePipeline(t, content)); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("%s: 应该返回 ErrInvalidInput，实际 %v", name, err)
+		}
+	}
+}
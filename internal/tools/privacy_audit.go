@@ -0,0 +1,203 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"go-ai-study/internal/config"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// defaultSumDB 未配置 GOSUMDB 时 go 命令使用的校验和数据库
+const defaultSumDB = "sum.golang.org"
+
+// PrivacyAuditor 隐私审计
+// 列出当前配置下程序可能访问的所有外部网络地址；程序本身不包含任何遥测上报
+type PrivacyAuditor struct {
+	*TypedTool[PrivacyAuditRequest, *PrivacyAuditResult]
+	config *config.Config
+}
+
+// NewPrivacyAuditor 创建隐私审计工具
+func NewPrivacyAuditor(cfg *config.Config) *PrivacyAuditor {
+	pa := &PrivacyAuditor{
+		config: cfg,
+	}
+	pa.TypedTool = NewTypedTool[PrivacyAuditRequest, *PrivacyAuditResult](
+		"privacy_audit",
+		"列出当前配置可能访问的所有外部网络地址（LLM、Milvus、模块代理等）",
+		pa,
+	)
+	return pa
+}
+
+// PrivacyAuditRequest 隐私审计请求
+type PrivacyAuditRequest struct {
+	LocalOnly bool `json:"local_only,omitempty"` // 按只允许本机连接的要求检查
+}
+
+// PrivacyDestination 一个可能访问的网络地址
+type PrivacyDestination struct {
+	Name     string   `json:"name"`
+	Endpoint string   `json:"endpoint"`       // 配置的地址，为空表示已关闭
+	Host     string   `json:"host,omitempty"` // 主机名，"*" 表示不确定的任意主机
+	Local    bool     `json:"local"`          // 是否只连接本机
+	UsedBy   []string `json:"used_by"`        // 会访问该地址的命令
+	Note     string   `json:"note,omitempty"`
+}
+
+// PrivacyAuditResult 隐私审计结果
+type PrivacyAuditResult struct {
+	LocalOnly    bool                 `json:"local_only"`
+	Destinations []PrivacyDestination `json:"destinations"`
+	Remote       int                  `json:"remote"`               // 非本机地址数
+	Violations   []string             `json:"violations,omitempty"` // local-only 模式下不允许的地址
+	Summary      string               `json:"summary"`
+}
+
+// ValidateInput 验证输入
+func (pa *PrivacyAuditor) ValidateInput(req PrivacyAuditRequest) error {
+	return nil
+}
+
+// Execute 执行隐私审计
+func (pa *PrivacyAuditor) Execute(ctx context.Context, req PrivacyAuditRequest) (*PrivacyAuditResult, error) {
+	result := &PrivacyAuditResult{
+		LocalOnly:    req.LocalOnly,
+		Destinations: PrivacyDestinations(ctx, pa.config),
+	}
+	active := 0
+	for _, dest := range result.Destinations {
+		if dest.Endpoint == "" {
+			continue
+		}
+		active++
+		if dest.Local {
+			continue
+		}
+		result.Remote++
+		if req.LocalOnly {
+			result.Violations = append(result.Violations, fmt.Sprintf("%s: %s", dest.Name, dest.Endpoint))
+		}
+	}
+	result.Summary = fmt.Sprintf("共 %d 个网络地址，其中 %d 个不是本机；程序不包含遥测上报", active, result.Remote)
+
+	return result, nil
+}
+
+// PrivacyDestinations 列出当前配置下可能访问的网络地址
+func PrivacyDestinations(ctx context.Context, cfg *config.Config) []PrivacyDestination {
+	dests := []PrivacyDestination{
+		endpointDestination("Ollama（对话与向量模型）", cfg.OllamaEndpoint,
+			[]string{"scan", "search", "sync-docs", "explain-finding", "triage", "diagram", "fix", "doc-coverage --generate"},
+			fmt.Sprintf("对话模型 %s，向量模型 %s；发送代码片段和问题", cfg.ChatModel, cfg.EmbeddingModel)),
+		endpointDestination("Milvus（代码索引）", cfg.MilvusEndpoint,
+			[]string{"scan", "search", "index", "sync-docs", "explain-finding"},
+			"存储代码片段、摘要和向量"),
+	}
+
+	goCommands := []string{"inventory --updates", "binsize", "error_coverage（go list）"}
+	proxies := goEnvValue(ctx, "GOPROXY")
+	if proxies == "" {
+		proxies = defaultModuleProxy
+	}
+	for _, p := range strings.FieldsFunc(proxies, func(r rune) bool { return r == ',' || r == '|' }) {
+		switch p {
+		case "off":
+			dests = append(dests, PrivacyDestination{Name: "Go 模块代理", UsedBy: goCommands, Local: true, Note: "GOPROXY=off，不会下载模块"})
+		case "direct":
+			dests = append(dests, PrivacyDestination{Name: "模块源站（GOPROXY=direct）", Endpoint: "direct", Host: "*", UsedBy: goCommands,
+				Note: "本地模块缓存缺失时直接从代码托管站点拉取"})
+		default:
+			dests = append(dests, endpointDestination("Go 模块代理", p, goCommands, "inventory --updates 查询最新版本；模块缓存缺失时下载依赖"))
+		}
+	}
+
+	sumdb := goEnvValue(ctx, "GOSUMDB")
+	if sumdb == "" {
+		sumdb = defaultSumDB
+	}
+	if sumdb == "off" {
+		dests = append(dests, PrivacyDestination{Name: "Go 校验和数据库", UsedBy: goCommands, Local: true, Note: "GOSUMDB=off"})
+	} else {
+		// GOSUMDB 可以是 "名称+公钥 URL"，只取地址部分
+		fields := strings.Fields(sumdb)
+		endpoint := fields[len(fields)-1]
+		if len(fields) == 1 {
+			endpoint = strings.SplitN(endpoint, "+", 2)[0]
+		}
+		dests = append(dests, endpointDestination("Go 校验和数据库", endpoint, goCommands, "下载新模块时校验 go.sum"))
+	}
+
+	for _, repo := range cfg.ProjectDocs.Repos {
+		dests = append(dests, endpointDestination(fmt.Sprintf("代码托管平台 API（%s）", repo.Repo), ProjectRepoAPI(repo),
+			[]string{"sync-docs"}, "读取 issue 和 PR 的标题与描述；只发送查询请求和访问令牌，不发送代码"))
+	}
+	return dests
+}
+
+// endpointDestination 根据配置的地址生成网络地址记录
+func endpointDestination(name, endpoint string, usedBy []string, note string) PrivacyDestination {
+	host := EndpointHost(endpoint)
+	return PrivacyDestination{
+		Name:     name,
+		Endpoint: endpoint,
+		Host:     host,
+		Local:    endpoint != "" && IsLocalHost(host),
+		UsedBy:   usedBy,
+		Note:     note,
+	}
+}
+
+// EndpointHost 从 "http://host:port"、"host:port" 或 "host" 形式的地址中取出主机名
+func EndpointHost(endpoint string) string {
+	if strings.Contains(endpoint, "://") {
+		if u, err := url.Parse(endpoint); err == nil {
+			return u.Hostname()
+		}
+	}
+	if host, _, err := net.SplitHostPort(endpoint); err == nil {
+		return host
+	}
+	return strings.SplitN(endpoint, "/", 2)[0]
+}
+
+// IsLocalHost 判断主机是否为本机（localhost 或回环地址）
+func IsLocalHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// CheckLocalOnly 检查配置的服务地址是否都在本机，否则返回错误
+// Go 模块代理和校验和数据库由调用方通过 GOPROXY=off / GOSUMDB=off 关闭
+func CheckLocalOnly(cfg *config.Config) error {
+	var remote []string
+	for _, dest := range []PrivacyDestination{
+		endpointDestination("ollama_endpoint", cfg.OllamaEndpoint, nil, ""),
+		endpointDestination("milvus_endpoint", cfg.MilvusEndpoint, nil, ""),
+	} {
+		if dest.Endpoint != "" && !dest.Local {
+			remote = append(remote, fmt.Sprintf("%s=%s", dest.Name, dest.Endpoint))
+		}
+	}
+	if len(remote) > 0 {
+		return fmt.Errorf("--local-only 模式下不允许连接非本机地址: %s", strings.Join(remote, ", "))
+	}
+	return nil
+}
+
+// goEnvValue 读取 go env 中的配置（包括 go env -w 写入的值），go 命令不可用时退回环境变量
+func goEnvValue(ctx context.Context, key string) string {
+	out, err := exec.CommandContext(ctx, "go", "env", key).Output()
+	if err != nil {
+		return os.Getenv(key)
+	}
+	return strings.TrimSpace(string(out))
+}
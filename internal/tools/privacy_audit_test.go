@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"go-ai-study/internal/config"
+	"testing"
+)
+
+// 测试本机地址判断
+func TestIsLocalHost(t *testing.T) {
+	tests := map[string]bool{
+		"localhost":        true,
+		"LOCALHOST.":       true,
+		"milvus.localhost": true,
+		"127.0.0.1":        true,
+		"127.1.2.3":        true,
+		"::1":              true,
+		"[::1]":            true,
+		"10.0.0.5":         false,
+		"api.openai.com":   false,
+		"":                 false,
+	}
+	for host, want := range tests {
+		if got := IsLocalHost(host); got != want {
+			t.Errorf("IsLocalHost(%q) = %v，期望 %v", host, got, want)
+		}
+	}
+}
+
+// 测试从不同形式的地址中取出主机名
+func TestEndpointHost(t *testing.T) {
+	tests := map[string]string{
+		"http://localhost:11434":    "localhost",
+		"https://proxy.golang.org/": "proxy.golang.org",
+		"localhost:19530":           "localhost",
+		"[::1]:19530":               "::1",
+		"sum.golang.org":            "sum.golang.org",
+	}
+	for endpoint, want := range tests {
+		if got := EndpointHost(endpoint); got != want {
+			t.Errorf("EndpointHost(%q) = %q，期望 %q", endpoint, got, want)
+		}
+	}
+}
+
+// 测试 local-only 检查配置的服务地址
+func TestCheckLocalOnly(t *testing.T) {
+	cfg := &config.Config{OllamaEndpoint: "http://localhost:11434", MilvusEndpoint: "127.0.0.1:19530"}
+	if err := CheckLocalOnly(cfg); err != nil {
+		t.Errorf("本机地址不应报错: %v", err)
+	}
+	cfg.MilvusEndpoint = "http://milvus.internal:19530"
+	if err := CheckLocalOnly(cfg); err == nil {
+		t.Error("非本机地址应该报错")
+	}
+}
+
+// 测试隐私审计列出网络地址并标出 local-only 违规
+func TestPrivacyAuditor_Run(t *testing.T) {
+	t.Setenv("GOPROXY", "https://goproxy.example.com,direct")
+	t.Setenv("GOSUMDB", "off")
+	cfg := &config.Config{OllamaEndpoint: "http://10.0.0.5:11434", MilvusEndpoint: "http://localhost:19530"}
+
+	output, err := NewPrivacyAuditor(cfg).Run(context.Background(), PrivacyAuditRequest{LocalOnly: true})
+	if err != nil {
+		t.Fatalf("隐私审计失败: %v", err)
+	}
+	var result PrivacyAuditResult
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("解析结果失败: %v", err)
+	}
+
+	// Ollama、Milvus、模块代理、direct、校验和数据库（已关闭）
+	if len(result.Destinations) != 5 {
+		t.Fatalf("应该列出 5 个网络地址，实际 %d: %+v", len(result.Destinations), result.Destinations)
+	}
+	if result.Remote != 3 {
+		t.Errorf("应该有 3 个非本机地址（Ollama、模块代理、direct），实际 %d", result.Remote)
+	}
+	if len(result.Violations) != 3 {
+		t.Errorf("local-only 模式下应该有 3 个违规，实际 %v", result.Violations)
+	}
+	if result.Destinations[2].Endpoint != "https://goproxy.example.com" {
+		t.Errorf("模块代理地址错误: %+v", result.Destinations[2])
+	}
+	if result.Destinations[4].Endpoint != "" {
+		t.Errorf("GOSUMDB=off 时校验和数据库应标记为关闭: %+v", result.Destinations[4])
+	}
+}
+
+// 测试配置了 issue/PR 同步的仓库时列出对应的 API 地址
+func TestPrivacyDestinationsProjectDocs(t *testing.T) {
+	t.Setenv("GOPROXY", "off")
+	t.Setenv("GOSUMDB", "off")
+	cfg := &config.Config{ProjectDocs: config.ProjectDocsConfig{Repos: []config.ProjectRepo{
+		{Repo: "org/app"},
+		{Provider: "gitlab", Repo: "group/app", BaseURL: "http://localhost:8929"},
+	}}}
+
+	dests := PrivacyDestinations(context.Background(), cfg)
+	github, gitlab := dests[len(dests)-2], dests[len(dests)-1]
+	if github.Endpoint != "https://api.github.com" || github.Local {
+		t.Errorf("GitHub 仓库应该列出公共 API 地址: %+v", github)
+	}
+	if gitlab.Endpoint != "http://localhost:8929" || !gitlab.Local {
+		t.Errorf("本机 GitLab 应该标记为本机: %+v", gitlab)
+	}
+}
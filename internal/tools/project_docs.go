@@ -0,0 +1,273 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-ai-study/internal/ai"
+	"go-ai-study/internal/config"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// issue/PR 同步的默认值
+const (
+	DefaultProjectDocsLimit    = 500       // 每个仓库每次最多同步的 issue/PR 数
+	DefaultProjectDocsInterval = time.Hour // sync-docs --watch 的默认同步周期
+	projectDocsPageSize        = 100       // 每页请求的条数（GitHub、GitLab 的上限都是 100）
+)
+
+// 支持的代码托管平台
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// projectRepoPattern 仓库路径：GitHub 为 owner/name，GitLab 可以有多级分组
+// 同时保证路径可以直接写进 Milvus 过滤表达式
+var projectRepoPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+(/[A-Za-z0-9_.-]+)+$`)
+
+// ValidateProjectRepo 检查同步仓库的配置
+func ValidateProjectRepo(repo config.ProjectRepo) error {
+	provider := projectRepoProvider(repo)
+	if provider != ProviderGitHub && provider != ProviderGitLab {
+		return fmt.Errorf("%w: 不支持的平台 %q（可选 github、gitlab）", ErrInvalidInput, repo.Provider)
+	}
+	if !projectRepoPattern.MatchString(repo.Repo) {
+		return fmt.Errorf("%w: 仓库路径无效 %q", ErrInvalidInput, repo.Repo)
+	}
+	if provider == ProviderGitHub && strings.Count(repo.Repo, "/") != 1 {
+		return fmt.Errorf("%w: GitHub 仓库应为 owner/name: %q", ErrInvalidInput, repo.Repo)
+	}
+	return nil
+}
+
+// projectRepoProvider 仓库所在的平台，未配置时为 github
+func projectRepoProvider(repo config.ProjectRepo) string {
+	if repo.Provider == "" {
+		return ProviderGitHub
+	}
+	return strings.ToLower(repo.Provider)
+}
+
+// ProjectRepoAPI 仓库所在平台的 API 地址（未配置时使用公共实例）
+func ProjectRepoAPI(repo config.ProjectRepo) string {
+	if repo.BaseURL != "" {
+		return strings.TrimRight(repo.BaseURL, "/")
+	}
+	if projectRepoProvider(repo) == ProviderGitLab {
+		return "https://gitlab.com"
+	}
+	return "https://api.github.com"
+}
+
+// projectRepoToken 从环境变量读取访问令牌，没有时匿名访问（只能读取公开仓库，且限流更严格）
+func projectRepoToken(repo config.ProjectRepo) string {
+	env := repo.TokenEnv
+	if env == "" {
+		env = "GITHUB_TOKEN"
+		if projectRepoProvider(repo) == ProviderGitLab {
+			env = "GITLAB_TOKEN"
+		}
+	}
+	return os.Getenv(env)
+}
+
+// FetchProjectDocs 拉取仓库中 since（Unix 秒，0 表示不限）之后更新过的 issue 和 PR，按更新时间从新到旧最多 limit 条
+// 只取标题和描述，不取评论
+func FetchProjectDocs(ctx context.Context, client *http.Client, repo config.ProjectRepo, since int64, limit int) ([]ai.ProjectDoc, error) {
+	if err := ValidateProjectRepo(repo); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultProjectDocsLimit
+	}
+
+	var docs []ai.ProjectDoc
+	var err error
+	if projectRepoProvider(repo) == ProviderGitLab {
+		docs, err = fetchGitLabDocs(ctx, client, repo, since, limit)
+	} else {
+		docs, err = fetchGitHubDocs(ctx, client, repo, since, limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool { return docs[i].Updated > docs[j].Updated })
+	if len(docs) > limit {
+		docs = docs[:limit]
+	}
+	return docs, nil
+}
+
+// githubIssue GitHub issues 接口的返回项（PR 也在其中，带 pull_request 字段）
+type githubIssue struct {
+	Number      int64     `json:"number"`
+	Title       string    `json:"title"`
+	Body        string    `json:"body"`
+	State       string    `json:"state"`
+	HTMLURL     string    `json:"html_url"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	PullRequest *struct{} `json:"pull_request"`
+	User        struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+// fetchGitHubDocs 分页读取 GitHub 的 issues 接口
+func fetchGitHubDocs(ctx context.Context, client *http.Client, repo config.ProjectRepo, since int64, limit int) ([]ai.ProjectDoc, error) {
+	query := url.Values{"state": {"all"}, "sort": {"updated"}, "direction": {"desc"}}
+	if since > 0 {
+		query.Set("since", time.Unix(since, 0).UTC().Format(time.RFC3339))
+	}
+	header := http.Header{
+		"Accept":               {"application/vnd.github+json"},
+		"X-GitHub-Api-Version": {"2022-11-28"},
+	}
+	if token := projectRepoToken(repo); token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+
+	endpoint := fmt.Sprintf("%s/repos/%s/issues", ProjectRepoAPI(repo), repo.Repo)
+	var docs []ai.ProjectDoc
+	err := fetchPages(ctx, client, endpoint, query, header, limit, func(body []byte) (int, error) {
+		var items []githubIssue
+		if err := json.Unmarshal(body, &items); err != nil {
+			return 0, err
+		}
+		for _, item := range items {
+			doc := ai.ProjectDoc{
+				Repo:    repo.Repo,
+				Kind:    ai.ProjectDocIssue,
+				Number:  item.Number,
+				Title:   item.Title,
+				State:   item.State,
+				Author:  item.User.Login,
+				URL:     item.HTMLURL,
+				Updated: item.UpdatedAt.Unix(),
+				Body:    item.Body,
+			}
+			if item.PullRequest != nil {
+				doc.Kind = ai.ProjectDocPullRequest
+			}
+			for _, label := range item.Labels {
+				doc.Labels = append(doc.Labels, label.Name)
+			}
+			docs = append(docs, doc)
+		}
+		return len(items), nil
+	})
+	return docs, err
+}
+
+// gitlabItem GitLab issues / merge_requests 接口的返回项
+type gitlabItem struct {
+	IID         int64     `json:"iid"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	State       string    `json:"state"`
+	WebURL      string    `json:"web_url"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Labels      []string  `json:"labels"`
+	Author      struct {
+		Username string `json:"username"`
+	} `json:"author"`
+}
+
+// fetchGitLabDocs 分页读取 GitLab 的 issues 和 merge_requests 接口
+func fetchGitLabDocs(ctx context.Context, client *http.Client, repo config.ProjectRepo, since int64, limit int) ([]ai.ProjectDoc, error) {
+	query := url.Values{"scope": {"all"}, "state": {"all"}, "order_by": {"updated_at"}, "sort": {"desc"}}
+	if since > 0 {
+		query.Set("updated_after", time.Unix(since, 0).UTC().Format(time.RFC3339))
+	}
+	header := http.Header{}
+	if token := projectRepoToken(repo); token != "" {
+		header.Set("PRIVATE-TOKEN", token)
+	}
+
+	project := fmt.Sprintf("%s/api/v4/projects/%s", ProjectRepoAPI(repo), url.PathEscape(repo.Repo))
+	var docs []ai.ProjectDoc
+	for _, source := range []struct{ path, kind string }{
+		{"/issues", ai.ProjectDocIssue},
+		{"/merge_requests", ai.ProjectDocPullRequest},
+	} {
+		err := fetchPages(ctx, client, project+source.path, query, header, limit, func(body []byte) (int, error) {
+			var items []gitlabItem
+			if err := json.Unmarshal(body, &items); err != nil {
+				return 0, err
+			}
+			for _, item := range items {
+				docs = append(docs, ai.ProjectDoc{
+					Repo:    repo.Repo,
+					Kind:    source.kind,
+					Number:  item.IID,
+					Title:   item.Title,
+					State:   item.State,
+					Author:  item.Author.Username,
+					URL:     item.WebURL,
+					Labels:  item.Labels,
+					Updated: item.UpdatedAt.Unix(),
+					Body:    item.Description,
+				})
+			}
+			return len(items), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return docs, nil
+}
+
+// fetchPages 按页请求列表接口，直到某一页不满或累计达到 limit 条
+// parse 解析一页的响应体并返回该页的条数
+func fetchPages(ctx context.Context, client *http.Client, endpoint string, query url.Values, header http.Header, limit int, parse func([]byte) (int, error)) error {
+	query.Set("per_page", strconv.Itoa(projectDocsPageSize))
+	total := 0
+	for page := 1; total < limit; page++ {
+		query.Set("page", strconv.Itoa(page))
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+query.Encode(), nil)
+		if err != nil {
+			return fmt.Errorf("创建请求失败: %w", err)
+		}
+		req.Header = header.Clone()
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("请求 %s 失败: %w", endpoint, err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("读取 %s 响应失败: %w", endpoint, err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			// 错误响应（限流、无权限等）只保留开头，足够说明原因
+			message := strings.TrimSpace(string(body))
+			if len(message) > 200 {
+				message = strings.ToValidUTF8(message[:200], "") + "..."
+			}
+			return fmt.Errorf("%s 返回 %s: %s", endpoint, resp.Status, message)
+		}
+
+		n, err := parse(body)
+		if err != nil {
+			return fmt.Errorf("解析 %s 响应失败: %w", endpoint, err)
+		}
+		total += n
+		if n < projectDocsPageSize {
+			return nil
+		}
+	}
+	return nil
+}
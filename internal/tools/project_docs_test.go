@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-ai-study/internal/ai"
+	"go-ai-study/internal/config"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// 测试从 GitHub 分页拉取 issue 和 PR：带令牌和 since 参数，PR 按 pull_request 字段区分
+func TestFetchProjectDocsGitHub(t *testing.T) {
+	t.Setenv("DOCS_TOKEN", "secret")
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/org/app/issues" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("应该带上令牌: %q", r.Header.Get("Authorization"))
+		}
+		if r.URL.Query().Get("since") != "2024-01-01T00:00:00Z" || r.URL.Query().Get("state") != "all" {
+			t.Errorf("查询参数不正确: %s", r.URL.RawQuery)
+		}
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		if page != "1" {
+			fmt.Fprint(w, `[{"number": 1, "title": "旧问题", "state": "closed", "updated_at": "2024-01-02T00:00:00Z", "user": {"login": "alice"}}]`)
+			return
+		}
+		// 第一页满 100 条才会请求下一页
+		items := make([]string, projectDocsPageSize)
+		for i := range items {
+			items[i] = fmt.Sprintf(`{"number": %d, "title": "t", "state": "open", "updated_at": "2024-02-01T00:00:00Z", "user": {"login": "bob"}}`, 1000+i)
+		}
+		items[0] = `{"number": 42, "title": "重试改为指数退避", "body": "固定间隔重试会压垮下游", "state": "closed",
+			"html_url": "https://github.com/org/app/pull/42", "updated_at": "2024-03-01T00:00:00Z",
+			"user": {"login": "carol"}, "labels": [{"name": "reliability"}], "pull_request": {"url": "x"}}`
+		fmt.Fprint(w, "["+strings.Join(items, ",")+"]")
+	}))
+	defer server.Close()
+
+	repo := config.ProjectRepo{Repo: "org/app", BaseURL: server.URL, TokenEnv: "DOCS_TOKEN"}
+	docs, err := FetchProjectDocs(context.Background(), server.Client(), repo, 1704067200, 0)
+	if err != nil {
+		t.Fatalf("拉取失败: %v", err)
+	}
+	if len(pages) != 2 || len(docs) != projectDocsPageSize+1 {
+		t.Fatalf("应该请求 2 页、拉取 %d 条，实际 %v 页、%d 条", projectDocsPageSize+1, pages, len(docs))
+	}
+	pr := docs[0]
+	if pr.Number != 42 || pr.Kind != ai.ProjectDocPullRequest || pr.Author != "carol" || len(pr.Labels) != 1 {
+		t.Errorf("最近更新的 PR 应该排在最前: %+v", pr)
+	}
+	if text := pr.Text(); !strings.Contains(text, "org/app#42") || !strings.Contains(text, "固定间隔重试") {
+		t.Errorf("文本缺少编号或描述:\n%s", text)
+	}
+	if last := docs[len(docs)-1]; last.Number != 1 || last.Kind != ai.ProjectDocIssue {
+		t.Errorf("没有 pull_request 字段的是 issue: %+v", last)
+	}
+
+	limited, err := FetchProjectDocs(context.Background(), server.Client(), repo, 1704067200, 10)
+	if err != nil || len(limited) != 10 {
+		t.Errorf("应该只保留 10 条: %d %v", len(limited), err)
+	}
+}
+
+// 测试从 GitLab 拉取 issue 和 MR：项目路径需要转义，MR 记为 pr
+func TestFetchProjectDocsGitLab(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/group%2Fsub%2Fapp/issues":
+			fmt.Fprint(w, `[{"iid": 3, "title": "超时", "description": "d", "state": "opened", "updated_at": "2024-01-01T00:00:00Z", "author": {"username": "dan"}, "labels": ["bug"]}]`)
+		case "/api/v4/projects/group%2Fsub%2Fapp/merge_requests":
+			fmt.Fprint(w, `[{"iid": 3, "title": "修复超时", "state": "merged", "updated_at": "2024-01-05T00:00:00Z", "author": {"username": "eve"}}]`)
+		default:
+			t.Errorf("未预期的请求: %s", r.URL.EscapedPath())
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	repo := config.ProjectRepo{Provider: "gitlab", Repo: "group/sub/app", BaseURL: server.URL}
+	docs, err := FetchProjectDocs(context.Background(), server.Client(), repo, 0, 0)
+	if err != nil {
+		t.Fatalf("拉取失败: %v", err)
+	}
+	if len(docs) != 2 || docs[0].Kind != ai.ProjectDocPullRequest || docs[1].Kind != ai.ProjectDocIssue {
+		t.Fatalf("应该拉取 1 个 MR 和 1 个 issue（按更新时间排序）: %+v", docs)
+	}
+}
+
+// 测试仓库配置校验和错误响应
+func TestFetchProjectDocsErrors(t *testing.T) {
+	for _, repo := range []config.ProjectRepo{
+		{Repo: "org/app' || true"},
+		{Repo: "group/sub/app"},
+		{Provider: "bitbucket", Repo: "org/app"},
+	} {
+		if err := ValidateProjectRepo(repo); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("%+v 应该校验失败: %v", repo, err)
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "API rate limit exceeded"}`, http.StatusForbidden)
+	}))
+	defer server.Close()
+	_, err := FetchProjectDocs(context.Background(), server.Client(), config.ProjectRepo{Repo: "org/app", BaseURL: server.URL}, 0, 0)
+	if err == nil || !strings.Contains(err.Error(), "rate limit") {
+		t.Errorf("错误响应应该带上原因: %v", err)
+	}
+}
@@ -0,0 +1,276 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EvalSet RAG 评测集：一组问题及其期望检索到的文件和回答中应出现的关键词
+// 修改切分方式、向量模型或对话模型前后各跑一次，比较命中率和关键词覆盖率
+type EvalSet struct {
+	TopK  int        `yaml:"top_k" json:"top_k"` // 每个问题检索的片段数，默认 5
+	Cases []EvalCase `yaml:"cases" json:"cases"`
+}
+
+// EvalCase 一个评测问题
+type EvalCase struct {
+	Question         string   `yaml:"question" json:"question"`
+	ExpectedSources  []string `yaml:"expected_sources" json:"expected_sources,omitempty"`   // 期望检索到的文件，按路径后缀匹配（如 internal/ai/scanner.go）
+	ExpectedKeywords []string `yaml:"expected_keywords" json:"expected_keywords,omitempty"` // 回答中应出现的关键词，不区分大小写
+}
+
+// EvalCaseResult 单个问题的评测结果
+type EvalCaseResult struct {
+	Question        string   `json:"question"`
+	Retrieved       []string `json:"retrieved"`                 // 检索到的片段来源，按相似度排序
+	SourceHit       bool     `json:"source_hit"`                // 是否检索到任一期望的文件
+	HitRank         int      `json:"hit_rank,omitempty"`        // 第一个命中的名次（从 1 开始），未命中为 0
+	MissingSources  []string `json:"missing_sources,omitempty"` // 没有检索到的期望文件
+	Answer          string   `json:"answer,omitempty"`          // 模型的回答（--no-answer 时为空）
+	MatchedKeywords []string `json:"matched_keywords,omitempty"`
+	MissingKeywords []string `json:"missing_keywords,omitempty"`
+	KeywordCoverage float64  `json:"keyword_coverage"` // 回答中出现的关键词比例，没有期望关键词时为 0
+	Error           string   `json:"error,omitempty"`  // 检索或生成回答失败
+}
+
+// EvalReport 评测报告
+type EvalReport struct {
+	Cases            []EvalCaseResult `json:"cases"`
+	RetrievalCases   int              `json:"retrieval_cases"`    // 有期望文件的问题数
+	RetrievalHitRate float64          `json:"retrieval_hit_rate"` // 检索命中率
+	MRR              float64          `json:"mrr"`                // 平均倒数排名（Mean Reciprocal Rank）
+	AnswerCases      int              `json:"answer_cases"`       // 有期望关键词且生成了回答的问题数
+	KeywordCoverage  float64          `json:"keyword_coverage"`   // 平均关键词覆盖率
+	Summary          string           `json:"summary"`
+}
+
+// defaultEvalTopK 默认每个问题检索的片段数
+const defaultEvalTopK = 5
+
+// LoadEvalSet 读取并校验 YAML 评测集
+func LoadEvalSet(path string) (*EvalSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取评测集失败: %w", err)
+	}
+	var set EvalSet
+	if err := yaml.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("%w: 解析评测集失败: %v", ErrInvalidInput, err)
+	}
+	if set.TopK <= 0 {
+		set.TopK = defaultEvalTopK
+	}
+	if len(set.Cases) == 0 {
+		return nil, fmt.Errorf("%w: 评测集 %s 中没有问题", ErrInvalidInput, path)
+	}
+	for i, c := range set.Cases {
+		if strings.TrimSpace(c.Question) == "" {
+			return nil, fmt.Errorf("%w: 第 %d 个问题的 question 为空", ErrInvalidInput, i+1)
+		}
+		if len(c.ExpectedSources) == 0 && len(c.ExpectedKeywords) == 0 {
+			return nil, fmt.Errorf("%w: 问题 %q 至少需要 expected_sources 或 expected_keywords", ErrInvalidInput, c.Question)
+		}
+	}
+	return &set, nil
+}
+
+// ScoreRetrieval 按检索到的来源给问题打分
+func ScoreRetrieval(c EvalCase, retrieved []string) EvalCaseResult {
+	result := EvalCaseResult{Question: c.Question, Retrieved: retrieved}
+	for _, expected := range c.ExpectedSources {
+		rank := sourceRank(retrieved, expected)
+		if rank == 0 {
+			result.MissingSources = append(result.MissingSources, expected)
+			continue
+		}
+		if result.HitRank == 0 || rank < result.HitRank {
+			result.HitRank = rank
+		}
+	}
+	result.SourceHit = result.HitRank > 0
+	return result
+}
+
+// sourceRank 期望文件在检索结果中的名次（从 1 开始），按路径后缀匹配，未出现时为 0
+func sourceRank(retrieved []string, expected string) int {
+	expected = strings.TrimPrefix(filepath.ToSlash(expected), "./")
+	for i, source := range retrieved {
+		source = filepath.ToSlash(source)
+		if source == expected || strings.HasSuffix(source, "/"+expected) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// ScoreAnswer 统计回答中出现的期望关键词
+func ScoreAnswer(c EvalCase, answer string, result *EvalCaseResult) {
+	result.Answer = answer
+	lower := strings.ToLower(answer)
+	for _, kw := range c.ExpectedKeywords {
+		if strings.Contains(lower, strings.ToLower(kw)) {
+			result.MatchedKeywords = append(result.MatchedKeywords, kw)
+		} else {
+			result.MissingKeywords = append(result.MissingKeywords, kw)
+		}
+	}
+	if len(c.ExpectedKeywords) > 0 {
+		result.KeywordCoverage = float64(len(result.MatchedKeywords)) / float64(len(c.ExpectedKeywords))
+	}
+}
+
+// SummarizeEval 汇总检索命中率、MRR 和关键词覆盖率
+// 只统计有对应期望的问题：没有 expected_sources 的问题不计入命中率，没有回答的问题不计入覆盖率
+func SummarizeEval(set *EvalSet, results []EvalCaseResult) *EvalReport {
+	report := &EvalReport{Cases: results}
+	var hits int
+	var reciprocal, coverage float64
+	for i, r := range results {
+		c := set.Cases[i]
+		if len(c.ExpectedSources) > 0 {
+			report.RetrievalCases++
+			if r.SourceHit {
+				hits++
+				reciprocal += 1 / float64(r.HitRank)
+			}
+		}
+		if len(c.ExpectedKeywords) > 0 && r.Answer != "" {
+			report.AnswerCases++
+			coverage += r.KeywordCoverage
+		}
+	}
+	if report.RetrievalCases > 0 {
+		report.RetrievalHitRate = float64(hits) / float64(report.RetrievalCases)
+		report.MRR = reciprocal / float64(report.RetrievalCases)
+	}
+	if report.AnswerCases > 0 {
+		report.KeywordCoverage = coverage / float64(report.AnswerCases)
+	}
+	report.Summary = fmt.Sprintf("%d 个问题：检索命中率 %.0f%%（%d/%d，top %d），MRR %.2f；回答关键词覆盖率 %.0f%%（%d 个问题）",
+		len(results), report.RetrievalHitRate*100, hits, report.RetrievalCases, set.TopK, report.MRR,
+		report.KeywordCoverage*100, report.AnswerCases)
+	return report
+}
+
+// ModelEvalReport 一个向量模型在样本索引上的评测结果
+type ModelEvalReport struct {
+	Model          string      `json:"model"`
+	Dim            int         `json:"dim,omitempty"`           // 向量维度
+	IndexSeconds   float64     `json:"index_seconds,omitempty"` // 为样本生成向量并写入的耗时
+	AvgQueryMillis float64     `json:"avg_query_ms,omitempty"`  // 平均检索耗时（含问题向量化）
+	P95QueryMillis float64     `json:"p95_query_ms,omitempty"`
+	Report         *EvalReport `json:"report,omitempty"`
+	Error          string      `json:"error,omitempty"` // 建索引失败
+}
+
+// EmbeddingComparison 多个向量模型在同一样本、同一评测集上的对比
+type EmbeddingComparison struct {
+	Files   int               `json:"files"`  // 样本文件数
+	Chunks  int               `json:"chunks"` // 样本片段数
+	TopK    int               `json:"top_k"`
+	Models  []ModelEvalReport `json:"models"`
+	Best    string            `json:"best,omitempty"` // 检索命中率最高的模型，相同时比较 MRR，再比较平均检索耗时
+	Summary string            `json:"summary"`
+}
+
+// SampleEvalFiles 从文件列表中抽取最多 n 个文件建立样本索引（n <= 0 时不抽样）
+// 评测集期望的文件总是包含在内，否则无论哪个模型都无法命中；其余名额按路径排序后等间隔抽取，结果可重复
+func SampleEvalFiles(files []string, set *EvalSet, n int) []string {
+	if n <= 0 || len(files) <= n {
+		return files
+	}
+	var sample, rest []string
+	for _, file := range files {
+		if expectedBy(set, file) {
+			sample = append(sample, file)
+		} else {
+			rest = append(rest, file)
+		}
+	}
+	sort.Strings(rest)
+	if remaining := n - len(sample); remaining > 0 {
+		step := float64(len(rest)) / float64(remaining)
+		for i := 0; i < remaining && i < len(rest); i++ {
+			sample = append(sample, rest[int(float64(i)*step)])
+		}
+	}
+	return sample
+}
+
+// expectedBy 文件是否是评测集中某个问题期望检索到的文件
+func expectedBy(set *EvalSet, file string) bool {
+	for _, c := range set.Cases {
+		for _, expected := range c.ExpectedSources {
+			if sourceRank([]string{file}, expected) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// QueryLatency 检索耗时的平均值和 P95（毫秒）
+func QueryLatency(durations []time.Duration) (avg, p95 float64) {
+	if len(durations) == 0 {
+		return 0, 0
+	}
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	idx := (len(sorted)*95+99)/100 - 1
+	return millis(total) / float64(len(sorted)), millis(sorted[idx])
+}
+
+// millis 耗时的毫秒数
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// CompareEmbeddingModels 选出最佳模型并生成摘要，建索引失败的模型不参与比较
+func CompareEmbeddingModels(c *EmbeddingComparison) {
+	var best *ModelEvalReport
+	for i := range c.Models {
+		m := &c.Models[i]
+		if m.Report == nil {
+			continue
+		}
+		if best == nil || betterModel(m, best) {
+			best = m
+		}
+	}
+	parts := []string{fmt.Sprintf("样本 %d 个文件、%d 个片段，top %d", c.Files, c.Chunks, c.TopK)}
+	for _, m := range c.Models {
+		if m.Report == nil {
+			parts = append(parts, fmt.Sprintf("%s 失败", m.Model))
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s 命中率 %.0f%%、MRR %.2f、平均检索 %.0fms",
+			m.Model, m.Report.RetrievalHitRate*100, m.Report.MRR, m.AvgQueryMillis))
+	}
+	if best != nil {
+		c.Best = best.Model
+		parts = append(parts, "推荐 "+best.Model)
+	}
+	c.Summary = strings.Join(parts, "；")
+}
+
+// betterModel a 是否优于 b：先比较检索命中率，再比较 MRR，最后比较平均检索耗时
+func betterModel(a, b *ModelEvalReport) bool {
+	const epsilon = 1e-9
+	if d := a.Report.RetrievalHitRate - b.Report.RetrievalHitRate; d > epsilon || d < -epsilon {
+		return d > 0
+	}
+	if d := a.Report.MRR - b.Report.MRR; d > epsilon || d < -epsilon {
+		return d > 0
+	}
+	return a.AvgQueryMillis < b.AvgQueryMillis
+}
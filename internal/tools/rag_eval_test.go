@@ -0,0 +1,123 @@
+package tools
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// 测试读取评测集：默认 top_k 和缺少期望时报错
+func TestLoadEvalSet(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cases.yaml")
+	content := `cases:
+  - question: 代码是怎么切分的？
+    expected_sources: [internal/ai/code_splitter.go]
+    expected_keywords: [SplitDocuments, 函数]
+  - question: 向量存在哪里？
+    expected_sources:
+      - internal/ai/milvus_service.go
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	set, err := LoadEvalSet(path)
+	if err != nil {
+		t.Fatalf("读取评测集失败: %v", err)
+	}
+	if set.TopK != defaultEvalTopK || len(set.Cases) != 2 || len(set.Cases[0].ExpectedKeywords) != 2 {
+		t.Errorf("评测集解析错误: %+v", set)
+	}
+
+	if err := os.WriteFile(path, []byte("cases:\n  - question: 没有期望\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadEvalSet(path); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("没有期望的问题应该返回 ErrInvalidInput，实际 %v", err)
+	}
+}
+
+// 测试检索命中、名次和关键词覆盖率的汇总
+func TestEvalScoring(t *testing.T) {
+	set := &EvalSet{TopK: 3, Cases: []EvalCase{
+		{Question: "q1", ExpectedSources: []string{"ai/code_splitter.go"}, ExpectedKeywords: []string{"SplitDocuments", "ast"}},
+		{Question: "q2", ExpectedSources: []string{"ai/milvus_service.go"}},
+		{Question: "q3", ExpectedKeywords: []string{"Milvus"}},
+	}}
+
+	results := []EvalCaseResult{
+		ScoreRetrieval(set.Cases[0], []string{"internal/ai/scanner.go", "internal/ai/code_splitter.go"}),
+		ScoreRetrieval(set.Cases[1], []string{"internal/ai/search.go"}),
+		ScoreRetrieval(set.Cases[2], nil),
+	}
+	if !results[0].SourceHit || results[0].HitRank != 2 {
+		t.Errorf("q1 应该在第 2 名命中: %+v", results[0])
+	}
+	if results[1].SourceHit || len(results[1].MissingSources) != 1 {
+		t.Errorf("q2 不应命中: %+v", results[1])
+	}
+
+	ScoreAnswer(set.Cases[0], "由 SplitDocuments 按函数切分", &results[0])
+	ScoreAnswer(set.Cases[2], "向量存在 milvus 中", &results[2])
+	if results[0].KeywordCoverage != 0.5 || len(results[0].MissingKeywords) != 1 {
+		t.Errorf("q1 关键词覆盖率应为 0.5: %+v", results[0])
+	}
+
+	report := SummarizeEval(set, results)
+	if report.RetrievalCases != 2 || report.RetrievalHitRate != 0.5 {
+		t.Errorf("检索命中率错误: %+v", report)
+	}
+	if math.Abs(report.MRR-0.25) > 1e-9 {
+		t.Errorf("MRR 应为 0.25，实际 %v", report.MRR)
+	}
+	if report.AnswerCases != 2 || math.Abs(report.KeywordCoverage-0.75) > 1e-9 {
+		t.Errorf("关键词覆盖率应为 0.75: %+v", report)
+	}
+}
+
+// 测试抽样：期望的文件总是包含在内，其余等间隔抽取
+func TestSampleEvalFiles(t *testing.T) {
+	set := &EvalSet{Cases: []EvalCase{{Question: "q", ExpectedSources: []string{"ai/search.go"}}}}
+	files := []string{"a.go", "b.go", "c.go", "d.go", "internal/ai/search.go", "e.go"}
+
+	got := SampleEvalFiles(files, set, 3)
+	want := []string{"internal/ai/search.go", "a.go", "c.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("抽样结果 = %v, want %v", got, want)
+	}
+	if got := SampleEvalFiles(files, set, 0); len(got) != len(files) {
+		t.Errorf("n 为 0 时不应抽样: %v", got)
+	}
+}
+
+// 测试检索耗时统计和模型比较
+func TestCompareEmbeddingModels(t *testing.T) {
+	var durations []time.Duration
+	for i := 1; i <= 20; i++ {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+	avg, p95 := QueryLatency(durations)
+	if avg != 10.5 || p95 != 19 {
+		t.Errorf("平均 %v、P95 %v，want 10.5、19", avg, p95)
+	}
+
+	c := &EmbeddingComparison{Files: 10, Chunks: 40, TopK: 5, Models: []ModelEvalReport{
+		{Model: "bge-m3", AvgQueryMillis: 30, Report: &EvalReport{RetrievalHitRate: 0.8, MRR: 0.6}},
+		{Model: "nomic-embed-text", AvgQueryMillis: 10, Report: &EvalReport{RetrievalHitRate: 0.8, MRR: 0.7}},
+		{Model: "broken", Error: "模型不存在"},
+	}}
+	CompareEmbeddingModels(c)
+	if c.Best != "nomic-embed-text" {
+		t.Errorf("命中率相同时应按 MRR 选出 nomic-embed-text，实际 %q", c.Best)
+	}
+
+	c.Models[1].Report.MRR = 0.6
+	CompareEmbeddingModels(c)
+	if c.Best != "nomic-embed-text" {
+		t.Errorf("MRR 也相同时应选检索更快的模型，实际 %q", c.Best)
+	}
+}
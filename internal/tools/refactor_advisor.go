@@ -0,0 +1,114 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// RefactorAdvisor 重构建议
+// 依赖 complexity_analyzer：由 ToolManager 先执行复杂度分析，再按复杂度和行数给出重构建议
+type RefactorAdvisor struct {
+	*TypedTool[string, *RefactorResult]
+}
+
+// NewRefactorAdvisor 创建重构建议工具
+func NewRefactorAdvisor() *RefactorAdvisor {
+	ra := &RefactorAdvisor{}
+	ra.TypedTool = NewTypedTool[string, *RefactorResult](
+		"refactor_advisor",
+		"根据复杂度分析结果，为复杂度或行数超标的函数给出重构建议",
+		ra,
+	)
+	return ra
+}
+
+// RefactorAdvice 单个函数的重构建议
+type RefactorAdvice struct {
+	Function    string   `json:"function"`
+	Line        int      `json:"line"`
+	Complexity  int      `json:"complexity"`
+	Lines       int      `json:"lines"`
+	Priority    string   `json:"priority"` // Medium、High、Critical，与 --fail-on 的分档一致
+	Suggestions []string `json:"suggestions"`
+}
+
+// RefactorResult 重构建议结果，按优先级从高到低排列
+type RefactorResult struct {
+	Advice  []RefactorAdvice `json:"advice"`
+	Summary string           `json:"summary"`
+}
+
+// Dependencies 需要复杂度分析的结果，输入相同（代码字符串）
+func (ra *RefactorAdvisor) Dependencies() []ToolDependency {
+	return []ToolDependency{{Tool: "complexity_analyzer"}}
+}
+
+// ValidateInput 验证输入：代码不能为空
+func (ra *RefactorAdvisor) ValidateInput(code string) error {
+	if code == "" {
+		return ErrInvalidInput
+	}
+	return nil
+}
+
+// Execute 读取前置的复杂度分析结果并生成建议
+func (ra *RefactorAdvisor) Execute(ctx context.Context, code string) (*RefactorResult, error) {
+	dep, ok := DependencyResult(ctx, "complexity_analyzer")
+	if !ok {
+		return nil, fmt.Errorf("%w: 缺少 complexity_analyzer 的结果，需要经由 ToolManager 执行", ErrToolExecution)
+	}
+	var complexity ComplexityResult
+	if err := json.Unmarshal([]byte(dep.Result), &complexity); err != nil {
+		return nil, fmt.Errorf("解析复杂度分析结果失败: %w", err)
+	}
+
+	result := &RefactorResult{Advice: []RefactorAdvice{}}
+	for _, fn := range complexity.Functions {
+		if advice, ok := refactorAdvice(fn); ok {
+			result.Advice = append(result.Advice, advice)
+		}
+	}
+	sort.SliceStable(result.Advice, func(i, j int) bool {
+		return severityIndex(result.Advice[i].Priority) > severityIndex(result.Advice[j].Priority)
+	})
+
+	if len(result.Advice) == 0 {
+		result.Summary = fmt.Sprintf("%d 个函数都不需要重构 ✅", len(complexity.Functions))
+	} else {
+		result.Summary = fmt.Sprintf("%d 个函数中有 %d 个建议重构", len(complexity.Functions), len(result.Advice))
+	}
+	return result, nil
+}
+
+// refactorAdvice 按复杂度和行数生成建议，两项都没有超标时返回 false
+func refactorAdvice(fn FunctionResult) (RefactorAdvice, bool) {
+	priority := ComplexitySeverity(fn.Complexity)
+	var suggestions []string
+	switch priority {
+	case "Critical", "High":
+		suggestions = append(suggestions, "按职责提取子函数（Extract Function），每个函数只做一件事")
+		suggestions = append(suggestions, "把长的 switch / if-else 链改为表驱动（map 或函数表）")
+		fallthrough
+	case "Medium":
+		suggestions = append(suggestions, "用卫语句（提前返回）减少嵌套层级")
+	}
+	if fn.Lines > 50 {
+		if priority == "" {
+			priority = "Medium"
+		}
+		suggestions = append(suggestions, fmt.Sprintf("函数有 %d 行，按处理步骤拆分，并为每一步命名", fn.Lines))
+	}
+	if len(suggestions) == 0 {
+		return RefactorAdvice{}, false
+	}
+	return RefactorAdvice{
+		Function:    fn.Name,
+		Line:        fn.Line,
+		Complexity:  fn.Complexity,
+		Lines:       fn.Lines,
+		Priority:    priority,
+		Suggestions: suggestions,
+	}, true
+}
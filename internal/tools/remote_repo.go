@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// RemoteRepo 远程仓库地址，如 https://github.com/org/repo@v1.2.3
+type RemoteRepo struct {
+	URL string `json:"url"`           // 克隆地址
+	Ref string `json:"ref,omitempty"` // 分支、标签或提交，为空表示默认分支
+}
+
+// String 返回 URL@Ref 形式
+func (r RemoteRepo) String() string {
+	if r.Ref == "" {
+		return r.URL
+	}
+	return r.URL + "@" + r.Ref
+}
+
+// Host 仓库所在的主机，file:// 地址为空
+func (r RemoteRepo) Host() string {
+	if strings.HasPrefix(r.URL, "file://") {
+		return ""
+	}
+	if rest, ok := strings.CutPrefix(r.URL, "git@"); ok {
+		host, _, _ := strings.Cut(rest, ":")
+		return host
+	}
+	return EndpointHost(r.URL)
+}
+
+// remoteSchemes 识别为远程仓库的地址前缀
+var remoteSchemes = []string{"https://", "http://", "ssh://", "git://", "git@", "file://"}
+
+// ParseRemoteRepo 识别远程仓库地址，不是远程地址（本地路径）时 ok 为 false
+// 最后一个路径段中的 @ 之后是版本；git@host:org/repo 中用户名部分的 @ 不算
+func ParseRemoteRepo(target string) (repo RemoteRepo, ok bool) {
+	remote := false
+	for _, scheme := range remoteSchemes {
+		if strings.HasPrefix(target, scheme) {
+			remote = true
+			break
+		}
+	}
+	if !remote {
+		return RemoteRepo{}, false
+	}
+
+	repo.URL = target
+	lastSlash := strings.LastIndexAny(target, "/:")
+	if at := strings.LastIndex(target, "@"); at > lastSlash {
+		repo.URL, repo.Ref = target[:at], target[at+1:]
+	}
+	return repo, true
+}
+
+// CloneRemoteRepo 把远程仓库的指定版本浅克隆到临时目录
+// 只取一个提交（分支、标签、提交哈希都可以），返回目录和删除目录的清理函数
+// 地址和版本来自用户输入，以 - 开头的会被 git 当作选项（如 --upload-pack 可执行任意命令），直接拒绝
+func CloneRemoteRepo(ctx context.Context, repo RemoteRepo) (dir string, cleanup func(), err error) {
+	if strings.HasPrefix(repo.URL, "-") || strings.HasPrefix(repo.Ref, "-") {
+		return "", nil, fmt.Errorf("无效的仓库地址或版本: %s", repo)
+	}
+	dir, err = os.MkdirTemp("", "go-ai-insight-remote-")
+	if err != nil {
+		return "", nil, fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	cleanup = func() { os.RemoveAll(dir) }
+
+	ref := repo.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	steps := [][]string{
+		{"init", "--quiet"},
+		{"fetch", "--quiet", "--depth", "1", "--", repo.URL, ref},
+		{"-c", "advice.detachedHead=false", "checkout", "--quiet", "FETCH_HEAD"},
+	}
+	for _, args := range steps {
+		if _, err := gitOutput(ctx, dir, args...); err != nil {
+			cleanup()
+			return "", nil, fmt.Errorf("克隆 %s 失败: %w", repo, err)
+		}
+	}
+	return dir, cleanup, nil
+}
@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// 测试远程仓库地址解析
+func TestParseRemoteRepo(t *testing.T) {
+	tests := []struct {
+		target string
+		ok     bool
+		url    string
+		ref    string
+		host   string
+	}{
+		{"https://github.com/org/repo@v1.2.3", true, "https://github.com/org/repo", "v1.2.3", "github.com"},
+		{"https://github.com/org/repo", true, "https://github.com/org/repo", "", "github.com"},
+		{"https://user@example.com/org/repo.git", true, "https://user@example.com/org/repo.git", "", "example.com"},
+		{"git@github.com:org/repo@main", true, "git@github.com:org/repo", "main", "github.com"},
+		{"file:///tmp/repo@abc123", true, "file:///tmp/repo", "abc123", ""},
+		{"./myproject", false, "", "", ""},
+		{"github.com/org/repo", false, "", "", ""},
+	}
+	for _, tt := range tests {
+		repo, ok := ParseRemoteRepo(tt.target)
+		if ok != tt.ok || repo.URL != tt.url || repo.Ref != tt.ref {
+			t.Errorf("ParseRemoteRepo(%q) = %+v %v, want %s@%s %v", tt.target, repo, ok, tt.url, tt.ref, tt.ok)
+			continue
+		}
+		if ok && repo.Host() != tt.host {
+			t.Errorf("%q 的主机 = %q, want %q", tt.target, repo.Host(), tt.host)
+		}
+	}
+}
+
+// 测试浅克隆指定标签，清理后临时目录被删除
+func TestCloneRemoteRepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("没有 git")
+	}
+	origin := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@t", "-c", "commit.gpgsign=false"}, args...)...)
+		cmd.Dir = origin
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v 失败: %v\n%s", args, err, out)
+		}
+	}
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(origin, "lib.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "-q")
+	write("package lib\n\nconst Version = 1\n")
+	git("add", "-A")
+	git("commit", "-q", "-m", "v1")
+	git("tag", "v1.0.0")
+	write("package lib\n\nconst Version = 2\n")
+	git("commit", "-q", "-am", "v2")
+
+	repo, _ := ParseRemoteRepo("file://" + filepath.ToSlash(origin) + "@v1.0.0")
+	dir, cleanup, err := CloneRemoteRepo(context.Background(), repo)
+	if err != nil {
+		t.Fatalf("克隆失败: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "lib.go"))
+	if err != nil || string(content) != "package lib\n\nconst Version = 1\n" {
+		t.Errorf("应该检出 v1.0.0 的内容: %q %v", content, err)
+	}
+	cleanup()
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("清理后临时目录应该被删除: %v", err)
+	}
+
+	if _, _, err := CloneRemoteRepo(context.Background(), RemoteRepo{URL: repo.URL, Ref: "v9.9.9"}); err == nil {
+		t.Error("不存在的版本应该返回错误")
+	}
+}
+
+// 测试以 - 开头的版本和地址不会作为选项传给 git：--upload-pack 中的命令不能被执行
+func TestCloneRemoteRepo_RejectsOptions(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("没有 git")
+	}
+	origin := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", origin).CombinedOutput(); err != nil {
+		t.Fatalf("git init 失败: %v\n%s", err, out)
+	}
+	marker := filepath.Join(t.TempDir(), "pwned")
+	if repo, _ := ParseRemoteRepo("https://host/repo@--upload-pack=touch"); repo.Ref != "--upload-pack=touch" {
+		t.Fatalf("版本应该原样解析出来: %+v", repo)
+	}
+	url := "file://" + filepath.ToSlash(origin)
+	for _, r := range []RemoteRepo{{URL: url, Ref: "--upload-pack=touch " + marker}, {URL: "--upload-pack=touch " + marker, Ref: url}} {
+		if _, _, err := CloneRemoteRepo(context.Background(), r); err == nil {
+			t.Errorf("%s 应该返回错误", r)
+		}
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Errorf("--upload-pack 中的命令不应该被执行: %v", err)
+	}
+}
@@ -0,0 +1,206 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// ruleFixtureDir 规则测试夹具的根目录
+//
+// 每组夹具是 testdata/rules/<组名>/ 下的 .go 文件，期望的问题写在所在行末尾：
+//
+//	rows, err := db.Query("SELECT 1") // want B120 B121
+//
+// testRuleFixtures 对夹具运行检测，比较指定规则的实际问题和 want 注释：
+// 缺少的问题和多出的问题都会按 文件:行号 报告。没有列出的规则不参与比较。
+const ruleFixtureDir = "testdata/rules"
+
+// ruleFixture 一个夹具文件
+type ruleFixture struct {
+	Path string
+	Code string
+	Want map[string][]int // 规则 ID -> 期望的行号（升序）
+}
+
+// loadRuleFixtures 读取一组夹具，解析其中的 want 注释
+func loadRuleFixtures(t *testing.T, group string) []ruleFixture {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(ruleFixtureDir, group, "*.go"))
+	if err != nil {
+		t.Fatalf("查找夹具失败: %v", err)
+	}
+	if len(paths) == 0 {
+		t.Fatalf("夹具组 %s 下没有 .go 文件", group)
+	}
+
+	var fixtures []ruleFixture
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("读取夹具失败: %v", err)
+		}
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, data, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("解析夹具失败: %v", err)
+		}
+
+		fx := ruleFixture{Path: path, Code: string(data), Want: make(map[string][]int)}
+		for _, group := range file.Comments {
+			for _, c := range group.List {
+				ids, ok := strings.CutPrefix(c.Text, "// want ")
+				if !ok {
+					continue
+				}
+				line := fset.Position(c.Slash).Line
+				for _, id := range strings.Fields(ids) {
+					fx.Want[id] = append(fx.Want[id], line)
+				}
+			}
+		}
+		fixtures = append(fixtures, fx)
+	}
+	return fixtures
+}
+
+// fixtureRuleLines 检测夹具，返回指定规则命中的行号（升序）
+// B 开头的规则由 Bug 检测器检查，其余由安全扫描器检查
+func fixtureRuleLines(t *testing.T, fx ruleFixture, ruleID string, opts ruleOptions) []int {
+	t.Helper()
+	lines := []int{}
+	if strings.HasPrefix(ruleID, "B") {
+		bugs, err := NewBugDetector().analyzeCode(context.Background(), fx.Code, fx.Path, opts)
+		if err != nil {
+			t.Fatalf("%s: 检测失败: %v", fx.Path, err)
+		}
+		for _, bug := range deduplicateBugIssues(bugs) {
+			if bug.RuleID == ruleID {
+				lines = append(lines, bug.Line)
+			}
+		}
+	} else {
+		result, err := NewSecurityScanner().Execute(context.Background(), SecurityScanInput{Code: fx.Code, File: fx.Path})
+		if err != nil {
+			t.Fatalf("%s: 扫描失败: %v", fx.Path, err)
+		}
+		for _, issue := range result.Issues {
+			if issue.RuleID == ruleID {
+				lines = append(lines, issue.Line)
+			}
+		}
+	}
+	sort.Ints(lines)
+	return lines
+}
+
+// testRuleFixtures 对一组夹具运行检测，逐条规则比较实际问题和 want 注释
+func testRuleFixtures(t *testing.T, group string, opts ruleOptions, ruleIDs ...string) {
+	t.Helper()
+	for _, fx := range loadRuleFixtures(t, group) {
+		for _, ruleID := range ruleIDs {
+			got := fixtureRuleLines(t, fx, ruleID, opts)
+			for _, msg := range diffRuleLines(fx.Want[ruleID], got) {
+				t.Errorf("%s:%s %s", fx.Path, msg, ruleID)
+			}
+		}
+	}
+}
+
+// testNoRuleFindings 对一组夹具运行检测，断言指定规则没有任何问题（用于规则因配置不生效的情况）
+func testNoRuleFindings(t *testing.T, group string, opts ruleOptions, ruleIDs ...string) {
+	t.Helper()
+	for _, fx := range loadRuleFixtures(t, group) {
+		for _, ruleID := range ruleIDs {
+			if got := fixtureRuleLines(t, fx, ruleID, opts); len(got) > 0 {
+				t.Errorf("%s: 不应报告 %s，实际行号 %v", fx.Path, ruleID, got)
+			}
+		}
+	}
+}
+
+// diffRuleLines 比较期望和实际的行号，返回 "行号: 缺少/多出" 形式的差异
+func diffRuleLines(want, got []int) []string {
+	count := make(map[int]int)
+	for _, line := range want {
+		count[line]++
+	}
+	for _, line := range got {
+		count[line]--
+	}
+
+	lines := make([]int, 0, len(count))
+	for line := range count {
+		lines = append(lines, line)
+	}
+	sort.Ints(lines)
+
+	var diffs []string
+	for _, line := range lines {
+		switch n := count[line]; {
+		case n > 0:
+			diffs = append(diffs, fmt.Sprintf("%d: 缺少问题", line))
+		case n < 0:
+			diffs = append(diffs, fmt.Sprintf("%d: 多出问题", line))
+		}
+	}
+	return diffs
+}
+
+// 测试每条内置规则都有夹具覆盖
+func TestRuleFixtures_Coverage(t *testing.T) {
+	covered := make(map[string]bool)
+	groups, err := os.ReadDir(ruleFixtureDir)
+	if err != nil {
+		t.Fatalf("读取夹具目录失败: %v", err)
+	}
+	for _, group := range groups {
+		if !group.IsDir() {
+			continue
+		}
+		for _, fx := range loadRuleFixtures(t, group.Name()) {
+			for id := range fx.Want {
+				covered[id] = true
+			}
+		}
+	}
+
+	bugEngine := NewBugRuleEngine()
+	bugEngine.RegisterAllRules()
+	for _, rule := range bugEngine.Rules {
+		if !covered[rule.ID()] {
+			t.Errorf("规则 %s 没有夹具（在 %s 下添加带 // want %s 注释的文件）", rule.ID(), ruleFixtureDir, rule.ID())
+		}
+	}
+	secEngine := NewRuleEngine()
+	secEngine.RegisterAllRules()
+	for _, rule := range secEngine.Rules {
+		if !covered[rule.ID()] {
+			t.Errorf("规则 %s 没有夹具（在 %s 下添加带 // want %s 注释的文件）", rule.ID(), ruleFixtureDir, rule.ID())
+		}
+	}
+}
+
+// 测试夹具框架的差异报告
+func TestRuleFixtures_Diff(t *testing.T) {
+	tests := []struct {
+		want, got []int
+		diffs     []string
+	}{
+		{[]int{3, 5}, []int{3, 5}, nil},
+		{[]int{3, 5}, []int{5, 7}, []string{"3: 缺少问题", "7: 多出问题"}},
+		{[]int{4, 4}, []int{4}, []string{"4: 缺少问题"}},
+		{nil, []int{}, nil},
+	}
+	for _, tt := range tests {
+		if diffs := diffRuleLines(tt.want, tt.got); strings.Join(diffs, ";") != strings.Join(tt.diffs, ";") {
+			t.Errorf("diffRuleLines(%v, %v) = %v, want %v", tt.want, tt.got, diffs, tt.diffs)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package tools
+
+import (
+	"fmt"
+	"path/filepath"
+	"plugin"
+	"strings"
+	"sync"
+)
+
+// RulePluginSymbol 规则插件导出的注册函数名，类型必须是 func(tools.RuleRegistry) error
+const RulePluginSymbol = "InsightRules"
+
+// RuleRegistry 外部规则的注册入口，传给规则插件的注册函数
+type RuleRegistry interface {
+	RegisterBugRule(rule BugRule) error
+	RegisterSecurityRule(rule SecurityRule) error
+}
+
+// externalRules 进程级的外部规则，BugRuleEngine 和 RuleEngine 的 RegisterAllRules 在内置规则之后注册它们
+var externalRules struct {
+	sync.RWMutex
+	bug      []BugRule
+	security []SecurityRule
+	plugins  map[string]bool // 已加载的插件（绝对路径），同一个插件只加载一次
+}
+
+// RegisterBugRule 注册外部 Bug 规则，之后创建的 bug_detector 都会使用
+// 公司内部的规则集可以在 init 中调用它，再在自己的构建中以空白导入引入，不需要修改本仓库
+func RegisterBugRule(rule BugRule) error {
+	return (&ruleBatch{bug: []BugRule{rule}}).commit("")
+}
+
+// RegisterSecurityRule 注册外部安全规则，之后创建的 security_scanner 都会使用
+func RegisterSecurityRule(rule SecurityRule) error {
+	return (&ruleBatch{security: []SecurityRule{rule}}).commit("")
+}
+
+// builtinRules 内置规则 ID 对应的规则文档（只有工具和名称），只构建一次
+var builtinRules = sync.OnceValue(func() map[string]RuleDoc {
+	docs := make(map[string]RuleDoc)
+	bugEngine := NewBugRuleEngine()
+	bugEngine.registerBuiltinRules()
+	for _, rule := range bugEngine.Rules {
+		docs[rule.ID()] = RuleDoc{ID: rule.ID(), Name: rule.Name(), Tool: "bug_detector"}
+	}
+	secEngine := NewRuleEngine()
+	secEngine.registerBuiltinRules()
+	for _, rule := range secEngine.Rules {
+		docs[rule.ID()] = RuleDoc{ID: rule.ID(), Name: rule.Name(), Tool: "security_scanner"}
+	}
+	return docs
+})
+
+// checkExternalRuleID 外部规则的 ID 不能为空，也不能与内置规则、已注册的外部规则或同一批中的规则重复
+// 调用方需持有 externalRules 的锁，检查和加入在同一次加锁中完成，并发注册同一个 ID 时只有一个成功
+func checkExternalRuleID(id string, pending map[string]bool) error {
+	if strings.TrimSpace(id) == "" {
+		return fmt.Errorf("%w: 外部规则缺少 ID", ErrInvalidInput)
+	}
+	if doc, ok := builtinRules()[id]; ok {
+		return fmt.Errorf("%w: 外部规则 %s 与 %s 的规则 %s 重复", ErrInvalidInput, id, doc.Tool, doc.Name)
+	}
+	for _, rule := range externalRules.bug {
+		if rule.ID() == id {
+			return fmt.Errorf("%w: 外部规则 %s 与已注册的 Bug 规则 %s 重复", ErrInvalidInput, id, rule.Name())
+		}
+	}
+	for _, rule := range externalRules.security {
+		if rule.ID() == id {
+			return fmt.Errorf("%w: 外部规则 %s 与已注册的安全规则 %s 重复", ErrInvalidInput, id, rule.Name())
+		}
+	}
+	if pending[id] {
+		return fmt.Errorf("%w: 外部规则 %s 重复注册", ErrInvalidInput, id)
+	}
+	return nil
+}
+
+// externalBugRules 已注册的外部 Bug 规则
+func externalBugRules() []BugRule {
+	externalRules.RLock()
+	defer externalRules.RUnlock()
+	return append([]BugRule(nil), externalRules.bug...)
+}
+
+// externalSecurityRules 已注册的外部安全规则
+func externalSecurityRules() []SecurityRule {
+	externalRules.RLock()
+	defer externalRules.RUnlock()
+	return append([]SecurityRule(nil), externalRules.security...)
+}
+
+// ruleBatch 一批待注册的外部规则，传给规则插件的注册函数
+// 注册函数返回 nil 后才经由 commit 一起加入进程级的外部规则；中途失败时已注册的规则全部丢弃
+type ruleBatch struct {
+	bug      []BugRule
+	security []SecurityRule
+}
+
+func (b *ruleBatch) RegisterBugRule(rule BugRule) error {
+	if err := b.check(rule.ID()); err != nil {
+		return err
+	}
+	b.bug = append(b.bug, rule)
+	return nil
+}
+
+func (b *ruleBatch) RegisterSecurityRule(rule SecurityRule) error {
+	if err := b.check(rule.ID()); err != nil {
+		return err
+	}
+	b.security = append(b.security, rule)
+	return nil
+}
+
+// check 注册时提前检查 ID，插件可以在注册函数中处理错误；commit 时还会在写锁下再检查一次
+func (b *ruleBatch) check(id string) error {
+	externalRules.RLock()
+	defer externalRules.RUnlock()
+	return checkExternalRuleID(id, b.ids())
+}
+
+// ids 本批中已有的规则 ID
+func (b *ruleBatch) ids() map[string]bool {
+	ids := make(map[string]bool, len(b.bug)+len(b.security))
+	for _, rule := range b.bug {
+		ids[rule.ID()] = true
+	}
+	for _, rule := range b.security {
+		ids[rule.ID()] = true
+	}
+	return ids
+}
+
+// commit 在一次写锁中检查所有 ID 并加入外部规则，任何一个 ID 冲突时一条都不加入
+// plugin 不为空时同时标记该插件已加载；插件已经被并发的另一次加载注册过时直接返回
+func (b *ruleBatch) commit(plugin string) error {
+	externalRules.Lock()
+	defer externalRules.Unlock()
+	if plugin != "" && externalRules.plugins[plugin] {
+		return nil
+	}
+	pending := make(map[string]bool, len(b.bug)+len(b.security))
+	for _, rule := range b.bug {
+		if err := checkExternalRuleID(rule.ID(), pending); err != nil {
+			return err
+		}
+		pending[rule.ID()] = true
+	}
+	for _, rule := range b.security {
+		if err := checkExternalRuleID(rule.ID(), pending); err != nil {
+			return err
+		}
+		pending[rule.ID()] = true
+	}
+	externalRules.bug = append(externalRules.bug, b.bug...)
+	externalRules.security = append(externalRules.security, b.security...)
+	if plugin != "" {
+		if externalRules.plugins == nil {
+			externalRules.plugins = make(map[string]bool)
+		}
+		externalRules.plugins[plugin] = true
+	}
+	return nil
+}
+
+// LoadRulePlugins 加载 Go 插件（go build -buildmode=plugin 生成的 .so）中的规则
+// 插件需导出 InsightRules 函数，在其中调用 RuleRegistry 注册规则。插件必须用与本程序相同的 Go 版本和
+// 相同版本的本仓库代码构建，且只支持 Linux 和 macOS；已加载的插件再次加载时跳过
+func LoadRulePlugins(paths []string) error {
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			abs = path
+		}
+		externalRules.RLock()
+		loaded := externalRules.plugins[abs]
+		externalRules.RUnlock()
+		if loaded {
+			continue
+		}
+
+		p, err := plugin.Open(abs)
+		if err != nil {
+			return fmt.Errorf("加载规则插件 %s 失败: %w", path, err)
+		}
+		sym, err := p.Lookup(RulePluginSymbol)
+		if err != nil {
+			return fmt.Errorf("规则插件 %s 没有导出 %s: %w", path, RulePluginSymbol, err)
+		}
+		register, ok := sym.(func(RuleRegistry) error)
+		if !ok {
+			return fmt.Errorf("%w: 规则插件 %s 的 %s 类型应为 func(tools.RuleRegistry) error，实际为 %T", ErrInvalidInput, path, RulePluginSymbol, sym)
+		}
+		if err := registerPluginRules(abs, register); err != nil {
+			return fmt.Errorf("规则插件 %s 注册规则失败: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// registerPluginRules 调用插件的注册函数，返回 nil 时才提交其中的规则并标记插件已加载
+// 注册函数失败时不留下任何规则，修复后可以重新加载同一个插件；已加载的插件直接跳过
+func registerPluginRules(plugin string, register func(RuleRegistry) error) error {
+	externalRules.RLock()
+	loaded := externalRules.plugins[plugin]
+	externalRules.RUnlock()
+	if loaded {
+		return nil
+	}
+	batch := &ruleBatch{}
+	if err := register(batch); err != nil {
+		return err
+	}
+	return batch.commit(plugin)
+}
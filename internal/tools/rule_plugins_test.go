@@ -0,0 +1,176 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"go/ast"
+	"maps"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// bannedPanicRule 测试用的外部 Bug 规则：调用 panic
+type bannedPanicRule struct{}
+
+func (r *bannedPanicRule) ID() string                              { return "X901" }
+func (r *bannedPanicRule) Name() string                            { return "Banned Panic" }
+func (r *bannedPanicRule) Severity() string                        { return "Medium" }
+func (r *bannedPanicRule) Category() string                        { return "Policy" }
+func (r *bannedPanicRule) Description() string                     { return "禁止调用 panic" }
+func (r *bannedPanicRule) GenerateSuggestion(node ast.Node) string { return "返回 error" }
+func (r *bannedPanicRule) Match(node ast.Node, ctx *BugRuleContext) bool {
+	call, ok := node.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := call.Fun.(*ast.Ident)
+	return ok && ident.Name == "panic"
+}
+
+// namedRule 指定 ID 的外部 Bug 规则
+type namedRule struct {
+	*bannedPanicRule
+	id string
+}
+
+func (r *namedRule) ID() string { return r.id }
+
+// restoreExternalRules 测试结束后恢复外部规则，避免影响其他测试
+func restoreExternalRules(t *testing.T) {
+	externalRules.Lock()
+	bug, security, plugins := externalRules.bug, externalRules.security, maps.Clone(externalRules.plugins)
+	externalRules.Unlock()
+	t.Cleanup(func() {
+		externalRules.Lock()
+		externalRules.bug, externalRules.security, externalRules.plugins = bug, security, plugins
+		externalRules.Unlock()
+	})
+}
+
+// 测试外部规则的注册、检测和规则设置
+func TestRegisterBugRule(t *testing.T) {
+	restoreExternalRules(t)
+	if err := RegisterBugRule(&bannedPanicRule{}); err != nil {
+		t.Fatalf("注册外部规则失败: %v", err)
+	}
+	if err := RegisterBugRule(&bannedPanicRule{}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("重复注册应返回 ErrInvalidInput, 实际 %v", err)
+	}
+	if err := RegisterSecurityRule(&HardCodedSecretRule{}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("与内置规则重复应返回 ErrInvalidInput, 实际 %v", err)
+	}
+
+	doc, ok := LookupRuleDoc("X901")
+	if !ok || doc.Tool != "bug_detector" {
+		t.Errorf("外部规则应有规则文档: %+v", doc)
+	}
+
+	code := "package x\n\nfunc f() {\n\tpanic(\"boom\")\n}\n"
+	result, err := NewBugDetector().Execute(context.Background(), BugDetectorInput{Code: code})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, bug := range result.Bugs {
+		found = found || bug.RuleID == "X901"
+	}
+	if !found {
+		t.Error("应检测到外部规则 X901")
+	}
+
+	// 外部规则同样可以在配置中关闭
+	config := DefaultToolConfig("bug_detector")
+	config.CustomConfig[CustomConfigDisabledRules] = []string{"X901"}
+	detector := NewBugDetector()
+	if err := detector.Configure(config); err != nil {
+		t.Fatalf("关闭外部规则失败: %v", err)
+	}
+	result, _ = detector.Execute(context.Background(), BugDetectorInput{Code: code})
+	for _, bug := range result.Bugs {
+		if bug.RuleID == "X901" {
+			t.Error("关闭的外部规则不应报告问题")
+		}
+	}
+}
+
+// 测试加载不存在的规则插件
+func TestLoadRulePlugins_Missing(t *testing.T) {
+	if err := LoadRulePlugins(nil); err != nil {
+		t.Errorf("没有插件时不应报错: %v", err)
+	}
+	if err := LoadRulePlugins([]string{filepath.Join(t.TempDir(), "missing.so")}); err == nil {
+		t.Error("插件不存在时应返回错误")
+	}
+}
+
+// 测试插件的注册函数失败时不留下已注册的规则，修复后可以重新加载；已加载的插件不重复注册
+func TestRegisterPluginRules(t *testing.T) {
+	restoreExternalRules(t)
+	hasRule := func(id string) bool {
+		for _, rule := range externalBugRules() {
+			if rule.ID() == id {
+				return true
+			}
+		}
+		return false
+	}
+	plugin := filepath.Join(t.TempDir(), "rules.so")
+
+	failing := func(r RuleRegistry) error {
+		if err := r.RegisterBugRule(&namedRule{&bannedPanicRule{}, "X902"}); err != nil {
+			return err
+		}
+		return r.RegisterSecurityRule(&HardCodedSecretRule{}) // 与内置规则 G101 重复
+	}
+	if err := registerPluginRules(plugin, failing); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("注册函数失败时应返回错误, 实际 %v", err)
+	}
+	if hasRule("X902") {
+		t.Error("注册函数失败时不应留下已注册的规则")
+	}
+
+	ok := func(r RuleRegistry) error {
+		return r.RegisterBugRule(&namedRule{&bannedPanicRule{}, "X902"})
+	}
+	if err := registerPluginRules(plugin, ok); err != nil {
+		t.Fatalf("重新加载失败: %v", err)
+	}
+	if err := registerPluginRules(plugin, ok); err != nil {
+		t.Errorf("已加载的插件再次加载应跳过: %v", err)
+	}
+	if !hasRule("X902") || len(externalBugRules()) != 1 {
+		t.Errorf("外部规则应只有一条 X902: %d", len(externalBugRules()))
+	}
+
+	duplicate := func(r RuleRegistry) error {
+		r.RegisterBugRule(&namedRule{&bannedPanicRule{}, "X903"})
+		return r.RegisterBugRule(&namedRule{&bannedPanicRule{}, "X903"})
+	}
+	if err := registerPluginRules(filepath.Join(t.TempDir(), "dup.so"), duplicate); !errors.Is(err, ErrInvalidInput) || hasRule("X903") {
+		t.Errorf("同一批中重复的 ID 应返回错误且不注册: %v", err)
+	}
+}
+
+// 测试并发注册同一个 ID 时只有一个成功
+func TestRegisterBugRule_Concurrent(t *testing.T) {
+	restoreExternalRules(t)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if RegisterBugRule(&namedRule{&bannedPanicRule{}, "X904"}) == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if succeeded != 1 || len(externalBugRules()) != 1 {
+		t.Errorf("并发注册同一个 ID 应只有一个成功: 成功 %d 次，外部规则 %d 条", succeeded, len(externalBugRules()))
+	}
+}
@@ -0,0 +1,147 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ToolConfig.CustomConfig 中的规则设置，对 bug_detector 和 security_scanner 生效
+const (
+	CustomConfigDisabledRules = "disabled_rules" // []string：关闭的规则 ID
+	CustomConfigRuleSeverity  = "rule_severity"  // map[string]string：规则 ID -> 覆盖后的严重程度
+	CustomConfigCustomRules   = "custom_rules"   // []string：YAML 自定义安全规则文件，只影响 security_scanner
+)
+
+// Configurable 需要读取 ToolConfig 的工具，ToolManager 在注册和更新配置时调用 Configure
+// 配置有误（如未知的规则 ID）时返回错误，注册失败
+type Configurable interface {
+	Configure(config ToolConfig) error
+}
+
+// RuleSettings 规则引擎注册规则前参考的设置
+type RuleSettings struct {
+	Disabled map[string]bool   // 关闭的规则，不注册
+	Severity map[string]string // 覆盖规则的严重程度
+	Custom   []*CustomRule     // YAML 定义的自定义安全规则
+}
+
+// RuleSettingsFromConfig 从 CustomConfig 读取规则设置
+// 规则 ID 必须是内置规则（两个工具共用一份设置，B 开头的规则只影响 bug_detector，G 开头的只影响 security_scanner），
+// 严重程度必须是 Low、Medium、High、Critical 之一。自定义规则先加载，它们的 ID 同样可以关闭和覆盖严重程度
+func RuleSettingsFromConfig(custom map[string]any) (RuleSettings, error) {
+	settings := RuleSettings{Disabled: make(map[string]bool), Severity: make(map[string]string)}
+
+	files, err := stringList(custom[CustomConfigCustomRules])
+	if err != nil {
+		return settings, fmt.Errorf("%w: %s: %v", ErrInvalidInput, CustomConfigCustomRules, err)
+	}
+	if settings.Custom, err = LoadCustomRules(files...); err != nil {
+		return settings, err
+	}
+
+	disabled, err := stringList(custom[CustomConfigDisabledRules])
+	if err != nil {
+		return settings, fmt.Errorf("%w: %s: %v", ErrInvalidInput, CustomConfigDisabledRules, err)
+	}
+	for _, id := range disabled {
+		if !settings.known(id) {
+			return settings, fmt.Errorf("%w: 未知的规则 %q", ErrInvalidInput, id)
+		}
+		settings.Disabled[id] = true
+	}
+
+	severities, err := stringMap(custom[CustomConfigRuleSeverity])
+	if err != nil {
+		return settings, fmt.Errorf("%w: %s: %v", ErrInvalidInput, CustomConfigRuleSeverity, err)
+	}
+	for _, id := range sortedStringKeys(severities) {
+		if !settings.known(id) {
+			return settings, fmt.Errorf("%w: 未知的规则 %q", ErrInvalidInput, id)
+		}
+		level, err := ParseSeverity(severities[id])
+		if err != nil {
+			return settings, fmt.Errorf("规则 %s: %w", id, err)
+		}
+		settings.Severity[id] = level
+	}
+	return settings, nil
+}
+
+// known 是否为内置规则或已加载的自定义规则
+func (s RuleSettings) known(id string) bool {
+	if _, ok := LookupRuleDoc(id); ok {
+		return true
+	}
+	for _, rule := range s.Custom {
+		if rule.ID() == id {
+			return true
+		}
+	}
+	return false
+}
+
+// enabled 规则是否注册
+func (s RuleSettings) enabled(id string) bool {
+	return !s.Disabled[id]
+}
+
+// severity 规则生效的严重程度，没有覆盖时为 fallback
+func (s RuleSettings) severity(id, fallback string) string {
+	if level, ok := s.Severity[id]; ok {
+		return level
+	}
+	return fallback
+}
+
+// stringList 读取 []string 配置，兼容从 JSON 解码得到的 []any
+func stringList(v any) ([]string, error) {
+	switch val := v.(type) {
+	case nil:
+		return nil, nil
+	case []string:
+		return val, nil
+	case []any:
+		list := make([]string, 0, len(val))
+		for _, item := range val {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("应为字符串列表，包含 %v", item)
+			}
+			list = append(list, strings.TrimSpace(s))
+		}
+		return list, nil
+	}
+	return nil, fmt.Errorf("应为字符串列表，实际为 %T", v)
+}
+
+// stringMap 读取 map[string]string 配置，兼容从 JSON 解码得到的 map[string]any
+func stringMap(v any) (map[string]string, error) {
+	switch val := v.(type) {
+	case nil:
+		return nil, nil
+	case map[string]string:
+		return val, nil
+	case map[string]any:
+		m := make(map[string]string, len(val))
+		for k, item := range val {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s 的值应为字符串，实际为 %v", k, item)
+			}
+			m[k] = s
+		}
+		return m, nil
+	}
+	return nil, fmt.Errorf("应为字符串映射，实际为 %T", v)
+}
+
+// sortedStringKeys 按字母序返回键，使报错顺序稳定
+func sortedStringKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// 测试从 CustomConfig 读取规则设置
+func TestRuleSettingsFromConfig(t *testing.T) {
+	settings, err := RuleSettingsFromConfig(map[string]any{
+		CustomConfigDisabledRules: []any{"B104", "G107"},
+		CustomConfigRuleSeverity:  map[string]any{"B103": "medium"},
+	})
+	if err != nil {
+		t.Fatalf("读取规则设置失败: %v", err)
+	}
+	if settings.enabled("B104") || settings.enabled("G107") || !settings.enabled("B101") {
+		t.Errorf("规则开关错误: %+v", settings.Disabled)
+	}
+	if got := settings.severity("B103", "Low"); got != "Medium" {
+		t.Errorf("B103 严重程度 = %q, want Medium", got)
+	}
+	if got := settings.severity("B101", "High"); got != "High" {
+		t.Errorf("没有覆盖的规则应保持原严重程度，实际 %q", got)
+	}
+
+	for name, custom := range map[string]map[string]any{
+		"未知规则":   {CustomConfigDisabledRules: []string{"B999"}},
+		"未知严重程度": {CustomConfigRuleSeverity: map[string]string{"B103": "urgent"}},
+		"类型错误":   {CustomConfigDisabledRules: "B104"},
+	} {
+		if _, err := RuleSettingsFromConfig(custom); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("%s: 应该返回 ErrInvalidInput，实际 %v", name, err)
+		}
+	}
+}
+
+// 测试注册时按配置关闭规则和覆盖严重程度
+func TestRuleSettings_Configure(t *testing.T) {
+	code := `package main
+
+import "os"
+
+func main() {
+	_ = os.Remove("a")
+	switch len(os.Args) {
+	case 1:
+	}
+}
+`
+	config := DefaultToolConfig("bug_detector")
+	config.CustomConfig[CustomConfigDisabledRules] = []string{"B101"}
+	config.CustomConfig[CustomConfigRuleSeverity] = map[string]string{"B103": "Critical"}
+
+	tm := NewToolManager(nil)
+	detector := NewBugDetector()
+	if err := tm.Register(detector, config); err != nil {
+		t.Fatalf("注册失败: %v", err)
+	}
+	result, err := detector.Execute(context.Background(), BugDetectorInput{Code: code})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var foundB103 bool
+	for _, bug := range result.Bugs {
+		switch bug.RuleID {
+		case "B101":
+			t.Error("关闭的规则 B101 不应报告问题")
+		case "B103":
+			foundB103 = true
+			if bug.Severity != "Critical" {
+				t.Errorf("B103 严重程度 = %q, want Critical", bug.Severity)
+			}
+		}
+	}
+	if !foundB103 {
+		t.Error("应该检测到 B103")
+	}
+
+	config.CustomConfig[CustomConfigDisabledRules] = []string{"X1"}
+	if err := NewToolManager(nil).Register(NewSecurityScanner(), config); err == nil {
+		t.Error("规则配置无效时注册应该失败")
+	}
+}
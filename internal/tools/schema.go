@@ -0,0 +1,251 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// stringInputField 输入类型为 string 的工具在 JSON Schema 中包装成的字段名
+// LLM 函数调用的参数必须是对象，字符串输入以 {"input": "..."} 形式传入
+const stringInputField = "input"
+
+// 工具结果的 JSON 结构版本（v1 结构），写在结果的 schema_version 字段中；--schema v2 时为 SchemaVersionV2
+// 字段改名、删除或改变类型时递增；只新增字段时不变，下游按版本号判断能否解析
+// 结构变化会使 testdata/schemas 下的快照测试失败，更新快照（go test -run TestResultSchemas -update）时同时确认是否需要递增版本
+const (
+	BugResultSchemaVersion        = 1
+	SecurityResultSchemaVersion   = 1
+	ComplexityResultSchemaVersion = 1
+)
+
+// OutputSchema 根据结果类型生成 JSON Schema，字段规则与 InputSchema 相同
+func OutputSchema(t reflect.Type) map[string]any {
+	return typeSchema(t, map[reflect.Type]bool{})
+}
+
+// InputSchema 根据输入类型生成 JSON Schema
+// 字段名取 json 标签；jsonschema 标签声明必填、枚举和说明，例如
+//
+//	`jsonschema:"required,enum=sequence|flowchart,description=图示类型"`
+//
+// description 必须放在最后，其内容可以包含逗号
+func InputSchema(t reflect.Type) map[string]any {
+	if t == nil {
+		return map[string]any{"type": "object", "properties": map[string]any{}}
+	}
+	if t.Kind() == reflect.String {
+		return map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				stringInputField: map[string]any{"type": "string", "description": "工具输入（代码或路径）"},
+			},
+			"required": []string{stringInputField},
+		}
+	}
+	return typeSchema(t, map[reflect.Type]bool{})
+}
+
+// typeSchema 生成单个类型的 JSON Schema，seen 用于避免递归类型无限展开
+func typeSchema(t reflect.Type, seen map[reflect.Type]bool) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem(), seen)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem(), seen)}
+	case reflect.Struct:
+		if seen[t] {
+			return map[string]any{"type": "object"}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+		return structSchema(t, seen)
+	default:
+		return map[string]any{}
+	}
+}
+
+// structSchema 生成结构体的 JSON Schema，字段规则与 encoding/json 一致
+func structSchema(t reflect.Type, seen map[reflect.Type]bool) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := typeSchema(field.Type, seen)
+		opts := parseSchemaTag(field.Tag.Get("jsonschema"))
+		if opts.description != "" {
+			schema["description"] = opts.description
+		}
+		if len(opts.enum) > 0 {
+			schema["enum"] = opts.enum
+		}
+		if opts.required {
+			required = append(required, name)
+		}
+		properties[name] = schema
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// schemaTag jsonschema 标签的内容
+type schemaTag struct {
+	required    bool
+	enum        []string
+	description string
+}
+
+// parseSchemaTag 解析 jsonschema 标签
+func parseSchemaTag(tag string) schemaTag {
+	var opts schemaTag
+	for tag != "" {
+		if strings.HasPrefix(tag, "description=") {
+			opts.description = strings.TrimPrefix(tag, "description=")
+			break
+		}
+		var part string
+		part, tag, _ = strings.Cut(tag, ",")
+		switch {
+		case part == "required":
+			opts.required = true
+		case strings.HasPrefix(part, "enum="):
+			opts.enum = strings.Split(strings.TrimPrefix(part, "enum="), "|")
+		}
+	}
+	return opts
+}
+
+// DecodeInput 把 JSON 参数解码为工具的输入类型，供 LLM 函数调用和 HTTP 接口使用
+// string 输入的工具接受 {"input": "..."}
+func DecodeInput(t reflect.Type, arguments string) (any, error) {
+	if strings.TrimSpace(arguments) == "" {
+		arguments = "{}"
+	}
+	if t.Kind() == reflect.String {
+		var wrapped map[string]string
+		if err := json.Unmarshal([]byte(arguments), &wrapped); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		return wrapped[stringInputField], nil
+	}
+	value := reflect.New(t)
+	if err := json.Unmarshal([]byte(arguments), value.Interface()); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	return value.Elem().Interface(), nil
+}
+
+// ToolSchema 工具的名称、描述和输入 JSON Schema
+type ToolSchema struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+// Schema 返回指定工具的输入 JSON Schema
+func (tm *ToolManager) Schema(name string) (*ToolSchema, error) {
+	tool, _, err := tm.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return &ToolSchema{
+		Name:        tool.Name(),
+		Description: tool.Description(),
+		InputSchema: InputSchema(tool.InputType()),
+	}, nil
+}
+
+// Schemas 返回所有已启用工具的输入 JSON Schema，按名称排序
+func (tm *ToolManager) Schemas() []ToolSchema {
+	var schemas []ToolSchema
+	names := tm.List()
+	sort.Strings(names)
+	for _, name := range names {
+		if schema, err := tm.Schema(name); err == nil {
+			schemas = append(schemas, *schema)
+		}
+	}
+	return schemas
+}
+
+// LLMTools 把已启用的工具转换为 LLM 函数调用定义，注册到对话模型
+func (tm *ToolManager) LLMTools() []llms.Tool {
+	var defs []llms.Tool
+	for _, schema := range tm.Schemas() {
+		defs = append(defs, llms.Tool{
+			Type: "function",
+			Function: &llms.FunctionDefinition{
+				Name:        schema.Name,
+				Description: schema.Description,
+				Parameters:  schema.InputSchema,
+			},
+		})
+	}
+	return defs
+}
+
+// RunJSON 以 JSON 参数执行工具，参数按工具的输入类型解码
+func (tm *ToolManager) RunJSON(ctx context.Context, name, arguments string) (*ToolResult, error) {
+	tool, _, err := tm.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	input, err := DecodeInput(tool.InputType(), arguments)
+	if err != nil {
+		return nil, err
+	}
+	return tm.Run(ctx, name, input)
+}
+
+// CallJSON 以 JSON 参数执行工具，返回结果文本；工具失败时返回错误
+// 签名与 ai.SourceInsightEngine.ToolRunner 一致，配合 LLMTools 注册给对话模型
+func (tm *ToolManager) CallJSON(ctx context.Context, name, arguments string) (string, error) {
+	result, err := tm.RunJSON(ctx, name, arguments)
+	if err != nil {
+		return "", err
+	}
+	if !result.Success {
+		return "", fmt.Errorf("%w: %s", ErrToolExecution, result.Error)
+	}
+	return result.Result, nil
+}
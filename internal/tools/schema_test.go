@@ -0,0 +1,210 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// updateSchemas 重新生成 testdata/schemas 下的结果结构快照
+var updateSchemas = flag.Bool("update", false, "更新 testdata/schemas 下的结果结构快照")
+
+type schemaSample struct {
+	Directory string            `json:"directory" jsonschema:"required,description=扫描目录，相对或绝对路径"`
+	Kind      string            `json:"kind,omitempty" jsonschema:"enum=a|b"`
+	Depth     int               `json:"depth,omitempty"`
+	Files     []string          `json:"files,omitempty"`
+	Labels    map[string]int    `json:"labels,omitempty"`
+	Nested    *schemaSampleNode `json:"nested,omitempty"`
+	Skipped   string            `json:"-"`
+	Plain     bool
+	hidden    string
+}
+
+type schemaSampleNode struct {
+	Name     string             `json:"name"`
+	Children []schemaSampleNode `json:"children"`
+}
+
+// 测试根据结构体生成 JSON Schema
+func TestInputSchema(t *testing.T) {
+	schema := InputSchema(reflect.TypeOf(schemaSample{}))
+	props := schema["properties"].(map[string]any)
+
+	for _, name := range []string{"directory", "kind", "depth", "files", "labels", "nested", "Plain"} {
+		if _, ok := props[name]; !ok {
+			t.Errorf("缺少字段 %s", name)
+		}
+	}
+	for _, name := range []string{"Skipped", "-", "hidden"} {
+		if _, ok := props[name]; ok {
+			t.Errorf("不应包含字段 %s", name)
+		}
+	}
+	if !reflect.DeepEqual(schema["required"], []string{"directory"}) {
+		t.Errorf("必填字段错误: %v", schema["required"])
+	}
+
+	dir := props["directory"].(map[string]any)
+	if dir["type"] != "string" || dir["description"] != "扫描目录，相对或绝对路径" {
+		t.Errorf("directory 字段错误: %v", dir)
+	}
+	if kind := props["kind"].(map[string]any); !reflect.DeepEqual(kind["enum"], []string{"a", "b"}) {
+		t.Errorf("kind 枚举错误: %v", kind)
+	}
+	if depth := props["depth"].(map[string]any); depth["type"] != "integer" {
+		t.Errorf("depth 类型错误: %v", depth)
+	}
+	if files := props["files"].(map[string]any); files["type"] != "array" || files["items"].(map[string]any)["type"] != "string" {
+		t.Errorf("files 类型错误: %v", files)
+	}
+	if labels := props["labels"].(map[string]any); labels["additionalProperties"].(map[string]any)["type"] != "integer" {
+		t.Errorf("labels 类型错误: %v", labels)
+	}
+
+	// 递归类型不会无限展开
+	nested := props["nested"].(map[string]any)
+	children := nested["properties"].(map[string]any)["children"].(map[string]any)
+	if children["items"].(map[string]any)["type"] != "object" {
+		t.Errorf("递归类型展开错误: %v", children)
+	}
+}
+
+// 测试字符串输入包装为对象
+func TestInputSchema_String(t *testing.T) {
+	schema := InputSchema(reflect.TypeOf(""))
+	if schema["type"] != "object" || !reflect.DeepEqual(schema["required"], []string{"input"}) {
+		t.Errorf("字符串输入的 Schema 错误: %v", schema)
+	}
+}
+
+// 测试按输入类型解码 JSON 参数
+func TestDecodeInput(t *testing.T) {
+	input, err := DecodeInput(reflect.TypeOf(schemaSample{}), `{"directory": "./x", "depth": 3}`)
+	if err != nil {
+		t.Fatalf("解码失败: %v", err)
+	}
+	sample, ok := input.(schemaSample)
+	if !ok || sample.Directory != "./x" || sample.Depth != 3 {
+		t.Errorf("解码结果错误: %#v", input)
+	}
+
+	input, err = DecodeInput(reflect.TypeOf(""), `{"input": "package main"}`)
+	if err != nil || input != "package main" {
+		t.Errorf("字符串输入解码错误: %v %v", input, err)
+	}
+
+	if _, err := DecodeInput(reflect.TypeOf(schemaSample{}), `{"depth": "x"}`); err == nil {
+		t.Error("类型不符应该返回错误")
+	}
+}
+
+// 测试以 JSON 参数执行工具并导出函数定义
+func TestToolManager_RunJSON(t *testing.T) {
+	tm := NewToolManager(NewNoopLogger())
+	tm.Register(NewMockTool("echo", func(ctx context.Context, input any) (string, error) {
+		return "echo: " + input.(string), nil
+	}), DefaultToolConfig("echo"))
+
+	out, err := tm.CallJSON(context.Background(), "echo", `{"input": "hi"}`)
+	if err != nil || out != "echo: hi" {
+		t.Errorf("CallJSON 结果错误: %q %v", out, err)
+	}
+	if _, err := tm.CallJSON(context.Background(), "echo", `{}`); err == nil {
+		t.Error("空输入应该返回错误")
+	}
+
+	defs := tm.LLMTools()
+	if len(defs) != 1 || defs[0].Function.Name != "echo" || defs[0].Function.Parameters == nil {
+		t.Errorf("函数定义错误: %+v", defs)
+	}
+
+	tm.Disable("echo")
+	if len(tm.Schemas()) != 0 {
+		t.Error("禁用的工具不应导出 Schema")
+	}
+}
+
+// 结果结构快照测试：BugResult、SecurityResult、ComplexityResult 的 JSON 结构与 testdata/schemas 下的快照一致
+// 字段改名、删除或改变类型会使测试失败，防止下游在不知情的情况下被破坏。
+// 确认变化后用 go test ./internal/tools -run TestResultSchemas -update 更新快照；不兼容的变化同时递增对应的 SchemaVersion
+func TestResultSchemas(t *testing.T) {
+	cases := []struct {
+		name    string
+		typ     reflect.Type
+		version int
+	}{
+		{"bug_result", reflect.TypeOf(BugResult{}), BugResultSchemaVersion},
+		{"security_result", reflect.TypeOf(SecurityResult{}), SecurityResultSchemaVersion},
+		{"complexity_result", reflect.TypeOf(ComplexityResult{}), ComplexityResultSchemaVersion},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			snapshot := map[string]any{
+				"schema_version": tc.version,
+				"schema":         OutputSchema(tc.typ),
+			}
+			got, err := json.MarshalIndent(snapshot, "", "  ")
+			if err != nil {
+				t.Fatalf("序列化结构失败: %v", err)
+			}
+			got = append(got, '\n')
+
+			path := filepath.Join("testdata", "schemas", tc.name+".json")
+			if *updateSchemas {
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("读取快照失败（首次运行请加 -update）: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s 的 JSON 结构与快照 %s 不一致；确认变化后加 -update 更新快照，不兼容的变化需要递增 SchemaVersion\n当前结构:\n%s", tc.typ.Name(), path, got)
+			}
+		})
+	}
+}
+
+// 测试工具结果中带有结构版本
+func TestResultSchemaVersion(t *testing.T) {
+	code := "package main\n\nfunc main() {}\n"
+
+	complexity, err := NewComplexityAnalyzer().Execute(context.Background(), ComplexityInput{Code: code})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if complexity.SchemaVersion != ComplexityResultSchemaVersion {
+		t.Errorf("复杂度结果的 schema_version = %d, want %d", complexity.SchemaVersion, ComplexityResultSchemaVersion)
+	}
+
+	security, err := NewSecurityScanner().Execute(context.Background(), SecurityScanInput{Code: code})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if security.SchemaVersion != SecurityResultSchemaVersion {
+		t.Errorf("安全扫描结果的 schema_version = %d, want %d", security.SchemaVersion, SecurityResultSchemaVersion)
+	}
+
+	data, err := json.Marshal(security)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields["schema_version"] != float64(SecurityResultSchemaVersion) {
+		t.Errorf("JSON 输出中缺少 schema_version: %s", data)
+	}
+}
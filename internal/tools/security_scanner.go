@@ -2,51 +2,197 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
-	"reflect"
+	"go-ai-study/internal/ai"
+	"go-ai-study/internal/i18n"
 	"strings"
 )
 
 // SecurityScanner 安全扫描器
 // 检测 Go 代码中的安全漏洞和风险（纯检测，不自动修复）
 type SecurityScanner struct {
-	*BaseTool
+	*TypedTool[SecurityScanInput, *SecurityResult]
 	ruleEngine *RuleEngine
 }
 
 // NewSecurityScanner 创建安全扫描器
 func NewSecurityScanner() *SecurityScanner {
-	scanner := &SecurityScanner{
-		BaseTool: NewBaseTool(
-			"security_scanner",
-			"检测 Go 代码中的安全漏洞和风险（硬编码密钥、SQL 注入、不安全随机数等）",
-			reflect.TypeOf(""),
-		),
-	}
+	scanner := &SecurityScanner{}
+	scanner.TypedTool = NewTypedTool[SecurityScanInput, *SecurityResult](
+		"security_scanner",
+		"检测 Go 代码中的安全漏洞和风险（硬编码密钥、SQL 注入、不安全随机数等）",
+		scanner,
+	)
 	scanner.ruleEngine = NewRuleEngine()
 	scanner.ruleEngine.RegisterAllRules()
 	return scanner
 }
 
-// Run 执行安全扫描
-func (ss *SecurityScanner) Run(ctx context.Context, input any) (string, error) {
-	// 类型断言
+// Configure 按 ToolConfig.CustomConfig 中的规则设置重新注册规则，并注册 YAML 自定义规则
+func (ss *SecurityScanner) Configure(config ToolConfig) error {
+	settings, err := RuleSettingsFromConfig(config.CustomConfig)
+	if err != nil {
+		return err
+	}
+	engine := NewRuleEngine()
+	engine.Settings = settings
+	engine.RegisterAllRules()
+	for _, rule := range settings.Custom {
+		engine.Register(rule)
+	}
+	ss.ruleEngine = engine
+	return nil
+}
+
+// SecurityScanInput 安全扫描输入
+// 与 BugDetectorInput 一致：Files 优先于 Directory，两者都为空时扫描 Code
+type SecurityScanInput struct {
+	Code      string   `json:"code,omitempty"`      // 单文件代码内容
+	File      string   `json:"file,omitempty"`      // 代码所在文件路径，用于问题定位和生成稳定 ID
+	Files     []string `json:"files,omitempty"`     // 多个文件路径
+	Directory string   `json:"directory,omitempty"` // 目录路径
+
+	MinConfidence string   `json:"min_confidence,omitempty"` // 只报告不低于该置信度的问题：high、medium、low，为空不过滤
+	Categories    []string `json:"categories,omitempty"`     // 只报告这些类别的问题（见 Categories），为空不过滤
+
+	// Snapshot 在结果中记录扫描的文件集合和内容哈希，SnapshotArchive 不为空时同时打包为 tar.gz（隐含 Snapshot）。
+	// 只对 Files、Directory 输入生效
+	Snapshot        bool   `json:"snapshot,omitempty"`
+	SnapshotArchive string `json:"snapshot_archive,omitempty"`
+}
+
+// ConvertInput 兼容代码字符串输入
+func (ss *SecurityScanner) ConvertInput(input any) (SecurityScanInput, bool) {
 	code, ok := input.(string)
-	if !ok {
-		return "", fmt.Errorf("输入类型错误: 期望 string, 实际 %T", input)
+	return SecurityScanInput{Code: code}, ok
+}
+
+// ValidateInput 验证输入：代码、文件列表、目录至少指定一个
+func (ss *SecurityScanner) ValidateInput(input SecurityScanInput) error {
+	if input.Code == "" && len(input.Files) == 0 && input.Directory == "" {
+		return ErrInvalidInput
+	}
+	if err := validateCategories(input.Categories); err != nil {
+		return err
+	}
+	return validateMinConfidence(input.MinConfidence)
+}
+
+// Execute 执行安全扫描
+func (ss *SecurityScanner) Execute(ctx context.Context, input SecurityScanInput) (*SecurityResult, error) {
+	if len(input.Files) > 0 || input.Directory != "" {
+		return ss.scanFiles(ctx, input)
 	}
 
+	issues, suppressed, err := ss.scanCode(ctx, ai.NormalizeSource(input.Code), input.File, input.MinConfidence)
+	if err != nil {
+		return nil, err
+	}
+	issues = filterSecurityIssuesByCategory(issues, input.Categories)
+	result := SecurityResult{
+		SchemaVersion: SecurityResultSchemaVersion,
+		File:       input.File,
+		Total:      len(issues),
+		Issues:     issues,
+		Suppressed: suppressed,
+		Summary:    generateSecuritySummary(issues) + suppressedSummary(len(suppressed)),
+		Statistics: calculateSecurityStatistics(issues),
+	}
+	if outputSchemaV2(ctx) {
+		result.SchemaVersion = SchemaVersionV2
+	}
+	capSecurityResult(ctx, &result)
+
+	return &result, nil
+}
+
+// scanFiles 逐个扫描文件列表或目录中的 Go 文件，汇总为一个结果
+// 读取或解析失败的文件记入 ErrorFiles，不影响其他文件；每个文件扫描完成后上报流式事件
+func (ss *SecurityScanner) scanFiles(ctx context.Context, input SecurityScanInput) (*SecurityResult, error) {
+	files, skippedFiles, err := collectSourceFiles(ctx, input.Files, input.Directory, "安全扫描器仅支持 Go 语言")
+	if err != nil {
+		return nil, fmt.Errorf("文件收集失败: %w", err)
+	}
+
+	issues := make([]SecurityIssue, 0)
+	var suppressed []SecurityIssue
+	errorFiles := make([]FileStatus, 0)
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return nil, context.Cause(ctx)
+		}
+		content, err := readSource(file)
+		if err != nil {
+			errorFiles = append(errorFiles, FileStatus{Path: file, Language: "go", Status: "error", Reason: fmt.Sprintf("读取文件失败: %v", err)})
+			emitFileFindings(ctx, "security_scanner", file, nil, err)
+			continue
+		}
+		fileIssues, fileSuppressed, err := ss.scanCode(ctx, content, file, input.MinConfidence)
+		if ctx.Err() != nil {
+			return nil, context.Cause(ctx)
+		}
+		if err != nil {
+			errorFiles = append(errorFiles, FileStatus{Path: file, Language: "go", Status: "error", Reason: err.Error()})
+			emitFileFindings(ctx, "security_scanner", file, nil, err)
+			continue
+		}
+		fileIssues = filterSecurityIssuesByCategory(fileIssues, input.Categories)
+		if streaming(ctx) {
+			findings := make([]Finding, 0, len(fileIssues))
+			for _, issue := range fileIssues {
+				findings = append(findings, securityFinding(issue))
+			}
+			emitFileFindings(ctx, "security_scanner", file, findings, nil)
+		}
+		issues = append(issues, fileIssues...)
+		suppressed = append(suppressed, fileSuppressed...)
+	}
+
+	status := "success"
+	if len(errorFiles) > 0 {
+		status = "partial"
+	}
+	if skippedFiles == nil {
+		skippedFiles = make([]FileStatus, 0)
+	}
+	result := SecurityResult{
+		SchemaVersion: SecurityResultSchemaVersion,
+		File:          input.Directory,
+		Status:        status,
+		TotalFiles:    len(files) + len(skippedFiles),
+		AnalyzedFiles: len(files) - len(errorFiles),
+		SkippedFiles:  skippedFiles,
+		ErrorFiles:    errorFiles,
+		Total:         len(issues),
+		Issues:        issues,
+		Suppressed:    suppressed,
+		Summary:       i18n.T("security.summary.files", len(files)-len(errorFiles)) + generateSecuritySummary(issues) + suppressedSummary(len(suppressed)),
+		Statistics:    calculateSecurityStatistics(issues),
+	}
+	if outputSchemaV2(ctx) {
+		result.SchemaVersion = SchemaVersionV2
+	}
+	if input.Snapshot || input.SnapshotArchive != "" {
+		if result.Snapshot, err = takeSnapshot(ctx, input.Directory, files, input.SnapshotArchive); err != nil {
+			return nil, fmt.Errorf("记录扫描快照失败: %w", err)
+		}
+	}
+	capSecurityResult(ctx, &result)
+	return &result, nil
+}
+
+// scanCode 扫描一个文件的代码，返回报告的问题和被注释抑制的问题
+func (ss *SecurityScanner) scanCode(ctx context.Context, code, filename, minConfidence string) ([]SecurityIssue, []SecurityIssue, error) {
 	// 创建文件集
 	fset := token.NewFileSet()
 
 	// 解析 Go 代码
-	node, err := parser.ParseFile(fset, "", code, parser.ParseComments)
+	node, err := parser.ParseFile(fset, filename, code, parser.ParseComments)
 	if err != nil {
-		return "", fmt.Errorf("解析 Go 代码失败: %w", err)
+		return nil, nil, fmt.Errorf("解析 Go 代码失败: %w", err)
 	}
 
 	// 扫描安全问题
@@ -58,57 +204,76 @@ func (ss *SecurityScanner) Run(ctx context.Context, input any) (string, error) {
 		if n == nil {
 			return false
 		}
+		// 每进入一个函数声明检查一次 ctx，取消后停止遍历
+		if _, ok := n.(*ast.FuncDecl); ok && ctx.Err() != nil {
+			return false
+		}
+		ruleCtx.CurrentFunc = enterFunc(ruleCtx.CurrentFunc, n)
 
 		// 应用所有规则
 		for _, rule := range ss.ruleEngine.Rules {
 			if rule.Match(n, ruleCtx) {
-				issue := buildSecurityIssue(rule, n, fset, code)
+				issue := buildSecurityIssue(rule, n, fset, code, filename, ruleCtx.CurrentFunc)
+				issue.Severity = ss.ruleEngine.Settings.severity(rule.ID(), issue.Severity)
 				issues = append(issues, issue)
 			}
 		}
 		return true
 	})
+	if ctx.Err() != nil {
+		return nil, nil, context.Cause(ctx)
+	}
 
 	// 去重（同一位置可能被多个规则匹配）
 	issues = deduplicateIssues(issues)
-
-	// 构建结果
-	result := SecurityResult{
-		File:       "",
-		Total:      len(issues),
-		Issues:     issues,
-		Summary:    generateSecuritySummary(issues),
-		Statistics: calculateSecurityStatistics(issues),
-	}
-
-	// 序列化为 JSON
-	jsonBytes, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return "", fmt.Errorf("序列化结果失败: %w", err)
+	disambiguateIssueIDs(issues)
+	issues, suppressed := suppressSecurityIssues(issues, parseSuppressions(code))
+	issues = filterIssuesByConfidence(issues, minConfidence)
+	if outputSchemaV2(ctx) {
+		enrichSecurityIssuesV2(issues, code)
+		enrichSecurityIssuesV2(suppressed, code)
 	}
-
-	return string(jsonBytes), nil
+	return issues, suppressed, nil
 }
 
 // SecurityIssue 单个安全问题
 type SecurityIssue struct {
-	ID          string `json:"id"`           // 问题唯一标识
+	ID          string `json:"id"`           // 问题唯一标识（由文件、规则和代码片段决定，跨文件不冲突）
 	RuleID      string `json:"rule_id"`      // 规则ID
 	Severity    string `json:"severity"`     // 严重程度：Critical, High, Medium, Low
 	Category    string `json:"category"`     // 问题类别
 	Description string `json:"description"`  // 问题描述
 	File        string `json:"file"`         // 文件名
 	Line        int    `json:"line"`         // 行号
+	Column      int    `json:"column"`       // 列号（从 1 开始，按字节计）
 	Function    string `json:"function"`     // 所在函数
+	Receiver    string `json:"receiver,omitempty"` // 所在方法的接收者类型（如 *Server）
+	Exported    bool   `json:"exported"`     // 所在函数是否导出
 	CodeSnippet string `json:"code_snippet"` // 代码片段
 	Suggestion  string `json:"suggestion"`   // 修复建议
+	Confidence  string `json:"confidence"`   // 置信度：high, medium, low
+	SuppressionReason string `json:"suppression_reason,omitempty"` // 被 //insight:ignore 抑制时注释中写的原因
+
+	// 以下字段只在 --schema v2 中输出
+	Fingerprint string `json:"fingerprint,omitempty"` // 稳定指纹，不随行号移动而变化
+	Context     string `json:"context,omitempty"`     // 问题前后的代码（带行号）
+	CWE         string `json:"cwe,omitempty"`         // 对应的 CWE 编号
 }
 
 // SecurityResult 完整的安全扫描结果
 type SecurityResult struct {
-	File       string          `json:"file"`       // 文件名
+	SchemaVersion int          `json:"schema_version"` // 结果结构版本，见 SecurityResultSchemaVersion
+	File       string          `json:"file"`       // 文件名（扫描目录时为目录）
+	Status        string       `json:"status,omitempty"`         // 扫描文件列表或目录时的状态：success, partial
+	TotalFiles    int          `json:"total_files,omitempty"`    // 总文件数
+	AnalyzedFiles int          `json:"analyzed_files,omitempty"` // 扫描的 Go 文件数
+	SkippedFiles  []FileStatus `json:"skipped_files,omitempty"`  // 跳过的文件（非 Go 文件）
+	ErrorFiles    []FileStatus `json:"error_files,omitempty"`    // 读取或解析失败的文件
 	Total      int             `json:"total"`      // 总问题数
 	Issues     []SecurityIssue `json:"issues"`     // 所有问题
+	Suppressed []SecurityIssue `json:"suppressed,omitempty"` // 被 //insight:ignore 注释抑制的问题，不计入 Total
+	Truncated  *Truncation     `json:"truncated,omitempty"`  // 超出问题数量上限没有列出的问题，仍计入 Total 和 Statistics
+	Snapshot   *Snapshot       `json:"snapshot,omitempty"`   // 扫描的文件集合和内容哈希（Snapshot 时）
 	Summary    string          `json:"summary"`    // 摘要
 	Statistics SecurityStats   `json:"statistics"` // 统计信息
 }
@@ -120,17 +285,20 @@ type SecurityStats struct {
 	High        int `json:"high"`          // 高危问题
 	Medium      int `json:"medium"`        // 中危问题
 	Low         int `json:"low"`           // 低危问题
+	ByCategory  map[string]int `json:"by_category,omitempty"` // 各类别的问题数
 }
 
 // RuleContext 规则检测上下文
 type RuleContext struct {
 	FSet      *token.FileSet
-	CurrentFunc *ast.FuncDecl
+	CurrentFunc *ast.FuncDecl // 遍历中当前节点所在的函数声明，不在函数中时为 nil
 }
 
 // RuleEngine 规则引擎
+// 规则只在构造时注册，之后只读，可被并发的检测共享
 type RuleEngine struct {
-	Rules []SecurityRule
+	Rules    []SecurityRule
+	Settings RuleSettings // 注册前参考的规则开关和严重程度覆盖
 }
 
 // NewRuleEngine 创建规则引擎
@@ -140,13 +308,24 @@ func NewRuleEngine() *RuleEngine {
 	}
 }
 
-// Register 注册规则
+// Register 注册规则，配置中关闭的规则跳过
 func (re *RuleEngine) Register(rule SecurityRule) {
+	if !re.Settings.enabled(rule.ID()) {
+		return
+	}
 	re.Rules = append(re.Rules, rule)
 }
 
-// RegisterAllRules 注册所有默认规则
+// RegisterAllRules 注册所有默认规则，以及经由 RegisterSecurityRule 或规则插件注册的外部规则
 func (re *RuleEngine) RegisterAllRules() {
+	re.registerBuiltinRules()
+	for _, rule := range externalSecurityRules() {
+		re.Register(rule)
+	}
+}
+
+// registerBuiltinRules 注册所有默认规则
+func (re *RuleEngine) registerBuiltinRules() {
 	re.Register(&HardCodedSecretRule{})
 	re.Register(&SQLInjectionRule{})
 	re.Register(&WeakRandomRule{})
@@ -172,7 +351,7 @@ type HardCodedSecretRule struct{}
 
 func (r *HardCodedSecretRule) ID() string             { return "G101" }
 func (r *HardCodedSecretRule) Name() string           { return "Hardcoded Secrets" }
-func (r *HardCodedSecretRule) Category() string       { return "Credentials" }
+func (r *HardCodedSecretRule) Category() string       { return CategorySecrets }
 func (r *HardCodedSecretRule) Severity() string       { return "Critical" }
 func (r *HardCodedSecretRule) Description() string    { return "检测到硬编码的密码/密钥/Token" }
 func (r *HardCodedSecretRule) Suggestion() string     { return "使用环境变量或配置文件存储敏感信息（如 os.Getenv、viper）" }
@@ -211,7 +390,7 @@ type SQLInjectionRule struct{}
 
 func (r *SQLInjectionRule) ID() string          { return "G201" }
 func (r *SQLInjectionRule) Name() string        { return "SQL Injection" }
-func (r *SQLInjectionRule) Category() string    { return "Injection" }
+func (r *SQLInjectionRule) Category() string    { return CategoryInjection }
 func (r *SQLInjectionRule) Severity() string    { return "Critical" }
 func (r *SQLInjectionRule) Description() string { return "SQL 注入风险：使用字符串拼接构造 SQL 语句" }
 func (r *SQLInjectionRule) Suggestion() string  { return "使用参数化查询（Prepared Statement）或 ORM" }
@@ -248,7 +427,7 @@ type WeakRandomRule struct{}
 
 func (r *WeakRandomRule) ID() string          { return "G401" }
 func (r *WeakRandomRule) Name() string        { return "Use of Weak Random Number Generator" }
-func (r *WeakRandomRule) Category() string    { return "Cryptography" }
+func (r *WeakRandomRule) Category() string    { return CategoryCrypto }
 func (r *WeakRandomRule) Severity() string    { return "High" }
 func (r *WeakRandomRule) Description() string { return "使用不安全的随机数生成器（math/rand）" }
 func (r *WeakRandomRule) Suggestion() string  { return "使用 crypto/rand 代替 math/rand 用于密码学场景" }
@@ -277,7 +456,7 @@ type InfoDisclosureRule struct{}
 
 func (r *InfoDisclosureRule) ID() string          { return "G104" }
 func (r *InfoDisclosureRule) Name() string        { return "Information Disclosure" }
-func (r *InfoDisclosureRule) Category() string    { return "Data Privacy" }
+func (r *InfoDisclosureRule) Category() string    { return CategoryDataExposure }
 func (r *InfoDisclosureRule) Severity() string    { return "Medium" }
 func (r *InfoDisclosureRule) Description() string { return "敏感信息打印到日志/控制台" }
 func (r *InfoDisclosureRule) Suggestion() string  { return "避免打印密码、Token、个人隐私信息到日志" }
@@ -312,7 +491,7 @@ type WeakEncryptionRule struct{}
 
 func (r *WeakEncryptionRule) ID() string          { return "G501" }
 func (r *WeakEncryptionRule) Name() string        { return "Use of Weak Cryptographic Algorithm" }
-func (r *WeakEncryptionRule) Category() string    { return "Cryptography" }
+func (r *WeakEncryptionRule) Category() string    { return CategoryCrypto }
 func (r *WeakEncryptionRule) Severity() string    { return "High" }
 func (r *WeakEncryptionRule) Description() string { return "使用弱加密算法（MD5、SHA1、DES、RC4）" }
 func (r *WeakEncryptionRule) Suggestion() string  { return "使用强加密算法（SHA256、SHA512、AES、ChaCha20）" }
@@ -348,7 +527,7 @@ type InsecureFilePermRule struct{}
 
 func (r *InsecureFilePermRule) ID() string          { return "G302" }
 func (r *InsecureFilePermRule) Name() string        { return "Insecure File Permissions" }
-func (r *InsecureFilePermRule) Category() string    { return "File System" }
+func (r *InsecureFilePermRule) Category() string    { return CategoryPermissions }
 func (r *InsecureFilePermRule) Severity() string    { return "Medium" }
 func (r *InsecureFilePermRule) Description() string { return "文件权限过于宽松（如 0777）" }
 func (r *InsecureFilePermRule) Suggestion() string  { return "使用更严格的文件权限（如 0600、0644）" }
@@ -383,7 +562,7 @@ type InsecureHTTPRule struct{}
 
 func (r *InsecureHTTPRule) ID() string          { return "G107" }
 func (r *InsecureHTTPRule) Name() string        { return "Insecure HTTP Request" }
-func (r *InsecureHTTPRule) Category() string    { return "Network Security" }
+func (r *InsecureHTTPRule) Category() string    { return CategoryNetwork }
 func (r *InsecureHTTPRule) Severity() string    { return "Medium" }
 func (r *InsecureHTTPRule) Description() string { return "使用 HTTP 而非 HTTPS" }
 func (r *InsecureHTTPRule) Suggestion() string  { return "使用 HTTPS 进行安全通信" }
@@ -450,7 +629,7 @@ func isPrintFunction(callExpr *ast.CallExpr) bool {
 }
 
 // 辅助函数：构建安全问题
-func buildSecurityIssue(rule SecurityRule, node ast.Node, fset *token.FileSet, code string) SecurityIssue {
+func buildSecurityIssue(rule SecurityRule, node ast.Node, fset *token.FileSet, code, filename string, fn *ast.FuncDecl) SecurityIssue {
 	position := fset.Position(node.Pos())
 	line := position.Line
 
@@ -464,29 +643,32 @@ func buildSecurityIssue(rule SecurityRule, node ast.Node, fset *token.FileSet, c
 		}
 	}
 
-	// 查找所在函数
-	var funcName string
-	ast.Inspect(node, func(n ast.Node) bool {
-		if fn, ok := n.(*ast.FuncDecl); ok {
-			if fn.Pos() < node.Pos() && node.Pos() < fn.End() {
-				funcName = fn.Name.Name
-				return false
-			}
-		}
-		return true
-	})
+	funcName, receiver, exported := funcMetadata(fn)
+
+	// 确定置信度
+	confidence := ConfidenceMedium
+	switch rule.ID() {
+	case "G501", "G302": // 明确的调用和字面量
+		confidence = ConfidenceHigh
+	case "G104": // 按变量名推测，容易误报
+		confidence = ConfidenceLow
+	}
 
 	return SecurityIssue{
-		ID:          fmt.Sprintf("sec-%d", position.Offset),
+		ID:          "sec-" + FindingFingerprint(rule.ID(), filename, codeSnippet),
 		RuleID:      rule.ID(),
 		Severity:    rule.Severity(),
-		Category:    rule.Category(),
-		Description: rule.Description(),
-		File:        "",
+		Category:    NormalizeCategory(rule.Category()),
+		Description: ruleText(rule.ID(), "description", rule.Description()),
+		File:        filename,
 		Line:        line,
+		Column:      position.Column,
 		Function:    funcName,
+		Receiver:    receiver,
+		Exported:    exported,
 		CodeSnippet: codeSnippet,
-		Suggestion:  rule.Suggestion(),
+		Suggestion:  ruleText(rule.ID(), "suggestion", rule.Suggestion()),
+		Confidence:  confidence,
 	}
 }
 
@@ -506,10 +688,37 @@ func deduplicateIssues(issues []SecurityIssue) []SecurityIssue {
 	return result
 }
 
+// 辅助函数：同一文件中规则和代码片段都相同的问题按出现顺序追加序号，保证 ID 唯一
+func disambiguateIssueIDs(issues []SecurityIssue) {
+	seen := make(map[string]int)
+	for i := range issues {
+		id := issues[i].ID
+		if n := seen[id]; n > 0 {
+			issues[i].ID = "sec-" + FindingFingerprint(issues[i].RuleID, issues[i].File,
+				fmt.Sprintf("%s#%d", issues[i].CodeSnippet, n))
+		}
+		seen[id]++
+	}
+}
+
+// 辅助函数：只保留置信度不低于 min 的问题
+func filterIssuesByConfidence(issues []SecurityIssue, min string) []SecurityIssue {
+	if min == "" {
+		return issues
+	}
+	kept := issues[:0]
+	for _, issue := range issues {
+		if meetsConfidence(issue.Confidence, min) {
+			kept = append(kept, issue)
+		}
+	}
+	return kept
+}
+
 // 辅助函数：生成安全摘要
 func generateSecuritySummary(issues []SecurityIssue) string {
 	if len(issues) == 0 {
-		return "✅ 未检测到安全问题"
+		return i18n.T("security.summary.none")
 	}
 
 	// 统计各级别数量
@@ -531,7 +740,7 @@ func generateSecuritySummary(issues []SecurityIssue) string {
 	}
 
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("检测到 %d 个安全问题", len(issues)))
+	sb.WriteString(i18n.T("security.summary.found", len(issues)))
 
 	parts := []string{}
 	if critical > 0 {
@@ -548,18 +757,22 @@ func generateSecuritySummary(issues []SecurityIssue) string {
 	}
 
 	if len(parts) > 0 {
-		sb.WriteString("（")
-		sb.WriteString(strings.Join(parts, ", "))
-		sb.WriteString("）")
+		sb.WriteString(i18n.T("security.summary.levels", strings.Join(parts, ", ")))
 	}
 
 	return sb.String()
 }
 
+// filterSecurityIssuesByCategory 只保留属于指定类别的安全问题
+func filterSecurityIssuesByCategory(issues []SecurityIssue, categories []string) []SecurityIssue {
+	return filterByCategory(issues, categories, func(i SecurityIssue) string { return i.Category })
+}
+
 // 辅助函数：计算安全统计
 func calculateSecurityStatistics(issues []SecurityIssue) SecurityStats {
 	stats := SecurityStats{
 		TotalIssues: len(issues),
+		ByCategory:  countByCategory(issues, func(i SecurityIssue) string { return i.Category }),
 	}
 
 	for _, issue := range issues {
@@ -3,6 +3,9 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -336,6 +339,11 @@ func PostData(data string) error {
 	}
 }
 
+// 测试安全规则的夹具
+func TestSecurityScanner_RuleFixtures(t *testing.T) {
+	testRuleFixtures(t, "security", ruleOptions{}, "G101", "G201", "G401", "G104", "G501", "G302", "G107")
+}
+
 // 测试安全代码（无问题）
 func TestSecurityScanner_SafeCode(t *testing.T) {
 	scanner := NewSecurityScanner()
@@ -652,3 +660,180 @@ func Login(username, password string) bool {
 	}
 	t.Log("\n=====================================")
 }
+
+// 测试问题带有文件、列号，ID 跨文件不冲突且不随行号变化
+func TestSecurityScanner_FileAttribution(t *testing.T) {
+	scanner := NewSecurityScanner()
+	ctx := context.Background()
+
+	code := `package main
+
+func Login() {
+	password := "admin123"
+	_ = password
+}
+
+func Logout() {
+	password := "admin123"
+	_ = password
+}
+`
+	scan := func(file, code string) SecurityResult {
+		t.Helper()
+		result, err := scanner.Run(ctx, SecurityScanInput{Code: code, File: file})
+		if err != nil {
+			t.Fatalf("扫描失败: %v", err)
+		}
+		var analysis SecurityResult
+		if err := json.Unmarshal([]byte(result), &analysis); err != nil {
+			t.Fatalf("解析结果失败: %v", err)
+		}
+		return analysis
+	}
+
+	a := scan("pkg/a.go", code)
+	b := scan("pkg/b.go", code)
+	if a.File != "pkg/a.go" || len(a.Issues) < 2 {
+		t.Fatalf("扫描结果错误: file=%q issues=%d", a.File, len(a.Issues))
+	}
+
+	ids := make(map[string]bool)
+	for _, issue := range append(a.Issues, b.Issues...) {
+		if ids[issue.ID] {
+			t.Errorf("问题 ID 重复: %s", issue.ID)
+		}
+		ids[issue.ID] = true
+	}
+	first := a.Issues[0]
+	if first.File != "pkg/a.go" || first.Line != 4 || first.Column != 2 {
+		t.Errorf("问题位置错误: %s:%d:%d", first.File, first.Line, first.Column)
+	}
+
+	// 插入空行后 ID 不变
+	moved := scan("pkg/a.go", strings.Replace(code, "func Login", "\n\nfunc Login", 1))
+	if moved.Issues[0].ID != first.ID || moved.Issues[0].Line != 6 {
+		t.Errorf("ID 随行号变化: %s(%d) -> %s(%d)", first.ID, first.Line, moved.Issues[0].ID, moved.Issues[0].Line)
+	}
+}
+
+// 测试安全问题带有置信度并可按最低置信度过滤
+func TestSecurityScanner_MinConfidence(t *testing.T) {
+	scanner := NewSecurityScanner()
+	ctx := context.Background()
+
+	code := `package main
+
+import (
+	"crypto/md5"
+	"fmt"
+)
+
+func Login(password string) {
+	apiKey := "sk-123456"
+	fmt.Println("Password:", password)
+	md5.New()
+	_ = apiKey
+}
+`
+	scan := func(min string) []SecurityIssue {
+		t.Helper()
+		result, err := scanner.Run(ctx, SecurityScanInput{Code: code, MinConfidence: min})
+		if err != nil {
+			t.Fatalf("扫描失败: %v", err)
+		}
+		var analysis SecurityResult
+		if err := json.Unmarshal([]byte(result), &analysis); err != nil {
+			t.Fatalf("解析结果失败: %v", err)
+		}
+		return analysis.Issues
+	}
+
+	want := map[string]string{"G101": ConfidenceMedium, "G104": ConfidenceLow, "G501": ConfidenceHigh}
+	all := scan("")
+	for _, issue := range all {
+		if c, ok := want[issue.RuleID]; ok && issue.Confidence != c {
+			t.Errorf("%s 置信度 = %s, want %s", issue.RuleID, issue.Confidence, c)
+		}
+	}
+
+	for min, rules := range map[string][]string{
+		ConfidenceHigh:   {"G501"},
+		ConfidenceMedium: {"G101", "G501"},
+		ConfidenceLow:    {"G101", "G104", "G501"},
+	} {
+		got := make(map[string]bool)
+		for _, issue := range scan(min) {
+			got[issue.RuleID] = true
+			if !meetsConfidence(issue.Confidence, min) {
+				t.Errorf("min=%s: %s(%s) 不应保留", min, issue.RuleID, issue.Confidence)
+			}
+		}
+		for _, id := range rules {
+			if !got[id] {
+				t.Errorf("min=%s: 缺少 %s", min, id)
+			}
+		}
+	}
+
+	if err := scanner.Validate(SecurityScanInput{Code: code, MinConfidence: "certain"}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("未知置信度应该返回 ErrInvalidInput，实际 %v", err)
+	}
+}
+
+// 测试扫描目录和文件列表：问题汇总到一个结果，非 Go 文件跳过，解析失败的文件记入 error_files
+func TestSecurityScanner_Directory(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.go":       "package main\n\nfunc A() {\n\tpassword := \"admin123\"\n\t_ = password\n}\n",
+		"sub/b.go":   "package sub\n\nimport \"crypto/md5\"\n\nfunc B() { md5.New() }\n",
+		"broken.go":  "package main\n\nfunc {\n",
+		"README.txt": "not go",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scanner := NewSecurityScanner()
+	scan := func(input SecurityScanInput) SecurityResult {
+		t.Helper()
+		result, err := scanner.Run(context.Background(), input)
+		if err != nil {
+			t.Fatalf("扫描失败: %v", err)
+		}
+		var analysis SecurityResult
+		if err := json.Unmarshal([]byte(result), &analysis); err != nil {
+			t.Fatalf("解析结果失败: %v", err)
+		}
+		return analysis
+	}
+
+	result := scan(SecurityScanInput{Directory: dir})
+	if result.Status != "partial" || result.AnalyzedFiles != 2 || len(result.ErrorFiles) != 1 {
+		t.Fatalf("目录扫描状态错误: status=%s analyzed=%d errors=%v", result.Status, result.AnalyzedFiles, result.ErrorFiles)
+	}
+	rules := map[string]string{}
+	for _, issue := range result.Issues {
+		rules[issue.RuleID] = filepath.Base(issue.File)
+	}
+	if rules["G101"] != "a.go" || rules["G501"] != "b.go" {
+		t.Errorf("问题没有汇总到结果中或文件归属错误: %v", rules)
+	}
+	if result.Total != len(result.Issues) {
+		t.Errorf("total = %d, want %d", result.Total, len(result.Issues))
+	}
+
+	result = scan(SecurityScanInput{Files: []string{filepath.Join(dir, "a.go"), filepath.Join(dir, "README.txt")}})
+	if result.Status != "success" || result.AnalyzedFiles != 1 || len(result.SkippedFiles) != 1 {
+		t.Errorf("文件列表扫描状态错误: status=%s analyzed=%d skipped=%v", result.Status, result.AnalyzedFiles, result.SkippedFiles)
+	}
+
+	if err := scanner.Validate(SecurityScanInput{}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("没有代码、文件和目录时应该返回 ErrInvalidInput，实际 %v", err)
+	}
+}
@@ -0,0 +1,279 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// SeverityAdjudicator 问题严重程度裁决器
+// 把低置信度的问题连同上下文分批交给对话模型，由模型决定保留、丢弃或提升严重程度，
+// 裁决结果（含理由）全部记录下来，减少误报进入报告
+type SeverityAdjudicator struct {
+	*TypedTool[AdjudicationRequest, *AdjudicationResult]
+	model  llms.Model
+	logger Logger
+}
+
+// NewSeverityAdjudicator 创建严重程度裁决器
+// model 为 nil 时执行会返回错误
+func NewSeverityAdjudicator(model llms.Model, logger Logger) *SeverityAdjudicator {
+	sa := &SeverityAdjudicator{
+		model:  model,
+		logger: logger,
+	}
+	sa.TypedTool = NewTypedTool[AdjudicationRequest, *AdjudicationResult](
+		"severity_adjudicator",
+		"让对话模型复核低置信度的问题，决定保留、丢弃或提升严重程度，并记录理由",
+		sa,
+	)
+	return sa
+}
+
+// 裁决结论
+const (
+	AdjudicationKeep  = "keep"  // 保留
+	AdjudicationDrop  = "drop"  // 判定为误报，丢弃
+	AdjudicationRaise = "raise" // 比规则给出的更严重，提升严重程度
+)
+
+// severityLevels 严重程度从低到高
+var severityLevels = []string{"Low", "Medium", "High", "Critical"}
+
+// AdjudicationRequest 裁决请求
+type AdjudicationRequest struct {
+	Findings     []Finding `json:"findings" jsonschema:"required"` // 待裁决的问题（通常来自 CollectFindings）
+	Confidence   string    `json:"confidence,omitempty"`           // 复核置信度不高于该级别的问题，默认 low
+	BatchSize    int       `json:"batch_size,omitempty"`           // 每次请求模型的问题数，默认 10
+	ContextLines int       `json:"context_lines,omitempty"`        // 问题行前后附带的代码行数，默认 5
+}
+
+// AdjudicationDecision 一个问题的裁决记录
+type AdjudicationDecision struct {
+	Fingerprint string `json:"fingerprint"`
+	RuleID      string `json:"rule_id"`
+	File        string `json:"file"`
+	Line        int    `json:"line"`
+	Decision    string `json:"decision"`               // keep, drop, raise
+	Severity    string `json:"severity"`               // 原严重程度
+	NewSeverity string `json:"new_severity,omitempty"` // 提升后的严重程度
+	Reason      string `json:"reason"`                 // 模型给出的理由
+}
+
+// AdjudicationResult 裁决结果
+type AdjudicationResult struct {
+	Findings  []Finding              `json:"findings"`  // 裁决后的问题（已去掉丢弃的，提升的已更新严重程度）
+	Decisions []AdjudicationDecision `json:"decisions"` // 每个被复核问题的裁决记录
+	Reviewed  int                    `json:"reviewed"`  // 复核的问题数
+	Dropped   int                    `json:"dropped"`   // 丢弃数
+	Raised    int                    `json:"raised"`    // 提升数
+	Summary   string                 `json:"summary"`
+}
+
+// ValidateInput 验证输入参数
+func (sa *SeverityAdjudicator) ValidateInput(req AdjudicationRequest) error {
+	if req.BatchSize < 0 || req.ContextLines < 0 {
+		return fmt.Errorf("%w: batch_size 和 context_lines 不能为负数", ErrInvalidInput)
+	}
+	return validateMinConfidence(req.Confidence)
+}
+
+// Execute 执行裁决
+func (sa *SeverityAdjudicator) Execute(ctx context.Context, req AdjudicationRequest) (*AdjudicationResult, error) {
+	if sa.model == nil {
+		return nil, fmt.Errorf("对话模型不可用，无法裁决严重程度")
+	}
+	threshold := req.Confidence
+	if threshold == "" {
+		threshold = ConfidenceLow
+	}
+	batchSize := req.BatchSize
+	if batchSize == 0 {
+		batchSize = 10
+	}
+	contextLines := req.ContextLines
+	if contextLines == 0 {
+		contextLines = 5
+	}
+
+	// 只复核置信度不高于阈值的问题，其余原样保留
+	var pending []int
+	for i, f := range req.Findings {
+		if confidenceRanks[f.Confidence] <= confidenceRanks[threshold] {
+			pending = append(pending, i)
+		}
+	}
+
+	result := &AdjudicationResult{Reviewed: len(pending)}
+	decisions := make(map[int]AdjudicationDecision, len(pending))
+	sources := make(map[string][]string)
+	for start := 0; start < len(pending); start += batchSize {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		end := min(start+batchSize, len(pending))
+		batch := pending[start:end]
+
+		verdicts, err := sa.adjudicateBatch(ctx, req.Findings, batch, sources, contextLines)
+		if err != nil {
+			// 模型失败时整批保留，不能因为裁决失败而漏报
+			sa.logger.Warn("严重程度裁决失败，保留该批问题", "error", err, "count", len(batch))
+			verdicts = make([]adjudicationVerdict, len(batch))
+		}
+		for n, idx := range batch {
+			decisions[idx] = newAdjudicationDecision(req.Findings[idx], verdicts[n], err)
+		}
+	}
+
+	for i, f := range req.Findings {
+		d, reviewed := decisions[i]
+		if reviewed {
+			result.Decisions = append(result.Decisions, d)
+			switch d.Decision {
+			case AdjudicationDrop:
+				result.Dropped++
+				continue
+			case AdjudicationRaise:
+				result.Raised++
+				f.Severity = d.NewSeverity
+			}
+		}
+		result.Findings = append(result.Findings, f)
+	}
+	result.Summary = fmt.Sprintf("复核 %d 个问题：保留 %d，丢弃 %d，提升 %d",
+		result.Reviewed, result.Reviewed-result.Dropped-result.Raised, result.Dropped, result.Raised)
+	return result, nil
+}
+
+// adjudicationVerdict 模型对单个问题的输出
+type adjudicationVerdict struct {
+	ID       int    `json:"id"`
+	Decision string `json:"decision"`
+	Severity string `json:"severity"`
+	Reason   string `json:"reason"`
+}
+
+// adjudicateBatch 让模型裁决一批问题，返回与 batch 一一对应的结论（模型漏掉的为空值）
+func (sa *SeverityAdjudicator) adjudicateBatch(ctx context.Context, findings []Finding, batch []int, sources map[string][]string, contextLines int) ([]adjudicationVerdict, error) {
+	var sb strings.Builder
+	for n, idx := range batch {
+		f := findings[idx]
+		sb.WriteString(fmt.Sprintf("### 问题 %d\n规则: %s（%s）\n严重程度: %s\n位置: %s:%d 函数 %s\n描述: %s\n代码:\n%s\n",
+			n+1, f.RuleID, f.Category, f.Severity, f.File, f.Line, f.Function, f.Description,
+			adjudicationContext(f, sources, contextLines)))
+	}
+
+	prompt := fmt.Sprintf(`你是一名资深 Go 代码审查者，正在复核静态分析工具给出的低置信度问题。
+请逐个判断下面的问题：
+- keep：确实是问题，严重程度合适；
+- drop：误报（例如错误已在别处处理、资源已被关闭、代码不可达）；
+- raise：确实是问题，而且比标注的严重程度更严重，请在 severity 中给出 Low、Medium、High 或 Critical。
+只输出一个 JSON 数组，每个元素形如 {"id": 1, "decision": "keep", "severity": "", "reason": "一句话理由"}，不要输出其他内容。
+
+%s`, sb.String())
+
+	answer, err := llms.GenerateFromSinglePrompt(ctx, sa.model, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("LLM 请求失败: %w", err)
+	}
+
+	var parsed []adjudicationVerdict
+	if err := json.Unmarshal([]byte(extractJSONArray(answer)), &parsed); err != nil {
+		return nil, fmt.Errorf("解析模型输出失败: %w", err)
+	}
+	verdicts := make([]adjudicationVerdict, len(batch))
+	for _, v := range parsed {
+		if v.ID >= 1 && v.ID <= len(batch) {
+			verdicts[v.ID-1] = v
+		}
+	}
+	return verdicts, nil
+}
+
+// newAdjudicationDecision 把模型结论规范化为裁决记录
+// 无法识别的结论按保留处理；提升时严重程度至少升高一级
+func newAdjudicationDecision(f Finding, v adjudicationVerdict, err error) AdjudicationDecision {
+	d := AdjudicationDecision{
+		Fingerprint: f.Fingerprint,
+		RuleID:      f.RuleID,
+		File:        f.File,
+		Line:        f.Line,
+		Decision:    AdjudicationKeep,
+		Severity:    f.Severity,
+		Reason:      strings.TrimSpace(v.Reason),
+	}
+	if err != nil {
+		d.Reason = fmt.Sprintf("裁决失败，保留: %v", err)
+		return d
+	}
+
+	switch strings.ToLower(strings.TrimSpace(v.Decision)) {
+	case AdjudicationDrop:
+		d.Decision = AdjudicationDrop
+	case AdjudicationRaise:
+		current := severityIndex(f.Severity)
+		target := severityIndex(v.Severity)
+		if target <= current {
+			target = current + 1
+		}
+		if target < len(severityLevels) {
+			d.Decision = AdjudicationRaise
+			d.NewSeverity = severityLevels[target]
+		}
+	case AdjudicationKeep:
+	default:
+		if d.Reason == "" {
+			d.Reason = "模型未给出裁决，保留"
+		}
+	}
+	return d
+}
+
+// severityIndex 严重程度在 severityLevels 中的位置，未知的按 Low 处理
+func severityIndex(severity string) int {
+	for i, level := range severityLevels {
+		if strings.EqualFold(level, strings.TrimSpace(severity)) {
+			return i
+		}
+	}
+	return 0
+}
+
+// adjudicationContext 读取问题行前后的代码；文件不可读时退化为问题自带的代码片段
+func adjudicationContext(f Finding, sources map[string][]string, contextLines int) string {
+	lines, ok := sources[f.File]
+	if !ok {
+		if content, err := os.ReadFile(f.File); err == nil {
+			lines = strings.Split(string(content), "\n")
+		}
+		sources[f.File] = lines
+	}
+	if f.Line < 1 || f.Line > len(lines) {
+		return f.CodeSnippet
+	}
+
+	var sb strings.Builder
+	for i := max(1, f.Line-contextLines); i <= min(len(lines), f.Line+contextLines); i++ {
+		marker := "  "
+		if i == f.Line {
+			marker = "> "
+		}
+		sb.WriteString(fmt.Sprintf("%s%4d | %s\n", marker, i, lines[i-1]))
+	}
+	return sb.String()
+}
+
+// extractJSONArray 从模型回答中提取 JSON 数组（可能包在代码块或说明文字中）
+func extractJSONArray(answer string) string {
+	text := extractCodeBlock(answer, "json")
+	start := strings.Index(text, "[")
+	end := strings.LastIndex(text, "]")
+	if start == -1 || end < start {
+		return text
+	}
+	return text[start : end+1]
+}
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms/fake"
+)
+
+// adjudicationFindings 三个低置信度问题和一个高置信度问题
+func adjudicationFindings(file string) []Finding {
+	return []Finding{
+		{Fingerprint: "a1", RuleID: "B101", Severity: "High", File: file, Line: 4, Confidence: ConfidenceLow},
+		{Fingerprint: "b2", RuleID: "G501", Severity: "High", File: file, Line: 5, Confidence: ConfidenceHigh},
+		{Fingerprint: "c3", RuleID: "G104", Severity: "Medium", File: file, Line: 6, Confidence: ConfidenceLow},
+		{Fingerprint: "d4", RuleID: "B103", Severity: "Low", File: file, Line: 7, Confidence: ConfidenceLow},
+	}
+}
+
+// 测试模型的保留、丢弃、提升结论
+func TestSeverityAdjudicator_Decisions(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "demo.go")
+	if err := os.WriteFile(file, []byte("package demo\n\nfunc f() {\n\t_ = g()\n\t_ = h()\n\t_ = k()\n\t_ = m()\n}\n"), 0644); err != nil {
+		t.Fatalf("写入测试文件失败: %v", err)
+	}
+
+	answer := "```json\n[" +
+		`{"id": 1, "decision": "drop", "reason": "返回值在调用方检查"},` +
+		`{"id": 2, "decision": "raise", "severity": "Low", "reason": "可以被外部输入触发"},` +
+		`{"id": 3, "decision": "maybe"}` +
+		"]\n```"
+	adjudicator := NewSeverityAdjudicator(fake.NewFakeLLM([]string{answer}), NewNoopLogger())
+	result, err := adjudicator.Execute(context.Background(), AdjudicationRequest{Findings: adjudicationFindings(file)})
+	if err != nil {
+		t.Fatalf("裁决失败: %v", err)
+	}
+
+	if result.Reviewed != 3 || result.Dropped != 1 || result.Raised != 1 {
+		t.Errorf("统计错误: %+v", result)
+	}
+	if len(result.Findings) != 3 || result.Findings[0].Fingerprint != "b2" {
+		t.Fatalf("丢弃后的问题错误: %+v", result.Findings)
+	}
+	// 模型给出的严重程度不高于原级别时至少提升一级
+	if result.Findings[1].Severity != "High" {
+		t.Errorf("提升后的严重程度 = %s, want High", result.Findings[1].Severity)
+	}
+	if d := result.Decisions[2]; d.Decision != AdjudicationKeep || d.Reason == "" {
+		t.Errorf("无法识别的结论应该保留并说明: %+v", d)
+	}
+	if d := result.Decisions[0]; d.Fingerprint != "a1" || d.Reason != "返回值在调用方检查" {
+		t.Errorf("裁决记录错误: %+v", d)
+	}
+}
+
+// 测试模型输出无法解析时整批保留
+func TestSeverityAdjudicator_ModelFailure(t *testing.T) {
+	adjudicator := NewSeverityAdjudicator(fake.NewFakeLLM([]string{"抱歉，我无法判断", "[]"}), NewNoopLogger())
+	findings := adjudicationFindings("missing.go")
+	result, err := adjudicator.Execute(context.Background(), AdjudicationRequest{Findings: findings, BatchSize: 2})
+	if err != nil {
+		t.Fatalf("裁决失败: %v", err)
+	}
+	if len(result.Findings) != len(findings) || result.Dropped != 0 {
+		t.Errorf("模型失败时不应丢弃问题: %+v", result)
+	}
+	if !strings.Contains(result.Decisions[0].Reason, "裁决失败") {
+		t.Errorf("应该记录失败原因: %+v", result.Decisions[0])
+	}
+}
+
+// 测试参数校验和模型不可用
+func TestSeverityAdjudicator_Validate(t *testing.T) {
+	adjudicator := NewSeverityAdjudicator(nil, NewNoopLogger())
+	if err := adjudicator.ValidateInput(AdjudicationRequest{Confidence: "certain"}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("未知置信度应该返回 ErrInvalidInput，实际 %v", err)
+	}
+	if _, err := adjudicator.Execute(context.Background(), AdjudicationRequest{}); err == nil {
+		t.Error("没有模型时应该返回错误")
+	}
+}
@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// snapshotManifest 快照归档中记录文件清单的条目名
+const snapshotManifest = ".insight-snapshot.json"
+
+// Snapshot 一次分析的文件集合和内容哈希
+// 记录在结果中，分支变化后仍能确认当时分析的是哪些代码；配合归档可以原样还原后重新检测
+type Snapshot struct {
+	Root    string         `json:"root,omitempty"`    // 文件路径的基准目录（目录输入），为空时路径按输入原样记录
+	Files   []SnapshotFile `json:"files"`             // 按路径排序
+	Digest  string         `json:"digest"`            // 所有文件路径和哈希的 SHA-256，两次分析的 digest 相同说明输入完全一致
+	Archive string         `json:"archive,omitempty"` // 文件内容的 tar.gz 归档
+}
+
+// SnapshotFile 快照中的一个文件
+type SnapshotFile struct {
+	Path   string `json:"path"` // 相对 Root 的路径，使用 / 分隔
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"` // 磁盘上原始内容（未去除 BOM、未转换换行）的哈希
+}
+
+// takeSnapshot 读取分析的文件并计算哈希，archive 不为空时同时把文件和清单打包为 tar.gz
+// 按磁盘上的原始字节计算，与分析时读取的内容之间如果文件被修改，以快照为准；
+// 归档经由 ctx 中的工作区写入（--out-dir 下的对应路径，dry-run 时只记录到变更集）
+func takeSnapshot(ctx context.Context, root string, files []string, archive string) (*Snapshot, error) {
+	snap := &Snapshot{Root: root, Files: make([]SnapshotFile, 0, len(files))}
+	contents := make(map[string][]byte, len(files))
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return nil, context.Cause(ctx)
+		}
+		// 读取失败的文件没有被分析，已记入结果的 error_files
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		path := snapshotPath(root, file)
+		snap.Files = append(snap.Files, SnapshotFile{Path: path, Size: int64(len(data)), SHA256: hex.EncodeToString(sum[:])})
+		contents[path] = data
+	}
+	sort.Slice(snap.Files, func(i, j int) bool { return snap.Files[i].Path < snap.Files[j].Path })
+
+	digest := sha256.New()
+	for _, f := range snap.Files {
+		fmt.Fprintf(digest, "%s\x00%s\n", f.Path, f.SHA256)
+	}
+	snap.Digest = hex.EncodeToString(digest.Sum(nil))
+
+	if archive != "" {
+		written, err := writeSnapshotArchive(ctx, archive, snap, contents)
+		if err != nil {
+			return nil, err
+		}
+		snap.Archive = written
+	}
+	return snap, nil
+}
+
+// snapshotPath 文件在快照中的路径：在 root 下时用相对路径，否则去掉开头的 / 和 ../，保证解压时不会写到归档目录之外
+func snapshotPath(root, file string) string {
+	path := filepath.Clean(file)
+	if root != "" {
+		if rel, err := filepath.Rel(root, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			path = rel
+		}
+	}
+	path = filepath.ToSlash(strings.TrimPrefix(path, filepath.VolumeName(path)))
+	path = strings.TrimLeft(path, "/")
+	for strings.HasPrefix(path, "../") {
+		path = strings.TrimPrefix(path, "../")
+	}
+	return path
+}
+
+// writeSnapshotArchive 写入 tar.gz 归档：快照中的每个文件加上根目录下的清单 .insight-snapshot.json
+// 条目的修改时间固定，相同的文件集合总是生成相同的归档；返回实际写入的路径
+func writeSnapshotArchive(ctx context.Context, path string, snap *Snapshot, contents map[string][]byte) (string, error) {
+	manifest, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("序列化快照清单失败: %w", err)
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	write := func(name string, data []byte) error {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), Typeflag: tar.TypeReg, ModTime: time.Unix(0, 0)}); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	if err := write(snapshotManifest, manifest); err != nil {
+		return "", fmt.Errorf("写入快照归档失败: %w", err)
+	}
+	for _, f := range snap.Files {
+		if err := write(f.Path, contents[f.Path]); err != nil {
+			return "", fmt.Errorf("写入快照归档失败: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return "", fmt.Errorf("写入快照归档失败: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return "", fmt.Errorf("写入快照归档失败: %w", err)
+	}
+	written, err := WriteOutputFile(ctx, path, buf.Bytes(), 0644)
+	if err != nil {
+		return "", fmt.Errorf("写入快照归档失败: %w", err)
+	}
+	return written, nil
+}
@@ -0,0 +1,161 @@
+package tools
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBugDetector_Snapshot(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":     "package main\n\nfunc main() {}\n",
+		"pkg/util.go": "package pkg\n\nfunc Util() int { return 1 }\n",
+		"notes.txt":   "not go",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	archive := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+
+	detect := func(input BugDetectorInput) BugResult {
+		t.Helper()
+		out, err := NewBugDetector().Run(context.Background(), input)
+		if err != nil {
+			t.Fatalf("检测失败: %v", err)
+		}
+		var result BugResult
+		if err := json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	if result := detect(BugDetectorInput{Directory: dir}); result.Snapshot != nil {
+		t.Fatalf("没有要求快照时不应记录: %+v", result.Snapshot)
+	}
+
+	first := detect(BugDetectorInput{Directory: dir, SnapshotArchive: archive})
+	snap := first.Snapshot
+	if snap == nil || snap.Archive != archive || snap.Root != dir {
+		t.Fatalf("快照缺失或字段错误: %+v", snap)
+	}
+	if len(snap.Files) != 2 || snap.Files[0].Path != "main.go" || snap.Files[1].Path != "pkg/util.go" {
+		t.Fatalf("快照文件列表错误: %+v", snap.Files)
+	}
+	if snap.Files[0].Size != int64(len(files["main.go"])) || len(snap.Files[0].SHA256) != 64 {
+		t.Errorf("文件大小或哈希错误: %+v", snap.Files[0])
+	}
+
+	// 内容不变时 digest 不变，修改任一文件后变化
+	if again := detect(BugDetectorInput{Directory: dir, Snapshot: true}); again.Snapshot.Digest != snap.Digest {
+		t.Errorf("相同输入的 digest 不同: %s != %s", again.Snapshot.Digest, snap.Digest)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() { println() }\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if changed := detect(BugDetectorInput{Directory: dir, Snapshot: true}); changed.Snapshot.Digest == snap.Digest {
+		t.Error("文件修改后 digest 应该变化")
+	}
+
+	// 归档中是清单和修改前的文件内容
+	f, err := os.Open(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := make(map[string]string)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries[hdr.Name] = string(data)
+	}
+	if entries["main.go"] != files["main.go"] || entries["pkg/util.go"] != files["pkg/util.go"] {
+		t.Errorf("归档内容错误: %v", entries)
+	}
+	var manifest Snapshot
+	if err := json.Unmarshal([]byte(entries[snapshotManifest]), &manifest); err != nil || manifest.Digest != snap.Digest {
+		t.Errorf("归档清单错误: %v %+v", err, manifest)
+	}
+}
+
+func TestSnapshotPath(t *testing.T) {
+	tests := []struct {
+		root, file, want string
+	}{
+		{"/repo", "/repo/internal/a.go", "internal/a.go"},
+		{"/repo", "/other/b.go", "other/b.go"},
+		{"", "../shared/c.go", "shared/c.go"},
+		{"", "./d.go", "d.go"},
+	}
+	for _, tt := range tests {
+		if got := snapshotPath(tt.root, tt.file); got != tt.want {
+			t.Errorf("snapshotPath(%q, %q) = %q, want %q", tt.root, tt.file, got, tt.want)
+		}
+	}
+}
+
+// 测试快照归档经由工作区写入：dry-run 时不创建文件只记录变更，--out-dir 时写到输出目录下
+func TestTakeSnapshot_Workspace(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(file, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	archive := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+
+	ws := NewWorkspace("")
+	ws.ChangeSet = NewChangeSet()
+	snap, err := takeSnapshot(WithWorkspace(context.Background(), ws), dir, []string{file}, archive)
+	if err != nil {
+		t.Fatalf("快照失败: %v", err)
+	}
+	if _, err := os.Stat(archive); !os.IsNotExist(err) {
+		t.Errorf("dry-run 不应创建归档: %v", err)
+	}
+	changes := ws.ChangeSet.Changes()
+	if snap.Archive != archive || len(changes) != 1 || changes[0].Path != archive || !changes[0].Created ||
+		changes[0].Diff != "Binary files /dev/null and b/"+archive+" differ\n" {
+		t.Errorf("dry-run 应该只记录归档的变更: %s %+v", snap.Archive, changes)
+	}
+
+	outDir := t.TempDir()
+	snap, err = takeSnapshot(WithWorkspace(context.Background(), NewWorkspace(outDir)), dir, []string{file}, archive)
+	if err != nil {
+		t.Fatalf("快照失败: %v", err)
+	}
+	if want := NewWorkspace(outDir).OutputPath(archive); snap.Archive != want {
+		t.Errorf("归档路径 = %s，期望 %s", snap.Archive, want)
+	}
+	if _, err := os.Stat(snap.Archive); err != nil {
+		t.Errorf("归档应该写到输出目录下: %v", err)
+	}
+	if _, err := os.Stat(archive); !os.IsNotExist(err) {
+		t.Errorf("指定 --out-dir 时不应写到原路径: %v", err)
+	}
+}
@@ -0,0 +1,16 @@
+package tools
+
+import (
+	"go-ai-study/internal/ai"
+	"os"
+)
+
+// readSource 读取源文件，并去掉 BOM、统一换行（见 ai.NormalizeSource）
+// 扫描器、测试生成器读取文件都经由它，行号、列号和代码片段不受文件来自哪个平台影响
+func readSource(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return ai.NormalizeSource(string(data)), nil
+}
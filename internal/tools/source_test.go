@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// windowsFixture 把 Windows 上保存的夹具（UTF-8 BOM + CRLF）复制为临时目录下的 main.go
+// 夹具不用 .go 扩展名，避免 gofmt 和 go 工具处理它；.gitattributes 保证换行不被 git 转换
+func windowsFixture(t *testing.T) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "encoding", "windows_crlf_bom.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "\uFEFF") || !strings.Contains(string(data), "\r\n") {
+		t.Fatal("夹具应以 BOM 开头并使用 CRLF 换行")
+	}
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// 测试 BOM 和 CRLF 不影响行号、列号和代码片段
+func TestReadSource_WindowsFixture(t *testing.T) {
+	path := windowsFixture(t)
+	src, err := readSource(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.ContainsAny(src, "\r\uFEFF") {
+		t.Errorf("读取后仍有 BOM 或 \\r: %q", src[:20])
+	}
+
+	ctx := context.Background()
+	out, err := NewSecurityScanner().Run(ctx, SecurityScanInput{Files: []string{path}})
+	if err != nil {
+		t.Fatalf("安全扫描失败: %v", err)
+	}
+	var sec SecurityResult
+	if err := json.Unmarshal([]byte(out), &sec); err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, issue := range sec.Issues {
+		if strings.Contains(issue.CodeSnippet, "\r") {
+			t.Errorf("%s 的代码片段带有 \\r: %q", issue.RuleID, issue.CodeSnippet)
+		}
+		if issue.RuleID == "G101" {
+			found = true
+			if issue.Line != 10 || issue.Column != 2 || issue.CodeSnippet != `password := "admin123"` {
+				t.Errorf("G101 位置或片段错误: %d:%d %q", issue.Line, issue.Column, issue.CodeSnippet)
+			}
+		}
+	}
+	if !found || sec.Status != "success" {
+		t.Fatalf("应报告 G101 且没有解析失败: status=%s issues=%+v", sec.Status, sec.Issues)
+	}
+
+	out, err = NewBugDetector().Run(ctx, BugDetectorInput{Files: []string{path}})
+	if err != nil {
+		t.Fatalf("Bug 检测失败: %v", err)
+	}
+	var bugs BugResult
+	if err := json.Unmarshal([]byte(out), &bugs); err != nil {
+		t.Fatal(err)
+	}
+	if bugs.Status != "success" || len(bugs.Bugs) == 0 {
+		t.Fatalf("Bug 检测应成功并报告问题: status=%s bugs=%d", bugs.Status, len(bugs.Bugs))
+	}
+	for _, bug := range bugs.Bugs {
+		if strings.Contains(bug.CodeSnippet, "\r") {
+			t.Errorf("%s 的代码片段带有 \\r: %q", bug.RuleID, bug.CodeSnippet)
+		}
+	}
+
+	complexity, err := NewComplexityAnalyzer().Execute(ctx, ComplexityInput{Directory: filepath.Dir(path)})
+	if err != nil || len(complexity.Functions) != 1 || complexity.Functions[0].Line != 9 {
+		t.Errorf("复杂度分析结果错误: %+v, %v", complexity, err)
+	}
+
+	functions, err := NewTestGenerator(NewNoopLogger()).parseFileFunctions(path)
+	if err != nil || len(functions) != 1 || strings.Contains(functions[0].DocComment, "\r") {
+		t.Errorf("测试生成器解析结果错误: %+v, %v", functions, err)
+	}
+}
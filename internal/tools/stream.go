@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// 流式事件类型
+const (
+	StreamEventFinding  = "finding"   // 发现一个问题
+	StreamEventFileDone = "file_done" // 一个文件分析完成
+	StreamEventDone     = "done"      // 全部分析完成
+)
+
+// StreamEvent 长时间运行的工具在执行过程中逐步产生的事件
+// 事件先于最终结果到达，只用于展示进度；去重、指纹消歧以工具返回的最终结果为准
+type StreamEvent struct {
+	Type     string   `json:"type"`
+	Tool     string   `json:"tool"`
+	File     string   `json:"file,omitempty"`
+	Finding  *Finding `json:"finding,omitempty"`
+	Findings int      `json:"findings,omitempty"` // file_done/done：问题数
+	Error    string   `json:"error,omitempty"`    // file_done：该文件分析失败的原因
+}
+
+// StreamFunc 接收流式事件的回调
+type StreamFunc func(StreamEvent)
+
+// streamKey 流式回调在 context 中的键
+type streamKey struct{}
+
+// WithStream 注册流式回调，工具通过 context 逐步上报事件
+// 回调按事件产生的顺序串行调用，不需要自行加锁
+func WithStream(ctx context.Context, fn StreamFunc) context.Context {
+	var mu sync.Mutex
+	return context.WithValue(ctx, streamKey{}, StreamFunc(func(event StreamEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		fn(event)
+	}))
+}
+
+// streaming 是否注册了流式回调
+func streaming(ctx context.Context) bool {
+	_, ok := ctx.Value(streamKey{}).(StreamFunc)
+	return ok
+}
+
+// emit 上报流式事件，没有注册回调时什么都不做
+func emit(ctx context.Context, event StreamEvent) {
+	if fn, ok := ctx.Value(streamKey{}).(StreamFunc); ok {
+		fn(event)
+	}
+}
+
+// emitFileFindings 上报一个文件的所有问题和文件完成事件
+func emitFileFindings(ctx context.Context, tool, file string, findings []Finding, fileErr error) {
+	if !streaming(ctx) {
+		return
+	}
+	for i := range findings {
+		emit(ctx, StreamEvent{Type: StreamEventFinding, Tool: tool, File: file, Finding: &findings[i]})
+	}
+	done := StreamEvent{Type: StreamEventFileDone, Tool: tool, File: file, Findings: len(findings)}
+	if fileErr != nil {
+		done.Error = fileErr.Error()
+	}
+	emit(ctx, done)
+}
+
+// NDJSONStream 把流式事件逐行写为 JSON（NDJSON），每个事件写完立即可读
+func NDJSONStream(w io.Writer) StreamFunc {
+	enc := json.NewEncoder(w)
+	return func(event StreamEvent) {
+		enc.Encode(event)
+	}
+}
@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// 测试目录检测时按文件流式上报问题
+func TestBugDetector_Stream(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.go"), []byte(`package x
+
+func Grade(score int) string {
+	switch score {
+	case 90:
+		return "A"
+	}
+	return "B"
+}
+`), 0644)
+	os.WriteFile(filepath.Join(dir, "b.go"), []byte("package x\n\nfunc g() {}\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "c.go"), []byte("package x\n\nfunc {\n"), 0644)
+
+	var events []StreamEvent
+	ctx := WithStream(context.Background(), func(event StreamEvent) {
+		events = append(events, event)
+	})
+	result, err := NewBugDetector().Execute(ctx, BugDetectorInput{Directory: dir})
+	if err != nil {
+		t.Fatalf("检测失败: %v", err)
+	}
+
+	findings, fileDone := 0, map[string]StreamEvent{}
+	for _, event := range events {
+		switch event.Type {
+		case StreamEventFinding:
+			findings++
+			if event.Finding == nil || event.Finding.Tool != "bug_detector" {
+				t.Errorf("finding 事件缺少问题: %+v", event)
+			}
+		case StreamEventFileDone:
+			fileDone[filepath.Base(event.File)] = event
+		}
+	}
+	if result.Total == 0 || findings != result.Total {
+		t.Errorf("流式上报 %d 个问题，最终结果 %d 个", findings, result.Total)
+	}
+	if len(fileDone) != 3 {
+		t.Errorf("每个文件都应该有 file_done 事件: %v", fileDone)
+	}
+	if fileDone["c.go"].Error == "" {
+		t.Error("解析失败的文件应该在 file_done 中带上原因")
+	}
+}
+
+// 测试 NDJSON 输出每行一个事件
+func TestNDJSONStream(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := WithStream(context.Background(), NDJSONStream(&buf))
+	emitFileFindings(ctx, "security_scanner", "a.go", []Finding{{RuleID: "G101"}, {RuleID: "G102"}}, nil)
+
+	scanner := bufio.NewScanner(&buf)
+	var types []string
+	for scanner.Scan() {
+		var event StreamEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("不是合法的 JSON 行: %s", scanner.Text())
+		}
+		types = append(types, event.Type)
+	}
+	want := []string{StreamEventFinding, StreamEventFinding, StreamEventFileDone}
+	if len(types) != len(want) {
+		t.Fatalf("事件错误: %v", types)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("第 %d 个事件应该是 %s，实际 %s", i, want[i], types[i])
+		}
+	}
+
+}
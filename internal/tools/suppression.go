@@ -0,0 +1,136 @@
+package tools
+
+import (
+	"go-ai-study/internal/i18n"
+	"go/scanner"
+	"go/token"
+	"strings"
+)
+
+// suppressionDirective 行内抑制注释：//insight:ignore <规则ID>[,<规则ID>...] <原因>
+//
+// 写在问题所在行的末尾时抑制该行的问题；单独占一行时抑制下一行的问题：
+//
+//	key := "sk-test-0000" //insight:ignore S001 测试用的假密钥
+//
+//	//insight:ignore B101,B120 进程退出前不关心关闭错误
+//	rows, _ := db.Query(q)
+//
+// 被抑制的问题不计入结果，而是列在结果的 suppressed 中并带上原因，便于审计
+const suppressionDirective = "//insight:ignore"
+
+// suppressionIndex 行号 -> 规则 ID -> 抑制原因
+type suppressionIndex map[int]map[string]string
+
+// parseSuppressions 从源码的注释中收集抑制指令
+// 用 go/scanner 而不是逐行匹配文本，字符串字面量中的 "//insight:ignore" 不会被当作指令；代码无法扫描的部分直接跳过
+func parseSuppressions(code string) suppressionIndex {
+	if !strings.Contains(code, suppressionDirective) {
+		return nil
+	}
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(code))
+	var s scanner.Scanner
+	s.Init(file, []byte(code), nil, scanner.ScanComments)
+
+	index := make(suppressionIndex)
+	lastCodeLine := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		line := file.Line(pos)
+		if tok != token.COMMENT {
+			// 自动插入的分号不算代码
+			if !(tok == token.SEMICOLON && lit == "\n") {
+				lastCodeLine = line
+			}
+			continue
+		}
+		rules, reason, ok := parseSuppressionComment(lit)
+		if !ok {
+			continue
+		}
+		target := line
+		if lastCodeLine != line {
+			target = line + 1
+		}
+		if index[target] == nil {
+			index[target] = make(map[string]string)
+		}
+		for _, rule := range rules {
+			index[target][rule] = reason
+		}
+	}
+	return index
+}
+
+// parseSuppressionComment 解析单条注释，返回规则 ID 列表和原因
+func parseSuppressionComment(comment string) ([]string, string, bool) {
+	rest, ok := strings.CutPrefix(comment, suppressionDirective)
+	if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return nil, "", false
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return nil, "", false
+	}
+	var rules []string
+	for _, rule := range strings.Split(fields[0], ",") {
+		if rule = strings.TrimSpace(rule); rule != "" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, strings.Join(fields[1:], " "), len(rules) > 0
+}
+
+// lookup 返回 line 行上规则 ruleID 的抑制原因
+func (idx suppressionIndex) lookup(ruleID string, line int) (string, bool) {
+	reason, ok := idx[line][ruleID]
+	return reason, ok
+}
+
+// suppressBugIssues 按行内注释拆分问题：返回保留的问题和被抑制的问题（带原因）
+func suppressBugIssues(bugs []BugIssue, idx suppressionIndex) ([]BugIssue, []BugIssue) {
+	if len(idx) == 0 {
+		return bugs, nil
+	}
+	kept := make([]BugIssue, 0, len(bugs))
+	var suppressed []BugIssue
+	for _, bug := range bugs {
+		if reason, ok := idx.lookup(bug.RuleID, bug.Line); ok {
+			bug.SuppressionReason = reason
+			suppressed = append(suppressed, bug)
+			continue
+		}
+		kept = append(kept, bug)
+	}
+	return kept, suppressed
+}
+
+// suppressSecurityIssues 按行内注释拆分安全问题：返回保留的问题和被抑制的问题（带原因）
+func suppressSecurityIssues(issues []SecurityIssue, idx suppressionIndex) ([]SecurityIssue, []SecurityIssue) {
+	if len(idx) == 0 {
+		return issues, nil
+	}
+	kept := make([]SecurityIssue, 0, len(issues))
+	var suppressed []SecurityIssue
+	for _, issue := range issues {
+		if reason, ok := idx.lookup(issue.RuleID, issue.Line); ok {
+			issue.SuppressionReason = reason
+			suppressed = append(suppressed, issue)
+			continue
+		}
+		kept = append(kept, issue)
+	}
+	return kept, suppressed
+}
+
+// suppressedSummary 附加在摘要后面的抑制数量说明
+func suppressedSummary(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return i18n.T("findings.summary.suppressed", n)
+}
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"testing"
+)
+
+// 测试抑制指令的解析：行尾注释作用于本行，单独一行的注释作用于下一行
+func TestParseSuppressions(t *testing.T) {
+	code := `package main
+
+func main() {
+	a := f() //insight:ignore B101 测试代码
+	//insight:ignore B101,B120 进程退出前不关心
+	b := f()
+	s := "//insight:ignore B101 字符串里的不算"
+	//insight:ignored B101 前缀不完整
+	c := f()
+	//insight:ignore
+	d := f()
+}
+`
+	idx := parseSuppressions(code)
+	if reason, ok := idx.lookup("B101", 4); !ok || reason != "测试代码" {
+		t.Errorf("第 4 行 B101 应被抑制，原因为 测试代码，实际 %q %v", reason, ok)
+	}
+	if _, ok := idx.lookup("B120", 6); !ok {
+		t.Error("第 6 行 B120 应被上一行的注释抑制")
+	}
+	if _, ok := idx.lookup("B101", 5); ok {
+		t.Error("单独一行的注释不应抑制本行")
+	}
+	for _, line := range []int{7, 9, 11} {
+		if len(idx[line]) > 0 {
+			t.Errorf("第 %d 行不应有抑制: %v", line, idx[line])
+		}
+	}
+	if parseSuppressions("package main\n") != nil {
+		t.Error("没有指令时应返回 nil")
+	}
+}
+
+// 测试被抑制的问题单独列出，不计入总数
+func TestSuppression_Results(t *testing.T) {
+	ctx := context.Background()
+	code := `package main
+
+import (
+	"math/rand"
+	"os"
+)
+
+func main() {
+	_ = os.Remove("a") //insight:ignore B101 清理失败无所谓
+	_ = os.Remove("b")
+	_ = rand.Intn(10) //insight:ignore G401 只用于抽样日志
+}
+`
+	bugs, err := NewBugDetector().Execute(ctx, BugDetectorInput{Code: code})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bugs.Suppressed) != 1 || bugs.Suppressed[0].Line != 9 || bugs.Suppressed[0].SuppressionReason != "清理失败无所谓" {
+		t.Errorf("第 9 行的 B101 应被抑制: %+v", bugs.Suppressed)
+	}
+	for _, bug := range bugs.Bugs {
+		if bug.RuleID == "B101" && bug.Line == 9 {
+			t.Error("被抑制的问题不应出现在 bugs 中")
+		}
+	}
+	if bugs.Total != len(bugs.Bugs) {
+		t.Errorf("Total = %d, 与 bugs 数量 %d 不一致", bugs.Total, len(bugs.Bugs))
+	}
+
+	security, err := NewSecurityScanner().Execute(ctx, SecurityScanInput{Code: code, File: "main.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(security.Suppressed) != 1 || security.Suppressed[0].RuleID != "G401" {
+		t.Errorf("G401 应被抑制: %+v", security.Suppressed)
+	}
+	if security.Total != 0 {
+		t.Errorf("安全问题应全部被抑制，实际 %+v", security.Issues)
+	}
+}
@@ -9,38 +9,36 @@ import (
 	"go/token"
 	"os"
 	"path/filepath"
-	"reflect"
 	"strings"
 	"text/template"
 )
 
 // TestGenerator 测试生成器
 type TestGenerator struct {
-	BaseTool
+	*TypedTool[GenerateRequest, *GenerateResult]
 	logger Logger
 }
 
 // NewTestGenerator 创建测试生成器
 func NewTestGenerator(logger Logger) *TestGenerator {
-	return &TestGenerator{
-		BaseTool: BaseTool{
-			name:        "test_generator",
-			description: "自动生成 Go 代码的单元测试，支持 Table-driven 模式和 Mock 生成",
-			inputType:   reflect.TypeOf(GenerateRequest{}),
-		},
-		logger: logger,
-	}
+	tg := &TestGenerator{logger: logger}
+	tg.TypedTool = NewTypedTool[GenerateRequest, *GenerateResult](
+		"test_generator",
+		"自动生成 Go 代码的单元测试，支持 Table-driven 模式和 Mock 生成",
+		tg,
+	)
+	return tg
 }
 
 // GenerateRequest 测试生成请求
 type GenerateRequest struct {
-	// 以下 3 个参数互斥，只能指定一个
+	// FilePath 和 DirPath 互斥；指定 FunctionName 时只为 FilePath 中的该函数生成测试
 	FunctionName string // 函数名（分析单个函数）
 	FilePath     string // 文件路径（分析整个文件）
 	DirPath      string // 目录路径（分析整个目录）
 
 	// 配置选项
-	TestMode    TestMode // 测试模式
+	TestMode    TestMode `jsonschema:"enum=basic|table-driven|mock"` // 测试模式
 	WithMock    bool     // 是否生成 Mock 建议
 	WithCoverage bool    // 是否生成覆盖率报告
 }
@@ -54,32 +52,16 @@ const (
 	TestModeMock        TestMode = "mock"          // Mock 测试
 )
 
-// Validate 验证输入参数
-func (tg *TestGenerator) Validate(input any) error {
-	req, ok := input.(GenerateRequest)
-	if !ok {
-		return ErrInvalidInput
-	}
-
+// ValidateInput 验证输入参数
+func (tg *TestGenerator) ValidateInput(req GenerateRequest) error {
 	// 检查至少指定了一个目标
 	if req.FunctionName == "" && req.FilePath == "" && req.DirPath == "" {
 		return fmt.Errorf("必须指定 FunctionName, FilePath 或 DirPath 其中之一")
 	}
 
-	// 检查不能同时指定多个
-	count := 0
-	if req.FunctionName != "" {
-		count++
-	}
-	if req.FilePath != "" {
-		count++
-	}
-	if req.DirPath != "" {
-		count++
-	}
-
-	if count > 1 {
-		return fmt.Errorf("FunctionName, FilePath 和 DirPath 不能同时指定")
+	// 函数必须位于单个文件中，不能与目录同时指定
+	if req.DirPath != "" && (req.FilePath != "" || req.FunctionName != "") {
+		return fmt.Errorf("DirPath 不能与 FunctionName 或 FilePath 同时指定")
 	}
 
 	// 验证路径存在
@@ -98,10 +80,21 @@ func (tg *TestGenerator) Validate(input any) error {
 	return nil
 }
 
-// Run 执行测试生成
+// Run 执行测试生成，结果以文本形式输出
 func (tg *TestGenerator) Run(ctx context.Context, input any) (string, error) {
-	req := input.(GenerateRequest)
+	req, err := tg.Decode(input)
+	if err != nil {
+		return "", err
+	}
+	result, err := tg.Execute(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return tg.formatResult(*result), nil
+}
 
+// Execute 执行测试生成
+func (tg *TestGenerator) Execute(ctx context.Context, req GenerateRequest) (*GenerateResult, error) {
 	tg.logger.Info("开始生成测试",
 		"mode", req.TestMode,
 		"function", req.FunctionName,
@@ -114,30 +107,27 @@ func (tg *TestGenerator) Run(ctx context.Context, input any) (string, error) {
 	// 根据不同的输入类型执行不同的逻辑
 	switch {
 	case req.FunctionName != "":
-		result, err = tg.generateFunctionTest(req)
+		result, err = tg.generateFunctionTest(ctx, req)
 	case req.FilePath != "":
-		result, err = tg.generateFileTests(req)
+		result, err = tg.generateFileTests(ctx, req)
 	case req.DirPath != "":
-		result, err = tg.generateDirectoryTests(req)
+		result, err = tg.generateDirectoryTests(ctx, req)
 	}
 
 	if err != nil {
 		tg.logger.Error("生成测试失败", "error", err)
-		return "", err
+		return nil, err
 	}
 
-	// 格式化输出
-	output := tg.formatResult(result)
-
 	tg.logger.Info("测试生成完成",
 		"files", len(result.GeneratedFiles),
 		"testCases", result.TestCaseCount)
 
-	return output, nil
+	return &result, nil
 }
 
 // generateFunctionTest 为单个函数生成测试
-func (tg *TestGenerator) generateFunctionTest(req GenerateRequest) (GenerateResult, error) {
+func (tg *TestGenerator) generateFunctionTest(ctx context.Context, req GenerateRequest) (GenerateResult, error) {
 	// 解析函数信息
 	funcInfo, err := tg.parseFunctionInfo(req.FilePath, req.FunctionName)
 	if err != nil {
@@ -151,8 +141,8 @@ func (tg *TestGenerator) generateFunctionTest(req GenerateRequest) (GenerateResu
 	}
 
 	// 写入文件
-	testFilePath := tg.getTestFilePath(req.FilePath)
-	if err := os.WriteFile(testFilePath, []byte(testCode), 0644); err != nil {
+	testFilePath, err := WriteOutputFile(ctx, tg.getTestFilePath(req.FilePath), []byte(testCode), 0644)
+	if err != nil {
 		return GenerateResult{}, fmt.Errorf("写入测试文件失败: %w", err)
 	}
 
@@ -170,7 +160,7 @@ func (tg *TestGenerator) generateFunctionTest(req GenerateRequest) (GenerateResu
 }
 
 // generateFileTests 为整个文件生成测试
-func (tg *TestGenerator) generateFileTests(req GenerateRequest) (GenerateResult, error) {
+func (tg *TestGenerator) generateFileTests(ctx context.Context, req GenerateRequest) (GenerateResult, error) {
 	// 解析文件中的所有函数
 	funcInfos, err := tg.parseFileFunctions(req.FilePath)
 	if err != nil {
@@ -205,8 +195,8 @@ func (tg *TestGenerator) generateFileTests(req GenerateRequest) (GenerateResult,
 	}
 
 	// 写入文件
-	testFilePath := tg.getTestFilePath(req.FilePath)
-	if err := os.WriteFile(testFilePath, []byte(allTestCode.String()), 0644); err != nil {
+	testFilePath, err := WriteOutputFile(ctx, tg.getTestFilePath(req.FilePath), []byte(allTestCode.String()), 0644)
+	if err != nil {
 		return GenerateResult{}, fmt.Errorf("写入测试文件失败: %w", err)
 	}
 
@@ -224,7 +214,7 @@ func (tg *TestGenerator) generateFileTests(req GenerateRequest) (GenerateResult,
 }
 
 // generateDirectoryTests 为整个目录生成测试
-func (tg *TestGenerator) generateDirectoryTests(req GenerateRequest) (GenerateResult, error) {
+func (tg *TestGenerator) generateDirectoryTests(ctx context.Context, req GenerateRequest) (GenerateResult, error) {
 	// 查找所有 Go 文件
 	var goFiles []string
 	err := filepath.Walk(req.DirPath, func(path string, info os.FileInfo, err error) error {
@@ -232,6 +222,9 @@ func (tg *TestGenerator) generateDirectoryTests(req GenerateRequest) (GenerateRe
 			return err
 		}
 		if !info.IsDir() && strings.HasSuffix(path, ".go") && !strings.HasSuffix(path, "_test.go") {
+			if skip, err := ScanFile(ctx, info); err != nil || skip {
+				return err
+			}
 			goFiles = append(goFiles, path)
 		}
 		return nil
@@ -250,6 +243,9 @@ func (tg *TestGenerator) generateDirectoryTests(req GenerateRequest) (GenerateRe
 	totalTestCases := 0
 
 	for _, filePath := range goFiles {
+		if ctx.Err() != nil {
+			return GenerateResult{}, context.Cause(ctx)
+		}
 		fileReq := GenerateRequest{
 			FilePath:     filePath,
 			TestMode:    req.TestMode,
@@ -257,7 +253,7 @@ func (tg *TestGenerator) generateDirectoryTests(req GenerateRequest) (GenerateRe
 			WithCoverage: false, // 目录模式下单独处理覆盖率
 		}
 
-		result, err := tg.generateFileTests(fileReq)
+		result, err := tg.generateFileTests(ctx, fileReq)
 		if err != nil {
 			tg.logger.Warn("生成文件测试失败",
 				"file", filePath,
@@ -308,8 +304,12 @@ type Parameter struct {
 
 // parseFunctionInfo 解析函数信息
 func (tg *TestGenerator) parseFunctionInfo(filePath, funcName string) (*FunctionInfo, error) {
+	src, err := readSource(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %w", err)
+	}
 	fset := token.NewFileSet()
-	node, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
+	node, err := parser.ParseFile(fset, filePath, src, parser.ParseComments)
 	if err != nil {
 		return nil, fmt.Errorf("解析文件失败: %w", err)
 	}
@@ -333,8 +333,12 @@ func (tg *TestGenerator) parseFunctionInfo(filePath, funcName string) (*Function
 
 // parseFileFunctions 解析文件中的所有函数
 func (tg *TestGenerator) parseFileFunctions(filePath string) ([]FunctionInfo, error) {
+	src, err := readSource(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %w", err)
+	}
 	fset := token.NewFileSet()
-	node, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
+	node, err := parser.ParseFile(fset, filePath, src, parser.ParseComments)
 	if err != nil {
 		return nil, fmt.Errorf("解析文件失败: %w", err)
 	}
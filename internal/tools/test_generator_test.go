@@ -36,6 +36,16 @@ func TestValidate(t *testing.T) {
 			input:   GenerateRequest{FilePath: "test_generator.go"},
 			wantErr: false,
 		},
+		{
+			name:    "valid request with function in file",
+			input:   GenerateRequest{FunctionName: "NewTestGenerator", FilePath: "test_generator.go"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid request - function with directory",
+			input:   GenerateRequest{FunctionName: "TestFunc", DirPath: "."},
+			wantErr: true,
+		},
 		{
 			name:    "invalid request - no target",
 			input:   GenerateRequest{},
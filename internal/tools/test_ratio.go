@@ -0,0 +1,171 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// TestRatioAnalyzer 测试代码比例分析器
+// 按包统计测试代码行数与业务代码行数的比例，并找出没有任何 _test.go 的包
+type TestRatioAnalyzer struct {
+	*TypedTool[TestRatioRequest, *TestRatioResult]
+}
+
+// NewTestRatioAnalyzer 创建测试代码比例分析器
+func NewTestRatioAnalyzer() *TestRatioAnalyzer {
+	ta := &TestRatioAnalyzer{}
+	ta.TypedTool = NewTypedTool[TestRatioRequest, *TestRatioResult](
+		"test_ratio",
+		"按包统计测试代码与业务代码的行数比例，列出没有测试文件的包",
+		ta,
+	)
+	return ta
+}
+
+// TestRatioRequest 测试代码比例请求
+type TestRatioRequest struct {
+	Directory string `json:"directory" jsonschema:"required"` // 扫描目录
+}
+
+// PackageTestRatio 单个包的测试代码比例
+type PackageTestRatio struct {
+	Package   string  `json:"package"`    // 包目录
+	CodeFiles int     `json:"code_files"` // 非测试 Go 文件数
+	TestFiles int     `json:"test_files"` // _test.go 文件数
+	CodeLOC   int     `json:"code_loc"`   // 业务代码行数（不含空行和注释行）
+	TestLOC   int     `json:"test_loc"`   // 测试代码行数
+	Ratio     float64 `json:"ratio"`      // TestLOC / CodeLOC
+}
+
+// TestRatioResult 测试代码比例结果
+type TestRatioResult struct {
+	Packages []PackageTestRatio `json:"packages"` // 按比例从低到高排序，最需要补测试的包在前
+	Untested []string           `json:"untested"` // 没有任何 _test.go 的包
+	CodeLOC  int                `json:"code_loc"`
+	TestLOC  int                `json:"test_loc"`
+	Ratio    float64            `json:"ratio"`
+	Summary  string             `json:"summary"`
+}
+
+// ValidateInput 验证输入参数
+func (ta *TestRatioAnalyzer) ValidateInput(req TestRatioRequest) error {
+	if req.Directory == "" {
+		return fmt.Errorf("必须指定 Directory")
+	}
+	return nil
+}
+
+// Execute 执行测试代码比例分析
+func (ta *TestRatioAnalyzer) Execute(ctx context.Context, req TestRatioRequest) (*TestRatioResult, error) {
+	return AnalyzeTestRatio(ctx, req.Directory)
+}
+
+// AnalyzeTestRatio 统计目录下每个包的测试代码比例
+func AnalyzeTestRatio(ctx context.Context, dir string) (*TestRatioResult, error) {
+	files, err := collectGoFiles(ctx, dir)
+	if err != nil {
+		return nil, fmt.Errorf("文件收集失败: %w", err)
+	}
+
+	packages := make(map[string]*PackageTestRatio)
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		src, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+
+		pkgDir := filepath.Dir(file)
+		pkg, ok := packages[pkgDir]
+		if !ok {
+			pkg = &PackageTestRatio{Package: pkgDir}
+			packages[pkgDir] = pkg
+		}
+
+		loc := countCodeLines(src)
+		if strings.HasSuffix(file, "_test.go") {
+			pkg.TestFiles++
+			pkg.TestLOC += loc
+		} else {
+			pkg.CodeFiles++
+			pkg.CodeLOC += loc
+		}
+	}
+
+	result := &TestRatioResult{Packages: []PackageTestRatio{}, Untested: []string{}}
+	for _, pkg := range packages {
+		if pkg.CodeFiles == 0 {
+			continue // 只有测试文件的目录（如外部测试目录）不计入
+		}
+		pkg.Ratio = testRatio(pkg.TestLOC, pkg.CodeLOC)
+		result.Packages = append(result.Packages, *pkg)
+		result.CodeLOC += pkg.CodeLOC
+		result.TestLOC += pkg.TestLOC
+		if pkg.TestFiles == 0 {
+			result.Untested = append(result.Untested, pkg.Package)
+		}
+	}
+
+	// 比例最低的排在前面；比例相同时代码量大的优先
+	sort.Slice(result.Packages, func(i, j int) bool {
+		a, b := result.Packages[i], result.Packages[j]
+		if a.Ratio != b.Ratio {
+			return a.Ratio < b.Ratio
+		}
+		if a.CodeLOC != b.CodeLOC {
+			return a.CodeLOC > b.CodeLOC
+		}
+		return a.Package < b.Package
+	})
+	sort.Strings(result.Untested)
+
+	result.Ratio = testRatio(result.TestLOC, result.CodeLOC)
+	result.Summary = fmt.Sprintf("%d 个包，业务代码 %d 行，测试代码 %d 行，测试比例 %.2f，%d 个包没有测试",
+		len(result.Packages), result.CodeLOC, result.TestLOC, result.Ratio, len(result.Untested))
+	return result, nil
+}
+
+// countCodeLines 统计非空、非注释行数（块注释按行跳过）
+func countCodeLines(src []byte) int {
+	count := 0
+	inBlock := false
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if inBlock {
+			if idx := strings.Index(line, "*/"); idx >= 0 {
+				inBlock = false
+				line = strings.TrimSpace(line[idx+2:])
+			} else {
+				continue
+			}
+		}
+		if strings.HasPrefix(line, "/*") && !strings.Contains(line, "*/") {
+			inBlock = true
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// testRatio 计算测试代码比例，保留两位小数
+func testRatio(testLOC, codeLOC int) float64 {
+	if codeLOC == 0 {
+		return 0
+	}
+	return float64(int(float64(testLOC)/float64(codeLOC)*100+0.5)) / 100
+}
@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// 测试按包统计测试代码比例并找出没有测试的包
+func TestAnalyzeTestRatio(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"store/store.go": `package store
+
+// Save 保存
+func Save() error {
+	/*
+	   块注释不计入
+	*/
+	return nil
+}
+`,
+		"store/store_test.go": `package store
+
+import "testing"
+
+func TestSave(t *testing.T) {
+	if err := Save(); err != nil {
+		t.Fatal(err)
+	}
+}
+`,
+		"util/util.go": "package util\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n",
+		"big/big.go":   "package big\n\nfunc A() {}\n\nfunc B() {}\n\nfunc C() {}\n\nfunc D() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("创建目录失败: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("写入测试文件失败: %v", err)
+		}
+	}
+
+	result, err := AnalyzeTestRatio(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("统计失败: %v", err)
+	}
+	if len(result.Packages) != 3 {
+		t.Fatalf("应该统计 3 个包: %+v", result.Packages)
+	}
+
+	// 比例相同（0）时代码量大的在前，有测试的包排在最后
+	order := []string{"big", "util", "store"}
+	for i, name := range order {
+		if got := filepath.Base(result.Packages[i].Package); got != name {
+			t.Fatalf("第 %d 个包应为 %s，实际 %s", i, name, got)
+		}
+	}
+
+	store := result.Packages[2]
+	if store.CodeLOC != 4 || store.TestLOC != 7 || store.Ratio != 1.75 {
+		t.Errorf("store 统计不正确: %+v", store)
+	}
+	if len(result.Untested) != 2 || filepath.Base(result.Untested[0]) != "big" || filepath.Base(result.Untested[1]) != "util" {
+		t.Errorf("未测试的包不正确: %v", result.Untested)
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+type Stats struct{ Total, Max int }
+
+func count() int
+
+func lost() {
+	var s Stats
+	s.Total = count() // want B108
+	s.Max = 3
+}
+
+func used() Stats {
+	s := Stats{}
+	s.Total = count()
+	return s
+}
+
+func aliased(p *Stats) {
+	s := p
+	s.Total = count()
+}
@@ -0,0 +1,40 @@
+package main
+
+func load() (int, error)
+func save(int) error
+
+func overwrite() int {
+	x := 1 // want B106
+	x = 2
+	return x
+}
+
+func lostError() (int, error) {
+	v, err := load()
+	if err != nil {
+		return 0, err
+	}
+	err = save(v) // want B106
+	return v, nil
+}
+
+func fine(c bool) (int, error) {
+	n := 0
+	if c {
+		n = 1
+	}
+	total := 0
+	for i := 0; i < 3; i++ {
+		total += n
+		n = i
+	}
+	v, err := load()
+	if err != nil {
+		return 0, err
+	}
+	p := &v
+	v = 3
+	f := func() { n = 5 }
+	f()
+	return *p + total, nil
+}
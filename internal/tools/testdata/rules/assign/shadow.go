@@ -0,0 +1,73 @@
+package main
+
+import "log"
+
+func load() (int, error)
+
+func shadowed(c bool) error {
+	var err error
+	if c {
+		v, err := load() // want B107
+		if err != nil {
+			log.Printf("load: %v", err)
+		}
+		_ = v
+	}
+	return err
+}
+
+func handled(c bool) error {
+	var err error
+	if c {
+		v, err := load()
+		if err != nil {
+			return err
+		}
+		_ = v
+	}
+	if v, err := load(); err != nil {
+		return err
+	} else {
+		_ = v
+	}
+	return err
+}
+
+func ifInitReturned() (err error) {
+	if _, err := load(); err != nil { // want B107
+		log.Printf("load: %v", err)
+	}
+	return err
+}
+
+func ifInitNamed() (err error) {
+	if v, err := load(); err != nil { // want B107
+		_ = v
+	}
+	return
+}
+
+func ifInitChecked(c bool) error {
+	var err error
+	if c {
+		_, err = load()
+	}
+	if _, err := load(); err != nil {
+		log.Printf("load: %v", err)
+	}
+	if err != nil {
+		log.Printf("earlier: %v", err)
+	}
+	err = nil
+	return err
+}
+
+func reassigned(c bool) (int, error) {
+	_, err := load() // want B106
+	for i := 0; i < 3; i++ {
+		n, err := load()
+		log.Println(n, err)
+	}
+	n, err := load()
+	return n, err
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func ignored() {
+	_ = os.Remove("file.txt") // want B101
+}
+
+func checked() error {
+	if err := os.Remove("file.txt"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func leaked() {
+	file, _ := os.Open("file.txt") // want B101 B102
+	_ = file
+}
+
+func grade(score int) string {
+	switch score { // want B103
+	case 90:
+		return "A"
+	}
+	switch {
+	case score > 60:
+		return "pass"
+	default:
+		return "fail"
+	}
+}
+
+func load(name string) error {
+	err := os.Chdir(name)
+	if err != nil {
+		return fmt.Errorf("chdir %s: %v", name, err) // want B105
+	}
+	return fmt.Errorf("load %s: %w", name, err)
+}
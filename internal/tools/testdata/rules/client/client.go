@@ -0,0 +1,117 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	pb "example.com/proto"
+	"google.golang.org/grpc"
+)
+
+var shared = &http.Client{}
+
+func fetch(url string) ([]byte, error) {
+	resp, err := http.Get(url) // want B124
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func fetchClosed(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := shared.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+func handler(w http.ResponseWriter, r *http.Request) {
+	client := &http.Client{}                                         // want B125
+	req, _ := http.NewRequest(http.MethodGet, "http://backend", nil) // want B126
+	resp, err := client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	io.Copy(w, resp.Body)
+}
+
+func poll(ctx context.Context, urls []string) {
+	for _, url := range urls {
+		client := new(http.Client)                    // want B125
+		if resp, err := client.Get(url); err == nil { // want B126
+			resp.Body.Close()
+		}
+	}
+}
+
+func dial(ctx context.Context, addr string) error {
+	conn, err := grpc.Dial(addr, grpc.WithInsecure()) // want B127
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = pb.NewGreeterClient(conn).SayHello(ctx, &pb.HelloRequest{})
+	return err
+}
+
+func dialBlocking(addr string) (*grpc.ClientConn, error) {
+	return grpc.Dial(addr, grpc.WithInsecure(), grpc.WithBlock()) // want B127
+}
+
+func dialOK(ctx context.Context, addr string) (*grpc.ClientConn, error) {
+	conn, err := grpc.DialContext(ctx, addr, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+func ping(url string) error {
+	_, err := http.Get(url) // want B124
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func pingHead(url string) error {
+	if _, err := http.Head(url); err != nil { // want B124
+		return err
+	}
+	return nil
+}
+
+func notify(url string) {
+	http.Get(url) // want B124
+}
+
+func statusOf(url string) (int, error) {
+	var resp, err = http.Get(url) // want B124
+	if err != nil {
+		return 0, err
+	}
+	return resp.StatusCode, nil
+}
+
+func statusClosed(url string) (int, error) {
+	var resp, err = shared.Get(url)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+type lazy struct{ once interface{ Do(func()) } }
+
+func (l *lazy) init(f func()) {
+	l.once.Do(f)
+}
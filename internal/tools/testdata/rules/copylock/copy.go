@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+type Cache struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+// 嵌套包含锁的结构体同样不能复制
+type Service struct {
+	cache Cache
+	name  string
+}
+
+type Pool struct {
+	sync.WaitGroup
+	workers int
+}
+
+func (c Cache) Get(key string) string { // want B134
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.data[key]
+}
+
+func (c *Cache) Set(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data[key] = value
+}
+
+func (s Service) Name() string { // want B134
+	return s.name
+}
+
+func wait(p Pool) { // want B134
+	p.Wait()
+}
+
+func inspect(c *Cache, s *Service, caches []Cache) Cache {
+	snapshot := *c   // want B134
+	inner := s.cache // want B134
+	var mu = c.mu    // want B134
+	mu.Lock()
+	fmt.Println(snapshot.data, inner.data)
+	for _, item := range caches { // want B134
+		_ = item.data
+	}
+	for i := range caches {
+		_ = caches[i].data
+	}
+	return caches[0] // want B134
+}
+
+func build() *Cache {
+	c := Cache{data: map[string]string{}}
+	other := c // want B134
+	use(&other)
+	return &c
+}
+
+func use(c *Cache) {}
+
+// 指针、新建的值和只包含指针的结构体都不复制锁
+type Handle struct {
+	mu *sync.Mutex
+}
+
+func (h Handle) Lock() {
+	h.mu.Lock()
+}
+
+func fresh() Cache {
+	return Cache{data: map[string]string{}}
+}
+
+func ok(c *Cache, h Handle) {
+	copied := h
+	n := fresh()
+	_ = *c
+	use(c)
+	fmt.Println(copied, n.data)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"sync"
+)
+
+func process(*os.File) error
+
+func readAll(names []string) error {
+	for _, name := range names {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close() // want B130
+		if err := process(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func lockAll(mus []*sync.Mutex) {
+	for i := 0; i < len(mus); i++ {
+		mus[i].Lock()
+		defer mus[i].Unlock() // want B130
+	}
+}
+
+func nested(names []string) {
+	for _, name := range names {
+		switch name {
+		case "":
+			continue
+		default:
+			f, _ := os.Open(name)
+			defer f.Close() // want B130
+			break
+		}
+	}
+}
+
+func wrapped(names []string) error {
+	for _, name := range names {
+		if err := func() error {
+			f, err := os.Open(name)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			return process(f)
+		}(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func firstAvailable(names []string) (*os.File, error) {
+	var mu sync.Mutex
+	for _, name := range names {
+		mu.Lock()
+		defer mu.Unlock()
+		f, err := os.Open(name)
+		if err == nil {
+			return f, nil
+		}
+		return nil, err
+	}
+	for {
+		mu.Lock()
+		defer mu.Unlock()
+		break
+	}
+	return nil, nil
+}
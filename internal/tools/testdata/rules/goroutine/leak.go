@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+func work() int { return 1 }
+
+// 接收方超时返回后，发送方永远阻塞
+func timeout() (int, error) {
+	ch := make(chan int)
+	go func() { // want B128
+		ch <- work()
+	}()
+	select {
+	case v := <-ch:
+		return v, nil
+	case <-time.After(time.Second):
+		return 0, errors.New("timeout")
+	}
+}
+
+// 调用方取消后，发送方永远阻塞
+func cancelled(ctx context.Context) (int, error) {
+	ch := make(chan int, 0)
+	go func() { // want B128
+		ch <- work()
+	}()
+	select {
+	case v := <-ch:
+		return v, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// 没有任何接收方
+func noReceiver() {
+	done := make(chan struct{})
+	go func() { // want B128
+		work()
+		done <- struct{}{}
+	}()
+}
+
+// 没有任何发送方，也没有关闭
+func noSender() {
+	start := make(chan bool)
+	go func() { // want B128
+		<-start
+		work()
+	}()
+}
+
+// range 通道但没有关闭
+func neverClosed(items []int) {
+	jobs := make(chan int)
+	go func() { // want B128
+		for j := range jobs {
+			_ = j
+		}
+	}()
+	for _, item := range items {
+		jobs <- item
+	}
+}
+
+// 有缓冲的通道：接收方离开后发送仍能完成
+func buffered(ctx context.Context) (int, error) {
+	ch := make(chan int, 1)
+	go func() {
+		ch <- work()
+	}()
+	select {
+	case v := <-ch:
+		return v, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// goroutine 同时监听取消
+func selectSend(ctx context.Context) (int, error) {
+	ch := make(chan int)
+	go func() {
+		select {
+		case ch <- work():
+		case <-ctx.Done():
+		}
+	}()
+	select {
+	case v := <-ch:
+		return v, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// 一定会接收
+func waited() int {
+	ch := make(chan int)
+	go func() {
+		ch <- work()
+	}()
+	return <-ch
+}
+
+// 关闭通道结束 range 和接收
+func closed(items []int) {
+	jobs := make(chan int)
+	quit := make(chan struct{})
+	go func() {
+		for j := range jobs {
+			_ = j
+		}
+	}()
+	go func() {
+		<-quit
+	}()
+	for _, item := range items {
+		jobs <- item
+	}
+	close(jobs)
+	close(quit)
+}
+
+// 循环中的 select 会反复接收
+func polled(ctx context.Context) {
+	ch := make(chan int)
+	go func() {
+		ch <- work()
+	}()
+	for {
+		select {
+		case <-ch:
+			return
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+// 通道交给其他函数，无法判断
+func handedOff() chan int {
+	ch := make(chan int)
+	go func() {
+		ch <- work()
+	}()
+	return ch
+}
+
+// 参数在启动 goroutine 前求值
+func argument() {
+	ch := make(chan int)
+	go consume(<-ch)
+	ch <- 1
+}
+
+func consume(int) {}
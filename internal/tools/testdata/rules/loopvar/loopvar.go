@@ -0,0 +1,36 @@
+package main
+
+import "sync"
+
+func process(int)
+
+func processRef(*int)
+
+func racy(items []int) []func() {
+	var wg sync.WaitGroup
+	var fns []func()
+	for _, item := range items {
+		wg.Add(1)
+		go func() { // want B112
+			defer wg.Done()
+			process(item)
+		}()
+		fns = append(fns, func() { process(item) }) // want B112
+		go processRef(&item)                        // want B112
+	}
+	for i := 0; i < 3; i++ {
+		defer func() { process(i) }() // want B112
+	}
+	wg.Wait()
+	return fns
+}
+
+func fine(items []int) {
+	for _, item := range items {
+		item := item
+		go func() { process(item) }()
+		go func(v int) { process(v) }(item)
+		go processRef(&item)
+		func() { process(item) }()
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Item struct {
+	Value int
+	Next  *Item
+}
+
+func (i *Item) Get() int { return i.Value }
+
+// SafeGet 先判断了 nil，接收者为 nil 时调用是安全的
+func (i *Item) SafeGet() int {
+	if i == nil {
+		return 0
+	}
+	return i.Value
+}
+
+func nilDeref() int {
+	var p *Item
+	return p.Get() // want B104
+}
+
+func nilReceiverHandled() int {
+	var p *Item
+	return p.SafeGet()
+}
+
+func fieldOnSomePaths(ok bool) int {
+	var p *Item
+	if ok {
+		p = &Item{Value: 1}
+	}
+	return p.Value // want B104
+}
+
+func checkedBeforeUse(ok bool) int {
+	var p *Item
+	if ok {
+		p = &Item{Value: 1}
+	}
+	if p != nil {
+		return p.Value
+	}
+	return 0
+}
+
+func defaultedWhenNil(ok bool) int {
+	var p *Item
+	if ok {
+		p = &Item{Value: 1}
+	}
+	if p == nil {
+		p = &Item{}
+	}
+	return p.Value
+}
+
+// alwaysNil p 总是 nil，判断之后的解引用执行不到
+func alwaysNil() int {
+	var p *Item
+	if p == nil {
+		return 0
+	}
+	return p.Value
+}
+
+func lastOfList(items []*Item) int {
+	var last *Item
+	for _, it := range items {
+		last = it
+	}
+	return last.Value // want B104
+}
+
+func store(ok bool) {
+	var p *int
+	if ok {
+		p = new(int)
+	}
+	*p = 1 // want B104
+}
+
+func reportedOnce(ok bool) int {
+	var p *Item
+	if ok {
+		p = &Item{}
+	}
+	p.Value = 1 // want B104
+	return p.Value
+}
+
+func unknownSource(p *Item) int {
+	return p.Value
+}
+
+func nilInterface() string {
+	var err error
+	if errors.Is(err, nil) {
+		return "ok"
+	}
+	return err.Error() // want B104
+}
+
+func formatted(p *Item) string {
+	return fmt.Sprint(p.Next.Value)
+}
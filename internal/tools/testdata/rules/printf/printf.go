@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+func report(name string, count int, err error) error {
+	fmt.Printf("%s: %d\n", name)                // want B132
+	fmt.Fprintf(os.Stderr, "%s\n", name, count) // want B132
+	log.Printf("同步 %s 失败: %w", name, err)       // want B132
+	msg := fmt.Sprintf("%s 失败: %w", name, err)  // want B132
+	fmt.Printf("%*d%%\n", 3, count)             // 宽度占一个参数
+	fmt.Printf("%[1]s %[1]s\n", name)           // 显式参数序号不计数
+	fmt.Println(msg)
+	args := []any{name, count}
+	fmt.Printf("%s %d\n", args...)
+	if count > 1 {
+		return fmt.Errorf("%s: %w, %w", name, err, err) // go 1.20 起允许多个 %w
+	}
+	return fmt.Errorf("%s 失败: %w", name, err)
+}
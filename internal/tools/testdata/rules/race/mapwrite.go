@@ -0,0 +1,118 @@
+package main
+
+import (
+	"sync"
+)
+
+func fetch(string) int { return 0 }
+
+// 循环中启动的 goroutine 同时写同一个 map
+func fetchAll(urls []string) map[string]int {
+	results := make(map[string]int)
+	var wg sync.WaitGroup
+	for _, u := range urls {
+		wg.Add(1)
+		go func(u string) {
+			defer wg.Done()
+			results[u] = fetch(u) // want B133
+			results[u+"#"] = 0
+		}(u)
+	}
+	wg.Wait()
+	return results
+}
+
+// 加锁后写入
+func fetchLocked(urls []string) map[string]int {
+	results := map[string]int{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, u := range urls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mu.Lock()
+			defer mu.Unlock()
+			results[u] = fetch(u)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// 两个 goroutine 分别写同一个 map
+func twoWriters(counts map[string]int) {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		counts["a"]++ // want B133
+	}()
+	go func() {
+		defer wg.Done()
+		delete(counts, "b") // want B133
+	}()
+	wg.Wait()
+}
+
+// 启动 goroutine 后，本函数在等待之前也写了 map
+func parentWrites() {
+	var seen map[string]bool = map[string]bool{}
+	done := make(chan struct{})
+	go func(m map[string]bool) {
+		m["child"] = true // want B133
+		close(done)
+	}(seen)
+	seen["parent"] = true
+	<-done
+}
+
+// 等待 goroutine 结束后再写，没有竞争
+func parentWaits() {
+	seen := make(map[string]bool)
+	done := make(chan struct{})
+	go func() {
+		seen["child"] = true
+		close(done)
+	}()
+	<-done
+	seen["parent"] = true
+}
+
+// 每轮循环各有一个 map
+func perIteration(urls []string) {
+	for _, u := range urls {
+		local := make(map[string]int)
+		go func() {
+			local[u] = fetch(u)
+		}()
+	}
+}
+
+// 闭包内声明的 map、sync.Map 和切片的按下标写入
+func notShared(urls []string) {
+	var cache sync.Map
+	out := make([]int, len(urls))
+	for i, u := range urls {
+		go func() {
+			m := map[string]int{}
+			m[u] = 1
+			cache.Store(u, 1)
+			out[i] = fetch(u)
+		}()
+	}
+}
+
+type crawler struct {
+	mu      sync.Mutex
+	visited map[string]bool
+}
+
+// 结构体中的 map 字段
+func (c *crawler) crawl(urls []string) {
+	for _, u := range urls {
+		go func() {
+			c.visited[u] = true // want B133
+		}()
+	}
+}
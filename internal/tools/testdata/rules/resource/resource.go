@@ -0,0 +1,124 @@
+package files
+
+import (
+	"bufio"
+	"io"
+	"os"
+)
+
+func deferred(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(io.Discard, f)
+	return err
+}
+
+func deferredClosure(name string) (err error) {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	_, err = f.WriteString("hello")
+	return err
+}
+
+func leaked(name string) (int, error) {
+	f, err := os.Open(name) // want B102
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(io.Discard, f)
+	return int(n), err
+}
+
+func closedOnAllPaths(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(io.Discard, f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func missedEarlyReturn(name string) error {
+	f, err := os.Open(name) // want B102
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(io.Discard, f); err != nil {
+		return err
+	}
+	f.Close()
+	return nil
+}
+
+func returned(name string) (*os.File, error) {
+	f, err := os.OpenFile(name, os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func handedOver(name string) (*bufio.Reader, error) {
+	return bufio.NewReader(must(os.Open(name))), nil
+}
+
+type store struct{ file *os.File }
+
+func stored(name string) (*store, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &store{file: f}, nil
+}
+
+func discarded(name string) {
+	_, _ = os.Open(name) // want B102
+	os.Create(name)      // want B102
+}
+
+func perFile(names []string) error {
+	for _, name := range names {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(io.Discard, f); err != nil {
+			f.Close()
+			return err
+		}
+		f.Close()
+	}
+	for _, name := range names {
+		f, err := os.Open(name) // want B102
+		if err != nil {
+			continue
+		}
+		io.Copy(io.Discard, f)
+	}
+	return nil
+}
+
+func written(name string) error {
+	return os.WriteFile(name, []byte("ok"), 0644)
+}
+
+func must(f *os.File, err error) *os.File {
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+func poll(ctx context.Context, interval time.Duration, check func() bool) {
+	ticker := time.NewTicker(interval) // want B131
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if check() {
+				ticker.Stop()
+				return
+			}
+		}
+	}
+}
+
+func pollStopped(ctx context.Context, interval time.Duration, check func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			check()
+		}
+	}
+}
+
+func heartbeat(interval time.Duration) {
+	_ = time.NewTicker(interval) // want B131
+}
+
+func background(interval time.Duration, tick func()) {
+	t := time.NewTicker(interval)
+	go func() {
+		defer t.Stop()
+		for range t.C {
+			tick()
+		}
+	}()
+}
+
+func owned(interval time.Duration) *time.Ticker {
+	t := time.NewTicker(interval)
+	return t
+}
@@ -0,0 +1,19 @@
+package main
+
+func blocked() int {
+	var ch chan int
+	ch <- 1     // want B111
+	return <-ch // want B111
+}
+
+func fine(done chan struct{}) int {
+	var ch chan int
+	select {
+	case v := <-ch:
+		return v
+	case <-done:
+	}
+	ch = make(chan int, 1)
+	ch <- 1
+	return <-ch
+}
@@ -0,0 +1,22 @@
+package main
+
+func lost() {
+	var m map[string]int
+	m["a"] = 1 // want B109
+	m["b"]++   // want B109
+}
+
+func fine(keys []string) map[string]int {
+	var m map[string]int
+	m = make(map[string]int)
+	m["a"] = 1
+
+	var seen map[string]bool
+	for _, k := range keys {
+		if seen == nil {
+			seen = map[string]bool{}
+		}
+		seen[k] = true
+	}
+	return m
+}
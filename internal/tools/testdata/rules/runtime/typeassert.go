@@ -0,0 +1,31 @@
+package main
+
+import "fmt"
+
+type named interface{ Name() string }
+
+func describe(v any) string {
+	s := v.(string)                        // want B129
+	fmt.Println(v.(fmt.Stringer).String()) // want B129
+	var n = v.(named)                      // want B129
+	return s + n.Name()
+}
+
+func checked(v any) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("意外的类型 %T", v)
+	}
+	var n, isNamed = v.(named)
+	if isNamed {
+		s += n.Name()
+	}
+	if _, ok = (v.(fmt.Stringer)); ok {
+		return s, nil
+	}
+	switch x := v.(type) {
+	case int:
+		return fmt.Sprint(x), nil
+	}
+	return s, nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import "sync"
+
+func work()
+
+func racy() {
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		go func() {
+			wg.Add(1) // want B110
+			defer wg.Done()
+			work()
+		}()
+	}
+	wg.Wait()
+}
+
+func fine() {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			work()
+		}()
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+)
+
+func login(db *sql.DB, username, password string) {
+	apiKey := "sk-123456" // want G101
+	token := os.Getenv("TOKEN")
+	_, _ = apiKey, token
+
+	query := "SELECT * FROM users WHERE username='" + username + "'" // want G201
+	db.Query(query)
+	db.Query("SELECT * FROM users WHERE username = ?", username)
+
+	fmt.Println("Password:", password) // want G104
+	fmt.Println("User:", username)
+
+	_ = rand.Intn(100) // want G401
+
+	md5.New() // want G501
+	sha256.New()
+
+	os.WriteFile("a.txt", nil, 0777) // want G302
+	os.WriteFile("b.txt", nil, 0600)
+
+	http.Get("http://example.com") // want G107
+	http.Get("https://example.com")
+}
@@ -0,0 +1,109 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+)
+
+func leak(db *sql.DB) ([]string, error) {
+	rows, err := db.Query("SELECT name FROM users") // want B120 B121
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		rows.Scan(&name)
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func closed(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+func handedOff(db *sql.DB) (*sql.Rows, error) {
+	rows, err := db.Query("SELECT 1")
+	return rows, err
+}
+
+func lookup(db *sql.DB, id int) string {
+	var name string
+	if err := db.QueryRow("SELECT name FROM users WHERE id = ?", id).Scan(&name); err != nil { // want B122
+		log.Printf("查询失败: %v", err)
+	}
+	row := db.QueryRow("SELECT name FROM users WHERE id = ?", id)
+	row.Scan(&name) // want B122
+	return name
+}
+
+func lookupWrapped(db *sql.DB, id int) (string, error) {
+	var name string
+	err := db.QueryRow("SELECT name FROM users WHERE id = ?", id).Scan(&name)
+	if err != nil {
+		return "", fmt.Errorf("查询用户 %d: %w", id, err)
+	}
+	return name, nil
+}
+
+func lookupNoRows(db *sql.DB, id int) string {
+	var name string
+	err := db.QueryRow("SELECT name FROM users WHERE id = ?", id).Scan(&name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ""
+	}
+	return name
+}
+
+func transfer(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil) // want B123
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE a SET n = n - 1"); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func transferSafe(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	return tx.Commit()
+}
+
+func firstName(db *sql.DB) (string, error) {
+	rows, err := db.Query("SELECT name FROM users") // want B120
+	if err != nil {
+		return "", err
+	}
+	if rows.Next() {
+		var name string
+		err := rows.Scan(&name)
+		return name, err
+	}
+	rows.Close()
+	return "", rows.Err()
+}
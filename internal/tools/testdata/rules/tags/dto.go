@@ -0,0 +1,18 @@
+package dto
+
+type UserResponse struct {
+	ID        int64  `json:"id" yaml:"id"`
+	Name      string // want B116
+	Secret    string `json:"-,omitempty"`     // want B118
+	Email     string `json:"email,omitEmpty"` // want B118
+	Phone     string `json:"omitempty"`       // want B118
+	UserID    int64  `json:"id"`              // want B117
+	CreatedAt string `json:"createdAt"`       // want B119
+	Note      string `json: "note"`           // want B116 B118
+	Ignored   string `json:"-"`
+	internal  string
+}
+
+type request struct {
+	Query string
+}
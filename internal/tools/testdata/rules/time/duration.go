@@ -0,0 +1,25 @@
+package main
+
+import "time"
+
+type Config struct {
+	Timeout time.Duration
+	Retries int
+}
+
+const interval = 5 * time.Second
+
+func wait(timeout time.Duration, seconds int, cfg Config, s string) {
+	time.Sleep(time.Duration(timeout) * time.Second) // want B115
+	time.Sleep(cfg.Timeout * time.Millisecond)       // want B115
+	time.Sleep(time.Second * interval)               // want B115
+	elapsed := time.Since(time.Now())
+	_ = elapsed * time.Second // want B115
+	d, _ := time.ParseDuration(s)
+	_ = time.Duration(d) * time.Minute // want B115
+
+	time.Sleep(time.Duration(seconds) * time.Second)
+	time.Sleep(time.Duration(cfg.Retries) * time.Second)
+	time.Sleep(3 * time.Second)
+	_ = time.Duration(elapsed/time.Millisecond) * time.Millisecond
+}
@@ -0,0 +1,17 @@
+package main
+
+import "time"
+
+func expired(start time.Time, created int64) bool {
+	if time.Now().Unix()-start.Unix() > 3600 { // want B113
+		return true
+	}
+	return 60 < (time.Now().UnixMilli() - created) // want B113
+}
+
+func fine(start time.Time, deadline int64) bool {
+	if time.Since(start) > time.Hour {
+		return true
+	}
+	return time.Now().Unix() > deadline
+}
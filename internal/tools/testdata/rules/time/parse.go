@@ -0,0 +1,17 @@
+package main
+
+import "time"
+
+const layout = "2006-01-02 15:04:05"
+
+func parse(s string) {
+	time.Parse("2006-01-02 15:04:05", s) // want B114
+	time.Parse(time.RFC1123, s)          // want B114
+	time.Parse(time.Kitchen, s)          // want B114
+
+	time.Parse("2006-01-02", s)
+	time.Parse(time.RFC3339, s)
+	time.Parse("2006-01-02 15:04:05 -0700", s)
+	time.ParseInLocation("2006-01-02 15:04:05", s, time.Local)
+	time.Parse(layout, s)
+}
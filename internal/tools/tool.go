@@ -28,6 +28,8 @@ type Tool interface {
 	// ctx: 上下文（用于超时控制、取消等）
 	// input: 输入参数（类型由 InputType() 决定）
 	// 返回: 工具执行结果（字符串形式）和错误
+	// 同一个实例可能被并发调用：Run 必须可重入，单次执行的状态只放在局部变量中，
+	// 生成的文件通过 WriteOutputFile 写入 ctx 中的工作区
 	Run(ctx context.Context, input any) (string, error)
 }
 
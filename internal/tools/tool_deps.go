@@ -0,0 +1,125 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ToolDependency 工具依赖的前置工具
+type ToolDependency struct {
+	Tool  string              // 前置工具名
+	Input func(input any) any // 由本工具的输入生成前置工具的输入，为 nil 时原样传入
+}
+
+// DependentTool 可选接口：声明前置工具
+// ToolManager.Run 按依赖顺序先执行前置工具（前置工具自己的依赖也会先执行，同一次执行中每个工具只运行一次），
+// 结果经由 context 传给本工具，在 Execute 中用 DependencyResult 读取
+type DependentTool interface {
+	Dependencies() []ToolDependency
+}
+
+// dependencyStep 依赖解析后的一步：工具名和它的输入
+type dependencyStep struct {
+	Tool  string
+	Input any
+}
+
+// dependencyResultsKey 前置工具结果在 context 中的键
+type dependencyResultsKey struct{}
+
+// dependencyResults 本次执行中已完成的前置工具结果，同一条调用链共用
+type dependencyResults map[string]*ToolResult
+
+// DependencyResult 取出前置工具的结果；不是经由 ToolManager 执行或没有声明该依赖时返回 false
+func DependencyResult(ctx context.Context, tool string) (*ToolResult, bool) {
+	results, _ := ctx.Value(dependencyResultsKey{}).(dependencyResults)
+	result, ok := results[tool]
+	return result, ok
+}
+
+// DependencyOrder 返回工具的前置工具，按执行顺序排列（不含工具自身）
+// 依赖的工具未注册或存在循环依赖时返回错误
+func (tm *ToolManager) DependencyOrder(name string) ([]string, error) {
+	steps, err := tm.resolveDependencies(name, nil)
+	if err != nil {
+		return nil, err
+	}
+	order := make([]string, len(steps))
+	for i, step := range steps {
+		order[i] = step.Tool
+	}
+	return order, nil
+}
+
+// resolveDependencies 深度优先解析依赖，前置工具排在依赖它的工具之前
+func (tm *ToolManager) resolveDependencies(name string, input any) ([]dependencyStep, error) {
+	var steps []dependencyStep
+	done := make(map[string]bool)
+	var visit func(name string, input any, path []string) error
+	visit = func(name string, input any, path []string) error {
+		for _, p := range path {
+			if p == name {
+				return fmt.Errorf("%w: 工具存在循环依赖: %s", ErrInvalidInput, strings.Join(append(path, name), " -> "))
+			}
+		}
+		tm.mu.RLock()
+		tool, ok := tm.tools[name]
+		tm.mu.RUnlock()
+		if !ok {
+			if len(path) > 0 {
+				return fmt.Errorf("%w: %s 依赖的工具 %s 未注册", ErrToolNotFound, path[len(path)-1], name)
+			}
+			return ErrToolNotFound
+		}
+		if dependent, ok := tool.(DependentTool); ok {
+			for _, dep := range dependent.Dependencies() {
+				depInput := input
+				if dep.Input != nil && input != nil {
+					depInput = dep.Input(input)
+				}
+				if err := visit(dep.Tool, depInput, append(path, name)); err != nil {
+					return err
+				}
+			}
+		}
+		if len(path) > 0 && !done[name] {
+			done[name] = true
+			steps = append(steps, dependencyStep{Tool: name, Input: input})
+		}
+		return nil
+	}
+	if err := visit(name, input, nil); err != nil {
+		return nil, err
+	}
+	return steps, nil
+}
+
+// runDependencies 依次执行前置工具，返回带有其结果的 context
+// 调用链上已经执行过的工具（context 中已有结果）不再重复执行；任一前置工具失败时返回错误
+func (tm *ToolManager) runDependencies(ctx context.Context, name string, input any) (context.Context, error) {
+	steps, err := tm.resolveDependencies(name, input)
+	if err != nil || len(steps) == 0 {
+		return ctx, err
+	}
+
+	results, ok := ctx.Value(dependencyResultsKey{}).(dependencyResults)
+	if !ok {
+		results = make(dependencyResults)
+		ctx = context.WithValue(ctx, dependencyResultsKey{}, results)
+	}
+	for _, step := range steps {
+		if _, ok := results[step.Tool]; ok {
+			continue
+		}
+		result, err := tm.Run(ctx, step.Tool, step.Input)
+		if err != nil {
+			return ctx, fmt.Errorf("前置工具 %s 执行失败: %w", step.Tool, err)
+		}
+		if !result.Success {
+			return ctx, fmt.Errorf("%w: 前置工具 %s 执行失败: %s", ErrToolExecution, step.Tool, result.Error)
+		}
+		results[step.Tool] = result
+	}
+	return ctx, nil
+}
@@ -0,0 +1,182 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// depMockTool 声明前置工具的模拟工具
+type depMockTool struct {
+	*MockTool
+	deps []ToolDependency
+}
+
+func (t *depMockTool) Dependencies() []ToolDependency {
+	return t.deps
+}
+
+func newDepMockTool(name string, deps []string, run func(ctx context.Context, input any) (string, error)) *depMockTool {
+	t := &depMockTool{MockTool: NewMockTool(name, run)}
+	for _, d := range deps {
+		t.deps = append(t.deps, ToolDependency{Tool: d})
+	}
+	return t
+}
+
+func registerAll(t *testing.T, tm *ToolManager, tools ...Tool) {
+	t.Helper()
+	for _, tool := range tools {
+		if err := tm.Register(tool, DefaultToolConfig(tool.Name())); err != nil {
+			t.Fatalf("注册工具 %s 失败: %v", tool.Name(), err)
+		}
+	}
+}
+
+func TestToolManager_DependencyOrder(t *testing.T) {
+	tm := NewToolManager(NewNoopLogger())
+	// d 依赖 b、c，b 和 c 都依赖 a（菱形依赖）
+	registerAll(t, tm,
+		NewMockTool("a", nil),
+		newDepMockTool("b", []string{"a"}, nil),
+		newDepMockTool("c", []string{"a"}, nil),
+		newDepMockTool("d", []string{"b", "c"}, nil),
+	)
+
+	order, err := tm.DependencyOrder("d")
+	if err != nil {
+		t.Fatalf("解析依赖失败: %v", err)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("依赖顺序 = %v, 期望 %v", order, want)
+	}
+
+	order, err = tm.DependencyOrder("a")
+	if err != nil || len(order) != 0 {
+		t.Errorf("没有依赖的工具应返回空顺序, got %v, %v", order, err)
+	}
+}
+
+func TestToolManager_RunWithDependencies(t *testing.T) {
+	tm := NewToolManager(NewNoopLogger())
+	var aRuns int32
+	var seen []string
+	registerAll(t, tm,
+		NewMockTool("a", func(ctx context.Context, input any) (string, error) {
+			atomic.AddInt32(&aRuns, 1)
+			return "a:" + input.(string), nil
+		}),
+		newDepMockTool("b", []string{"a"}, func(ctx context.Context, input any) (string, error) {
+			a, _ := DependencyResult(ctx, "a")
+			return "b(" + a.Result + ")", nil
+		}),
+		newDepMockTool("c", []string{"a"}, func(ctx context.Context, input any) (string, error) {
+			return "c", nil
+		}),
+		newDepMockTool("d", []string{"b", "c"}, func(ctx context.Context, input any) (string, error) {
+			for _, name := range []string{"a", "b", "c"} {
+				if r, ok := DependencyResult(ctx, name); ok {
+					seen = append(seen, r.Result)
+				}
+			}
+			return "d", nil
+		}),
+	)
+
+	result, err := tm.Run(context.Background(), "d", "x")
+	if err != nil || !result.Success {
+		t.Fatalf("执行失败: %v, %+v", err, result)
+	}
+	if n := atomic.LoadInt32(&aRuns); n != 1 {
+		t.Errorf("菱形依赖中 a 应只执行一次, 实际 %d 次", n)
+	}
+	if want := []string{"a:x", "b(a:x)", "c"}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("d 看到的前置结果 = %v, 期望 %v", seen, want)
+	}
+}
+
+func TestToolManager_DependencyInput(t *testing.T) {
+	tm := NewToolManager(NewNoopLogger())
+	var got any
+	registerAll(t, tm,
+		NewMockTool("a", func(ctx context.Context, input any) (string, error) {
+			got = input
+			return "ok", nil
+		}),
+		&depMockTool{
+			MockTool: NewMockTool("b", nil),
+			deps: []ToolDependency{{Tool: "a", Input: func(input any) any {
+				return strings.ToUpper(input.(string))
+			}}},
+		},
+	)
+	if _, err := tm.Run(context.Background(), "b", "code"); err != nil {
+		t.Fatal(err)
+	}
+	if got != "CODE" {
+		t.Errorf("前置工具的输入 = %v, 期望 CODE", got)
+	}
+}
+
+func TestToolManager_DependencyErrors(t *testing.T) {
+	tm := NewToolManager(NewNoopLogger())
+	registerAll(t, tm,
+		newDepMockTool("loop1", []string{"loop2"}, nil),
+		newDepMockTool("loop2", []string{"loop1"}, nil),
+		newDepMockTool("orphan", []string{"missing"}, nil),
+		NewMockTool("broken", func(ctx context.Context, input any) (string, error) {
+			return "", errors.New("boom")
+		}),
+		newDepMockTool("needs_broken", []string{"broken"}, func(ctx context.Context, input any) (string, error) {
+			t.Error("前置工具失败时不应执行本工具")
+			return "", nil
+		}),
+	)
+
+	if _, err := tm.DependencyOrder("loop1"); !errors.Is(err, ErrInvalidInput) || !strings.Contains(err.Error(), "loop1 -> loop2 -> loop1") {
+		t.Errorf("循环依赖应返回 ErrInvalidInput 并给出路径, got %v", err)
+	}
+	if _, err := tm.DependencyOrder("orphan"); !errors.Is(err, ErrToolNotFound) {
+		t.Errorf("依赖未注册的工具应返回 ErrToolNotFound, got %v", err)
+	}
+
+	result, err := tm.Run(context.Background(), "needs_broken", "x")
+	if err != nil {
+		t.Fatalf("前置工具失败应返回失败结果而不是错误: %v", err)
+	}
+	if result.Success || !strings.Contains(result.Error, "broken") {
+		t.Errorf("前置工具失败时结果应失败并指明工具, got %+v", result)
+	}
+
+	result, _ = tm.Run(context.Background(), "loop1", "x")
+	if result.Success {
+		t.Error("循环依赖时执行应失败")
+	}
+}
+
+func TestRefactorAdvisor(t *testing.T) {
+	tm := NewToolManager(NewNoopLogger())
+	registerAll(t, tm, NewComplexityAnalyzer(), NewRefactorAdvisor())
+
+	var branches strings.Builder
+	for i := 0; i < 12; i++ {
+		branches.WriteString("\tif x == " + string(rune('0'+i%10)) + " {\n\t\tx++\n\t}\n")
+	}
+	code := "package p\n\nfunc simple() int {\n\treturn 1\n}\n\nfunc tangled(x int) int {\n" + branches.String() + "\treturn x\n}\n"
+
+	result, err := tm.Run(context.Background(), "refactor_advisor", code)
+	if err != nil || !result.Success {
+		t.Fatalf("执行失败: %v, %+v", err, result)
+	}
+	if !strings.Contains(result.Result, `"function": "tangled"`) || strings.Contains(result.Result, `"function": "simple"`) {
+		t.Errorf("应只对 tangled 给出建议:\n%s", result.Result)
+	}
+
+	// 不经由 ToolManager 时没有前置结果
+	if _, err := NewRefactorAdvisor().Run(context.Background(), code); !errors.Is(err, ErrToolExecution) {
+		t.Errorf("直接执行应返回 ErrToolExecution, got %v", err)
+	}
+}
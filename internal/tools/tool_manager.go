@@ -22,6 +22,9 @@ type ToolConfig struct {
 	// MaxRetries 最大重试次数
 	MaxRetries int
 
+	// Limits 内存、扫描文件数等资源上限（零值表示不限制）
+	Limits ResourceLimits
+
 	// CustomConfig 自定义配置（工具特定）
 	CustomConfig map[string]any
 }
@@ -39,18 +42,22 @@ func DefaultToolConfig(name string) ToolConfig {
 
 // ToolManager 工具管理器
 type ToolManager struct {
-	tools   map[string]Tool       // 工具注册表
-	configs map[string]ToolConfig // 工具配置
-	mu      sync.RWMutex          // 读写锁
-	logger  Logger                // 日志记录器
+	tools     map[string]Tool        // 工具注册表
+	factories map[string]func() Tool // 每次执行创建新实例的工具
+	configs   map[string]ToolConfig  // 工具配置
+	mu        sync.RWMutex           // 读写锁
+	logger    Logger                 // 日志记录器
+	auditLog  *AuditLog              // 审计日志，为 nil 表示不记录
+	crashDir  string                 // 崩溃报告目录，为空表示不写
 }
 
 // NewToolManager 创建工具管理器
 func NewToolManager(logger Logger) *ToolManager {
 	return &ToolManager{
-		tools:   make(map[string]Tool),
-		configs: make(map[string]ToolConfig),
-		logger:  logger,
+		tools:     make(map[string]Tool),
+		factories: make(map[string]func() Tool),
+		configs:   make(map[string]ToolConfig),
+		logger:    logger,
 	}
 }
 
@@ -71,6 +78,11 @@ func (tm *ToolManager) Register(tool Tool, config ToolConfig) error {
 	if _, exists := tm.tools[name]; exists {
 		return fmt.Errorf("工具 %s 已注册", name)
 	}
+	if c, ok := tool.(Configurable); ok {
+		if err := c.Configure(config); err != nil {
+			return fmt.Errorf("工具 %s 配置无效: %w", name, err)
+		}
+	}
 
 	tm.tools[name] = tool
 	tm.configs[name] = config
@@ -82,6 +94,23 @@ func (tm *ToolManager) Register(tool Tool, config ToolConfig) error {
 	return nil
 }
 
+// RegisterFactory 注册按次创建实例的工具
+// 持有单次执行状态的工具用工厂注册，每次 Run 都使用新的实例，并发调用互不影响
+func (tm *ToolManager) RegisterFactory(factory func() Tool, config ToolConfig) error {
+	if factory == nil {
+		return ErrInvalidInput
+	}
+	tool := factory()
+	if err := tm.Register(tool, config); err != nil {
+		return err
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.factories[tool.Name()] = factory
+	return nil
+}
+
 // Get 获取工具
 func (tm *ToolManager) Get(name string) (Tool, ToolConfig, error) {
 	tm.mu.RLock()
@@ -97,9 +126,60 @@ func (tm *ToolManager) Get(name string) (Tool, ToolConfig, error) {
 		return nil, ToolConfig{}, ErrToolDisabled
 	}
 
+	if factory, ok := tm.factories[name]; ok {
+		tool = factory()
+	}
 	return tool, config, nil
 }
 
+// GetConfig 获取工具配置（包括已禁用的工具）
+func (tm *ToolManager) GetConfig(name string) (ToolConfig, error) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	if _, exists := tm.tools[name]; !exists {
+		return ToolConfig{}, ErrToolNotFound
+	}
+	return tm.configs[name], nil
+}
+
+// SetAuditLog 设置审计日志，每次工具调用（包括被拒绝和验证失败的调用）都记录一条
+func (tm *ToolManager) SetAuditLog(auditLog *AuditLog) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.auditLog = auditLog
+}
+
+// audit 写入审计记录，写入失败只记录日志，不影响工具执行结果
+func (tm *ToolManager) audit(ctx context.Context, toolName string, input any, start time.Time, status string, err error) {
+	tm.mu.RLock()
+	auditLog := tm.auditLog
+	tm.mu.RUnlock()
+	if auditLog == nil {
+		return
+	}
+
+	summary, hash := summarizeAuditInput(input)
+	entry := AuditEntry{
+		Time:        start,
+		Command:     invocationFromContext(ctx),
+		Tool:        toolName,
+		Input:       summary,
+		InputSHA256: hash,
+		DurationMs:  time.Since(start).Milliseconds(),
+		Status:      status,
+	}
+	if ws := WorkspaceFromContext(ctx); ws != nil {
+		entry.RunID = ws.RunID
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if werr := auditLog.Record(entry); werr != nil && tm.logger != nil {
+		tm.logger.Error("写入审计日志失败", "tool", toolName, "error", werr)
+	}
+}
+
 // GetLogger 获取日志记录器
 func (tm *ToolManager) GetLogger() Logger {
 	return tm.logger
@@ -145,35 +225,69 @@ type ToolStatus struct {
 
 // Run 执行工具
 func (tm *ToolManager) Run(ctx context.Context, toolName string, input any) (*ToolResult, error) {
+	// 为本次执行准备工作区
+	ws := WorkspaceFromContext(ctx)
+	if ws == nil {
+		ws = NewWorkspace("")
+		ctx = WithWorkspace(ctx, ws)
+		defer ws.Cleanup()
+	}
+	auditStart := time.Now()
+
 	// 1. 获取工具
 	tool, config, err := tm.Get(toolName)
 	if err != nil {
 		if tm.logger != nil {
 			tm.logger.Error("获取工具失败", "tool", toolName, "error", err)
 		}
+		tm.audit(ctx, toolName, input, auditStart, AuditStatusRejected, err)
 		return nil, err
 	}
 
 	// 2. 验证输入
-	if err := tool.Validate(input); err != nil {
+	if err := safeValidate(tool, input); err != nil {
+		var perr *PanicError
+		if errors.As(err, &perr) {
+			tm.audit(ctx, toolName, input, auditStart, AuditStatusFailed, err)
+			return tm.panicResult(ctx, perr, input, 0), nil
+		}
 		if tm.logger != nil {
 			tm.logger.Error("输入验证失败", "tool", toolName, "error", err)
 		}
+		tm.audit(ctx, toolName, input, auditStart, AuditStatusInvalid, err)
 		return NewToolResult(false, "", fmt.Sprintf("输入验证失败: %v", err), 0), nil
 	}
 
-	// 3. 创建带超时的上下文
+	// 3. 先执行声明的前置工具，结果经由 context 传给本工具
+	if _, ok := tool.(DependentTool); ok {
+		if ctx, err = tm.runDependencies(ctx, toolName, input); err != nil {
+			if tm.logger != nil {
+				tm.logger.Error("前置工具执行失败", "tool", toolName, "error", err)
+			}
+			tm.audit(ctx, toolName, input, auditStart, AuditStatusFailed, err)
+			return NewToolResult(false, "", err.Error(), 0), nil
+		}
+	}
+
+	// 4. 创建带超时的上下文
 	runCtx := ctx
 	if config.Timeout > 0 {
 		var cancel context.CancelFunc
-		runCtx, cancel = context.WithTimeout(ctx, time.Duration(config.Timeout)*time.Millisecond)
+		runCtx, cancel = context.WithTimeout(runCtx, time.Duration(config.Timeout)*time.Millisecond)
 		defer cancel()
 	}
+	if config.Limits.MaxMemoryMB > 0 {
+		var cancel context.CancelCauseFunc
+		runCtx, cancel = context.WithCancelCause(runCtx)
+		defer cancel(nil)
+		defer watchMemory(runCtx, config.Limits.MaxMemoryMB, cancel)()
+	}
 
-	// 4. 执行工具（带重试）
+	// 5. 执行工具（带重试），panic 转换为失败结果，不重试
 	startTime := time.Now()
 	var result string
 	var execErr error
+	var perr *PanicError
 
 	for retry := 0; retry <= config.MaxRetries; retry++ {
 		if retry > 0 {
@@ -182,8 +296,30 @@ func (tm *ToolManager) Run(ctx context.Context, toolName string, input any) (*To
 			}
 		}
 
-		result, execErr = tool.Run(runCtx, input)
-		if execErr == nil {
+		// 每次尝试使用独立的扫描预算
+		attemptCtx := runCtx
+		if !config.Limits.IsZero() {
+			attemptCtx = WithResourceLimits(runCtx, config.Limits)
+		}
+
+		result, execErr = safeRun(attemptCtx, tool, input)
+		if execErr == nil || errors.As(execErr, &perr) {
+			break
+		}
+
+		// 超出资源限制时重试也无济于事
+		if cause := context.Cause(runCtx); errors.Is(cause, ErrResourceLimit) {
+			execErr = cause
+		}
+		if errors.Is(execErr, ErrResourceLimit) {
+			if tm.logger != nil {
+				tm.logger.Error("工具超出资源限制", "tool", toolName, "error", execErr)
+			}
+			break
+		}
+
+		// 调用方取消（如 Ctrl-C）时不再重试
+		if errors.Is(execErr, context.Canceled) {
 			break
 		}
 
@@ -197,24 +333,31 @@ func (tm *ToolManager) Run(ctx context.Context, toolName string, input any) (*To
 	}
 
 	executionTime := time.Since(startTime).Milliseconds()
+	if perr != nil {
+		tm.audit(ctx, toolName, input, auditStart, AuditStatusFailed, execErr)
+		return tm.panicResult(ctx, perr, input, executionTime), nil
+	}
 
-	// 5. 构建结果
+	// 6. 构建结果
 	toolResult := NewToolResult(
 		execErr == nil,
 		result,
 		"",
 		executionTime,
 	)
+	toolResult.Metadata["run_id"] = ws.RunID
 
 	if execErr != nil {
 		toolResult.Error = execErr.Error()
 		if tm.logger != nil {
 			tm.logger.Error("工具执行失败", "tool", toolName, "error", execErr, "time", executionTime)
 		}
+		tm.audit(ctx, toolName, input, auditStart, AuditStatusFailed, execErr)
 	} else {
 		if tm.logger != nil {
 			tm.logger.Info("工具执行成功", "tool", toolName, "time", executionTime)
 		}
+		tm.audit(ctx, toolName, input, auditStart, AuditStatusSuccess, nil)
 	}
 
 	return toolResult, nil
@@ -263,9 +406,15 @@ func (tm *ToolManager) UpdateConfig(name string, config ToolConfig) error {
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
 
-	if _, exists := tm.tools[name]; !exists {
+	tool, exists := tm.tools[name]
+	if !exists {
 		return ErrToolNotFound
 	}
+	if c, ok := tool.(Configurable); ok {
+		if err := c.Configure(config); err != nil {
+			return fmt.Errorf("工具 %s 配置无效: %w", name, err)
+		}
+	}
 
 	tm.configs[name] = config
 	if tm.logger != nil {
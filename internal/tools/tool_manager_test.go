@@ -276,3 +276,28 @@ func TestBaseTool_Validate(t *testing.T) {
 		t.Fatal("错误类型应该验证失败")
 	}
 }
+
+// 测试工厂注册的工具每次执行使用新实例
+func TestToolManager_RegisterFactory(t *testing.T) {
+	tm := NewToolManager(NewNoopLogger())
+	created := 0
+	err := tm.RegisterFactory(func() Tool {
+		created++
+		return NewMockTool("per_run", nil)
+	}, DefaultToolConfig("per_run"))
+	if err != nil {
+		t.Fatalf("注册失败: %v", err)
+	}
+
+	first, _, _ := tm.Get("per_run")
+	second, _, _ := tm.Get("per_run")
+	if first == second {
+		t.Error("每次获取应该返回新的实例")
+	}
+	if _, err := tm.Run(context.Background(), "per_run", "x"); err != nil {
+		t.Fatalf("执行失败: %v", err)
+	}
+	if created != 4 {
+		t.Errorf("工厂应该被调用 4 次（注册、两次获取、执行），实际 %d", created)
+	}
+}
@@ -0,0 +1,42 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// typedPackagesMode 需要语法树和完整类型信息的加载模式
+const typedPackagesMode = packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+	packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports
+
+// loadTypedPackages 以 dir 为工作目录加载包（带类型信息），patterns 为空时加载 ./...
+// 只有无法调用 go list 时才返回错误；单个包的类型错误保留在 Package.Errors 中
+func loadTypedPackages(ctx context.Context, dir string, patterns []string) ([]*packages.Package, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	cfg := &packages.Config{
+		Context: ctx,
+		Mode:    typedPackagesMode,
+		Dir:     dir,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("加载包失败: %w", err)
+	}
+	return pkgs, nil
+}
+
+// packageErrors 汇总包的加载/类型错误
+func packageErrors(pkgs []*packages.Package) []string {
+	var errs []string
+	for _, pkg := range pkgs {
+		for _, e := range pkg.Errors {
+			errs = append(errs, e.Error())
+		}
+	}
+	return errs
+}
@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// TypedRunner 强类型工具的实现：输入类型为 I，结果类型为 O
+// 由 TypedTool 适配为 Tool 接口，实现中不再需要对 any 做类型断言
+type TypedRunner[I, O any] interface {
+	// ValidateInput 验证已解码的输入
+	ValidateInput(input I) error
+
+	// Execute 执行工具，返回结构化结果
+	Execute(ctx context.Context, input I) (O, error)
+}
+
+// InputConverter 可选接口：把历史上支持的其他输入形式转换为 I
+// 例如 bug_detector 仍接受代码字符串作为输入
+type InputConverter[I any] interface {
+	ConvertInput(input any) (I, bool)
+}
+
+// TypedTool 把 TypedRunner 适配为 Tool 接口
+// 输入可以是 I、*I，也可以是来自命令行或 HTTP 的 JSON（[]byte、json.RawMessage、map[string]any），
+// 统一解码为 I；结果 O 为 string 时原样返回，否则序列化为缩进的 JSON
+type TypedTool[I, O any] struct {
+	*BaseTool
+	impl TypedRunner[I, O]
+}
+
+// NewTypedTool 创建强类型工具适配器，输入类型由 I 决定
+func NewTypedTool[I, O any](name, description string, impl TypedRunner[I, O]) *TypedTool[I, O] {
+	return &TypedTool[I, O]{
+		BaseTool: NewBaseTool(name, description, reflect.TypeFor[I]()),
+		impl:     impl,
+	}
+}
+
+// Decode 把任意形式的输入转换为 I
+func (tt *TypedTool[I, O]) Decode(input any) (I, error) {
+	var zero I
+	switch v := input.(type) {
+	case I:
+		return v, nil
+	case *I:
+		if v != nil {
+			return *v, nil
+		}
+	case json.RawMessage:
+		return decodeTypedJSON[I](v)
+	case []byte:
+		return decodeTypedJSON[I](v)
+	case map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return zero, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		return decodeTypedJSON[I](data)
+	}
+	if converter, ok := tt.impl.(InputConverter[I]); ok {
+		if v, ok := converter.ConvertInput(input); ok {
+			return v, nil
+		}
+	}
+	return zero, fmt.Errorf("%w: 期望 %v, 实际 %T", ErrInvalidInput, tt.InputType(), input)
+}
+
+// Validate 解码输入后交给实现验证
+func (tt *TypedTool[I, O]) Validate(input any) error {
+	req, err := tt.Decode(input)
+	if err != nil {
+		return err
+	}
+	return tt.impl.ValidateInput(req)
+}
+
+// Run 解码输入、执行工具并序列化结果
+func (tt *TypedTool[I, O]) Run(ctx context.Context, input any) (string, error) {
+	req, err := tt.Decode(input)
+	if err != nil {
+		return "", err
+	}
+	result, err := tt.impl.Execute(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return marshalToolResult(result)
+}
+
+// decodeTypedJSON 按 I 的类型解码 JSON，string 输入接受 {"input": "..."}
+func decodeTypedJSON[I any](data []byte) (I, error) {
+	var zero I
+	value, err := DecodeInput(reflect.TypeFor[I](), string(data))
+	if err != nil {
+		return zero, err
+	}
+	return value.(I), nil
+}
+
+// marshalToolResult 把工具结果转换为文本：字符串原样返回，其他类型序列化为缩进的 JSON
+func marshalToolResult(result any) (string, error) {
+	if text, ok := result.(string); ok {
+		return text, nil
+	}
+	jsonBytes, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("序列化结果失败: %w", err)
+	}
+	return string(jsonBytes), nil
+}
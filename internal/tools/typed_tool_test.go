@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type typedSampleRequest struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type typedSampleResult struct {
+	Greeting string `json:"greeting"`
+}
+
+type typedSampleTool struct {
+	*TypedTool[typedSampleRequest, *typedSampleResult]
+}
+
+func newTypedSampleTool() *typedSampleTool {
+	t := &typedSampleTool{}
+	t.TypedTool = NewTypedTool[typedSampleRequest, *typedSampleResult]("typed_sample", "强类型示例工具", t)
+	return t
+}
+
+func (t *typedSampleTool) ValidateInput(req typedSampleRequest) error {
+	if req.Name == "" {
+		return fmt.Errorf("必须指定 name")
+	}
+	return nil
+}
+
+func (t *typedSampleTool) Execute(ctx context.Context, req typedSampleRequest) (*typedSampleResult, error) {
+	return &typedSampleResult{Greeting: fmt.Sprintf("hello %s x%d", req.Name, req.Count)}, nil
+}
+
+// 测试不同形式的输入都能解码为强类型请求
+func TestTypedTool_Decode(t *testing.T) {
+	tool := newTypedSampleTool()
+	if tool.InputType() != reflect.TypeOf(typedSampleRequest{}) {
+		t.Fatalf("输入类型错误: %v", tool.InputType())
+	}
+
+	want := typedSampleRequest{Name: "go", Count: 2}
+	inputs := []any{
+		want,
+		&want,
+		[]byte(`{"name": "go", "count": 2}`),
+		json.RawMessage(`{"name": "go", "count": 2}`),
+		map[string]any{"name": "go", "count": 2},
+	}
+	for _, input := range inputs {
+		got, err := tool.Decode(input)
+		if err != nil || got != want {
+			t.Errorf("解码 %T 错误: %+v %v", input, got, err)
+		}
+	}
+
+	for _, input := range []any{"go", 42, nil, (*typedSampleRequest)(nil), []byte(`{"count": "x"}`)} {
+		if _, err := tool.Decode(input); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("输入 %#v 应该返回 ErrInvalidInput，实际 %v", input, err)
+		}
+	}
+}
+
+// 测试验证和执行走强类型实现，结果序列化为 JSON
+func TestTypedTool_Run(t *testing.T) {
+	tool := newTypedSampleTool()
+	if err := tool.Validate(typedSampleRequest{}); err == nil {
+		t.Error("缺少 name 应该验证失败")
+	}
+
+	out, err := tool.Run(context.Background(), []byte(`{"name": "go", "count": 3}`))
+	if err != nil {
+		t.Fatalf("执行失败: %v", err)
+	}
+	var result typedSampleResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil || result.Greeting != "hello go x3" {
+		t.Errorf("结果错误: %s %v", out, err)
+	}
+
+	// 通过 ToolManager 以 JSON 参数调用
+	tm := NewToolManager(NewNoopLogger())
+	tm.Register(tool, DefaultToolConfig(tool.Name()))
+	out, err = tm.CallJSON(context.Background(), "typed_sample", `{"name": "json"}`)
+	if err != nil || !json.Valid([]byte(out)) {
+		t.Errorf("CallJSON 结果错误: %s %v", out, err)
+	}
+}
+
+// 测试 string 输入的工具和兼容旧输入形式的工具
+func TestTypedTool_BuiltinInputs(t *testing.T) {
+	analyzer := NewComplexityAnalyzer()
+	if err := analyzer.Validate(""); err == nil {
+		t.Error("空代码应该验证失败")
+	}
+	if _, err := analyzer.Run(context.Background(), json.RawMessage(`{"input": "package x\nfunc f() {}"}`)); err != nil {
+		t.Errorf("JSON 包装的代码应该可以执行: %v", err)
+	}
+
+	detector := NewBugDetector()
+	for _, input := range []any{"package x", BugDetectorInput{Code: "package x"}} {
+		if err := detector.Validate(input); err != nil {
+			t.Errorf("输入 %T 应该验证通过: %v", input, err)
+		}
+	}
+	if err := detector.Validate(42); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("错误的输入类型应该返回 ErrInvalidInput，实际 %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// workspaceKey 工作区在 context 中的键
+type workspaceKey struct{}
+
+// runCounter 生成执行 ID 的序号
+var runCounter atomic.Uint64
+
+// Workspace 单次工具执行的工作区
+// 由 ToolManager.Run 通过 context 传给工具；工具写文件、创建临时文件都经由工作区，
+// 同一个工具实例被并发调用时各次执行互不干扰
+type Workspace struct {
+	RunID     string     // 本次执行的唯一标识
+	OutDir    string     // 生成文件的输出目录，为空表示写回原路径
+	ChangeSet *ChangeSet // 不为 nil 时为 dry-run 模式，写入只记录到变更集
+
+	mu      sync.Mutex
+	tempDir string
+}
+
+// NewWorkspace 创建工作区，outDir 为空表示生成的文件写回原路径
+func NewWorkspace(outDir string) *Workspace {
+	return &Workspace{
+		RunID:  fmt.Sprintf("%s-%d", time.Now().Format("20060102-150405"), runCounter.Add(1)),
+		OutDir: outDir,
+	}
+}
+
+// WithWorkspace 把工作区放入 context
+func WithWorkspace(ctx context.Context, ws *Workspace) context.Context {
+	return context.WithValue(ctx, workspaceKey{}, ws)
+}
+
+// WorkspaceFromContext 取出 context 中的工作区，没有时返回 nil
+func WorkspaceFromContext(ctx context.Context) *Workspace {
+	ws, _ := ctx.Value(workspaceKey{}).(*Workspace)
+	return ws
+}
+
+// OutputPath 返回生成文件的实际写入路径
+// 指定了 OutDir 时按原路径的相对结构放到 OutDir 下
+func (ws *Workspace) OutputPath(path string) string {
+	if ws == nil || ws.OutDir == "" {
+		return path
+	}
+	rel := filepath.Clean(path)
+	if filepath.IsAbs(rel) {
+		rel = strings.TrimPrefix(rel, filepath.VolumeName(rel))
+	}
+	rel = strings.TrimLeft(rel, string(filepath.Separator))
+	for strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		rel = strings.TrimPrefix(rel, ".."+string(filepath.Separator))
+	}
+	return filepath.Join(ws.OutDir, rel)
+}
+
+// TempDir 返回本次执行专用的临时目录，首次调用时创建
+func (ws *Workspace) TempDir() (string, error) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if ws.tempDir == "" {
+		dir, err := os.MkdirTemp("", "insight-run-"+ws.RunID+"-")
+		if err != nil {
+			return "", fmt.Errorf("创建临时目录失败: %w", err)
+		}
+		ws.tempDir = dir
+	}
+	return ws.tempDir, nil
+}
+
+// Cleanup 删除本次执行的临时目录
+func (ws *Workspace) Cleanup() error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if ws.tempDir == "" {
+		return nil
+	}
+	err := os.RemoveAll(ws.tempDir)
+	ws.tempDir = ""
+	return err
+}
+
+// pathLocks 按路径串行化文件写入，避免并发执行写同一个文件时内容交错
+var pathLocks sync.Map
+
+// WriteOutputFile 经由 context 中的工作区写入生成的文件，返回实际写入的路径
+// 先写临时文件再重命名，读者不会看到写了一半的内容；dry-run 模式下只记录到变更集
+func WriteOutputFile(ctx context.Context, path string, data []byte, perm os.FileMode) (string, error) {
+	ws := WorkspaceFromContext(ctx)
+	target := ws.OutputPath(path)
+	if ws != nil && ws.ChangeSet != nil {
+		return target, ws.ChangeSet.Record(target, data)
+	}
+
+	abs, err := filepath.Abs(target)
+	if err != nil {
+		abs = target
+	}
+	lock, _ := pathLocks.LoadOrStore(abs, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	dir := filepath.Dir(target)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("创建输出目录失败: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(target)+".tmp-")
+	if err != nil {
+		return "", fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("写入文件失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("写入文件失败: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return "", fmt.Errorf("设置文件权限失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return "", fmt.Errorf("写入文件失败: %w", err)
+	}
+	return target, nil
+}
@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// 测试指定输出目录时生成文件的路径映射
+func TestWorkspace_OutputPath(t *testing.T) {
+	var none *Workspace
+	if got := none.OutputPath("a/b.go"); got != "a/b.go" {
+		t.Errorf("没有工作区时应该写回原路径，实际 %s", got)
+	}
+
+	ws := NewWorkspace("out")
+	tests := map[string]string{
+		"a/b_test.go":       filepath.Join("out", "a", "b_test.go"),
+		"./a/b_test.go":     filepath.Join("out", "a", "b_test.go"),
+		"../x/y.go":         filepath.Join("out", "x", "y.go"),
+		"/abs/path/file.md": filepath.Join("out", "abs", "path", "file.md"),
+	}
+	for path, want := range tests {
+		if got := ws.OutputPath(path); got != want {
+			t.Errorf("OutputPath(%q) = %q，期望 %q", path, got, want)
+		}
+	}
+}
+
+// 测试经由工作区写文件和临时目录清理
+func TestWriteOutputFile(t *testing.T) {
+	outDir := t.TempDir()
+	ws := NewWorkspace(outDir)
+	ctx := WithWorkspace(context.Background(), ws)
+
+	path, err := WriteOutputFile(ctx, "pkg/gen_test.go", []byte("package pkg\n"), 0644)
+	if err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	if path != filepath.Join(outDir, "pkg", "gen_test.go") {
+		t.Errorf("写入路径错误: %s", path)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "package pkg\n" {
+		t.Errorf("文件内容错误: %q %v", data, err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("不应残留临时文件: %v", entries)
+	}
+
+	tmp, err := ws.TempDir()
+	if err != nil {
+		t.Fatalf("创建临时目录失败: %v", err)
+	}
+	if again, _ := ws.TempDir(); again != tmp {
+		t.Error("同一次执行应该复用临时目录")
+	}
+	ws.Cleanup()
+	if _, err := os.Stat(tmp); !os.IsNotExist(err) {
+		t.Error("Cleanup 后临时目录应该被删除")
+	}
+}
+
+// 测试并发执行时每次执行都有独立的工作区
+func TestToolManager_RunWorkspace(t *testing.T) {
+	tm := NewToolManager(NewNoopLogger())
+	tm.Register(NewMockTool("ws", func(ctx context.Context, input any) (string, error) {
+		ws := WorkspaceFromContext(ctx)
+		if ws == nil {
+			t.Error("context 中缺少工作区")
+			return "", nil
+		}
+		return ws.RunID, nil
+	}), DefaultToolConfig("ws"))
+
+	const runs = 20
+	ids := make([]string, runs)
+	var wg sync.WaitGroup
+	for i := 0; i < runs; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result, err := tm.Run(context.Background(), "ws", "x")
+			if err != nil || !result.Success {
+				t.Errorf("执行失败: %v", err)
+				return
+			}
+			if result.Metadata["run_id"] != result.Result {
+				t.Errorf("结果中的 run_id 不一致: %v", result.Metadata["run_id"])
+			}
+			ids[i] = result.Result
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if seen[id] {
+			t.Errorf("执行 ID 重复: %s", id)
+		}
+		seen[id] = true
+	}
+
+	// 调用方传入的工作区原样传给工具
+	ws := NewWorkspace("")
+	result, _ := tm.Run(WithWorkspace(context.Background(), ws), "ws", "x")
+	if result.Result != ws.RunID {
+		t.Errorf("应该使用调用方的工作区，实际 %s", result.Result)
+	}
+}